@@ -0,0 +1,199 @@
+"""jolo add — in-project generators, defined per template.
+
+A template (or addon) ships generators under ``generators/<name>/``:
+files rendered into the project plus a ``generator.toml`` with a
+description, variables and patches, in the same format as a template
+manifest. ``jolo add handler users`` run inside a project made by
+``jolo new`` reads its jolo.lock, renders the template's ``handler``
+generator with ``NAME=users`` on top of the project's recorded answers,
+and applies the patches (e.g. a route in main.go) to the files on disk.
+Generators are excluded when the template itself is rendered.
+``--force`` overwrites existing files but keeps their protected regions
+(see ``_jolo.regions``). In a Go project laid out under ``cmd/`` the
+main package's files, written or patched, are the first command's.
+"""
+
+from __future__ import annotations
+
+import contextlib
+import sys
+import tomllib
+from pathlib import Path
+
+from _jolo import golang, log, placeholders, portable, regions, remote
+from _jolo.scaffold import (
+    ADDONS_ROOT,
+    GENERATOR_FILE,
+    TEMPLATES_ROOT,
+    Template,
+    apply_patch,
+    condition_holds,
+    ejected_template,
+    find_addon,
+    find_template,
+    load_template,
+    parse_var_args,
+    project_variables,
+    read_lock,
+    render_template,
+    resolve_variables,
+    write_files,
+)
+
+
+def generators(templates: list[Template]) -> dict[str, Template]:
+    """Generator name -> generator; earlier TEMPLATES win a name."""
+    found = {}
+    for template in templates:
+        root = template.path / "generators"
+        for manifest in sorted(root.glob(f"*/{GENERATOR_FILE}")):
+            name = manifest.parent.name
+            if name in found:
+                continue
+            with open(manifest, "rb") as f:
+                found[name] = Template(
+                    name=name, path=manifest.parent, manifest=tomllib.load(f)
+                )
+    return found
+
+
+def _locked_templates(lock: dict, stack: contextlib.ExitStack) -> list:
+    """The lock's template as it is now (the project's copy, once
+    ejected), then its addons."""
+    name = lock["template"]
+    if lock.get("ejected"):
+        template, addons_root = ejected_template(Path.cwd(), lock)
+        addons = [find_addon(a, template, addons_root) for a in lock["addons"]]
+        return [template, *addons]
+    if remote.is_remote(name):
+        path = stack.enter_context(remote.fetched(name))
+        template = load_template(path, name)
+    elif lock.get("templates_dir"):
+        template = find_template(name, Path(lock["templates_dir"]))
+    else:
+        template = find_template(name, TEMPLATES_ROOT)
+    addons = [find_addon(a, template, ADDONS_ROOT) for a in lock["addons"]]
+    return [template, *addons]
+
+
+def _with_rendered_defaults(
+    generator: Template, variables: dict[str, str]
+) -> Template:
+    """GENERATOR with placeholders in its string defaults rendered, so a
+    default such as ``/{{NAME | kebab}}`` follows the name given."""
+    specs = {}
+    for name, spec in generator.variables.items():
+        default = spec.get("default")
+        if isinstance(default, str):
+            spec = {**spec, "default": placeholders.render(default, variables)}
+        specs[name] = spec
+    manifest = {**generator.manifest, "variables": specs}
+    return Template(generator.name, generator.path, manifest)
+
+
+def render_generator(
+    generator: Template, project: Path, variables: dict[str, str]
+) -> tuple[list[tuple[str, str | bytes]], dict[str, str]]:
+    """(new files, patched project files) for GENERATOR in PROJECT."""
+    label = f"Generator {generator.name}"
+    files = [
+        (golang.cmd_path(rel, variables), content)
+        for rel, content in render_template(generator, variables)
+    ]
+    original, patched = {}, {}
+    for patch in generator.manifest.get("patches", []):
+        try:
+            if "when" in patch and not condition_holds(
+                patch["when"], variables
+            ):
+                continue
+        except ValueError as e:
+            sys.exit(f"Error: {label}: {e}")
+        rel = placeholders.render(patch["file"], variables)
+        rel = golang.cmd_path(rel, variables)
+        if rel not in patched:
+            path = project / rel
+            if not path.is_file():
+                sys.exit(f"Error: {label}: no file {rel} to patch")
+            original[rel] = patched[rel] = portable.read_text(path)
+        text = patch.get("insert") or patch.get("append", "")
+        insert = placeholders.render(text, variables)
+        if insert.strip() and insert.strip() in patched[rel]:
+            continue  # already added, e.g. by an earlier run
+        try:
+            patched[rel] = apply_patch(patched[rel], patch, variables)
+        except ValueError as e:
+            sys.exit(f"Error: {label}: {rel}: {e}")
+    return files, {r: c for r, c in patched.items() if c != original[r]}
+
+
+def run_add_mode(args) -> None:
+    """Run a generator of ./jolo.lock's template, or list them."""
+    project = Path.cwd()
+    lock = read_lock(project)
+    with contextlib.ExitStack() as stack:
+        found = generators(_locked_templates(lock, stack))
+        if not args.generator:
+            if not found:
+                sys.exit(f"{lock['template']} has no generators")
+            width = max(len(n) for n in found)
+            for name, generator in found.items():
+                print(f"{name:<{width}}  {generator.description}".rstrip())
+            return
+        if args.generator not in found:
+            have = ", ".join(found) or "none"
+            sys.exit(
+                f"Error: Unknown generator for {lock['template']}: "
+                f"{args.generator} (have: {have})"
+            )
+        if not args.name:
+            sys.exit(f"Error: {args.generator} needs a NAME")
+
+        variables = project_variables(lock["name"])
+        variables.update(lock["variables"])
+        variables["NAME"] = args.name
+        generator = _with_rendered_defaults(found[args.generator], variables)
+        given = parse_var_args(args.var)
+        variables.update(
+            resolve_variables(generator, given, args.no_input)
+        )
+        files, patched = render_generator(generator, project, variables)
+
+    existing = [rel for rel, _ in files if (project / rel).exists()]
+    if existing and not args.force:
+        sys.exit(
+            f"Error: Already exists: {', '.join(existing)} (see --force)"
+        )
+    kept = {}
+    for i, (rel, content) in enumerate(files):
+        if rel not in existing or not isinstance(content, str):
+            continue
+        try:
+            current = portable.read_text(project / rel)
+            files[i] = (rel, regions.preserve(content, current))
+            kept[rel] = regions.dropped(content, current)
+        except (UnicodeDecodeError, ValueError) as e:
+            sys.exit(f"Error: {rel}: {e}")
+    if args.dry_run:
+        for rel, _ in files:
+            print(f"{'overwrite' if rel in existing else 'create'}  {rel}")
+        for rel in patched:
+            print(f"patch  {rel}")
+        return
+
+    write_files(files, project)
+    for rel, content in patched.items():
+        portable.write_text(project / rel, content)
+    for rel, lost in kept.items():
+        for key in lost:
+            log.warning(
+                f"{rel}: region {key} is no longer in the "
+                "generator's output; its lines were dropped"
+            )
+    log.summary(f"Added {args.generator} {args.name}")
+    for label, paths in (
+        ("Created", [rel for rel, _ in files]),
+        ("Patched", list(patched)),
+    ):
+        if paths:
+            log.summary(f"{label}: {', '.join(paths)}")
@@ -0,0 +1,75 @@
+"""Template aliases and favorites, from the ``[new]`` config table.
+
+    [new]
+    favorites = ["web", "python/web"]
+
+    [new.aliases]
+    web = "go/web --with tailwind,docker --license mit"
+
+An alias is a template followed by ``jolo new`` flags: ``jolo new web
+shop`` is ``jolo new go/web shop --with tailwind,docker --license
+mit``. A flag given on the command line wins over the alias's, and the
+repeatable ones (``--with``, ``--var``, ``--features``) add to it.
+Aliases stand for templates, not for other aliases. ``jolo show web``
+shows what the alias renders, with the flags ``show`` has.
+
+Favorites, templates or aliases, come first in the template picker and
+in ``jolo templates``, ahead of the ``[new] templates`` list.
+"""
+
+from __future__ import annotations
+
+import shlex
+import sys
+
+from _jolo.cli import build_parser
+
+
+def aliases(new_cfg: dict) -> dict[str, str]:
+    """The config's aliases: name -> ``TEMPLATE [FLAGS...]``."""
+    found = new_cfg.get("aliases", {})
+    if not isinstance(found, dict) or not all(
+        isinstance(v, str) for v in found.values()
+    ):
+        sys.exit('Error: [new.aliases] maps names to "TEMPLATE FLAGS..."')
+    return found
+
+
+def preferred(new_cfg: dict) -> list[str]:
+    """Favorites, then the ``templates`` list: what pickers show first."""
+    names = [*new_cfg.get("favorites", []), *new_cfg.get("templates", [])]
+    return list(dict.fromkeys(names))
+
+
+def _words(name: str, line: str) -> list[str]:
+    try:
+        words = shlex.split(line)
+    except ValueError as e:
+        sys.exit(f"Error: alias {name}: {e}")
+    if not words or words[0].startswith("-"):
+        sys.exit(f"Error: alias {name}: {line!r} starts with no template")
+    return words
+
+
+def expand(args, new_cfg: dict) -> None:
+    """If ARGS.template is an alias, put its template and flags in ARGS,
+    under the ones ARGS already has."""
+    name = args.template
+    line = aliases(new_cfg).get(name or "")
+    if line is None:
+        return
+    parser = build_parser()
+    base = vars(parser.parse_args(["new"]))
+    ns, extra = parser.parse_known_args(["new", *_words(name, line)])
+    if extra:
+        sys.exit(f"Error: alias {name}: unknown {' '.join(extra)}")
+    for key, value in vars(ns).items():
+        if value == base[key] or not hasattr(args, key):
+            continue
+        current = getattr(args, key)
+        if key == "template":
+            args.template = value
+        elif isinstance(value, list):
+            setattr(args, key, [*value, *current])
+        elif current == base[key]:
+            setattr(args, key, value)
@@ -0,0 +1,199 @@
+"""Programmatic scaffolding: what ``jolo new`` does, without the CLI.
+
+For editors, platforms and scripts that want projects from jolo
+templates without shelling out::
+
+    from _jolo import api
+
+    with api.open_template("go/web") as template:
+        api.describe(template)          # name, description, variables
+        api.generate(template, Path("~/src/shop").expanduser(),
+                     answers={"AUTHOR": "Ada"}, addons=["docker"])
+
+Nothing here prompts, reads the user's jolo config, or exits: answers
+not given take the manifest default, and every problem (unknown
+template, bad answer, failing hook) raises ScaffoldError.
+"""
+
+from __future__ import annotations
+
+import contextlib
+import shutil
+import uuid
+from collections.abc import Iterator
+from pathlib import Path
+
+from _jolo import remote, scaffold, validation
+from _jolo.scaffold import Template
+
+Answers = dict[str, "str | bool | int | list[str]"]
+
+
+class ScaffoldError(Exception):
+    """A template could not be found, rendered or written."""
+
+
+@contextlib.contextmanager
+def _errors() -> Iterator[None]:
+    """Turn the CLI's ``sys.exit("Error: ...")`` into ScaffoldError."""
+    try:
+        yield
+    except SystemExit as e:
+        message = str(e.code)
+        raise ScaffoldError(message.removeprefix("Error: ")) from None
+    except RuntimeError as e:
+        raise ScaffoldError(str(e)) from None
+
+
+def list_templates(templates_dirs: list[str] = ()) -> list[Template]:
+    """Every template in TEMPLATES_DIRS, ``$JOLO_TEMPLATES`` and the
+    builtins; earlier directories shadow later ones."""
+    with _errors():
+        roots = scaffold.template_roots(list(templates_dirs), {})
+        return scaffold.list_layered(roots)
+
+
+@contextlib.contextmanager
+def open_template(
+    source: str | Path, templates_dirs: list[str] = ()
+) -> Iterator[Template]:
+    """Yield the template SOURCE names: a Path to a template directory,
+    a git source (fetched for the duration of the block), or a template
+    name (``go/web``, ``go/web@^1``) looked up in TEMPLATES_DIRS and the
+    builtins."""
+    with _errors(), contextlib.ExitStack() as stack:
+        if isinstance(source, Path):
+            path = source.expanduser().resolve()
+            template = scaffold.load_template(path, path.name, path.parent)
+        elif remote.is_remote(source):
+            source = remote.pin(source)
+            path = stack.enter_context(remote.fetched(source))
+            template = scaffold.load_template(path, source)
+        else:
+            roots = scaffold.template_roots(list(templates_dirs), {})
+            template, path = scaffold.find_pinned(source, roots)
+        template.commit = remote.head_commit(path)
+        yield template
+
+
+def describe(template: Template) -> dict:
+    """Name, version, description, tags, variable specs and the addons
+    it takes, as plain data."""
+    with _errors():
+        variables = template.variables
+    return {
+        "name": template.name,
+        "version": template.version,
+        "description": template.description,
+        "tags": list(template.manifest.get("tags", [])),
+        "variables": {
+            name: {"type": spec.get("type", "string"), **spec}
+            for name, spec in variables.items()
+        },
+        "addons": scaffold.addon_names(template),
+    }
+
+
+def _prepare(
+    template: Template, name: str, answers: Answers | None, addons: list[str]
+) -> tuple[list[Template], dict[str, str], dict[str, str]]:
+    """(addons, answers, render variables) for NAME."""
+    given = dict(answers or {})
+    addons = [*addons, *scaffold.feature_addons(template, given, True)]
+    addon_templates = [
+        scaffold.find_addon(a, template)
+        for a in scaffold.parse_with_args(addons)
+    ]
+    resolved = {
+        "UUID": str(given.pop("UUID", uuid.uuid4())),
+        **scaffold.config_variables({}, name),
+    }
+    for key in (*scaffold.CONFIG_VARIABLES, "DESCRIPTION"):
+        if key in given:
+            resolved[key] = str(given.pop(key))
+    scaffold.check_module_path(
+        [template, *addon_templates], resolved["MODULE_PATH"]
+    )
+    project = scaffold.project_variables(name)
+    validation.check_builtins(
+        [template, *addon_templates], {**project, **resolved}
+    )
+    for t in (template, *addon_templates):
+        resolved.update(
+            scaffold.resolve_variables(
+                t, given, no_input=True, context={**project, **resolved}
+            )
+        )
+    resolved = {k: str(v) for k, v in resolved.items()}
+    variables = {**project, **resolved}
+    return addon_templates, resolved, variables
+
+
+def render(
+    template: Template,
+    name: str,
+    answers: Answers | None = None,
+    addons: list[str] = (),
+) -> dict[str, str | bytes]:
+    """Render TEMPLATE for project NAME in memory: path -> content (bytes
+    for binary files)."""
+    with _errors():
+        addon_templates, _, variables = _prepare(
+            template, name, answers, list(addons)
+        )
+        return dict(
+            scaffold.render_project(template, addon_templates, variables)
+        )
+
+
+def generate(
+    template: Template,
+    dest: Path,
+    answers: Answers | None = None,
+    addons: list[str] = (),
+    hooks: bool = False,
+    overwrite: bool = False,
+) -> dict[str, str | bytes]:
+    """Write TEMPLATE into DEST (project name: DEST's name) with a
+    jolo.lock, so ``jolo upgrade`` works on it later.
+
+    A non-empty DEST is refused unless OVERWRITE. HOOKS runs the
+    template's pre/post commands; on failure a new DEST is removed.
+    Returns what was written.
+    """
+    dest = Path(dest).expanduser().resolve()
+    existed = dest.exists()
+    if existed and any(dest.iterdir()) and not overwrite:
+        raise ScaffoldError(f"{dest} is not empty")
+    with _errors():
+        addon_templates, resolved, variables = _prepare(
+            template, dest.name, answers, list(addons)
+        )
+        templates = [template, *addon_templates]
+        files = scaffold.render_project(template, addon_templates, variables)
+        pre = scaffold.template_hooks(templates, "pre", variables)
+        post = scaffold.template_hooks(templates, "post", variables)
+        dest.mkdir(parents=True, exist_ok=True)
+        try:
+            if hooks:
+                scaffold.run_hooks(pre, dest)
+            scaffold.write_files(
+                files, dest, scaffold.render_modes(templates, variables)
+            )
+            scaffold.write_lock(
+                dest,
+                scaffold.lock_data(
+                    template,
+                    addon_templates,
+                    dest.name,
+                    resolved,
+                    template.root,
+                ),
+            )
+            if hooks:
+                scaffold.run_hooks(post, dest)
+        except RuntimeError:
+            if not existed:
+                shutil.rmtree(dest)
+            raise
+    return dict(files)
@@ -0,0 +1,61 @@
+"""jolo cache — the git template sources jolo new has fetched.
+
+    jolo cache [list] [MATCH]   url, ref, commit, when fetched, size
+    jolo cache clean [MATCH]    delete entries
+    jolo cache refresh [MATCH]  fetch every entry again
+
+MATCH keeps only entries whose URL contains it. See ``_jolo.remote`` for
+when the cache is used.
+"""
+
+from __future__ import annotations
+
+import shutil
+import sys
+
+from _jolo import log, remote
+from _jolo.commands import _fmt_size
+
+
+def _size(entry: remote.CacheEntry) -> int:
+    return sum(p.stat().st_size for p in entry.path.rglob("*") if p.is_file())
+
+
+def run_cache_mode(args) -> None:
+    entries = [
+        e
+        for e in remote.cache_entries()
+        if not args.match or args.match in e.url
+    ]
+    if not entries:
+        log.summary(f"No cached templates in {remote.cache_root()}")
+        return
+
+    if args.action == "clean":
+        total = sum(_size(e) for e in entries)
+        for entry in entries:
+            shutil.rmtree(entry.path)
+        log.summary(f"Removed {len(entries)} entries ({_fmt_size(total)})")
+        return
+
+    if args.action == "refresh":
+        if remote.offline():
+            sys.exit("Error: cannot refresh while offline")
+        for entry in entries:
+            log.info(f"Fetching {entry.url}@{entry.ref or 'HEAD'}")
+            remote.refresh(entry.url, entry.ref or None)
+        return
+
+    rows = [
+        (
+            e.url,
+            e.ref or "HEAD",
+            e.commit[:12],
+            e.fetched.replace("T", " "),
+            _fmt_size(_size(e)),
+        )
+        for e in entries
+    ]
+    widths = [max(len(row[i]) for row in rows) for i in range(5)]
+    for row in rows:
+        print("  ".join(f"{c:<{w}}" for c, w in zip(row, widths)).rstrip())
@@ -0,0 +1,201 @@
+"""jolo capture — turn an existing project into a template.
+
+``jolo capture ~/src/billing team/service`` copies the project (git's
+tracked and unignored files, or everything but .git outside a repo)
+into the templates directory as ``team/service``, replacing the
+project's name with placeholders in contents and paths: its module path
+(from go.mod, or ``--module-path``) becomes ``{{MODULE_PATH}}``, and
+``billing-api``, ``billing_api``, ``BillingApi``, ``billingApi``,
+``BILLING_API`` and ``Billing Api`` become ``{{PROJECT_NAME}}`` with the
+filter that gives them back. Each replacement is confirmed first unless
+``--no-input``. A starter manifest with a description and, when the
+build tool is recognised, ``[verify]`` commands is written alongside.
+"""
+
+from __future__ import annotations
+
+import re
+import subprocess
+import sys
+from pathlib import Path
+
+from _jolo import log, placeholders
+from _jolo.commands import load_config
+from _jolo.scaffold import (
+    LOCK_FILE,
+    MANIFEST_FILE,
+    _prompt_line,
+    is_binary,
+    project_variables,
+    write_toml,
+)
+
+# Placeholder for each spelling of the project name, longest match first.
+SPELLINGS = [
+    "{{PROJECT_NAME}}",
+    "{{PROJECT_NAME_UNDERSCORE}}",
+    "{{PROJECT_NAME | pascal}}",
+    "{{PROJECT_NAME | camel}}",
+    "{{PROJECT_NAME | snake | upper}}",
+    "{{PROJECT_NAME | title}}",
+]
+
+# Build files -> the ``[verify] run`` commands of the matching builtins.
+VERIFY = [
+    ("go.mod", ["go vet ./...", "go test ./..."]),
+    ("Cargo.toml", ["cargo check", "cargo test"]),
+    ("pyproject.toml", ["uv run pytest"]),
+    ("package.json", ["bun install", "bun test"]),
+]
+
+
+def project_files(src: Path) -> list[str]:
+    """Files to capture, relative to SRC."""
+    result = subprocess.run(
+        [
+            "git",
+            "ls-files",
+            "-z",
+            "--cached",
+            "--others",
+            "--exclude-standard",
+        ],
+        cwd=src,
+        capture_output=True,
+    )
+    if result.returncode == 0:
+        names = result.stdout.decode().split("\0")
+    else:
+        names = [
+            p.relative_to(src).as_posix()
+            for p in src.rglob("*")
+            if ".git" not in p.relative_to(src).parts
+        ]
+    return sorted(
+        n for n in names if n and n != LOCK_FILE and (src / n).is_file()
+    )
+
+
+def module_path(src: Path) -> str:
+    """The module path in SRC's go.mod, if any."""
+    gomod = src / "go.mod"
+    if gomod.is_file():
+        match = re.search(r"^module\s+(\S+)", gomod.read_text(), re.M)
+        if match:
+            return match[1]
+    return ""
+
+
+def replacements(name: str, module: str = "") -> list[tuple[str, str]]:
+    """(literal, placeholder) pairs for NAME's spellings (and MODULE),
+    longest literal first. Spellings that coincide are listed once."""
+    variables = {**project_variables(name), "MODULE_PATH": module}
+    pairs = {}
+    if module and module != name:
+        pairs[module] = "{{MODULE_PATH}}"
+    for placeholder in SPELLINGS:
+        literal = placeholders.render(placeholder, variables)
+        if literal and literal != placeholder:
+            pairs.setdefault(literal, placeholder)
+    return sorted(pairs.items(), key=lambda p: -len(p[0]))
+
+
+def _pattern(literal: str) -> re.Pattern:
+    """LITERAL as a whole word: ``app`` not in ``apple``, but ``MyApp``
+    in ``MyAppServer``."""
+    return re.compile(rf"(?<![A-Za-z0-9]){re.escape(literal)}(?![a-z0-9])")
+
+
+def substitute(text: str, pairs: list[tuple[str, str]]) -> str:
+    """Replace every PAIRS literal in TEXT in a single pass, so one
+    placeholder's text is never replaced again by a shorter literal."""
+    if not pairs:
+        return text
+    combined = re.compile(
+        "|".join(f"(?:{_pattern(lit).pattern})" for lit, _ in pairs)
+    )
+    lookup = dict(pairs)
+    return combined.sub(lambda m: lookup[m[0]], text)
+
+
+def count(texts: list[str], literal: str) -> int:
+    pattern = _pattern(literal)
+    return sum(len(pattern.findall(t)) for t in texts)
+
+
+def _confirm(label: str) -> bool:
+    answer = _prompt_line(f"{label} [Y/n]")
+    if answer is None:
+        sys.exit("Aborted.")
+    return answer.lower() in ("", "y", "yes")
+
+
+def manifest(src: Path, description: str) -> dict:
+    data = {"description": description}
+    for build_file, commands in VERIFY:
+        if (src / build_file).is_file():
+            data["verify"] = {"run": commands}
+            break
+    return data
+
+
+def _templates_dir(args) -> Path:
+    if args.templates_dir:
+        return Path(args.templates_dir).expanduser()
+    dirs = load_config().get("new", {}).get("templates_dirs", [])
+    if not dirs:
+        sys.exit(
+            "Error: No templates directory; pass --templates-dir or set "
+            "[new] templates_dirs in the config"
+        )
+    return Path(dirs[0]).expanduser()
+
+
+def run_capture_mode(args) -> None:
+    """Copy args.source into the templates dir as template args.name."""
+    src = Path(args.source).expanduser().resolve()
+    if not src.is_dir():
+        sys.exit(f"Error: Not a directory: {src}")
+    root = _templates_dir(args)
+    dest = root / args.name
+    if dest.exists():
+        sys.exit(f"Error: Template already exists: {dest}")
+
+    files = {}
+    for rel in project_files(src):
+        data = (src / rel).read_bytes()
+        files[rel] = data if is_binary(data) else data.decode()
+    texts = [c for c in files.values() if isinstance(c, str)] + list(files)
+
+    name = args.project_name or src.name
+    module = args.module_path or module_path(src)
+    pairs = []
+    for literal, placeholder in replacements(name, module):
+        n = count(texts, literal)
+        if not n:
+            continue
+        label = f"Replace {n} x {literal!r} with {placeholder}?"
+        if args.no_input or _confirm(label):
+            pairs.append((literal, placeholder))
+
+    description = args.description
+    if description is None and not args.no_input:
+        description = _prompt_line("Description") or ""
+    description = description or f"Captured from {src.name}"
+
+    dest.mkdir(parents=True)
+    for rel, content in files.items():
+        path = dest / substitute(rel, pairs)
+        path.parent.mkdir(parents=True, exist_ok=True)
+        if isinstance(content, bytes):
+            path.write_bytes(content)
+        else:
+            path.write_text(substitute(content, pairs))
+        path.chmod((src / rel).stat().st_mode & 0o777)
+    write_toml(
+        dest / MANIFEST_FILE,
+        manifest(src, description),
+        f"Captured from {src} by jolo capture; add [variables] as needed.",
+    )
+    log.summary(f"Captured {len(files)} files into {dest}")
+    log.summary(f"Try it: jolo new {args.name} demo --templates-dir {root}")
@@ -0,0 +1,153 @@
+"""jolo templates / jolo search — what ``jolo new`` can render.
+
+Lists every template across the template directories (``--templates-dir``,
+``$JOLO_TEMPLATES``, config ``templates_dirs``, builtins) with its
+language, tags and description, plus the git sources named in the
+config's ``[new] templates`` and its aliases. Manifests may set
+``language`` (default: the first segment of the name) and ``tags =
+[...]``. The config's favorites are listed first.
+
+With a query, entries are fuzzy-matched: every word must appear in the
+name, language, a tag, or the description, or its letters must occur in
+order in the name (``gw`` finds ``go/web``). Best matches come first.
+"""
+
+from __future__ import annotations
+
+import sys
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from _jolo import aliases, remote
+from _jolo.commands import load_config
+from _jolo.scaffold import TEMPLATES_ROOT, list_templates, template_roots
+
+
+@dataclass
+class Entry:
+    name: str
+    language: str
+    source: str
+    description: str = ""
+    tags: list[str] = field(default_factory=list)
+
+
+def _source_label(root: Path) -> str:
+    if root == TEMPLATES_ROOT:
+        return "builtin"
+    home = str(Path.home())
+    path = str(root)
+    return "~" + path[len(home) :] if path.startswith(home + "/") else path
+
+
+def catalog(roots: list[Path], preferred: list[str]) -> list[Entry]:
+    """One entry per template name (earlier roots shadow later ones),
+    then the config's git sources."""
+    seen = {}
+    for root in roots:
+        for t in list_templates(root):
+            if t.name in seen:
+                continue
+            seen[t.name] = Entry(
+                name=t.name,
+                language=t.manifest.get("language", t.name.split("/")[0]),
+                source=_source_label(root),
+                description=t.description,
+                tags=[str(tag) for tag in t.manifest.get("tags", [])],
+            )
+    entries = sorted(seen.values(), key=lambda e: e.name)
+    for spec in preferred:
+        if remote.is_remote(spec):
+            entries.append(Entry(name=spec, language="", source="git"))
+    return entries
+
+
+def alias_entries(entries: list[Entry], alias: dict[str, str]) -> list[Entry]:
+    """ENTRIES followed by one for each alias, described by what it
+    stands for and in its template's language."""
+    languages = {e.name: e.language for e in entries}
+    extra = []
+    for name, line in alias.items():
+        template = line.split()[0] if line.split() else ""
+        extra.append(
+            Entry(
+                name=name,
+                language=languages.get(template, ""),
+                source="alias",
+                description=f"= {line}",
+            )
+        )
+    return entries + extra
+
+
+def favorites_first(entries: list[Entry], favorites: list[str]) -> list[Entry]:
+    """ENTRIES with the FAVORITES up front, in their order."""
+    rank = {name: i for i, name in enumerate(favorites)}
+    return sorted(entries, key=lambda e: rank.get(e.name, len(rank)))
+
+
+def _subsequence(needle: str, haystack: str) -> bool:
+    it = iter(haystack)
+    return all(c in it for c in needle)
+
+
+def _word_score(word: str, entry: Entry) -> int:
+    """How well one query word matches ENTRY; 0 is no match."""
+    name = entry.name.lower()
+    segments = name.split("/")
+    tags = [t.lower() for t in entry.tags]
+    if word == name or word in segments:
+        return 100
+    if word in tags or word == entry.language.lower():
+        return 80
+    if any(s.startswith(word) for s in segments):
+        return 60
+    if word in name or any(word in t for t in tags):
+        return 40
+    if word in entry.description.lower():
+        return 20
+    if _subsequence(word, name):
+        return 10
+    return 0
+
+
+def search(entries: list[Entry], query: str) -> list[Entry]:
+    """Entries matching every word of QUERY, best first."""
+    words = query.lower().split()
+    scored = []
+    for i, entry in enumerate(entries):
+        scores = [_word_score(w, entry) for w in words]
+        if all(scores):
+            scored.append((-sum(scores), i, entry))
+    return [entry for _, _, entry in sorted(scored)]
+
+
+def format_entries(entries: list[Entry]) -> list[str]:
+    """Aligned columns: name, language, tags, source, description."""
+    rows = [
+        (e.name, e.language, ",".join(e.tags), e.source, e.description)
+        for e in entries
+    ]
+    widths = [max(len(row[i]) for row in rows) for i in range(5)]
+    return [
+        "  ".join(f"{c:<{w}}" for c, w in zip(row, widths) if w).rstrip()
+        for row in rows
+    ]
+
+
+def run_templates_mode(args) -> None:
+    """List templates, or search them when a query is given."""
+    new_cfg = load_config(profile=args.profile).get("new", {})
+    roots = template_roots(args.templates_dir, new_cfg)
+    entries = catalog(roots, new_cfg.get("templates", []))
+    entries = alias_entries(entries, aliases.aliases(new_cfg))
+    entries = favorites_first(entries, new_cfg.get("favorites", []))
+    if args.tag:
+        entries = [e for e in entries if args.tag in e.tags]
+    query = " ".join(args.query)
+    if query:
+        entries = search(entries, query)
+    if not entries:
+        sys.exit(f"No templates match {query or args.tag!r}")
+    for line in format_entries(entries):
+        print(line)
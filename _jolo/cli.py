@@ -19,7 +19,7 @@ except ImportError:
 
 import base64
 
-from _jolo import constants
+from _jolo import constants, log
 
 
 def clipboard_copy(text: str) -> None:
@@ -103,9 +103,8 @@ def read_port_from_devcontainer(workspace_dir: Path) -> int | None:
 
 
 def verbose_print(msg: str) -> None:
-    """Print message if verbose mode is enabled."""
-    if constants.VERBOSE:
-        print(f"[verbose] {msg}", file=sys.stderr)
+    """Log message at debug level, shown with -v."""
+    log.debug(msg)
 
 
 def select_flavors_interactive() -> list[str]:
@@ -263,6 +262,15 @@ def parse_flavor_arg(value: str) -> list[str]:
     return flavors
 
 
+def parse_bool_arg(value: str) -> bool:
+    """Parse a yes/no option value such as ``--git=false``."""
+    if value.lower() in ("y", "yes", "true", "1", "on"):
+        return True
+    if value.lower() in ("n", "no", "false", "0", "off"):
+        return False
+    raise argparse.ArgumentTypeError(f"expected true or false, got {value!r}")
+
+
 def parse_mount(arg: str, project_name: str) -> dict:
     """Parse mount argument into structured data.
 
@@ -334,13 +342,12 @@ def parse_copy(arg: str, project_name: str) -> dict:
 
 
 def verbose_cmd(cmd: list[str]) -> None:
-    """Print command if verbose mode is enabled."""
-    if constants.VERBOSE:
-        print(f"[verbose] $ {' '.join(cmd)}", file=sys.stderr)
+    """Log command at debug level, shown with -v."""
+    log.debug(f"$ {' '.join(cmd)}")
 
 
-def parse_args(argv: list[str]) -> argparse.Namespace:
-    """Parse command-line arguments."""
+def build_parser() -> argparse.ArgumentParser:
+    """The jolo argument parser, shared by parse_args and completion."""
     # --- Reusable parent parsers (no help to avoid duplicate -h) ---
     # Each groups related flags so subcommands pick only what they need.
 
@@ -351,6 +358,31 @@ def parse_args(argv: list[str]) -> argparse.Namespace:
         action="store_true",
         help="Print commands being executed",
     )
+    p_verbose.add_argument(
+        "--quiet",
+        "-q",
+        action="store_true",
+        help="Print warnings and errors only",
+    )
+    p_verbose.add_argument(
+        "--plain",
+        action="store_true",
+        help="No colour, even on a terminal (as does $NO_COLOR)",
+    )
+
+    p_offline = argparse.ArgumentParser(add_help=False)
+    p_offline.add_argument(
+        "--offline",
+        action="store_true",
+        help="Use cached git template sources only (see jolo cache)",
+    )
+
+    p_verify = argparse.ArgumentParser(add_help=False)
+    p_verify.add_argument(
+        "--verify-signature",
+        action="store_true",
+        help="Require a git template's tag or commit to be signed",
+    )
 
     p_prompt = argparse.ArgumentParser(add_help=False)
     p_prompt.add_argument(
@@ -464,11 +496,18 @@ def parse_args(argv: list[str]) -> argparse.Namespace:
         flavor=None,
         yes=False,
         verbose=False,
+        quiet=False,
+        plain=False,
         purge=False,
         target=None,
         deep=False,
     )
 
+    parser.add_argument(
+        "--stdio",
+        action="store_true",
+        help="Serve the editor protocol (JSON-RPC) on stdin/stdout",
+    )
     subparsers = parser.add_subparsers(dest="command", prog="jolo")
 
     # up: prompt, agent, detach, exec, mounts, recreate, verbose
@@ -500,6 +539,585 @@ def parse_args(argv: list[str]) -> argparse.Namespace:
         help="Project flavor(s): typescript-web, typescript, go-web, go, python-web, python, rust-web, rust, shell, prose, other",
     )
 
+    # new: files only, prompts for anything missing
+    sub_new = subparsers.add_parser(
+        "new",
+        parents=[p_verbose, p_offline, p_verify],
+        help="Scaffold project files from a template (no container)",
+    )
+    sub_new.add_argument(
+        "template",
+        nargs="?",
+        default=None,
+        help=(
+            "Template name (go/web) or git source "
+            "(github.com/me/tpl//sub@ref); prompted if omitted"
+        ),
+    )
+    sub_new.add_argument(
+        "name",
+        nargs="?",
+        default=None,
+        help="Project name (prompted if omitted)",
+    )
+    sub_new.add_argument(
+        "--no-input",
+        action="store_true",
+        help="Never prompt; use defaults, fail when there is none",
+    )
+    sub_new.add_argument(
+        "--var",
+        action="append",
+        default=[],
+        metavar="KEY=VALUE",
+        help="Set a template variable (repeatable)",
+    )
+    sub_new.add_argument(
+        "--with",
+        dest="with_",
+        action="append",
+        default=[],
+        metavar="ADDONS",
+        help="Comma-separated addons to overlay, e.g. docker,sqlite",
+    )
+    sub_new.add_argument(
+        "--features",
+        action="append",
+        default=[],
+        metavar="FEATURES",
+        help="Answer the template's features prompt, e.g. docker,auth",
+    )
+    sub_new.add_argument(
+        "--no-hooks",
+        action="store_true",
+        help="Skip the template's pre/post-generation commands",
+    )
+    sub_new.add_argument(
+        "--check",
+        action="store_true",
+        help="Check the template's tools first; stop if any is missing",
+    )
+    sub_new.add_argument(
+        "--no-install",
+        action="store_true",
+        help="Skip the template's dependency install commands, and so "
+        "its checks",
+    )
+    sub_new.add_argument(
+        "--no-verify",
+        action="store_true",
+        help="Skip the template's checks that the project builds",
+    )
+    sub_new.add_argument(
+        "--eol",
+        choices=["lf", "crlf", "native"],
+        default=None,
+        help="Line endings for text files (default: as in the template)",
+    )
+    sub_new.add_argument(
+        "--tasks",
+        choices=["just", "make", "task"],
+        default=None,
+        help="Task runner file for the template's tasks (default: [new] "
+        "tasks, then the template's own)",
+    )
+    sub_new.add_argument(
+        "--cmd",
+        action="append",
+        default=[],
+        metavar="NAME",
+        help="Go: put the main package in cmd/NAME/; again for another "
+        "binary sharing internal/",
+    )
+    sub_new.add_argument(
+        "--jobs",
+        "-j",
+        type=int,
+        default=0,
+        metavar="N",
+        help="Read, render and write files on N threads (default: auto)",
+    )
+    sub_new.add_argument(
+        "--open",
+        nargs="?",
+        const="",
+        default=None,
+        metavar="EDITOR",
+        help="Open the project in EDITOR (default: $EDITOR, code, emacs)",
+    )
+    sub_new.add_argument(
+        "--json",
+        action="store_true",
+        help="Write JSON Lines events to stdout (implies --no-input)",
+    )
+    sub_new.add_argument(
+        "--dry-run",
+        action="store_true",
+        help="Print the files that would be written; touch nothing",
+    )
+    sub_new.add_argument(
+        "--on-conflict",
+        choices=["skip", "overwrite", "backup", "prompt"],
+        default=None,
+        help="Allow an existing target; what to do with differing files",
+    )
+    sub_new.add_argument(
+        "--profile",
+        default=None,
+        help="Apply [profiles.PROFILE] from the jolo config",
+    )
+    sub_new.add_argument(
+        "--templates-dir",
+        action="append",
+        default=[],
+        metavar="DIR",
+        help="Extra template directory; shadows builtins by name",
+    )
+    sub_new.add_argument(
+        "--git",
+        type=parse_bool_arg,
+        nargs="?",
+        const=True,
+        default=None,
+        metavar="BOOL",
+        help="git init and commit the new project (default true; "
+        "--git=false skips)",
+    )
+    sub_new.add_argument(
+        "--remote",
+        default=None,
+        metavar="URL",
+        help="Add URL as the new repository's origin",
+    )
+    sub_new.add_argument(
+        "--register",
+        action="store_true",
+        help="Add a task running the new project's recipes to the "
+        "repository's root Taskfile or Makefile",
+    )
+    sub_new.add_argument(
+        "--author",
+        default=None,
+        metavar="NAME",
+        help="AUTHOR for the templates (default: [new] author, then git's "
+        "user.name)",
+    )
+    sub_new.add_argument(
+        "--email",
+        default=None,
+        metavar="ADDR",
+        help="EMAIL for the templates (default: [new] email, then git's "
+        "user.email)",
+    )
+    sub_new.add_argument(
+        "--license",
+        default=None,
+        metavar="NAME",
+        help="Add a LICENSE (mit, apache-2.0, gpl-3.0, mpl-2.0, "
+        "bsd-3-clause, isc, unlicense, none, ...) and its SPDX id",
+    )
+    sub_new.add_argument(
+        "--output",
+        "-o",
+        default=None,
+        metavar="FILE",
+        help="Write a .zip/.tar/.tar.gz archive instead (- for tar on "
+        "stdout); hooks are skipped",
+    )
+    sub_new.add_argument(
+        "--record",
+        default=None,
+        metavar="FILE",
+        help="Save every answer to FILE (TOML) for --answers",
+    )
+    sub_new.add_argument(
+        "--answers",
+        default=None,
+        metavar="FILE",
+        help="Replay answers saved with --record instead of prompting",
+    )
+
+    # upgrade: re-render the template in jolo.lock and merge it in
+    sub_upgrade = subparsers.add_parser(
+        "upgrade",
+        parents=[p_verbose, p_offline, p_verify],
+        help="Merge template changes into a project made by jolo new",
+    )
+    sub_upgrade.add_argument(
+        "--ref",
+        default=None,
+        help="Git ref or version pin (1.2, ^1) to upgrade to",
+    )
+    sub_upgrade.add_argument(
+        "--no-input",
+        action="store_true",
+        help="Never prompt for new template variables; use defaults",
+    )
+
+    # diff: project vs. the template output recorded in jolo.lock
+    subparsers.add_parser(
+        "diff",
+        parents=[p_verbose, p_offline],
+        help="Show how a jolo new project drifted from its template",
+    )
+
+    # add: run one of the project template's in-project generators
+    sub_add = subparsers.add_parser(
+        "add",
+        parents=[p_verbose, p_offline],
+        help="Add a handler, component, ... from the project's template",
+    )
+    sub_add.add_argument(
+        "generator",
+        nargs="?",
+        default=None,
+        help="Generator to run (omit to list them)",
+    )
+    sub_add.add_argument(
+        "name", nargs="?", default=None, help="What to call it ({{NAME}})"
+    )
+    sub_add.add_argument(
+        "--var",
+        action="append",
+        default=[],
+        metavar="KEY=VALUE",
+        help="Set a generator variable (repeatable)",
+    )
+    sub_add.add_argument(
+        "--no-input",
+        action="store_true",
+        help="Never prompt; use variable defaults",
+    )
+    sub_add.add_argument(
+        "--dry-run",
+        action="store_true",
+        help="Show what would be created and patched",
+    )
+    sub_add.add_argument(
+        "--force",
+        action="store_true",
+        help="Overwrite files that already exist (keeping their "
+        "jolo:keep regions)",
+    )
+
+    # eject: copy the project's template into it, to use from there on
+    subparsers.add_parser(
+        "eject",
+        parents=[p_verbose, p_offline],
+        help="Copy the project's template into .jolo/templates/ to change",
+    )
+
+    # capture: copy a project into a templates dir as a new template
+    sub_capture = subparsers.add_parser(
+        "capture",
+        parents=[p_verbose],
+        help="Turn an existing project into a template",
+    )
+    sub_capture.add_argument("source", help="Project directory to capture")
+    sub_capture.add_argument(
+        "name", help="Template name to create, e.g. team/service"
+    )
+    sub_capture.add_argument(
+        "--templates-dir",
+        default=None,
+        metavar="DIR",
+        help="Where to put it (default: first config templates_dirs)",
+    )
+    sub_capture.add_argument(
+        "--project-name",
+        default=None,
+        help="Name to replace with {{PROJECT_NAME}} (default: dir name)",
+    )
+    sub_capture.add_argument(
+        "--module-path",
+        default=None,
+        help="Module path to replace with {{MODULE_PATH}} (default: go.mod)",
+    )
+    sub_capture.add_argument(
+        "--description", default=None, help="Manifest description"
+    )
+    sub_capture.add_argument(
+        "--no-input",
+        action="store_true",
+        help="Make every replacement without asking",
+    )
+
+    # templates: what jolo new can render; search is the same with a query
+    sub_templates = subparsers.add_parser(
+        "templates",
+        aliases=["search"],
+        parents=[p_verbose],
+        help="List or fuzzy-search the templates jolo new can render",
+    )
+    sub_templates.add_argument(
+        "query",
+        nargs="*",
+        help="Keywords matched against name, language, tags, description",
+    )
+    sub_templates.add_argument(
+        "--tag", default=None, help="Only templates with this tag"
+    )
+    sub_templates.add_argument(
+        "--profile",
+        default=None,
+        help="Apply [profiles.PROFILE] from the jolo config",
+    )
+    sub_templates.add_argument(
+        "--templates-dir",
+        action="append",
+        default=[],
+        metavar="DIR",
+        help="Extra template directory; shadows builtins by name",
+    )
+
+    # show: a template's file tree and variables, or one rendered file
+    sub_show = subparsers.add_parser(
+        "show",
+        parents=[p_verbose, p_offline, p_verify],
+        help="Print a template's files and variables, or render one file",
+    )
+    sub_show.add_argument(
+        "template", help="Template name (go/web) or git source"
+    )
+    sub_show.add_argument(
+        "file",
+        nargs="?",
+        default=None,
+        help="Rendered path to print to stdout, e.g. main.go",
+    )
+    sub_show.add_argument(
+        "--var",
+        action="append",
+        default=[],
+        metavar="KEY=VALUE",
+        help="Set a template variable (repeatable; PROJECT_NAME too)",
+    )
+    sub_show.add_argument(
+        "--with",
+        dest="with_",
+        action="append",
+        default=[],
+        metavar="ADDONS",
+        help="Comma-separated addons to overlay, e.g. docker,sqlite",
+    )
+    sub_show.add_argument(
+        "--features",
+        action="append",
+        default=[],
+        metavar="FEATURES",
+        help="Answer the template's features prompt, e.g. docker,auth",
+    )
+    sub_show.add_argument(
+        "--tasks",
+        choices=["just", "make", "task"],
+        default=None,
+        help="Task runner file for the template's tasks",
+    )
+    sub_show.add_argument(
+        "--cmd",
+        action="append",
+        default=[],
+        metavar="NAME",
+        help="Go: put the main package in cmd/NAME/",
+    )
+    sub_show.add_argument(
+        "--profile",
+        default=None,
+        help="Apply [profiles.PROFILE] from the jolo config",
+    )
+    sub_show.add_argument(
+        "--templates-dir",
+        action="append",
+        default=[],
+        metavar="DIR",
+        help="Extra template directory; shadows builtins by name",
+    )
+
+    # verify: render each template and run its build/test commands
+    sub_verify = subparsers.add_parser(
+        "verify",
+        parents=[p_verbose],
+        help="Build and test every template in a temp dir (maintainers)",
+    )
+    sub_verify.add_argument(
+        "templates",
+        nargs="*",
+        metavar="TEMPLATE",
+        help="Templates to verify (default: all)",
+    )
+    sub_verify.add_argument(
+        "--keep",
+        action="store_true",
+        help="Keep the rendered projects and print where they are",
+    )
+    sub_verify.add_argument(
+        "--templates-dir",
+        action="append",
+        default=[],
+        metavar="DIR",
+        help="Extra template directory; shadows builtins by name",
+    )
+
+    # lint: check templates for undeclared placeholders and the like
+    sub_lint = subparsers.add_parser(
+        "lint",
+        parents=[p_verbose],
+        help="Check templates for mistakes (template authors)",
+    )
+    sub_lint.add_argument(
+        "templates",
+        nargs="*",
+        metavar="TEMPLATE",
+        help="Template names or directories (default: the current one)",
+    )
+    sub_lint.add_argument(
+        "--all",
+        action="store_true",
+        help="Every template and addon in the templates dirs",
+    )
+    sub_lint.add_argument(
+        "--templates-dir",
+        action="append",
+        default=[],
+        metavar="DIR",
+        help="Extra template directory; shadows builtins by name",
+    )
+
+    # snapshot: rendered templates vs. committed golden files
+    sub_snapshot = subparsers.add_parser(
+        "snapshot",
+        parents=[p_verbose],
+        help="Diff rendered templates against golden files (maintainers)",
+    )
+    sub_snapshot.add_argument("dir", help="Golden files, one dir per template")
+    sub_snapshot.add_argument(
+        "templates",
+        nargs="*",
+        metavar="TEMPLATE",
+        help="Templates to check (default: all)",
+    )
+    sub_snapshot.add_argument(
+        "--update",
+        action="store_true",
+        help="Rewrite the golden files from the current templates",
+    )
+    sub_snapshot.add_argument(
+        "--templates-dir",
+        action="append",
+        default=[],
+        metavar="DIR",
+        help="Extra template directory; shadows builtins by name",
+    )
+
+    # serve: jolo new over HTTP, projects returned as archives
+    sub_serve = subparsers.add_parser(
+        "serve",
+        parents=[p_verbose],
+        help="Serve templates over HTTP; POST /generate returns an archive",
+    )
+    sub_serve.add_argument(
+        "--host",
+        default="127.0.0.1",
+        help="Address to listen on (default: 127.0.0.1)",
+    )
+    sub_serve.add_argument(
+        "--port",
+        type=int,
+        default=8421,
+        help="Port to listen on (default: 8421; 0 picks a free one)",
+    )
+    sub_serve.add_argument(
+        "--templates-dir",
+        action="append",
+        default=[],
+        metavar="DIR",
+        help="Extra template directory; shadows builtins by name",
+    )
+
+    # mcp: the scaffolding tools for AI agents, over stdio
+    sub_mcp = subparsers.add_parser(
+        "mcp",
+        help="Run an MCP server (stdio) for agents to generate projects",
+    )
+    sub_mcp.add_argument(
+        "--workspace",
+        default=None,
+        metavar="DIR",
+        help="Where generated projects go (default: current directory)",
+    )
+    sub_mcp.add_argument(
+        "--templates-dir",
+        action="append",
+        default=[],
+        metavar="DIR",
+        help="Extra template directory; shadows builtins by name",
+    )
+
+    # cache: the fetched git template sources
+    sub_cache = subparsers.add_parser(
+        "cache",
+        parents=[p_verbose],
+        help="List, clean, or refresh cached git template sources",
+    )
+    sub_cache.add_argument(
+        "action",
+        nargs="?",
+        default="list",
+        choices=["list", "clean", "refresh"],
+    )
+    sub_cache.add_argument(
+        "match",
+        nargs="?",
+        default=None,
+        help="Only entries whose URL contains this",
+    )
+
+    # config: read and write the jolo config file
+    sub_config = subparsers.add_parser(
+        "config",
+        parents=[p_verbose],
+        help="Get, set, list, or unset settings in the jolo config",
+    )
+    sub_config.set_defaults(profile=None)
+    # Its own subcommands, so --profile can come between them and KEY;
+    # plain ``jolo config`` lists.
+    config_actions = sub_config.add_subparsers(dest="action", prog="config")
+    p_config = argparse.ArgumentParser(add_help=False)
+    p_config.add_argument(
+        "--profile",
+        default=None,
+        help="Read or write [profiles.PROFILE]",
+    )
+    p_config_file = argparse.ArgumentParser(add_help=False)
+    p_config_file.add_argument(
+        "--project",
+        action="store_true",
+        help="Write ./.jolo.toml instead of ~/.config/jolo/config.toml",
+    )
+    config_actions.add_parser(
+        "list", parents=[p_config], help="Print every setting in effect"
+    )
+    config_get = config_actions.add_parser(
+        "get", parents=[p_config], help="Print one setting"
+    )
+    config_get.add_argument("key", help="Dotted key, e.g. new.author")
+    config_set = config_actions.add_parser(
+        "set", parents=[p_config, p_config_file], help="Write a setting"
+    )
+    config_set.add_argument("key", help="Dotted key, e.g. new.author")
+    config_set.add_argument("value", help="TOML value, or plain text")
+    config_unset = config_actions.add_parser(
+        "unset", parents=[p_config, p_config_file], help="Remove a setting"
+    )
+    config_unset.add_argument("key", help="Dotted key, e.g. new.author")
+
+    # completion: print a shell completion script
+    sub_completion = subparsers.add_parser(
+        "completion",
+        help="Print a shell completion script (bash, zsh, fish)",
+    )
+    sub_completion.add_argument("shell", choices=["bash", "zsh", "fish"])
+
     # clone: prompt, agent, detach, exec, mounts, recreate, verbose
     sub_clone = subparsers.add_parser(
         "clone",
@@ -576,11 +1194,32 @@ def parse_args(argv: list[str]) -> argparse.Namespace:
     )
 
     # doctor: verbose
-    subparsers.add_parser(
+    sub_doctor = subparsers.add_parser(
         "doctor",
         parents=[p_verbose],
         help="Pre-flight check: runtime, image, ports, tools, API keys",
     )
+    sub_doctor.add_argument(
+        "templates",
+        nargs="*",
+        metavar="TEMPLATE",
+        help="Check the tools these templates need instead",
+    )
+    sub_doctor.add_argument(
+        "--with",
+        dest="with_",
+        action="append",
+        default=[],
+        metavar="ADDONS",
+        help="Comma-separated addons whose tools to check too",
+    )
+    sub_doctor.add_argument(
+        "--templates-dir",
+        action="append",
+        default=[],
+        metavar="DIR",
+        help="Extra template directory; shadows builtins by name",
+    )
 
     # attach: recreate, verbose
     subparsers.add_parser(
@@ -762,6 +1401,12 @@ def parse_args(argv: list[str]) -> argparse.Namespace:
         help="List projects with cross-container podman access enabled",
     )
 
+    return parser
+
+
+def parse_args(argv: list[str]) -> argparse.Namespace:
+    """Parse command-line arguments."""
+    parser = build_parser()
     if constants.HAVE_ARGCOMPLETE:
         argcomplete.autocomplete(parser)
 
@@ -12,7 +12,7 @@ from pathlib import Path
 
 import tomllib
 
-from _jolo import constants, registry
+from _jolo import constants, log, registry
 from _jolo.cli import (
     _format_container_display,
     _podman_proxy_pidfile,
@@ -52,6 +52,7 @@ from _jolo.container import (
     set_port,
     stop_container,
 )
+from _jolo.golang import default_module_path
 from _jolo.setup import (
     JOLO_POST_COMMIT_INSTALL_SCRIPT,
     TEMPLATE_HASHES_FILE,
@@ -149,8 +150,14 @@ def get_agent_name(
     return agents[index % len(agents)]
 
 
-def _fzf_pick(header: str, labels: list[str]) -> str | None:
-    """Run fzf picker with the given header and labels, return selected line."""
+def _fzf_pick(
+    header: str, labels: list[str], query: str = "", multi: bool = False
+) -> str | None:
+    """Run fzf picker with the given header and labels, return selected line.
+
+    QUERY starts the search off, as if typed. With MULTI, several lines
+    can be marked; they come back newline-separated.
+    """
     try:
         result = subprocess.run(
             [
@@ -161,7 +168,8 @@ def _fzf_pick(header: str, labels: list[str]) -> str | None:
                 "~10",
                 "--layout",
                 "reverse",
-                "--no-multi",
+                "--multi" if multi else "--no-multi",
+                *(["--query", query] if query else []),
             ],
             input="\n".join(labels),
             capture_output=True,
@@ -276,6 +284,7 @@ def _setup_container_env(workspace: Path, config: dict) -> None:
 def load_config(
     global_config_dir: Path | None = None,
     project_dir: Path | None = None,
+    profile: str | None = None,
 ) -> dict:
     """Load configuration from TOML files.
 
@@ -283,6 +292,8 @@ def load_config(
     1. Default config
     2. Global config: ~/.config/jolo/config.toml
     3. Project config: .jolo.toml in project_dir (defaults to cwd)
+    4. [profiles.<profile>] from the merged config, when profile is set;
+       its tables merge into same-named tables instead of replacing them
     """
     config = constants.DEFAULT_CONFIG.copy()
 
@@ -304,6 +315,15 @@ def load_config(
             project_cfg = tomllib.load(f)
             config.update(project_cfg)
 
+    if profile:
+        profiles = config.get("profiles", {})
+        if profile not in profiles:
+            sys.exit(f"Error: Unknown profile: {profile}")
+        for key, value in profiles[profile].items():
+            if isinstance(value, dict) and isinstance(config.get(key), dict):
+                value = {**config[key], **value}
+            config[key] = value
+
     # The gateway address is host-specific; the LITELLM_HOST env overrides the
     # configured value here so the rest of the code reads it from config alone.
     env_gateway = os.environ.get("LITELLM_HOST")
@@ -462,9 +482,7 @@ def run_port_mode(args: argparse.Namespace) -> None:
         if not 1 <= port <= 65535:
             sys.exit(f"Error: Port must be between 1 and 65535, got {port}.")
         if not is_port_available(port):
-            print(
-                f"Warning: Port {port} is currently in use.", file=sys.stderr
-            )
+            log.warning(f"Port {port} is currently in use.")
         set_port(git_root, port)
         print(f"Port set to {port}")
         if is_container_running(git_root):
@@ -931,8 +949,34 @@ def _fmt_size(nbytes: int) -> str:
     return f"{size:.1f} TB"
 
 
+def _doctor_templates(args: argparse.Namespace) -> None:
+    """``jolo doctor TEMPLATE...``: the tools the templates require."""
+    from _jolo import scaffold, toolchain
+
+    new_cfg = load_config().get("new", {})
+    roots = scaffold.template_roots(args.templates_dir, new_cfg)
+    templates = [scaffold.find_layered(n, roots)[0] for n in args.templates]
+    addons = [
+        scaffold.find_addon(a, templates[0])
+        for a in scaffold.parse_with_args(args.with_)
+    ]
+    checks = toolchain.check(toolchain.requirements([*templates, *addons]))
+    if not checks:
+        print("No tools declared under [requires]")
+        return
+    toolchain.print_checks(checks)
+    failed = sum(not c.ok for c in checks)
+    print()
+    print(f"{len(checks) - failed} passed, {failed} failed")
+    if failed:
+        sys.exit(1)
+
+
 def run_doctor_mode(args: argparse.Namespace) -> None:
     """Pre-flight check: runtime, image, ports, tools, API keys."""
+    if getattr(args, "templates", None):
+        _doctor_templates(args)
+        return
     ok_count = 0
     fail_count = 0
 
@@ -1407,12 +1451,14 @@ def run_create_mode(args: argparse.Namespace) -> None:
     test_config = get_test_framework_config(primary_flavor)
     module_name = to_snake_case(project_name)
     pascal_name = to_pascal_case(project_name)
+    module_path = default_module_path("", project_name)
 
     def replace_placeholders(text: str) -> str:
         return (
             text.replace("{{PROJECT_NAME}}", project_name)
             .replace("{{PROJECT_NAME_UNDERSCORE}}", module_name)
             .replace("{{MODULE_NAME}}", pascal_name)
+            .replace("{{MODULE_PATH}}", module_path)
         )
 
     if test_config.get("config_file"):
@@ -1870,8 +1916,8 @@ def run_spawn_mode(args: argparse.Namespace) -> None:
             print(f"  Ready: {path.name}")
 
     if failed:
-        print(
-            f"Warning: {len(failed)} container(s) failed to start: {', '.join(failed)}"
+        log.warning(
+            f"{len(failed)} container(s) failed to start: {', '.join(failed)}"
         )
 
     # If no prompt, just report status
@@ -2414,14 +2460,35 @@ def main(argv: list[str] | None = None) -> None:
     if argv is None:
         argv = sys.argv[1:]
 
+    # Hidden entry point for the scripts `jolo completion` prints.
+    if argv[:1] == ["__complete"]:
+        from _jolo.completion import run_complete
+
+        run_complete(argv[1:])
+        return
+
     args = parse_args(argv)
 
     # Set verbose mode
     if args.verbose:
         constants.VERBOSE = True
+    log.setup(
+        1 if args.verbose else -1 if args.quiet else 0,
+        plain=args.plain,
+    )
+    if getattr(args, "offline", False):
+        constants.OFFLINE = True
+    if getattr(args, "jobs", 0):
+        constants.JOBS = args.jobs
 
     cmd = args.command
 
+    if args.stdio:
+        from _jolo.stdio import run_stdio_mode
+
+        run_stdio_mode(args)
+        return
+
     if cmd == "list":
         run_list_mode(args)
         return
@@ -2472,6 +2539,106 @@ def main(argv: list[str] | None = None) -> None:
         run_expose_mode(args)
         return
 
+    if cmd == "new":
+        from _jolo import events
+        from _jolo.scaffold import run_new_mode
+
+        try:
+            run_new_mode(args)
+        except events.Failure as e:
+            sys.exit(events.exit_status(e))
+        return
+
+    if cmd == "upgrade":
+        from _jolo.upgrade import run_upgrade_mode
+
+        run_upgrade_mode(args)
+        return
+
+    if cmd == "diff":
+        from _jolo.upgrade import run_diff_mode
+
+        run_diff_mode(args)
+        return
+
+    if cmd == "add":
+        from _jolo.add import run_add_mode
+
+        run_add_mode(args)
+        return
+
+    if cmd == "eject":
+        from _jolo.eject import run_eject_mode
+
+        run_eject_mode(args)
+        return
+
+    if cmd == "capture":
+        from _jolo.capture import run_capture_mode
+
+        run_capture_mode(args)
+        return
+
+    if cmd == "lint":
+        from _jolo.lint import run_lint_mode
+
+        run_lint_mode(args)
+        return
+
+    if cmd == "verify":
+        from _jolo.verify import run_verify_mode
+
+        run_verify_mode(args)
+        return
+
+    if cmd == "snapshot":
+        from _jolo.snapshot import run_snapshot_mode
+
+        run_snapshot_mode(args)
+        return
+
+    if cmd == "serve":
+        from _jolo.serve import run_serve_mode
+
+        run_serve_mode(args)
+        return
+
+    if cmd == "mcp":
+        from _jolo.mcp import run_mcp_mode
+
+        run_mcp_mode(args)
+        return
+
+    if cmd == "config":
+        from _jolo.settings import run_config_mode
+
+        run_config_mode(args)
+        return
+
+    if cmd == "cache":
+        from _jolo.cache import run_cache_mode
+
+        run_cache_mode(args)
+        return
+
+    if cmd == "completion":
+        from _jolo.completion import run_completion_mode
+
+        run_completion_mode(args)
+        return
+
+    if cmd == "show":
+        from _jolo.show import run_show_mode
+
+        run_show_mode(args)
+        return
+
+    if cmd in ("templates", "search"):
+        from _jolo.catalog import run_templates_mode
+
+        run_templates_mode(args)
+        return
+
     if cmd == "allow":
         run_allow_mode(args)
         return
@@ -0,0 +1,223 @@
+"""jolo completion — shell completion for bash, zsh and fish.
+
+``jolo completion SHELL`` prints a script that asks jolo itself for
+candidates: the shell calls ``jolo __complete WORD...`` with the words
+typed after ``jolo`` (the last one possibly empty) and offers whatever
+comes back, one per line. Subcommands and flags are read from the
+argparse parser; template names, addons and ``--var`` keys come from the
+template directories at completion time. No output means "complete file
+names".
+"""
+
+from __future__ import annotations
+
+import argparse
+import sys
+from pathlib import Path
+
+from _jolo import aliases, catalog, licenses, scaffold
+from _jolo.cli import build_parser
+from _jolo.commands import load_config
+
+BASH = r"""# jolo bash completion; eval "$(jolo completion bash)"
+_jolo() {
+    local IFS=$'\n'
+    local cur=${COMP_WORDS[COMP_CWORD]}
+    COMPREPLY=($(jolo __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+    if [ ${#COMPREPLY[@]} -eq 0 ]; then
+        COMPREPLY=($(compgen -f -- "$cur"))
+    elif [ ${#COMPREPLY[@]} -eq 1 ] && [[ ${COMPREPLY[0]} == *= ]]; then
+        compopt -o nospace
+    fi
+}
+complete -F _jolo jolo
+"""
+
+ZSH = r"""#compdef jolo
+# jolo zsh completion; eval "$(jolo completion zsh)"
+_jolo() {
+    local -a candidates
+    local out="$(jolo __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"
+    candidates=(${(f)out})
+    if (( ${#candidates} )); then
+        compadd -S '' -- ${(M)candidates:#*=}
+        compadd -- ${candidates:#*=}
+    else
+        _files
+    fi
+}
+compdef _jolo jolo
+"""
+
+FISH = r"""# jolo fish completion; jolo completion fish | source
+function __jolo_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    jolo __complete $tokens[2..-1] 2>/dev/null
+end
+complete -c jolo -f -a '(__jolo_complete)'
+"""
+
+SCRIPTS = {"bash": BASH, "zsh": ZSH, "fish": FISH}
+
+
+def _subcommands(parser: argparse.ArgumentParser) -> dict:
+    for action in parser._actions:
+        if isinstance(action, argparse._SubParsersAction):
+            return action.choices
+    return {}
+
+
+def _options(parser: argparse.ArgumentParser) -> dict[str, argparse.Action]:
+    return {
+        opt: action
+        for action in parser._actions
+        for opt in action.option_strings
+    }
+
+
+def _takes_value(action: argparse.Action) -> bool:
+    return action.nargs != 0
+
+
+def _split(
+    parser: argparse.ArgumentParser, words: list[str]
+) -> tuple[list[str], dict[str, list[str]]]:
+    """Positionals and option values among the completed WORDS."""
+    options = _options(parser)
+    positionals, values = [], {}
+    pending = None
+    for word in words:
+        if pending:
+            values.setdefault(pending.dest, []).append(word)
+            pending = None
+        elif word.startswith("-"):
+            opt, eq, value = word.partition("=")
+            action = options.get(opt)
+            if action and _takes_value(action):
+                if eq:
+                    values.setdefault(action.dest, []).append(value)
+                else:
+                    pending = action
+        else:
+            positionals.append(word)
+    return positionals, values
+
+
+def _new_cfg(values: dict[str, list[str]]) -> dict:
+    profile = (values.get("profile") or [None])[-1]
+    return load_config(profile=profile).get("new", {})
+
+
+def _roots(values: dict[str, list[str]]) -> list[Path]:
+    """Template roots as ``jolo new`` would see them for these flags."""
+    new_cfg = _new_cfg(values)
+    return scaffold.template_roots(values.get("templates_dir", []), new_cfg)
+
+
+def _values(
+    command: str,
+    action: argparse.Action,
+    positionals: list[str],
+    values: dict[str, list[str]],
+    current: str,
+) -> list[str]:
+    """Candidates for the value of option ACTION."""
+    if action.choices:
+        return [str(c) for c in action.choices]
+    if (
+        command in ("new", "show")
+        and positionals
+        and action.dest in ("with_", "var", "features")
+    ):
+        name = positionals[0]
+        line = aliases.aliases(_new_cfg(values)).get(name, "")
+        name = line.split()[0] if line.split() else name
+        template, _ = scaffold.find_layered(name, _roots(values))
+        if action.dest == "var":
+            names = [*template.variables, *scaffold.CONFIG_VARIABLES]
+            return [f"{name}=" for name in names]
+        if action.dest == "features":
+            spec = template.variables.get("features", {})
+            known = [str(c) for c in spec.get("choices", [])]
+        else:
+            known = scaffold.addon_names(template)
+        # --with and --features take comma-separated lists; complete the
+        # last item.
+        done, _, _ = current.rpartition(",")
+        prefix = done + "," if done else ""
+        taken = scaffold.parse_with_args([*values.get(action.dest, []), done])
+        return [prefix + name for name in known if name not in taken]
+    if command == "new" and action.dest == "license":
+        return [name.lower() for name in licenses.LICENSES]
+    if command in ("templates", "search") and action.dest == "tag":
+        entries = catalog.catalog(_roots(values), [])
+        return sorted({tag for e in entries for tag in e.tags})
+    return []
+
+
+def _positional(
+    sub: argparse.ArgumentParser,
+    command: str,
+    positionals: list[str],
+    values: dict[str, list[str]],
+) -> list[str]:
+    """Candidates for the next positional argument of SUB."""
+    slots = [a for a in sub._actions if not a.option_strings]
+    if not slots:
+        return []
+    # A nargs="*" positional keeps taking words.
+    slot = slots[min(len(positionals), len(slots) - 1)]
+    if slot.choices:
+        return [str(c) for c in slot.choices]
+    if command in ("new", "show") and slot.dest == "template":
+        names = [t.name for t in scaffold.list_layered(_roots(values))]
+        return [*names, *aliases.aliases(_new_cfg(values))]
+    if command == "doctor" and slot.dest == "templates":
+        return [t.name for t in scaffold.list_layered(_roots(values))]
+    return []
+
+
+def complete(words: list[str]) -> list[str]:
+    """Completion candidates for WORDS (everything after ``jolo``; the
+    last word is the one being completed)."""
+    parser = build_parser()
+    *done, current = words or [""]
+    commands = _subcommands(parser)
+    if not done:
+        if current.startswith("-"):
+            candidates = list(_options(parser))
+        else:
+            candidates = list(commands)
+        return [c for c in candidates if c.startswith(current)]
+
+    command = done[0]
+    sub = commands.get(command)
+    if sub is None:
+        return []
+    options = _options(sub)
+    positionals, values = _split(sub, done[1:])
+
+    previous = options.get(done[-1]) if len(done) > 1 else None
+    try:
+        if previous and _takes_value(previous):
+            candidates = _values(
+                command, previous, positionals, values, current
+            )
+        elif current.startswith("-"):
+            candidates = list(options)
+        else:
+            candidates = _positional(sub, command, positionals, values)
+    except SystemExit:
+        # Unknown template or missing templates dir: nothing to offer.
+        return []
+    return [c for c in candidates if c.startswith(current)]
+
+
+def run_complete(words: list[str]) -> None:
+    for candidate in complete(words):
+        print(candidate)
+
+
+def run_completion_mode(args) -> None:
+    """Print the completion script for args.shell."""
+    sys.stdout.write(SCRIPTS[args.shell])
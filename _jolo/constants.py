@@ -80,6 +80,13 @@ EXPOSE_PUBLIC_URL = "https://pub.glvortex.net"
 # Global verbose flag
 VERBOSE = False
 
+# Global --offline flag: remote templates come from the cache only
+OFFLINE = False
+
+# Global --jobs flag: threads reading, rendering and writing template
+# files (0: $JOLO_JOBS, else picked from the CPU count; see parallel)
+JOBS = 0
+
 # Valid flavors for --flavor flag (also used directly in interactive picker)
 VALID_FLAVORS = [
     "typescript-web",
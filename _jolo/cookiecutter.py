@@ -0,0 +1,315 @@
+"""Cookiecutter templates, rendered by jolo.
+
+A directory with a ``cookiecutter.json`` and no ``template.toml`` (a
+local path or a git source such as
+``github.com/audreyfeldroy/cookiecutter-pypackage``) loads as a jolo
+template: its one ``{{cookiecutter.X}}`` directory is the template root,
+and cookiecutter.json becomes the variables (strings, lists as choices,
+booleans). X itself defaults to the project name given to ``jolo new``,
+since that directory is what jolo creates. Defaults may refer to
+earlier variables, as in cookiecutter.
+
+Contents and paths are rendered with the Jinja subset templates use in
+practice: ``{{ cookiecutter.x }}`` with string methods (``.lower()``,
+``.replace(' ', '_')``, ...) and filters (``lower``, ``replace``,
+``default``, ``slugify``, ...), ``{% if %}``/``{% elif %}``/``{% else
+%}`` on comparisons joined by ``and``/``or``/``not``, ``{% raw %}``,
+comments and ``-`` whitespace control. Anything else (``{% for %}``,
+macros, extensions) is reported as unsupported. ``_copy_without_render``
+globs are copied verbatim; cookiecutter's ``hooks/`` scripts are not
+run.
+"""
+
+from __future__ import annotations
+
+import ast
+import json
+import re
+import sys
+from pathlib import Path
+
+from _jolo.placeholders import FILTERS
+
+CONFIG_FILE = "cookiecutter.json"
+
+_TAG = re.compile(
+    r"\{%(-?)\s*raw\s*(-?)%\}(.*?)\{%(-?)\s*endraw\s*(-?)%\}"
+    r"|\{\{(-?)(.*?)(-?)\}\}"
+    r"|\{%(-?)(.*?)(-?)%\}"
+    r"|\{#(-?).*?(-?)#\}",
+    re.S,
+)
+_ROOT = re.compile(r"\{\{\s*cookiecutter\.(\w+)\s*\}\}")
+
+_METHODS = {
+    "lower": str.lower,
+    "upper": str.upper,
+    "title": str.title,
+    "capitalize": str.capitalize,
+    "strip": str.strip,
+    "lstrip": str.lstrip,
+    "rstrip": str.rstrip,
+    "replace": str.replace,
+    "startswith": str.startswith,
+    "endswith": str.endswith,
+}
+
+
+def _slugify(value: str, separator: str = "-") -> str:
+    return separator.join(re.findall(r"[a-z0-9]+", str(value).lower()))
+
+
+_FILTERS = {
+    "lower": lambda v: str(v).lower(),
+    "upper": lambda v: str(v).upper(),
+    "title": lambda v: str(v).title(),
+    "capitalize": lambda v: str(v).capitalize(),
+    "trim": lambda v: str(v).strip(),
+    "replace": lambda v, old, new: str(v).replace(old, new),
+    "default": lambda v, default="": v if v not in ("", None) else default,
+    "length": len,
+    "string": str,
+    "slugify": _slugify,
+    **{
+        name: lambda v, f=f: f(str(v))
+        for name, f in FILTERS.items()
+        if name in ("snake", "kebab", "pascal", "camel")
+    },
+}
+
+
+def is_cookiecutter(path: Path) -> bool:
+    return (path / CONFIG_FILE).is_file()
+
+
+def project_dir(path: Path) -> Path:
+    """The ``{{cookiecutter.X}}`` directory of the template at PATH."""
+    dirs = [
+        p for p in sorted(path.iterdir()) if p.is_dir() and "{{" in p.name
+    ]
+    if len(dirs) != 1:
+        sys.exit(
+            f"Error: {path}: expected one {{{{cookiecutter.*}}}} "
+            f"directory next to {CONFIG_FILE}, found {len(dirs)}"
+        )
+    return dirs[0]
+
+
+def manifest(path: Path) -> dict:
+    """A jolo manifest (``engine = "cookiecutter"``) for the cookiecutter
+    template at PATH."""
+    try:
+        config = json.loads((path / CONFIG_FILE).read_text())
+    except json.JSONDecodeError as e:
+        sys.exit(f"Error: {path / CONFIG_FILE}: {e}")
+    prompts = config.get("__prompts__", {})
+    root = _ROOT.fullmatch(project_dir(path).name)
+    variables = {}
+    for name, value in config.items():
+        if name.startswith("_"):
+            continue
+        if isinstance(value, bool):
+            spec = {"type": "bool", "default": value}
+        elif isinstance(value, list) and value:
+            choices = [str(c) for c in value]
+            spec = {
+                "type": "choice",
+                "choices": choices,
+                "default": choices[0],
+            }
+        elif isinstance(value, (str, int, float)):
+            spec = {"type": "string", "default": str(value)}
+        else:
+            sys.exit(f"Error: {path / CONFIG_FILE}: {name}: unsupported value")
+        if isinstance(prompts.get(name), str):
+            spec["prompt"] = prompts[name]
+        variables[name] = spec
+    if root and root[1] in variables:
+        variables[root[1]]["default"] = "{{ cookiecutter.PROJECT_NAME }}"
+    return {
+        "engine": "cookiecutter",
+        "variables": variables,
+        "binary": list(config.get("_copy_without_render", [])),
+    }
+
+
+def _truthy(value) -> bool:
+    if isinstance(value, str) and value in ("true", "false"):
+        return value == "true"  # jolo's bool variables
+    return bool(value)
+
+
+class _Eval:
+    """Evaluate one Jinja expression over the cookiecutter variables."""
+
+    def __init__(self, variables: dict[str, str]):
+        self.variables = variables
+
+    def __call__(self, text: str):
+        parts = _split_filters(text)
+        value = self.node(_parse(parts[0]))
+        for part in parts[1:]:
+            call = _parse(part)
+            name, args = call, []
+            if isinstance(call, ast.Call):
+                name, args = call.func, [self.node(a) for a in call.args]
+            if not isinstance(name, ast.Name) or name.id not in _FILTERS:
+                raise ValueError(f"unsupported filter: {part.strip()}")
+            value = _FILTERS[name.id](value, *args)
+        return value
+
+    def node(self, node):
+        if isinstance(node, ast.Constant):
+            return node.value
+        if isinstance(node, (ast.List, ast.Tuple)):
+            return [self.node(n) for n in node.elts]
+        if isinstance(node, ast.Name):
+            names = {"true": True, "false": False, "none": None}
+            if node.id.lower() in names:
+                return names[node.id.lower()]
+            raise ValueError(f"unknown name: {node.id}")
+        if isinstance(node, ast.Attribute):
+            if isinstance(node.value, ast.Name) and (
+                node.value.id == "cookiecutter"
+            ):
+                if node.attr not in self.variables:
+                    raise ValueError(f"undefined: cookiecutter.{node.attr}")
+                return self.variables[node.attr]
+            raise ValueError(f"unsupported attribute: {node.attr}")
+        if isinstance(node, ast.Call) and isinstance(node.func, ast.Attribute):
+            method = _METHODS.get(node.func.attr)
+            if not method:
+                raise ValueError(f"unsupported method: {node.func.attr}")
+            target = str(self.node(node.func.value))
+            return method(target, *(self.node(a) for a in node.args))
+        if isinstance(node, ast.BoolOp):
+            values = (self.node(v) for v in node.values)
+            if isinstance(node.op, ast.And):
+                return all(_truthy(v) for v in values)
+            return any(_truthy(v) for v in values)
+        if isinstance(node, ast.UnaryOp) and isinstance(node.op, ast.Not):
+            return not _truthy(self.node(node.operand))
+        if isinstance(node, ast.BinOp) and isinstance(node.op, ast.Add):
+            return self.node(node.left) + self.node(node.right)
+        if isinstance(node, ast.Compare):
+            return _compare(self, node)
+        raise ValueError(f"unsupported expression: {ast.unparse(node)}")
+
+
+_COMPARE = {
+    ast.Eq: lambda a, b: a == b,
+    ast.NotEq: lambda a, b: a != b,
+    ast.In: lambda a, b: a in b,
+    ast.NotIn: lambda a, b: a not in b,
+    ast.Lt: lambda a, b: a < b,
+    ast.LtE: lambda a, b: a <= b,
+    ast.Gt: lambda a, b: a > b,
+    ast.GtE: lambda a, b: a >= b,
+}
+
+
+def _compare(evaluate: _Eval, node: ast.Compare) -> bool:
+    left = evaluate.node(node.left)
+    for op, comparator in zip(node.ops, node.comparators):
+        right = evaluate.node(comparator)
+        if type(op) not in _COMPARE or not _COMPARE[type(op)](left, right):
+            return False
+        left = right
+    return True
+
+
+def _parse(text: str) -> ast.AST:
+    try:
+        return ast.parse(text.strip(), mode="eval").body
+    except SyntaxError:
+        raise ValueError(f"unsupported expression: {text.strip()}") from None
+
+
+def _split_filters(text: str) -> list[str]:
+    """TEXT split at the ``|`` outside quotes and brackets."""
+    parts, depth, quote, start = [], 0, "", 0
+    for i, c in enumerate(text):
+        if quote:
+            quote = "" if c == quote else quote
+        elif c in "'\"":
+            quote = c
+        elif c in "([":
+            depth += 1
+        elif c in ")]":
+            depth -= 1
+        elif c == "|" and depth == 0:
+            parts.append(text[start:i])
+            start = i + 1
+    return [*parts, text[start:]]
+
+
+def _tokens(text: str) -> list[tuple[str, str]]:
+    """TEXT as (kind, value) tokens, with ``-`` whitespace control
+    already applied to the text around them."""
+    tokens = []
+    pos = 0
+    strip_next = False
+    for m in _TAG.finditer(text):
+        before = text[pos : m.start()]
+        if strip_next:
+            before = before.lstrip()
+        groups = m.groups()
+        left = groups[0] or groups[5] or groups[8] or groups[11]
+        right = groups[4] or groups[7] or groups[10] or groups[12]
+        if left:
+            before = before.rstrip()
+        tokens.append(("text", before))
+        if m[3] is not None:
+            tokens.append(("text", m[3]))
+        elif m[7] is not None:
+            tokens.append(("expr", m[7]))
+        elif m[10] is not None:
+            tokens.append(("tag", m[10].strip()))
+        strip_next = bool(right)
+        pos = m.end()
+    rest = text[pos:]
+    tokens.append(("text", rest.lstrip() if strip_next else rest))
+    return tokens
+
+
+def render(text: str, variables: dict[str, str]) -> str:
+    """Render cookiecutter TEXT; VARIABLES are the ``cookiecutter.X``
+    values. Raises ValueError for what jolo does not support."""
+    if "{" not in text:
+        return text
+    evaluate = _Eval(variables)
+    out = []
+    # One frame per open {% if %}: [emitting now, some branch taken].
+    stack = []
+    for kind, value in _tokens(text):
+        active = all(frame[0] for frame in stack)
+        if kind == "text":
+            if active:
+                out.append(value)
+            continue
+        if kind == "expr":
+            if active:
+                result = evaluate(value)
+                out.append("" if result is None else str(result))
+            continue
+        word, _, rest = value.partition(" ")
+        if word == "if":
+            taken = active and _truthy(evaluate(rest))
+            stack.append([taken, taken])
+        elif word in ("elif", "else", "endif") and not stack:
+            raise ValueError(f"{{% {word} %}} without {{% if %}}")
+        elif word == "elif":
+            frame = stack[-1]
+            parent = all(f[0] for f in stack[:-1])
+            frame[0] = parent and not frame[1] and _truthy(evaluate(rest))
+            frame[1] = frame[1] or frame[0]
+        elif word == "else":
+            frame = stack[-1]
+            frame[0], frame[1] = not frame[1], True
+        elif word == "endif":
+            stack.pop()
+        else:
+            raise ValueError(f"unsupported Jinja tag: {{% {word} %}}")
+    if stack:
+        raise ValueError("{% if %} without {% endif %}")
+    return "".join(out)
@@ -0,0 +1,70 @@
+"""``jolo new --open``: the new project, opened in an editor.
+
+``jolo new --open[=EDITOR]`` opens the project once it is written.
+EDITOR, or else ``[new] editor`` in the config, ``$VISUAL`` or
+``$EDITOR``, is a command line; without any of them the first of VS
+Code and emacsclient found is used. VS Code and friends open the
+directory in a window of their own and emacs goes through
+``emacsclient -n``; anything else (vim, hx) runs in the terminal until
+it exits. An editor that fails to start is a warning.
+"""
+
+from __future__ import annotations
+
+import os
+import shlex
+import shutil
+import subprocess
+from pathlib import Path
+
+from _jolo import log
+
+# Editors that open a window of their own and return at once.
+_GUI = ("code", "code-insiders", "codium", "cursor", "zed", "subl")
+
+
+def _default() -> str:
+    for var in ("VISUAL", "EDITOR"):
+        if os.environ.get(var):
+            return os.environ[var]
+    for program in ("code", "emacsclient"):
+        if shutil.which(program):
+            return program
+    return ""
+
+
+def command(editor: str, path: Path) -> list[str]:
+    """The command line that opens PATH in EDITOR (a command line too)."""
+    words = shlex.split(editor)
+    program = Path(words[0]).name
+    if program == "emacs" and shutil.which("emacsclient"):
+        words = ["emacsclient", *words[1:]]
+        program = "emacsclient"
+    if program == "emacsclient" and "-n" not in words:
+        words.append("-n")
+    return [*words, str(path)]
+
+
+def open_project(path: Path, editor: str = "") -> None:
+    """Open PATH in EDITOR, or the user's editor if that is empty."""
+    editor = editor or _default()
+    if not editor:
+        log.warning("--open: no editor found (set $EDITOR or [new] editor)")
+        return
+    cmd = command(editor, path)
+    try:
+        if Path(cmd[0]).name in _GUI:
+            subprocess.Popen(
+                cmd,
+                stdin=subprocess.DEVNULL,
+                stdout=subprocess.DEVNULL,
+                stderr=subprocess.DEVNULL,
+                start_new_session=True,
+            )
+            return
+        result = subprocess.run(cmd)
+    except OSError as e:
+        log.warning(f"--open: {e}")
+        return
+    if result.returncode != 0:
+        log.warning(f"{' '.join(cmd)} exited {result.returncode}")
@@ -0,0 +1,80 @@
+"""Language rules for the project's ``.editorconfig``.
+
+``--with editorconfig`` adds an ``.editorconfig`` with the rules every
+project shares (UTF-8, LF, a final newline, no trailing whitespace,
+four-space indents). Templates and addons tune it for their files under
+``[editorconfig]``, one table per section glob:
+
+    [editorconfig."*.go"]
+    indent_style = "tab"
+
+Sections are appended after the shared ones, so they win for the files
+they match; a glob several templates set is written once, later
+templates winning key by key. Inside an existing work tree the file
+drops ``root = true``, so the repository's own ``.editorconfig`` still
+applies above it.
+"""
+
+from __future__ import annotations
+
+import re
+import sys
+
+FILE = ".editorconfig"
+_ROOT = re.compile(r"^[ \t]*root[ \t]*=[ \t]*true[ \t]*\n?", re.M | re.I)
+
+
+def _value(value) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
+
+
+def sections(templates: list) -> dict[str, dict[str, str]]:
+    """The manifests' ``[editorconfig]`` sections: glob -> properties."""
+    found: dict[str, dict[str, str]] = {}
+    for template in templates:
+        table = template.manifest.get("editorconfig", {})
+        if not isinstance(table, dict) or not all(
+            isinstance(v, dict) for v in table.values()
+        ):
+            sys.exit(
+                f'Error: {template.name}: [editorconfig."GLOB"] tables '
+                "hold the properties for each glob"
+            )
+        for glob, props in table.items():
+            for key, value in props.items():
+                if isinstance(value, (dict, list)):
+                    sys.exit(
+                        f"Error: {template.name}: editorconfig.{glob}.{key} "
+                        "must be a string, integer or boolean"
+                    )
+                found.setdefault(glob, {})[key] = _value(value)
+    return found
+
+
+def apply(
+    files: list[tuple[str, str | bytes]], templates: list
+) -> list[tuple[str, str | bytes]]:
+    """FILES with the templates' sections added to the .editorconfig."""
+    text = dict(files).get(FILE)
+    found = sections(templates)
+    if not isinstance(text, str) or not found:
+        return files
+    blocks = [text.rstrip("\n")] if text.strip() else []
+    for glob, props in found.items():
+        lines = [f"[{glob}]", *(f"{k} = {v}" for k, v in props.items())]
+        blocks.append("\n".join(lines))
+    text = "\n\n".join(blocks) + "\n"
+    return [(rel, text if rel == FILE else c) for rel, c in files]
+
+
+def nested(
+    files: list[tuple[str, str | bytes]],
+) -> list[tuple[str, str | bytes]]:
+    """FILES with ``root = true`` taken out of the .editorconfig, for a
+    project inside a repository that may have one of its own."""
+    return [
+        (rel, _ROOT.sub("", c) if rel == FILE and isinstance(c, str) else c)
+        for rel, c in files
+    ]
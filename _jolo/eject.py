@@ -0,0 +1,106 @@
+"""jolo eject — copy a project's template into the project itself.
+
+``jolo eject`` inside a project made by ``jolo new`` reads its jolo.lock
+and copies the template as it was at the locked commit into
+``.jolo/templates/lang/<name>``, laid out like ``templates/``: the
+templates it extends go beside it, and the locked addons under
+``.jolo/templates/addons/``. A git template is
+named after its repo (or the last part of its subdir). The lock then
+records ``ejected``, and ``jolo add``, ``jolo upgrade`` and ``jolo diff``
+use the copy from there on, so a team can commit and change it as their
+own. The first upgrade after ejecting merges in what the team changed
+in the copy since.
+"""
+
+from __future__ import annotations
+
+import contextlib
+import shutil
+import sys
+from pathlib import Path
+
+from _jolo import log, remote
+from _jolo.scaffold import (
+    EJECT_DIR,
+    MANIFEST_FILE,
+    Template,
+    find_addon,
+    read_lock,
+    write_lock,
+)
+from _jolo.upgrade import locked_template
+
+
+def ejected_name(spec: str) -> str:
+    """The name SPEC's template gets in the project's copy."""
+    if not remote.is_remote(spec):
+        return spec
+    parsed = remote.parse_spec(spec)
+    last = (parsed.subdir or parsed.url).rstrip("/").rsplit("/", 1)[-1]
+    return last.removesuffix(".git")
+
+
+def _skipped(directory: str, names: list[str]) -> list[str]:
+    """Of NAMES in DIRECTORY, .git and the templates nested there, which
+    are not the ejected template's."""
+    return [
+        n
+        for n in names
+        if n == ".git" or (Path(directory) / n / MANIFEST_FILE).is_file()
+    ]
+
+
+def _copy(src: Path, dest: Path) -> None:
+    shutil.copytree(src, dest, symlinks=True, ignore=_skipped)
+
+
+def eject(
+    template: Template,
+    name: str,
+    addons: list[Template],
+    addons_root: Path,
+    dest: Path,
+) -> list[str]:
+    """Copy TEMPLATE into DEST/lang/NAME, with its parents beside it,
+    and ADDONS (found below ADDONS_ROOT) into DEST/addons;
+    return the directories written, relative to DEST."""
+    written = []
+    lang = dest / "lang"
+    chain, t = [(template, name)], template.parent
+    while t is not None:
+        chain.append((t, t.name))
+        t = t.parent
+    for t, rel in chain:
+        _copy(t.path, lang / rel)
+        written.append(f"lang/{rel}")
+    # A git template's manifest is optional; the copy is found by its own.
+    (lang / name / MANIFEST_FILE).touch()
+    for addon in addons:
+        rel = addon.path.relative_to(addons_root).as_posix()
+        _copy(addon.path, dest / "addons" / rel)
+        written.append(f"addons/{rel}")
+    return written
+
+
+def run_eject_mode(args) -> None:
+    """Copy ./jolo.lock's template into .jolo/templates/ and use it."""
+    project = Path.cwd()
+    lock = read_lock(project)
+    if lock.get("ejected"):
+        sys.exit(
+            f"Error: {lock['template']} is already ejected to "
+            f"{EJECT_DIR / 'lang' / lock['ejected']}"
+        )
+    name = ejected_name(lock["template"])
+    dest = project / EJECT_DIR
+    if (dest / "lang" / name).exists():
+        sys.exit(f"Error: {EJECT_DIR / 'lang' / name} already exists")
+
+    with contextlib.ExitStack() as stack:
+        template, addons_root = locked_template(lock, stack)
+        addons = [find_addon(a, template, addons_root) for a in lock["addons"]]
+        written = eject(template, name, addons, addons_root, dest)
+
+    write_lock(project, {**lock, "ejected": name})
+    log.summary(f"Ejected {lock['template']} to {EJECT_DIR / 'lang' / name}")
+    log.summary(f"Copied: {', '.join(written)}")
@@ -0,0 +1,78 @@
+"""Environment variables for the project's direnv ``.envrc``.
+
+A template or addon names the variables its project wants while
+developing under ``[env]``; values are rendered like template files:
+
+    [env]
+    RUST_LOG = "{{PROJECT_NAME_UNDERSCORE}}=debug"
+
+When the project has a ``.envrc`` (``--with direnv``, or one the
+template ships), each becomes an ``export NAME=VALUE`` line, unless the
+file sets NAME already. They go above ``source_env_if_exists`` so a
+``.envrc.local`` still has the last word. Without a ``.envrc`` they are
+not written anywhere.
+"""
+
+from __future__ import annotations
+
+import re
+import shlex
+import sys
+
+from _jolo import placeholders
+
+FILE = ".envrc"
+_NAME = re.compile(r"[A-Za-z_]\w*")
+
+
+def exports(templates: list, variables: dict[str, str]) -> dict[str, str]:
+    """The manifests' ``[env]`` tables, rendered; later templates win."""
+    found = {}
+    for template in templates:
+        table = template.manifest.get("env", {})
+        if not isinstance(table, dict):
+            sys.exit(f"Error: {template.name}: [env] must be a table")
+        for name, value in table.items():
+            if not _NAME.fullmatch(name) or not isinstance(value, str):
+                sys.exit(
+                    f"Error: {template.name}: env.{name} must be a string "
+                    "under a shell variable name"
+                )
+            found[name] = placeholders.render(value, variables)
+    return found
+
+
+def _sets(text: str) -> set[str]:
+    return set(re.findall(r"^\s*export\s+([A-Za-z_]\w*)=", text, re.M))
+
+
+def apply(
+    files: list[tuple[str, str | bytes]],
+    templates: list,
+    variables: dict[str, str],
+) -> list[tuple[str, str | bytes]]:
+    """FILES with the ``[env]`` exports added to the ``.envrc``."""
+    envrc = dict(files).get(FILE)
+    if not isinstance(envrc, str):
+        return files
+    have = _sets(envrc)
+    lines = [
+        f"export {name}={shlex.quote(value)}\n"
+        for name, value in exports(templates, variables).items()
+        if name not in have
+    ]
+    if not lines:
+        return files
+    rows = envrc.splitlines(keepends=True)
+    at = next(
+        (
+            i
+            for i, row in enumerate(rows)
+            if row.lstrip().startswith("source_env_if_exists")
+        ),
+        len(rows),
+    )
+    if at and not rows[at - 1].endswith("\n"):
+        rows[at - 1] += "\n"
+    text = "".join([*rows[:at], *lines, *rows[at:]])
+    return [(rel, text if rel == FILE else c) for rel, c in files]
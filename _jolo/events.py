@@ -0,0 +1,87 @@
+"""``jolo new --json``: a JSON Lines event stream, and jolo's exit codes.
+
+With ``--json``, stdout carries one JSON object per line and nothing
+else; the human-readable messages, hook output included, go to stderr
+and prompting is off (as with ``--no-input``). Every object has an
+``event``:
+
+- ``start``: ``template``, ``addons``, ``target``
+- ``plan``: ``path``, ``bytes``, ``action`` (``--dry-run`` only)
+- ``conflict``: ``path``, ``decision`` (skip, overwrite, backup)
+- ``file``: ``path``, ``bytes``, ``action`` (create, overwrite, backup)
+- ``hook``: ``stage``, ``command``, ``status`` (started, ok, failed),
+  ``exit`` once it has finished
+- ``install``: ``command``, ``status`` (ok, fail, skip, not run),
+  ``seconds``
+- ``verify``: the same, for the checks that follow (see
+  ``_jolo.smoke``)
+- ``summary``: ``target``, ``template``, ``files`` (count per action),
+  ``exit``; the last line of a run that did not fail
+- ``error``: ``message``, ``exit``; the last line of one that did
+
+The exit status means the same with or without ``--json``: see
+``EXIT``. argparse's usage errors are 2.
+"""
+
+from __future__ import annotations
+
+import json
+import sys
+from typing import TextIO
+
+EXIT = {
+    "ok": 0,
+    "error": 1,
+    "usage": 2,
+    "conflict": 3,  # the target exists and --on-conflict was not given
+    "hook": 4,  # a pre or post hook failed
+    "tools": 5,  # jolo new --check found a tool missing or too old
+    "verify": 6,  # the new project failed its [verify] checks
+    "interrupted": 130,
+}
+
+_stream: TextIO | None = None
+
+
+class Failure(SystemExit):
+    """An error with its own exit status; ``code`` is the message, as for
+    ``sys.exit("Error: ...")``."""
+
+    def __init__(self, message: str, status: str):
+        super().__init__(message)
+        self.status = EXIT[status]
+
+
+def enable(stream: TextIO) -> None:
+    global _stream
+    _stream = stream
+
+
+def disable() -> None:
+    global _stream
+    _stream = None
+
+
+def enabled() -> bool:
+    return _stream is not None
+
+
+def emit(event: str, **fields) -> None:
+    """Write one event, if --json is on."""
+    if _stream is None:
+        return
+    _stream.write(json.dumps({"event": event, **fields}) + "\n")
+    _stream.flush()
+
+
+def exit_status(error: SystemExit) -> int:
+    """The exit status for ERROR, reported as an ``error`` event."""
+    if error.code is None or isinstance(error.code, int):
+        status = error.code or 0
+        if status:
+            emit("error", message="", exit=status)
+        return status
+    status = getattr(error, "status", EXIT["error"])
+    print(error.code, file=sys.stderr)
+    emit("error", message=str(error.code), exit=status)
+    return status
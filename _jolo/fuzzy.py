@@ -0,0 +1,59 @@
+"""Near misses: "did you mean" suggestions and the picker's filter.
+
+An unknown template or addon name is reported with the closest known
+ones, ``Unknown template: gp/web (did you mean go/web?)``: names that
+are spelled nearly the same, and for a bare name, those ending in it
+(``web`` suggests ``go/web`` and ``python/web``). Where ``jolo new``
+may prompt, it opens the template picker instead, filtered by what was
+typed.
+
+The picker's filter matches the way fzf does: the letters of the query
+in order, not necessarily together, ignoring case. Whole substrings
+rank first, then tighter matches.
+"""
+
+from __future__ import annotations
+
+import difflib
+
+
+def suggest(word: str, candidates: list[str], n: int = 3) -> list[str]:
+    """Up to N of CANDIDATES that WORD has probably misspelled."""
+    found = difflib.get_close_matches(word, candidates, n, cutoff=0.6)
+    if "/" not in word:
+        found += [c for c in candidates if c.rsplit("/", 1)[-1] == word]
+    return list(dict.fromkeys(c for c in found if c != word))[:n]
+
+
+def did_you_mean(word: str, candidates: list[str]) -> str:
+    """`` (did you mean A or B?)`` for an error message, or ``""``."""
+    close = suggest(word, candidates)
+    if not close:
+        return ""
+    if len(close) > 1:
+        return f" (did you mean {', '.join(close[:-1])} or {close[-1]}?)"
+    return f" (did you mean {close[0]}?)"
+
+
+def _rank(query: str, text: str) -> tuple[int, int, int] | None:
+    query, text = query.lower(), text.lower()
+    at = text.find(query)
+    if at >= 0:
+        return (0, at, len(text))
+    start = pos = -1
+    for char in query:
+        pos = text.find(char, pos + 1)
+        if pos < 0:
+            return None
+        if start < 0:
+            start = pos
+    return (1, pos - start, len(text))
+
+
+def matches(query: str, candidates: list[str]) -> list[str]:
+    """CANDIDATES that QUERY picks out, best first; all of them, in
+    order, for an empty QUERY."""
+    if not query:
+        return list(candidates)
+    ranked = [(_rank(query, c), i, c) for i, c in enumerate(candidates)]
+    return [c for rank, _, c in sorted(r for r in ranked if r[0])]
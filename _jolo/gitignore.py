@@ -0,0 +1,103 @@
+"""A .gitignore for each new project, from a library of rules.
+
+``templates/gitignore/`` holds one rule set per toolchain (``go``,
+``node``, ``python``, ``rust``, ``elixir``) plus ``common``. A template
+gets ``common`` and the set for its ``language`` (default: the first
+part of its name; ``typescript`` and ``javascript`` use ``node``), and
+its manifest may add more, and entries of its own::
+
+    [gitignore]
+    rules = ["node"]                # extra rule sets
+    entries = ["public/styles.css"]
+
+Addons add theirs the same way. Rule sets and entries are rendered
+like template files, so ``/{{PROJECT_NAME}}`` ignores a Go binary. A
+``.gitignore`` the template ships is kept below the generated rules,
+less the lines already in them. Templates with no known language (most
+git sources) and no ``[gitignore]`` are left as they are.
+"""
+
+from __future__ import annotations
+
+import sys
+
+from _jolo import placeholders
+from _jolo.templates import _TEMPLATES_DIR
+
+RULES_DIR = _TEMPLATES_DIR / "gitignore"
+FILE = ".gitignore"
+ALIASES = {"typescript": "node", "javascript": "node"}
+
+
+def available() -> list[str]:
+    return sorted(p.stem for p in RULES_DIR.glob("*.gitignore"))
+
+
+def rule_sets(template) -> list[str]:
+    """The rule sets TEMPLATE asks for, ``common`` aside."""
+    language = template.manifest.get("language", template.name.split("/")[0])
+    language = ALIASES.get(language, language)
+    names = [language] if language in available() else []
+    for name in template.manifest.get("gitignore", {}).get("rules", []):
+        if name not in available():
+            sys.exit(
+                f"Error: {template.name}: unknown gitignore rules {name} "
+                f"(one of {', '.join(available())})"
+            )
+        if name not in names:
+            names.append(name)
+    return names
+
+
+def _section(title: str, lines: list[str], seen: set[str]) -> list[str]:
+    """LINES under a ``# title`` banner, without patterns in SEEN."""
+    out = []
+    for line in lines:
+        pattern = line.strip()
+        if pattern and not pattern.startswith("#"):
+            if pattern in seen:
+                continue
+            seen.add(pattern)
+        out.append(line)
+    while out and not out[-1].strip():
+        out.pop()
+    if not any(line.strip() and not line.startswith("#") for line in out):
+        return []
+    return [f"# --- {title} ---", *out, ""]
+
+
+def generate(templates: list, variables: dict[str, str], own: str = "") -> str:
+    """The .gitignore for TEMPLATES (base first, then addons), ending in
+    OWN, the one the template shipped."""
+    names, entries = [], []
+    for template in templates:
+        names += [n for n in rule_sets(template) if n not in names]
+        entries += template.manifest.get("gitignore", {}).get("entries", [])
+    seen: set[str] = set()
+    lines = ["# Generated by jolo new; add project rules at the end.", ""]
+    for name in ["common", *names]:
+        text = (RULES_DIR / f"{name}.gitignore").read_text()
+        rules = placeholders.render(text, variables).splitlines()
+        lines += _section(name, rules, seen)
+    rendered = [placeholders.render(e, variables) for e in entries]
+    lines += _section("template", rendered, seen)
+    lines += _section("project", own.splitlines(), seen)
+    return "\n".join(lines).rstrip("\n") + "\n"
+
+
+def apply(
+    files: list[tuple[str, str | bytes]],
+    templates: list,
+    variables: dict[str, str],
+) -> list[tuple[str, str | bytes]]:
+    """FILES with the generated .gitignore, which takes in the one the
+    template shipped."""
+    if not any(rule_sets(t) or "gitignore" in t.manifest for t in templates):
+        return files
+    own = dict(files).get(FILE, "")
+    if not isinstance(own, str):
+        return files
+    text = generate(templates, variables, own)
+    if FILE not in dict(files):
+        return [*files, (FILE, text)]
+    return [(rel, text if rel == FILE else c) for rel, c in files]
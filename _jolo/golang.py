@@ -0,0 +1,204 @@
+"""Go specifics for ``jolo new``: module paths and the Go version.
+
+For a template with a go.mod, ``jolo new`` asks for ``MODULE_PATH`` (on
+a terminal, unless ``--var MODULE_PATH=...`` gives it) apart from the
+directory name, and checks it the way ``go mod`` checks a module path:
+slash-separated elements of ASCII letters, digits and ``-._~``, none
+empty or starting or ending with a dot, and a leading element that,
+when it is a domain, is lower case. The default, ``[new]
+module_prefix`` plus the project name, uses the name's kebab-case form
+when the name itself would not do (``My App`` -> ``my-app``).
+
+GO_VERSION is the newest ``[requires] go`` of the templates (1.21 when
+none says), for the go.mod ``go`` line and the build images that must
+match it; ``--var GO_VERSION=1.23`` overrides it.
+
+``--cmd NAME`` (again for more, kept as the GO_CMDS answer) lays the
+module out as ``cmd/<name>/main.go`` over ``internal/``: the template's
+root ``package main`` files, tests too, move to the first name's
+directory, each other name gets a starter main.go, and the task runner
+file gains ``build-<name>`` and ``run-<name>``. MAIN_PKG, what the
+templates' ``go run`` and ``go build`` lines name, is then
+``./cmd/<name>`` rather than ``.``. A main package that embeds files
+from the project root cannot move away from them, so such templates
+keep the flat layout.
+"""
+
+from __future__ import annotations
+
+import re
+
+from _jolo.placeholders import FILTERS
+from _jolo.tasks import Task
+
+_ELEMENT = re.compile(r"[A-Za-z0-9._~-]+")
+_DOMAIN = re.compile(r"[a-z0-9.-]+")
+_RESERVED = re.compile(r"(con|prn|aux|nul|com[1-9]|lpt[1-9])", re.I)
+DEFAULT_GO_VERSION = "1.21"
+_CMD = re.compile(r"[a-z][a-z0-9-]*")
+_MAIN = re.compile(r"^package main\b", re.M)
+_EMBED = re.compile(r"^//go:embed ", re.M)
+_STARTER = """\
+// Command {name} runs until interrupted; what it does belongs in
+// packages under internal/, which every command in cmd/ can share.
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {{
+	ctx, stop := signal.NotifyContext(
+		context.Background(), os.Interrupt, syscall.SIGTERM,
+	)
+	defer stop()
+
+	slog.Info("{name} started")
+	<-ctx.Done()
+	slog.Info("{name} stopped")
+}}
+"""
+
+
+def check_module_path(path: str) -> None:
+    """Raise ValueError saying why PATH is not a valid Go module path."""
+    if not path:
+        raise ValueError("empty module path")
+    if path.startswith("/") or path.endswith("/"):
+        raise ValueError(f"{path!r}: leading or trailing slash")
+    for element in path.split("/"):
+        if not element:
+            raise ValueError(f"{path!r}: empty path element")
+        if not _ELEMENT.fullmatch(element):
+            bad = next(c for c in element if not _ELEMENT.fullmatch(c))
+            raise ValueError(f"{path!r}: invalid character {bad!r}")
+        if element.startswith(".") or element.endswith("."):
+            raise ValueError(f"{path!r}: {element!r} starts or ends with .")
+        short = element.split(".")[0]
+        if _RESERVED.fullmatch(short) or re.search(r"~[0-9]+$", short):
+            raise ValueError(f"{path!r}: {element!r} is a reserved name")
+    first = path.split("/")[0]
+    if first.startswith("-"):
+        raise ValueError(f"{path!r}: leading dash")
+    if "." in first and not _DOMAIN.fullmatch(first):
+        raise ValueError(f"{path!r}: domain {first!r} must be lower case")
+
+
+def is_go(template) -> bool:
+    """Whether TEMPLATE makes a Go module (it, or a template it extends,
+    has a go.mod)."""
+    while template is not None:
+        if (template.path / "go.mod").is_file():
+            return True
+        template = getattr(template, "parent", None)
+    return False
+
+
+def _version_key(version: str) -> tuple[int, ...]:
+    return tuple(int(n) for n in re.findall(r"\d+", version))
+
+
+def go_version(templates: list) -> str:
+    """The newest ``[requires] go`` among TEMPLATES."""
+    versions = [
+        t.manifest.get("requires", {}).get("go", "") for t in templates
+    ]
+    return max(
+        filter(None, versions), key=_version_key, default=DEFAULT_GO_VERSION
+    )
+
+
+def is_module_path(path: str) -> bool:
+    try:
+        check_module_path(path)
+    except ValueError:
+        return False
+    return True
+
+
+def default_module_path(prefix: str, project_name: str) -> str:
+    """PREFIX/PROJECT_NAME, with the name kebab-cased if it is not a
+    valid path element as written."""
+    name = project_name
+    if not is_module_path(name):
+        name = FILTERS["kebab"](name) or name
+    return f"{prefix}/{name}" if prefix else name
+
+
+def check_cmd(name: str) -> None:
+    """Raise ValueError unless NAME will do as a ``cmd/`` directory."""
+    if not _CMD.fullmatch(name):
+        raise ValueError(
+            f"{name!r}: a command is a lower-case letter, then letters, "
+            "digits and -"
+        )
+
+
+def cmds(variables: dict[str, str]) -> list[str]:
+    """The GO_CMDS answer's commands, the first the template's own."""
+    return [c for c in variables.get("GO_CMDS", "").split(",") if c]
+
+
+def main_pkg(variables: dict[str, str]) -> str:
+    """The main package's path for ``go run`` and ``go build``."""
+    names = cmds(variables)
+    return f"./cmd/{names[0]}" if names else "."
+
+
+def cmd_path(rel: str, variables: dict[str, str]) -> str:
+    """REL, a root ``.go`` file of the main package, in its ``cmd/``
+    directory when there is one; other paths as they are."""
+    names = cmds(variables)
+    if not names or "/" in rel or not rel.endswith(".go"):
+        return rel
+    return f"cmd/{names[0]}/{rel}"
+
+
+def split_cmds(files: dict, variables: dict[str, str], air: bool) -> dict:
+    """FILES laid out for the GO_CMDS answer (see above), ignoring the
+    ``bin/`` the build tasks write and with an .air.toml building the
+    first command when AIR and there is none.
+    Raises ValueError when the main package embeds root files."""
+    names = cmds(variables)
+    if not names:
+        return files
+    laid = {}
+    for rel, content in files.items():
+        if isinstance(content, str) and _MAIN.search(content):
+            moved = cmd_path(rel, variables)
+            if moved != rel and _EMBED.search(content):
+                raise ValueError(
+                    f"{rel} embeds files from the project root, which "
+                    f"cmd/{names[0]}/ cannot reach; --cmd needs the flat "
+                    "layout's main package to embed nothing"
+                )
+            rel = moved
+        laid[rel] = content
+    for name in names:
+        laid.setdefault(f"cmd/{name}/main.go", _STARTER.format(name=name))
+    ignore = laid.get(".gitignore", "")
+    if isinstance(ignore, str) and "bin/" not in ignore.split():
+        laid[".gitignore"] = ignore + "bin/\n"
+    if air:
+        laid.setdefault(
+            ".air.toml",
+            f'[build]\n  cmd = "go build -o ./tmp/main ./cmd/{names[0]}"\n',
+        )
+    return laid
+
+
+def cmd_tasks(variables: dict[str, str]) -> dict[str, Task]:
+    """``build-<name>`` and ``run-<name>`` for each command."""
+    found = {}
+    for name in cmds(variables):
+        found[f"build-{name}"] = Task(
+            f"Build bin/{name}", [f"go build -o bin/{name} ./cmd/{name}"]
+        )
+        found[f"run-{name}"] = Task(
+            f"Run cmd/{name}", [f"go run ./cmd/{name}"]
+        )
+    return found
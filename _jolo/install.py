@@ -0,0 +1,183 @@
+"""Install a new project's dependencies once ``jolo new`` has written it.
+
+Manifests (base and addons) list the commands under ``[install] run``,
+rendered like hooks::
+
+    [install]
+    run = ["go mod tidy"]
+
+They run in the new directory after the post hooks and before the first
+commit, so lock files land in it. A template whose commands need a
+program that is not installed is skipped, and a failing command is a
+warning that stops the rest: the project is written either way. On a
+terminal each command shows a spinner and keeps its output unless it
+fails; a summary line says what ran. ``--no-install`` (or ``[new]
+install = false``) skips the step.
+"""
+
+from __future__ import annotations
+
+import itertools
+import re
+import shlex
+import shutil
+import subprocess
+import sys
+import threading
+import time
+from dataclasses import dataclass
+from pathlib import Path
+
+from _jolo import events, log, placeholders
+
+_FRAMES = "|/-\\"
+
+
+@dataclass
+class Step:
+    command: str
+    status: str = "pending"  # pending, ok, fail, skip
+    detail: str = ""
+    seconds: float = 0.0
+    output: str = ""  # all a failing command printed
+
+
+def commands(templates: list, variables: dict[str, str]) -> list[list[str]]:
+    """The install commands of each template that has any, rendered."""
+    return [
+        [placeholders.render(cmd, variables) for cmd in run]
+        for t in templates
+        if (run := t.manifest.get("install", {}).get("run", []))
+    ]
+
+
+# Words a command can start with before the program it runs.
+_SHELL_KEYWORDS = {"!", "{", "(", "if", "while", "until", "time", "exec"}
+# Builtins, there whatever is installed.
+_SHELL_BUILTINS = {"cd", "command", "echo", "exit", "export", "test", "["}
+_ASSIGNMENT = re.compile(r"[A-Za-z_][A-Za-z0-9_]*=")
+
+
+def program(cmd: str) -> str | None:
+    """The program CMD runs: its first word after any NAME=value
+    assignments and shell keywords (``GOOS=js go vet`` runs go)."""
+    for word in shlex.split(cmd):
+        if not (_ASSIGNMENT.match(word) or word in _SHELL_KEYWORDS):
+            return word
+    return None
+
+
+def missing_program(commands: list[str]) -> str | None:
+    """The first program COMMANDS run that is not installed."""
+    for cmd in commands:
+        name = program(cmd)
+        if name and name not in _SHELL_BUILTINS and not shutil.which(name):
+            return name
+    return None
+
+
+class Spinner:
+    """``| COMMAND`` spinning on stderr until stopped, on a terminal."""
+
+    def __init__(self, label: str):
+        self.label = label
+        self._done = threading.Event()
+        self._thread = threading.Thread(target=self._spin, daemon=True)
+
+    def _spin(self) -> None:
+        for frame in itertools.cycle(_FRAMES):
+            sys.stderr.write(f"\r{frame} {self.label}")
+            sys.stderr.flush()
+            if self._done.wait(0.1):
+                break
+        sys.stderr.write("\r\033[K")
+        sys.stderr.flush()
+
+    def __enter__(self) -> Spinner:
+        if log.quiet():
+            pass
+        elif log.color(sys.stderr):
+            self._thread.start()
+        else:
+            log.info(f"==> {self.label}")
+        return self
+
+    def __exit__(self, *exc) -> None:
+        self._done.set()
+        if self._thread.is_alive():
+            self._thread.join()
+
+
+def run_step(step: Step, cwd: Path) -> None:
+    """Run STEP's command in CWD, recording how it went in STEP."""
+    start = time.monotonic()
+    with Spinner(step.command):
+        result = subprocess.run(
+            step.command,
+            shell=True,
+            cwd=cwd,
+            capture_output=True,
+            text=True,
+        )
+    step.seconds = time.monotonic() - start
+    if result.returncode == 0:
+        step.status = "ok"
+        return
+    output = (result.stdout + result.stderr).strip()
+    tail = "\n".join(output.splitlines()[-20:])
+    step.output = output
+    step.status = "fail"
+    step.detail = f"exit {result.returncode}\n{tail}".rstrip()
+
+
+def run(groups: list[list[str]], cwd: Path) -> list[Step]:
+    """Run each group of COMMANDS in CWD, printing a line per command;
+    stop at the first failure."""
+    steps = []
+    failed = False
+    for group in groups:
+        missing = missing_program(group)
+        for cmd in group:
+            step = Step(cmd)
+            steps.append(step)
+            if missing:
+                step.status, step.detail = "skip", f"{missing} not installed"
+            elif not failed:
+                run_step(step, cwd)
+                failed = step.status == "fail"
+            print_step(step)
+            events.emit(
+                "install",
+                command=cmd,
+                status="not run" if step.status == "pending" else step.status,
+                seconds=round(step.seconds, 2),
+            )
+    print_summary(steps)
+    return steps
+
+
+def print_step(step: Step) -> None:
+    if step.status == "pending":
+        log.info(f"  -     {step.command}  (not run)")
+        return
+    line = f"  {step.status:<4}  {step.command}"
+    if step.status == "skip":
+        line += f"  ({step.detail})"
+    else:
+        line += f"  ({step.seconds:.1f}s)"
+    log.info(line)
+    if step.status == "fail":
+        for detail in step.detail.splitlines():
+            log.info(f"        {detail}")
+
+
+def print_summary(steps: list[Step]) -> None:
+    counts = {}
+    for step in steps:
+        status = "not run" if step.status == "pending" else step.status
+        counts[status] = counts.get(status, 0) + 1
+    log.info("Install: " + ", ".join(f"{n} {s}" for s, n in counts.items()))
+    if counts.get("fail"):
+        log.warning(
+            "install failed; the project is written, rerun the command in it"
+        )
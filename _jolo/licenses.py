@@ -0,0 +1,155 @@
+"""LICENSE files and SPDX identifiers for new projects.
+
+``jolo new --license apache-2.0`` (or the LICENSE variable, from
+``--var``, the ``[new] license`` config or a prompt) adds the license
+text as ``LICENSE``, with the year and AUTHOR filled in where the
+license has a copyright line, and records the SPDX identifier in the
+manifests that take one: a ``// SPDX-License-Identifier:`` comment
+atop go.mod, ``"license"`` in package.json, ``license`` in the
+pyproject.toml ``[project]`` and Cargo.toml ``[package]`` tables.
+
+Names match case-insensitively, with a few aliases (``gpl-3.0``,
+``apache``, ``bsd``). ``none`` adds nothing, as does a license jolo has
+no text for; a template that ships its own LICENSE or COPYING keeps it.
+"""
+
+from __future__ import annotations
+
+import json
+import re
+import sys
+from pathlib import PurePosixPath
+
+from _jolo import placeholders
+from _jolo.templates import _TEMPLATES_DIR
+
+LICENSES_DIR = _TEMPLATES_DIR / "licenses"
+NONE = "none"
+
+# SPDX id -> description for the picker; texts are LICENSES_DIR/<id>.txt
+LICENSES = {
+    "MIT": "MIT License",
+    "Apache-2.0": "Apache License 2.0",
+    "GPL-3.0-or-later": "GNU General Public License v3.0 or later",
+    "MPL-2.0": "Mozilla Public License 2.0",
+    "BSD-2-Clause": 'BSD 2-Clause "Simplified" License',
+    "BSD-3-Clause": 'BSD 3-Clause "New" or "Revised" License',
+    "ISC": "ISC License",
+    "Unlicense": "The Unlicense (public domain)",
+    NONE: "no license file",
+}
+ALIASES = {
+    "apache": "Apache-2.0",
+    "apache2": "Apache-2.0",
+    "gpl": "GPL-3.0-or-later",
+    "gpl-3.0": "GPL-3.0-or-later",
+    "gpl3": "GPL-3.0-or-later",
+    "mpl": "MPL-2.0",
+    "bsd": "BSD-3-Clause",
+}
+_OWN_LICENSE = ("LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING")
+
+
+def spdx(value: str) -> str | None:
+    """The SPDX id (or ``none``) VALUE names, or None if jolo has no
+    text for it."""
+    key = value.strip().lower()
+    for name in LICENSES:
+        if name.lower() == key:
+            return name
+    return ALIASES.get(key)
+
+
+def parse_license_arg(value: str) -> str:
+    """``--license VALUE`` as an SPDX id; exits on an unknown license."""
+    name = spdx(value)
+    if name is None:
+        sys.exit(
+            f"Error: Unknown license: {value} "
+            f"(one of {', '.join(n.lower() for n in LICENSES)})"
+        )
+    return name
+
+
+def license_text(name: str, variables: dict[str, str]) -> str:
+    return placeholders.render(
+        (LICENSES_DIR / f"{name}.txt").read_text(), variables
+    )
+
+
+def tag_go_mod(text: str, name: str) -> str:
+    if "SPDX-License-Identifier" in text:
+        return text
+    return f"// SPDX-License-Identifier: {name}\n{text}"
+
+
+def tag_package_json(text: str, name: str) -> str:
+    try:
+        data = json.loads(text)
+    except json.JSONDecodeError:
+        return text
+    if not isinstance(data, dict) or "license" in data:
+        return text
+    keys = list(data)
+    after = next((k for k in ("version", "name") if k in data), None)
+    at = keys.index(after) + 1 if after else 0
+    keys.insert(at, "license")
+    data["license"] = name
+    return json.dumps({k: data[k] for k in keys}, indent=2) + "\n"
+
+
+def tag_toml(text: str, table: str, name: str) -> str:
+    """TEXT with ``license = NAME`` in [TABLE], after its version (or
+    name) key, unless the table already has a license."""
+    lines = text.splitlines(keepends=True)
+    try:
+        start = next(
+            i for i, line in enumerate(lines) if line.strip() == f"[{table}]"
+        )
+    except StopIteration:
+        return text
+    end = next(
+        (
+            i
+            for i in range(start + 1, len(lines))
+            if lines[i].lstrip().startswith("[")
+        ),
+        len(lines),
+    )
+    body = range(start + 1, end)
+    if any(re.match(r"license\s*=", lines[i]) for i in body):
+        return text
+    at = start + 1
+    for key in ("name", "version"):
+        at = next(
+            (i + 1 for i in body if re.match(rf"{key}\s*=", lines[i])), at
+        )
+    lines.insert(at, f'license = "{name}"\n')
+    return "".join(lines)
+
+
+_TAGGERS = {
+    "go.mod": tag_go_mod,
+    "package.json": tag_package_json,
+    "pyproject.toml": lambda text, name: tag_toml(text, "project", name),
+    "Cargo.toml": lambda text, name: tag_toml(text, "package", name),
+}
+
+
+def apply(
+    files: list[tuple[str, str | bytes]], variables: dict[str, str]
+) -> list[tuple[str, str | bytes]]:
+    """FILES with the LICENSE variable's license text added and its SPDX
+    id in their manifests."""
+    name = spdx(variables.get("LICENSE", NONE))
+    if name in (None, NONE):
+        return files
+    out = []
+    for rel, content in files:
+        tagger = _TAGGERS.get(PurePosixPath(rel).name)
+        if tagger and isinstance(content, str):
+            content = tagger(content, name)
+        out.append((rel, content))
+    if not any(rel in _OWN_LICENSE for rel, _ in files):
+        out.append(("LICENSE", license_text(name, variables)))
+    return out
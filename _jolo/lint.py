@@ -0,0 +1,468 @@
+"""jolo lint — check templates for their authors' mistakes.
+
+``jolo lint go/web`` checks the named templates or template directories
+(``jolo lint`` alone, the one in the current directory; ``--all``,
+every template in the templates dirs, those only there to be extended
+included, and every addon). Each finding is printed as
+``path[:line]: message [check]``:
+
+- ``manifest``: a top-level key jolo does not know or a value of the
+  wrong type, a bad ``[variables]`` entry, or a manifest that does not
+  load (bad TOML, an unknown ``extends``)
+- ``undeclared``: a jolo-style placeholder (``{{DB_URL}}``, upper case)
+  no variable, secret, computed value or jolo itself supplies, or a
+  ``{{ partial }}`` with no file in ``_partials/``
+- ``unused``: a declared variable no file, path, manifest entry or
+  script mentions
+- ``unreachable``: a ``[conditions]`` entry no file matches, or whose
+  condition can never hold (an unknown variable, or a value that is not
+  among the choices)
+- ``tests``: a template with no test files, nor Rust or Zig tests in
+  its sources
+- ``binary``: a binary file no ``binary`` glob in the manifest names,
+  so it is passed through only because it looks binary
+
+A template's own files are checked, with the files of the templates
+it extends; a finding in a file several templates share is printed
+once. ``[lint] ignore = ["tests"]`` in a manifest turns checks off for
+that template. Lower-case placeholders are left alone, as rendering
+leaves them (just's ``{{args}}``). jolo lint exits 1 when it finds
+anything.
+"""
+
+from __future__ import annotations
+
+import re
+import sys
+import tomllib
+from dataclasses import dataclass
+from pathlib import Path
+
+from _jolo import placeholders, secret
+from _jolo.commands import load_config
+from _jolo.scaffold import (
+    ADDONS_ROOT,
+    CONFIG_VARIABLES,
+    MANIFEST_FILE,
+    Template,
+    _matches,
+    _template_sources,
+    abstract,
+    computed_variables,
+    find_layered,
+    is_binary,
+    load_template,
+    partials,
+    project_variables,
+    resolve_variables,
+    script_variables,
+    template_roots,
+)
+
+CHECKS = ("manifest", "undeclared", "unused", "unreachable", "tests", "binary")
+
+# Top-level manifest keys and the type each takes.
+KEYS = {
+    "description": str,
+    "version": str,
+    "tags": list,
+    "language": str,
+    "engine": str,
+    "script": str,
+    "extends": str,
+    "templates": list,
+    "needs": list,
+    "binary": list,
+    "patches": list,
+    "variables": dict,
+    "computed": dict,
+    "conditions": dict,
+    "editorconfig": dict,
+    "env": dict,
+    "gitignore": dict,
+    "hooks": dict,
+    "install": dict,
+    "verify": dict,
+    "modes": dict,
+    "readme": dict,
+    "requires": dict,
+    "secrets": dict,
+    "tasks": dict,
+    "validate": dict,
+    "lint": dict,
+}
+VARIABLE_KEYS = (
+    "type",
+    "prompt",
+    "default",
+    "description",
+    "choices",
+    "addons",
+    "when",
+    "required",
+    "pattern",
+    "min",
+    "max",
+    "message",
+)
+# The tables whose keys list commands.
+COMMANDS = {
+    "hooks": ("pre", "post", "git"),
+    "install": ("run",),
+    "verify": ("run",),
+}
+
+# What jolo supplies beyond project_variables and config_variables.
+JOLO_VARIABLES = {
+    "UUID",
+    "TODAY",
+    "ADDONS",
+    "TASK_RUNNER",
+    "GO_CMDS",
+    "GO_VERSION",
+    "MAIN_PKG",
+    "SECRET_SEED",
+}
+_JOLO_NAME = re.compile(r"[A-Z][A-Z0-9_]*")
+# Test files by the conventions of the languages jolo has templates for.
+TEST_FILES = [
+    "*_test.*",
+    "test_*",
+    "*.test.*",
+    "*_spec.*",
+    "*.spec.*",
+    "*Test.*",
+    "*Tests.*",
+    "*.tftest.hcl",
+]
+TEST_DIRS = ("test", "tests", "spec", "t")
+# Tests kept beside the code: Rust's #[test] and Zig's test blocks.
+INLINE_TESTS = re.compile(r'^\s*(#\[test\]|#\[cfg\(test\)\]|test ")', re.M)
+
+
+@dataclass(frozen=True)
+class Finding:
+    path: Path
+    check: str
+    message: str
+    line: int = 0
+
+    def __str__(self) -> str:
+        where = _show(self.path) + (f":{self.line}" if self.line else "")
+        return f"{where}: {self.message} [{self.check}]"
+
+
+def _show(path: Path) -> str:
+    """PATH relative to the current directory when it is below it."""
+    try:
+        return str(path.relative_to(Path.cwd()))
+    except ValueError:
+        return str(path)
+
+
+def check_manifest(manifest: dict) -> list[str]:
+    """What is wrong with MANIFEST's shape."""
+    problems = []
+    for key, value in manifest.items():
+        want = KEYS.get(key)
+        if want is None:
+            problems.append(f"unknown key {key}")
+        elif not isinstance(value, want):
+            name = {str: "a string", list: "a list", dict: "a table"}[want]
+            problems.append(f"{key} must be {name}")
+    for table, keys in COMMANDS.items():
+        value = manifest.get(table)
+        for key in keys if isinstance(value, dict) else ():
+            commands = value.get(key, [])
+            if not isinstance(commands, list) or not all(
+                isinstance(c, str) for c in commands
+            ):
+                problems.append(f"{table}.{key} must be a list of commands")
+    for name, spec in _table(manifest, "variables").items():
+        if not isinstance(spec, dict):
+            problems.append(f"variables.{name} must be a table")
+            continue
+        for key in spec:
+            if key not in VARIABLE_KEYS:
+                problems.append(f"variables.{name}: unknown key {key}")
+    for path, expr in _table(manifest, "conditions").items():
+        if not isinstance(expr, str):
+            problems.append(f"conditions.{path} must be a string")
+    ignored = _table(manifest, "lint").get("ignore", [])
+    for check in ignored if isinstance(ignored, list) else [ignored]:
+        if check not in CHECKS:
+            problems.append(f"lint.ignore: unknown check {check!r}")
+    return problems
+
+
+def _table(manifest: dict, key: str) -> dict:
+    value = manifest.get(key, {})
+    return value if isinstance(value, dict) else {}
+
+
+def _exits(call, *args):
+    """(CALL's result, None), or (None, its message) if it exits."""
+    try:
+        return call(*args), None
+    except SystemExit as e:
+        return None, str(e.code).removeprefix("Error: ")
+
+
+def known_variables(template: Template) -> tuple[dict[str, str], str | None]:
+    """The variables TEMPLATE renders with by default, and why they could
+    not all be worked out, if they could not."""
+    variables = project_variables("lint-app")
+    for name in (*CONFIG_VARIABLES, *JOLO_VARIABLES):
+        variables.setdefault(name, "")
+    answers, problem = _exits(
+        resolve_variables, template, {}, True, None, variables
+    )
+    if problem:
+        return variables, problem
+    variables.update(answers)
+    variables.update({n: "" for n in secret.declared([template])})
+    for derive in (computed_variables, script_variables):
+        derived, problem = _exits(derive, [template], variables)
+        if problem:
+            return variables, problem
+        variables.update(derived)
+    return variables, None
+
+
+def _condition_problem(
+    expr: str, template: Template, variables: dict[str, str]
+) -> str | None:
+    """Why EXPR can never hold, if it cannot."""
+    specs = template.variables
+    for clause in expr.split(" and "):
+        clause = clause.strip()
+        name, has, value = clause.partition(" has ")
+        op = " has " if has else ""
+        if not has:
+            for sign in ("==", "!="):
+                if sign in clause:
+                    name, value = clause.split(sign, 1)
+                    op = sign
+                    break
+            else:
+                name = clause.lstrip("!")
+        name, value = name.strip(), value.strip().strip("\"'")
+        if name not in variables:
+            return f"unknown variable {name}"
+        choices = specs.get(name, {}).get("choices")
+        if op in (" has ", "==") and choices and value not in choices:
+            return f"{value} is not one of {name}'s {', '.join(choices)}"
+    return None
+
+
+def is_test(rel: str) -> bool:
+    """Whether REL is a test file, by its name or a directory it is in."""
+    *dirs, name = rel.split("/")
+    return _matches(name, TEST_FILES) or any(d in TEST_DIRS for d in dirs)
+
+
+def lint_template(template: Template, addon: bool = False) -> list[Finding]:
+    """Every finding in TEMPLATE, bar the checks its manifest ignores."""
+    manifest_path = template.path / MANIFEST_FILE
+    findings = [
+        Finding(manifest_path, "manifest", p)
+        for p in check_manifest(template.manifest)
+    ]
+    _, problem = _exits(lambda: template.variables)
+    if problem:
+        findings.append(Finding(manifest_path, "manifest", problem))
+        return findings
+    ignored = _table(template.manifest, "lint").get("ignore", [])
+    sources = _template_sources(template)
+    texts = {}
+    for src, rel in sources:
+        data = src.read_bytes()
+        verbatim = _matches(rel, template.manifest.get("binary", []))
+        if is_binary(data):
+            if not verbatim:
+                message = "binary file no binary glob in the manifest names"
+                findings.append(Finding(src, "binary", message))
+        elif not verbatim:
+            texts[src] = (rel, data.decode())
+
+    variables, problem = known_variables(template)
+    if problem:
+        findings.append(Finding(manifest_path, "manifest", problem))
+    if not addon:
+        findings += _undeclared(template, texts, variables)
+    findings += _unused(template, texts)
+    findings += _unreachable(template, sources, variables)
+    if not (addon or abstract(template.name)):
+        rels = [rel for _, rel in sources]
+        inline = any(INLINE_TESTS.search(t) for _, t in texts.values())
+        if not (inline or any(is_test(rel) for rel in rels)):
+            findings.append(Finding(template.path, "tests", "no test files"))
+    return [f for f in findings if f.check not in ignored]
+
+
+def _undeclared(
+    template: Template,
+    texts: dict[Path, tuple[str, str]],
+    variables: dict[str, str],
+) -> list[Finding]:
+    found = partials(template)
+    helpers = {*placeholders.HELPERS, "partial"}
+    findings = []
+    for src, (rel, text) in texts.items():
+        for n, line in enumerate([rel, *text.splitlines()]):
+            for match in placeholders._PLACEHOLDER.finditer(line):
+                name = match["name"]
+                if name == "partial":
+                    include = placeholders._ARG.search(match["args"])
+                    if include and include[1] not in found:
+                        message = f"no partial {include[1]} in _partials/"
+                        findings.append(
+                            Finding(src, "undeclared", message, n)
+                        )
+                    continue
+                if name in variables or name in helpers:
+                    continue
+                if _JOLO_NAME.fullmatch(name):
+                    message = f"{{{{{name}}}}} is not a declared variable"
+                    findings.append(Finding(src, "undeclared", message, n))
+    return findings
+
+
+def _unused(
+    template: Template, texts: dict[Path, tuple[str, str]]
+) -> list[Finding]:
+    """The declared variables nothing mentions. A multi variable with
+    ``addons`` is used by picking them."""
+    corpus = [rel + "\n" + text for rel, text in texts.values()]
+    script = template.manifest.get("script")
+    if script and (template.path / script).is_file():
+        corpus.append((template.path / script).read_text())
+    findings = []
+    for name, spec in template.variables.items():
+        if "addons" in spec or name == "features":
+            continue
+        rest = {
+            **template.manifest,
+            "variables": {
+                k: v for k, v in template.variables.items() if k != name
+            },
+        }
+        word = re.compile(rf"(?<![\w-]){re.escape(name)}(?![\w-])")
+        if not any(word.search(text) for text in [*corpus, repr(rest)]):
+            findings.append(
+                Finding(
+                    template.path / MANIFEST_FILE,
+                    "unused",
+                    f"variable {name} is never used",
+                )
+            )
+    return findings
+
+
+def _unreachable(
+    template: Template,
+    sources: list[tuple[Path, str]],
+    variables: dict[str, str],
+) -> list[Finding]:
+    manifest_path = template.path / MANIFEST_FILE
+    findings = []
+    rels = [rel for _, rel in sources]
+    for pattern, expr in _table(template.manifest, "conditions").items():
+        if not isinstance(expr, str):
+            continue
+        where = f"conditions.{pattern!r}"
+        if not any(
+            rel == pattern or pattern.endswith("/") and rel.startswith(pattern)
+            for rel in rels
+        ):
+            message = f"{where} matches no file"
+            findings.append(Finding(manifest_path, "unreachable", message))
+        problem = _condition_problem(expr, template, variables)
+        if problem:
+            message = f"{where} can never hold: {problem}"
+            findings.append(Finding(manifest_path, "unreachable", message))
+    return findings
+
+
+def _all_templates(roots: list[Path]) -> list[tuple[Template, bool]]:
+    """Every template in ROOTS (the first of a name wins), abstract ones
+    included, then every builtin addon; with whether each is an addon."""
+    found = {}
+    for root in roots:
+        for manifest in sorted(root.rglob(MANIFEST_FILE)):
+            name = manifest.parent.relative_to(root).as_posix()
+            if name not in found:
+                found[name] = (manifest.parent, root)
+    templates = [
+        (_load(path, name, root), False)
+        for name, (path, root) in sorted(found.items())
+    ]
+    addons = [
+        (_load(m.parent, m.parent.relative_to(ADDONS_ROOT).as_posix()), True)
+        for m in sorted(ADDONS_ROOT.rglob(MANIFEST_FILE))
+    ]
+    return templates + addons
+
+
+def _load(path: Path, name: str, root: Path | None = None):
+    """The template at PATH, or the Finding that it does not load."""
+    try:
+        return load_template(path, name, root)
+    except tomllib.TOMLDecodeError as e:
+        return Finding(path / MANIFEST_FILE, "manifest", str(e))
+    except SystemExit as e:
+        message = str(e.code).removeprefix("Error: ")
+        return Finding(path / MANIFEST_FILE, "manifest", message)
+
+
+def _at(path: Path, roots: list[Path]) -> tuple[Template | Finding, bool]:
+    """The template in directory PATH, named from the templates dir it
+    is in (if none of ROOTS, the first one up that is not a template);
+    with whether it is an addon."""
+    path = path.resolve()
+    for root in [*roots, ADDONS_ROOT]:
+        if path.is_relative_to(root) and path != root:
+            name = path.relative_to(root).as_posix()
+            return _load(path, name, root), root == ADDONS_ROOT
+    root = path.parent
+    while (root / MANIFEST_FILE).is_file():
+        root = root.parent
+    return _load(path, path.relative_to(root).as_posix(), root), False
+
+
+def run_lint_mode(args) -> None:
+    """Lint the named templates, the current directory's, or --all."""
+    new_cfg = load_config().get("new", {})
+    roots = template_roots(args.templates_dir, new_cfg)
+    if args.all:
+        targets = _all_templates(roots)
+    elif args.templates:
+        targets = []
+        for name in args.templates:
+            if (Path(name) / MANIFEST_FILE).is_file():
+                targets.append(_at(Path(name), roots))
+            else:
+                targets.append((find_layered(name, roots)[0], False))
+    elif (Path.cwd() / MANIFEST_FILE).is_file():
+        targets = [_at(Path.cwd(), roots)]
+    else:
+        sys.exit(
+            f"Error: no {MANIFEST_FILE} here; name templates to lint, "
+            "or --all"
+        )
+
+    findings = []
+    for target, addon in targets:
+        if isinstance(target, Finding):
+            findings.append(target)
+        else:
+            findings += lint_template(target, addon)
+    findings = list(dict.fromkeys(findings))
+    for finding in findings:
+        print(finding)
+    count = len(targets)
+    noun = "template" if count == 1 else "templates"
+    if findings:
+        sys.exit(f"{len(findings)} findings in {count} {noun}")
+    print(f"{count} {noun}, no findings")
+
@@ -0,0 +1,123 @@
+"""jolo's messages: verbosity, colour, and the summary a command ends with.
+
+Progress, warnings and debug output are records on the ``jolo`` logger
+(the standard library's ``logging``) and go to stderr. A record carries
+fields as well as a message, ``log.debug("wrote", path=rel)``, shown
+as ``wrote path=rel``. ``-v`` adds the debug records (the commands jolo
+runs, each file it writes); ``-q`` leaves warnings and errors only.
+
+A command's summary (``Created go/web project: shop``) is not a log
+record: ``summary`` prints it to stdout, so ``2>/dev/null`` keeps it
+and drops everything else; ``-q`` drops it too.
+
+On a terminal, warnings, errors and debug lines are coloured, unless
+``$NO_COLOR`` is set to anything, ``$TERM`` is ``dumb`` or ``--plain``
+is given. ``--plain`` is for CI logs and other programs reading jolo's
+output: no colour and no spinner, wherever stderr goes.
+"""
+
+from __future__ import annotations
+
+import logging
+import os
+import sys
+
+logger = logging.getLogger("jolo")
+logger.propagate = False
+
+_PREFIX = {
+    logging.DEBUG: "[verbose] ",
+    logging.WARNING: "Warning: ",
+    logging.ERROR: "Error: ",
+}
+_COLOR = {logging.DEBUG: "2", logging.WARNING: "33", logging.ERROR: "31"}
+
+_plain = False
+
+
+class _Handler(logging.Handler):
+    """Writes to whatever sys.stderr is at the time, so redirecting it
+    (``--json``, tests) redirects the log."""
+
+    def emit(self, record: logging.LogRecord) -> None:
+        try:
+            line = self.format(record)
+            stream = sys.stderr
+            code = _COLOR.get(record.levelno)
+            if code and color(stream):
+                line = f"\033[{code}m{line}\033[0m"
+            stream.write(line + "\n")
+            stream.flush()
+        except Exception:
+            self.handleError(record)
+
+
+class _Formatter(logging.Formatter):
+    def format(self, record: logging.LogRecord) -> str:
+        text = record.getMessage()
+        fields = getattr(record, "fields", None)
+        if fields:
+            text += "".join(f" {k}={_value(v)}" for k, v in fields.items())
+        return _PREFIX.get(record.levelno, "") + text
+
+
+def _value(value) -> str:
+    text = str(value)
+    if not text or any(c.isspace() or c in "\"=" for c in text):
+        return '"' + text.replace("\\", "\\\\").replace('"', '\\"') + '"'
+    return text
+
+
+_handler = _Handler()
+_handler.setFormatter(_Formatter())
+logger.addHandler(_handler)
+logger.setLevel(logging.INFO)
+
+
+def setup(verbosity: int = 0, plain: bool = False) -> None:
+    """-1 for -q (warnings and errors), 0, or 1 for -v (debug too)."""
+    global _plain
+    _plain = plain
+    if verbosity > 0:
+        logger.setLevel(logging.DEBUG)
+    elif verbosity < 0:
+        logger.setLevel(logging.WARNING)
+    else:
+        logger.setLevel(logging.INFO)
+
+
+def color(stream) -> bool:
+    """Whether to colour (or animate, as the install spinner does) what
+    goes to STREAM."""
+    if _plain or os.environ.get("NO_COLOR"):
+        return False
+    if os.environ.get("TERM") == "dumb":
+        return False
+    isatty = getattr(stream, "isatty", None)
+    return bool(isatty and isatty())
+
+
+def quiet() -> bool:
+    return not logger.isEnabledFor(logging.INFO)
+
+
+def debug(msg: str, **fields) -> None:
+    logger.debug(msg, extra={"fields": fields})
+
+
+def info(msg: str, **fields) -> None:
+    logger.info(msg, extra={"fields": fields})
+
+
+def warning(msg: str, **fields) -> None:
+    logger.warning(msg, extra={"fields": fields})
+
+
+def error(msg: str, **fields) -> None:
+    logger.error(msg, extra={"fields": fields})
+
+
+def summary(line: str) -> None:
+    """Print LINE, part of what a command did, to stdout unless -q."""
+    if not quiet():
+        print(line)
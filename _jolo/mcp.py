@@ -0,0 +1,242 @@
+"""jolo mcp — the scaffolding API as a Model Context Protocol server.
+
+Speaks JSON-RPC 2.0 over stdio, one message per line, so an agent can
+be configured with ``{"command": "jolo", "args": ["mcp"]}``. Tools:
+
+    list_templates      names, descriptions and tags (optional query)
+    describe_template   one template's variables and addons
+    generate_project    render a template into WORKSPACE/NAME
+
+Every tool returns its result both as ``structuredContent`` and as JSON
+text. Projects are only written below the workspace (``--workspace``,
+default the current directory); hooks run only when asked for.
+"""
+
+from __future__ import annotations
+
+import json
+import os
+import sys
+from pathlib import Path
+
+from _jolo import api, catalog, scaffold
+from _jolo.commands import load_config
+
+PROTOCOL_VERSION = "2025-06-18"
+
+_STRINGS = {"type": "array", "items": {"type": "string"}}
+TOOLS = [
+    {
+        "name": "list_templates",
+        "description": (
+            "List the project templates jolo can generate, best match "
+            "first when a query is given."
+        ),
+        "inputSchema": {
+            "type": "object",
+            "properties": {
+                "query": {
+                    "type": "string",
+                    "description": "Keywords, e.g. 'go htmx'",
+                },
+                "tag": {"type": "string"},
+            },
+        },
+    },
+    {
+        "name": "describe_template",
+        "description": (
+            "Show a template's description, tags, variables (type, "
+            "default, choices) and the addons it accepts."
+        ),
+        "inputSchema": {
+            "type": "object",
+            "properties": {"template": {"type": "string"}},
+            "required": ["template"],
+        },
+    },
+    {
+        "name": "generate_project",
+        "description": (
+            "Generate a new project from a template into the workspace. "
+            "Variables not given take their defaults."
+        ),
+        "inputSchema": {
+            "type": "object",
+            "properties": {
+                "template": {
+                    "type": "string",
+                    "description": "Template name or git source",
+                },
+                "name": {
+                    "type": "string",
+                    "description": "Project directory, relative to the "
+                    "workspace",
+                },
+                "variables": {"type": "object"},
+                "addons": _STRINGS,
+                "hooks": {
+                    "type": "boolean",
+                    "description": "Run the template's setup commands",
+                },
+                "overwrite": {
+                    "type": "boolean",
+                    "description": "Write into a non-empty directory",
+                },
+            },
+            "required": ["template", "name"],
+        },
+    },
+]
+
+
+class ToolError(Exception):
+    """A tool call failed; reported to the agent as an isError result."""
+
+
+class Server:
+    def __init__(self, workspace: Path, templates_dirs: list[str]):
+        self.workspace = workspace
+        self.templates_dirs = templates_dirs
+
+    def list_templates(self, query: str = "", tag: str = ""):
+        roots = scaffold.template_roots(self.templates_dirs, {})
+        entries = catalog.catalog(roots, [])
+        if tag:
+            entries = [e for e in entries if tag in e.tags]
+        if query:
+            entries = catalog.search(entries, query)
+        return {
+            "templates": [
+                {"name": e.name, "description": e.description, "tags": e.tags}
+                for e in entries
+            ]
+        }
+
+    def describe_template(self, template: str):
+        with api.open_template(template, self.templates_dirs) as t:
+            return api.describe(t)
+
+    def generate_project(
+        self,
+        template: str,
+        name: str,
+        variables: dict | None = None,
+        addons: list[str] = (),
+        hooks: bool = False,
+        overwrite: bool = False,
+    ):
+        dest = (self.workspace / name).resolve()
+        if not dest.is_relative_to(self.workspace) or dest == self.workspace:
+            raise ToolError(f"{name} is outside the workspace")
+        with api.open_template(template, self.templates_dirs) as t:
+            files = api.generate(
+                t, dest, variables, addons, hooks=hooks, overwrite=overwrite
+            )
+        return {
+            "template": template,
+            "path": str(dest),
+            "files": sorted([*files, scaffold.LOCK_FILE]),
+        }
+
+    def call_tool(self, name: str, arguments: dict) -> dict:
+        tool = {t["name"] for t in TOOLS}
+        if name not in tool:
+            raise KeyError(name)
+        try:
+            result = getattr(self, name)(**arguments)
+        except TypeError as e:
+            return _tool_error(f"bad arguments: {e}")
+        except (api.ScaffoldError, ToolError) as e:
+            return _tool_error(str(e))
+        return {
+            "content": [{"type": "text", "text": json.dumps(result)}],
+            "structuredContent": result,
+            "isError": False,
+        }
+
+    def handle(self, message: dict) -> dict | None:
+        """The response to one JSON-RPC MESSAGE (None for notifications)."""
+        if not isinstance(message, dict):
+            return _error({}, -32600, "invalid request")
+        method = message.get("method")
+        params = message.get("params") or {}
+        if "id" not in message:
+            return None
+        try:
+            if method == "initialize":
+                result = {
+                    "protocolVersion": params.get(
+                        "protocolVersion", PROTOCOL_VERSION
+                    ),
+                    "capabilities": {"tools": {}},
+                    "serverInfo": {"name": "jolo", "version": "1"},
+                }
+            elif method == "ping":
+                result = {}
+            elif method == "tools/list":
+                result = {"tools": TOOLS}
+            elif method == "tools/call":
+                try:
+                    result = self.call_tool(
+                        params["name"], params.get("arguments") or {}
+                    )
+                except KeyError as e:
+                    return _error(message, -32602, f"unknown tool: {e}")
+            else:
+                return _error(message, -32601, f"unknown method: {method}")
+        except Exception as e:  # keep serving whatever one call did
+            return _error(message, -32603, str(e))
+        return {"jsonrpc": "2.0", "id": message["id"], "result": result}
+
+    def serve(self, stdin, stdout) -> None:
+        for line in stdin:
+            if not line.strip():
+                continue
+            try:
+                message = json.loads(line)
+            except json.JSONDecodeError as e:
+                response = _error({"id": None}, -32700, f"parse error: {e}")
+            else:
+                response = self.handle(message)
+            if response is not None:
+                stdout.write(json.dumps(response) + "\n")
+                stdout.flush()
+
+
+def _tool_error(text: str) -> dict:
+    return {"content": [{"type": "text", "text": text}], "isError": True}
+
+
+def _error(message: dict, code: int, text: str) -> dict:
+    return {
+        "jsonrpc": "2.0",
+        "id": message.get("id"),
+        "error": {"code": code, "message": text},
+    }
+
+
+def protocol_stdout():
+    """The real stdout, for protocol messages; everything else printing
+    to stdout, hooks included, goes to stderr from here on."""
+    # Hooks and jolo's own messages go to stderr: stdout (the file
+    # descriptor, which hook processes inherit) carries protocol only.
+    sys.stdout.flush()
+    protocol = os.fdopen(os.dup(1), "w")
+    os.dup2(2, 1)
+    sys.stdout = sys.stderr
+    return protocol
+
+
+def run_mcp_mode(args) -> None:
+    """Serve the MCP tools on stdin/stdout until EOF."""
+    new_cfg = load_config().get("new", {})
+    templates_dirs = [
+        *args.templates_dir,
+        *new_cfg.get("templates_dirs", []),
+    ]
+    scaffold.template_roots(templates_dirs, {})  # exits on a missing dir
+    workspace = Path(args.workspace or Path.cwd()).expanduser().resolve()
+    if not workspace.is_dir():
+        sys.exit(f"Error: Workspace not found: {workspace}")
+    Server(workspace, templates_dirs).serve(sys.stdin, protocol_stdout())
@@ -0,0 +1,188 @@
+"""``jolo new`` into a subdirectory of an existing repository.
+
+When the new project lands inside a git work tree (``jolo new go
+services/api`` from a repo root) it is one module of a monorepo: no
+repository of its own is made, a Go module is added to the ``go.work``
+at the root (one is created, using every module already there, when the
+repo has none), and ``--register`` adds a task to the root Taskfile or
+Makefile that runs the project's own ``just`` recipes (or ``make`` or
+``task`` tasks, as ``--tasks`` picked)::
+
+    task api -- test        # Taskfile: cd services/api && just test
+    make api/test           # Makefile: the same
+
+The root is the nearest directory above the project with a go.work, or
+else the top of the work tree.
+"""
+
+from __future__ import annotations
+
+import re
+import subprocess
+import sys
+from pathlib import Path
+
+GO_WORK = "go.work"
+TASKFILES = ("Taskfile.yml", "Taskfile.yaml")
+MAKEFILE = "Makefile"
+_SKIP = {"node_modules", "vendor", "testdata"}
+_USE_BLOCK = re.compile(r"^use\s*\(\n(?P<body>.*?)^\)", re.M | re.S)
+_USE_LINE = re.compile(r"^use\s+(?P<dir>[^\s(]\S*)", re.M)
+_GO_VERSION = re.compile(r"^go\s+(\S+)", re.M)
+
+
+def find_root(target: Path) -> Path | None:
+    """The monorepo root TARGET would be written into, or None when it
+    is not inside a repository."""
+    start = target.parent
+    while not start.exists():
+        start = start.parent
+    for parent in (start, *start.parents):
+        if (parent / GO_WORK).is_file():
+            return parent
+    result = subprocess.run(
+        ["git", "rev-parse", "--show-toplevel"],
+        cwd=start,
+        capture_output=True,
+        text=True,
+    )
+    if result.returncode != 0:
+        return None
+    return Path(result.stdout.strip()).resolve()
+
+
+def _rel(root: Path, module: Path) -> str:
+    rel = module.relative_to(root).as_posix()
+    return "." if rel == "." else f"./{rel}"
+
+
+def _clean(rel: str) -> str:
+    return rel.rstrip("/").removeprefix("./") or "."
+
+
+def used_modules(text: str) -> list[str]:
+    """The directories a go.work uses, as written."""
+    dirs = [m["dir"] for m in _USE_LINE.finditer(text)]
+    for block in _USE_BLOCK.finditer(text):
+        for line in block["body"].splitlines():
+            line = line.split("//")[0].strip()
+            if line:
+                dirs.append(line)
+    return dirs
+
+
+def go_modules(root: Path) -> list[Path]:
+    """Directories below ROOT with a go.mod, skipping hidden ones and
+    vendored or test data."""
+    return sorted(
+        mod.parent
+        for mod in root.rglob("go.mod")
+        if not any(
+            part.startswith(".") or part in _SKIP
+            for part in mod.relative_to(root).parts[:-1]
+        )
+    )
+
+
+def add_to_go_work(root: Path, module: Path) -> str | None:
+    """Add MODULE to ROOT/go.work, creating it if needed. Returns what
+    was done, or None when the module was already used."""
+    work = root / GO_WORK
+    rel = _rel(root, module)
+    if not work.exists():
+        found = (module / "go.mod").read_text(encoding="utf-8")
+        version = _GO_VERSION.search(found)
+        uses = [_rel(root, m) for m in go_modules(root)]
+        if rel not in uses:
+            uses.append(rel)
+        lines = "".join(f"\t{u}\n" for u in uses)
+        go = version[1] if version else "1.21"
+        work.write_text(f"go {go}\n\nuse (\n{lines})\n", encoding="utf-8")
+        return f"created {work} using {', '.join(uses)}"
+    text = work.read_text(encoding="utf-8")
+    if _clean(rel) in {_clean(d) for d in used_modules(text)}:
+        return None
+    block = _USE_BLOCK.search(text)
+    if block:
+        end = block.end() - 1
+        text = f"{text[:end]}\t{rel}\n{text[end:]}"
+    else:
+        text = f"{text.rstrip()}\n\nuse {rel}\n"
+    work.write_text(text, encoding="utf-8")
+    return f"added {rel} to {work}"
+
+
+def registration(
+    root: Path, module: Path, runner: str = "just"
+) -> tuple[Path, str]:
+    """The Taskfile or Makefile at ROOT and its text with a task for
+    MODULE added, running MODULE's RUNNER (just, make or task) tasks.
+    Exits when ROOT has neither, or already has a task of that name, so
+    it can be checked before anything is written."""
+    name = module.name
+    rel = module.relative_to(root).as_posix()
+    for filename in (*TASKFILES, MAKEFILE):
+        path = root / filename
+        if path.is_file():
+            add = _add_make if filename == MAKEFILE else _add_task
+            return path, add(path, name, rel, runner)
+    sys.exit(
+        f"Error: --register: no {', '.join(TASKFILES)} or {MAKEFILE} "
+        f"in {root}"
+    )
+
+
+# What a module's tasks are called, by the runner that has them.
+_WHAT = {"just": "just recipes", "make": "make targets", "task": "tasks"}
+
+
+def _add_task(path: Path, name: str, rel: str, runner: str) -> str:
+    lines = path.read_text(encoding="utf-8").splitlines()
+    start = next(
+        (i for i, line in enumerate(lines) if re.match(r"tasks:\s*$", line)),
+        None,
+    )
+    if start is None:
+        lines += ["", "tasks:"]
+        start = len(lines) - 1
+    end = next(
+        (
+            i
+            for i in range(start + 1, len(lines))
+            if lines[i] and not lines[i][0].isspace() and lines[i][0] != "#"
+        ),
+        len(lines),
+    )
+    children = [line for line in lines[start + 1 : end] if line.strip()]
+    indent = "  "
+    if children:
+        indent = children[0][: len(children[0]) - len(children[0].lstrip())]
+    if any(re.match(rf"{indent}{re.escape(name)}:", c) for c in children):
+        sys.exit(f"Error: --register: {path} already has a task {name}")
+    while end > start + 1 and not lines[end - 1].strip():
+        end -= 1
+    block = [
+        f"{indent}{name}:",
+        f"{indent * 2}desc: {_WHAT[runner]} of {rel} "
+        f"(task {name} -- RECIPE)",
+        f"{indent * 2}dir: {rel}",
+        f"{indent * 2}cmds:",
+        f"{indent * 3}- {runner} {{{{.CLI_ARGS}}}}",
+    ]
+    lines[end:end] = block
+    return "\n".join(lines) + "\n"
+
+
+def _add_make(path: Path, name: str, rel: str, runner: str) -> str:
+    text = path.read_text(encoding="utf-8")
+    if re.search(rf"^{re.escape(name)}(/%)?\s*:", text, re.M):
+        sys.exit(f"Error: --register: {path} already has a target {name}")
+    block = (
+        f"\n# {_WHAT[runner]} of {rel}: make {name}/RECIPE\n"
+        f".PHONY: {name}\n"
+        f"{name}:\n"
+        f"\tcd {rel} && {runner}\n"
+        f"{name}/%:\n"
+        f"\tcd {rel} && {runner} $*\n"
+    )
+    return text.rstrip("\n") + "\n" + block
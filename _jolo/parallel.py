@@ -0,0 +1,46 @@
+"""A bounded thread pool for the file work of rendering a project.
+
+Reading template files, rendering them and writing the results is
+mostly waiting on the filesystem, which threads overlap well; on a
+network filesystem a template of a few hundred files is several times
+faster this way. ``--jobs N`` (or ``$JOLO_JOBS``) caps the threads;
+``--jobs 1`` does everything in order on the calling thread.
+"""
+
+from __future__ import annotations
+
+import os
+from collections.abc import Callable, Iterable
+from concurrent.futures import ThreadPoolExecutor
+from typing import TypeVar
+
+from _jolo import constants
+
+T = TypeVar("T")
+R = TypeVar("R")
+
+# Below this many items starting threads costs more than it saves.
+MIN_ITEMS = 16
+
+
+def jobs() -> int:
+    """How many threads to use, at least 1."""
+    if constants.JOBS:
+        return max(constants.JOBS, 1)
+    try:
+        return max(int(os.environ.get("JOLO_JOBS", "")), 1)
+    except ValueError:
+        return min(32, (os.cpu_count() or 1) + 4)
+
+
+def pmap(fn: Callable[[T], R], items: Iterable[T]) -> list[R]:
+    """``[fn(x) for x in items]`` on up to ``jobs()`` threads. The first
+    item (in order) to raise, SystemExit included, raises here once the
+    rest are done."""
+    items = list(items)
+    workers = min(jobs(), len(items))
+    if workers <= 1 or len(items) < MIN_ITEMS:
+        return [fn(x) for x in items]
+    with ThreadPoolExecutor(max_workers=workers) as pool:
+        futures = [pool.submit(fn, x) for x in items]
+    return [f.result() for f in futures]
@@ -0,0 +1,169 @@
+"""Placeholder substitution for `jolo new` templates.
+
+A placeholder is ``{{ EXPR | filter | ... }}`` where EXPR is a variable
+name or a helper call with quoted (or integer) arguments:
+
+    {{PROJECT_NAME | pascal}}      MyApp
+    {{ date "%Y" }}               2026 (or the year of a TODAY variable)
+    {{ env "EDITOR" "vi" }}       $EDITOR, or vi when unset
+    {{ uuid }}                    the project's UUID
+    {{ uuid "db" | upper }}       a second stable UUID, derived from it
+    {{ secret 32 "base64" }}      32 random bytes (see ``_jolo.secret``)
+    {{ partial "add.just" }}      a file from the template's _partials/
+
+Variables win over helpers of the same name. A placeholder whose
+variable, helper, or filter is unknown is left exactly as written, so
+just's ``{{args}}``, Jinja's ``{{ title }}`` and Go's ``{{ .Name }}``
+survive rendering.
+"""
+
+from __future__ import annotations
+
+import datetime
+import os
+import re
+import uuid
+from collections.abc import Callable, Iterable, Iterator
+
+from _jolo import secret
+
+_PLACEHOLDER = re.compile(
+    r"\{\{\s*(?P<name>[A-Za-z_]\w*)"
+    r"(?P<args>(?:\s+(?:\"[^\"]*\"|\d+))*)"
+    r"(?P<filters>(?:\s*\|\s*[A-Za-z_]\w*)*)\s*\}\}"
+)
+_ARG = re.compile(r"\"([^\"]*)\"|(\d+)")
+
+
+def words(text: str) -> list[str]:
+    """Split TEXT at case changes and non-alphanumerics:
+    ``myHTTPServer-v2`` -> my, HTTP, Server, v2."""
+    text = re.sub(r"([a-z0-9])([A-Z])", r"\1 \2", text)
+    text = re.sub(r"([A-Z]+)([A-Z][a-z])", r"\1 \2", text)
+    return re.findall(r"[A-Za-z0-9]+", text)
+
+
+def _pascal(text: str) -> str:
+    return "".join(w[:1].upper() + w[1:].lower() for w in words(text))
+
+
+def _camel(text: str) -> str:
+    pascal = _pascal(text)
+    return pascal[:1].lower() + pascal[1:]
+
+
+FILTERS = {
+    "upper": str.upper,
+    "lower": str.lower,
+    "title": lambda s: " ".join(w.capitalize() for w in words(s)),
+    "snake": lambda s: "_".join(words(s)).lower(),
+    "kebab": lambda s: "-".join(words(s)).lower(),
+    "pascal": _pascal,
+    "camel": _camel,
+}
+
+
+def _date(variables: dict[str, str], fmt: str = "%Y-%m-%d") -> str:
+    """Today, or the ISO date in a ``TODAY`` variable (for snapshots)."""
+    if variables.get("TODAY"):
+        day = datetime.date.fromisoformat(variables["TODAY"])
+    else:
+        day = datetime.date.today()
+    return day.strftime(fmt)
+
+
+def _env(variables: dict[str, str], name: str, default: str = "") -> str:
+    return os.environ.get(name, default)
+
+
+def _uuid(variables: dict[str, str], key: str = "") -> str:
+    """The project's UUID (the ``UUID`` variable), or one derived from
+    it and KEY, so re-rendering for ``jolo upgrade`` gives the same ids."""
+    project = variables.get("UUID")
+    if not project:
+        return str(uuid.uuid4())
+    if not key:
+        return project
+    return str(uuid.uuid5(uuid.UUID(project), key))
+
+
+def _secret(
+    variables: dict[str, str], nbytes: str = "32", encoding: str = "hex"
+) -> str:
+    """NBYTES random bytes; derived from a ``SECRET_SEED`` variable if
+    there is one, so snapshots and upgrades render the same value."""
+    if not nbytes.isdigit() or encoding not in secret.ENCODINGS:
+        raise TypeError(f"secret {nbytes} {encoding}")
+    seed = variables.get("SECRET_SEED", "")
+    return secret.token(int(nbytes), encoding, seed, f"{nbytes} {encoding}")
+
+
+HELPERS = {"date": _date, "env": _env, "secret": _secret, "uuid": _uuid}
+
+
+# The longest placeholder render_chunks is sure to see whole.
+MAX_PLACEHOLDER = 4096
+
+
+def _replacer(
+    variables: dict[str, str], helpers: dict | None = None
+) -> Callable[[re.Match], str]:
+    helpers = {**HELPERS, **(helpers or {})}
+
+    def replace(match: re.Match) -> str:
+        name = match["name"]
+        args = [q or n for q, n in _ARG.findall(match["args"])]
+        if name in variables and not args:
+            value = variables[name]
+        elif name in helpers:
+            try:
+                value = helpers[name](variables, *args)
+            except TypeError:
+                return match[0]
+        else:
+            return match[0]
+        for f in match["filters"].split("|")[1:]:
+            f = f.strip()
+            if f not in FILTERS:
+                return match[0]
+            value = FILTERS[f](value)
+        return value
+
+    return replace
+
+
+def render(
+    text: str, variables: dict[str, str], helpers: dict | None = None
+) -> str:
+    """Substitute every placeholder in TEXT that can be resolved, with
+    HELPERS on top of the builtin ones."""
+    return _PLACEHOLDER.sub(_replacer(variables, helpers), text)
+
+
+def render_chunks(
+    chunks: Iterable[str], variables: dict[str, str]
+) -> Iterator[str]:
+    """``render`` for text that arrives in CHUNKS, holding back only the
+    last MAX_PLACEHOLDER characters, so a placeholder split between
+    chunks is still seen whole."""
+    replace = _replacer(variables)
+    buf = ""
+    for chunk in chunks:
+        buf += chunk
+        # A match starting before LIMIT ends inside BUF; later ones may
+        # still grow, so they wait for the next chunk.
+        limit = len(buf) - MAX_PLACEHOLDER
+        if limit <= 0:
+            continue
+        out = []
+        at = 0
+        for match in _PLACEHOLDER.finditer(buf):
+            if match.start() >= limit:
+                break
+            out += [buf[at : match.start()], replace(match)]
+            at = match.end()
+        cut = max(at, limit)
+        out.append(buf[at:cut])
+        yield "".join(out)
+        buf = buf[cut:]
+    yield render(buf, variables)
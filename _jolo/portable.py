@@ -0,0 +1,124 @@
+"""Projects that also work on Windows: line endings, paths and symlinks.
+
+``jolo new --eol=lf|crlf|native`` (or ``[new] eol``) rewrites the line
+endings of every text file; without it a file keeps the endings its
+template has. Shell scripts, justfiles and Makefiles, and anything
+starting with ``#!``, stay LF whatever is asked, since their
+interpreters choke on CR. The choice is recorded in ``jolo.lock``
+(``native`` as what it meant where the project was made), so ``jolo
+upgrade`` renders the same way.
+
+A rendered path that Windows cannot create (``CON``, ``aux.go``, a
+``:`` or a trailing dot) is refused on Windows, and a backslash in a
+rendered path is refused everywhere, since it is a separator only
+there. Project files are read and written as UTF-8 with their line
+endings untouched, on Windows too.
+
+A symlink in a template, pointing at a file inside it, becomes a
+symlink in the project. Where one cannot be made (Windows without
+Developer Mode, or a zip) the file it points at is copied instead.
+"""
+
+from __future__ import annotations
+
+import fnmatch
+import os
+import re
+from collections.abc import Iterable, Iterator
+from dataclasses import dataclass
+from pathlib import Path, PurePosixPath
+
+EOLS = {"lf": "\n", "crlf": "\r\n"}
+KEEP_LF = ("*.sh", "*.bash", "*.zsh", "*.fish", "justfile", "Makefile")
+_RESERVED = re.compile(r"(?i)(con|prn|aux|nul|com[1-9]|lpt[1-9])(\..*)?")
+_BAD = re.compile(r'[<>:"|?*\x00-\x1f]')
+
+
+@dataclass
+class Link:
+    """A symlink to TARGET (a POSIX path relative to the link); CONTENT,
+    the rendered file it points at, is written where links cannot be."""
+
+    target: str
+    content: object  # str, bytes or a streaming.Streamed
+
+
+def resolve_eol(name: str) -> str:
+    """lf or crlf for NAME (lf, crlf, native); SystemExit if unknown."""
+    if name == "native":
+        return "crlf" if os.linesep == "\r\n" else "lf"
+    if name not in EOLS:
+        choices = ", ".join([*EOLS, "native"])
+        raise SystemExit(f"Error: Unknown eol {name!r} (one of {choices})")
+    return name
+
+
+def keeps_lf(rel: str, text: str) -> bool:
+    name = PurePosixPath(rel).name
+    return text.startswith("#!") or any(
+        fnmatch.fnmatch(name, p) for p in KEEP_LF
+    )
+
+
+def convert(text: str, eol: str) -> str:
+    """TEXT with every line ending (LF or CRLF) made EOL's."""
+    return text.replace("\r\n", "\n").replace("\n", EOLS[eol])
+
+
+def convert_chunks(chunks: Iterable[str], eol: str) -> Iterator[str]:
+    """``convert`` for text in CHUNKS; a CR ending one chunk waits to
+    see whether an LF starts the next."""
+    held = ""
+    for chunk in chunks:
+        chunk = held + chunk
+        held = "\r" if chunk.endswith("\r") else ""
+        yield convert(chunk[: len(chunk) - len(held)], eol)
+    yield held
+
+
+def apply_eol(files: list, eol: str) -> list:
+    """FILES with the text ones converted to EOL (lf or crlf)."""
+    out = []
+    for rel, content in files:
+        if isinstance(content, Link):
+            content = Link(content.target, _eol(rel, content.content, eol))
+        else:
+            content = _eol(rel, content, eol)
+        out.append((rel, content))
+    return out
+
+
+def _eol(rel: str, content, eol: str):
+    if isinstance(content, str):
+        return content if keeps_lf(rel, content) else convert(content, eol)
+    if getattr(content, "variables", None) is not None:  # a text Streamed
+        with content.src.open("rb") as f:
+            head = f.read(2).decode(errors="replace")
+        if not keeps_lf(rel, head):
+            return content.with_eol(eol)
+    return content
+
+
+def read_text(path: Path) -> str:
+    """PATH as UTF-8, line endings as they are (read_text turns CRLF
+    into LF)."""
+    return path.read_bytes().decode()
+
+
+def write_text(path: Path, text: str) -> None:
+    """TEXT to PATH as UTF-8, line endings as they are (write_text on
+    Windows turns LF into CRLF)."""
+    path.write_text(text, encoding="utf-8", newline="")
+
+
+def windows_problem(path: str) -> str | None:
+    """Why Windows cannot create the relative POSIX PATH, if it cannot."""
+    for part in path.split("/"):
+        if _RESERVED.fullmatch(part):
+            return f"{part} is a reserved name"
+        bad = _BAD.search(part)
+        if bad:
+            return f"{part} has {bad[0]!r} in it"
+        if part.endswith((".", " ")):
+            return f"{part} ends in {part[-1]!r}"
+    return None
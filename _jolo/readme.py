@@ -0,0 +1,142 @@
+"""A README.md for each new project.
+
+``jolo new`` asks for a one-line DESCRIPTION (``--var
+DESCRIPTION=...`` answers it; ``--no-input`` leaves it empty) and, unless
+the template ships a README of its own, writes a README.md with the
+project name and description, a quickstart, the top-level layout, the
+template's own sections and the license.
+
+The quickstart is the justfile's ``setup``, ``dev`` and ``test`` recipes
+with their comments (a Makefile's or Taskfile's tasks when ``--tasks``
+picked one), unless the manifest lists commands. Manifests
+(base and addons) may also describe layout entries and add sections::
+
+    [readme]
+    quickstart = ["just setup", "just dev"]
+    layout = { "components/" = "templ components" }
+
+    [[readme.sections]]
+    title = "Routes"
+    body = "`GET /` renders the page, `GET /api/greet` the greeting."
+
+Section titles and bodies are rendered like template files.
+"""
+
+from __future__ import annotations
+
+import re
+
+from _jolo import placeholders
+
+FILE = "README.md"
+_OWN_README = ("README", "README.md", "README.org", "README.rst", "README.txt")
+_RECIPES = ("setup", "dev", "test")
+# A recipe line (not ``x := y``), with the comment above it.
+_RECIPE = re.compile(
+    r"^(?:# (?P<about>.*)\n)?(?P<name>[\w-]+)\b[^:=\n]*:(?!=)", re.M
+)
+# A Taskfile task, with its desc.
+_TASK = re.compile(
+    r'^  (?P<name>[\w-]+):[ \t]*\n(?:    desc: "?(?P<about>[^"\n]*)"?\n)?',
+    re.M,
+)
+# Runner files, the command running their tasks, and how tasks look.
+_RUNNERS = (
+    ("justfile", "just", _RECIPE),
+    ("Makefile", "make", _RECIPE),
+    ("Taskfile.yml", "task", _TASK),
+)
+
+# What well-known top-level entries are, unless a manifest says.
+LAYOUT = {
+    "src/": "source code",
+    "lib/": "source code",
+    "tests/": "tests",
+    "static/": "static assets",
+    "public/": "files served as-is",
+    "templates/": "HTML templates",
+    "components/": "UI components",
+    "justfile": "tasks: `just --list`",
+    "Makefile": "tasks: `make build`, `make test`, ...",
+    "Taskfile.yml": "tasks: `task --list`",
+    "LICENSE": "the license",
+}
+
+
+def quickstart(templates: list, files: dict) -> list[tuple[str, str]]:
+    """(command, what it does) pairs for the quickstart."""
+    for template in reversed(templates):
+        commands = template.manifest.get("readme", {}).get("quickstart")
+        if commands:
+            return [(c, "") for c in commands]
+    for filename, command, pattern in _RUNNERS:
+        text = files.get(filename)
+        if isinstance(text, str):
+            break
+    else:
+        return []
+    recipes = {m["name"]: m["about"] or "" for m in pattern.finditer(text)}
+    return [(f"{command} {r}", recipes[r]) for r in _RECIPES if r in recipes]
+
+
+def layout(templates: list, files: dict) -> list[tuple[str, str]]:
+    """(top-level entry, description) pairs, directories first."""
+    about = dict(LAYOUT)
+    for template in templates:
+        about.update(template.manifest.get("readme", {}).get("layout", {}))
+    top = {
+        rel.split("/")[0] + ("/" if "/" in rel else "")
+        for rel in files
+        if not rel.startswith(".") and rel != FILE
+    }
+    ordered = sorted(top, key=lambda e: (not e.endswith("/"), e.lower()))
+    return [(entry, about.get(entry, "")) for entry in ordered]
+
+
+def generate(templates: list, files: dict, variables: dict[str, str]) -> str:
+    lines = [f"# {variables['PROJECT_NAME']}", ""]
+    if variables.get("DESCRIPTION"):
+        lines += [variables["DESCRIPTION"], ""]
+    commands = quickstart(templates, files)
+    if commands:
+        width = max(len(c) for c, _ in commands)
+        lines += ["## Quickstart", "", "```sh"]
+        for command, about in commands:
+            lines.append(
+                f"{command:<{width}}  # {about}" if about else command
+            )
+        lines += ["```", ""]
+    sections = [
+        section
+        for template in templates
+        for section in template.manifest.get("readme", {}).get("sections", [])
+    ]
+    for section in sections:
+        title = placeholders.render(section["title"], variables)
+        body = placeholders.render(section["body"], variables).strip()
+        lines += [f"## {title}", "", body, ""]
+    entries = layout(templates, files)
+    if entries:
+        lines += ["## Layout", ""]
+        for entry, about in entries:
+            lines.append(f"- `{entry}`: {about}" if about else f"- `{entry}`")
+        lines.append("")
+    if "LICENSE" in files and variables.get("LICENSE"):
+        license = variables["LICENSE"]
+        lines += ["## License", "", f"{license}, see LICENSE.", ""]
+    return "\n".join(lines).rstrip("\n") + "\n"
+
+
+def apply(
+    files: list[tuple[str, str | bytes]],
+    templates: list,
+    variables: dict[str, str],
+) -> list[tuple[str, str | bytes]]:
+    """FILES plus a generated README.md, unless they have a README (or
+    there is no PROJECT_NAME to title it with)."""
+    rendered = dict(files)
+    if "PROJECT_NAME" not in variables or any(
+        rel in _OWN_README for rel in rendered
+    ):
+        return files
+    return [*files, (FILE, generate(templates, rendered, variables))]
@@ -0,0 +1,88 @@
+"""Protected regions: text that survives regenerating a file.
+
+A template marks a region with comment lines in whatever syntax the
+file uses::
+
+    // jolo:keep-start routes
+    mux.HandleFunc("GET /", home)
+    // jolo:keep-end
+
+When ``jolo add --force`` or ``jolo upgrade`` writes a fresh rendering
+over a file, each region's lines are taken from the file as it is, so
+what the user put there stays. Regions pair up by name, or by position
+among the unnamed ones. Markers must not nest.
+"""
+
+from __future__ import annotations
+
+import re
+
+_START = re.compile(r"jolo:keep-start(?:[ \t]+([\w.-]+))?")
+_END = "jolo:keep-end"
+
+
+def _parse(lines: list[str]) -> list[tuple[str, int, int]]:
+    """(key, start line, end line) for each region, 0-based; raises
+    ValueError on unbalanced or nested markers."""
+    found = []
+    keys = set()
+    unnamed = 0
+    open_at = key = None
+    for i, line in enumerate(lines):
+        start = _START.search(line)
+        if start:
+            if open_at is not None:
+                raise ValueError(
+                    f"line {i + 1}: jolo:keep-start inside the region "
+                    f"from line {open_at + 1}"
+                )
+            if start[1]:
+                key = start[1]
+            else:
+                unnamed += 1
+                key = f"#{unnamed}"
+            if key in keys:
+                raise ValueError(f"line {i + 1}: second region {key}")
+            keys.add(key)
+            open_at = i
+        elif _END in line:
+            if open_at is None:
+                raise ValueError(f"line {i + 1}: jolo:keep-end without start")
+            found.append((key, open_at, i))
+            open_at = None
+    if open_at is not None:
+        raise ValueError(f"line {open_at + 1}: jolo:keep-start never ends")
+    return found
+
+
+def regions(text: str) -> dict[str, str]:
+    """Region key -> the lines between its markers."""
+    lines = text.splitlines(keepends=True)
+    return {
+        key: "".join(lines[start + 1 : end])
+        for key, start, end in _parse(lines)
+    }
+
+
+def preserve(generated: str, current: str) -> str:
+    """GENERATED with each region's body taken from CURRENT, where
+    CURRENT has that region."""
+    keep = regions(current)
+    if not keep:
+        return generated
+    lines = generated.splitlines(keepends=True)
+    out = []
+    at = 0
+    for key, start, end in _parse(lines):
+        out += lines[at : start + 1]
+        body = "".join(lines[start + 1 : end])
+        out.append(keep.get(key, body))
+        at = end
+    out += lines[at:]
+    return "".join(out)
+
+
+def dropped(generated: str, current: str) -> list[str]:
+    """Regions of CURRENT that GENERATED no longer has."""
+    new = regions(generated)
+    return [key for key in regions(current) if key not in new]
@@ -0,0 +1,315 @@
+"""Remote template sources for `jolo new`.
+
+A remote spec is ``SOURCE[//SUBDIR][@REF]`` where SOURCE is a git URL
+(``https://…``, ``git@host:org/repo``, ``file://…``) or a bare
+``host/org/repo`` shorthand that expands to ``https://host/org/repo.git``.
+REF may be a branch, tag, or commit; the default is the remote HEAD. A
+version pin as REF (``@1.2``, ``@^1.2``; see ``_jolo.semver``) selects
+the highest matching release tag, ``v1.2.3`` or ``1.2.3``.
+
+Fetched sources are kept under ``$XDG_CACHE_HOME/jolo/templates``, one
+checkout per (url, ref). A release tag or commit is reused as is; a
+branch or HEAD is updated in place, falling back to the cached copy when
+the network fails. ``--offline`` (or ``JOLO_OFFLINE=1``) only ever uses
+the cache.
+
+Private repositories: ``git@`` URLs authenticate with the ssh agent (set
+``JOLO_GIT_PROTOCOL=ssh`` to expand shorthands to ``git@host:org/repo``),
+and https URLs with ``~/.netrc`` or a token from ``JOLO_GIT_TOKEN_<HOST>``
+(``JOLO_GIT_TOKEN_GITHUB_COM``), ``JOLO_GIT_TOKEN``, or ``GITHUB_TOKEN`` /
+``GITLAB_TOKEN`` for github.com / gitlab.com. A token is handed to git
+in its environment, never on the command line.
+"""
+
+from __future__ import annotations
+
+import base64
+import contextlib
+import datetime
+import hashlib
+import json
+import os
+import re
+import subprocess
+import sys
+import tempfile
+from dataclasses import dataclass
+from pathlib import Path
+
+from _jolo import constants, log, semver
+from _jolo.cli import verbose_cmd
+
+
+@dataclass
+class RemoteSpec:
+    url: str
+    ref: str | None = None
+    subdir: str = ""
+
+
+def is_remote(spec: str) -> bool:
+    """True for URLs and ``host.tld/…`` shorthands; built-in names like
+    ``go/web`` never have a dot in their first segment."""
+    if "://" in spec or spec.startswith("git@"):
+        return True
+    return "." in spec.split("/", 1)[0]
+
+
+def split_ref(spec: str) -> tuple[str, str | None]:
+    """``src//sub@v1`` -> (``src//sub``, ``v1``); the user part of
+    ``git@host:…`` is not a ref."""
+    tail = max(spec.rfind("/"), spec.rfind(":"))
+    at = spec.rfind("@")
+    if at > tail:
+        return spec[:at], spec[at + 1 :] or None
+    return spec, None
+
+
+def parse_spec(spec: str) -> RemoteSpec:
+    spec, ref = split_ref(spec)
+
+    scheme_end = spec.find("://")
+    start = scheme_end + 3 if scheme_end != -1 else 0
+    subdir = ""
+    split = spec.find("//", start)
+    if split != -1:
+        spec, subdir = spec[:split], spec[split + 2 :].strip("/")
+
+    if "://" not in spec and not spec.startswith("git@"):
+        if not spec.endswith(".git"):
+            spec += ".git"
+        if os.environ.get("JOLO_GIT_PROTOCOL") == "ssh":
+            host, _, path = spec.partition("/")
+            spec = f"git@{host}:{path}"
+        else:
+            spec = f"https://{spec}"
+    return RemoteSpec(url=spec, ref=ref, subdir=subdir)
+
+
+# Per-host tokens that other tools already set.
+HOST_TOKENS = {"github.com": "GITHUB_TOKEN", "gitlab.com": "GITLAB_TOKEN"}
+
+_AUTH_FAILURES = (
+    "Authentication failed",
+    "could not read Username",
+    "terminal prompts disabled",
+    "Permission denied (publickey)",
+)
+
+
+def token_for(host: str) -> str | None:
+    """The access token for HOST from the environment, if any."""
+    suffix = re.sub(r"[^A-Za-z0-9]", "_", host).upper()
+    for var in (
+        f"JOLO_GIT_TOKEN_{suffix}",
+        "JOLO_GIT_TOKEN",
+        HOST_TOKENS.get(host),
+    ):
+        if var and os.environ.get(var):
+            return os.environ[var]
+    return None
+
+
+def git_env(url: str | None = None) -> dict[str, str]:
+    """Environment for git talking to URL: never prompt, and send the
+    host's token, if any, as an https Authorization header."""
+    env = {**os.environ, "GIT_TERMINAL_PROMPT": "0"}
+    if not url or not url.startswith("https://"):
+        return env
+    host = url.removeprefix("https://").split("/", 1)[0].rpartition("@")[2]
+    token = token_for(host)
+    if not token:
+        return env
+    user = os.environ.get("JOLO_GIT_USER") or (
+        "x-access-token" if "github" in host else "oauth2"
+    )
+    basic = base64.b64encode(f"{user}:{token}".encode()).decode()
+    n = int(env.get("GIT_CONFIG_COUNT") or 0)
+    env["GIT_CONFIG_COUNT"] = str(n + 1)
+    env[f"GIT_CONFIG_KEY_{n}"] = f"http.https://{host}/.extraHeader"
+    env[f"GIT_CONFIG_VALUE_{n}"] = f"Authorization: Basic {basic}"
+    return env
+
+
+def _failure(cmd: list[str], stderr: str) -> str:
+    message = f"Error: {' '.join(cmd)} failed:\n{stderr.strip()}"
+    if any(s in stderr for s in _AUTH_FAILURES):
+        message += (
+            "\nFor a private repository set JOLO_GIT_TOKEN (or "
+            "JOLO_GIT_TOKEN_<HOST>), add the host to ~/.netrc, or use a "
+            "git@ URL (or JOLO_GIT_PROTOCOL=ssh) with ssh-agent."
+        )
+    return message
+
+
+def remote_refs(url: str) -> tuple[list[str], list[str]]:
+    """(tags, branches) of the repository at URL; offline, the release
+    tags and commits in the cache."""
+    if offline():
+        return [e.ref for e in cache_entries() if e.url == url], []
+    cmd = ["git", "ls-remote", "--refs", url]
+    verbose_cmd(cmd)
+    result = subprocess.run(
+        cmd, capture_output=True, text=True, env=git_env(url)
+    )
+    if result.returncode != 0:
+        sys.exit(_failure(cmd, result.stderr))
+    tags, branches = [], []
+    for line in result.stdout.splitlines():
+        ref = line.partition("\t")[2]
+        if ref.startswith("refs/tags/"):
+            tags.append(ref.removeprefix("refs/tags/"))
+        elif ref.startswith("refs/heads/"):
+            branches.append(ref.removeprefix("refs/heads/"))
+    return tags, branches
+
+
+def pin(spec: str) -> str:
+    """SPEC with a version pin as its REF replaced by the tag it selects;
+    other refs (and a tag or branch named exactly like the pin) are kept."""
+    source, ref = split_ref(spec)
+    if not ref or not semver.is_pin(ref):
+        return spec
+    url = parse_spec(source).url
+    tags, branches = remote_refs(url)
+    if ref in tags or ref in branches:
+        return spec
+    tag = semver.best(tags, ref)
+    if not tag:
+        versions = ", ".join(sorted(t for t in tags if semver.parse(t)))
+        sys.exit(
+            f"Error: No release tag of {url} matches {ref} "
+            f"(have: {versions or 'none'})"
+        )
+    return f"{source}@{tag}"
+
+
+def _git(args: list[str], cwd: Path, url: str | None = None) -> None:
+    cmd = ["git", *args]
+    verbose_cmd(cmd)
+    result = subprocess.run(
+        cmd, cwd=cwd, capture_output=True, text=True, env=git_env(url)
+    )
+    if result.returncode != 0:
+        sys.exit(_failure(cmd, result.stderr))
+
+
+def fetch(spec: RemoteSpec, dest: Path) -> Path:
+    """Shallow-fetch SPEC into DEST; return the template root inside it."""
+    dest.mkdir(parents=True, exist_ok=True)
+    if not (dest / ".git").is_dir():
+        _git(["init", "-q"], dest)
+    _git(
+        ["fetch", "-q", "--depth", "1", spec.url, spec.ref or "HEAD"],
+        dest,
+        url=spec.url,
+    )
+    _git(["checkout", "-q", "--force", "FETCH_HEAD"], dest)
+    _git(["clean", "-q", "-fdx"], dest)
+    root = dest / spec.subdir if spec.subdir else dest
+    if not root.is_dir():
+        sys.exit(f"Error: {spec.subdir} not found in {spec.url}")
+    return root
+
+
+def head_commit(path: Path) -> str:
+    """Commit checked out in the repo containing PATH."""
+    result = subprocess.run(
+        ["git", "rev-parse", "HEAD"], cwd=path, capture_output=True, text=True
+    )
+    return result.stdout.strip() if result.returncode == 0 else ""
+
+
+def offline() -> bool:
+    return constants.OFFLINE or os.environ.get("JOLO_OFFLINE") == "1"
+
+
+def cache_root() -> Path:
+    base = os.environ.get("XDG_CACHE_HOME") or Path.home() / ".cache"
+    return Path(base) / "jolo" / "templates"
+
+
+@dataclass
+class CacheEntry:
+    url: str
+    ref: str
+    commit: str
+    fetched: str
+    path: Path
+
+
+def _entry_path(url: str, ref: str | None) -> Path:
+    slug = re.sub(r"[^A-Za-z0-9]+", "-", url.split("://")[-1]).strip("-")
+    key = hashlib.sha256(f"{url}@{ref or ''}".encode()).hexdigest()[:12]
+    return cache_root() / f"{slug[-40:]}-{key}"
+
+
+def cache_entries() -> list[CacheEntry]:
+    entries = []
+    for meta in sorted(cache_root().glob("*/entry.json")):
+        try:
+            data = json.loads(meta.read_text())
+        except (OSError, json.JSONDecodeError):
+            continue
+        entries.append(CacheEntry(**data, path=meta.parent))
+    return entries
+
+
+def _immutable(ref: str | None) -> bool:
+    """Release tags and full commit ids never move, so a cached copy of
+    one is reused without asking the network."""
+    return bool(
+        ref and (semver.parse(ref) or re.fullmatch(r"[0-9a-f]{40}", ref))
+    )
+
+
+def refresh(url: str, ref: str | None) -> Path:
+    """Fetch URL at REF into its cache entry; return the checkout."""
+    entry = _entry_path(url, ref)
+    repo = entry / "repo"
+    if repo.is_dir():
+        fetch(RemoteSpec(url=url, ref=ref), repo)
+    else:
+        entry.parent.mkdir(parents=True, exist_ok=True)
+        with tempfile.TemporaryDirectory(
+            prefix=".fetch-", dir=entry.parent
+        ) as tmp:
+            fetch(RemoteSpec(url=url, ref=ref), Path(tmp) / "repo")
+            entry.mkdir(exist_ok=True)
+            (Path(tmp) / "repo").rename(repo)
+    meta = {
+        "url": url,
+        "ref": ref or "",
+        "commit": head_commit(repo),
+        "fetched": datetime.datetime.now().isoformat(timespec="seconds"),
+    }
+    (entry / "entry.json").write_text(json.dumps(meta, indent=2) + "\n")
+    return repo
+
+
+def checkout(spec: RemoteSpec) -> Path:
+    """The template root of SPEC in the cache, fetched if need be."""
+    entry = _entry_path(spec.url, spec.ref)
+    repo = entry / "repo"
+    at = f"{spec.url}@{spec.ref or 'HEAD'}"
+    if offline():
+        if not repo.is_dir():
+            sys.exit(f"Error: {at} is not cached (run once without --offline)")
+    elif not (repo.is_dir() and _immutable(spec.ref)):
+        try:
+            refresh(spec.url, spec.ref)
+        except SystemExit as e:
+            if not repo.is_dir():
+                raise
+            print(f"{e.code}", file=sys.stderr)
+            log.warning(f"using the cached copy of {at}")
+    root = repo / spec.subdir if spec.subdir else repo
+    if not root.is_dir():
+        sys.exit(f"Error: {spec.subdir} not found in {spec.url}")
+    return root
+
+
+@contextlib.contextmanager
+def fetched(spec: str):
+    """Yield the template root of SPEC, from the cache."""
+    yield checkout(parse_spec(spec))
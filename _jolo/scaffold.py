@@ -0,0 +1,1998 @@
+"""jolo new — render a project template into a fresh directory.
+
+A template is a directory with a ``template.toml`` manifest, builtin
+under ``templates/lang/``, local or from git. Manifests describe what
+varies between projects (the questions, which files, what runs around
+them) so that the files themselves stay plain; addons are templates
+overlaid on one. README.org documents the manifest tables and flags.
+"""
+
+from __future__ import annotations
+
+import contextlib
+import fnmatch
+import itertools
+import json
+import os
+import shutil
+import subprocess
+import sys
+import tomllib
+import uuid
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from _jolo import (
+    aliases,
+    cookiecutter,
+    editor,
+    editorconfig,
+    envrc,
+    events,
+    fuzzy,
+    gitignore,
+    golang,
+    install,
+    licenses,
+    log,
+    monorepo,
+    parallel,
+    placeholders,
+    portable,
+    readme,
+    remote,
+    secret,
+    semver,
+    smoke,
+    starlark,
+    streaming,
+    tasks,
+    toolchain,
+    trust,
+    validation,
+)
+from _jolo.cli import verbose_cmd, verbose_print
+from _jolo.commands import _fmt_size, _fzf_pick, load_config
+from _jolo.templates import _TEMPLATES_DIR, to_snake_case
+from _jolo.writers import DirWriter, Writer, open_output
+
+TEMPLATES_ROOT = _TEMPLATES_DIR / "lang"
+ADDONS_ROOT = _TEMPLATES_DIR / "addons"
+MANIFEST_FILE = "template.toml"
+PARTIALS_DIR = "_partials"
+GENERATOR_FILE = "generator.toml"
+LOCK_FILE = "jolo.lock"
+# Where jolo eject copies a project's template, laid out like templates/.
+EJECT_DIR = Path(".jolo") / "templates"
+VARIABLE_TYPES = ("string", "bool", "int", "choice", "multi")
+# What config_variables sets; answers may override each.
+CONFIG_VARIABLES = ("AUTHOR", "EMAIL", "LICENSE", "MODULE_PATH")
+_TRUE = ("y", "yes", "true", "1", "on")
+_FALSE = ("n", "no", "false", "0", "off")
+
+# Rendered output: (relative path, text or, for binary files, bytes;
+# for jolo new also a Streamed it renders as it writes, or a Link).
+Files = list[tuple[str, str | bytes | streaming.Streamed | portable.Link]]
+
+
+@dataclass
+class Template:
+    name: str
+    path: Path
+    manifest: dict = field(default_factory=dict)
+    commit: str = ""
+    # The templates dir a template looked up by name was found in,
+    # where the template it extends is looked up first.
+    root: Path | None = None
+    # The template its manifest ``extends``, loaded.
+    parent: Template | None = None
+
+    @property
+    def description(self) -> str:
+        return self.manifest.get("description", "")
+
+    @property
+    def version(self) -> str:
+        """The manifest's version; for a git source pinned to a release
+        tag without one, the tag's."""
+        version = str(self.manifest.get("version", ""))
+        if version and not semver.parse(version):
+            sys.exit(f"Error: {self.name}: version {version!r} is not X.Y.Z")
+        if not version and remote.is_remote(self.name):
+            ref = remote.split_ref(self.name)[1] or ""
+            if semver.parse(ref):
+                version = ref.removeprefix("v")
+        return version
+
+    @property
+    def variables(self) -> dict[str, dict]:
+        variables = self.manifest.get("variables", {})
+        for name, spec in variables.items():
+            kind = spec.get("type", "string")
+            if kind not in VARIABLE_TYPES:
+                sys.exit(f"Error: {self.name}: {name} has unknown type {kind}")
+            if kind in ("choice", "multi") and not spec.get("choices"):
+                sys.exit(f"Error: {self.name}: {name} needs choices")
+            if "addons" in spec and (
+                kind != "multi" or not isinstance(spec["addons"], dict)
+            ):
+                sys.exit(
+                    f"Error: {self.name}: {name}: addons needs a multi "
+                    "variable and a table of choice = addon"
+                )
+            if "addons" in spec and "when" in spec:
+                sys.exit(f"Error: {self.name}: {name}: addons and when")
+            problem = validation.spec_problem(spec)
+            if problem:
+                sys.exit(f"Error: {self.name}: {name}: {problem}")
+            if not isinstance(spec.get("when", ""), str):
+                sys.exit(f"Error: {self.name}: {name}: when must be a string")
+        return variables
+
+
+def load_template(
+    path: Path, name: str, root: Path | None = None, _chain: tuple = ()
+) -> Template:
+    """Load the template rooted at PATH, found as NAME in the templates
+    dir ROOT. A missing manifest is an empty one, so any directory (e.g.
+    a fetched repo) renders as a template; a cookiecutter template is
+    read from its cookiecutter.json. The template its manifest
+    ``extends`` is loaded as its parent (see ``_extend``)."""
+    manifest = {}
+    if (path / MANIFEST_FILE).is_file():
+        with open(path / MANIFEST_FILE, "rb") as f:
+            manifest = tomllib.load(f)
+    elif cookiecutter.is_cookiecutter(path):
+        return Template(
+            name=name,
+            path=cookiecutter.project_dir(path),
+            manifest=cookiecutter.manifest(path),
+            root=root,
+        )
+    template = Template(name=name, path=path, manifest=manifest, root=root)
+    return _extend(template, _chain)
+
+
+# What a manifest keeps to itself rather than passing to the templates
+# that extend it.
+_OWN_KEYS = ("description", "version", "tags", "script", "extends")
+
+
+def _merge(parent: dict, child: dict) -> dict:
+    """CHILD over PARENT: tables merge key by key, and any other value
+    CHILD has (a list included) replaces PARENT's."""
+    merged = dict(parent)
+    for key, value in child.items():
+        if isinstance(value, dict) and isinstance(merged.get(key), dict):
+            value = _merge(merged[key], value)
+        merged[key] = value
+    return merged
+
+
+def _extend(template: Template, chain: tuple = ()) -> Template:
+    """TEMPLATE with the template its manifest ``extends`` as its parent,
+    looked up in TEMPLATE's templates dir and then the builtin ones, and
+    the parent's manifest (bar ``_OWN_KEYS``) under its own. CHAIN names
+    the templates extending TEMPLATE, to refuse a loop."""
+    name = template.manifest.get("extends")
+    if not name:
+        return template
+    if not isinstance(name, str):
+        sys.exit(f"Error: {template.name}: extends names one template")
+    chain = (*chain, template.name)
+    if name in chain:
+        loop = " -> ".join((*chain, name))
+        sys.exit(f"Error: {template.name}: extends loops: {loop}")
+    roots = [r for r in (template.root, TEMPLATES_ROOT) if r is not None]
+    for root in roots:
+        if path := _template_dir(root, name):
+            break
+    else:
+        sys.exit(f"Error: {template.name}: extends unknown template {name}")
+    template.parent = load_template(path, name, root, chain)
+    inherited = {
+        k: v for k, v in template.parent.manifest.items() if k not in _OWN_KEYS
+    }
+    template.manifest = _merge(inherited, template.manifest)
+    return template
+
+
+def abstract(name: str) -> bool:
+    """Whether NAME is a template only there to be extended: one with a
+    part starting with ``_`` (``go/_base``)."""
+    return any(part.startswith("_") for part in name.split("/"))
+
+
+def list_templates(root: Path = TEMPLATES_ROOT) -> list[Template]:
+    """Return every template below ROOT, sorted by name, bar the ones
+    only there to be extended."""
+    templates = [
+        load_template(m.parent, m.parent.relative_to(root).as_posix(), root)
+        for m in root.rglob(MANIFEST_FILE)
+        if not abstract(m.parent.relative_to(root).as_posix())
+    ]
+    return sorted(templates, key=lambda t: t.name)
+
+
+def plain_name(name: str) -> bool:
+    """True for a template name that stays below the root it is looked up
+    in: relative, with no empty, ``.`` or ``..`` segments."""
+    parts = name.split("/")
+    return not any(p in ("", ".", "..") or "\\" in p for p in parts)
+
+
+def _template_dir(root: Path, name: str) -> Path | None:
+    """ROOT/NAME if it is a template inside ROOT."""
+    if not plain_name(name):
+        return None
+    path = root / name
+    if not path.resolve().is_relative_to(root.resolve()):
+        return None
+    return path if (path / MANIFEST_FILE).is_file() else None
+
+
+def find_template(name: str, root: Path = TEMPLATES_ROOT) -> Template:
+    path = _template_dir(root, name)
+    if path is None:
+        names = [t.name for t in list_templates(root)]
+        hint = fuzzy.did_you_mean(name, names)
+        sys.exit(f"Error: Unknown template: {name}{hint}")
+    return load_template(path, name, root)
+
+
+def template_roots(dirs: list[str], new_cfg: dict) -> list[Path]:
+    """Template directories, highest priority first: ``--templates-dir``,
+    ``$JOLO_TEMPLATES`` (path-separated), config ``templates_dirs``, then
+    the builtin templates. A name found earlier shadows later ones."""
+    env = os.environ.get("JOLO_TEMPLATES", "")
+    layers = [
+        *dirs,
+        *[d for d in env.split(os.pathsep) if d],
+        *new_cfg.get("templates_dirs", []),
+    ]
+    roots = [Path(d).expanduser().resolve() for d in layers]
+    for root in roots:
+        if not root.is_dir():
+            sys.exit(f"Error: Templates directory not found: {root}")
+    return [*roots, TEMPLATES_ROOT]
+
+
+def list_layered(roots: list[Path]) -> list[Template]:
+    seen = {}
+    for root in roots:
+        for template in list_templates(root):
+            seen.setdefault(template.name, template)
+    return sorted(seen.values(), key=lambda t: t.name)
+
+
+def find_layered(name: str, roots: list[Path]) -> tuple[Template, Path]:
+    """The first template called NAME in ROOTS, and the root it is in."""
+    for root in roots:
+        if path := _template_dir(root, name):
+            return load_template(path, name, root), root
+    hint = fuzzy.did_you_mean(name, [t.name for t in list_layered(roots)])
+    sys.exit(f"Error: Unknown template: {name}{hint}")
+
+
+def find_pinned(spec: str, roots: list[Path]) -> tuple[Template, Path]:
+    """find_layered for ``NAME[@PIN]``: a local template has one version,
+    which must satisfy PIN."""
+    name, _, want = spec.partition("@")
+    template, root = find_layered(name, roots)
+    if want:
+        check_pin(template, want)
+    return template, root
+
+
+def check_pin(template: Template, want: str) -> None:
+    if not semver.is_pin(want):
+        sys.exit(f"Error: {want!r} is not a version pin (e.g. 1.2, ^1.2)")
+    if not semver.satisfies(template.version, want):
+        have = template.version or "unversioned"
+        sys.exit(f"Error: {template.name} is {have}, which is not {want}")
+
+
+def _addon_dirs(base: Template, root: Path) -> list[Path]:
+    """Where BASE's addons live, most specific first: ``go/web``, then
+    ``go``, then the top."""
+    parts = [p for p in base.name.split("/") if p not in ("", ".", "..")]
+    dirs = [root.joinpath(*parts[:n]) for n in range(len(parts), 0, -1)]
+    return [*dirs, root]
+
+
+def find_addon(
+    name: str, base: Template, root: Path = ADDONS_ROOT
+) -> Template:
+    for path in (_template_dir(d, name) for d in _addon_dirs(base, root)):
+        if path is not None:
+            break
+    else:
+        hint = fuzzy.did_you_mean(name, addon_names(base, root))
+        sys.exit(f"Error: Unknown addon for {base.name}: {name}{hint}")
+    addon = load_template(path, name)
+    allowed = addon.manifest.get("templates")
+    if allowed and base.name not in allowed:
+        sys.exit(
+            f"Error: Addon {name} supports {', '.join(allowed)}, "
+            f"not {base.name}"
+        )
+    return addon
+
+
+def addon_names(base: Template, root: Path = ADDONS_ROOT) -> list[str]:
+    """Addons find_addon accepts for BASE (what ``--with`` can take),
+    sorted."""
+    names = []
+    for path in _addon_dirs(base, root):
+        for manifest in path.glob(f"*/{MANIFEST_FILE}"):
+            name = manifest.parent.name
+            addon = load_template(manifest.parent, name)
+            allowed = addon.manifest.get("templates")
+            if name in names or (allowed and base.name not in allowed):
+                continue
+            names.append(name)
+    return sorted(names)
+
+
+def order_addons(addons: list[Template]) -> list[Template]:
+    """ADDONS with each after those its manifest ``needs``, otherwise in
+    the order picked; exits when one it needs was not picked."""
+    by_name = {a.name: a for a in addons}
+    ordered: list[Template] = []
+
+    def visit(addon: Template, path: tuple[str, ...]) -> None:
+        if addon in ordered:
+            return
+        needs = addon.manifest.get("needs", [])
+        if not isinstance(needs, list) or not all(
+            isinstance(n, str) for n in needs
+        ):
+            sys.exit(f"Error: Addon {addon.name}: needs must be a list")
+        for name in needs:
+            if name not in by_name:
+                sys.exit(
+                    f"Error: Addon {addon.name} needs {name} "
+                    f"(--with {name},{addon.name})"
+                )
+            if name in path:
+                sys.exit(f"Error: Addon {addon.name}: needs cycle via {name}")
+            visit(by_name[name], (*path, addon.name))
+        ordered.append(addon)
+
+    for addon in addons:
+        visit(addon, ())
+    return ordered
+
+
+def parse_with_args(values: list[str]) -> list[str]:
+    """``--with a,b --with c`` -> [a, b, c], dropping repeats."""
+    names = []
+    for value in values:
+        for name in value.split(","):
+            if name.strip() and name.strip() not in names:
+                names.append(name.strip())
+    return names
+
+
+def project_variables(project_name: str) -> dict[str, str]:
+    """Placeholder values every template can use.
+
+    MODULE_NAME is snake_case to match what the justfiles expect.
+    """
+    snake = to_snake_case(project_name)
+    return {
+        "PROJECT_NAME": project_name,
+        "PROJECT_NAME_UNDERSCORE": snake,
+        "MODULE_NAME": snake,
+        "MODULE_PATH": project_name,
+        "DESCRIPTION": "",
+    }
+
+
+def _git_config(key: str) -> str:
+    result = subprocess.run(
+        ["git", "config", "--get", key], capture_output=True, text=True
+    )
+    return result.stdout.strip()
+
+
+def config_variables(new_cfg: dict, project_name: str) -> dict[str, str]:
+    """AUTHOR, EMAIL, LICENSE and MODULE_PATH from the ``[new]`` config
+    table, falling back to git's user.name and user.email, MIT, and the
+    bare project name."""
+    prefix = new_cfg.get("module_prefix", "").rstrip("/")
+    license = new_cfg.get("license", "MIT")
+    return {
+        "AUTHOR": new_cfg.get("author") or _git_config("user.name"),
+        "EMAIL": new_cfg.get("email") or _git_config("user.email"),
+        "LICENSE": licenses.spdx(license) or license,
+        "MODULE_PATH": golang.default_module_path(prefix, project_name),
+    }
+
+
+def template_config(new_cfg: dict, name: str) -> dict:
+    """NEW_CFG for the template NAME: with the ``[new.for.KEY]`` tables
+    whose KEY is NAME, or a prefix of it (``go`` for ``go/web``), merged
+    in, the closest last. Tables such as ``vars`` merge key by key."""
+    cfg = {k: v for k, v in new_cfg.items() if k != "for"}
+    overlays = new_cfg.get("for", {})
+    for key in sorted(overlays, key=lambda k: k.count("/")):
+        if name != key and not name.startswith(key + "/"):
+            continue
+        for k, v in overlays[key].items():
+            if isinstance(v, dict) and isinstance(cfg.get(k), dict):
+                v = {**cfg[k], **v}
+            cfg[k] = v
+    return cfg
+
+
+def coerce_variable(spec: dict, value: str | bool | int | list) -> str:
+    """Validate VALUE against SPEC's type and rules (see
+    ``_jolo.validation``) and return its rendered form.
+
+    Raises ValueError so prompts can ask again and ``--var`` can fail.
+    """
+    rendered = _coerce(spec, value)
+    validation.check(spec, rendered)
+    return rendered
+
+
+def _coerce(spec: dict, value: str | bool | int | list) -> str:
+    kind = spec.get("type", "string")
+    if kind == "bool":
+        if isinstance(value, bool):
+            return "true" if value else "false"
+        if str(value).lower() in _TRUE:
+            return "true"
+        if str(value).lower() in _FALSE:
+            return "false"
+        raise ValueError(f"expected yes or no, got {value!r}")
+    if kind == "int":
+        try:
+            return str(int(value))
+        except ValueError:
+            raise ValueError(f"expected an integer, got {value!r}") from None
+    if kind == "multi":
+        return _coerce_many([str(c) for c in spec["choices"]], value)
+    if kind == "choice":
+        choices = [str(c) for c in spec["choices"]]
+        if str(value) not in choices:
+            raise ValueError(
+                f"expected one of {', '.join(choices)}, got {value!r}"
+            )
+    return str(value)
+
+
+def _coerce_many(choices: list[str], value: str | list) -> str:
+    """A multi variable's VALUE (a list, or comma-separated) as its
+    picks in CHOICES order, comma-separated."""
+    items = value if isinstance(value, list) else str(value).split(",")
+    picked = {str(item).strip() for item in items} - {""}
+    unknown = [item for item in sorted(picked) if item not in choices]
+    if unknown:
+        raise ValueError(
+            f"expected some of {', '.join(choices)}, "
+            f"got {', '.join(unknown)}"
+        )
+    return ",".join(c for c in choices if c in picked)
+
+
+def parse_var_args(pairs: list[str]) -> dict[str, str]:
+    values = {}
+    for pair in pairs:
+        key, sep, value = pair.partition("=")
+        if not sep or not key:
+            sys.exit(f"Error: --var expects KEY=VALUE, got {pair!r}")
+        values[key] = value
+    return values
+
+
+def _template_sources(template: Template) -> list[tuple[Path, str]]:
+    """(file, relative path) to render: everything but the manifest,
+    .git, ``_partials/``, and nested templates or ``jolo add`` generators
+    (dirs with their own manifest), over its parent's."""
+    manifests = (MANIFEST_FILE, GENERATOR_FILE)
+    script = template.manifest.get("script")
+    nested = [
+        m.parent
+        for name in manifests
+        for m in template.path.rglob(name)
+        if m.parent != template.path
+    ]
+    sources = []
+    for path in sorted(template.path.rglob("*")):
+        if not path.is_file():
+            continue
+        parts = path.relative_to(template.path).parts
+        if ".git" in parts or parts[0] == PARTIALS_DIR:
+            continue
+        if path.parent == template.path and path.name in manifests:
+            continue
+        if script and path == template.path / script:
+            continue
+        if any(n in path.parents for n in nested):
+            continue
+        sources.append(path)
+    found = {p.relative_to(template.path).as_posix(): p for p in sources}
+    if template.parent is not None:
+        for path, rel in _template_sources(template.parent):
+            found.setdefault(rel, path)
+    # By path parts, so a/b sorts before a-b, as Paths do.
+    return sorted(
+        ((p, rel) for rel, p in found.items()), key=lambda s: s[1].split("/")
+    )
+
+
+def condition_holds(expr: str, variables: dict[str, str]) -> bool:
+    """Evaluate a manifest condition: ``NAME`` (truthy), ``!NAME``,
+    ``NAME == VALUE``, ``NAME != VALUE`` or, for a multi variable,
+    ``NAME has VALUE``, or several joined by ``and``, which all hold."""
+    if " and " in expr:
+        return all(
+            condition_holds(part, variables) for part in expr.split(" and ")
+        )
+    expr = expr.strip()
+    name, has, value = expr.partition(" has ")
+    if has:
+        name = name.strip()
+        if name not in variables:
+            raise ValueError(f"unknown variable in condition: {name}")
+        return value.strip().strip("\"'") in variables[name].split(",")
+    for op in ("==", "!="):
+        if op in expr:
+            name, value = (part.strip() for part in expr.split(op, 1))
+            if name not in variables:
+                raise ValueError(f"unknown variable in condition: {name}")
+            return (variables[name] == value.strip("\"'")) == (op == "==")
+    negate = expr.startswith("!")
+    name = expr.lstrip("!").strip()
+    if name not in variables:
+        raise ValueError(f"unknown variable in condition: {name}")
+    value = variables[name].lower()
+    truthy = bool(value) and value not in _FALSE
+    return truthy != negate
+
+
+def _matches(rel: str, patterns: list[str]) -> bool:
+    """REL matches a glob in PATTERNS, or sits below a ``dir/`` one."""
+    return any(
+        rel.startswith(p) if p.endswith("/") else fnmatch.fnmatch(rel, p)
+        for p in patterns
+    )
+
+
+def _included(
+    rel: str, conditions: dict[str, str], variables: dict[str, str]
+) -> bool:
+    """False if REL, or a directory (``dir/``) above it, has a condition
+    in the manifest's ``[conditions]`` that does not hold."""
+    for pattern, expr in conditions.items():
+        if rel == pattern or (
+            pattern.endswith("/") and rel.startswith(pattern)
+        ):
+            if not condition_holds(expr, variables):
+                return False
+    return True
+
+
+def is_binary(data: bytes) -> bool:
+    """NUL bytes or invalid UTF-8 in the first 8 KiB mean binary."""
+    head = data[:8192]
+    if b"\0" in head:
+        return True
+    try:
+        head.decode()
+    except UnicodeDecodeError as e:
+        # A multi-byte character cut off at the 8 KiB mark is still text.
+        cut = len(data) > len(head) and e.reason == "unexpected end of data"
+        return not cut
+    return False
+
+
+def partials(template: Template) -> dict[str, Path]:
+    """The partials TEMPLATE's files may include, by their path below
+    ``_partials/``: its own, then its parent's and so on up (the nearest
+    one of a name wins)."""
+    found = {}
+    while template is not None:
+        root = template.path / PARTIALS_DIR
+        for path in sorted(root.rglob("*")) if root.is_dir() else []:
+            if path.is_file():
+                found.setdefault(path.relative_to(root).as_posix(), path)
+        template = template.parent
+    return found
+
+
+def renderer(template: Template):
+    """The text renderer for TEMPLATE: jolo placeholders, with ``{{
+    partial "NAME" }}`` when it has partials, or the Jinja subset of a
+    cookiecutter template. Both raise ValueError."""
+    if template.manifest.get("engine") == "cookiecutter":
+        return cookiecutter.render
+    found = partials(template)
+    if not found:
+        return placeholders.render
+
+    def render(text: str, variables: dict[str, str], including=()) -> str:
+        def partial(variables: dict[str, str], name: str) -> str:
+            if name not in found:
+                raise ValueError(f"no partial {name} in {PARTIALS_DIR}/")
+            if name in including:
+                raise ValueError(f"partial {name} includes itself")
+            text = found[name].read_text().removesuffix("\n")
+            return render(text, variables, (*including, name))
+
+        return placeholders.render(text, variables, {"partial": partial})
+
+    return render
+
+
+def render_path(
+    rel: str, variables: dict[str, str], render=placeholders.render
+) -> str:
+    """Render a template-relative path such as ``cmd/{{PROJECT_NAME}}/``.
+
+    A value may add directories (``a/b``) but the result must stay inside
+    the project: no absolute paths, ``..``, empty segments or
+    backslashes, nor on Windows a name it cannot create.
+    """
+    rendered = render(rel, variables)
+    parts = rendered.split("/")
+    if (
+        rendered.startswith("/")
+        or "\\" in rendered
+        or any(p in ("", ".", "..") for p in parts)
+    ):
+        raise ValueError(f"{rel} renders to an invalid path: {rendered!r}")
+    problem = portable.windows_problem(rendered) if os.name == "nt" else None
+    if problem:
+        raise ValueError(f"{rel} renders to {rendered!r}: {problem}")
+    return rendered
+
+
+def load_script(template: Template) -> starlark.Script | None:
+    """TEMPLATE's manifest ``script``, loaded (see ``_jolo.starlark``)."""
+    name = template.manifest.get("script")
+    if not name:
+        return None
+    path = template.path / name
+    if not path.is_file():
+        sys.exit(f"Error: {template.name}: no script {name}")
+    try:
+        return starlark.load(path)
+    except ValueError as e:
+        sys.exit(f"Error: {template.name}: {e}")
+
+
+def _call_script(template: Template, script, hook: str, *args, want=None):
+    """Call HOOK in TEMPLATE's script; exit unless it returns a WANT."""
+    try:
+        result = script.call(hook, *args)
+    except ValueError as e:
+        sys.exit(f"Error: {template.name}: {e}")
+    if want is not None and not isinstance(result, want):
+        sys.exit(
+            f"Error: {template.name}: {script.filename}: {hook}() returned "
+            f"{starlark.type_name(result)}, expected {want.__name__}"
+        )
+    return result
+
+
+def script_variables(
+    templates: list[Template], variables: dict[str, str]
+) -> dict[str, str]:
+    """The variables the templates' scripts derive, in template order
+    (each sees those derived before it)."""
+    derived = {}
+    for template in templates:
+        script = load_script(template)
+        if script is None or not script.has("variables"):
+            continue
+        result = _call_script(
+            template,
+            script,
+            "variables",
+            {**variables, **derived},
+            want=dict,
+        )
+        for key, value in result.items():
+            value = _derived(value)
+            if not isinstance(key, str) or value is None:
+                sys.exit(
+                    f"Error: {template.name}: {script.filename}: "
+                    f"variables() gave {key!r} = {result[key]!r}; expected "
+                    "a string, int or bool"
+                )
+            derived[key] = value
+    return derived
+
+
+def _derived(value) -> str | None:
+    """VALUE as a variable, if it is a string, int or bool."""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, (str, int)):
+        return str(value)
+    return None
+
+
+def computed_variables(
+    templates: list[Template], variables: dict[str, str]
+) -> dict[str, str]:
+    """The manifests' ``[computed]`` variables, ``NAME = "expression"``
+    (see ``starlark.Expression``), in order; each sees those before it."""
+    derived = {}
+    for template in templates:
+        for name, source in template.manifest.get("computed", {}).items():
+            where = f"Error: {template.name}: computed.{name}"
+            if not isinstance(source, str):
+                sys.exit(f"{where}: expected an expression in a string")
+            try:
+                expression = starlark.Expression(source, f"computed.{name}")
+                result = expression.evaluate({**variables, **derived})
+            except ValueError as e:
+                sys.exit(f"Error: {template.name}: {e}")
+            value = _derived(result)
+            if value is None:
+                sys.exit(
+                    f"{where} is {starlark.type_name(result)}; expected a "
+                    "string, int or bool"
+                )
+            derived[name] = value
+    return derived
+
+
+def _link_target(template: Template, src: Path) -> str | None:
+    """SRC's link text, if it is a relative symlink to a file inside
+    TEMPLATE."""
+    if not src.is_symlink():
+        return None
+    target = os.readlink(src)
+    resolved = src.resolve()
+    if Path(target).is_absolute() or not resolved.is_file():
+        return None
+    if not resolved.is_relative_to(template.path.resolve()):
+        return None
+    return Path(target).as_posix()
+
+
+def render_template(
+    template: Template, variables: dict[str, str], stream: bool = False
+) -> Files:
+    """Render TEMPLATE to a list of (relative_path, content) pairs.
+
+    Paths and contents share the same placeholder substitution (see
+    ``_jolo.placeholders``); anything that does not resolve (e.g. just's
+    ``{{args}}``) is left as is. Binary files, and those matching the
+    manifest's ``binary`` globs, are copied verbatim as bytes. A script
+    may leave files out, transform rendered text and add files. With
+    STREAM (for jolo new, writing the files), large files are left to
+    render as they are written (see ``_jolo.streaming``) and symlinks
+    within the template stay links (see ``_jolo.portable``).
+    """
+    conditions = template.manifest.get("conditions", {})
+    verbatim = template.manifest.get("binary", [])
+    render = renderer(template)
+    script = load_script(template)
+    hooks = {
+        hook
+        for hook in ("include", "transform", "files")
+        if script and script.has(hook)
+    }
+    # Which files and where they go is decided here, in order; reading
+    # and rendering them is spread over threads (see _jolo.parallel).
+    # The script only ever runs on this thread.
+    chosen = {}
+    for src, rel in _template_sources(template):
+        try:
+            if not _included(rel, conditions, variables):
+                continue
+            path = render_path(rel, variables, render)
+        except ValueError as e:
+            sys.exit(f"Error: {template.name}: {e}")
+        if "include" in hooks and not _call_script(
+            template, script, "include", rel, dict(variables)
+        ):
+            continue
+        if path in chosen:
+            sys.exit(f"Error: {template.name}: two files render to {path}")
+        chosen[path] = (src, rel)
+
+    links = stream
+    stream = (
+        stream and render is placeholders.render and "transform" not in hooks
+    )
+
+    def read(item: tuple[Path, str]):
+        src, rel = item
+        target = _link_target(template, src) if links else None
+        if target is not None:
+            content = read((src.resolve(), rel))
+            return portable.Link(render(target, variables), content)
+        if stream and src.stat().st_size >= streaming.STREAM_SIZE:
+            with src.open("rb") as f:
+                head = f.read(8192)
+            if is_binary(head) or _matches(rel, verbatim):
+                return streaming.Streamed(src, None)
+            return streaming.Streamed(src, variables)
+        data = src.read_bytes()
+        if is_binary(data) or _matches(rel, verbatim):
+            return data
+        try:
+            return render(data.decode(), variables)
+        except ValueError as e:
+            sys.exit(f"Error: {template.name}: {rel}: {e}")
+
+    contents = parallel.pmap(read, list(chosen.values()))
+    files = dict(zip(chosen, contents))
+    if "transform" in hooks:
+        for path, content in files.items():
+            if isinstance(content, str):
+                files[path] = _call_script(
+                    template,
+                    script,
+                    "transform",
+                    path,
+                    content,
+                    dict(variables),
+                    want=str,
+                )
+    if "files" in hooks:
+        extra = _call_script(
+            template, script, "files", dict(variables), want=dict
+        )
+        for path, content in extra.items():
+            if not isinstance(path, str) or not isinstance(content, str):
+                sys.exit(
+                    f"Error: {template.name}: {script.filename}: files() "
+                    "must map paths to strings"
+                )
+            try:
+                path = render_path(path, variables)
+            except ValueError as e:
+                sys.exit(f"Error: {template.name}: {script.filename}: {e}")
+            if path in files:
+                sys.exit(
+                    f"Error: {template.name}: {script.filename}: files() "
+                    f"gives {path}, which the template already has"
+                )
+            files[path] = content
+    return list(files.items())
+
+
+def apply_patch(content: str, patch: dict, variables: dict[str, str]) -> str:
+    """Apply one addon patch: ``append``, or ``insert`` placed
+    ``after``/``before`` the first line containing an anchor, or in its
+    place for ``replace``."""
+    if "append" in patch:
+        text = placeholders.render(patch["append"], variables)
+        if content and not content.endswith("\n"):
+            content += "\n"
+        return content + text
+
+    anchor = (
+        patch.get("after") or patch.get("before") or patch.get("replace")
+    )
+    text = placeholders.render(patch["insert"], variables)
+    if not text.endswith("\n"):
+        text += "\n"
+    lines = content.splitlines(keepends=True)
+    for i, line in enumerate(lines):
+        if anchor in line:
+            if "replace" in patch:
+                lines[i] = text
+                return "".join(lines)
+            at = i + 1 if "after" in patch else i
+            if at == len(lines) and not line.endswith("\n"):
+                lines[i] += "\n"
+            lines.insert(at, text)
+            return "".join(lines)
+    raise ValueError(f"anchor not found: {anchor!r}")
+
+
+def _parse_mode(value: str | int) -> int:
+    """``"755"`` or TOML's ``0o755``."""
+    try:
+        mode = value if isinstance(value, int) else int(value, 8)
+    except ValueError:
+        raise ValueError(f"bad mode {value!r}, expected octal") from None
+    if not 0 <= mode <= 0o7777:
+        raise ValueError(f"bad mode {value!r}, expected octal")
+    return mode
+
+
+def render_modes(
+    templates: list[Template], variables: dict[str, str]
+) -> dict[str, int]:
+    """Modes for rendered paths that need one: sources with an executable
+    bit keep their mode, and a manifest's ``[modes]`` globs
+    (``"scripts/*.sh" = "755"``) override it. Later templates win."""
+    modes = {}
+    for template in templates:
+        overrides = template.manifest.get("modes", {})
+        for src, rel in _template_sources(template):
+            forced = [v for p, v in overrides.items() if _matches(rel, [p])]
+            try:
+                path = render_path(rel, variables, renderer(template))
+                mode = _parse_mode(forced[-1]) if forced else None
+            except ValueError as e:
+                sys.exit(f"Error: {template.name}: {e}")
+            if mode is None:
+                mode = src.stat().st_mode & 0o777
+            if forced or mode & 0o111:
+                modes[path] = mode
+            else:
+                modes.pop(path, None)
+    return modes
+
+
+def render_project(
+    template: Template,
+    addons: list[Template],
+    variables: dict[str, str],
+    stream: bool = False,
+) -> Files:
+    """Render TEMPLATE with each addon overlaid in order, plus the
+    .gitignore, .env, .envrc exports, .editorconfig sections, LICENSE,
+    task runner file and README (see ``_jolo.gitignore``,
+    ``_jolo.secret``, ``_jolo.envrc``, ``_jolo.editorconfig``,
+    ``_jolo.licenses``, ``_jolo.tasks``, ``_jolo.readme``). Secrets
+    VARIABLES lacks, then computed and script-derived variables, are
+    added first, ADDONS names the addons (``ADDONS has docker``) and, for
+    Go, GO_VERSION is the version the manifests require and MAIN_PKG
+    the main package, which the GO_CMDS answer may move to ``cmd/``
+    (see ``_jolo.golang``).
+    STREAM is as for render_template."""
+    addons = order_addons(addons)
+    templates = [template, *addons]
+    variables = {"ADDONS": ",".join(a.name for a in addons), **variables}
+    go = any(golang.is_go(t) for t in templates)
+    if go:
+        variables = {
+            "GO_VERSION": golang.go_version(templates),
+            "MAIN_PKG": golang.main_pkg(variables),
+            **variables,
+        }
+    variables = {**variables, **secret.generate(templates, variables)}
+    variables = {**variables, **computed_variables(templates, variables)}
+    variables = {**variables, **script_variables(templates, variables)}
+    files = dict(render_template(template, variables, stream))
+    for addon in addons:
+        files.update(render_template(addon, variables, stream))
+        for patch in addon.manifest.get("patches", []):
+            try:
+                if "when" in patch and not condition_holds(
+                    patch["when"], variables
+                ):
+                    continue
+            except ValueError as e:
+                sys.exit(f"Error: Addon {addon.name}: {e}")
+            rel = placeholders.render(patch["file"], variables)
+            if rel not in files:
+                sys.exit(f"Error: Addon {addon.name}: no file {rel} to patch")
+            if isinstance(files[rel], portable.Link):
+                files[rel] = files[rel].content
+            if isinstance(files[rel], streaming.Streamed):
+                files[rel] = files[rel].read()
+            if isinstance(files[rel], bytes):
+                sys.exit(f"Error: Addon {addon.name}: {rel} is binary")
+            try:
+                files[rel] = apply_patch(files[rel], patch, variables)
+            except ValueError as e:
+                sys.exit(f"Error: Addon {addon.name}: {rel}: {e}")
+    if go:
+        air = any("air" in t.manifest.get("requires", {}) for t in templates)
+        try:
+            files = golang.split_cmds(files, variables, air)
+        except ValueError as e:
+            sys.exit(f"Error: {template.name}: {e}")
+    rendered = gitignore.apply(
+        list(files.items()), [template, *addons], variables
+    )
+    rendered = secret.apply(rendered, templates, variables)
+    rendered = envrc.apply(rendered, templates, variables)
+    rendered = editorconfig.apply(rendered, templates)
+    rendered = licenses.apply(rendered, variables)
+    extra = golang.cmd_tasks(variables) if go else {}
+    rendered = tasks.apply(rendered, templates, variables, extra)
+    return readme.apply(rendered, [template, *addons], variables)
+
+
+def template_hooks(
+    templates: list[Template], stage: str, variables: dict[str, str]
+) -> list[str]:
+    return [
+        placeholders.render(cmd, variables)
+        for t in templates
+        for cmd in t.manifest.get("hooks", {}).get(stage, [])
+    ]
+
+
+def run_hooks(commands: list[str], cwd: Path, stage: str = "") -> None:
+    """Run each shell command in CWD; raise on the first failure. STAGE
+    (pre, post, git) is for the event stream."""
+    for cmd in commands:
+        log.info(f"==> {cmd}")
+        events.emit("hook", stage=stage, command=cmd, status="started")
+        # Under --json stdout is the event stream; hook output goes to
+        # stderr (fd 2) with the rest of the human-readable text.
+        out = 2 if events.enabled() else None
+        result = subprocess.run(cmd, shell=True, cwd=cwd, stdout=out)
+        status = "ok" if result.returncode == 0 else "failed"
+        events.emit(
+            "hook",
+            stage=stage,
+            command=cmd,
+            status=status,
+            exit=result.returncode,
+        )
+        if result.returncode != 0:
+            raise RuntimeError(
+                f"hook failed (exit {result.returncode}): {cmd}"
+            )
+
+
+def init_repo(
+    target: Path, message: str, branch: str = "", origin: str = ""
+) -> bool:
+    """``git init`` TARGET and commit all of it as MESSAGE; whether a
+    repository was made. Failures are warnings: the project is written
+    either way."""
+    inside = subprocess.run(
+        ["git", "rev-parse", "--is-inside-work-tree"],
+        cwd=target,
+        capture_output=True,
+        text=True,
+    )
+    if inside.stdout.strip() == "true":
+        verbose_print(f"{target} is already in a git work tree")
+        return False
+    init = ["git", "init", "-q"]
+    if branch:
+        init += ["--initial-branch", branch]
+    steps = [init]
+    if origin:
+        steps.append(["git", "remote", "add", "origin", origin])
+    steps += [["git", "add", "-A"], ["git", "commit", "-q", "-m", message]]
+    for cmd in steps:
+        verbose_cmd(cmd)
+        result = subprocess.run(
+            cmd, cwd=target, capture_output=True, text=True
+        )
+        if result.returncode != 0:
+            log.warning(f"{' '.join(cmd)} failed:\n{result.stderr.strip()}")
+            return False
+    return True
+
+
+def write_files(
+    files: Files, target: Path | Writer, modes: dict[str, int] | None = None
+) -> None:
+    """Write FILES below TARGET, a directory or a writer (an archive).
+    Files go to a directory in parallel, to an archive one by one."""
+    writer = DirWriter(target) if isinstance(target, Path) else target
+
+    def write(item: tuple[str, str | bytes]) -> None:
+        rel, content = item
+        writer.write(rel, content, (modes or {}).get(rel))
+        log.debug("wrote", path=rel)
+
+    if isinstance(writer, DirWriter):
+        parallel.pmap(write, files)
+    else:
+        for item in files:
+            write(item)
+
+
+def _size(content) -> int:
+    if isinstance(content, portable.Link):
+        return _size(content.content)
+    return streaming.size(content)
+
+
+def _same(content, path: Path) -> bool:
+    if isinstance(content, portable.Link):
+        if path.is_symlink():
+            return Path(os.readlink(path)).as_posix() == content.target
+        return _same(content.content, path)
+    if isinstance(content, streaming.Streamed):
+        return content.same_as(path)
+    data = content if isinstance(content, bytes) else content.encode()
+    return path.read_bytes() == data
+
+
+def plan_files(files: Files, target: Path) -> list[tuple[str, int, str]]:
+    """(path, size, action) per file; action is create, overwrite, or
+    unchanged depending on what is already at TARGET."""
+    plan = []
+    for rel, content in files:
+        path = target / rel
+        if not path.exists():
+            action = "create"
+        elif path.is_file() and _same(content, path):
+            action = "unchanged"
+        else:
+            action = "overwrite"
+        plan.append((rel, _size(content), action))
+    return plan
+
+
+def format_tree(root: str, plan: list[tuple[str, int, str]]) -> list[str]:
+    tree: dict = {}
+    for rel, size, action in plan:
+        *dirs, leaf = rel.split("/")
+        node = tree
+        for d in dirs:
+            node = node.setdefault(d + "/", {})
+        node[leaf] = (size, action)
+
+    rows = [(root, None)]
+
+    def walk(node: dict, prefix: str) -> None:
+        names = sorted(node)
+        for i, name in enumerate(names):
+            last = i == len(names) - 1
+            child = node[name]
+            label = prefix + ("└── " if last else "├── ") + name
+            if isinstance(child, dict):
+                rows.append((label, None))
+                walk(child, prefix + ("    " if last else "│   "))
+            else:
+                rows.append((label, child))
+
+    walk(tree, "")
+    width = max(len(label) for label, _ in rows)
+    lines = []
+    for label, info in rows:
+        if info is None:
+            lines.append(label)
+        else:
+            size, action = info
+            lines.append(f"{label:<{width}}  {_fmt_size(size):>8}  {action}")
+    return lines
+
+
+def print_plan(
+    target: Path,
+    files: Files,
+    pre: list[str],
+    post: list[str],
+    policy: str | None = None,
+) -> None:
+    plan = [
+        (rel, size, policy if action == "overwrite" and policy else action)
+        for rel, size, action in plan_files(files, target)
+    ]
+    for line in format_tree(f"{target}/", plan):
+        print(line)
+    counts = {}
+    for _, _, action in plan:
+        counts[action] = counts.get(action, 0) + 1
+    print()
+    print(", ".join(f"{n} {a}" for a, n in counts.items()) or "no files")
+    for stage, commands in (("pre", pre), ("post", post)):
+        for cmd in commands:
+            print(f"{stage}-hook: {cmd}")
+
+
+def _prompt_conflict(rel: str) -> str:
+    while True:
+        answer = _prompt_line(f"{rel} exists: [o]verwrite, [s]kip, [b]ackup")
+        if answer is None:
+            sys.exit("Aborted.")
+        for choice in ("overwrite", "skip", "backup"):
+            if answer and choice.startswith(answer.lower()):
+                return choice
+
+
+def decide_conflicts(
+    files: Files, target: Path, policy: str | None
+) -> list[tuple[str, str, str]]:
+    """(path, content, decision) per file, where decision is create,
+    unchanged, or what POLICY (asking, for prompt) makes of a conflict."""
+    decided = []
+    for (rel, content), (_, _, action) in zip(
+        files, plan_files(files, target)
+    ):
+        if action == "overwrite":
+            action = _prompt_conflict(rel) if policy == "prompt" else policy
+        decided.append((rel, content, action))
+    return decided
+
+
+def backup_file(path: Path) -> Path:
+    """Move PATH aside to PATH.orig (or .orig.N if that is taken)."""
+    backup = path.with_name(path.name + ".orig")
+    n = 1
+    while backup.exists():
+        backup = path.with_name(f"{path.name}.orig.{n}")
+        n += 1
+    path.rename(backup)
+    return backup
+
+
+def print_conflict_summary(decided: list[tuple[str, str, str]]) -> None:
+    for decision, label in (
+        ("skip", "Skipped"),
+        ("overwrite", "Overwrote"),
+        ("backup", "Backed up (.orig)"),
+    ):
+        paths = [rel for rel, _, d in decided if d == decision]
+        if paths:
+            log.summary(f"{label}: {', '.join(paths)}")
+
+
+def builtin_commit() -> str:
+    """Commit of the jolo checkout the builtin templates come from."""
+    return remote.head_commit(_TEMPLATES_DIR)
+
+
+def write_toml(path: Path, data: dict, header: str) -> None:
+    portable.write_text(path, toml_text(data, header))
+
+
+def _toml_value(value) -> str:
+    # JSON's \uXXXX escapes split characters past U+FFFF into surrogate
+    # pairs, which TOML rejects; so write them as they are, and escape
+    # DEL, the one control character JSON leaves alone.
+    return json.dumps(value, ensure_ascii=False).replace("\x7f", "\\u007f")
+
+
+def toml_text(data: dict, header: str) -> str:
+    """DATA (scalars, lists, one level of tables) as TOML; JSON scalars
+    and arrays are valid TOML values."""
+    lines = [f"# {header}"]
+    tables = {k: v for k, v in data.items() if isinstance(v, dict)}
+    for key, value in data.items():
+        if key not in tables:
+            lines.append(f"{key} = {_toml_value(value)}")
+    for key, table in tables.items():
+        lines.append(f"\n[{key}]")
+        for k, v in table.items():
+            lines.append(f"{_toml_value(k)} = {_toml_value(v)}")
+    return "\n".join(lines) + "\n"
+
+
+def lock_data(
+    template: Template,
+    addons: list[Template],
+    name: str,
+    answers: dict[str, str],
+    root: Path | None,
+    eol: str = "",
+) -> dict:
+    """The jolo.lock table; ROOT is the templates dir (None for git)."""
+    lock = {
+        "template": template.name,
+        "commit": template.commit,
+        "addons": [a.name for a in addons],
+        "name": name,
+        "variables": answers,
+    }
+    if eol:
+        lock["eol"] = eol
+    if template.version:
+        lock["version"] = template.version
+    if remote.is_remote(template.name):
+        lock["checksum"] = trust.content_hash(template.path)
+    if root and root != TEMPLATES_ROOT:
+        lock["templates_dir"] = str(root)
+    return lock
+
+
+def write_lock(target: Path | Writer, lock: dict) -> None:
+    text = toml_text(lock, "Written by jolo new, read by jolo upgrade.")
+    write_files([(LOCK_FILE, text)], target)
+
+
+def read_answers(path: str) -> dict:
+    try:
+        with open(path, "rb") as f:
+            return tomllib.load(f)
+    except FileNotFoundError:
+        sys.exit(f"Error: Answers file not found: {path}")
+    except tomllib.TOMLDecodeError as e:
+        sys.exit(f"Error: {path}: {e}")
+
+
+def read_lock(project: Path) -> dict:
+    path = project / LOCK_FILE
+    if not path.is_file():
+        sys.exit(f"Error: No {LOCK_FILE} in {project} (not made by jolo new)")
+    with open(path, "rb") as f:
+        return tomllib.load(f)
+
+
+def ejected_template(project: Path, lock: dict) -> tuple[Template, Path]:
+    """The template PROJECT's lock says ``jolo eject`` copied into it, as
+    it is now, and the addons dir beside it."""
+    root = project / EJECT_DIR
+    return find_template(lock["ejected"], root / "lang"), root / "addons"
+
+
+def _prompt_line(label: str) -> str | None:
+    """Read one answer from stdin; None on EOF/Ctrl-C."""
+    try:
+        return input(f"{label}: ").strip()
+    except (EOFError, KeyboardInterrupt):
+        print(file=sys.stderr)
+        return None
+
+
+def prompt_choice(
+    label: str, choices: list[tuple[str, str]], query: str = ""
+) -> str | None:
+    """Pick one of CHOICES ((value, description) pairs), the search
+    starting from QUERY.
+
+    fzf on a TTY, otherwise a numbered list answered by number or value;
+    any other answer narrows the list to what it fuzzily matches.
+    """
+    width = max(len(value) for value, _ in choices)
+    lines = {
+        value: f"{value:<{width}}  {desc}".rstrip() for value, desc in choices
+    }
+    if sys.stdin.isatty() and shutil.which("fzf"):
+        picked = _fzf_pick(f"{label}:", list(lines.values()), query)
+        return picked.split()[0] if picked else None
+
+    values = list(lines)
+    shown = fuzzy.matches(query, values) or values
+    while True:
+        for i, value in enumerate(shown, 1):
+            print(f"  {i}) {lines[value]}", file=sys.stderr)
+        while True:
+            answer = _prompt_line(label)
+            if not answer:
+                return None
+            if answer.isdigit() and 1 <= int(answer) <= len(shown):
+                return shown[int(answer) - 1]
+            if answer in values:
+                return answer
+            narrowed = fuzzy.matches(answer, values)
+            if len(narrowed) == 1:
+                return narrowed[0]
+            if narrowed:
+                break
+            print(f"  not one of the choices: {answer}", file=sys.stderr)
+        shown = narrowed
+
+
+def prompt_many(
+    label: str, choices: list[tuple[str, str]]
+) -> list[str] | None:
+    """Pick any of CHOICES ((value, description) pairs); None for no
+    answer.
+
+    fzf on a TTY (tab marks), otherwise a numbered list answered by
+    numbers or values, e.g. ``1,3`` or ``docker auth``.
+    """
+    width = max(len(value) for value, _ in choices)
+    lines = {
+        value: f"{value:<{width}}  {desc}".rstrip() for value, desc in choices
+    }
+    if sys.stdin.isatty() and shutil.which("fzf"):
+        picked = _fzf_pick(
+            f"{label} (tab to mark):", list(lines.values()), multi=True
+        )
+        if not picked:
+            return None
+        return [line.split()[0] for line in picked.splitlines()]
+
+    values = list(lines)
+    for i, value in enumerate(values, 1):
+        print(f"  {i}) {lines[value]}", file=sys.stderr)
+    while True:
+        answer = _prompt_line(label)
+        if answer is None:
+            sys.exit("Aborted.")
+        if not answer:
+            return None
+        picked, unknown = [], []
+        for word in answer.replace(",", " ").split():
+            if word.isdigit() and 1 <= int(word) <= len(values):
+                picked.append(values[int(word) - 1])
+            elif word in values:
+                picked.append(word)
+            else:
+                unknown.append(word)
+        if not unknown:
+            return picked
+        print(
+            f"  not one of the choices: {', '.join(unknown)}", file=sys.stderr
+        )
+
+
+def _template_choices(
+    preferred: list[str], roots: list[Path], alias: dict | None = None
+) -> list[tuple[str, str]]:
+    """Picker entries, with the config's preferred templates first and
+    ALIAS (name -> what it stands for) after the templates."""
+    builtin = {t.name: t.description for t in list_layered(roots)}
+    builtin.update((name, f"= {line}") for name, line in (alias or {}).items())
+    first = [
+        (name, "git source" if remote.is_remote(name) else builtin[name])
+        for name in preferred
+        if remote.is_remote(name) or name in builtin
+    ]
+    rest = [(n, d) for n, d in builtin.items() if n not in preferred]
+    return first + rest
+
+
+def _resolve_template(
+    args, stack: contextlib.ExitStack, new_cfg: dict
+) -> tuple[Template, Path | None]:
+    """The template to render, and the templates dir it came from (None
+    for git sources). A template alias fills in ARGS as it says."""
+    preferred = aliases.preferred(new_cfg)
+    alias = aliases.aliases(new_cfg)
+    roots = template_roots(args.templates_dir, new_cfg)
+    aliases.expand(args, new_cfg)
+    picked = args.template
+    if not picked and args.no_input:
+        sys.exit("Error: template required (--no-input)")
+    if not picked:
+        picked = prompt_choice(
+            "Template", _template_choices(preferred, roots, alias)
+        )
+    elif (
+        not remote.is_remote(picked)
+        and not args.no_input
+        and sys.stdin.isatty()
+        and not _known(picked, roots)
+    ):
+        print(f"Unknown template: {picked}", file=sys.stderr)
+        picked = prompt_choice(
+            "Template",
+            _template_choices(preferred, roots, alias),
+            picked.partition("@")[0],
+        )
+    if picked in alias:
+        args.template = picked
+        aliases.expand(args, new_cfg)
+        picked = args.template
+    if not picked:
+        sys.exit("No template selected, aborting.")
+
+    if remote.is_remote(picked):
+        picked = remote.pin(picked)
+        path = stack.enter_context(remote.fetched(picked))
+        if args.verify_signature or new_cfg.get("verify_signatures"):
+            trust.verify_signature(path, picked)
+        template = load_template(path, picked)
+        template.commit = remote.head_commit(path)
+        return template, None
+    template, root = find_pinned(picked, roots)
+    template.commit = remote.head_commit(root)
+    return template, root
+
+
+def _known(spec: str, roots: list[Path]) -> bool:
+    name = spec.partition("@")[0]
+    return any(_template_dir(root, name) for root in roots)
+
+
+def _prompt_variable(name: str, spec: dict) -> str:
+    label = spec.get("prompt", name)
+    default = spec.get("default")
+    if spec.get("description"):
+        print(f"  {spec['description']}", file=sys.stderr)
+
+    if spec.get("type") == "choice":
+        choices = [(str(c), "") for c in spec["choices"]]
+        picked = prompt_choice(label, choices)
+        if picked:
+            return picked
+        if default is None:
+            sys.exit(f"No {name} selected, aborting.")
+        return coerce_variable(spec, default)
+
+    if spec.get("type") == "multi":
+        choices = [(str(c), "") for c in spec["choices"]]
+        if default:
+            label += f" [{coerce_variable(spec, default)}]"
+        while True:
+            picked = prompt_many(label, choices)
+            if picked is None:
+                picked = [] if default is None else default
+            try:
+                return coerce_variable(spec, picked)
+            except ValueError as e:
+                print(f"  {e}", file=sys.stderr)
+
+    if spec.get("type") == "bool":
+        label += " (y/n)"
+    if default is not None:
+        label += f" [{coerce_variable(spec, default)}]"
+    while True:
+        answer = _prompt_line(label)
+        if answer is None:
+            sys.exit("Aborted.")
+        if not answer and default is not None:
+            return coerce_variable(spec, default)
+        try:
+            return coerce_variable(spec, answer)
+        except ValueError as e:
+            print(f"  {e}", file=sys.stderr)
+
+
+def resolve_variables(
+    template: Template,
+    given: dict[str, str],
+    no_input: bool,
+    defaults: dict | None = None,
+    context: dict | None = None,
+) -> dict[str, str]:
+    """Values for the manifest's variables, plus any extra GIVEN ones.
+
+    DEFAULTS (the config's ``[new.vars]``) replace manifest defaults. A
+    cookiecutter template's string defaults are rendered with CONTEXT
+    (the project variables) and the values so far. A variable whose
+    ``when`` condition is false for CONTEXT and the answers before it is
+    not asked for: it takes its default, or is empty, unless GIVEN.
+    """
+    given = dict(given)
+    values = {}
+    templated = template.manifest.get("engine") == "cookiecutter"
+    for name, spec in template.variables.items():
+        if defaults and name in defaults:
+            spec = {**spec, "default": defaults[name]}
+        elif templated and isinstance(spec.get("default"), str):
+            try:
+                default = cookiecutter.render(
+                    spec["default"], {**(context or {}), **values}
+                )
+            except ValueError as e:
+                sys.exit(f"Error: {template.name}: {name}: {e}")
+            spec = {**spec, "default": default}
+        if name not in given and not _wanted(
+            template, name, spec, {**(context or {}), **values}
+        ):
+            default = spec.get("default")
+            values[name] = "" if default is None else _coerce(spec, default)
+        elif name in given:
+            try:
+                values[name] = coerce_variable(spec, given.pop(name))
+            except ValueError as e:
+                sys.exit(f"Error: {name}: {e}")
+        elif no_input:
+            if "default" not in spec and spec.get("type") != "multi":
+                sys.exit(f"Error: {name} required (--no-input)")
+            default = spec.get("default", [])
+            try:
+                values[name] = coerce_variable(spec, default)
+            except ValueError as e:
+                sys.exit(f"Error: {name}: default {default!r}: {e}")
+        else:
+            values[name] = _prompt_variable(name, spec)
+    values.update(given)
+    return values
+
+
+def _wanted(
+    template: Template, name: str, spec: dict, answered: dict[str, str]
+) -> bool:
+    """Whether SPEC's ``when`` condition (if any) holds for ANSWERED."""
+    if "when" not in spec:
+        return True
+    try:
+        return condition_holds(spec["when"], answered)
+    except ValueError as e:
+        sys.exit(f"Error: {template.name}: {name}: when: {e}")
+
+
+def feature_addons(
+    template: Template,
+    given: dict[str, str],
+    no_input: bool,
+    defaults: dict | None = None,
+) -> list[str]:
+    """The addons picked by TEMPLATE's multi variables with ``addons``
+    tables, which are answered first (into GIVEN) for it."""
+    picked = []
+    for name, spec in template.variables.items():
+        if "addons" not in spec:
+            continue
+        if defaults and name in defaults:
+            spec = {**spec, "default": defaults[name]}
+        if name in given:
+            try:
+                value = coerce_variable(spec, given[name])
+            except ValueError as e:
+                sys.exit(f"Error: {name}: {e}")
+        elif no_input:
+            value = _coerce(spec, spec.get("default", []))
+        else:
+            value = _prompt_variable(name, spec)
+        given[name] = value
+        for choice in filter(None, value.split(",")):
+            if choice in spec["addons"]:
+                picked.append(spec["addons"][choice])
+    return picked
+
+
+def given_features(template: Template, features: list[str]) -> dict:
+    """``--features a,b`` as the answer to TEMPLATE's features."""
+    if not features:
+        return {}
+    if "features" not in template.variables:
+        sys.exit(f"Error: {template.name} has no features (--features)")
+    return {"features": ",".join(parse_with_args(features))}
+
+
+def _prompt_license() -> str:
+    picked = prompt_choice("License", list(licenses.LICENSES.items()))
+    return picked or "MIT"
+
+
+def check_module_path(templates: list[Template], path: str) -> None:
+    """Exit unless PATH is a valid module path, when TEMPLATES are Go."""
+    if any(golang.is_go(t) for t in templates):
+        try:
+            golang.check_module_path(path)
+        except ValueError as e:
+            sys.exit(f"Error: MODULE_PATH: {e}")
+
+
+def go_cmds(templates: list[Template], flags: list[str], given: dict) -> str:
+    """The GO_CMDS answer: the ``--cmd`` FLAGS, else one GIVEN (a --var
+    or replayed answer), checked; exits unless TEMPLATES are Go."""
+    names = flags or [c for c in given.pop("GO_CMDS", "").split(",") if c]
+    if not names:
+        return ""
+    if not any(golang.is_go(t) for t in templates):
+        sys.exit("Error: --cmd: not a Go template")
+    for name in names:
+        try:
+            golang.check_cmd(name)
+        except ValueError as e:
+            sys.exit(f"Error: --cmd: {e}")
+    if len(set(names)) != len(names):
+        sys.exit("Error: --cmd: a command is named twice")
+    return ",".join(names)
+
+
+def _resolve_module_path(
+    given: str | None,
+    default: str,
+    no_input: bool,
+    templates: list[Template] = (),
+) -> str:
+    """The Go module path: GIVEN, else asked for on a terminal, else
+    DEFAULT (from ``[new] module_prefix`` and the name)."""
+    if given is None and not no_input and sys.stdin.isatty():
+        while True:
+            answer = _prompt_line(f"Go module path [{default}]")
+            if answer is None:
+                sys.exit("Aborted.")
+            try:
+                golang.check_module_path(answer or default)
+                validation.check_builtin(
+                    templates, "MODULE_PATH", answer or default
+                )
+                return answer or default
+            except ValueError as e:
+                print(f"  {e}", file=sys.stderr)
+    return default if given is None else given
+
+
+def _prompt_description(templates: list[Template]) -> str:
+    while True:
+        answer = _prompt_line("Project description")
+        try:
+            validation.check_builtin(templates, "DESCRIPTION", answer or "")
+            return answer or ""
+        except ValueError as e:
+            if answer is None:
+                sys.exit(f"Error: DESCRIPTION: {e}")
+            print(f"  {e}", file=sys.stderr)
+
+
+def _resolve_name(args, templates: list[Template] = ()) -> str:
+    if args.name:
+        return args.name
+    if args.no_input:
+        sys.exit("Error: project name required (--no-input)")
+    while True:
+        name = _prompt_line("Project name")
+        if not name:
+            sys.exit("No project name given, aborting.")
+        try:
+            validation.check_builtin(
+                templates, "PROJECT_NAME", (Path.cwd() / name).resolve().name
+            )
+            return name
+        except ValueError as e:
+            print(f"  {e}", file=sys.stderr)
+
+
+def run_new_mode(args) -> None:
+    """Scaffold a template into ./NAME."""
+    if not args.json:
+        _new(args)
+        return
+    args.no_input = True
+    events.enable(sys.stdout)
+    try:
+        with contextlib.redirect_stdout(sys.stderr):
+            _new(args)
+    except SystemExit as e:
+        raise SystemExit(events.exit_status(e)) from None
+    except KeyboardInterrupt:
+        error = events.Failure("Interrupted", "interrupted")
+        raise SystemExit(events.exit_status(error)) from None
+    finally:
+        events.disable()
+
+
+def _summary(target: Path, template, actions: list[str]) -> None:
+    counts = {}
+    for action in actions:
+        counts[action] = counts.get(action, 0) + 1
+    events.emit(
+        "summary",
+        target=str(target),
+        template=template.name,
+        files=counts,
+        exit=events.EXIT["ok"],
+    )
+
+
+def _new(args) -> None:
+    new_cfg = load_config(profile=args.profile).get("new", {})
+    replay = read_answers(args.answers) if args.answers else {}
+    args.template = args.template or replay.get("template")
+    args.name = args.name or replay.get("name")
+    with contextlib.ExitStack() as stack:
+        template, root = _resolve_template(args, stack, new_cfg)
+        new_cfg = template_config(new_cfg, template.name)
+        given = {
+            **replay.get("variables", {}),
+            **parse_var_args(args.var),
+            **given_features(template, args.features),
+        }
+        for key, flag in (("AUTHOR", args.author), ("EMAIL", args.email)):
+            if flag is not None:
+                given[key] = flag
+        if args.with_:
+            addon_names = args.with_
+        elif "addons" in replay:
+            addon_names = replay["addons"]
+        else:
+            addon_names = new_cfg.get("addons", {}).get(template.name, [])
+        addon_names = [
+            *addon_names,
+            *feature_addons(
+                template, given, args.no_input, new_cfg.get("vars", {})
+            ),
+        ]
+        addons = [
+            find_addon(a, template) for a in parse_with_args(addon_names)
+        ]
+        if args.check:
+            toolchain.check_or_exit([template, *addons])
+        name = _resolve_name(args, [template, *addons])
+
+        target = (Path.cwd() / name).resolve()
+        events.emit(
+            "start",
+            template=template.name,
+            addons=[a.name for a in addons],
+            target=str(target),
+        )
+        existed = target.exists()
+        if existed and not (args.dry_run or args.on_conflict or args.output):
+            raise events.Failure(
+                f"Error: Directory already exists: {target} "
+                "(see --on-conflict)",
+                "conflict",
+            )
+        eol = args.eol or new_cfg.get("eol", "")
+        eol = portable.resolve_eol(eol) if eol else ""
+        if args.on_conflict == "prompt" and args.no_input:
+            sys.exit("Error: --on-conflict=prompt needs input (--no-input)")
+        runner = (
+            args.tasks
+            or given.pop("TASK_RUNNER", None)
+            or new_cfg.get("tasks")
+        )
+        if runner:
+            tasks.check_runner(runner)
+        repo_root = None if args.output else monorepo.find_root(target)
+        registration = None
+        if args.register:
+            if repo_root is None:
+                sys.exit(
+                    f"Error: --register: {target} is not in a repository"
+                )
+            registration = monorepo.registration(
+                repo_root, target, runner or "just"
+            )
+
+        templates = [template, *addons]
+        variables = project_variables(target.name)
+        answers = {
+            "UUID": str(uuid.uuid4()),
+            **config_variables(new_cfg, target.name),
+        }
+        # A --var or replayed LICENSE may name one jolo has no text for.
+        license = given.pop("LICENSE", None)
+        if args.license:
+            license = licenses.parse_license_arg(args.license)
+        elif license is None and "license" not in new_cfg:
+            if not args.no_input and sys.stdin.isatty():
+                license = _prompt_license()
+        if license is not None:
+            answers["LICENSE"] = licenses.spdx(license) or license
+        if runner:
+            answers["TASK_RUNNER"] = runner
+        if any(golang.is_go(t) for t in templates):
+            answers["MODULE_PATH"] = _resolve_module_path(
+                given.pop("MODULE_PATH", None),
+                answers["MODULE_PATH"],
+                args.no_input,
+                templates,
+            )
+            check_module_path(templates, answers["MODULE_PATH"])
+        if cmds := go_cmds(templates, args.cmd, given):
+            answers["GO_CMDS"] = cmds
+        if "DESCRIPTION" in given:
+            answers["DESCRIPTION"] = given.pop("DESCRIPTION")
+        elif not args.no_input and sys.stdin.isatty():
+            answers["DESCRIPTION"] = _prompt_description(templates)
+        validation.check_builtins(
+            templates, {"PROJECT_NAME": target.name, **answers}
+        )
+        for t in templates:
+            answers.update(
+                resolve_variables(
+                    t,
+                    given,
+                    args.no_input,
+                    new_cfg.get("vars", {}),
+                    context={**variables, **answers},
+                )
+            )
+        variables.update(answers)
+        if args.record:
+            write_toml(
+                Path(args.record),
+                {
+                    "template": template.name,
+                    "name": name,
+                    "addons": [a.name for a in addons],
+                    # A replay is a new project with a UUID of its own.
+                    "variables": {
+                        k: v for k, v in answers.items() if k != "UUID"
+                    },
+                },
+                "Answers recorded by jolo new; replay with --answers.",
+            )
+        files = render_project(template, addons, variables, stream=True)
+        if repo_root is not None:
+            files = editorconfig.nested(files)
+        if eol:
+            files = portable.apply_eol(files, eol)
+        pre = template_hooks(templates, "pre", variables)
+        post = template_hooks(templates, "post", variables)
+        if args.no_hooks:
+            pre = post = []
+        installs, checks = [], []
+        if new_cfg.get("verify", True) and not args.no_verify:
+            checks = smoke.commands(templates, variables)
+        if new_cfg.get("install", True) and not args.no_install:
+            installs = install.commands(templates, variables)
+        elif checks:
+            # They build against the dependencies the install brings in.
+            log.info("Skipping verify: the dependencies are not installed")
+            checks = []
+        if args.dry_run:
+            print_plan(target, files, pre, post, args.on_conflict)
+            for cmd in itertools.chain(*installs):
+                print(f"install: {cmd}")
+            for cmd in itertools.chain(*checks):
+                print(f"verify: {cmd}")
+            plan = plan_files(files, target)
+            for rel, size, action in plan:
+                events.emit("plan", path=rel, bytes=size, action=action)
+            _summary(target, template, [action for _, _, action in plan])
+            return
+        if args.output:
+            if pre or post:
+                log.info(
+                    "Skipping hooks: --output writes no directory to run "
+                    "them in"
+                )
+            with open_output(args.output, target.name) as writer:
+                write_files(files, writer, render_modes(templates, variables))
+                write_lock(
+                    writer,
+                    lock_data(
+                        template, addons, target.name, answers, root, eol
+                    ),
+                )
+            where = "stdout" if args.output == "-" else args.output
+            log.info(f"Wrote {template.name} project to {where}")
+            _summary(target, template, ["create"] * len(files))
+            return
+
+        decided = decide_conflicts(files, target, args.on_conflict)
+        for rel, _, decision in decided:
+            if decision in ("skip", "overwrite", "backup"):
+                events.emit("conflict", path=rel, decision=decision)
+        target.mkdir(parents=True, exist_ok=True)
+        try:
+            run_hooks(pre, target, "pre")
+            for rel, _, decision in decided:
+                if decision == "backup":
+                    backup_file(target / rel)
+            write_files(
+                [
+                    (rel, content)
+                    for rel, content, decision in decided
+                    if decision not in ("skip", "unchanged")
+                ],
+                target,
+                render_modes(templates, variables),
+            )
+            for rel, content, decision in decided:
+                if decision not in ("skip", "unchanged"):
+                    events.emit(
+                        "file",
+                        path=rel,
+                        bytes=_size(content),
+                        action=decision,
+                    )
+            write_lock(
+                target,
+                lock_data(template, addons, target.name, answers, root, eol),
+            )
+            run_hooks(post, target, "post")
+        except RuntimeError as e:
+            if existed:
+                raise events.Failure(f"Error: {e}", "hook")
+            shutil.rmtree(target)
+            raise events.Failure(f"Error: {e}; removed {target}", "hook")
+    notes = []
+    if repo_root is not None and (target / "go.mod").is_file():
+        notes.append(monorepo.add_to_go_work(repo_root, target))
+    if registration is not None:
+        path, text = registration
+        path.write_text(text, encoding="utf-8")
+        notes.append(f"registered {target.name} in {path}")
+    if installs:
+        steps = install.run(installs, target)
+        if checks and any(s.status == "fail" for s in steps):
+            log.info("Skipping verify: the install failed")
+            checks = []
+    git = new_cfg.get("git", True) if args.git is None else args.git
+    if git and not existed:
+        made = init_repo(
+            target,
+            f"scaffold {template.name} with jolo",
+            new_cfg.get("default_branch", ""),
+            args.remote or "",
+        )
+        if made and not args.no_hooks:
+            try:
+                run_hooks(
+                    template_hooks(templates, "git", variables), target, "git"
+                )
+            except RuntimeError as e:
+                log.warning(str(e))
+    elif args.remote:
+        log.warning("--remote needs a new git repository")
+    if checks and not smoke.passed(smoke.run(checks, target)):
+        raise events.Failure(
+            f"Error: {target} is written but fails its checks "
+            "(--no-verify skips them)",
+            "verify",
+        )
+    with_ = f" with {', '.join(a.name for a in addons)}" if addons else ""
+    log.summary(f"Created {template.name} project{with_}: {target}")
+    for note in filter(None, notes):
+        log.summary(f"  {note}")
+    print_conflict_summary(decided)
+    _summary(target, template, [decision for _, _, decision in decided])
+    if args.open is not None:
+        editor.open_project(target, args.open or new_cfg.get("editor", ""))
@@ -0,0 +1,152 @@
+"""Generated secrets: session keys, CSRF tokens and the like.
+
+A manifest names the secrets a project needs under ``[secrets]``:
+
+    [secrets]
+    SESSION_KEY = 32                                  # bytes, as hex
+    CSRF_SECRET = { bytes = 32, encoding = "base64" }
+
+Each is a fresh random value (``secrets.token_bytes``) in hex, base64 or
+base64url, rendered wherever ``{{SESSION_KEY}}`` appears and written to
+the project's ``.env`` as ``SESSION_KEY=...``, added to the template's
+own ``.env`` if it renders one. The ``.env`` is kept out of git: a
+``.gitignore`` that does not ignore it gets a ``.env`` line. Secrets are
+not answers, so neither ``jolo.lock`` nor ``--record`` holds them; ``jolo
+upgrade`` takes them back from ``.env``.
+
+``{{ secret 32 }}`` (``{{ secret 32 "base64" }}``) renders a one-off
+value in place, not kept anywhere. With a ``SECRET_SEED`` variable both
+forms are derived from it instead, the same on every render: ``jolo
+snapshot`` pins one, and ``jolo upgrade`` uses one per run so the two
+renders it merges agree.
+"""
+
+from __future__ import annotations
+
+import base64
+import fnmatch
+import hashlib
+import re
+import secrets
+import sys
+from pathlib import Path
+
+ENV_FILE = ".env"
+ENCODINGS = ("hex", "base64", "base64url")
+_NAME = re.compile(r"[A-Za-z_]\w*")
+
+
+def token(
+    nbytes: int, encoding: str = "hex", seed: str = "", key: str = ""
+) -> str:
+    """NBYTES random bytes in ENCODING; derived from SEED and KEY when a
+    seed is given."""
+    if seed:
+        raw = hashlib.shake_256(f"{seed}\0{key}".encode()).digest(nbytes)
+    else:
+        raw = secrets.token_bytes(nbytes)
+    if encoding == "hex":
+        return raw.hex()
+    if encoding == "base64":
+        return base64.b64encode(raw).decode()
+    if encoding == "base64url":
+        return base64.urlsafe_b64encode(raw).decode().rstrip("=")
+    raise ValueError(f"unknown encoding {encoding!r}")
+
+
+def _spec(template, name: str, value) -> tuple[int, str]:
+    where = f"Error: {template.name}: secrets.{name}"
+    if isinstance(value, int) and not isinstance(value, bool):
+        value = {"bytes": value}
+    if not isinstance(value, dict) or set(value) - {"bytes", "encoding"}:
+        sys.exit(f"{where}: expected BYTES or {{ bytes, encoding }}")
+    nbytes = value.get("bytes", 32)
+    encoding = value.get("encoding", "hex")
+    if not isinstance(nbytes, int) or isinstance(nbytes, bool) or nbytes < 1:
+        sys.exit(f"{where}: bytes must be a positive integer")
+    if encoding not in ENCODINGS:
+        sys.exit(f"{where}: encoding must be one of {', '.join(ENCODINGS)}")
+    return nbytes, encoding
+
+
+def declared(templates: list) -> dict[str, tuple[int, str]]:
+    """The manifests' secrets: name -> (bytes, encoding)."""
+    found = {}
+    for template in templates:
+        table = template.manifest.get("secrets", {})
+        if not isinstance(table, dict):
+            sys.exit(f"Error: {template.name}: [secrets] must be a table")
+        for name, value in table.items():
+            if not _NAME.fullmatch(name):
+                sys.exit(f"Error: {template.name}: bad secret name {name!r}")
+            found[name] = _spec(template, name, value)
+    return found
+
+
+def generate(templates: list, variables: dict[str, str]) -> dict[str, str]:
+    """A value for each declared secret VARIABLES does not already hold."""
+    seed = variables.get("SECRET_SEED", "")
+    return {
+        name: token(nbytes, encoding, seed, name)
+        for name, (nbytes, encoding) in declared(templates).items()
+        if name not in variables
+    }
+
+
+def parse_env(text: str) -> dict[str, str]:
+    """The ``NAME=VALUE`` lines of a .env file's TEXT."""
+    found = {}
+    for line in text.splitlines():
+        name, eq, value = line.strip().removeprefix("export ").partition("=")
+        if eq and _NAME.fullmatch(name.strip()):
+            found[name.strip()] = value.strip().strip("\"'")
+    return found
+
+
+def read_env(path: Path) -> dict[str, str]:
+    if not path.is_file():
+        return {}
+    return parse_env(path.read_text(encoding="utf-8"))
+
+
+def _ignores_env(gitignore: str) -> bool:
+    ignored = False
+    for line in gitignore.splitlines():
+        line = line.strip()
+        negated = line.startswith("!")
+        pattern = line.lstrip("!").removeprefix("/").rstrip("/")
+        if line and not line.startswith("#"):
+            if fnmatch.fnmatchcase(ENV_FILE, pattern):
+                ignored = not negated
+    return ignored
+
+
+def _with_env(env: str, names, variables: dict[str, str]) -> str:
+    have = parse_env(env)
+    missing = [f"{n}={variables[n]}\n" for n in names if n not in have]
+    if env and not env.endswith("\n") and missing:
+        env += "\n"
+    return env + "".join(missing)
+
+
+def apply(
+    files: list[tuple[str, str | bytes]],
+    templates: list,
+    variables: dict[str, str],
+) -> list[tuple[str, str | bytes]]:
+    """FILES with the declared secrets in ``.env``, and ``.env`` in
+    ``.gitignore``."""
+    names = list(declared(templates))
+    if not names:
+        return files
+    rendered = dict(files)
+    env = rendered.get(ENV_FILE, "")
+    if not isinstance(env, str):
+        sys.exit(f"Error: {ENV_FILE} is not text; cannot add secrets to it")
+    rendered[ENV_FILE] = _with_env(env, names, variables)
+    ignore = rendered.get(".gitignore", "")
+    if isinstance(ignore, str) and not _ignores_env(ignore):
+        if ignore and not ignore.endswith("\n"):
+            ignore += "\n"
+        rendered[".gitignore"] = f"{ignore}{ENV_FILE}\n"
+    return list(rendered.items())
@@ -0,0 +1,46 @@
+"""Template versions and the pins ``jolo new NAME@PIN`` accepts.
+
+A version is ``MAJOR.MINOR.PATCH`` (a leading ``v``, as in git tags, is
+allowed; pre-releases are not versions here). A pin is a version, a
+prefix of one (``1.2`` is any 1.2.x), ``^1.2`` (>= 1.2.0, same major;
+same minor below 1.0) or ``~1.2`` (>= 1.2.0, same minor).
+"""
+
+from __future__ import annotations
+
+import re
+
+_VERSION = re.compile(r"v?(\d+)\.(\d+)\.(\d+)")
+_PIN = re.compile(r"([\^~]?)v?(\d+)(?:\.(\d+))?(?:\.(\d+))?")
+
+
+def parse(text: str) -> tuple[int, int, int] | None:
+    match = _VERSION.fullmatch(str(text).strip())
+    return tuple(int(n) for n in match.groups()) if match else None
+
+
+def is_pin(ref: str) -> bool:
+    return bool(_PIN.fullmatch(ref))
+
+
+def satisfies(version: str, pin: str) -> bool:
+    have = parse(version)
+    match = _PIN.fullmatch(pin)
+    if not have or not match:
+        return False
+    op = match[1]
+    want = [int(n) for n in match.groups()[1:] if n is not None]
+    if not op:
+        return list(have[: len(want)]) == want
+    low = tuple(want + [0] * (3 - len(want)))
+    if have < low:
+        return False
+    if op == "~" or (op == "^" and want[0] == 0 and len(want) > 1):
+        return have[: min(len(want), 2)] == low[: min(len(want), 2)]
+    return have[0] == low[0]
+
+
+def best(versions: list[str], pin: str) -> str | None:
+    """The highest of VERSIONS that satisfies PIN."""
+    matching = [v for v in versions if satisfies(v, pin)]
+    return max(matching, key=parse, default=None)
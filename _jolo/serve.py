@@ -0,0 +1,172 @@
+"""jolo serve — scaffold-as-a-service over HTTP.
+
+    GET  /templates   [{"name", "description", "tags", "variables"}, ...]
+    POST /generate    {"template": "go/web", "name": "shop",
+                       "variables": {...}, "addons": ["docker"],
+                       "format": "zip" | "tar.gz"}
+
+``/generate`` answers with the project as an archive (everything under
+``NAME/``, jolo.lock included, executable bits kept) or, for a bad
+request, a 4xx with ``{"error": "..."}``. Only named templates from the
+builtins and the configured templates dirs are served: no git sources,
+and template hooks never run, so a request cannot make the server fetch
+or execute anything.
+"""
+
+from __future__ import annotations
+
+import io
+import json
+import re
+import sys
+import tempfile
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+from pathlib import Path
+
+from _jolo import api, remote, scaffold
+from _jolo.commands import load_config
+from _jolo.writers import archive_writer
+
+MAX_BODY = 1 << 20
+FORMATS = {"zip": "application/zip", "tar.gz": "application/gzip"}
+_NAME = re.compile(r"[A-Za-z0-9][A-Za-z0-9._-]*")
+
+
+class RequestError(Exception):
+    def __init__(self, status: int, message: str):
+        super().__init__(message)
+        self.status = status
+
+
+def archive(root: Path, fmt: str) -> bytes:
+    """ROOT as a FMT archive whose entries start with ROOT's name."""
+    buf = io.BytesIO()
+    with archive_writer(buf, root.name, fmt) as writer:
+        for path in sorted(p for p in root.rglob("*") if p.is_file()):
+            writer.write(
+                path.relative_to(root).as_posix(),
+                path.read_bytes(),
+                path.stat().st_mode & 0o777,
+            )
+    return buf.getvalue()
+
+
+def generate(request: dict, templates_dirs: list[str]) -> tuple[str, bytes]:
+    """(format, archive) for a /generate REQUEST body."""
+    template_name = request.get("template")
+    name = request.get("name")
+    variables = request.get("variables", {})
+    addons = request.get("addons", [])
+    fmt = request.get("format", "zip")
+    if not isinstance(template_name, str) or not template_name:
+        raise RequestError(400, "template required")
+    if remote.is_remote(template_name):
+        raise RequestError(400, "git sources are not served")
+    if not scaffold.plain_name(template_name.partition("@")[0]):
+        raise RequestError(400, "template must be a template name")
+    if not isinstance(name, str) or not _NAME.fullmatch(name):
+        raise RequestError(400, "name must be a plain directory name")
+    if not isinstance(variables, dict):
+        raise RequestError(400, "variables must be an object")
+    if not isinstance(addons, list):
+        raise RequestError(400, "addons must be a list")
+    if fmt not in FORMATS:
+        raise RequestError(400, f"format must be one of {', '.join(FORMATS)}")
+
+    try:
+        with (
+            api.open_template(template_name, templates_dirs) as template,
+            tempfile.TemporaryDirectory(prefix="jolo-serve-") as tmp,
+        ):
+            dest = Path(tmp) / name
+            api.generate(template, dest, variables, addons)
+            return fmt, archive(dest, fmt)
+    except api.ScaffoldError as e:
+        status = 404 if str(e).startswith("Unknown template") else 400
+        raise RequestError(status, str(e)) from None
+
+
+def list_templates(templates_dirs: list[str]) -> list[dict]:
+    return [api.describe(t) for t in api.list_templates(templates_dirs)]
+
+
+def make_handler(templates_dirs: list[str]) -> type[BaseHTTPRequestHandler]:
+    class Handler(BaseHTTPRequestHandler):
+        server_version = "jolo"
+
+        def _send(self, status: int, body: bytes, content_type: str, **hdr):
+            self.send_response(status)
+            self.send_header("Content-Type", content_type)
+            self.send_header("Content-Length", str(len(body)))
+            for key, value in hdr.items():
+                self.send_header(key.replace("_", "-"), value)
+            self.end_headers()
+            self.wfile.write(body)
+
+        def _json(self, status: int, data) -> None:
+            body = json.dumps(data, indent=2).encode() + b"\n"
+            self._send(status, body, "application/json")
+
+        def do_GET(self) -> None:
+            if self.path.split("?")[0] != "/templates":
+                self._json(404, {"error": f"no such endpoint: {self.path}"})
+                return
+            try:
+                self._json(200, list_templates(templates_dirs))
+            except api.ScaffoldError as e:
+                self._json(500, {"error": str(e)})
+
+        def do_POST(self) -> None:
+            if self.path.split("?")[0] != "/generate":
+                self._json(404, {"error": f"no such endpoint: {self.path}"})
+                return
+            try:
+                length = int(self.headers.get("Content-Length") or 0)
+                if length > MAX_BODY:
+                    raise RequestError(413, "request body too large")
+                try:
+                    request = json.loads(self.rfile.read(length) or b"{}")
+                except json.JSONDecodeError as e:
+                    raise RequestError(400, f"invalid JSON: {e}") from None
+                if not isinstance(request, dict):
+                    raise RequestError(400, "body must be a JSON object")
+                fmt, body = generate(request, templates_dirs)
+            except RequestError as e:
+                self._json(e.status, {"error": str(e)})
+                return
+            filename = f"{request['name']}.{fmt}"
+            self._send(
+                200,
+                body,
+                FORMATS[fmt],
+                Content_Disposition=f'attachment; filename="{filename}"',
+            )
+
+        def log_message(self, format: str, *args) -> None:
+            print(f"{self.address_string()} {format % args}", file=sys.stderr)
+
+    return Handler
+
+
+def run_serve_mode(args) -> None:
+    """Serve /templates and /generate until interrupted."""
+    new_cfg = load_config().get("new", {})
+    templates_dirs = [
+        *args.templates_dir,
+        *new_cfg.get("templates_dirs", []),
+    ]
+    try:
+        api.list_templates(templates_dirs)
+    except api.ScaffoldError as e:
+        sys.exit(f"Error: {e}")
+    server = ThreadingHTTPServer(
+        (args.host, args.port), make_handler(templates_dirs)
+    )
+    host, port = server.server_address[:2]
+    print(f"Serving jolo templates on http://{host}:{port}", file=sys.stderr)
+    try:
+        server.serve_forever()
+    except KeyboardInterrupt:
+        pass
+    finally:
+        server.server_close()
@@ -0,0 +1,292 @@
+"""jolo config — read and write settings without editing TOML by hand.
+
+    jolo config [list]            every setting in effect, KEY = VALUE
+    jolo config get KEY           one setting (a table: all below it)
+    jolo config set KEY VALUE     write KEY to the config file
+    jolo config unset KEY         remove KEY from the config file
+
+KEY is a dotted path, ``new.module_prefix`` or ``new.vars.go_version``;
+a bare ``jolo new`` setting (``author``, ``license``, ...) is short for
+``new.KEY``. VALUE is read as TOML when it is TOML (``true``, ``4000``,
+``["go/web"]``) and as a string otherwise, so ``jolo config set author
+"T. Bergheim"`` needs no inner quotes.
+
+``set`` and ``unset`` write ``~/.config/jolo/config.toml``, or the
+current directory's ``.jolo.toml`` with ``--project``; ``--profile
+NAME`` writes under ``[profiles.NAME]``, and makes ``get`` and ``list``
+show what that profile sees. The file is edited line by line, so its
+comments and layout stay; a key it cannot edit that way (a value
+spanning lines, a whole ``[table]``, or a key inside an inline table) is
+an error, left for editing by hand.
+"""
+
+from __future__ import annotations
+
+import copy
+import json
+import re
+import sys
+import tomllib
+from pathlib import Path
+
+from _jolo.commands import load_config
+
+NEW_KEYS = (
+    "addons",
+    "aliases",
+    "author",
+    "default_branch",
+    "editor",
+    "email",
+    "eol",
+    "favorites",
+    "git",
+    "install",
+    "license",
+    "module_prefix",
+    "tasks",
+    "templates",
+    "templates_dirs",
+    "vars",
+    "verify_signatures",
+)
+_BARE = re.compile(r"[A-Za-z0-9_-]+")
+
+
+def config_file(project: bool) -> Path:
+    if project:
+        return Path.cwd() / ".jolo.toml"
+    return Path.home() / ".config" / "jolo" / "config.toml"
+
+
+def key_path(key: str, profile: str | None = None) -> list[str]:
+    """KEY as table keys, under ``profiles.PROFILE`` if given."""
+    path = key.split(".")
+    if not key or not all(path):
+        sys.exit(f"Error: bad key {key!r}")
+    if len(path) == 1 and key in NEW_KEYS:
+        path = ["new", key]
+    return ["profiles", profile, *path] if profile else path
+
+
+def parse_value(text: str):
+    """TEXT as a TOML value, or as a string if it is not one."""
+    try:
+        return tomllib.loads(f"v = {text}")["v"]
+    except tomllib.TOMLDecodeError:
+        return text
+
+
+def _string(text: str) -> str:
+    # ASCII escapes would write characters past U+FFFF as surrogate
+    # pairs, which TOML rejects; DEL is the control character JSON keeps.
+    return json.dumps(text, ensure_ascii=False).replace("\x7f", "\\u007f")
+
+
+def _key(name: str) -> str:
+    return name if _BARE.fullmatch(name) else _string(name)
+
+
+def format_value(value) -> str:
+    """VALUE as TOML."""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, dict):
+        items = [f"{_key(k)} = {format_value(v)}" for k, v in value.items()]
+        return f"{{ {', '.join(items)} }}" if items else "{}"
+    if isinstance(value, list):
+        return "[" + ", ".join(format_value(v) for v in value) + "]"
+    if isinstance(value, str):
+        return _string(value)
+    return str(value)
+
+
+def flatten(data: dict, prefix: str = "") -> list[tuple[str, object]]:
+    """(dotted key, value) for every non-table value in DATA."""
+    found = []
+    for name, value in data.items():
+        key = prefix + _key(name)
+        if isinstance(value, dict) and value:
+            found += flatten(value, key + ".")
+        else:
+            found.append((key, value))
+    return found
+
+
+def _header(line: str) -> list[str] | None:
+    """The table path a ``[a.b]`` line opens, [] for ``[[a]]``, None for
+    any other line."""
+    stripped = line.strip()
+    if not stripped.startswith("["):
+        return None
+    if stripped.startswith("[["):
+        return []
+    try:
+        data = tomllib.loads(stripped)
+    except tomllib.TOMLDecodeError:
+        return None
+    path = []
+    while len(data) == 1 and isinstance(next(iter(data.values())), dict):
+        name, data = next(iter(data.items()))
+        path.append(name)
+    return path
+
+
+def _section(lines: list[str], table: list[str]) -> tuple[int, int] | None:
+    """The line range holding TABLE's keys: from after its header (or
+    the file's start, for the top level) to the next header."""
+    start = 0 if not table else None
+    for i, line in enumerate(lines):
+        opened = _header(line)
+        if opened is None:
+            continue
+        if start is not None:
+            return start, i
+        if opened == table:
+            start = i + 1
+    return None if start is None else (start, len(lines))
+
+
+def _line_key(line: str) -> str | None:
+    """The key a one-line ``key = value`` sets, if LINE is one."""
+    try:
+        data = tomllib.loads(line)
+    except tomllib.TOMLDecodeError:
+        return None
+    if len(data) != 1:
+        return None
+    name, value = next(iter(data.items()))
+    # A dotted ``a.b = 1`` sets b, not all of a.
+    if isinstance(value, dict) and not _inline(line):
+        return None
+    return name
+
+
+def _inline(line: str) -> bool:
+    return line.split("=", 1)[1].lstrip().startswith("{")
+
+
+def _comment(line: str) -> str:
+    """The ``# ...`` ending the one-line ``key = value`` LINE, if any,
+    with the space before it."""
+    whole = tomllib.loads(line)
+    for at, char in enumerate(line):
+        if char != "#":
+            continue
+        try:
+            if tomllib.loads(line[:at]) == whole:
+                body = line[:at].rstrip()
+                return line[len(body) :].rstrip("\n")
+        except tomllib.TOMLDecodeError:
+            continue
+    return ""
+
+
+def edit(text: str, path: list[str], value=None, remove: bool = False) -> str:
+    """TEXT with PATH set to VALUE, or removed."""
+    lines = text.splitlines(keepends=True)
+    table, leaf = path[:-1], path[-1]
+    where = _section(lines, table)
+    line = f"{_key(leaf)} = {format_value(value)}\n"
+    if where is None:
+        if remove:
+            return text
+        header = ".".join(_key(name) for name in table)
+        gap = "\n" if text.strip() else ""
+        if text and not text.endswith("\n"):
+            text += "\n"
+        return f"{text}{gap}[{header}]\n{line}"
+    start, end = where
+    for i in range(start, end):
+        if _line_key(lines[i]) == leaf:
+            kept = line[:-1] + _comment(lines[i]) + "\n"
+            lines[i : i + 1] = [] if remove else [kept]
+            return "".join(lines)
+    if remove:
+        return text
+    at = end
+    while at > start and not lines[at - 1].strip():
+        at -= 1
+    if at > 0 and not lines[at - 1].endswith("\n"):
+        lines[at - 1] += "\n"
+    lines.insert(at, line)
+    return "".join(lines)
+
+
+def _changed(data: dict, path: list[str], value=None, remove=False):
+    """A copy of DATA with PATH set to VALUE, or removed."""
+    data = copy.deepcopy(data)
+    table = data
+    for i, name in enumerate(path[:-1]):
+        table = table.setdefault(name, {})
+        if not isinstance(table, dict):
+            sys.exit(f"Error: {'.'.join(path[: i + 1])} is not a table")
+    if remove:
+        table.pop(path[-1], None)
+    else:
+        table[path[-1]] = value
+    return data
+
+
+def write(file: Path, path: list[str], value=None, remove=False) -> bool:
+    """Set PATH to VALUE in FILE, or remove it; False if it was not
+    there to remove."""
+    text = file.read_text(encoding="utf-8") if file.exists() else ""
+    try:
+        data = tomllib.loads(text)
+    except tomllib.TOMLDecodeError as e:
+        sys.exit(f"Error: {file}: {e}")
+    key = ".".join(path)
+    if remove:
+        table = data
+        for name in path[:-1]:
+            table = table.get(name, {}) if isinstance(table, dict) else {}
+        if not isinstance(table, dict) or path[-1] not in table:
+            return False
+    want = _changed(data, path, value, remove)
+    edited = edit(text, path, value, remove)
+    try:
+        got = tomllib.loads(edited)
+    except tomllib.TOMLDecodeError:
+        got = None
+    if got != want:
+        sys.exit(f"Error: cannot edit {key} in {file}; edit it by hand")
+    file.parent.mkdir(parents=True, exist_ok=True)
+    file.write_text(edited, encoding="utf-8")
+    return True
+
+
+def _lookup(config: dict, path: list[str]):
+    value = config
+    for name in path:
+        if not isinstance(value, dict) or name not in value:
+            return None
+        value = value[name]
+    return value
+
+
+def run_config_mode(args) -> None:
+    if args.action in ("set", "unset"):
+        file = config_file(args.project)
+        path = key_path(args.key, args.profile)
+        if args.action == "set":
+            write(file, path, parse_value(args.value))
+            return
+        if not write(file, path, remove=True):
+            sys.exit(f"Error: {'.'.join(path)} is not set in {file}")
+        return
+
+    config = load_config(profile=args.profile)
+    if args.action in (None, "list"):
+        found = flatten(config)
+    else:
+        path = key_path(args.key)
+        value = _lookup(config, path)
+        if value is None:
+            sys.exit(f"Error: {args.key} is not set")
+        if not isinstance(value, dict):
+            print(value if isinstance(value, str) else format_value(value))
+            return
+        found = flatten(value, ".".join(_key(name) for name in path) + ".")
+    for key, value in found:
+        print(f"{key} = {format_value(value)}")
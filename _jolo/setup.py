@@ -11,7 +11,7 @@ import urllib.error
 import urllib.request
 from pathlib import Path
 
-from _jolo import constants
+from _jolo import constants, log
 from _jolo.cli import (
     detect_flavors,
     read_port_from_devcontainer,
@@ -158,10 +158,7 @@ def merge_mcp_configs(target_config: dict, mcp_templates_dir: Path) -> dict:
             if "mcpServers" in mcp_data:
                 mcp_servers.update(mcp_data["mcpServers"])
         except Exception as e:
-            print(
-                f"Warning: Failed to load MCP template {mcp_file}: {e}",
-                file=sys.stderr,
-            )
+            log.warning(f"Failed to load MCP template {mcp_file}: {e}")
 
     return target_config
 
@@ -240,9 +237,8 @@ def setup_credential_cache(
 
             write_json(claude_json_dst, claude_config, newline=False)
         except Exception as e:
-            print(
-                f"Warning: Failed to inject MCP configs into .claude.json: {e}",
-                file=sys.stderr,
+            log.warning(
+                f"Failed to inject MCP configs into .claude.json: {e}"
             )
 
     # Gemini credentials
@@ -424,9 +420,8 @@ def setup_credential_cache(
 
             codex_config_path.write_text("\n".join(toml_lines) + "\n")
     except Exception as e:
-        print(
-            f"Warning: Failed to inject MCP configs into Codex config.toml: {e}",
-            file=sys.stderr,
+        log.warning(
+            f"Failed to inject MCP configs into Codex config.toml: {e}"
         )
 
     # Pi credentials
@@ -698,9 +693,8 @@ def _fetch_llama_model_ids(llama_host: str) -> list[str]:
         urllib.error.URLError,
         json.JSONDecodeError,
     ) as e:
-        print(
-            f"Warning: Failed to fetch llama-swap models from {models_url}: {e}",
-            file=sys.stderr,
+        log.warning(
+            f"Failed to fetch llama-swap models from {models_url}: {e}"
         )
         return []
 
@@ -1354,10 +1348,7 @@ def copy_template_files(target_dir: Path) -> None:
     templates_dir = Path(__file__).resolve().parent.parent / "templates"
 
     if not templates_dir.exists():
-        print(
-            f"Warning: Templates directory not found: {templates_dir}",
-            file=sys.stderr,
-        )
+        log.warning(f"Templates directory not found: {templates_dir}")
         return
 
     template_files = [
@@ -1406,10 +1397,7 @@ def ensure_test_gate_script(target_dir: Path) -> None:
     templates_dir = Path(__file__).resolve().parent.parent / "templates"
     src = templates_dir / "scripts" / "test-gate"
     if not src.exists():
-        print(
-            f"Warning: test-gate template not found: {src}",
-            file=sys.stderr,
-        )
+        log.warning(f"test-gate template not found: {src}")
         return
 
     dst = target_dir / "scripts" / "test-gate"
@@ -1429,10 +1417,7 @@ def ensure_lighthouse_run_script(target_dir: Path, flavor: str) -> None:
     templates_dir = Path(__file__).resolve().parent.parent / "templates"
     src = templates_dir / "scripts" / "lighthouse-run"
     if not src.exists():
-        print(
-            f"Warning: lighthouse-run template not found: {src}",
-            file=sys.stderr,
-        )
+        log.warning(f"lighthouse-run template not found: {src}")
         return
 
     dst = target_dir / "scripts" / "lighthouse-run"
@@ -1698,10 +1683,7 @@ def _litellm_generate_key(
         urllib.error.URLError,
         json.JSONDecodeError,
     ) as e:
-        print(
-            f"Warning: LiteLLM key mint failed for {project_name}: {e}",
-            file=sys.stderr,
-        )
+        log.warning(f"LiteLLM key mint failed for {project_name}: {e}")
         return None
     key = data.get("key")
     return key if isinstance(key, str) and key else None
@@ -0,0 +1,170 @@
+"""jolo show — what a template renders, before ``jolo new`` writes it.
+
+``jolo show TEMPLATE`` prints the template's description, the file tree
+it renders (sizes included), its variables with their types and
+defaults, its computed variables, and the addons it takes. ``jolo show
+TEMPLATE FILE`` prints one rendered file to stdout instead; FILE is the
+path as it lands in the project (``cmd/demo/main.go``, not
+``cmd/{{PROJECT_NAME}}/...``).
+
+Rendering works as for ``jolo new --no-input``: ``--var KEY=VALUE``
+answers a variable, the rest take their defaults, ``--with`` (or
+``--features``) adds addons and ``--tasks`` picks the task runner. The
+project is called ``demo`` unless ``--var PROJECT_NAME=...`` says
+otherwise. Nothing is written and no hooks run.
+"""
+
+from __future__ import annotations
+
+import contextlib
+import sys
+import uuid
+
+from _jolo import fuzzy, tasks, validation
+from _jolo.commands import load_config
+from _jolo.scaffold import (
+    CONFIG_VARIABLES,
+    Template,
+    _resolve_template,
+    addon_names,
+    config_variables,
+    feature_addons,
+    find_addon,
+    format_tree,
+    given_features,
+    go_cmds,
+    parse_var_args,
+    parse_with_args,
+    project_variables,
+    render_project,
+    resolve_variables,
+    template_config,
+)
+
+DEFAULT_NAME = "demo"
+
+
+def _render(args, new_cfg: dict, template: Template):
+    given = {
+        **parse_var_args(args.var),
+        **given_features(template, args.features),
+    }
+    name = given.pop("PROJECT_NAME", DEFAULT_NAME)
+    picked = [
+        *args.with_,
+        *feature_addons(template, given, True, new_cfg.get("vars", {})),
+    ]
+    addons = [find_addon(a, template) for a in parse_with_args(picked)]
+    variables = project_variables(name)
+    answers = {"UUID": str(uuid.uuid4()), **config_variables(new_cfg, name)}
+    for key in (*CONFIG_VARIABLES, "DESCRIPTION"):
+        if key in given:
+            answers[key] = given.pop(key)
+    runner = (
+        args.tasks or given.pop("TASK_RUNNER", None) or new_cfg.get("tasks")
+    )
+    if runner:
+        answers["TASK_RUNNER"] = tasks.check_runner(runner)
+    if cmds := go_cmds([template, *addons], args.cmd, given):
+        answers["GO_CMDS"] = cmds
+    validation.check_builtins([template, *addons], {**variables, **answers})
+    for t in (template, *addons):
+        answers.update(
+            resolve_variables(
+                t,
+                given,
+                True,
+                new_cfg.get("vars", {}),
+                context={**variables, **answers},
+            )
+        )
+    variables.update(answers)
+    return name, render_project(template, addons, variables)
+
+
+def _about(spec: dict) -> str:
+    about = spec.get("description", "")
+    if "when" in spec:
+        about = f"{about} (when {spec['when']})".lstrip()
+    return about
+
+
+def _default(value) -> str:
+    if value is None:
+        return ""
+    if isinstance(value, list):  # a multi variable's
+        return ",".join(map(str, value))
+    return str(value)
+
+
+def format_variables(template: Template) -> list[str]:
+    """One aligned line per variable: name, type, default, description."""
+    rows = [
+        (
+            name,
+            spec.get("type", "string"),
+            _default(spec.get("default")),
+            _about(spec),
+        )
+        for name, spec in template.variables.items()
+    ]
+    if not rows:
+        return []
+    widths = [max(len(row[i]) for row in rows) for i in range(4)]
+    return [
+        "  " + "  ".join(f"{c:<{w}}" for c, w in zip(row, widths)).rstrip()
+        for row in rows
+    ]
+
+
+def print_template(template: Template, name: str, files) -> None:
+    version = f" {template.version}" if template.version else ""
+    header = f"{template.name}{version}"
+    if template.description:
+        header += f": {template.description}"
+    print(header)
+    print()
+    plan = [(rel, len(_bytes(content)), "") for rel, content in files]
+    for line in format_tree(f"{name}/", plan):
+        print(line.rstrip())
+    variables = format_variables(template)
+    if variables:
+        print()
+        print("Variables:")
+        for line in variables:
+            print(line)
+    computed = template.manifest.get("computed", {})
+    if computed:
+        width = max(map(len, computed))
+        print()
+        print("Computed:")
+        for name, source in computed.items():
+            print(f"  {name:<{width}}  {source}")
+    addons = addon_names(template)
+    if addons:
+        print()
+        print(f"Addons: {', '.join(addons)}")
+
+
+def _bytes(content: str | bytes) -> bytes:
+    return content.encode() if isinstance(content, str) else content
+
+
+def run_show_mode(args) -> None:
+    new_cfg = load_config(profile=args.profile).get("new", {})
+    args.no_input = True
+    with contextlib.ExitStack() as stack:
+        template, _ = _resolve_template(args, stack, new_cfg)
+        new_cfg = template_config(new_cfg, template.name)
+        name, files = _render(args, new_cfg, template)
+    if args.file is None:
+        print_template(template, name, files)
+        return
+    rendered = dict(files)
+    rel = args.file.removeprefix("./")
+    if rel not in rendered:
+        hint = fuzzy.did_you_mean(rel, list(rendered))
+        sys.exit(f"Error: {template.name} renders no {rel}{hint}")
+    sys.stdout.flush()
+    sys.stdout.buffer.write(_bytes(rendered[rel]))
+    sys.stdout.buffer.flush()
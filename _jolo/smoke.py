@@ -0,0 +1,80 @@
+"""Check that a new project builds once ``jolo new`` has written it.
+
+The commands under a manifest's ``[verify] run`` (``go vet ./...``,
+``cargo check``, ``npm run build``), the ones ``jolo verify`` runs over
+every template, also run in each project made from it. They run after
+the dependencies are installed and the first commit is made, so what
+they build stays out of it, and print a line each as the install
+commands do. A template whose commands need a program that is not
+installed is skipped, and the first failure stops the rest. Its whole
+output goes to a log file, whose path is printed with the last lines of
+it, and ``jolo new`` exits with status 6 (``verify``): the project is
+written either way. ``--no-verify`` (or ``[new] verify = false``) skips
+the checks, and so does ``--no-install``, since they need the
+dependencies; ``jolo new`` says so when it does.
+"""
+
+from __future__ import annotations
+
+import tempfile
+from pathlib import Path
+
+from _jolo import events, log, placeholders
+from _jolo.install import Step, missing_program, print_step, run_step
+
+
+def commands(templates: list, variables: dict[str, str]) -> list[list[str]]:
+    """The verify commands of each template that has any, rendered."""
+    return [
+        [placeholders.render(cmd, variables) for cmd in run]
+        for t in templates
+        if (run := t.manifest.get("verify", {}).get("run", []))
+    ]
+
+
+def write_log(step: Step, cwd: Path) -> Path:
+    """Save what STEP printed to a log file outside CWD; return its path."""
+    with tempfile.NamedTemporaryFile(
+        "w", prefix=f"jolo-verify-{cwd.name}-", suffix=".log", delete=False
+    ) as f:
+        f.write(f"$ {step.command}\n{step.output}\n")
+    return Path(f.name)
+
+
+def run(groups: list[list[str]], cwd: Path) -> list[Step]:
+    """Run each group of COMMANDS in CWD, printing a line per command;
+    stop at the first failure."""
+    steps = []
+    failed = False
+    for group in groups:
+        missing = missing_program(group)
+        for cmd in group:
+            step = Step(cmd)
+            steps.append(step)
+            if missing:
+                step.status, step.detail = "skip", f"{missing} not installed"
+            elif not failed:
+                run_step(step, cwd)
+                failed = step.status == "fail"
+            print_step(step)
+            if step.status == "fail":
+                log.info(f"        full output: {write_log(step, cwd)}")
+            events.emit(
+                "verify",
+                command=cmd,
+                status="not run" if step.status == "pending" else step.status,
+                seconds=round(step.seconds, 2),
+            )
+    return steps
+
+
+def passed(steps: list[Step]) -> bool:
+    """Print the verdict on STEPS; whether none failed."""
+    if any(s.status == "fail" for s in steps):
+        log.info("Verify: FAIL")
+        return False
+    if all(s.status == "skip" for s in steps):
+        log.info("Verify: skipped (tools not installed)")
+    else:
+        log.info("Verify: PASS")
+    return True
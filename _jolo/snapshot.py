@@ -0,0 +1,142 @@
+"""jolo snapshot — compare rendered templates against golden files.
+
+A golden tree is a directory with one subdirectory per template (``go``,
+``go-web`` for ``go/web``) holding what the template renders with a
+fixed answer set: project ``golden-app``, author ``Golden Author``, a
+fixed UUID, date and secret seed, and every manifest variable at its
+default. An optional ``<dir>.answers.toml`` next to a subdirectory (the
+format ``jolo new --record`` writes) overrides the variables and addons.
+
+``jolo snapshot DIR`` prints a unified diff for every template whose
+output moved and exits non-zero; ``--update`` rewrites the golden files
+instead. The same helpers back this repo's own golden-file tests.
+"""
+
+from __future__ import annotations
+
+import difflib
+import shutil
+import sys
+from pathlib import Path
+
+from _jolo.commands import load_config
+from _jolo.scaffold import (
+    Template,
+    find_addon,
+    find_layered,
+    is_binary,
+    list_layered,
+    project_variables,
+    read_answers,
+    render_project,
+    resolve_variables,
+    template_roots,
+    write_files,
+)
+
+PROJECT_NAME = "golden-app"
+FIXED = {
+    "AUTHOR": "Golden Author",
+    "EMAIL": "golden@example.com",
+    "DESCRIPTION": "A project rendered for the golden snapshots.",
+    "LICENSE": "MIT",
+    "MODULE_PATH": PROJECT_NAME,
+    "UUID": "00000000-0000-4000-8000-000000000000",
+    "TODAY": "2000-01-01",
+    "SECRET_SEED": "golden",
+}
+
+
+def golden_dir(root: Path, name: str) -> Path:
+    return root / name.replace("/", "-")
+
+
+def render_snapshot(template: Template, root: Path) -> dict[str, str | bytes]:
+    """TEMPLATE rendered with the fixed answers (and ROOT's answers file
+    for it, if any)."""
+    answers_file = golden_dir(root, template.name).with_suffix(
+        ".answers.toml"
+    )
+    answers = read_answers(answers_file) if answers_file.is_file() else {}
+    addons = [find_addon(a, template) for a in answers.get("addons", [])]
+    variables = {**project_variables(PROJECT_NAME), **FIXED}
+    given = answers.get("variables", {})
+    for t in (template, *addons):
+        variables.update(resolve_variables(t, given, no_input=True))
+    return dict(render_project(template, addons, variables))
+
+
+def read_tree(path: Path) -> dict[str, str | bytes]:
+    files = {}
+    if not path.is_dir():
+        return files
+    for src in sorted(path.rglob("*")):
+        if src.is_file():
+            data = src.read_bytes()
+            rel = src.relative_to(path).as_posix()
+            files[rel] = data if is_binary(data) else data.decode()
+    return files
+
+
+def diff_tree(
+    golden: dict[str, str | bytes], rendered: dict[str, str | bytes]
+) -> list[str]:
+    """Unified diff lines from GOLDEN to RENDERED."""
+    lines = []
+    for rel in sorted(set(golden) | set(rendered)):
+        old, new = golden.get(rel), rendered.get(rel)
+        if old == new:
+            continue
+        if isinstance(old, bytes) or isinstance(new, bytes):
+            lines.append(f"Binary files a/{rel} and b/{rel} differ\n")
+            continue
+        for line in difflib.unified_diff(
+            (old or "").splitlines(keepends=True),
+            (new or "").splitlines(keepends=True),
+            fromfile=f"a/{rel}" if old is not None else "/dev/null",
+            tofile=f"b/{rel}" if new is not None else "/dev/null",
+        ):
+            if not line.endswith("\n"):
+                line += "\n\\ No newline at end of file\n"
+            lines.append(line)
+    return lines
+
+
+def update_tree(path: Path, files: dict[str, str | bytes]) -> None:
+    if path.exists():
+        shutil.rmtree(path)
+    path.mkdir(parents=True)
+    write_files(list(files.items()), path)
+
+
+def run_snapshot_mode(args) -> None:
+    """Check (or with --update, rewrite) the golden files in args.dir."""
+    root = Path(args.dir)
+    new_cfg = load_config().get("new", {})
+    roots = template_roots(args.templates_dir, new_cfg)
+    if args.templates:
+        templates = [find_layered(name, roots)[0] for name in args.templates]
+    else:
+        templates = list_layered(roots)
+
+    changed = []
+    for template in templates:
+        path = golden_dir(root, template.name)
+        rendered = render_snapshot(template, root)
+        lines = diff_tree(read_tree(path), rendered)
+        if not lines:
+            continue
+        changed.append(template.name)
+        if args.update:
+            update_tree(path, rendered)
+            print(f"Updated {path}")
+        else:
+            sys.stdout.writelines(lines)
+
+    if not changed:
+        print(f"{len(templates)} templates match {root}")
+    elif not args.update:
+        sys.exit(
+            f"Golden files differ: {', '.join(changed)} "
+            "(jolo snapshot --update to accept)"
+        )
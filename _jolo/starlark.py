@@ -0,0 +1,457 @@
+"""Template scripts: a sandboxed Starlark dialect.
+
+A manifest's ``script = "template.star"`` names a script in the
+template whose functions ``jolo new`` calls while rendering (any may be
+left out)::
+
+    def variables(vars):
+        # Derived variables, seen by files, paths, patches and the rest.
+        names = [s.strip() for s in vars["SERVICES"].split(",")]
+        return {"SERVICE_COUNT": len(names)}
+
+    def include(path, vars):
+        # Whether the template file PATH (as in the template) is rendered.
+        return not path.startswith("docs/") or vars["DOCS"] == "true"
+
+    def transform(path, content, vars):
+        # The rendered text of PATH, changed.
+        return content.replace("\\t", "    ")
+
+    def files(vars):
+        # Extra files, path -> text; render() fills in placeholders.
+        return {
+            "services/%s/main.go" % n: render(MAIN, vars)
+            for n in vars["SERVICES"].split(",")
+        }
+
+Scripts are Starlark as far as jolo needs it: Python syntax without
+``while``, ``class``, ``import``, ``try``, ``with``, f-strings,
+recursion or attribute access beyond the usual string, list and dict
+methods (``str.format`` is left out; ``%`` formats). The builtins are
+Starlark's common ones plus ``render`` (with no ``env`` helper) and
+``fail``. A script has no access to files, the environment or the
+network, runs a bounded number of steps, and may not build strings or
+lists above ``MAX_SIZE``.
+Anything else is a ValueError saying where.
+
+``Expression`` evaluates one expression in the same dialect, for a
+manifest's ``[computed]`` variables: the variables are names, the
+placeholder filters are functions (``snake(PROJECT_NAME)``), and
+``now()`` is today's date, whose ``.year``, ``.month`` and ``.day``
+can be read (``TODAY`` stands in for it, as for ``{{date}}``).
+"""
+
+from __future__ import annotations
+
+import ast
+import datetime
+import math
+import re
+import sys
+from pathlib import Path
+
+from _jolo import placeholders
+
+MAX_STEPS = 1_000_000
+MAX_SIZE = 1_000_000
+
+_NODES = (
+    ast.Module,
+    ast.Expression,
+    ast.Expr,
+    ast.Assign,
+    ast.AugAssign,
+    ast.For,
+    ast.If,
+    ast.Return,
+    ast.Pass,
+    ast.Break,
+    ast.Continue,
+    ast.FunctionDef,
+    ast.arguments,
+    ast.arg,
+    ast.Name,
+    ast.Constant,
+    ast.BinOp,
+    ast.UnaryOp,
+    ast.BoolOp,
+    ast.Compare,
+    ast.IfExp,
+    ast.Call,
+    ast.keyword,
+    ast.Attribute,
+    ast.Subscript,
+    ast.Slice,
+    ast.List,
+    ast.Tuple,
+    ast.Dict,
+    ast.ListComp,
+    ast.DictComp,
+    ast.comprehension,
+    ast.Lambda,
+    ast.Starred,
+    ast.operator,
+    ast.unaryop,
+    ast.boolop,
+    ast.cmpop,
+    ast.Load,
+    ast.Store,
+)
+_CONSTANTS = (str, int, float, bool, type(None))
+_METHODS = {
+    str: {
+        "capitalize", "count", "endswith", "find", "index", "isalnum",
+        "isalpha", "isdigit", "islower", "isspace", "istitle", "isupper",
+        "join", "lower", "lstrip", "partition", "removeprefix",
+        "removesuffix", "replace", "rfind", "rindex", "rpartition",
+        "rsplit", "rstrip", "split", "splitlines", "startswith", "strip",
+        "title", "upper",
+    },
+    list: {"append", "clear", "extend", "index", "insert", "pop", "remove"},
+    dict: {
+        "clear", "get", "items", "keys", "pop", "popitem", "setdefault",
+        "update", "values",
+    },
+}  # fmt: skip
+# The operators that can build big values, checked before they run.
+_SIZED_OPS = (ast.Add, ast.Mult, ast.Mod, ast.Pow, ast.LShift)
+_WIDTH = re.compile(r"%[-+ #0]*(\d*)(?:\.(\d+))?")
+_TYPE_NAMES = {str: "string", dict: "dict", list: "list", bool: "bool"}
+# Fields an expression may read without calling them.
+_FIELDS = {datetime.date: {"year", "month", "day"}}
+
+
+class ScriptError(Exception):
+    def __init__(self, message: str, line: int | None = None):
+        super().__init__(message)
+        self.line = line
+
+
+def _error(node: ast.AST, message: str) -> ScriptError:
+    return ScriptError(message, getattr(node, "lineno", None))
+
+
+def _check(tree: ast.AST, fields: bool = False) -> None:
+    """Reject everything outside the dialect; FIELDS allows reading
+    ``_FIELDS`` (``now().year``)."""
+    for node in ast.walk(tree):
+        if not isinstance(node, _NODES):
+            name = type(node).__name__
+            raise _error(node, f"{name} is not allowed in a template script")
+        if isinstance(node, ast.Constant) and not isinstance(
+            node.value, _CONSTANTS
+        ):
+            raise _error(node, f"{type(node.value).__name__} literal")
+        if isinstance(node, ast.Name) and node.id.startswith("__"):
+            raise _error(node, f"name {node.id} is not allowed")
+        if isinstance(node, ast.FunctionDef) and (
+            node.decorator_list or node.returns
+        ):
+            raise _error(node, "decorators and annotations are not allowed")
+        if isinstance(node, ast.arg) and node.annotation:
+            raise _error(node, "annotations are not allowed")
+        if isinstance(node, ast.comprehension) and node.is_async:
+            raise _error(node, "async is not allowed")
+        if isinstance(node, ast.Call) and isinstance(node.func, ast.Attribute):
+            node.func.method = True
+        if isinstance(node, ast.Attribute):
+            if fields and not getattr(node, "method", False):
+                if not any(node.attr in f for f in _FIELDS.values()):
+                    raise _error(node, f"no field {node.attr}")
+                node.field = True
+            elif not getattr(node, "method", False):
+                raise _error(node, f".{node.attr} outside a method call")
+            if node.attr.startswith("_") or node.attr == "format":
+                raise _error(node, f"method {node.attr} is not allowed")
+        if (
+            isinstance(node, ast.AugAssign)
+            and isinstance(node.op, _SIZED_OPS)
+            and not isinstance(node.target, ast.Name)
+        ):
+            raise _error(node, "write x = x OP y for this target")
+
+
+class _Rewrite(ast.NodeTransformer):
+    """Route method calls and size-building operators through checks."""
+
+    def visit_Call(self, node: ast.Call) -> ast.AST:
+        self.generic_visit(node)
+        if isinstance(node.func, ast.Attribute):
+            node.func = ast.Call(
+                func=ast.Name(id="$method", ctx=ast.Load()),
+                args=[node.func.value, ast.Constant(node.func.attr)],
+                keywords=[],
+            )
+        return node
+
+    def visit_Attribute(self, node: ast.Attribute) -> ast.AST:
+        self.generic_visit(node)
+        if not getattr(node, "field", False):
+            return node
+        return ast.Call(
+            func=ast.Name(id="$field", ctx=ast.Load()),
+            args=[node.value, ast.Constant(node.attr)],
+            keywords=[],
+        )
+
+    def _sized(self, op: ast.operator, left, right) -> ast.AST:
+        return ast.Call(
+            func=ast.Name(id="$binop", ctx=ast.Load()),
+            args=[ast.Constant(type(op).__name__), left, right],
+            keywords=[],
+        )
+
+    def visit_BinOp(self, node: ast.BinOp) -> ast.AST:
+        self.generic_visit(node)
+        if isinstance(node.op, _SIZED_OPS):
+            return self._sized(node.op, node.left, node.right)
+        return node
+
+    def visit_AugAssign(self, node: ast.AugAssign) -> ast.AST:
+        self.generic_visit(node)
+        if not isinstance(node.op, _SIZED_OPS):
+            return node
+        load = ast.Name(id=node.target.id, ctx=ast.Load())
+        return ast.Assign(
+            targets=[node.target],
+            value=self._sized(node.op, load, node.value),
+        )
+
+
+def _size(value) -> int:
+    sized = (str, list, tuple, dict, range)
+    return len(value) if isinstance(value, sized) else 0
+
+
+def _limit(value):
+    if _size(value) > MAX_SIZE:
+        raise ScriptError(f"value of {_size(value)} items is too big")
+    return value
+
+
+def _binop(op: str, left, right):
+    if op == "Add":
+        if _size(left) + _size(right) > MAX_SIZE:
+            raise ScriptError("value is too big")
+        return left + right
+    if op == "Mult":
+        for seq, n in ((left, right), (right, left)):
+            if _size(seq) and isinstance(n, int) and _size(seq) * n > MAX_SIZE:
+                raise ScriptError("value is too big")
+        return left * right
+    if op == "Mod":
+        if isinstance(left, str):
+            for width in _WIDTH.finditer(left):
+                if any(int(n) > MAX_SIZE for n in width.groups() if n):
+                    raise ScriptError("format width is too big")
+        return left % right
+    if op == "Pow":
+        if isinstance(left, int) and isinstance(right, int) and right > 0:
+            if abs(left) > 1 and right * math.log2(abs(left)) > 4096:
+                raise ScriptError("number is too big")
+        return left**right
+    if right > 4096:
+        raise ScriptError("number is too big")
+    return left << right
+
+
+def _method(obj, name: str):
+    kind = next((t for t in _METHODS if isinstance(obj, t)), None)
+    if kind is None or name not in _METHODS[kind]:
+        raise ScriptError(f"{type_name(obj)} has no method {name}")
+    bound = getattr(obj, name)
+
+    def call(*args, **kwargs):
+        if name == "replace" and len(args) >= 2:
+            grow = obj.count(args[0]) * max(len(args[1]) - len(args[0]), 0)
+            if len(obj) + grow > MAX_SIZE:
+                raise ScriptError("value is too big")
+        if name == "join":
+            args = (list(args[0]),) + args[1:] if args else args
+            if args and sum(map(_size, args[0])) > MAX_SIZE:
+                raise ScriptError("value is too big")
+        result = bound(*args, **kwargs)
+        _limit(obj)
+        if name in ("items", "keys", "values"):
+            return list(result)
+        return _limit(result)
+
+    return call
+
+
+def _field(obj, name: str):
+    kind = next((t for t in _FIELDS if isinstance(obj, t)), None)
+    if kind is None or name not in _FIELDS[kind]:
+        raise ScriptError(f"{type_name(obj)} has no field {name}")
+    return getattr(obj, name)
+
+
+def type_name(value) -> str:
+    for kind, name in _TYPE_NAMES.items():
+        if type(value) is kind:
+            return name
+    if callable(value):
+        return "function"
+    return type(value).__name__
+
+
+def _range(*args) -> range:
+    return _limit(range(*args))
+
+
+def _fail(*message) -> None:
+    raise ScriptError(" ".join(str(m) for m in message))
+
+
+def _print(*values) -> None:
+    print(*values, file=sys.stderr)
+
+
+def _no_env(variables: dict, *args) -> str:
+    raise ScriptError("render: env is not available to scripts")
+
+
+def _render(text: str, variables: dict) -> str:
+    return placeholders.render(
+        text, {k: str(v) for k, v in variables.items()}, {"env": _no_env}
+    )
+
+
+_BUILTINS = {
+    "True": True,
+    "False": False,
+    "None": None,
+    "abs": abs,
+    "all": all,
+    "any": any,
+    "bool": bool,
+    "dict": dict,
+    "enumerate": lambda *a: list(enumerate(*a)),
+    "fail": _fail,
+    "int": int,
+    "len": len,
+    "list": lambda *a: _limit(list(*a)),
+    "max": max,
+    "min": min,
+    "print": _print,
+    "range": _range,
+    "render": _render,
+    "repr": repr,
+    "reversed": lambda seq: list(reversed(seq)),
+    "sorted": sorted,
+    "str": str,
+    "tuple": tuple,
+    "type": type_name,
+    "zip": lambda *a: list(zip(*a)),
+}
+
+
+class Script:
+    """A loaded template script; ``call`` runs one of its functions."""
+
+    def __init__(self, source: str, filename: str):
+        self.filename = filename
+        try:
+            tree = ast.parse(source, filename)
+            _check(tree)
+        except SyntaxError as e:
+            raise ValueError(f"{filename}:{e.lineno}: {e.msg}") from None
+        except ScriptError as e:
+            raise ValueError(f"{filename}:{e.line}: {e}") from None
+        tree = ast.fix_missing_locations(_Rewrite().visit(tree))
+        self.globals = {
+            "__builtins__": _BUILTINS,
+            "$method": _method,
+            "$binop": _binop,
+        }
+        self._run(lambda: exec(compile(tree, filename, "exec"), self.globals))
+
+    def has(self, name: str) -> bool:
+        return callable(self.globals.get(name))
+
+    def call(self, name: str, *args):
+        return self._run(lambda: self.globals[name](*args))
+
+    def _run(self, thunk):
+        steps = 0
+
+        def trace(frame, event, arg):
+            nonlocal steps
+            if frame.f_code.co_filename != self.filename:
+                return None
+            if event == "call":
+                outer = frame.f_back
+                while outer is not None:
+                    if outer.f_code is frame.f_code:
+                        raise ScriptError(
+                            f"{frame.f_code.co_name}: recursion is not "
+                            "allowed"
+                        )
+                    outer = outer.f_back
+            steps += 1
+            if steps > MAX_STEPS:
+                raise ScriptError(f"more than {MAX_STEPS} steps")
+            return trace
+
+        previous = sys.gettrace()
+        sys.settrace(trace)
+        try:
+            return thunk()
+        except Exception as e:
+            raise ValueError(f"{self._where(e)}{_message(e)}") from None
+        finally:
+            sys.settrace(previous)
+
+    def _where(self, error: BaseException) -> str:
+        line = None
+        tb = error.__traceback__
+        while tb is not None:
+            if tb.tb_frame.f_code.co_filename == self.filename:
+                line = tb.tb_lineno
+            tb = tb.tb_next
+        return f"{self.filename}:{line}: " if line else f"{self.filename}: "
+
+
+class Expression(Script):
+    """One expression (see the module docstring); ``evaluate`` gives
+    its value."""
+
+    def __init__(self, source: str, filename: str):
+        self.filename = filename
+        try:
+            tree = ast.parse(source.strip(), filename, mode="eval")
+            _check(tree, fields=True)
+        except SyntaxError as e:
+            raise ValueError(f"{filename}: {e.msg}") from None
+        except ScriptError as e:
+            raise ValueError(f"{filename}: {e}") from None
+        tree = ast.fix_missing_locations(_Rewrite().visit(tree))
+        self.code = compile(tree, filename, "eval")
+
+    def evaluate(self, variables: dict[str, str]):
+        def now() -> datetime.date:
+            if variables.get("TODAY"):
+                return datetime.date.fromisoformat(variables["TODAY"])
+            return datetime.date.today()
+
+        builtins = {**_BUILTINS, **placeholders.FILTERS, "now": now}
+        names = {
+            **variables,
+            "__builtins__": builtins,
+            "$method": _method,
+            "$binop": _binop,
+            "$field": _field,
+        }
+        return self._run(lambda: eval(self.code, names))
+
+
+def _message(error: BaseException) -> str:
+    if isinstance(error, ScriptError):
+        return str(error)
+    if isinstance(error, NameError):
+        return str(error)
+    return f"{type(error).__name__}: {error}"
+
+
+def load(path: Path) -> Script:
+    return Script(path.read_text(encoding="utf-8"), path.name)
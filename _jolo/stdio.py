@@ -0,0 +1,117 @@
+"""jolo --stdio — the scaffolding API for editor plugins.
+
+Speaks JSON-RPC 2.0 over stdin and stdout, one message per line, until
+EOF, so a plugin can keep one ``jolo --stdio`` process around. Methods:
+
+    initialize          {"name", "version", "methods"}
+    templates/list      optional query, tag -> {"templates": [...]}
+    templates/describe  template -> description, variables, addons
+    project/preview     template, path, variables, addons ->
+                        {"path", "files"} without writing anything
+    project/generate    template, path, variables, addons, hooks,
+                        overwrite -> {"path", "files"}
+
+A plugin can walk the user through ``describe``'s variables itself and
+send the answers to ``generate``. A relative path is taken from the
+directory ``jolo --stdio`` was started in. Failures are JSON-RPC
+errors with code -32000 and jolo's message.
+"""
+
+from __future__ import annotations
+
+import sys
+from pathlib import Path
+
+from _jolo import api, mcp, scaffold
+from _jolo.commands import load_config
+
+VERSION = "1"
+SCAFFOLD_ERROR = -32000
+
+
+class Server(mcp.Server):
+    """The ``--stdio`` protocol, on the MCP server's template methods."""
+
+    METHODS = (
+        "initialize",
+        "templates/list",
+        "templates/describe",
+        "project/preview",
+        "project/generate",
+    )
+
+    def preview(
+        self,
+        template: str,
+        path: str,
+        variables: dict | None = None,
+        addons: list[str] = (),
+    ):
+        dest = (self.workspace / path).resolve()
+        with api.open_template(template, self.templates_dirs) as t:
+            files = api.render(t, dest.name, variables, addons)
+        return {"path": str(dest), "files": sorted(files)}
+
+    def generate(
+        self,
+        template: str,
+        path: str,
+        variables: dict | None = None,
+        addons: list[str] = (),
+        hooks: bool = False,
+        overwrite: bool = False,
+    ):
+        dest = (self.workspace / path).resolve()
+        with api.open_template(template, self.templates_dirs) as t:
+            files = api.generate(
+                t, dest, variables, addons, hooks=hooks, overwrite=overwrite
+            )
+        return {
+            "path": str(dest),
+            "files": sorted([*files, scaffold.LOCK_FILE]),
+        }
+
+    def call(self, method: str, params: dict):
+        if method == "initialize":
+            return {
+                "name": "jolo",
+                "version": VERSION,
+                "methods": list(self.METHODS),
+            }
+        if method == "templates/list":
+            return self.list_templates(**params)
+        if method == "templates/describe":
+            return self.describe_template(**params)
+        if method == "project/preview":
+            return self.preview(**params)
+        return self.generate(**params)
+
+    def handle(self, message: dict) -> dict | None:
+        """The response to one JSON-RPC MESSAGE (None for notifications)."""
+        if not isinstance(message, dict):
+            return mcp._error({}, -32600, "invalid request")
+        if "id" not in message:
+            return None
+        method = message.get("method")
+        params = message.get("params") or {}
+        if method not in self.METHODS:
+            return mcp._error(message, -32601, f"unknown method: {method}")
+        if not isinstance(params, dict):
+            return mcp._error(message, -32602, "params must be an object")
+        try:
+            result = self.call(method, params)
+        except TypeError as e:
+            return mcp._error(message, -32602, f"bad params: {e}")
+        except api.ScaffoldError as e:
+            return mcp._error(message, SCAFFOLD_ERROR, str(e))
+        except Exception as e:  # keep serving whatever one call did
+            return mcp._error(message, -32603, str(e))
+        return {"jsonrpc": "2.0", "id": message["id"], "result": result}
+
+
+def run_stdio_mode(args) -> None:
+    """Serve the editor protocol on stdin/stdout until EOF."""
+    templates_dirs = load_config().get("new", {}).get("templates_dirs", [])
+    scaffold.template_roots(templates_dirs, {})  # exits on a missing dir
+    protocol = mcp.protocol_stdout()
+    Server(Path.cwd(), templates_dirs).serve(sys.stdin, protocol)
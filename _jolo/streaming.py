@@ -0,0 +1,118 @@
+"""Large template files, rendered while they are written.
+
+A template file of ``STREAM_SIZE`` bytes or more (a vendored asset, a
+generated lockfile) is not read into memory by ``jolo new``: rendering
+gives a Streamed in its place, which reads the source ``CHUNK`` bytes
+at a time, substitutes placeholders as it goes (see
+``placeholders.render_chunks``) and hands the output to the writer.
+Binary files are copied the same way. Anything that needs the whole
+text, such as an addon patch, calls ``read()``.
+
+Templates using a script's ``transform`` or the cookiecutter engine
+are always rendered in memory.
+"""
+
+from __future__ import annotations
+
+import codecs
+import io
+from collections.abc import Iterator
+from pathlib import Path
+from typing import BinaryIO
+
+from _jolo import placeholders, portable
+
+STREAM_SIZE = 1 << 20
+CHUNK = 1 << 16
+
+
+class Streamed:
+    """SRC rendered with VARIABLES, or copied as is when VARIABLES is
+    None, produced a chunk at a time; EOL (lf, crlf) converts the line
+    endings of text (see ``_jolo.portable``)."""
+
+    def __init__(
+        self,
+        src: Path,
+        variables: dict[str, str] | None,
+        eol: str = "",
+    ):
+        self.src = src
+        self.variables = variables
+        self.eol = eol
+        self._size = None
+
+    def with_eol(self, eol: str) -> Streamed:
+        return Streamed(self.src, self.variables, eol)
+
+    def _read(self) -> Iterator[bytes]:
+        with self.src.open("rb") as f:
+            while chunk := f.read(CHUNK):
+                yield chunk
+
+    def chunks(self) -> Iterator[bytes]:
+        if self.variables is None:
+            yield from self._read()
+            return
+        decoder = codecs.getincrementaldecoder("utf-8")()
+        text = (decoder.decode(chunk) for chunk in self._read())
+        rendered = placeholders.render_chunks(text, self.variables)
+        if self.eol:
+            rendered = portable.convert_chunks(rendered, self.eol)
+        for out in rendered:
+            if out:
+                yield out.encode()
+        decoder.decode(b"", final=True)  # a cut-off character at the end
+
+    @property
+    def size(self) -> int:
+        """The rendered size in bytes (rendering it once to find out)."""
+        if self._size is None:
+            self._size = sum(len(chunk) for chunk in self.chunks())
+        return self._size
+
+    def open(self) -> BinaryIO:
+        """The rendered bytes as a file to read from."""
+        return io.BufferedReader(_ChunkReader(self.chunks()), CHUNK)
+
+    def read(self) -> str | bytes:
+        """All of it: text, or bytes for a file copied as is."""
+        data = b"".join(self.chunks())
+        return data if self.variables is None else data.decode()
+
+    def same_as(self, path: Path) -> bool:
+        """Whether PATH already holds exactly the rendered bytes."""
+        with path.open("rb") as f:
+            for chunk in self.chunks():
+                if f.read(len(chunk)) != chunk:
+                    return False
+            return f.read(1) == b""
+
+
+class _ChunkReader(io.RawIOBase):
+    def __init__(self, chunks: Iterator[bytes]):
+        self.chunks = chunks
+        self.pending = b""
+
+    def readable(self) -> bool:
+        return True
+
+    def readinto(self, buffer) -> int:
+        while not self.pending:
+            chunk = next(self.chunks, None)
+            if chunk is None:
+                return 0
+            self.pending = chunk
+        n = min(len(buffer), len(self.pending))
+        buffer[:n] = self.pending[:n]
+        self.pending = self.pending[n:]
+        return n
+
+
+def size(content: str | bytes | Streamed) -> int:
+    """CONTENT's size in bytes, as written."""
+    if isinstance(content, Streamed):
+        return content.size
+    if isinstance(content, str):
+        return len(content.encode())
+    return len(content)
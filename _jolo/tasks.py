@@ -0,0 +1,177 @@
+"""The project's task runner: a justfile, Makefile or Taskfile.
+
+Templates and addons list their canonical tasks under ``[tasks]``, a
+command, a list of them, or a table with an ``about`` line:
+
+    [tasks]
+    generate = "templ generate"
+    build = ["templ generate", "go build ./..."]
+    dev = { run = "air", about = "Run with auto-reload" }
+
+``--tasks make|task|just`` (or ``[new] tasks``) picks the runner; the
+choice is kept as the TASK_RUNNER answer, so ``jolo upgrade`` renders
+the same file. With ``make`` or ``task`` the template's justfile gives
+way to a Makefile or Taskfile.yml holding the tasks (build, test, dev,
+lint and generate first, then the rest as declared), its other recipes
+left behind. With ``just`` a template's justfile stays, and gains a
+recipe for each task it lacks. Commands are rendered like template
+files. Without a choice the template's files stay as they are.
+"""
+
+from __future__ import annotations
+
+import json
+import re
+import sys
+from dataclasses import dataclass
+
+from _jolo import placeholders
+
+RUNNERS = ("just", "make", "task")
+FILES = {"just": "justfile", "make": "Makefile", "task": "Taskfile.yml"}
+CANONICAL = ("build", "test", "dev", "lint", "generate")
+_NAME = re.compile(r"[A-Za-z][\w-]*")
+# A justfile recipe or Makefile target line, not ``x := y``.
+_TARGET = re.compile(r"^(?P<name>[\w-]+)\b[^:=\n]*:(?!=)", re.M)
+_TASK = re.compile(r"^  (?P<name>[\w-]+):\s*$", re.M)
+
+
+@dataclass
+class Task:
+    about: str
+    run: list[str]
+
+
+def check_runner(runner: str) -> str:
+    if runner not in RUNNERS:
+        sys.exit(
+            f"Error: unknown task runner {runner!r} "
+            f"(one of {', '.join(RUNNERS)})"
+        )
+    return runner
+
+
+def _task(template, name: str, spec, variables: dict[str, str]) -> Task:
+    about = ""
+    if isinstance(spec, dict):
+        about, spec = spec.get("about", ""), spec.get("run")
+    if isinstance(spec, str):
+        spec = [spec]
+    if (
+        not _NAME.fullmatch(name)
+        or not isinstance(about, str)
+        or not isinstance(spec, list)
+        or not spec
+        or not all(isinstance(c, str) for c in spec)
+    ):
+        sys.exit(
+            f"Error: {template.name}: tasks.{name}: expected a command, a "
+            'list of them, or { run = ..., about = "..." }'
+        )
+    return Task(
+        placeholders.render(about, variables),
+        [placeholders.render(c, variables) for c in spec],
+    )
+
+
+def declared(templates: list, variables: dict[str, str]) -> dict[str, Task]:
+    """The manifests' tasks, canonical ones first; later templates win."""
+    found = {}
+    for template in templates:
+        table = template.manifest.get("tasks", {})
+        if not isinstance(table, dict):
+            sys.exit(f"Error: {template.name}: [tasks] must be a table")
+        for name, spec in table.items():
+            found[name] = _task(template, name, spec, variables)
+    first = [n for n in CANONICAL if n in found]
+    rest = [n for n in found if n not in CANONICAL]
+    return {n: found[n] for n in (*first, *rest)}
+
+
+def _just(tasks: dict[str, Task]) -> list[str]:
+    blocks = []
+    for name, task in tasks.items():
+        lines = [f"# {task.about}"] if task.about else []
+        lines += [f"{name}:", *(f"    {c}" for c in task.run)]
+        blocks.append("\n".join(lines))
+    return blocks
+
+
+def _make(tasks: dict[str, Task]) -> list[str]:
+    blocks = [f".PHONY: {' '.join(tasks)}"]
+    for name, task in tasks.items():
+        lines = [f"# {task.about}"] if task.about else []
+        lines.append(f"{name}:")
+        lines += [f"\t{c.replace('$', '$$')}" for c in task.run]
+        blocks.append("\n".join(lines))
+    return blocks
+
+
+def _taskfile(tasks: dict[str, Task]) -> list[str]:
+    blocks = []
+    for name, task in tasks.items():
+        lines = [f"  {name}:"]
+        if task.about:
+            lines.append(f"    desc: {json.dumps(task.about)}")
+        lines += ["    cmds:", *(f"      - {json.dumps(c)}" for c in task.run)]
+        blocks.append("\n".join(lines))
+    return blocks
+
+
+_HEADERS = {
+    "just": 'set shell := ["bash", "-eo", "pipefail", "-c"]',
+    "make": "SHELL := bash\n.SHELLFLAGS := -eo pipefail -c",
+    "task": 'version: "3"\n\nset: [pipefail]\n\ntasks:',
+}
+_BLOCKS = {"just": _just, "make": _make, "task": _taskfile}
+
+
+def names(runner: str, text: str) -> list[str]:
+    """The tasks TEXT, a file for RUNNER, defines."""
+    pattern = _TASK if runner == "task" else _TARGET
+    return [m["name"] for m in pattern.finditer(text)]
+
+
+def render(runner: str, tasks: dict[str, Task], text: str = "") -> str:
+    """TEXT, RUNNER's file, with the TASKS it lacks added; a new file
+    when TEXT is empty."""
+    have = names(runner, text)
+    missing = {n: t for n, t in tasks.items() if n not in have}
+    if text.strip():
+        if not missing:
+            return text
+        if runner == "task" and not re.search(r"^tasks:", text, re.M):
+            sys.exit(f"Error: {FILES[runner]} has no tasks: to add to")
+        parts = [text.rstrip("\n")]
+    else:
+        parts = [_HEADERS[runner]]
+    sep = "\n" if runner == "task" else "\n\n"
+    return sep.join([*parts, *_BLOCKS[runner](missing)]) + "\n"
+
+
+def apply(
+    files: list[tuple[str, str | bytes]],
+    templates: list,
+    variables: dict[str, str],
+    extra: dict[str, Task] | None = None,
+) -> list[tuple[str, str | bytes]]:
+    """FILES with the TASK_RUNNER variable's runner file holding the
+    templates' tasks, in place of another runner's justfile. EXTRA tasks
+    (a Go module's per-command ones) follow them, and go into the
+    justfile without a runner."""
+    runner = variables.get("TASK_RUNNER")
+    tasks = declared(templates, variables) if runner else {}
+    tasks.update(extra or {})
+    if not runner:
+        if not tasks:
+            return files
+        runner = "just"
+    check_runner(runner)
+    rendered = dict(files)
+    if runner != "just" and tasks:
+        rendered.pop(FILES["just"], None)
+    own = rendered.get(FILES[runner], "")
+    if not tasks or not isinstance(own, str):
+        return list(rendered.items())
+    rendered[FILES[runner]] = render(runner, tasks, own)
+    return list(rendered.items())
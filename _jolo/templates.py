@@ -1,6 +1,10 @@
 """Template and config generation functions for jolo."""
 
+import os
 import re
+import shutil
+import tempfile
+import zipfile
 from pathlib import Path
 
 from _jolo import constants
@@ -21,7 +25,45 @@ def sanitize_for_testbed(name: str) -> str:
     return slug
 
 
-_TEMPLATES_DIR = Path(__file__).parent.parent / "templates"
+def _bundled_templates(archive: Path) -> Path:
+    """Extract templates/ from a single-file jolo (zipapp) once per build.
+
+    Everything downstream walks real directories, so the bundled tree is
+    unpacked into the cache rather than read through zipfile.
+    """
+    st = archive.stat()
+    cache_home = Path(
+        os.environ.get("XDG_CACHE_HOME") or Path.home() / ".cache"
+    )
+    cache = cache_home / "jolo" / f"templates-{st.st_size}-{int(st.st_mtime)}"
+    if not cache.is_dir():
+        cache.parent.mkdir(parents=True, exist_ok=True)
+        tmp = Path(tempfile.mkdtemp(dir=cache.parent))
+        with zipfile.ZipFile(archive) as zf:
+            members = [
+                m for m in zf.infolist() if m.filename.startswith("templates/")
+            ]
+            zf.extractall(tmp, members)
+        # extractall drops permissions; template scripts must stay runnable.
+        for member in members:
+            mode = member.external_attr >> 16 & 0o777
+            if mode and not member.is_dir():
+                (tmp / member.filename).chmod(mode)
+        try:
+            tmp.rename(cache)
+        except OSError:  # another jolo won the race
+            shutil.rmtree(tmp)
+    return cache / "templates"
+
+
+def _find_templates_dir() -> Path:
+    root = Path(__file__).parent.parent
+    if zipfile.is_zipfile(root):
+        return _bundled_templates(root)
+    return root / "templates"
+
+
+_TEMPLATES_DIR = _find_templates_dir()
 
 
 def to_snake_case(name: str) -> str:
@@ -418,18 +460,20 @@ def get_test_framework_config(flavor: str) -> dict:
             "config_content": _read_template(pyproject_path),
             "example_test_file": "tests/test_main.py",
             "example_test_content": _read_template(
-                _flavor_template_path(flavor, "test_main.py")
+                _flavor_template_path(flavor, "tests/test_main.py")
             ),
             "main_file": "src/{{PROJECT_NAME_UNDERSCORE}}/main.py",
             "main_content": _read_template(
-                _flavor_template_path(flavor, "main.py")
+                _flavor_template_path(
+                    flavor, "src/{{PROJECT_NAME_UNDERSCORE}}/main.py"
+                )
             ),
             "init_file": "src/{{PROJECT_NAME_UNDERSCORE}}/__init__.py",
             "tests_init_file": "tests/__init__.py",
         }
 
     elif lang == "typescript":
-        test_file = "example.test.ts"
+        test_file = "src/example.test.ts"
         return {
             "config_file": None,
             "config_content": "# Bun has built-in testing. Run tests with: bun test",
@@ -440,12 +484,16 @@ def get_test_framework_config(flavor: str) -> dict:
         }
 
     elif lang == "go":
+        # go/web's tests run the whole app rather than a handler.
+        test_file = (
+            "integration_test.go" if flavor == "go-web" else "example_test.go"
+        )
         return {
             "config_file": None,
             "config_content": "# Go uses built-in testing. Run tests with: go test ./...",
-            "example_test_file": "example_test.go",
+            "example_test_file": test_file,
             "example_test_content": _read_template(
-                _flavor_template_path(flavor, "example_test.go")
+                _flavor_template_path(flavor, test_file)
             ),
             "main_file": "main.go",
             "main_content": _read_template(
@@ -457,7 +505,7 @@ def get_test_framework_config(flavor: str) -> dict:
         if flavor == "rust-web":
             main_rs = _read_template("lang/rust/web/src/main.rs")
         else:
-            main_rs = _read_template("lang/rust/main.rs")
+            main_rs = _read_template("lang/rust/src/main.rs")
         return {
             "config_file": None,
             "config_content": "# Rust uses built-in testing. Run tests with: cargo test",
@@ -516,14 +564,14 @@ def get_scaffold_files(flavor: str) -> list[tuple[str, str]]:
         return [
             (
                 "go.mod",
-                _read_template("lang/go/go.mod"),
+                _read_template("lang/go/_base/go.mod"),
             ),
         ]
     elif flavor == "go-web":
         return [
             (
                 "go.mod",
-                _read_template("lang/go/go.mod"),
+                _read_template("lang/go/web/go.mod"),
             ),
             (
                 "components/page.templ",
@@ -546,7 +594,56 @@ def get_scaffold_files(flavor: str) -> list[tuple[str, str]]:
         return [
             (
                 "src/{{PROJECT_NAME_UNDERSCORE}}/app.py",
-                _read_template("lang/python/web/app.py"),
+                _read_template(
+                    "lang/python/web/src/{{PROJECT_NAME_UNDERSCORE}}/app.py"
+                ),
+            ),
+            (
+                "src/{{PROJECT_NAME_UNDERSCORE}}/settings.py",
+                _read_template(
+                    "lang/python/web/src/{{PROJECT_NAME_UNDERSCORE}}/"
+                    "settings.py"
+                ),
+            ),
+            (
+                "src/{{PROJECT_NAME_UNDERSCORE}}/routers/__init__.py",
+                _read_template(
+                    "lang/python/web/src/{{PROJECT_NAME_UNDERSCORE}}/"
+                    "routers/__init__.py"
+                ),
+            ),
+            (
+                "src/{{PROJECT_NAME_UNDERSCORE}}/routers/pages.py",
+                _read_template(
+                    "lang/python/web/src/{{PROJECT_NAME_UNDERSCORE}}/"
+                    "routers/pages.py"
+                ),
+            ),
+            (
+                "src/{{PROJECT_NAME_UNDERSCORE}}/routers/api.py",
+                _read_template(
+                    "lang/python/web/src/{{PROJECT_NAME_UNDERSCORE}}/"
+                    "routers/api.py"
+                ),
+            ),
+            (
+                "src/{{PROJECT_NAME_UNDERSCORE}}/routers/health.py",
+                _read_template(
+                    "lang/python/web/src/{{PROJECT_NAME_UNDERSCORE}}/"
+                    "routers/health.py"
+                ),
+            ),
+            (
+                "tests/conftest.py",
+                _read_template("lang/python/web/tests/conftest.py"),
+            ),
+            (
+                "tests/test_api.py",
+                _read_template("lang/python/web/tests/test_api.py"),
+            ),
+            (
+                "tests/test_settings.py",
+                _read_template("lang/python/web/tests/test_settings.py"),
             ),
             (
                 "templates/base.html",
@@ -0,0 +1,253 @@
+"""The tools a template needs, and whether this machine has them.
+
+A manifest (base or addon) names them under ``[requires]``, each with
+the lowest version that will do, or ``""`` for any::
+
+    [requires]
+    go = "1.21"
+    templ = ""
+
+``jolo doctor TEMPLATE...`` checks them and says how to install what is
+missing or too old; ``jolo new --check`` does the same first and stops
+before writing anything. Well-known tools (``TOOLS``) have their
+version read and an install hint; any other name is looked up on PATH.
+"""
+
+from __future__ import annotations
+
+import re
+import shutil
+import subprocess
+from dataclasses import dataclass
+
+from _jolo import events
+
+_NUMBER = r"(\d+\.\d+(?:\.\d+)?)"
+
+
+@dataclass(frozen=True)
+class Tool:
+    version: tuple[str, ...]  # the command printing its version
+    hint: str
+    pattern: str = _NUMBER
+
+
+TOOLS = {
+    "go": Tool(("go", "version"), "https://go.dev/dl/"),
+    "templ": Tool(
+        ("templ", "version"),
+        "go install github.com/a-h/templ/cmd/templ@latest",
+    ),
+    "air": Tool(
+        ("air", "-v"), "go install github.com/air-verse/air@latest"
+    ),
+    "just": Tool(
+        ("just", "--version"), "https://just.systems/man/en/packages.html"
+    ),
+    "python": Tool(
+        ("python3", "--version"),
+        "uv python install, or your package manager",
+    ),
+    "uv": Tool(
+        ("uv", "--version"),
+        "curl -LsSf https://astral.sh/uv/install.sh | sh",
+    ),
+    "cargo": Tool(("cargo", "--version"), "https://rustup.rs"),
+    "bacon": Tool(("bacon", "--version"), "cargo install --locked bacon"),
+    "node": Tool(("node", "--version"), "https://nodejs.org/en/download"),
+    "pnpm": Tool(("pnpm", "--version"), "npm install -g pnpm"),
+    "bun": Tool(
+        ("bun", "--version"), "curl -fsSL https://bun.sh/install | bash"
+    ),
+    "docker": Tool(
+        ("docker", "--version"), "https://docs.docker.com/get-docker/"
+    ),
+    "tailwindcss": Tool(
+        ("tailwindcss", "--help"),
+        "https://tailwindcss.com/docs/installation/tailwind-cli",
+        r"tailwindcss v" + _NUMBER,
+    ),
+    "esbuild": Tool(
+        ("esbuild", "--version"),
+        "go install github.com/evanw/esbuild/cmd/esbuild@latest",
+    ),
+    "sqlc": Tool(
+        ("sqlc", "version"),
+        "go install github.com/sqlc-dev/sqlc/cmd/sqlc@latest",
+    ),
+    "oapi-codegen": Tool(
+        ("oapi-codegen", "-version"),
+        "go install github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@latest",
+    ),
+    "buf": Tool(
+        ("buf", "--version"),
+        "go install github.com/bufbuild/buf/cmd/buf@latest",
+    ),
+    "kubectl": Tool(
+        ("kubectl", "version", "--client"),
+        "https://kubernetes.io/docs/tasks/tools/",
+    ),
+    "tinygo": Tool(
+        ("tinygo", "version"), "https://tinygo.org/getting-started/install/"
+    ),
+    "sam": Tool(
+        ("sam", "--version"),
+        "https://docs.aws.amazon.com/serverless-application-model/latest/"
+        "developerguide/install-sam-cli.html",
+    ),
+    "terraform": Tool(
+        ("terraform", "version"),
+        "https://developer.hashicorp.com/terraform/install",
+    ),
+    "zip": Tool(
+        ("zip", "-v"), "your package manager", r"Zip " + _NUMBER
+    ),
+    "elixir": Tool(
+        ("elixir", "--version"),
+        "https://elixir-lang.org/install.html",
+        r"Elixir " + _NUMBER,
+    ),
+    "zig": Tool(("zig", "version"), "https://ziglang.org/download/"),
+    "dune": Tool(("dune", "--version"), "opam install dune"),
+    "ghc": Tool(
+        ("ghc", "--numeric-version"), "https://www.haskell.org/ghcup/"
+    ),
+    "cabal": Tool(
+        ("cabal", "--numeric-version"), "https://www.haskell.org/ghcup/"
+    ),
+    "stack": Tool(
+        ("stack", "--numeric-version"), "https://www.haskell.org/ghcup/"
+    ),
+    "clojure": Tool(
+        ("clojure", "--version"),
+        "https://clojure.org/guides/install_clojure",
+    ),
+    "sbcl": Tool(
+        ("sbcl", "--version"),
+        "https://www.sbcl.org/getting.html",
+        r"SBCL " + _NUMBER,
+    ),
+    "emacs": Tool(
+        ("emacs", "--version"),
+        "https://www.gnu.org/software/emacs/download.html",
+        r"GNU Emacs " + _NUMBER,
+    ),
+    "eask": Tool(("eask", "--version"), "npm install -g @emacs-eask/cli"),
+    "gleam": Tool(
+        ("gleam", "--version"), "https://gleam.run/getting-started/installing/"
+    ),
+    "bash": Tool(
+        ("bash", "--version"), "your package manager", r"version " + _NUMBER
+    ),
+    "bats": Tool(
+        ("bats", "--version"), "https://bats-core.readthedocs.io/en/stable/"
+    ),
+    "busted": Tool(("busted", "--version"), "luarocks install busted"),
+    "cmake": Tool(("cmake", "--version"), "https://cmake.org/download/"),
+    "cc": Tool(("cc", "--version"), "your package manager (gcc or clang)"),
+    "java": Tool(
+        ("java", "-version"), "https://adoptium.net/", r'version "' + _NUMBER
+    ),
+    "gradle": Tool(
+        ("gradle", "--version"),
+        "https://gradle.org/install/",
+        r"Gradle " + _NUMBER,
+    ),
+    "deno": Tool(("deno", "--version"), "https://docs.deno.com/runtime/"),
+    "nix": Tool(
+        ("nix", "--version"),
+        "https://nixos.org/download/",
+    ),
+}
+
+
+@dataclass
+class Check:
+    tool: str
+    minimum: str
+    found: str | None  # the version, "" when unknown, None when missing
+    hint: str
+
+    @property
+    def ok(self) -> bool:
+        if self.found is None:
+            return False
+        if not self.minimum or not self.found:
+            return True
+        return _key(self.found) >= _key(self.minimum)
+
+
+def _key(version: str) -> tuple[int, ...]:
+    parts = [int(n) for n in re.findall(r"\d+", version)[:3]]
+    return tuple(parts + [0] * (3 - len(parts)))
+
+
+def requirements(templates: list) -> dict[str, str]:
+    """Tool -> minimum version over TEMPLATES (the highest one wins)."""
+    need = {}
+    for template in templates:
+        for tool, minimum in template.manifest.get("requires", {}).items():
+            minimum = str(minimum)
+            if tool not in need or _key(minimum) > _key(need[tool]):
+                need[tool] = minimum
+    return need
+
+
+def installed_version(tool: str) -> str | None:
+    """TOOL's version, "" if it is installed but did not say, or None
+    if it is not on PATH."""
+    known = TOOLS.get(tool)
+    command = known.version if known else (tool,)
+    if not shutil.which(command[0]):
+        return None
+    if not known:
+        return ""
+    try:
+        result = subprocess.run(
+            list(command), capture_output=True, text=True, timeout=10
+        )
+    except (OSError, subprocess.TimeoutExpired):
+        return ""
+    match = re.search(known.pattern, result.stdout + result.stderr)
+    return match[1] if match else ""
+
+
+def check(need: dict[str, str]) -> list[Check]:
+    return [
+        Check(
+            tool,
+            minimum,
+            installed_version(tool),
+            TOOLS[tool].hint if tool in TOOLS else "",
+        )
+        for tool, minimum in need.items()
+    ]
+
+
+def print_checks(checks: list[Check]) -> None:
+    """One line per check in ``jolo doctor``'s format, with a hint for
+    each failure."""
+    for c in checks:
+        icon = "ok" if c.ok else "FAIL"
+        if c.found is None:
+            detail = "not installed"
+        else:
+            detail = c.found or "installed"
+        if c.minimum:
+            detail += f", needs {c.minimum}"
+        print(f"  [{icon:>4}] {c.tool}  ({detail})")
+        if not c.ok and c.hint:
+            print(f"         install: {c.hint}")
+
+
+def check_or_exit(templates: list) -> None:
+    """``jolo new --check``: print the checks and exit unless all pass."""
+    checks = check(requirements(templates))
+    print_checks(checks)
+    failed = [c.tool for c in checks if not c.ok]
+    if failed:
+        raise events.Failure(
+            f"Error: missing or too old: {', '.join(failed)} "
+            "(run without --check to create the project anyway)",
+            "tools",
+        )
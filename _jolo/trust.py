@@ -0,0 +1,76 @@
+"""Integrity checks for templates fetched from git.
+
+jolo.lock records a ``checksum`` of a git template's files next to its
+commit. ``jolo upgrade`` and ``jolo diff`` re-fetch the template at
+that commit and refuse to render it if the files no longer hash the same
+(a rewritten cache entry, a host serving different content).
+
+With ``--verify-signature`` (or ``[new] verify_signatures = true``) the
+fetched ref must also carry a signature git accepts: ``git verify-tag``
+for an annotated tag, ``git verify-commit`` otherwise. Keys come from
+git's own configuration, so GPG keyrings, ``gpg.ssh.allowedSignersFile``
+and sigstore's gitsign (as ``gpg.x509.program``) all work.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import subprocess
+import sys
+from pathlib import Path
+
+from _jolo.cli import verbose_cmd
+
+
+def content_hash(root: Path) -> str:
+    """``sha256:…`` over every file below ROOT (but .git): each file's
+    path, executable bit and contents."""
+    digest = hashlib.sha256()
+    files = sorted(
+        p
+        for p in root.rglob("*")
+        if p.is_file() and ".git" not in p.relative_to(root).parts
+    )
+    for path in files:
+        rel = path.relative_to(root).as_posix()
+        mode = "x" if path.stat().st_mode & 0o111 else "-"
+        digest.update(f"{rel}\0{mode}\0".encode())
+        digest.update(hashlib.sha256(path.read_bytes()).digest())
+    return f"sha256:{digest.hexdigest()}"
+
+
+def check_checksum(root: Path, expected: str, label: str) -> None:
+    """Exit unless the files below ROOT hash to EXPECTED."""
+    actual = content_hash(root)
+    if actual != expected:
+        sys.exit(
+            f"Error: {label} does not match the checksum in jolo.lock\n"
+            f"  expected {expected}\n  got      {actual}\n"
+            "Clear it with `jolo cache clean` and retry; if it still "
+            "differs, the source changed under the same commit."
+        )
+
+
+def _signed_object(repo: Path) -> tuple[str, str]:
+    """(git verify command, object) for the ref last fetched into REPO:
+    the tag itself when it was an annotated tag, else the commit."""
+    result = subprocess.run(
+        ["git", "cat-file", "-t", "FETCH_HEAD"],
+        cwd=repo,
+        capture_output=True,
+        text=True,
+    )
+    if result.stdout.strip() == "tag":
+        return "verify-tag", "FETCH_HEAD"
+    return "verify-commit", "HEAD"
+
+
+def verify_signature(repo: Path, label: str) -> None:
+    """Exit unless git accepts the signature on what REPO checked out."""
+    command, obj = _signed_object(repo)
+    cmd = ["git", command, obj]
+    verbose_cmd(cmd)
+    result = subprocess.run(cmd, cwd=repo, capture_output=True, text=True)
+    if result.returncode != 0:
+        detail = result.stderr.strip() or "no valid signature"
+        sys.exit(f"Error: {label} is not signed by a trusted key:\n{detail}")
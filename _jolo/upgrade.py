@@ -0,0 +1,401 @@
+"""jolo upgrade / jolo diff — a generated project against its template.
+
+``jolo new`` leaves a ``jolo.lock`` naming the template, the commit it was
+rendered from, the addons and the answers. Upgrade renders the template
+twice with those answers, at the locked commit (base) and at the current
+one, and three-way merges the difference into the project with
+``git merge-file``. Files both sides changed get standard conflict
+markers; files the project deleted stay deleted. Protected regions
+(``jolo:keep-start`` ... ``jolo:keep-end``, see ``_jolo.regions``) keep
+the project's lines whatever the template did to them.
+
+``jolo diff`` renders only the locked version and prints a unified diff
+from that pristine output to the project's current files.
+
+Both render the project's secrets with the values in its ``.env`` (see
+``_jolo.secret``). For git templates both check the locked commit
+against the lock's ``checksum`` before rendering it.
+
+After ``jolo eject`` the current side is the project's own copy of the
+template, in ``.jolo/templates/``, and the lock's ``ejected_commit``
+the project commit whose copy the next upgrade starts from.
+"""
+
+from __future__ import annotations
+
+import contextlib
+import dataclasses
+import difflib
+import io
+import secrets
+import subprocess
+import sys
+import tarfile
+import tempfile
+from pathlib import Path
+
+from _jolo import log, portable, regions, remote, secret, semver, trust
+from _jolo.cli import verbose_cmd
+from _jolo.commands import load_config
+from _jolo.scaffold import (
+    ADDONS_ROOT,
+    EJECT_DIR,
+    LOCK_FILE,
+    TEMPLATES_ROOT,
+    Template,
+    builtin_commit,
+    check_pin,
+    ejected_template,
+    find_addon,
+    find_template,
+    load_template,
+    project_variables,
+    read_lock,
+    render_modes,
+    render_project,
+    resolve_variables,
+    write_lock,
+)
+from _jolo.templates import _TEMPLATES_DIR
+
+
+def _safe_member(member: tarfile.TarInfo, dest: str):
+    """The data filter, skipping members it refuses (links out of the
+    tree, such as a skill linked from elsewhere) instead of failing."""
+    try:
+        return tarfile.data_filter(member, dest)
+    except tarfile.FilterError:
+        return None
+
+
+@contextlib.contextmanager
+def _archive_at(directory: Path, commit: str):
+    """Yield a temp copy of DIRECTORY as it was at COMMIT."""
+    # Run from a subdirectory, archive holds just that subtree, rooted there.
+    cmd = ["git", "-C", str(directory), "archive", commit]
+    verbose_cmd(cmd)
+    result = subprocess.run(cmd, capture_output=True)
+    if result.returncode != 0:
+        sys.exit(
+            f"Error: cannot read {directory} at {commit}:\n"
+            f"{result.stderr.decode().strip()}"
+        )
+    with tempfile.TemporaryDirectory(prefix="jolo-upgrade-") as tmp:
+        with tarfile.open(fileobj=io.BytesIO(result.stdout)) as tar:
+            tar.extractall(tmp, filter=_safe_member)
+        yield Path(tmp)
+
+
+@contextlib.contextmanager
+def _builtin_at(commit: str):
+    """Yield (lang_root, addons_root) of the builtin templates at COMMIT."""
+    with _archive_at(_TEMPLATES_DIR, commit) as root:
+        yield root / "lang", root / "addons"
+
+
+@contextlib.contextmanager
+def _remote_at(spec: str, ref: str | None):
+    """Yield the template root of SPEC fetched at REF (spec's own if None)."""
+    parsed = remote.parse_spec(spec)
+    if ref:
+        parsed = dataclasses.replace(parsed, ref=ref)
+    root = remote.checkout(parsed)
+    yield load_template(root, spec), remote.head_commit(root)
+
+
+def _render(
+    template: Template,
+    addons_root: Path,
+    lock: dict,
+    no_input: bool,
+    kept: dict[str, str] | None = None,
+) -> tuple[dict[str, str], dict[str, str], dict[str, int]]:
+    """Render TEMPLATE plus the locked addons, with the secrets in KEPT
+    (see project_secrets); return (files, answers, modes)."""
+    addons = [find_addon(a, template, addons_root) for a in lock["addons"]]
+    answers = {}
+    variables = project_variables(lock["name"])
+    for t in (template, *addons):
+        answers.update(
+            resolve_variables(
+                t,
+                lock["variables"],
+                no_input,
+                context={**variables, **answers},
+            )
+        )
+    variables.update(answers)
+    names = {*secret.declared([template, *addons]), "SECRET_SEED"}
+    variables.update({k: v for k, v in (kept or {}).items() if k in names})
+    files = render_project(template, addons, variables)
+    if lock.get("eol"):
+        files = portable.apply_eol(files, lock["eol"])
+    return dict(files), answers, render_modes([template, *addons], variables)
+
+
+def merge_file(ours: str, base: str, theirs: str) -> tuple[str, bool]:
+    """Three-way merge via git merge-file; return (text, conflicted)."""
+    with tempfile.TemporaryDirectory(prefix="jolo-merge-") as tmp:
+        paths = []
+        for name, text in (("ours", ours), ("base", base), ("theirs", theirs)):
+            path = Path(tmp) / name
+            portable.write_text(path, text)
+            paths.append(str(path))
+        cmd = [
+            "git",
+            "merge-file",
+            "-p",
+            "-L",
+            "project",
+            "-L",
+            "template (locked)",
+            "-L",
+            "template (new)",
+            *paths,
+        ]
+        # Bytes, so CRLF files come back as they are.
+        result = subprocess.run(cmd, capture_output=True)
+    if result.returncode < 0 or result.returncode > 127:
+        stderr = result.stderr.decode(errors="replace").strip()
+        sys.exit(f"Error: git merge-file failed:\n{stderr}")
+    return result.stdout.decode(), result.returncode > 0
+
+
+def _read(path: Path, like: str | bytes) -> str | bytes | None:
+    """PATH read the way LIKE is held (bytes or text); None if absent."""
+    if not path.is_file():
+        return None
+    if isinstance(like, bytes):
+        return path.read_bytes()
+    try:
+        return portable.read_text(path)
+    except UnicodeDecodeError:
+        return path.read_bytes()
+
+
+def _write(path: Path, content: str | bytes) -> None:
+    if isinstance(content, bytes):
+        path.write_bytes(content)
+    else:
+        portable.write_text(path, content)
+
+
+def merge_into(
+    project: Path,
+    base: dict[str, str],
+    theirs: dict[str, str],
+    modes: dict[str, int] | None = None,
+) -> dict[str, list[str]]:
+    """Apply the base -> theirs template change to PROJECT; files it adds
+    get their MODES entry.
+
+    Returns the touched paths grouped by what happened to them.
+    """
+    report = {
+        "updated": [],
+        "added": [],
+        "removed": [],
+        "merged": [],
+        "conflicted": [],
+        "kept": [],
+    }
+    for rel in sorted(set(base) | set(theirs)):
+        old, new = base.get(rel), theirs.get(rel)
+        if old == new:
+            continue
+        path = project / rel
+        ours = _read(path, old if new is None else new)
+        if isinstance(ours, str) and isinstance(new, str):
+            # Protected regions keep the project's lines on every side.
+            try:
+                new = regions.preserve(new, ours)
+                if isinstance(old, str):
+                    old = regions.preserve(old, ours)
+            except ValueError as e:
+                sys.exit(f"Error: {rel}: {e}")
+
+        if new is None:
+            if ours == old:
+                path.unlink()
+                report["removed"].append(rel)
+            elif ours is not None:
+                report["kept"].append(rel)
+            continue
+        if ours is None:
+            if old is None:
+                path.parent.mkdir(parents=True, exist_ok=True)
+                _write(path, new)
+                if modes and rel in modes:
+                    path.chmod(modes[rel])
+                report["added"].append(rel)
+            continue
+        if ours == new:
+            continue
+        if ours == old:
+            _write(path, new)
+            report["updated"].append(rel)
+            continue
+        if isinstance(new, bytes) or isinstance(old, bytes):
+            # No line merge for binaries: local edits win.
+            report["kept"].append(rel)
+            continue
+        text, conflicted = merge_file(ours, old or "", new)
+        portable.write_text(path, text)
+        report["conflicted" if conflicted else "merged"].append(rel)
+    return report
+
+
+def project_secrets(project: Path) -> dict[str, str]:
+    """The secrets to render PROJECT with: the ones in its .env, and a
+    seed for the rest that holds for this run (see ``_jolo.secret``)."""
+    return {
+        **secret.read_env(project / secret.ENV_FILE),
+        "SECRET_SEED": secrets.token_hex(16),
+    }
+
+
+def locked_template(
+    lock: dict, stack: contextlib.ExitStack
+) -> tuple[Template, Path]:
+    """The lock's template as it was at the locked commit, and the addons
+    dir to go with it. Once upgraded from an ejected copy, that is the
+    copy at the project commit recorded then."""
+    name = lock["template"]
+    if not lock.get("commit"):
+        sys.exit(f"Error: {LOCK_FILE} records no template commit")
+    if lock.get("ejected_commit"):
+        root = stack.enter_context(
+            _archive_at(Path.cwd() / EJECT_DIR, lock["ejected_commit"])
+        )
+        return find_template(lock["ejected"], root / "lang"), root / "addons"
+    if remote.is_remote(name):
+        template, _ = stack.enter_context(_remote_at(name, lock["commit"]))
+        if lock.get("checksum"):
+            trust.check_checksum(template.path, lock["checksum"], name)
+        return template, ADDONS_ROOT
+    if lock.get("templates_dir"):
+        root = stack.enter_context(
+            _archive_at(Path(lock["templates_dir"]), lock["commit"])
+        )
+        return find_template(name, root), ADDONS_ROOT
+    lang_root, addons_root = stack.enter_context(_builtin_at(lock["commit"]))
+    return find_template(name, lang_root), addons_root
+
+
+def render_locked(
+    lock: dict, stack: contextlib.ExitStack, kept: dict[str, str] | None = None
+) -> dict[str, str]:
+    """Render the lock's template exactly as it was at the locked commit,
+    with the secrets in KEPT."""
+    template, addons_root = locked_template(lock, stack)
+    return _render(template, addons_root, lock, True, kept)[0]
+
+
+def _check_local_version(template: Template, locked: str, ref: str) -> None:
+    """A local template has only its current version: --ref pins what it
+    must be, and without one a new major version is refused."""
+    if ref:
+        check_pin(template, ref)
+        return
+    old, new = semver.parse(locked), semver.parse(template.version)
+    if old and new and new[0] != old[0]:
+        sys.exit(
+            f"Error: {template.name} is now {template.version} (project has "
+            f"{locked}); upgrade with --ref {new[0]} if that is intended"
+        )
+
+
+def run_upgrade_mode(args) -> None:
+    """Re-render ./jolo.lock's template and merge the changes in."""
+    project = Path.cwd()
+    lock = read_lock(project)
+    name = lock["template"]
+    kept = project_secrets(project)
+
+    with contextlib.ExitStack() as stack:
+        base = render_locked(lock, stack, kept)
+        addons_root = ADDONS_ROOT
+        if lock.get("ejected"):
+            template, addons_root = ejected_template(project, lock)
+            commit = remote.head_commit(project)
+        elif remote.is_remote(name):
+            if args.ref:
+                name = remote.pin(f"{remote.split_ref(name)[0]}@{args.ref}")
+            template, commit = stack.enter_context(_remote_at(name, None))
+            new_cfg = load_config().get("new", {})
+            if args.verify_signature or new_cfg.get("verify_signatures"):
+                trust.verify_signature(template.path, name)
+        else:
+            if lock.get("templates_dir"):
+                template = find_template(name, Path(lock["templates_dir"]))
+                commit = remote.head_commit(template.path)
+            else:
+                template = find_template(name, TEMPLATES_ROOT)
+                commit = builtin_commit()
+            _check_local_version(template, lock.get("version", ""), args.ref)
+        theirs, answers, modes = _render(
+            template, addons_root, lock, args.no_input, kept
+        )
+
+    report = merge_into(project, base, theirs, modes)
+    if lock.get("ejected"):
+        # The commit stays the one ejected: the copy has no other.
+        updated = {**lock, "ejected_commit": commit}
+    else:
+        updated = {**lock, "template": name, "commit": commit}
+    updated.pop("version", None)
+    if template.version:
+        updated["version"] = template.version
+    if remote.is_remote(name) and not lock.get("ejected"):
+        updated["checksum"] = trust.content_hash(template.path)
+    write_lock(project, {**updated, "variables": answers})
+
+    old, new = lock.get("version"), template.version
+    if old and new and old != new:
+        log.summary(f"{name}: {old} -> {new}")
+    if not any(report.values()):
+        log.summary(f"{project.name} is up to date with {name}")
+        return
+    for key, paths in report.items():
+        if paths:
+            log.summary(f"{key.capitalize()}: {', '.join(paths)}")
+    if report["conflicted"]:
+        sys.exit("Resolve the conflict markers, then commit.")
+
+
+def project_diff(project: Path, pristine: dict[str, str]) -> list[str]:
+    """Unified diff lines from PRISTINE template output to PROJECT."""
+    lines = []
+    for rel, old in sorted(pristine.items()):
+        path = project / rel
+        new = _read(path, old)
+        if new == old:
+            continue
+        if isinstance(old, bytes) or isinstance(new, bytes):
+            to = f"b/{rel}" if new is not None else "/dev/null"
+            lines.append(f"Binary files a/{rel} and {to} differ\n")
+            continue
+        new = new or ""
+        for line in difflib.unified_diff(
+            old.splitlines(keepends=True),
+            new.splitlines(keepends=True),
+            fromfile=f"a/{rel}",
+            tofile=f"b/{rel}" if path.is_file() else "/dev/null",
+        ):
+            if not line.endswith("\n"):
+                line += "\n\\ No newline at end of file\n"
+            lines.append(line)
+    return lines
+
+
+def run_diff_mode(args) -> None:
+    """Show how ./ drifted from the template output it was generated as."""
+    project = Path.cwd()
+    lock = read_lock(project)
+    with contextlib.ExitStack() as stack:
+        pristine = render_locked(lock, stack, project_secrets(project))
+    lines = project_diff(project, pristine)
+    if not lines:
+        log.summary(f"{project.name} matches {lock['template']}")
+        return
+    sys.stdout.writelines(lines)
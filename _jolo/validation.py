@@ -0,0 +1,117 @@
+"""Validation rules for the answers a template is rendered with.
+
+A ``[variables.NAME]`` table in ``template.toml`` may constrain what it
+accepts, on top of its type::
+
+    [variables.service]
+    pattern = "[a-z][a-z0-9-]*"   # the whole answer must match
+    min = 3                       # an int's least value, a string's
+    max = 30                      #   shortest and longest length
+    required = true               # an empty answer is not an answer
+    choices = ["api", "worker"]   # for a string or int
+    message = "service names are lowercase, e.g. billing-api"
+
+``[validate.NAME]`` tables hold the same rules for the values jolo
+supplies itself (``BUILTIN``): the project name, the Go module path,
+and so on. Every template and addon being rendered gets its say.
+
+An answer that breaks a rule is an error naming the variable and the
+rule, or ``message`` when the manifest gives one. Typed at a prompt, it
+is asked again; from ``--var``, an answers file or the config, ``jolo
+new`` stops before writing anything.
+"""
+
+from __future__ import annotations
+
+import re
+import sys
+
+RULES = ("pattern", "min", "max", "required", "choices", "message")
+BUILTIN = (
+    "PROJECT_NAME",
+    "MODULE_PATH",
+    "AUTHOR",
+    "EMAIL",
+    "DESCRIPTION",
+    "LICENSE",
+)
+
+
+def spec_problem(spec: dict) -> str | None:
+    """What is wrong with SPEC's rules, if anything."""
+    kind = spec.get("type", "string")
+    if "pattern" in spec:
+        try:
+            re.compile(spec["pattern"])
+        except (re.error, TypeError) as e:
+            return f"bad pattern {spec['pattern']!r}: {e}"
+    for bound in ("min", "max"):
+        if bound not in spec:
+            continue
+        if kind not in ("string", "int"):
+            return f"{bound} needs a string or int, not {kind}"
+        if not isinstance(spec[bound], int) or isinstance(spec[bound], bool):
+            return f"{bound} must be an integer"
+    return None
+
+
+def check(spec: dict, value: str) -> None:
+    """Raise ValueError if VALUE (as rendered) breaks one of SPEC's rules."""
+    problem = _broken(spec, value)
+    if problem:
+        raise ValueError(spec.get("message") or problem)
+
+
+def _broken(spec: dict, value: str) -> str | None:
+    kind = spec.get("type", "string")
+    if spec.get("required") and value == "":
+        return "a value is required"
+    # A choice or multi variable's type already checks its choices.
+    choices = None if kind in ("choice", "multi") else spec.get("choices")
+    if choices and value not in map(str, choices):
+        return f"expected one of {', '.join(map(str, choices))}, got {value!r}"
+    pattern = spec.get("pattern")
+    if pattern and not re.fullmatch(pattern, value):
+        return f"must match {pattern}, got {value!r}"
+    if kind == "int":
+        size, unit = int(value), ""
+    elif kind == "string":
+        size, unit = len(value), " characters long"
+    else:
+        return None
+    if "min" in spec and size < spec["min"]:
+        return f"must be at least {spec['min']}{unit}, got {value!r}"
+    if "max" in spec and size > spec["max"]:
+        return f"must be at most {spec['max']}{unit}, got {value!r}"
+    return None
+
+
+def rules(templates: list, name: str) -> list[dict]:
+    """The ``[validate.NAME]`` tables of TEMPLATES, checked."""
+    found = []
+    for template in templates:
+        spec = template.manifest.get("validate", {}).get(name)
+        if spec is None:
+            continue
+        problem = spec_problem(spec)
+        if problem:
+            sys.exit(f"Error: {template.name}: validate.{name}: {problem}")
+        found.append(spec)
+    return found
+
+
+def check_builtin(templates: list, name: str, value: str) -> None:
+    """Raise ValueError if VALUE breaks a rule TEMPLATES set for NAME."""
+    for spec in rules(templates, name):
+        check(spec, value)
+
+
+def check_builtins(templates: list, variables: dict[str, str]) -> None:
+    """Exit if any of the BUILTIN VARIABLES breaks a rule."""
+    for name in BUILTIN:
+        if name not in variables:
+            continue
+        try:
+            check_builtin(templates, name, str(variables[name]))
+        except ValueError as e:
+            sys.exit(f"Error: {name}: {e}")
@@ -0,0 +1,161 @@
+"""jolo verify — render every template and run its own build and tests.
+
+Each template is rendered with default answers into a temp dir, its
+hooks run, and then the commands in its manifest's ``[verify] run``
+list (``go vet ./...``, ``cargo check``, ...). Rendered text that still
+contains a jolo-style placeholder (``{{PROJECT_NAME}}``, ``{{ X | f }}``)
+fails the template before anything runs. A command whose program is not
+installed skips the template rather than failing it.
+
+This is for template maintainers; nothing here touches the current
+directory.
+"""
+
+from __future__ import annotations
+
+import re
+import shutil
+import subprocess
+import sys
+import tempfile
+import time
+from dataclasses import dataclass
+from pathlib import Path
+
+from _jolo.cli import verbose_print
+from _jolo.commands import load_config
+from _jolo.install import missing_program
+from _jolo.scaffold import (
+    Template,
+    config_variables,
+    find_layered,
+    list_layered,
+    project_variables,
+    render_modes,
+    render_project,
+    resolve_variables,
+    template_hooks,
+    template_roots,
+    write_files,
+)
+
+PROJECT_NAME = "verify-app"
+# Upper-case names are jolo's; just and Jinja use lower case.
+_LEAK = re.compile(r"\{\{\s*[A-Z][A-Z0-9_]*\s*(\|[^}]*)?\}\}")
+
+
+@dataclass
+class Result:
+    template: str
+    status: str  # pass, fail, skip
+    detail: str = ""
+    seconds: float = 0.0
+
+
+def find_leaks(files: list[tuple[str, str | bytes]]) -> list[str]:
+    """``path:line: {{X}}`` for every unrendered placeholder."""
+    leaks = []
+    for rel, content in files:
+        if isinstance(content, bytes):
+            continue
+        if _LEAK.search(rel):
+            leaks.append(f"{rel}: path")
+        for n, line in enumerate(content.splitlines(), 1):
+            for match in _LEAK.finditer(line):
+                leaks.append(f"{rel}:{n}: {match[0]}")
+    return leaks
+
+
+def _run(commands: list[str], cwd: Path) -> str | None:
+    """Run COMMANDS in CWD; return the first failure's report."""
+    for cmd in commands:
+        verbose_print(f"[{cwd.name}] {cmd}")
+        result = subprocess.run(
+            cmd,
+            shell=True,
+            cwd=cwd,
+            capture_output=True,
+            text=True,
+        )
+        if result.returncode != 0:
+            output = (result.stdout + result.stderr).strip()
+            tail = "\n".join(output.splitlines()[-20:])
+            return f"{cmd}: exit {result.returncode}\n{tail}".rstrip()
+    return None
+
+
+def verify_template(template: Template, work: Path) -> Result:
+    """Render TEMPLATE into WORK and run its hooks and verify commands."""
+    variables = project_variables(PROJECT_NAME)
+    try:
+        variables.update(config_variables({}, PROJECT_NAME))
+        variables.update(resolve_variables(template, {}, no_input=True))
+        files = render_project(template, [], variables)
+    except SystemExit as e:
+        return Result(template.name, "fail", str(e.code))
+
+    leaks = find_leaks(files)
+    if leaks:
+        detail = "unrendered placeholders:\n" + "\n".join(leaks)
+        return Result(template.name, "fail", detail)
+
+    pre = template_hooks([template], "pre", variables)
+    post = template_hooks([template], "post", variables)
+    checks = template.manifest.get("verify", {}).get("run", [])
+    missing = missing_program([*pre, *post, *checks])
+    if missing:
+        return Result(template.name, "skip", f"{missing} not installed")
+
+    target = work / PROJECT_NAME
+    target.mkdir(parents=True)
+    failure = _run(pre, target)
+    if failure is None:
+        write_files(files, target, render_modes([template], variables))
+        failure = _run([*post, *checks], target)
+    if failure:
+        return Result(template.name, "fail", failure)
+    return Result(template.name, "pass")
+
+
+def print_result(result: Result) -> None:
+    label = {"pass": "PASS", "fail": "FAIL", "skip": "SKIP"}[result.status]
+    line = f"{label}  {result.template}  ({result.seconds:.1f}s)"
+    if result.status == "skip":
+        line += f"  {result.detail}"
+    print(line)
+    if result.status == "fail":
+        for detail in result.detail.splitlines():
+            print(f"      {detail}")
+
+
+def run_verify_mode(args) -> None:
+    """Verify the named templates, or all of them."""
+    new_cfg = load_config().get("new", {})
+    roots = template_roots(args.templates_dir, new_cfg)
+    if args.templates:
+        templates = [find_layered(name, roots)[0] for name in args.templates]
+    else:
+        templates = list_layered(roots)
+
+    results = []
+    for template in templates:
+        work = Path(tempfile.mkdtemp(prefix="jolo-verify-"))
+        start = time.monotonic()
+        try:
+            result = verify_template(template, work)
+        finally:
+            if not args.keep:
+                shutil.rmtree(work)
+        result.seconds = time.monotonic() - start
+        print_result(result)
+        if args.keep:
+            print(f"      kept in {work / PROJECT_NAME}")
+        results.append(result)
+
+    counts = {
+        status: sum(r.status == status for r in results)
+        for status in ("pass", "fail", "skip")
+    }
+    print(", ".join(f"{n} {status}" for status, n in counts.items()))
+    if counts["fail"]:
+        sys.exit(1)
@@ -0,0 +1,163 @@
+"""Destinations for rendered files: a directory, or an archive.
+
+``jolo new --output shop.zip`` (or ``.tar``, ``.tar.gz``, ``.tgz``)
+writes the project into an archive whose entries all start with
+``shop/``, and ``--output -`` streams it to stdout as a tar, so a
+scaffold can be piped into other tooling or made where jolo cannot
+write the final directory.
+"""
+
+from __future__ import annotations
+
+import os
+import shutil
+import sys
+import tarfile
+import time
+import zipfile
+from io import BytesIO
+from pathlib import Path, PurePath
+from typing import BinaryIO
+
+from _jolo import portable
+from _jolo.portable import Link
+from _jolo.streaming import Streamed
+
+# --output suffix -> tar compression (None: a zip)
+SUFFIXES = {".zip": None, ".tar": "", ".tar.gz": "gz", ".tgz": "gz"}
+
+
+class Writer:
+    """Somewhere to put files; used as a context manager, it is closed
+    (and an archive finished) on exit."""
+
+    def write(
+        self, rel: str, content: str | bytes, mode: int | None = None
+    ) -> None:
+        raise NotImplementedError
+
+    def close(self) -> None:
+        pass
+
+    def __enter__(self):
+        return self
+
+    def __exit__(self, *exc) -> None:
+        self.close()
+
+
+class DirWriter(Writer):
+    def __init__(self, root: Path):
+        self.root = root
+
+    def write(self, rel, content, mode=None) -> None:
+        path = self.root / rel
+        path.parent.mkdir(parents=True, exist_ok=True)
+        if isinstance(content, Link):
+            if path.is_symlink() or path.is_file():
+                path.unlink()
+            try:
+                os.symlink(str(PurePath(content.target)), path)
+                return
+            except (OSError, NotImplementedError):
+                # Windows without the privilege to link: copy.
+                content = content.content
+        if isinstance(content, Streamed):
+            with path.open("wb") as f:
+                shutil.copyfileobj(content.open(), f)
+        elif isinstance(content, bytes):
+            path.write_bytes(content)
+        else:
+            portable.write_text(path, content)
+        if mode is not None:
+            path.chmod(mode)
+
+
+class ZipWriter(Writer):
+    def __init__(self, file: BinaryIO, prefix: str, owned: bool = False):
+        self.file, self.prefix, self.owned = file, prefix, owned
+        self.zip = zipfile.ZipFile(file, "w", zipfile.ZIP_DEFLATED)
+
+    def write(self, rel, content, mode=None) -> None:
+        info = zipfile.ZipInfo(f"{self.prefix}/{rel}", time.localtime()[:6])
+        info.external_attr = (0o100000 | (mode or 0o644)) << 16
+        info.compress_type = zipfile.ZIP_DEFLATED
+        if isinstance(content, Link):  # zip has no portable symlinks
+            content = content.content
+        if isinstance(content, Streamed):
+            with self.zip.open(info, "w", force_zip64=True) as f:
+                shutil.copyfileobj(content.open(), f)
+            return
+        data = content if isinstance(content, bytes) else content.encode()
+        self.zip.writestr(info, data)
+
+    def close(self) -> None:
+        self.zip.close()
+        if self.owned:
+            self.file.close()
+
+
+class TarWriter(Writer):
+    """A tar stream (``w|``), so FILE need not be seekable."""
+
+    def __init__(
+        self,
+        file: BinaryIO,
+        prefix: str,
+        compression: str = "",
+        owned: bool = False,
+    ):
+        self.file, self.prefix, self.owned = file, prefix, owned
+        self.tar = tarfile.open(fileobj=file, mode=f"w|{compression}")
+
+    def write(self, rel, content, mode=None) -> None:
+        info = tarfile.TarInfo(f"{self.prefix}/{rel}")
+        info.mode = mode or 0o644
+        info.mtime = int(time.time())
+        if isinstance(content, Link):
+            info.type, info.linkname = tarfile.SYMTYPE, content.target
+            self.tar.addfile(info)
+            return
+        if isinstance(content, Streamed):
+            info.size = content.size
+            self.tar.addfile(info, content.open())
+            return
+        data = content if isinstance(content, bytes) else content.encode()
+        info.size = len(data)
+        self.tar.addfile(info, BytesIO(data))
+
+    def close(self) -> None:
+        self.tar.close()
+        if self.owned:
+            self.file.close()
+        else:
+            self.file.flush()
+
+
+def archive_writer(file: BinaryIO, prefix: str, fmt: str) -> Writer:
+    """A writer of FMT (``zip``, ``tar`` or ``tar.gz``) into FILE."""
+    if fmt == "zip":
+        return ZipWriter(file, prefix)
+    return TarWriter(file, prefix, "gz" if fmt.endswith("gz") else "")
+
+
+def open_output(output: str, prefix: str) -> Writer:
+    """The writer for ``--output OUTPUT``: ``-`` is a tar on stdout."""
+    if output == "-":
+        if sys.stdout.isatty():
+            sys.exit("Error: Not writing a tar stream to a terminal")
+        sys.stdout.flush()
+        return TarWriter(sys.stdout.buffer, prefix)
+    suffix = next((s for s in SUFFIXES if output.endswith(s)), None)
+    if suffix is None:
+        sys.exit(
+            f"Error: --output {output}: expected a .zip, .tar, .tar.gz "
+            "or .tgz file, or - for stdout"
+        )
+    path = Path(output).expanduser()
+    if path.exists():
+        sys.exit(f"Error: Already exists: {path}")
+    file = open(path, "wb")
+    if SUFFIXES[suffix] is None:
+        return ZipWriter(file, prefix, owned=True)
+    return TarWriter(file, prefix, SUFFIXES[suffix], owned=True)
@@ -0,0 +1,134 @@
+#!/usr/bin/env python3
+"""Benchmark jolo new's render-and-write pipeline at several --jobs.
+
+Renders a synthetic template (``--files`` files of ``--size`` bytes,
+with placeholders, spread over nested directories) or a real one
+(``--template go/web``) into a temp directory, ``--repeat`` times per
+job count, and prints the best and median time and the speedup over
+``--jobs 1``. ``--latency MS`` adds a delay to every file read and
+write, to stand in for a network filesystem::
+
+    python3 benchmarks/render.py
+    python3 benchmarks/render.py --files 1000 --latency 2 --jobs 1,8,32
+    just bench --template go/web
+"""
+
+from __future__ import annotations
+
+import argparse
+import contextlib
+import shutil
+import statistics
+import sys
+import tempfile
+import time
+from pathlib import Path
+from unittest import mock
+
+sys.path.insert(0, str(Path(__file__).resolve().parent.parent))
+
+from _jolo import constants, parallel, scaffold  # noqa: E402
+
+LINE = "// {{PROJECT_NAME | pascal}} ({{MODULE_PATH}}): {{PROJECT_NAME}}\n"
+
+
+def synthetic(root: Path, files: int, size: int) -> scaffold.Template:
+    """A template of FILES text files of about SIZE bytes each."""
+    path = root / "bench"
+    path.mkdir()
+    (path / scaffold.MANIFEST_FILE).write_text('description = "bench"\n')
+    body = LINE * max(size // len(LINE), 1)
+    for i in range(files):
+        rel = path / f"pkg{i % 20}" / f"sub{i % 7}" / f"file{i}.go"
+        rel.parent.mkdir(parents=True, exist_ok=True)
+        rel.write_text(body)
+    return scaffold.load_template(path, "bench")
+
+
+@contextlib.contextmanager
+def latency(ms: float):
+    """Delay each file read and write by MS milliseconds."""
+    if not ms:
+        yield
+        return
+    delay = ms / 1000
+    originals = {
+        name: getattr(Path, name)
+        for name in ("read_bytes", "write_bytes", "write_text")
+    }
+
+    def slow(original):
+        def method(self, *args, **kwargs):
+            time.sleep(delay)
+            return original(self, *args, **kwargs)
+
+        return method
+
+    with contextlib.ExitStack() as stack:
+        for name, original in originals.items():
+            stack.enter_context(mock.patch.object(Path, name, slow(original)))
+        yield
+
+
+def run_once(template, variables: dict, out: Path) -> float:
+    start = time.perf_counter()
+    files = scaffold.render_project(template, [], variables)
+    scaffold.write_files(files, out)
+    elapsed = time.perf_counter() - start
+    shutil.rmtree(out)
+    return elapsed
+
+
+def main(argv: list[str] | None = None) -> None:
+    parser = argparse.ArgumentParser(description=__doc__.splitlines()[0])
+    parser.add_argument("--template", help="a builtin or local template")
+    parser.add_argument("--files", type=int, default=400)
+    parser.add_argument("--size", type=int, default=4096)
+    parser.add_argument("--latency", type=float, default=0.0, metavar="MS")
+    parser.add_argument("--repeat", type=int, default=5)
+    parser.add_argument(
+        "--jobs",
+        default=f"1,4,{parallel.jobs()}",
+        help="comma-separated job counts (default: 1,4,auto)",
+    )
+    args = parser.parse_args(argv)
+    counts = sorted({int(n) for n in args.jobs.split(",")})
+
+    with tempfile.TemporaryDirectory() as tmp:
+        root = Path(tmp)
+        if args.template:
+            template, _ = scaffold.find_pinned(
+                args.template, scaffold.template_roots([], {})
+            )
+            label = args.template
+        else:
+            template = synthetic(root, args.files, args.size)
+            label = f"{args.files} files of {args.size} bytes"
+        variables = {
+            **scaffold.project_variables("bench-app"),
+            **scaffold.config_variables({}, "bench-app"),
+            "UUID": "00000000-0000-0000-0000-000000000000",
+        }
+        variables.update(scaffold.resolve_variables(template, {}, True))
+        if args.latency:
+            label += f", {args.latency:g} ms per file operation"
+        print(f"{label}, best of {args.repeat}")
+        baseline = None
+        for jobs in counts:
+            constants.JOBS = jobs
+            with latency(args.latency):
+                times = [
+                    run_once(template, variables, root / "out")
+                    for _ in range(args.repeat)
+                ]
+            best = min(times)
+            baseline = baseline or best
+            print(
+                f"  --jobs {jobs:<3} best {best * 1000:8.1f} ms  "
+                f"median {statistics.median(times) * 1000:8.1f} ms  "
+                f"{baseline / best:5.2f}x"
+            )
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"{{MODULE_PATH}}/components"
+	"{{MODULE_PATH}}/internal/auth"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func handleRegisterForm(w http.ResponseWriter, r *http.Request) {
+	components.Page("Create an account", components.RegisterForm("", "")).Render(r.Context(), w)
+}
+
+// handleRegister creates the account and logs it in, or shows the form
+// again with what was wrong.
+func handleRegister(accounts *auth.Accounts) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := r.FormValue("email")
+		u, err := accounts.Register(r.Context(), email, r.FormValue("password"))
+		if errors.Is(err, auth.ErrInvalidEmail) || errors.Is(err, auth.ErrPasswordLength) || errors.Is(err, auth.ErrEmailTaken) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			components.Page("Create an account", components.RegisterForm(email, err.Error())).Render(r.Context(), w)
+			return
+		}
+		if err == nil {
+			err = accounts.Login(r.Context(), u)
+		}
+		if err != nil {
+			serverError(w, r, "register", err)
+			return
+		}
+		http.Redirect(w, r, "/account", http.StatusSeeOther)
+	}
+}
+
+func handleLoginForm(w http.ResponseWriter, r *http.Request) {
+	next := r.URL.Query().Get("next")
+	components.Page("Log in", components.LoginForm("", next, "")).Render(r.Context(), w)
+}
+
+// handleLogin logs the user in and sends them where they were going.
+func handleLogin(accounts *auth.Accounts) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email, next := r.FormValue("email"), r.FormValue("next")
+		u, err := accounts.Authenticate(r.Context(), email, r.FormValue("password"))
+		if errors.Is(err, auth.ErrBadCredentials) {
+			w.WriteHeader(http.StatusUnauthorized)
+			components.Page("Log in", components.LoginForm(email, next, err.Error())).Render(r.Context(), w)
+			return
+		}
+		if err == nil {
+			err = accounts.Login(r.Context(), u)
+		}
+		if err != nil {
+			serverError(w, r, "login", err)
+			return
+		}
+		http.Redirect(w, r, localPath(next, "/account"), http.StatusSeeOther)
+	}
+}
+
+func handleLogout(accounts *auth.Accounts) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := accounts.Logout(r.Context()); err != nil {
+			serverError(w, r, "logout", err)
+			return
+		}
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
+
+// handleAccount is a page only logged-in users see (main wraps it in
+// RequireLogin).
+func handleAccount(w http.ResponseWriter, r *http.Request) {
+	u, _ := auth.UserFrom(r.Context())
+	components.Page("Your account", components.Account(u.Email)).Render(r.Context(), w)
+}
+
+// localPath is next if it is a path on this site, else fallback, so a
+// login link cannot send users on to another one.
+func localPath(next, fallback string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return fallback
+	}
+	return next
+}
+
+func serverError(w http.ResponseWriter, r *http.Request, what string, err error) {
+	logging.FromContext(r.Context()).Error(what, "err", err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
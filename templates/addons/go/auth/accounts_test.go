@@ -0,0 +1,125 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/auth"
+	"{{MODULE_PATH}}/internal/config"
+)
+
+// accountsApp is the account routes as main mounts them, with users in
+// memory, and a client that keeps the session cookie.
+type accountsApp struct {
+	handler http.Handler
+	cookie  *http.Cookie
+}
+
+func newAccountsApp(t *testing.T) *accountsApp {
+	sessions := newSessions(config.Config{BaseURL: "http://localhost:4000"})
+	accounts := &auth.Accounts{Users: auth.NewMemoryUsers(), Sessions: sessions}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /register", handleRegisterForm)
+	mux.HandleFunc("POST /register", handleRegister(accounts))
+	mux.HandleFunc("GET /login", handleLoginForm)
+	mux.HandleFunc("POST /login", handleLogin(accounts))
+	mux.HandleFunc("POST /logout", handleLogout(accounts))
+	mux.Handle("GET /account", accounts.RequireLogin(http.HandlerFunc(handleAccount)))
+	return &accountsApp{handler: sessions.LoadAndSave(mux)}
+}
+
+func (a *accountsApp) do(method, target string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if a.cookie != nil {
+		req.AddCookie(a.cookie)
+	}
+	w := httptest.NewRecorder()
+	a.handler.ServeHTTP(w, req)
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "session" {
+			a.cookie = c
+		}
+	}
+	return w
+}
+
+func expect(t *testing.T, w *httptest.ResponseRecorder, status int, location string) {
+	t.Helper()
+	if w.Code != status || w.Header().Get("Location") != location {
+		t.Fatalf("expected %d to %q, got %d to %q: %s", status, location, w.Code, w.Header().Get("Location"), w.Body)
+	}
+}
+
+func TestRegisterLoginLogout(t *testing.T) {
+	app := newAccountsApp(t)
+	expect(t, app.do("GET", "/account", nil), http.StatusSeeOther, "/login?next=%2Faccount")
+
+	creds := url.Values{"email": {" Ada@Example.com "}, "password": {"correct horse"}}
+	expect(t, app.do("POST", "/register", creds), http.StatusSeeOther, "/account")
+	w := app.do("GET", "/account", nil)
+	expect(t, w, http.StatusOK, "")
+	if !strings.Contains(w.Body.String(), "ada@example.com") {
+		t.Errorf("expected the account page to name the user: %s", w.Body)
+	}
+
+	expect(t, app.do("POST", "/logout", nil), http.StatusSeeOther, "/")
+	expect(t, app.do("GET", "/account", nil), http.StatusSeeOther, "/login?next=%2Faccount")
+
+	creds.Set("next", "/account?tab=1")
+	expect(t, app.do("POST", "/login", creds), http.StatusSeeOther, "/account?tab=1")
+	expect(t, app.do("GET", "/account", nil), http.StatusOK, "")
+}
+
+func TestLoginRenewsTheSessionToken(t *testing.T) {
+	app := newAccountsApp(t)
+	app.do("POST", "/register", url.Values{"email": {"ada@example.com"}, "password": {"correct horse"}})
+	before := app.cookie
+	app.do("POST", "/login", url.Values{"email": {"ada@example.com"}, "password": {"correct horse"}})
+	if before == nil || app.cookie.Value == before.Value {
+		t.Error("expected a new session token after logging in")
+	}
+}
+
+func TestFormsShowWhatIsWrong(t *testing.T) {
+	app := newAccountsApp(t)
+	app.do("POST", "/register", url.Values{"email": {"ada@example.com"}, "password": {"correct horse"}})
+	app.do("POST", "/logout", nil)
+	tests := []struct {
+		name, target string
+		form         url.Values
+		status       int
+		problem      string
+	}{
+		{"bad email", "/register", url.Values{"email": {"ada"}, "password": {"correct horse"}}, http.StatusUnprocessableEntity, auth.ErrInvalidEmail.Error()},
+		{"short password", "/register", url.Values{"email": {"bob@example.com"}, "password": {"short"}}, http.StatusUnprocessableEntity, "use a password of"},
+		{"taken email", "/register", url.Values{"email": {"ada@example.com"}, "password": {"correct horse"}}, http.StatusUnprocessableEntity, "already exists"},
+		{"wrong password", "/login", url.Values{"email": {"ada@example.com"}, "password": {"wrong horse"}}, http.StatusUnauthorized, auth.ErrBadCredentials.Error()},
+		{"no such user", "/login", url.Values{"email": {"eve@example.com"}, "password": {"correct horse"}}, http.StatusUnauthorized, auth.ErrBadCredentials.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := app.do("POST", tt.target, tt.form)
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.problem) {
+				t.Errorf("expected %d saying %q, got %d: %s", tt.status, tt.problem, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	for next, want := range map[string]string{
+		"/account?tab=1":       "/account?tab=1",
+		"":                     "/account",
+		"https://evil.example": "/account",
+		"//evil.example":       "/account",
+		"/\\evil.example":      "/account",
+	} {
+		if got := localPath(next, "/account"); got != want {
+			t.Errorf("localPath(%q) = %q, want %q", next, got, want)
+		}
+	}
+}
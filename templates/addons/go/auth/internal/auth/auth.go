@@ -0,0 +1,141 @@
+// Package auth is the app's accounts: registering with an email and a
+// password (hashed with argon2id), logging in and out, and the
+// middleware that keeps pages to logged-in users.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+const (
+	MinPasswordLength = 8
+	// Longer passwords only cost hashing time.
+	MaxPasswordLength = 256
+	sessionKey        = "auth.user_id"
+)
+
+var (
+	ErrInvalidEmail   = errors.New("enter a valid email address")
+	ErrPasswordLength = fmt.Errorf("use a password of %d to %d characters", MinPasswordLength, MaxPasswordLength)
+	ErrBadCredentials = errors.New("wrong email or password")
+)
+
+// dummyHash is checked against when there is no such user, so a login
+// takes as long whether or not the email has an account.
+var dummyHash, _ = HashPassword("not anyone's password")
+
+// Accounts registers users and logs them in and out, keeping the ID of
+// the one logged in in their session.
+type Accounts struct {
+	Users    Users
+	Sessions *scs.SessionManager
+}
+
+// Register creates the account, failing with ErrInvalidEmail,
+// ErrPasswordLength or ErrEmailTaken for the form to show.
+func (a *Accounts) Register(ctx context.Context, email, password string) (User, error) {
+	email, ok := normalizeEmail(email)
+	if !ok {
+		return User{}, ErrInvalidEmail
+	}
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return User{}, ErrPasswordLength
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return User{}, err
+	}
+	return a.Users.Create(ctx, email, hash)
+}
+
+// Authenticate is the user with email and password, or ErrBadCredentials.
+func (a *Accounts) Authenticate(ctx context.Context, email, password string) (User, error) {
+	email, _ = normalizeEmail(email)
+	u, err := a.Users.ByEmail(ctx, email)
+	if errors.Is(err, ErrNoUser) {
+		CheckPassword(dummyHash, password)
+		return User{}, ErrBadCredentials
+	}
+	if err != nil {
+		return User{}, err
+	}
+	if u.PasswordHash == "" { // made by signing in elsewhere
+		CheckPassword(dummyHash, password)
+		return User{}, ErrBadCredentials
+	}
+	ok, err := CheckPassword(u.PasswordHash, password)
+	if err != nil {
+		return User{}, err
+	}
+	if !ok {
+		return User{}, ErrBadCredentials
+	}
+	return u, nil
+}
+
+// Login makes u the session's user, under a new session token so one
+// planted before the login is worth nothing after it.
+func (a *Accounts) Login(ctx context.Context, u User) error {
+	if err := a.Sessions.RenewToken(ctx); err != nil {
+		return err
+	}
+	a.Sessions.Put(ctx, sessionKey, u.ID)
+	return nil
+}
+
+// Logout ends the session, and with it everything else it held.
+func (a *Accounts) Logout(ctx context.Context) error {
+	return a.Sessions.Destroy(ctx)
+}
+
+// RequireLogin serves next to logged-in users, with their User in the
+// context (UserFrom). Others are sent to /login to come back after, or
+// get a 401 for anything but a GET.
+func (a *Accounts) RequireLogin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, err := User{}, ErrNoUser
+		if id := a.Sessions.GetInt64(r.Context(), sessionKey); id != 0 {
+			u, err = a.Users.ByID(r.Context(), id)
+		}
+		if errors.Is(err, ErrNoUser) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			logging.FromContext(r.Context()).Error("load user", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, u)))
+	})
+}
+
+type userKey struct{}
+
+// UserFrom is the logged-in user RequireLogin put in ctx.
+func UserFrom(ctx context.Context) (User, bool) {
+	u, ok := ctx.Value(userKey{}).(User)
+	return u, ok
+}
+
+// normalizeEmail is email trimmed and lowercased, and whether it is a
+// bare address.
+func normalizeEmail(email string) (string, bool) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	addr, err := mail.ParseAddress(email)
+	return email, err == nil && addr.Address == email
+}
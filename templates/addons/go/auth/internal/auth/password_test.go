@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$") {
+		t.Errorf("unexpected hash format: %s", hash)
+	}
+	if other, _ := HashPassword("correct horse"); other == hash {
+		t.Error("expected a fresh salt for every hash")
+	}
+	for password, want := range map[string]bool{"correct horse": true, "correct horsE": false, "": false} {
+		if ok, err := CheckPassword(hash, password); err != nil || ok != want {
+			t.Errorf("CheckPassword(%q) = %v, %v; want %v", password, ok, err, want)
+		}
+	}
+	if _, err := CheckPassword("$2a$10$bcrypt", "x"); err == nil {
+		t.Error("expected an error for a hash that is not argon2id")
+	}
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"{{MODULE_PATH}}/internal/postgres"
+)
+
+// PostgresUsers keeps the accounts in PostgreSQL's users table.
+type PostgresUsers struct {
+	q *postgres.Queries
+}
+
+func NewPostgresUsers(q *postgres.Queries) *PostgresUsers {
+	return &PostgresUsers{q: q}
+}
+
+func (p *PostgresUsers) Create(ctx context.Context, email, passwordHash string) (User, error) {
+	u, err := p.q.CreateUser(ctx, email, passwordHash)
+	if errors.Is(err, pgx.ErrNoRows) { // ON CONFLICT DO NOTHING
+		return User{}, ErrEmailTaken
+	}
+	return User(u), err
+}
+
+func (p *PostgresUsers) ByEmail(ctx context.Context, email string) (User, error) {
+	u, err := p.q.UserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, ErrNoUser
+	}
+	return User(u), err
+}
+
+func (p *PostgresUsers) ByID(ctx context.Context, id int64) (User, error) {
+	u, err := p.q.UserByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, ErrNoUser
+	}
+	return User(u), err
+}
@@ -0,0 +1,45 @@
+//go:build integration
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/postgres"
+)
+
+func TestPostgresUsers(t *testing.T) {
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	pool, err := postgres.Open(ctx, url, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	users := NewPostgresUsers(postgres.New(tx))
+
+	u, err := users.Create(ctx, "ada@example.com", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.Create(ctx, "ada@example.com", "other"); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("second account for the email: expected ErrEmailTaken, got %v", err)
+	}
+	if got, err := users.ByEmail(ctx, "ada@example.com"); err != nil || got != u {
+		t.Errorf("ByEmail: expected %+v, got %+v, %v", u, got, err)
+	}
+	if _, err := users.ByID(ctx, u.ID+1); !errors.Is(err, ErrNoUser) {
+		t.Errorf("unknown ID: expected ErrNoUser, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"{{MODULE_PATH}}/internal/store"
+)
+
+// SQLiteUsers keeps the accounts in the store's users table.
+type SQLiteUsers struct {
+	q *store.Queries
+}
+
+func NewSQLiteUsers(q *store.Queries) *SQLiteUsers {
+	return &SQLiteUsers{q: q}
+}
+
+func (s *SQLiteUsers) Create(ctx context.Context, email, passwordHash string) (User, error) {
+	row, err := s.q.CreateUser(ctx, store.CreateUserParams{Email: email, PasswordHash: passwordHash})
+	if errors.Is(err, sql.ErrNoRows) { // ON CONFLICT DO NOTHING
+		return User{}, ErrEmailTaken
+	}
+	return User{ID: row.ID, Email: row.Email, PasswordHash: row.PasswordHash}, err
+}
+
+func (s *SQLiteUsers) ByEmail(ctx context.Context, email string) (User, error) {
+	row, err := s.q.UserByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, ErrNoUser
+	}
+	return User{ID: row.ID, Email: row.Email, PasswordHash: row.PasswordHash}, err
+}
+
+func (s *SQLiteUsers) ByID(ctx context.Context, id int64) (User, error) {
+	row, err := s.q.UserByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, ErrNoUser
+	}
+	return User{ID: row.ID, Email: row.Email, PasswordHash: row.PasswordHash}, err
+}
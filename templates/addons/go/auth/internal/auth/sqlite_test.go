@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/store"
+)
+
+func TestSQLiteUsers(t *testing.T) {
+	ctx := context.Background()
+	db, err := store.Open(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	users := NewSQLiteUsers(store.New(db))
+
+	u, err := users.Create(ctx, "ada@example.com", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.Create(ctx, "ada@example.com", "other"); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("second account for the email: expected ErrEmailTaken, got %v", err)
+	}
+	if got, err := users.ByEmail(ctx, "ada@example.com"); err != nil || got != u {
+		t.Errorf("ByEmail: expected %+v, got %+v, %v", u, got, err)
+	}
+	if got, err := users.ByID(ctx, u.ID); err != nil || got != u {
+		t.Errorf("ByID: expected %+v, got %+v, %v", u, got, err)
+	}
+	if _, err := users.ByID(ctx, u.ID+1); !errors.Is(err, ErrNoUser) {
+		t.Errorf("unknown ID: expected ErrNoUser, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrEmailTaken = errors.New("an account with that email already exists")
+	ErrNoUser     = errors.New("no such user")
+)
+
+// User is an account: the ID its session holds, the email it logs in
+// with and its password's hash.
+type User struct {
+	ID           int64
+	Email        string
+	PasswordHash string
+}
+
+// Users is where the accounts are kept.
+type Users interface {
+	// Create adds an account, or fails with ErrEmailTaken.
+	Create(ctx context.Context, email, passwordHash string) (User, error)
+	// ByEmail and ByID fail with ErrNoUser when there is no such account.
+	ByEmail(ctx context.Context, email string) (User, error)
+	ByID(ctx context.Context, id int64) (User, error)
+}
+
+// MemoryUsers keeps the accounts in memory, losing them on a restart;
+// for tests, and apps without a database.
+type MemoryUsers struct {
+	mu    sync.Mutex
+	users []User
+}
+
+func NewMemoryUsers() *MemoryUsers {
+	return &MemoryUsers{}
+}
+
+func (m *MemoryUsers) Create(_ context.Context, email, passwordHash string) (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range m.users {
+		if u.Email == email {
+			return User{}, ErrEmailTaken
+		}
+	}
+	u := User{ID: int64(len(m.users) + 1), Email: email, PasswordHash: passwordHash}
+	m.users = append(m.users, u)
+	return u, nil
+}
+
+func (m *MemoryUsers) ByEmail(_ context.Context, email string) (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range m.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return User{}, ErrNoUser
+}
+
+func (m *MemoryUsers) ByID(_ context.Context, id int64) (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id < 1 || id > int64(len(m.users)) {
+		return User{}, ErrNoUser
+	}
+	return m.users[id-1], nil
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+)
+
+type User struct {
+	ID           int64
+	Email        string
+	PasswordHash string
+}
+
+// CreateUser adds the account; pgx.ErrNoRows means the email has one.
+func (q *Queries) CreateUser(ctx context.Context, email, passwordHash string) (User, error) {
+	var u User
+	err := q.db.QueryRow(ctx,
+		`INSERT INTO users (email, password_hash) VALUES ($1, $2)
+		ON CONFLICT (email) DO NOTHING
+		RETURNING id, email, password_hash`, email, passwordHash,
+	).Scan(&u.ID, &u.Email, &u.PasswordHash)
+	return u, err
+}
+
+func (q *Queries) UserByEmail(ctx context.Context, email string) (User, error) {
+	var u User
+	err := q.db.QueryRow(ctx,
+		"SELECT id, email, password_hash FROM users WHERE email = $1", email,
+	).Scan(&u.ID, &u.Email, &u.PasswordHash)
+	return u, err
+}
+
+func (q *Queries) UserByID(ctx context.Context, id int64) (User, error) {
+	var u User
+	err := q.db.QueryRow(ctx,
+		"SELECT id, email, password_hash FROM users WHERE id = $1", id,
+	).Scan(&u.ID, &u.Email, &u.PasswordHash)
+	return u, err
+}
@@ -0,0 +1,10 @@
+-- name: CreateUser :one
+INSERT INTO users (email, password_hash) VALUES (?, ?)
+ON CONFLICT (email) DO NOTHING
+RETURNING id, email, password_hash;
+
+-- name: UserByEmail :one
+SELECT id, email, password_hash FROM users WHERE email = ?;
+
+-- name: UserByID :one
+SELECT id, email, password_hash FROM users WHERE id = ?;
@@ -0,0 +1,32 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// completionCommand prints cobra's scripts, which ask the program (its
+// hidden __complete command) what comes next, so they keep up with new
+// commands and flags without being written again.
+func completionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:       "completion bash|zsh|fish",
+		Short:     "Print a shell completion script",
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		Args: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return usagef(c, "completion takes a shell: bash, zsh or fish")
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			root, out := c.Root(), c.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			}
+			return usagef(c, "no completion for %q; there is bash, zsh and fish", args[0])
+		},
+	}
+}
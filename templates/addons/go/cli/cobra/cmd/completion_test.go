@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		stdout, stderr, code := run(t, nil, "completion", shell)
+		if code != 0 || !strings.Contains(stdout, "__complete") {
+			t.Errorf("%s: expected a script asking __complete, got %d %q (stderr %q)", shell, code, stdout, stderr)
+		}
+	}
+	if _, _, code := run(t, nil, "completion", "tcsh"); code != 2 {
+		t.Errorf("expected exit 2 for a shell without completion, got %d", code)
+	}
+}
+
+// TestComplete asks what the scripts ask: what may follow.
+func TestComplete(t *testing.T) {
+	stdout, _, code := run(t, nil, "__complete", "")
+	if code != 0 || !strings.Contains(stdout, "greet\t") || hasWord(stdout, "man") {
+		t.Errorf("expected the visible commands, got %d %q", code, stdout)
+	}
+	stdout, _, _ = run(t, nil, "__complete", "greet", "--")
+	if !strings.Contains(stdout, "--shout") {
+		t.Errorf("expected greet's flags, got %q", stdout)
+	}
+}
+
+func TestMan(t *testing.T) {
+	stdout, _, code := run(t, nil, "man")
+	for _, want := range []string{".TH ", ".SH COMMANDS", ".SS greet", `\fB\-\-shout\fR`, "GREETING"} {
+		if code != 0 || !strings.Contains(stdout, want) {
+			t.Errorf("expected a manual page with %q, got %d %q", want, code, stdout)
+		}
+	}
+}
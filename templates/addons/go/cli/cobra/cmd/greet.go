@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func greetCommand(a *app) *cobra.Command {
+	var greeting string
+	var shout bool
+	c := &cobra.Command{
+		Use:   "greet [NAME...]",
+		Short: "Greet each NAME, or the world",
+		RunE: func(c *cobra.Command, names []string) error {
+			cfg, err := a.config()
+			if err != nil {
+				return err
+			}
+			if greeting == "" {
+				greeting = cfg.Greeting
+			}
+			if len(names) == 0 {
+				names = []string{"world"}
+			}
+			for _, n := range names {
+				// A long-running command checks its context, so
+				// Ctrl-C stops it.
+				if err := c.Context().Err(); err != nil {
+					return err
+				}
+				line := fmt.Sprintf("%s, %s!", greeting, n)
+				if shout {
+					line = strings.ToUpper(line)
+				}
+				fmt.Fprintln(c.OutOrStdout(), line)
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVar(&greeting, "greeting", "", "greet with `WORD` rather than the configured greeting")
+	c.Flags().BoolVarP(&shout, "shout", "s", false, "greet in capitals")
+	return c
+}
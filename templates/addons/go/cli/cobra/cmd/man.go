@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/version"
+)
+
+// manCommand prints the manual page, for packagers and just dist; it is
+// hidden, as users read the page rather than make it.
+func manCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    "man",
+		Short:  "Print the manual page, in roff",
+		Hidden: true,
+		Args:   noArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			writeMan(c.OutOrStdout(), c.Root())
+			return nil
+		},
+	}
+}
+
+// roff escapes s for a roff text line.
+var roff = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace
+
+// manFlags writes a tagged paragraph for each of fs's flags but help.
+func manFlags(w io.Writer, fs *pflag.FlagSet) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "help" {
+			return
+		}
+		value, usage := pflag.UnquoteUsage(f)
+		fmt.Fprintf(w, ".TP\n")
+		if f.Shorthand != "" {
+			fmt.Fprintf(w, "\\fB\\-%s\\fR, ", roff(f.Shorthand))
+		}
+		fmt.Fprintf(w, "\\fB\\-\\-%s\\fR", roff(f.Name))
+		if value != "" {
+			fmt.Fprintf(w, " \\fI%s\\fR", roff(value))
+		}
+		fmt.Fprintf(w, "\n%s\n", roff(usage))
+	})
+}
+
+func writeMan(w io.Writer, root *cobra.Command) {
+	fmt.Fprintf(w, ".TH %s 1 \"\" \"%s %s\" \"User Commands\"\n", roff(strings.ToUpper(name)), roff(name), roff(version.String()))
+	fmt.Fprintf(w, ".SH NAME\n%s \\- %s\n", roff(name), roff(strings.TrimSuffix(about, ".")))
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B %s\n\\fICOMMAND\\fR [\\fIflags\\fR] [\\fIargs\\fR]\n", roff(name))
+	fmt.Fprintf(w, ".SH DESCRIPTION\n%s\n", roff(about))
+	fmt.Fprintf(w, ".SH OPTIONS\n")
+	manFlags(w, root.PersistentFlags())
+	manFlags(w, root.LocalNonPersistentFlags())
+	fmt.Fprintf(w, ".SH COMMANDS\n")
+	for _, c := range root.Commands() {
+		if !c.IsAvailableCommand() {
+			continue
+		}
+		fmt.Fprintf(w, ".SS %s\n%s.\n", roff(c.Use), roff(c.Short))
+		manFlags(w, c.LocalNonPersistentFlags())
+	}
+	fmt.Fprintf(w, ".SH ENVIRONMENT\n")
+	fmt.Fprintf(w, ".TP\n.B %sCONFIG\nthe config file, when \\fB\\-\\-config\\fR does not name one\n", roff(config.Prefix))
+	fmt.Fprintf(w, ".TP\n.B %sGREETING\nwhat greet greets with, over the config file's greeting\n", roff(config.Prefix))
+	fmt.Fprintf(w, ".SH FILES\n.TP\n.I $XDG_CONFIG_HOME/%s/config.json\n", roff(name))
+	fmt.Fprintf(w, "the settings, as JSON, such as {\"greeting\": \"Hi\"}; ~/.config stands in for $XDG_CONFIG_HOME when it is not set\n")
+}
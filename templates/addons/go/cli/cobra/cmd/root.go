@@ -0,0 +1,112 @@
+// Package cmd is the command line, built with cobra: Execute runs the
+// root command, which picks a subcommand, and each subcommand has a
+// file of its own. Commands write to OutOrStdout and ErrOrStderr rather
+// than os.Stdout, so tests can run them and read what they said.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"{{MODULE_PATH}}/internal/config"
+)
+
+// name is what the program is called, in help, completions and the
+// manual.
+const name = "{{PROJECT_NAME}}"
+
+// about says what the program does, for help and the manual.
+const about = "Greet people by name."
+
+// commands is every subcommand, in the order the manual lists them;
+// help sorts them. They are made anew for each Execute, so no flag's
+// value outlives a run.
+func commands(a *app) []*cobra.Command {
+	return []*cobra.Command{greetCommand(a), versionCommand(), completionCommand(), manCommand()}
+}
+
+// app is what commands run with, besides their cobra.Command.
+type app struct {
+	getenv     func(string) string
+	configPath string // --config; config.Load finds one when empty
+}
+
+func (a *app) config() (config.Config, error) {
+	return config.Load(a.configPath, a.getenv)
+}
+
+// errUsage is a command line that is wrong, already reported with how
+// to put it right; Execute exits 2 on it.
+var errUsage = errors.New("usage")
+
+func usagef(c *cobra.Command, format string, args ...any) error {
+	fmt.Fprintf(c.ErrOrStderr(), "%s: %s\nRun '%s help' for usage.\n", name, fmt.Sprintf(format, args...), name)
+	return errUsage
+}
+
+// noArgs is cobra.NoArgs, reported as a usage error.
+func noArgs(c *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return usagef(c, "%s takes no arguments", c.Name())
+	}
+	return nil
+}
+
+// Execute runs the command line args (os.Args[1:]) and is the exit
+// code: 0 when it succeeds, 2 when the command line is wrong and 1 when
+// the command fails, which it says on stderr.
+func Execute(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	root := newRoot(&app{getenv: getenv})
+	// Never nil, or cobra reads os.Args instead.
+	root.SetArgs(append([]string{}, args...))
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	err := root.ExecuteContext(ctx)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	}
+}
+
+func newRoot(a *app) *cobra.Command {
+	var showVersion bool
+	root := &cobra.Command{
+		Use:   name,
+		Short: strings.TrimSuffix(about, "."),
+		Long:  about,
+		// The root runs, rather than leaving cobra to, so that no
+		// command and an unknown one are usage errors.
+		Args: cobra.ArbitraryArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			switch {
+			case showVersion:
+				return printVersion(c)
+			case len(args) > 0:
+				return usagef(c, "unknown command %q", args[0])
+			default:
+				fmt.Fprint(c.ErrOrStderr(), c.UsageString())
+				return errUsage
+			}
+		},
+		SilenceErrors: true, // Execute says them
+		SilenceUsage:  true, // usagef points at help instead
+	}
+	root.CompletionOptions.DisableDefaultCmd = true // completion.go has one
+	root.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return usagef(c, "%v", err)
+	})
+	root.PersistentFlags().StringVar(&a.configPath, "config", "", "read the settings from `FILE` rather than $XDG_CONFIG_HOME/"+name+"/config.json")
+	root.Flags().BoolVar(&showVersion, "version", false, "print the version and exit")
+	root.AddCommand(commands(a)...)
+	return root
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+
+	greetv1 "{{MODULE_PATH}}/gen/greet/v1"
+	"{{MODULE_PATH}}/internal/interceptor"
+)
+
+// newGateway answers the REST routes the google.api.http options in
+// proto/ map out by making the gRPC calls they stand for through conn,
+// as JSON in and out. A request's X-Request-Id goes through as the
+// call's, and comes back in the response.
+func newGateway(conn *grpc.ClientConn) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			if strings.EqualFold(key, interceptor.Header) {
+				return interceptor.Header, true
+			}
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+		runtime.WithOutgoingHeaderMatcher(func(key string) (string, bool) {
+			if key == interceptor.Header {
+				return "X-Request-Id", true
+			}
+			return runtime.MetadataHeaderPrefix + key, true
+		}),
+	)
+	if err := greetv1.RegisterGreetServiceHandler(context.Background(), mux, conn); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
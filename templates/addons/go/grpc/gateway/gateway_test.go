@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGateway(t *testing.T) {
+	gateway, err := newGateway(dial(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		method, target, body string
+		status               int
+		message              string // what the body's message says: the greeting, or the error
+	}{
+		{"POST", "/v1/greet", `{"name": "Ada"}`, http.StatusOK, "Hello, Ada!"},
+		{"GET", "/v1/greet?name=Ada", "", http.StatusOK, "Hello, Ada!"},
+		{"GET", "/v1/greet", "", http.StatusOK, "Hello, world!"},
+		{"POST", "/v1/greet", `{"name": "` + strings.Repeat("a", 101) + `"}`, http.StatusBadRequest, "at most 100"},
+		{"GET", "/v1/nothing", "", http.StatusNotFound, "Not Found"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		req.Header.Set("X-Request-Id", "abc-123")
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, req)
+
+		var body struct{ Message string }
+		json.Unmarshal(w.Body.Bytes(), &body)
+		if w.Code != tt.status || !strings.Contains(body.Message, tt.message) {
+			t.Errorf("%s %s: expected %d saying %q, got %d %s", tt.method, tt.target, tt.status, tt.message, w.Code, w.Body)
+		}
+		if tt.status == http.StatusOK && w.Header().Get("X-Request-Id") != "abc-123" {
+			t.Errorf("%s %s: expected the request ID back, got %q", tt.method, tt.target, w.Header().Get("X-Request-Id"))
+		}
+	}
+}
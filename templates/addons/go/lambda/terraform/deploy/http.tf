@@ -0,0 +1,20 @@
+# An HTTP API sending every request to the http function.
+
+resource "aws_apigatewayv2_api" "http" {
+  name          = var.name
+  protocol_type = "HTTP"
+  target        = aws_lambda_function.function["http"].arn
+}
+
+resource "aws_lambda_permission" "http" {
+  statement_id  = "AllowAPIGateway"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.function["http"].function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_apigatewayv2_api.http.execution_arn}/*/*"
+}
+
+output "api_url" {
+  description = "Where the HTTP API answers"
+  value       = aws_apigatewayv2_api.http.api_endpoint
+}
@@ -0,0 +1,79 @@
+# The functions just zip builds, and what they need. The state is kept
+# in deploy/ (and ignored by git); put a backend block here to share it.
+
+terraform {
+  required_version = ">= 1.5"
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  region = var.region
+}
+
+variable "region" {
+  description = "The AWS region to deploy to"
+  type        = string
+  default     = "us-east-1"
+}
+
+variable "name" {
+  description = "What the functions, queue and API are named after"
+  type        = string
+  default     = "{{PROJECT_NAME | kebab}}"
+}
+
+locals {
+  functions = {
+    http = { timeout = 10 }
+    sqs  = { timeout = 10 }
+  }
+}
+
+resource "aws_iam_role" "function" {
+  name = "${var.name}-function"
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect    = "Allow"
+      Principal = { Service = "lambda.amazonaws.com" }
+      Action    = "sts:AssumeRole"
+    }]
+  })
+}
+
+resource "aws_iam_role_policy_attachment" "logs" {
+  role       = aws_iam_role.function.name
+  policy_arn = "arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"
+}
+
+resource "aws_cloudwatch_log_group" "function" {
+  for_each          = local.functions
+  name              = "/aws/lambda/${var.name}-${each.key}"
+  retention_in_days = 14
+}
+
+resource "aws_lambda_function" "function" {
+  for_each         = local.functions
+  function_name    = "${var.name}-${each.key}"
+  role             = aws_iam_role.function.arn
+  filename         = "${path.module}/../dist/${each.key}.zip"
+  source_code_hash = filebase64sha256("${path.module}/../dist/${each.key}.zip")
+  runtime          = "provided.al2"
+  handler          = "bootstrap"
+  architectures    = ["arm64"]
+  memory_size      = 128
+  timeout          = each.value.timeout
+
+  environment {
+    variables = {
+      APP_ENV = "production"
+    }
+  }
+
+  depends_on = [aws_cloudwatch_log_group.function]
+}
@@ -0,0 +1,35 @@
+# A queue feeding the sqs function in batches, and a dead-letter queue
+# for the messages that keep failing.
+
+resource "aws_sqs_queue" "dead_letter" {
+  name                      = "${var.name}-dead-letter"
+  message_retention_seconds = 1209600
+}
+
+resource "aws_sqs_queue" "queue" {
+  name = var.name
+  # At least six times the function's timeout, as AWS advises.
+  visibility_timeout_seconds = 6 * local.functions.sqs.timeout
+  redrive_policy = jsonencode({
+    deadLetterTargetArn = aws_sqs_queue.dead_letter.arn
+    maxReceiveCount     = 5
+  })
+}
+
+resource "aws_iam_role_policy_attachment" "sqs" {
+  role       = aws_iam_role.function.name
+  policy_arn = "arn:aws:iam::aws:policy/service-role/AWSLambdaSQSQueueExecutionRole"
+}
+
+resource "aws_lambda_event_source_mapping" "sqs" {
+  event_source_arn        = aws_sqs_queue.queue.arn
+  function_name           = aws_lambda_function.function["sqs"].arn
+  batch_size              = 10
+  function_response_types = ["ReportBatchItemFailures"]
+  depends_on              = [aws_iam_role_policy_attachment.sqs]
+}
+
+output "queue_url" {
+  description = "Where to send the messages the sqs function takes"
+  value       = aws_sqs_queue.queue.url
+}
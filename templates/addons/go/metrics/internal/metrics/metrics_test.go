@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareRecordsRequests(t *testing.T) {
+	m := New()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/greet", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("GET /boom", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	h := m.Middleware(mux)(mux)
+	for _, path := range []string{"/api/greet", "/api/greet", "/boom", "/nowhere"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`http_request_duration_seconds_bucket{route="GET /api/greet",status="200",le="+Inf"} 2`,
+		`http_request_duration_seconds_count{route="GET /boom",status="500"} 1`,
+		`http_requests_total{route="unmatched",status="404"} 1`,
+		"http_requests_in_flight 0",
+		"go_goroutines ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in the metrics, got:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Package oauth signs users in with another site's account: Google,
+// GitHub or any OpenID Connect provider, through the authorization code
+// flow with state and PKCE.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/alexedwards/scs/v2"
+	"golang.org/x/oauth2"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// Identity is who a provider says the user is.
+type Identity struct {
+	Provider      string // the Provider's Name
+	Subject       string // the provider's ID for the user
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+func init() {
+	gob.Register(Identity{}) // for the session, which gob-encodes its values
+}
+
+// Provider is a site users can sign in with.
+type Provider struct {
+	Name   string // in the /auth/{provider}/ paths
+	Title  string // for the sign-in button
+	Config oauth2.Config
+	// Identify asks the provider who the token belongs to.
+	Identify func(ctx context.Context, client *http.Client) (Identity, error)
+}
+
+// SignIn runs the flow for its providers, keeping the state and PKCE
+// verifier of each sign-in in the session until the callback.
+type SignIn struct {
+	Providers []*Provider
+	Sessions  *scs.SessionManager
+	// Done finishes a sign-in, by default (KeepIdentity) keeping the
+	// Identity in the session; it answers the request.
+	Done func(w http.ResponseWriter, r *http.Request, id Identity)
+}
+
+const (
+	stateKey    = "oauth.state"
+	verifierKey = "oauth.verifier"
+	providerKey = "oauth.provider"
+	nextKey     = "oauth.next"
+	identityKey = "oauth.identity"
+)
+
+func (s *SignIn) provider(name string) *Provider {
+	for _, p := range s.Providers {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// HandleLogin sends the user to the provider in the path to sign in, to
+// come back to HandleCallback and then the local ?next= path.
+func (s *SignIn) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	p := s.provider(r.PathValue("provider"))
+	if p == nil {
+		http.NotFound(w, r)
+		return
+	}
+	state, err := randomString()
+	if err != nil {
+		serverError(w, r, "oauth state", err)
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+	ctx := r.Context()
+	s.Sessions.Put(ctx, stateKey, state)
+	s.Sessions.Put(ctx, verifierKey, verifier)
+	s.Sessions.Put(ctx, providerKey, p.Name)
+	s.Sessions.Put(ctx, nextKey, LocalPath(r.URL.Query().Get("next"), "/"))
+	http.Redirect(w, r, p.Config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)), http.StatusSeeOther)
+}
+
+// HandleCallback is where the provider sends the user back: it checks the
+// state, trades the code for a token and asks who the user is.
+func (s *SignIn) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	p := s.provider(r.PathValue("provider"))
+	state := s.Sessions.PopString(ctx, stateKey)
+	verifier := s.Sessions.PopString(ctx, verifierKey)
+	if p == nil || s.Sessions.PopString(ctx, providerKey) != p.Name || state == "" ||
+		subtle.ConstantTimeCompare([]byte(state), []byte(r.FormValue("state"))) != 1 {
+		http.Error(w, "this sign-in has expired or was not started here; try again", http.StatusBadRequest)
+		return
+	}
+	if e := r.FormValue("error"); e != "" {
+		http.Error(w, "the sign-in was cancelled or refused: "+e, http.StatusBadRequest)
+		return
+	}
+	token, err := p.Config.Exchange(ctx, r.FormValue("code"), oauth2.VerifierOption(verifier))
+	if err != nil {
+		serverError(w, r, "oauth exchange", err)
+		return
+	}
+	id, err := p.Identify(ctx, p.Config.Client(ctx, token))
+	if err != nil {
+		serverError(w, r, "oauth identify", err)
+		return
+	}
+	id.Provider = p.Name
+	done := s.Done
+	if done == nil {
+		done = s.KeepIdentity
+	}
+	done(w, r, id)
+}
+
+// KeepIdentity signs the user in with the identity alone: it goes in the
+// session, under a new token, and the user on to where they were going.
+func (s *SignIn) KeepIdentity(w http.ResponseWriter, r *http.Request, id Identity) {
+	if err := s.Sessions.RenewToken(r.Context()); err != nil {
+		serverError(w, r, "renew session", err)
+		return
+	}
+	s.Sessions.Put(r.Context(), identityKey, id)
+	http.Redirect(w, r, s.Next(r.Context()), http.StatusSeeOther)
+}
+
+// Next is the local path the sign-in started with, taken from the
+// session; "/" without one.
+func (s *SignIn) Next(ctx context.Context) string {
+	return LocalPath(s.Sessions.PopString(ctx, nextKey), "/")
+}
+
+// Current is the identity KeepIdentity signed in with, if any.
+func (s *SignIn) Current(ctx context.Context) (Identity, bool) {
+	id, ok := s.Sessions.Get(ctx, identityKey).(Identity)
+	return id, ok
+}
+
+// LocalPath is next if it is a path on this site, else fallback, so a
+// sign-in link cannot send users on to another one.
+func LocalPath(next, fallback string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return fallback
+	}
+	return next
+}
+
+var errNoEmail = errors.New("oauth: the provider gave no email address")
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func serverError(w http.ResponseWriter, r *http.Request, what string, err error) {
+	logging.FromContext(r.Context()).Error(what, "err", err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
@@ -0,0 +1,149 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/alexedwards/scs/v2"
+	"golang.org/x/oauth2"
+
+	"{{MODULE_PATH}}/internal/oauth/oauthtest"
+)
+
+// signInApp is an app signing in with the stub provider, and a browser
+// that keeps its session cookie.
+type signInApp struct {
+	t        *testing.T
+	provider *oauthtest.Server
+	handler  http.Handler
+	cookie   *http.Cookie
+}
+
+func newSignInApp(t *testing.T) *signInApp {
+	provider := oauthtest.NewServer()
+	t.Cleanup(provider.Close)
+	p, err := OIDC(context.Background(), "test", "Test", provider.URL, oauth2.Config{
+		ClientID:     oauthtest.ClientID,
+		ClientSecret: oauthtest.ClientSecret,
+		RedirectURL:  "http://app.test/auth/test/callback",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := scs.New()
+	s := &SignIn{Providers: []*Provider{p}, Sessions: sessions}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /auth/{provider}/login", s.HandleLogin)
+	mux.HandleFunc("GET /auth/{provider}/callback", s.HandleCallback)
+	mux.HandleFunc("GET /whoami", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := s.Current(r.Context())
+		fmt.Fprint(w, id.Email)
+	})
+	return &signInApp{t: t, provider: provider, handler: sessions.LoadAndSave(mux)}
+}
+
+func (a *signInApp) get(target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", target, nil)
+	if a.cookie != nil {
+		req.AddCookie(a.cookie)
+	}
+	w := httptest.NewRecorder()
+	a.handler.ServeHTTP(w, req)
+	for _, c := range w.Result().Cookies() {
+		a.cookie = c
+	}
+	return w
+}
+
+// authorize follows the login redirect to the provider, which sends
+// the browser back with a callback URL.
+func (a *signInApp) authorize(login *httptest.ResponseRecorder) string {
+	a.t.Helper()
+	if login.Code != http.StatusSeeOther || !strings.HasPrefix(login.Header().Get("Location"), a.provider.URL) {
+		a.t.Fatalf("expected a redirect to the provider, got %d: %s", login.Code, login.Header().Get("Location"))
+	}
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(login.Header().Get("Location"))
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	resp.Body.Close()
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || callback.Path != "/auth/test/callback" {
+		a.t.Fatalf("expected the provider to send the browser back, got %d: %s", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	return callback.RequestURI()
+}
+
+func TestSignIn(t *testing.T) {
+	app := newSignInApp(t)
+	callback := app.authorize(app.get("/auth/test/login?next=/account"))
+	before := app.cookie.Value
+
+	w := app.get(callback)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/account" {
+		t.Fatalf("expected a redirect to next, got %d: %s %s", w.Code, w.Header().Get("Location"), w.Body)
+	}
+	if app.cookie.Value == before {
+		t.Error("expected a new session token after signing in")
+	}
+	if got := app.get("/whoami").Body.String(); got != "ada@example.com" {
+		t.Errorf("expected the provider's user in the session, got %q", got)
+	}
+
+	// The state is spent: the same callback again is refused.
+	if w := app.get(callback); w.Code != http.StatusBadRequest {
+		t.Errorf("replayed callback: expected status 400, got %d", w.Code)
+	}
+}
+
+func TestCallbackChecksState(t *testing.T) {
+	app := newSignInApp(t)
+	callback := app.authorize(app.get("/auth/test/login"))
+	u, _ := url.Parse(callback)
+	q := u.Query()
+	q.Set("state", "forged")
+	u.RawQuery = q.Encode()
+	if w := app.get(u.RequestURI()); w.Code != http.StatusBadRequest {
+		t.Errorf("forged state: expected status 400, got %d", w.Code)
+	}
+	if got := app.get("/whoami").Body.String(); got != "" {
+		t.Errorf("expected no one signed in, got %q", got)
+	}
+}
+
+func TestLoginRejectsUnknownProvidersAndForeignNext(t *testing.T) {
+	app := newSignInApp(t)
+	if w := app.get("/auth/nope/login"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown provider: expected status 404, got %d", w.Code)
+	}
+	w := app.get(app.authorize(app.get("/auth/test/login?next=//evil.example")))
+	if w.Header().Get("Location") != "/" {
+		t.Errorf("expected a foreign next to become /, got %q", w.Header().Get("Location"))
+	}
+}
+
+func TestGitHubIdentify(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			fmt.Fprint(w, `{"id": 42, "login": "ada", "name": ""}`)
+		case "/user/emails":
+			fmt.Fprint(w, `[{"email": "old@example.com"}, {"email": "ada@example.com", "primary": true, "verified": true}]`)
+		}
+	}))
+	defer api.Close()
+	defer func(saved string) { githubAPI = saved }(githubAPI)
+	githubAPI = api.URL
+
+	id, err := GitHub(oauth2.Config{}).Identify(context.Background(), api.Client())
+	want := Identity{Subject: "42", Email: "ada@example.com", EmailVerified: true, Name: "ada"}
+	if err != nil || id != want {
+		t.Errorf("expected %+v, got %+v, %v", want, id, err)
+	}
+}
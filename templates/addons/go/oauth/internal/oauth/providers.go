@@ -0,0 +1,156 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+
+	"{{MODULE_PATH}}/internal/config"
+)
+
+// FromConfig is the providers cfg has a client for, each coming back to
+// BaseURL/auth/{provider}/callback.
+func FromConfig(ctx context.Context, cfg config.Config) ([]*Provider, error) {
+	var providers []*Provider
+	if c := cfg.OAuthGoogle; c.ClientID != "" {
+		p, err := OIDC(ctx, "google", "Google", "https://accounts.google.com", clientConfig(cfg, "google", c))
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	if c := cfg.OAuthGitHub; c.ClientID != "" {
+		providers = append(providers, GitHub(clientConfig(cfg, "github", c)))
+	}
+	if c := cfg.OAuthOIDC; c.ClientID != "" {
+		p, err := OIDC(ctx, "oidc", c.Title, c.Issuer, clientConfig(cfg, "oidc", c))
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+func clientConfig(cfg config.Config, name string, c config.OAuthClient) oauth2.Config {
+	return oauth2.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		RedirectURL:  cfg.BaseURL + "/auth/" + name + "/callback",
+	}
+}
+
+// discoveryClient fetches discovery documents at start, so a provider
+// that is down cannot hold the app up for long.
+var discoveryClient = &http.Client{Timeout: 10 * time.Second}
+
+// OIDC is the OpenID Connect provider at issuer, its endpoints read from
+// issuer/.well-known/openid-configuration. It asks for the openid, email
+// and profile scopes and identifies users at its userinfo endpoint.
+func OIDC(ctx context.Context, name, title, issuer string, cfg oauth2.Config) (*Provider, error) {
+	var doc struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	}
+	issuer = strings.TrimSuffix(issuer, "/")
+	if err := getJSON(ctx, discoveryClient, issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, fmt.Errorf("oauth: discover %s: %w", issuer, err)
+	}
+	if doc.Issuer != issuer || doc.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("oauth: discover %s: issuer %q and no userinfo endpoint", issuer, doc.Issuer)
+	}
+	cfg.Endpoint = oauth2.Endpoint{AuthURL: doc.AuthorizationEndpoint, TokenURL: doc.TokenEndpoint}
+	cfg.Scopes = []string{"openid", "email", "profile"}
+	return &Provider{
+		Name:   name,
+		Title:  title,
+		Config: cfg,
+		Identify: func(ctx context.Context, client *http.Client) (Identity, error) {
+			var info struct {
+				Subject       string `json:"sub"`
+				Email         string `json:"email"`
+				EmailVerified bool   `json:"email_verified"`
+				Name          string `json:"name"`
+			}
+			if err := getJSON(ctx, client, doc.UserinfoEndpoint, &info); err != nil {
+				return Identity{}, err
+			}
+			if info.Subject == "" {
+				return Identity{}, fmt.Errorf("oauth: %s userinfo has no sub", name)
+			}
+			return Identity{Subject: info.Subject, Email: info.Email, EmailVerified: info.EmailVerified, Name: info.Name}, nil
+		},
+	}, nil
+}
+
+// githubAPI is where GitHub identifies users; tests point it elsewhere.
+var githubAPI = "https://api.github.com"
+
+// GitHub signs in with a GitHub account (an OAuth app, which is not
+// OpenID Connect), its primary email when that one is verified.
+func GitHub(cfg oauth2.Config) *Provider {
+	cfg.Endpoint = endpoints.GitHub
+	cfg.Scopes = []string{"read:user", "user:email"}
+	return &Provider{
+		Name:   "github",
+		Title:  "GitHub",
+		Config: cfg,
+		Identify: func(ctx context.Context, client *http.Client) (Identity, error) {
+			var user struct {
+				ID    int64  `json:"id"`
+				Login string `json:"login"`
+				Name  string `json:"name"`
+			}
+			var emails []struct {
+				Email    string `json:"email"`
+				Primary  bool   `json:"primary"`
+				Verified bool   `json:"verified"`
+			}
+			if err := getJSON(ctx, client, githubAPI+"/user", &user); err != nil {
+				return Identity{}, err
+			}
+			if err := getJSON(ctx, client, githubAPI+"/user/emails", &emails); err != nil {
+				return Identity{}, err
+			}
+			id := Identity{Subject: strconv.FormatInt(user.ID, 10), Name: user.Name}
+			if id.Name == "" {
+				id.Name = user.Login
+			}
+			for _, e := range emails {
+				if e.Primary {
+					id.Email, id.EmailVerified = e.Email, e.Verified
+				}
+			}
+			if id.Email == "" {
+				return Identity{}, errNoEmail
+			}
+			return id, nil
+		},
+	}
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alexedwards/scs/v2"
+
+	"{{MODULE_PATH}}/components"
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/oauth"
+)
+
+// newSignIn signs users in with the providers cfg sets up, looking the
+// OpenID Connect ones up first.
+func newSignIn(ctx context.Context, cfg config.Config, sessions *scs.SessionManager) (*oauth.SignIn, error) {
+	providers, err := oauth.FromConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth.SignIn{Providers: providers, Sessions: sessions}, nil
+}
+
+// handleSignIn offers the providers, and says who is signed in.
+func handleSignIn(signIn *oauth.SignIn) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := signIn.Current(r.Context())
+		who := id.Name
+		if who == "" {
+			who = id.Email
+		}
+		next := oauth.LocalPath(r.URL.Query().Get("next"), "/")
+		components.Page("Sign in", components.SignIn(signIn.Providers, next, who)).Render(r.Context(), w)
+	}
+}
+
+func handleSignOut(sessions *scs.SessionManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := sessions.Destroy(r.Context()); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
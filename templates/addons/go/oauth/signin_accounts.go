@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"{{MODULE_PATH}}/internal/auth"
+	"{{MODULE_PATH}}/internal/oauth"
+)
+
+// loginAccount finishes a sign-in by logging in to the account with the
+// provider's email, made (without a password) the first time. Only an
+// email the provider has verified counts, or anyone could claim one.
+func loginAccount(accounts *auth.Accounts, signIn *oauth.SignIn) func(http.ResponseWriter, *http.Request, oauth.Identity) {
+	return func(w http.ResponseWriter, r *http.Request, id oauth.Identity) {
+		if !id.EmailVerified {
+			http.Error(w, "sign in with an account whose email address is verified", http.StatusForbidden)
+			return
+		}
+		email := strings.ToLower(id.Email)
+		u, err := accounts.Users.ByEmail(r.Context(), email)
+		if errors.Is(err, auth.ErrNoUser) {
+			u, err = accounts.Users.Create(r.Context(), email, "")
+		}
+		if err == nil {
+			err = accounts.Login(r.Context(), u)
+		}
+		if err != nil {
+			serverError(w, r, "sign in", err)
+			return
+		}
+		http.Redirect(w, r, signIn.Next(r.Context()), http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+
+	"{{MODULE_PATH}}/internal/auth"
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/oauth"
+	"{{MODULE_PATH}}/internal/oauth/oauthtest"
+)
+
+func TestSignInLogsInToAnAccount(t *testing.T) {
+	provider := oauthtest.NewServer()
+	defer provider.Close()
+	p, err := oauth.OIDC(context.Background(), "test", "Test", provider.URL, oauth2.Config{
+		ClientID:     oauthtest.ClientID,
+		ClientSecret: oauthtest.ClientSecret,
+		RedirectURL:  "http://app.test/auth/test/callback",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := newSessions(config.Config{BaseURL: "http://app.test"})
+	users := auth.NewMemoryUsers()
+	accounts := &auth.Accounts{Users: users, Sessions: sessions}
+	signIn := &oauth.SignIn{Providers: []*oauth.Provider{p}, Sessions: sessions}
+	signIn.Done = loginAccount(accounts, signIn)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /auth/{provider}/login", signIn.HandleLogin)
+	mux.HandleFunc("GET /auth/{provider}/callback", signIn.HandleCallback)
+	mux.Handle("GET /account", accounts.RequireLogin(http.HandlerFunc(handleAccount)))
+	handler := sessions.LoadAndSave(mux)
+
+	var cookie *http.Cookie
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		for _, c := range w.Result().Cookies() {
+			cookie = c
+		}
+		return w
+	}
+	signInOnce := func() *httptest.ResponseRecorder {
+		login := get("/auth/test/login?next=/account")
+		client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+		resp, err := client.Get(login.Header().Get("Location"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		callback, _ := url.Parse(resp.Header.Get("Location"))
+		return get(callback.RequestURI())
+	}
+
+	if w := signInOnce(); w.Header().Get("Location") != "/account" {
+		t.Fatalf("expected a redirect to the account, got %d: %s", w.Code, w.Body)
+	}
+	if w := get("/account"); !strings.Contains(w.Body.String(), "ada@example.com") {
+		t.Errorf("expected the account page for the provider's email, got %d: %s", w.Code, w.Body)
+	}
+	signInOnce()
+	if _, err := users.ByID(context.Background(), 2); err == nil {
+		t.Error("expected signing in again to reuse the account")
+	}
+
+	provider.User["email_verified"] = false
+	if w := signInOnce(); w.Code != http.StatusForbidden {
+		t.Errorf("unverified email: expected status 403, got %d", w.Code)
+	}
+}
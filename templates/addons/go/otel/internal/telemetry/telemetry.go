@@ -0,0 +1,48 @@
+// Package telemetry sets up OpenTelemetry tracing: spans exported over
+// OTLP, and trace context carried in and out of requests.
+package telemetry
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Setup installs the global tracer provider and the W3C trace context
+// and baggage propagators. The standard variables configure it:
+// OTEL_EXPORTER_OTLP_ENDPOINT (or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT)
+// is the collector to send spans to over OTLP/HTTP, localhost:4318 by
+// default, OTEL_EXPORTER_OTLP_HEADERS what to send with them,
+// OTEL_TRACES_SAMPLER which to keep, and OTEL_SERVICE_NAME and
+// OTEL_RESOURCE_ATTRIBUTES what the spans say they come from, service
+// otherwise. Shutdown sends the spans still waiting to go out.
+func Setup(ctx context.Context, service string) (shutdown func(context.Context) error, err error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", service)),
+		resource.WithFromEnv(), // after, so OTEL_SERVICE_NAME wins
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/telemetry"
+)
+
+// tracer starts the spans the app's own code adds, such as greet's,
+// with whichever provider is installed at the time.
+func tracer() trace.Tracer {
+	return otel.Tracer("{{MODULE_PATH}}")
+}
+
+// setupTracing starts exporting spans, once an OTLP endpoint is set, and
+// has requests made through http.DefaultClient carry the trace along to
+// the services they call. Without one, as in development, spans go
+// nowhere. The function it returns sends what is left; main defers it,
+// so it runs once the server has shut down.
+func setupTracing(ctx context.Context, cfg config.Config) (func(), error) {
+	if !cfg.Tracing {
+		return func() {}, nil
+	}
+	shutdown, err := telemetry.Setup(ctx, "{{PROJECT_NAME}}")
+	if err != nil {
+		return nil, err
+	}
+	http.DefaultClient.Transport = otelhttp.NewTransport(http.DefaultTransport)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			slog.Error("tracing shutdown", "err", err)
+		}
+	}, nil
+}
+
+// traced gives each request a span, continuing the trace of the caller,
+// named for the mux pattern it matches ("GET /api/greet").
+func traced(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return otelhttp.NewHandler(next, "request",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				if _, pattern := mux.Handler(r); pattern != "" {
+					return pattern
+				}
+				return r.Method + " unmatched"
+			}),
+		)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans sends the test's spans to a recorder instead of a
+// collector.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return recorder
+}
+
+func TestGreetSpanIsInsideTheRequests(t *testing.T) {
+	recorder := recordSpans(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/greet", handleGreet)
+	traced(mux)(mux).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/greet", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected the request's span and greet's, got %d", len(spans))
+	}
+	greet, request := spans[0], spans[1]
+	if greet.Name() != "greet" || request.Name() != "GET /api/greet" {
+		t.Fatalf("unexpected spans %q and %q", greet.Name(), request.Name())
+	}
+	if greet.Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Error("expected greet's span to be a child of the request's")
+	}
+}
+
+func TestOutgoingRequestsCarryTheTrace(t *testing.T) {
+	recordSpans(t)
+	var traceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	ctx, span := tracer().Start(context.Background(), "call")
+	req, _ := http.NewRequestWithContext(ctx, "GET", upstream.URL, nil)
+	resp, err := client.Do(req)
+	span.End()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if want := span.SpanContext().TraceID().String(); len(traceparent) < 35 || traceparent[3:35] != want {
+		t.Errorf("expected a traceparent for trace %s, got %q", want, traceparent)
+	}
+}
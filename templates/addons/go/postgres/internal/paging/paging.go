@@ -0,0 +1,87 @@
+// Package paging splits a list into numbered pages, chosen by the page
+// and size query parameters, and links from one page to the next.
+package paging
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultSize = 20  // items to a page when the query does not say
+	MaxSize     = 100 // the most a query may ask for
+)
+
+// Page is one page of a list of Total items, Size to a page, and
+// numbered from 1.
+type Page struct {
+	Number int
+	Size   int
+	Total  int64
+}
+
+// FromQuery is the page q asks for: page (1 without it) and size
+// (DefaultSize), or an error naming the one that is not a number in
+// range. Total is for the caller to fill in once it has counted.
+func FromQuery(q url.Values) (Page, error) {
+	p := Page{Number: 1, Size: DefaultSize}
+	if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return Page{}, fmt.Errorf("page: %q is not a page number", v)
+		}
+		p.Number = n
+	}
+	if v := q.Get("size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxSize {
+			return Page{}, fmt.Errorf("size: %q is not a number from 1 to %d", v, MaxSize)
+		}
+		p.Size = n
+	}
+	return p, nil
+}
+
+// Offset is how many items come before the page.
+func (p Page) Offset() int { return (p.Number - 1) * p.Size }
+
+// Pages is how many pages the list takes; an empty list has one, with
+// nothing on it.
+func (p Page) Pages() int {
+	if p.Total <= 0 {
+		return 1
+	}
+	return int((p.Total + int64(p.Size) - 1) / int64(p.Size))
+}
+
+// Past is whether the page is beyond the list's last.
+func (p Page) Past() bool { return p.Number > p.Pages() }
+
+// Prev and Next are the numbers of the pages either side, 0 when there
+// is none.
+func (p Page) Prev() int {
+	if p.Number <= 1 || p.Past() {
+		return 0
+	}
+	return p.Number - 1
+}
+
+func (p Page) Next() int {
+	if p.Number >= p.Pages() {
+		return 0
+	}
+	return p.Number + 1
+}
+
+// URL is u, the list's, at page n, with the rest of its query kept.
+func (p Page) URL(u *url.URL, n int) string {
+	q := u.Query()
+	q.Set("page", strconv.Itoa(n))
+	if p.Size == DefaultSize {
+		q.Del("size")
+	} else {
+		q.Set("size", strconv.Itoa(p.Size))
+	}
+	return u.Path + "?" + q.Encode()
+}
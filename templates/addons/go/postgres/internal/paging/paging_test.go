@@ -0,0 +1,70 @@
+package paging
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFromQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Page
+		err   string // what the error says, "" for none
+	}{
+		{"", Page{Number: 1, Size: DefaultSize}, ""},
+		{"page=3&size=5", Page{Number: 3, Size: 5}, ""},
+		{"page=0", Page{}, "page"},
+		{"page=last", Page{}, "page"},
+		{"size=0", Page{}, "size"},
+		{"size=1000", Page{}, "size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, _ := url.ParseQuery(tt.query)
+			got, err := FromQuery(q)
+			if tt.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.err+":") {
+					t.Fatalf("expected an error about %s, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("expected %+v, got %+v, %v", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	tests := []struct {
+		page          Page
+		offset, pages int
+		prev, next    int
+		past          bool
+	}{
+		{Page{Number: 1, Size: 10, Total: 0}, 0, 1, 0, 0, false},
+		{Page{Number: 1, Size: 10, Total: 25}, 0, 3, 0, 2, false},
+		{Page{Number: 2, Size: 10, Total: 25}, 10, 3, 1, 3, false},
+		{Page{Number: 3, Size: 10, Total: 25}, 20, 3, 2, 0, false},
+		{Page{Number: 2, Size: 10, Total: 20}, 10, 2, 1, 0, false},
+		{Page{Number: 4, Size: 10, Total: 25}, 30, 3, 0, 0, true},
+	}
+	for _, tt := range tests {
+		p := tt.page
+		if p.Offset() != tt.offset || p.Pages() != tt.pages || p.Prev() != tt.prev || p.Next() != tt.next || p.Past() != tt.past {
+			t.Errorf("%+v: got offset %d, %d pages, prev %d, next %d, past %v",
+				p, p.Offset(), p.Pages(), p.Prev(), p.Next(), p.Past())
+		}
+	}
+}
+
+func TestURL(t *testing.T) {
+	u, _ := url.Parse("/items?q=hi&page=1")
+	if got := (Page{Number: 1, Size: DefaultSize}).URL(u, 2); got != "/items?page=2&q=hi" {
+		t.Errorf("expected the other parameters kept, got %s", got)
+	}
+	if got := (Page{Number: 1, Size: 5}).URL(u, 2); got != "/items?page=2&q=hi&size=5" {
+		t.Errorf("expected the size kept, got %s", got)
+	}
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DBTX is what the queries run on: the pool, a connection or a
+// transaction.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Queries runs the app's queries on a DBTX.
+type Queries struct {
+	db DBTX
+}
+
+func New(db DBTX) *Queries {
+	return &Queries{db: db}
+}
+
+type Note struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) CreateNote(ctx context.Context, body string) (Note, error) {
+	var n Note
+	err := q.db.QueryRow(ctx,
+		"INSERT INTO notes (body) VALUES ($1) RETURNING id, body, created_at", body,
+	).Scan(&n.ID, &n.Body, &n.CreatedAt)
+	return n, err
+}
+
+// ListNotes is the latest limit notes, newest first.
+func (q *Queries) ListNotes(ctx context.Context, limit int) ([]Note, error) {
+	rows, err := q.db.Query(ctx,
+		"SELECT id, body, created_at FROM notes ORDER BY id DESC LIMIT $1", limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Note])
+}
+
+// ListNotesPage is limit notes, newest first, after the first offset.
+func (q *Queries) ListNotesPage(ctx context.Context, limit, offset int) ([]Note, error) {
+	rows, err := q.db.Query(ctx,
+		"SELECT id, body, created_at FROM notes ORDER BY id DESC LIMIT $1 OFFSET $2", limit, offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByPos[Note])
+}
+
+func (q *Queries) CountNotes(ctx context.Context) (int64, error) {
+	var n int64
+	err := q.db.QueryRow(ctx, "SELECT count(*) FROM notes").Scan(&n)
+	return n, err
+}
@@ -0,0 +1,92 @@
+//go:build integration
+
+// Run with `just test-integration`, against the database in
+// DATABASE_URL (`just db` starts a local one).
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestOpenMigratesAndQueries(t *testing.T) {
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	pool, err := Open(ctx, url, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	// Each test works in a transaction it rolls back, leaving no rows.
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	q := New(tx)
+	for _, body := range []string{"first", "second"} {
+		if _, err := q.CreateNote(ctx, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notes, err := q.ListNotes(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2 || notes[0].Body != "second" || notes[0].CreatedAt.IsZero() {
+		t.Errorf("expected the two notes, newest first, got %+v", notes)
+	}
+
+	// Migrating again finds nothing to do.
+	if err := Migrate(ctx, pool); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListNotesPage(t *testing.T) {
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	pool, err := Open(ctx, url, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	q := New(tx)
+
+	before, err := q.CountNotes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, body := range []string{"first", "second", "third"} {
+		if _, err := q.CreateNote(ctx, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := q.CountNotes(ctx); err != nil || n != before+3 {
+		t.Fatalf("expected %d notes, got %d, %v", before+3, n, err)
+	}
+	first, err := q.ListNotesPage(ctx, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := q.ListNotesPage(ctx, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 2 || first[0].Body != "third" || first[1].Body != "second" || len(next) == 0 || next[0].Body != "first" {
+		t.Errorf("expected the notes two to a page, newest first, got %+v then %+v", first, next)
+	}
+}
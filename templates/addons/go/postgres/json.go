@@ -0,0 +1,13 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// writeJSON answers with status and v as JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
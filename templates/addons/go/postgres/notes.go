@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"{{MODULE_PATH}}/components"
+	"{{MODULE_PATH}}/internal/logging"
+	"{{MODULE_PATH}}/internal/paging"
+	"{{MODULE_PATH}}/internal/postgres"
+)
+
+// handleListNotes answers with the latest notes saved, as JSON.
+func handleListNotes(q *postgres.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		notes, err := q.ListNotes(r.Context(), 20)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("list notes", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, notes)
+	}
+}
+
+// handleCreateNote saves the form's body.
+func handleCreateNote(q *postgres.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := strings.TrimSpace(r.FormValue("body"))
+		if body == "" {
+			http.Error(w, "body is required", http.StatusBadRequest)
+			return
+		}
+		note, err := q.CreateNote(r.Context(), body)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("create note", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusCreated, note)
+	}
+}
+
+// handleNotesPage is the notes saved as a page of a list, newest first,
+// paged by ?page= and ?size=. A page past the last is not found.
+func handleNotesPage(q *postgres.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := paging.FromQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if p.Total, err = q.CountNotes(r.Context()); err != nil {
+			logging.FromContext(r.Context()).Error("count notes", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if p.Past() {
+			handleNotFound(w, r)
+			return
+		}
+		notes, err := q.ListNotesPage(r.Context(), p.Size, p.Offset())
+		if err != nil {
+			logging.FromContext(r.Context()).Error("list notes", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		components.Page("Notes", components.Notes(notes, p, r.URL)).Render(r.Context(), w)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// The rest of the notes API runs against a database, in
+// internal/postgres's integration test.
+func TestCreateNoteNeedsABody(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleCreateNote(nil)(w, httptest.NewRequest("POST", "/api/notes", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestNotesPageNeedsAPageNumber(t *testing.T) {
+	for _, path := range []string{"/notes?page=0", "/notes?page=last", "/notes?size=1000"} {
+		w := httptest.NewRecorder()
+		handleNotesPage(nil)(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, w.Code)
+		}
+	}
+}
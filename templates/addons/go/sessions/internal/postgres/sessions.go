@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SessionStore keeps scs sessions in the sessions table. Saving one
+// also deletes those that have expired.
+type SessionStore struct {
+	db DBTX
+}
+
+func NewSessionStore(db DBTX) *SessionStore {
+	return &SessionStore{db: db}
+}
+
+func (s *SessionStore) FindCtx(ctx context.Context, token string) ([]byte, bool, error) {
+	var data []byte
+	err := s.db.QueryRow(ctx,
+		"SELECT data FROM sessions WHERE token = $1 AND expiry > now()", token,
+	).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	return data, err == nil, err
+}
+
+func (s *SessionStore) CommitCtx(ctx context.Context, token string, data []byte, expiry time.Time) error {
+	if _, err := s.db.Exec(ctx, "DELETE FROM sessions WHERE expiry <= now()"); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(ctx,
+		`INSERT INTO sessions (token, data, expiry) VALUES ($1, $2, $3)
+		ON CONFLICT (token) DO UPDATE SET data = excluded.data, expiry = excluded.expiry`,
+		token, data, expiry,
+	)
+	return err
+}
+
+func (s *SessionStore) DeleteCtx(ctx context.Context, token string) error {
+	_, err := s.db.Exec(ctx, "DELETE FROM sessions WHERE token = $1", token)
+	return err
+}
+
+func (s *SessionStore) Find(token string) ([]byte, bool, error) {
+	return s.FindCtx(context.Background(), token)
+}
+
+func (s *SessionStore) Commit(token string, data []byte, expiry time.Time) error {
+	return s.CommitCtx(context.Background(), token, data, expiry)
+}
+
+func (s *SessionStore) Delete(token string) error {
+	return s.DeleteCtx(context.Background(), token)
+}
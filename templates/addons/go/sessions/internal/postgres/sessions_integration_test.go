@@ -0,0 +1,48 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSessionStore(t *testing.T) {
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	pool, err := Open(ctx, url, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	s := NewSessionStore(tx)
+
+	if err := s.CommitCtx(ctx, "live", []byte("a"), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CommitCtx(ctx, "old", []byte("a"), time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if data, found, err := s.FindCtx(ctx, "live"); err != nil || !found || string(data) != "a" {
+		t.Errorf("expected the session, got %q, %v, %v", data, found, err)
+	}
+	if _, found, err := s.FindCtx(ctx, "old"); err != nil || found {
+		t.Errorf("expected an expired session to be gone, got %v, %v", found, err)
+	}
+	if err := s.DeleteCtx(ctx, "live"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := s.FindCtx(ctx, "live"); found {
+		t.Error("expected a deleted session to be gone")
+	}
+}
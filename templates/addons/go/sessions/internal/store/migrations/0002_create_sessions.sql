@@ -0,0 +1,7 @@
+CREATE TABLE sessions (
+    token TEXT PRIMARY KEY,
+    data BLOB NOT NULL,
+    expiry INTEGER NOT NULL -- Unix seconds
+);
+
+CREATE INDEX sessions_expiry_idx ON sessions (expiry);
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// SessionStore keeps scs sessions in the sessions table. Saving one
+// also deletes those that have expired.
+type SessionStore struct {
+	q *Queries
+}
+
+func NewSessionStore(db DBTX) *SessionStore {
+	return &SessionStore{q: New(db)}
+}
+
+func (s *SessionStore) FindCtx(ctx context.Context, token string) ([]byte, bool, error) {
+	data, err := s.q.FindSession(ctx, FindSessionParams{Token: token, Expiry: time.Now().Unix()})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	return data, err == nil, err
+}
+
+func (s *SessionStore) CommitCtx(ctx context.Context, token string, data []byte, expiry time.Time) error {
+	if err := s.q.DeleteExpiredSessions(ctx, time.Now().Unix()); err != nil {
+		return err
+	}
+	return s.q.CommitSession(ctx, CommitSessionParams{Token: token, Data: data, Expiry: expiry.Unix()})
+}
+
+func (s *SessionStore) DeleteCtx(ctx context.Context, token string) error {
+	return s.q.DeleteSession(ctx, token)
+}
+
+func (s *SessionStore) Find(token string) ([]byte, bool, error) {
+	return s.FindCtx(context.Background(), token)
+}
+
+func (s *SessionStore) Commit(token string, data []byte, expiry time.Time) error {
+	return s.CommitCtx(context.Background(), token, data, expiry)
+}
+
+func (s *SessionStore) Delete(token string) error {
+	return s.DeleteCtx(context.Background(), token)
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSessionStore(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s := NewSessionStore(db)
+
+	if err := s.CommitCtx(ctx, "live", []byte("a"), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CommitCtx(ctx, "live", []byte("b"), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if data, found, err := s.FindCtx(ctx, "live"); err != nil || !found || string(data) != "b" {
+		t.Errorf("expected the latest data, got %q, %v, %v", data, found, err)
+	}
+
+	if err := s.CommitCtx(ctx, "old", []byte("a"), time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := s.FindCtx(ctx, "old"); err != nil || found {
+		t.Errorf("expected an expired session to be gone, got %v, %v", found, err)
+	}
+
+	if err := s.DeleteCtx(ctx, "live"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := s.FindCtx(ctx, "live"); found {
+		t.Error("expected a deleted session to be gone")
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alexedwards/scs/v2"
+
+	"{{MODULE_PATH}}/internal/config"
+)
+
+// newSessions is the session manager: a cookie that lasts a day, sent
+// only over https when users reach the app that way. Sessions live in
+// memory, lost on a restart, unless main gives it a database store.
+func newSessions(cfg config.Config) *scs.SessionManager {
+	sessions := scs.New()
+	sessions.Lifetime = 24 * time.Hour
+	sessions.Cookie.Secure = cfg.HTTPS()
+	return sessions
+}
+
+// handleVisits counts the visitor's requests in their session.
+func handleVisits(sessions *scs.SessionManager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		visits := sessions.GetInt(r.Context(), "visits") + 1
+		sessions.Put(r.Context(), "visits", visits)
+		fmt.Fprintf(w, "visits: %d\n", visits)
+	}
+}
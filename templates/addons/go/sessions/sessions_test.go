@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/config"
+)
+
+func TestVisitsKeepCountInTheCookiesSession(t *testing.T) {
+	sessions := newSessions(config.Config{BaseURL: "http://localhost:4000"})
+	handler := sessions.LoadAndSave(handleVisits(sessions))
+	visit := func(cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/visits", nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := visit(nil)
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "session" || !cookies[0].HttpOnly || cookies[0].Secure {
+		t.Fatalf("expected an HttpOnly session cookie, got %+v", cookies)
+	}
+	if got := visit(cookies[0]).Body.String(); got != "visits: 2\n" {
+		t.Errorf("with the cookie: expected the second visit, got %q", got)
+	}
+	if got := visit(nil).Body.String(); got != "visits: 1\n" {
+		t.Errorf("without it: expected a new session, got %q", got)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"{{MODULE_PATH}}/components"
+	"{{MODULE_PATH}}/internal/logging"
+	"{{MODULE_PATH}}/internal/paging"
+	"{{MODULE_PATH}}/internal/store"
+)
+
+// handleListGreetings answers with the latest greetings saved, as JSON.
+func handleListGreetings(q *store.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		greetings, err := q.ListGreetings(r.Context(), 20)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("list greetings", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if greetings == nil {
+			greetings = []store.Greeting{}
+		}
+		writeJSON(w, http.StatusOK, greetings)
+	}
+}
+
+// handleCreateGreeting saves the form's message.
+func handleCreateGreeting(q *store.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		message := strings.TrimSpace(r.FormValue("message"))
+		if message == "" {
+			http.Error(w, "message is required", http.StatusBadRequest)
+			return
+		}
+		greeting, err := q.CreateGreeting(r.Context(), message)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("create greeting", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusCreated, greeting)
+	}
+}
+
+// handleGreetingsPage is the greetings saved as a page of a list,
+// newest first, paged by ?page= and ?size=. A page past the last is
+// not found.
+func handleGreetingsPage(q *store.Queries) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := paging.FromQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if p.Total, err = q.CountGreetings(r.Context()); err != nil {
+			logging.FromContext(r.Context()).Error("count greetings", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if p.Past() {
+			handleNotFound(w, r)
+			return
+		}
+		greetings, err := q.ListGreetingsPage(r.Context(), store.ListGreetingsPageParams{
+			Limit:  int64(p.Size),
+			Offset: int64(p.Offset()),
+		})
+		if err != nil {
+			logging.FromContext(r.Context()).Error("list greetings", "err", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		components.Page("Greetings", components.Greetings(greetings, p, r.URL)).Render(r.Context(), w)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/store"
+)
+
+func TestGreetings(t *testing.T) {
+	db, err := store.Open(context.Background(), filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := store.New(db)
+
+	form := url.Values{"message": {"hi there"}}.Encode()
+	req := httptest.NewRequest("POST", "/api/greetings", strings.NewReader(form))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	handleCreateGreeting(q)(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body)
+	}
+
+	w = httptest.NewRecorder()
+	handleListGreetings(q)(w, httptest.NewRequest("GET", "/api/greetings", nil))
+	var greetings []store.Greeting
+	if err := json.NewDecoder(w.Body).Decode(&greetings); err != nil {
+		t.Fatal(err)
+	}
+	if len(greetings) != 1 || greetings[0].Message != "hi there" {
+		t.Errorf("expected the saved greeting, got %+v", greetings)
+	}
+
+	w = httptest.NewRecorder()
+	handleCreateGreeting(q)(w, httptest.NewRequest("POST", "/api/greetings", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("no message: expected status 400, got %d", w.Code)
+	}
+}
+
+func TestGreetingsPage(t *testing.T) {
+	db, err := store.Open(context.Background(), filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := store.New(db)
+	for i := 1; i <= 25; i++ {
+		if _, err := q.CreateGreeting(context.Background(), fmt.Sprintf("greeting %02d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path   string
+		status int
+		want   []string // in the page
+		absent []string // not
+	}{
+		{"/greetings", http.StatusOK, []string{"greeting 25", "greeting 06", "?page=2", "Page 1 of 2"}, []string{"greeting 05", `rel="prev"`}},
+		{"/greetings?page=2", http.StatusOK, []string{"greeting 05", "greeting 01", "?page=1", "Page 2 of 2"}, []string{"greeting 06", `rel="next"`}},
+		{"/greetings?size=10&page=3", http.StatusOK, []string{"greeting 05", "Page 3 of 3", "?page=2&amp;size=10"}, []string{"greeting 06"}},
+		{"/greetings?page=3", http.StatusNotFound, nil, nil},
+		{"/greetings?page=first", http.StatusBadRequest, []string{"page"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handleGreetingsPage(q)(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(w.Body.String(), s) {
+					t.Errorf("expected %q in the page, got %s", s, w.Body)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(w.Body.String(), s) {
+					t.Errorf("expected no %q in the page, got %s", s, w.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+CREATE TABLE greetings (
+    id INTEGER PRIMARY KEY,
+    message TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,11 @@
+-- name: CreateGreeting :one
+INSERT INTO greetings (message) VALUES (?) RETURNING *;
+
+-- name: ListGreetings :many
+SELECT * FROM greetings ORDER BY id DESC LIMIT ?;
+
+-- name: ListGreetingsPage :many
+SELECT * FROM greetings ORDER BY id DESC LIMIT ? OFFSET ?;
+
+-- name: CountGreetings :one
+SELECT count(*) FROM greetings;
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenMigratesAndQueries(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "test.db")
+	db, err := Open(ctx, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := New(db)
+	for _, message := range []string{"first", "second"} {
+		if _, err := q.CreateGreeting(ctx, message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	// Opening again finds the migrations applied and the rows kept.
+	db, err = Open(ctx, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	greetings, err := New(db).ListGreetings(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(greetings) != 2 || greetings[0].Message != "second" || greetings[0].CreatedAt.IsZero() {
+		t.Errorf("expected the two greetings, newest first, got %+v", greetings)
+	}
+}
+
+func TestListGreetingsPage(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := New(db)
+	for i := 1; i <= 5; i++ {
+		if _, err := q.CreateGreeting(ctx, fmt.Sprint("greeting ", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := q.CountGreetings(ctx); err != nil || n != 5 {
+		t.Fatalf("expected 5 greetings, got %d, %v", n, err)
+	}
+	var got []string
+	for offset := int64(0); offset < 6; offset += 2 {
+		page, err := q.ListGreetingsPage(ctx, ListGreetingsPageParams{Limit: 2, Offset: offset})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, g := range page {
+			got = append(got, g.Message)
+		}
+	}
+	if fmt.Sprint(got) != "[greeting 5 greeting 4 greeting 3 greeting 2 greeting 1]" {
+		t.Errorf("expected each greeting once, newest first, got %q", got)
+	}
+}
@@ -0,0 +1,11 @@
+// Alpine components. The CSP build evaluates no expressions, so an
+// x-data names a component registered here and its attributes name
+// the component's properties and methods.
+document.addEventListener("alpine:init", () => {
+  Alpine.data("counter", () => ({
+    count: 0,
+    increment() {
+      this.count++;
+    },
+  }));
+});
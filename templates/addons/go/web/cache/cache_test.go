@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	ts := startApp(t, nil)
+	for _, path := range []string{"/", "/api/greet", "/static/favicon.svg"} {
+		t.Run(path, func(t *testing.T) {
+			res, err := ts.Client().Get(ts.URL + path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+			etag := res.Header.Get("ETag")
+			if res.StatusCode != http.StatusOK || etag == "" {
+				t.Fatalf("expected a tagged 200, got %d %q", res.StatusCode, etag)
+			}
+
+			req, _ := http.NewRequest("GET", ts.URL+path, nil)
+			for _, c := range res.Cookies() {
+				req.AddCookie(c) // the same CSRF token, so the same page
+			}
+			req.Header.Set("If-None-Match", etag)
+			res, err = ts.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+			if res.StatusCode != http.StatusNotModified {
+				t.Errorf("expected status 304, got %d", res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestGreetingCached(t *testing.T) {
+	ts := startApp(t, nil)
+	get := func() *http.Response {
+		res, err := ts.Client().Get(ts.URL + "/api/greet")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res
+	}
+	if res := get(); res.Header.Get("Age") != "" {
+		t.Errorf("the first answer should not come from the cache, got Age %q", res.Header.Get("Age"))
+	}
+	if res := get(); res.Header.Get("Age") == "" {
+		t.Error("expected the second answer from the cache, with an Age")
+	}
+}
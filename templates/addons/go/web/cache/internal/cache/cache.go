@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache keeps the answers of an expensive handler in memory for ttl,
+// and serves them again meanwhile without calling it. Only a 200 to a
+// GET is kept, and not one that sets a cookie or says Cache-Control
+// private or no-store: those are meant for one client. An answer is
+// kept per URL and per value of the vary headers, and at most max of
+// them (0 is no limit); the ones past their time go first when it is
+// full, then the oldest.
+type Cache struct {
+	ttl  time.Duration
+	max  int
+	vary []string
+	now  func() time.Time // for the tests
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	header  http.Header
+	body    []byte
+	stored  time.Time
+	expires time.Time
+}
+
+// New returns a Cache keeping answers for ttl (0 keeps none), at most
+// max of them, told apart by the vary headers as well as their URL.
+func New(ttl time.Duration, max int, vary ...string) *Cache {
+	return &Cache{ttl: ttl, max: max, vary: vary, now: time.Now, entries: map[string]*entry{}}
+}
+
+// Handler serves next's answers from the cache while they are fresh,
+// with an Age header saying for how long they have been kept, and
+// calls it for the rest.
+func (c *Cache) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.ttl <= 0 || r.Method != http.MethodGet && r.Method != http.MethodHead || streams(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := c.key(r)
+		if e := c.get(key); e != nil {
+			for k, v := range e.header {
+				w.Header()[k] = v
+			}
+			w.Header().Set("Age", strconv.Itoa(int(c.now().Sub(e.stored)/time.Second)))
+			w.Write(e.body)
+			return
+		}
+		rec := newRecorder()
+		next.ServeHTTP(rec, r)
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.status())
+		w.Write(rec.body.Bytes())
+		if r.Method == http.MethodGet && keeps(rec) {
+			c.put(key, rec)
+		}
+	})
+}
+
+// Clear drops every answer kept, for when what they show has changed.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+func (c *Cache) key(r *http.Request) string {
+	var b strings.Builder
+	b.WriteString(r.URL.RequestURI())
+	for _, h := range c.vary {
+		b.WriteString("\n" + r.Header.Get(h))
+	}
+	return b.String()
+}
+
+func (c *Cache) get(key string) *entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.entries[key]
+	if e == nil || !c.now().Before(e.expires) {
+		return nil
+	}
+	return e
+}
+
+func (c *Cache) put(key string, rec *recorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if _, ok := c.entries[key]; !ok && c.max > 0 && len(c.entries) >= c.max {
+		c.evict(now)
+	}
+	c.entries[key] = &entry{
+		header:  rec.header.Clone(),
+		body:    append([]byte(nil), rec.body.Bytes()...),
+		stored:  now,
+		expires: now.Add(c.ttl),
+	}
+}
+
+// evict makes room for one more entry: it drops those past their time
+// or, when none are, the oldest.
+func (c *Cache) evict(now time.Time) {
+	var oldest string
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+			continue
+		}
+		if oldest == "" || e.stored.Before(c.entries[oldest].stored) {
+			oldest = k
+		}
+	}
+	if len(c.entries) >= c.max {
+		delete(c.entries, oldest)
+	}
+}
+
+// keeps is whether rec is an answer Cache may give another client.
+func keeps(rec *recorder) bool {
+	if rec.status() != http.StatusOK || rec.header.Get("Set-Cookie") != "" {
+		return false
+	}
+	control := strings.ToLower(rec.header.Get("Cache-Control"))
+	return !strings.Contains(control, "private") && !strings.Contains(control, "no-store")
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// counter answers each request with how many it has had, so a test can
+// tell a cached answer from a fresh one.
+type counter struct{ calls int }
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.calls++
+	if r.URL.Query().Has("cookie") {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "x"})
+	}
+	if r.URL.Query().Has("private") {
+		w.Header().Set("Cache-Control", "private")
+	}
+	if r.URL.Query().Has("missing") {
+		http.NotFound(w, r)
+		return
+	}
+	fmt.Fprintf(w, "call %d", c.calls)
+}
+
+func get(t *testing.T, h http.Handler, path string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("GET", path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestCache(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := New(10*time.Second, 0)
+	c.now = func() time.Time { return now }
+	next := &counter{}
+	h := c.Handler(next)
+
+	if got := get(t, h, "/greet").Body.String(); got != "call 1" {
+		t.Fatalf("expected the first call, got %q", got)
+	}
+	now = now.Add(3 * time.Second)
+	w := get(t, h, "/greet")
+	if w.Body.String() != "call 1" || w.Header().Get("Age") != "3" {
+		t.Errorf("expected the kept answer, 3 seconds old, got %q age %q", w.Body, w.Header().Get("Age"))
+	}
+	if got := get(t, h, "/greet?name=x").Body.String(); got != "call 2" {
+		t.Errorf("another URL should be called for, got %q", got)
+	}
+
+	now = now.Add(7 * time.Second)
+	if got := get(t, h, "/greet").Body.String(); got != "call 3" {
+		t.Errorf("expected the answer called for again after the TTL, got %q", got)
+	}
+
+	c.Clear()
+	if got := get(t, h, "/greet").Body.String(); got != "call 4" {
+		t.Errorf("expected Clear to drop what was kept, got %q", got)
+	}
+}
+
+func TestCacheKeepsOnlyShared(t *testing.T) {
+	for _, path := range []string{"/?cookie", "/?private", "/?missing"} {
+		next := &counter{}
+		h := New(time.Minute, 0).Handler(next)
+		get(t, h, path)
+		get(t, h, path)
+		if next.calls != 2 {
+			t.Errorf("%s should not be kept, the handler had %d calls", path, next.calls)
+		}
+	}
+
+	next := &counter{}
+	h := New(time.Minute, 0).Handler(next)
+	req := httptest.NewRequest("POST", "/", nil)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if next.calls != 2 {
+		t.Errorf("a POST should not be kept, the handler had %d calls", next.calls)
+	}
+}
+
+func TestCacheVary(t *testing.T) {
+	next := &counter{}
+	h := New(time.Minute, 0, "Accept-Language").Handler(next)
+	get(t, h, "/", "Accept-Language", "en")
+	if got := get(t, h, "/", "Accept-Language", "nb").Body.String(); got != "call 2" {
+		t.Errorf("another language should be called for, got %q", got)
+	}
+	if got := get(t, h, "/", "Accept-Language", "en").Body.String(); got != "call 1" {
+		t.Errorf("expected the English answer kept, got %q", got)
+	}
+}
+
+func TestCacheMax(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := New(time.Minute, 2)
+	c.now = func() time.Time { return now }
+	next := &counter{}
+	h := c.Handler(next)
+
+	for _, path := range []string{"/a", "/b", "/c"} {
+		get(t, h, path)
+		now = now.Add(time.Second)
+	}
+	if len(c.entries) != 2 {
+		t.Fatalf("expected 2 kept, got %d", len(c.entries))
+	}
+	if got := get(t, h, "/a").Body.String(); got != "call 4" {
+		t.Errorf("expected the oldest dropped, got %q", got)
+	}
+	if got := get(t, h, "/c").Body.String(); got != "call 3" {
+		t.Errorf("expected the newest kept, got %q", got)
+	}
+}
+
+func TestCacheOff(t *testing.T) {
+	next := &counter{}
+	h := New(0, 0).Handler(next)
+	get(t, h, "/")
+	get(t, h, "/")
+	if next.calls != 2 {
+		t.Errorf("a TTL of 0 should keep nothing, the handler had %d calls", next.calls)
+	}
+}
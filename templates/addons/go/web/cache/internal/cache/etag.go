@@ -0,0 +1,135 @@
+// Package cache answers repeated requests cheaply: ETag and Fresh let a
+// browser's copy be checked with a 304 and no body, and Cache keeps a
+// handler's answers in memory for a while.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag tags each 200 answer to a GET or HEAD with the hash of its body,
+// unless the handler has tagged it already, and answers a request
+// whose If-None-Match has that tag with a 304 instead. The body is held
+// until the handler returns, to be hashed, so WebSocket upgrades and
+// event streams are let through, as are paths under the except
+// prefixes.
+func ETag(except ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead || streams(r) || under(r.URL.Path, except) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rec := newRecorder()
+			next.ServeHTTP(rec, r)
+			for k, v := range rec.header {
+				w.Header()[k] = v
+			}
+			if rec.status() == http.StatusOK && w.Header().Get("ETag") == "" {
+				sum := sha256.Sum256(rec.body.Bytes())
+				w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+			}
+			if rec.status() == http.StatusOK && Fresh(w, r, w.Header().Get("ETag"), time.Time{}) {
+				return
+			}
+			w.WriteHeader(rec.status())
+			w.Write(rec.body.Bytes())
+		})
+	}
+}
+
+// Fresh sets w's ETag and Last-Modified to etag and modified, those
+// that are not empty or zero, and reports whether r says the browser
+// has that version already, in which case it has had a 304: a handler
+// that knows what version it would render can ask first, and skip
+// rendering it.
+func Fresh(w http.ResponseWriter, r *http.Request, etag string, modified time.Time) bool {
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	if !modified.IsZero() {
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	// If-None-Match wins when both are sent.
+	fresh := false
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		fresh = etag != "" && matches(match, etag)
+	} else if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+		fresh = !modified.IsZero() && !modified.Truncate(time.Second).After(since)
+	}
+	if fresh {
+		for _, k := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+			w.Header().Del(k)
+		}
+		w.WriteHeader(http.StatusNotModified)
+	}
+	return fresh
+}
+
+// matches is whether an If-None-Match list names etag, or is *; weak
+// tags match strong ones, as a GET's comparison allows.
+func matches(list, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// streams is whether r asks for a response that stays open.
+func streams(r *http.Request) bool {
+	return r.Header.Get("Upgrade") != "" || strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+func under(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// recorder holds a response for ETag and Cache to look at before it is
+// sent on.
+type recorder struct {
+	header http.Header
+	body   bytes.Buffer
+	code   int
+}
+
+func newRecorder() *recorder { return &recorder{header: http.Header{}} }
+
+func (rec *recorder) Header() http.Header { return rec.header }
+
+func (rec *recorder) Write(p []byte) (int, error) {
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	return rec.body.Write(p)
+}
+
+func (rec *recorder) WriteHeader(code int) {
+	if rec.code != 0 {
+		return
+	}
+	rec.code = code
+}
+
+func (rec *recorder) status() int {
+	if rec.code == 0 {
+		return http.StatusOK
+	}
+	return rec.code
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestETag(t *testing.T) {
+	page := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<p>hello</p>"))
+	})
+	h := ETag("/debug/")(page)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.String() != "<p>hello</p>" {
+		t.Fatalf("expected a tagged 200, got %d %q %q", w.Code, etag, w.Body)
+	}
+
+	tests := []struct {
+		name   string
+		match  string
+		status int
+	}{
+		{"the same tag", etag, http.StatusNotModified},
+		{"among others", `"other", ` + etag, http.StatusNotModified},
+		{"weak", "W/" + etag, http.StatusNotModified},
+		{"any", "*", http.StatusNotModified},
+		{"another tag", `"other"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("If-None-Match", tt.match)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if tt.status == http.StatusNotModified && (w.Body.Len() != 0 || w.Header().Get("Content-Type") != "") {
+				t.Errorf("expected a 304 without a body, got %q %v", w.Body, w.Header())
+			}
+		})
+	}
+}
+
+func TestETagLeavesAlone(t *testing.T) {
+	tagged := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("tagged"))
+	})
+	w := httptest.NewRecorder()
+	ETag()(tagged).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if got := w.Header().Get("ETag"); got != `"v1"` {
+		t.Errorf("expected the handler's own tag, got %q", got)
+	}
+
+	missing := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	w = httptest.NewRecorder()
+	ETag()(missing).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+		t.Errorf("expected an untagged 404, got %d %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("POST", "/", nil),
+		httptest.NewRequest("GET", "/debug/pprof/", nil),
+		httptest.NewRequest("GET", "/events", nil),
+	} {
+		if req.URL.Path == "/events" {
+			req.Header.Set("Accept", "text/event-stream")
+		}
+		w = httptest.NewRecorder()
+		ETag("/debug/")(tagless()).ServeHTTP(w, req)
+		if w.Header().Get("ETag") != "" {
+			t.Errorf("%s %s should not be tagged", req.Method, req.URL.Path)
+		}
+	}
+}
+
+func tagless() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("body"))
+	})
+}
+
+func TestFresh(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+	tests := []struct {
+		name   string
+		header map[string]string
+		fresh  bool
+	}{
+		{"no conditions", nil, false},
+		{"the same tag", map[string]string{"If-None-Match": `"v1"`}, true},
+		{"another tag", map[string]string{"If-None-Match": `"v0"`}, false},
+		{"not modified since", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"}, true},
+		{"modified since", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 11:59:59 GMT"}, false},
+		{"the tag wins", map[string]string{"If-None-Match": `"v0"`, "If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"}, false},
+		{"a bad date", map[string]string{"If-Modified-Since": "yesterday"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			if got := Fresh(w, req, `"v1"`, modified); got != tt.fresh {
+				t.Errorf("expected %v, got %v", tt.fresh, got)
+			}
+			if w.Header().Get("ETag") != `"v1"` || w.Header().Get("Last-Modified") != "Wed, 01 May 2024 12:00:00 GMT" {
+				t.Errorf("expected the validators set, got %v", w.Header())
+			}
+			if tt.fresh && w.Code != http.StatusNotModified {
+				t.Errorf("expected status 304, got %d", w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+//go:build e2e
+
+// The end-to-end tests drive a headless Chrome, through chromedp,
+// against the whole app as startApp runs it. They are left out of `go
+// test ./...`; `just e2e` runs them.
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// browser is a headless Chrome tab, closed when the test ends.
+func browser(t *testing.T) context.Context {
+	t.Helper()
+	// CI containers run as root, where Chrome's sandbox will not start.
+	opts := append(chromedp.DefaultExecAllocatorOptions[:], chromedp.NoSandbox)
+	ctx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	ctx, cancelTab := chromedp.NewContext(ctx)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	t.Cleanup(func() {
+		cancel()
+		cancelTab()
+		cancelAlloc()
+	})
+	return ctx
+}
+
+func TestBrowserGreets(t *testing.T) {
+	ts := startApp(t, nil)
+	ctx := browser(t)
+
+	var title, greeting string
+	err := chromedp.Run(ctx,
+		chromedp.Navigate(ts.URL),
+		chromedp.Title(&title),
+		chromedp.Click("#greeting button", chromedp.ByQuery),
+		// Waits for htmx to swap the greeting in.
+		chromedp.Text("#greeting p", &greeting, chromedp.ByQuery),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Home" {
+		t.Errorf("expected the title Home, got %q", title)
+	}
+	if greeting != "Hello from the server!" {
+		t.Errorf("expected the greeting, got %q", greeting)
+	}
+}
@@ -0,0 +1,6 @@
+// The entry point esbuild bundles into internal/web/static/dist. Import
+// modules and stylesheets from here; components.Asset finds the hashed
+// output.
+import "./main.css";
+
+document.documentElement.dataset.js = "ready";
@@ -0,0 +1,78 @@
+package components
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"{{MODULE_PATH}}/internal/static"
+)
+
+// metafile is esbuild's record of the files it wrote to
+// internal/web/static/dist, found among the files package static serves.
+const metafile = "dist/meta.json"
+
+// staticDir is where the files package static serves are in the
+// project, which the metafile's paths start with.
+const staticDir = "internal/web/static/"
+
+var assets struct {
+	sync.Mutex
+	modified time.Time
+	urls     map[string]string
+}
+
+// Asset is the URL of what esbuild built from an entry point in assets/:
+// Asset("main.js") is /static/dist/main-HASH.js and Asset("main.css")
+// the stylesheet main.ts imports. In development the metafile is read
+// again whenever esbuild rewrites it, so a --watch rebuild shows on the
+// next page load; before the first build the name is used unhashed.
+func Asset(name string) string {
+	assets.Lock()
+	defer assets.Unlock()
+	files := static.FS()
+	info, err := fs.Stat(files, metafile)
+	if err == nil && (assets.urls == nil || !info.ModTime().Equal(assets.modified)) {
+		if urls, err := readMetafile(files); err == nil {
+			assets.modified, assets.urls = info.ModTime(), urls
+		}
+	}
+	if url, ok := assets.urls[name]; ok {
+		return url
+	}
+	return "/static/dist/" + name
+}
+
+// readMetafile maps each entry point's name, with its output's
+// extension, to the output's URL.
+func readMetafile(files fs.FS) (map[string]string, error) {
+	data, err := fs.ReadFile(files, metafile)
+	if err != nil {
+		return nil, err
+	}
+	var meta struct {
+		Outputs map[string]struct {
+			EntryPoint string `json:"entryPoint"`
+			CSSBundle  string `json:"cssBundle"`
+		} `json:"outputs"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	urls := map[string]string{}
+	for out, o := range meta.Outputs {
+		if o.EntryPoint == "" {
+			continue
+		}
+		entry := path.Base(o.EntryPoint)
+		stem := strings.TrimSuffix(entry, path.Ext(entry))
+		urls[stem+path.Ext(out)] = "/static/" + strings.TrimPrefix(out, staticDir)
+		if o.CSSBundle != "" {
+			urls[stem+".css"] = "/static/" + strings.TrimPrefix(o.CSSBundle, staticDir)
+		}
+	}
+	return urls, nil
+}
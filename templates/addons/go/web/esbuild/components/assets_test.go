@@ -0,0 +1,44 @@
+package components
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/static"
+)
+
+const meta = `{"outputs": {
+	"internal/web/static/dist/main-AB12CD34.js": {
+		"entryPoint": "assets/main.ts",
+		"cssBundle": "internal/web/static/dist/main-EF56GH78.css"
+	},
+	"internal/web/static/dist/main-AB12CD34.js.map": {},
+	"internal/web/static/dist/main-EF56GH78.css": {}
+}}`
+
+func TestAsset(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	os.MkdirAll(filepath.Join(dir, staticDir, "dist"), 0o755)
+	static.Use(nil, true) // internal/web/static/ on disk, here dir's
+
+	if got := Asset("main.js"); got != "/static/dist/main.js" {
+		t.Errorf("before a build: expected the unhashed name, got %q", got)
+	}
+	if err := os.WriteFile(filepath.Join(dir, staticDir, metafile), []byte(meta), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"main.js":  "/static/dist/main-AB12CD34.js",
+		"main.css": "/static/dist/main-EF56GH78.css",
+	} {
+		if got := Asset(name); got != want {
+			t.Errorf("Asset(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+import "net/http"
+
+// isHTMX reports whether r was sent by htmx (hx-get and friends), which
+// swaps the response into the page, rather than by a browser
+// navigating to the URL.
+func isHTMX(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHomeTriggersTheGreeting(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleHome(w, httptest.NewRequest("GET", "/", nil))
+
+	if !strings.Contains(w.Body.String(), `hx-get="/api/greet"`) {
+		t.Errorf("home has no hx-get for /api/greet: %s", w.Body)
+	}
+}
+
+func TestGreetReturnsAFragment(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/greet", nil)
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	handleGreet(w, req)
+
+	body := w.Body.String()
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if body != "<p>Hello from the server!</p>" {
+		t.Errorf("expected the greeting fragment alone, got %q", body)
+	}
+}
+
+func TestGreetWithoutHTMXIsAPage(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleGreet(w, httptest.NewRequest("GET", "/api/greet", nil))
+
+	if !strings.Contains(w.Body.String(), "<html") {
+		t.Errorf("expected a full page, got %q", w.Body)
+	}
+}
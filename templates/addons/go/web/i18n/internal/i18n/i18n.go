@@ -0,0 +1,129 @@
+// Package i18n picks each request's language from its Accept-Language
+// header and translates the app's messages into it. The catalogs are
+// locales/<tag>.json, one per language, each mapping the English text
+// of a message to its translation; English is also what a message
+// falls back to when its language does not have it.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Locales are the catalogs built into the binary.
+//
+//go:embed locales/*.json
+var Locales embed.FS
+
+// Source is the language the messages are written in, served when a
+// request asks for none of the catalogs' languages.
+var Source = language.English
+
+// Catalog holds the translations and picks among their languages.
+type Catalog struct {
+	tags    []language.Tag
+	matcher language.Matcher
+	builder *catalog.Builder
+}
+
+// New loads the locales/*.json catalogs in fsys.
+func New(fsys fs.FS) (*Catalog, error) {
+	paths, err := fs.Glob(fsys, "locales/*.json")
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{tags: []language.Tag{Source}, builder: catalog.NewBuilder(catalog.Fallback(Source))}
+	for _, p := range paths {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(p), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		for key, msg := range messages {
+			if err := c.builder.SetString(tag, key, msg); err != nil {
+				return nil, fmt.Errorf("%s: %q: %w", p, key, err)
+			}
+		}
+		if tag != Source {
+			c.tags = append(c.tags, tag)
+		}
+	}
+	c.matcher = language.NewMatcher(c.tags)
+	return c, nil
+}
+
+// Languages are the catalogs' languages, Source first.
+func (c *Catalog) Languages() []language.Tag {
+	return c.tags
+}
+
+// Match is the catalog language that best suits an Accept-Language
+// header: Source when it is empty, malformed or names none of them.
+func (c *Catalog) Match(accept string) language.Tag {
+	prefs, _, _ := language.ParseAcceptLanguage(accept)
+	_, i, _ := c.matcher.Match(prefs...)
+	return c.tags[i]
+}
+
+type ctxKey struct{}
+
+type locale struct {
+	tag     language.Tag
+	printer *message.Printer
+}
+
+// Negotiate is middleware giving each request the language its
+// Accept-Language header prefers, for T and Lang, and saying so in
+// Content-Language.
+func (c *Catalog) Negotiate(next http.Handler) http.Handler {
+	printers := map[language.Tag]*message.Printer{}
+	for _, tag := range c.tags {
+		printers[tag] = message.NewPrinter(tag, message.Catalog(c.builder))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tag := c.Match(r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", tag.String())
+		ctx := context.WithValue(r.Context(), ctxKey{}, locale{tag, printers[tag]})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+var fallback = locale{Source, message.NewPrinter(Source)}
+
+func from(ctx context.Context) locale {
+	if l, ok := ctx.Value(ctxKey{}).(locale); ok {
+		return l
+	}
+	return fallback
+}
+
+// T is key, a message's English text, in the request's language,
+// formatted with args as by fmt.Sprintf. In a templ component:
+//
+//	<h1>{ i18n.T(ctx, "Hello, %s!", name) }</h1>
+func T(ctx context.Context, key string, args ...any) string {
+	return from(ctx).printer.Sprintf(key, args...)
+}
+
+// Lang is the request's language, for <html lang>.
+func Lang(ctx context.Context) string {
+	return from(ctx).tag.String()
+}
@@ -0,0 +1,127 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMatch(t *testing.T) {
+	c, err := New(Locales)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for accept, want := range map[string]string{
+		"":                        "en",
+		"nb":                      "nb",
+		"nb-NO,nb;q=0.9,en;q=0.8": "nb",
+		"no":                      "nb",
+		"fr-FR, nb;q=0.5":         "nb",
+		"en-GB,en;q=0.9,nb;q=0.8": "en",
+		"fr":                      "en",
+		"not a header;;":          "en",
+	} {
+		if got := c.Match(accept).String(); got != want {
+			t.Errorf("Match(%q) = %s, want %s", accept, got, want)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	c, err := New(Locales)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lang, text string
+	h := c.Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang, text = Lang(r.Context()), T(r.Context(), "Hello, World!")
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "nb-NO")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if lang != "nb" || text != "Hei, verden!" {
+		t.Errorf("nb-NO gave %s %q", lang, text)
+	}
+	if got := w.Header().Get("Content-Language"); got != "nb" {
+		t.Errorf("Content-Language = %q, want nb", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Language" {
+		t.Errorf("Vary = %q, want Accept-Language", got)
+	}
+
+	req.Header.Set("Accept-Language", "de")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if lang != "en" || text != "Hello, World!" {
+		t.Errorf("de gave %s %q", lang, text)
+	}
+}
+
+func TestFallsBack(t *testing.T) {
+	c, err := New(fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{"Hi, %s!": "Hi, %s!", "Bye": "Bye"}`)},
+		"locales/nb.json": {Data: []byte(`{"Hi, %s!": "Hei, %s!"}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hi, bye, unknown string
+	h := c.Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hi, bye, unknown = T(r.Context(), "Hi, %s!", "Ada"), T(r.Context(), "Bye"), T(r.Context(), "New")
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "nb")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if hi != "Hei, Ada!" || bye != "Bye" || unknown != "New" {
+		t.Errorf("got %q %q %q", hi, bye, unknown)
+	}
+
+	// Outside Negotiate, as in a handler's unit test, it is English.
+	if got := T(context.Background(), "Hi, %s!", "Ada"); got != "Hi, Ada!" {
+		t.Errorf("T without Negotiate = %q", got)
+	}
+	if got := Lang(context.Background()); got != "en" {
+		t.Errorf("Lang without Negotiate = %q", got)
+	}
+}
+
+func TestBadCatalogs(t *testing.T) {
+	for name, fsys := range map[string]fstest.MapFS{
+		"bad tag":  {"locales/xx-!.json": {Data: []byte(`{}`)}},
+		"bad json": {"locales/nb.json": {Data: []byte(`{`)}},
+	} {
+		if _, err := New(fsys); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+// Every catalog translates every message en.json lists.
+func TestCatalogsAreComplete(t *testing.T) {
+	read := func(p string) map[string]string {
+		data, err := fs.ReadFile(Locales, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]string
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatalf("%s: %v", p, err)
+		}
+		return m
+	}
+	source := read("locales/en.json")
+	paths, _ := fs.Glob(Locales, "locales/*.json")
+	for _, p := range paths {
+		messages := read(p)
+		for key := range source {
+			if messages[key] == "" {
+				t.Errorf("%s lacks %q", p, key)
+			}
+		}
+	}
+}
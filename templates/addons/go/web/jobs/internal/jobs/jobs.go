@@ -0,0 +1,146 @@
+// Package jobs runs work in the background, off the request that asks
+// for it: a queue of jobs, and a pool of workers taking them in turn.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+var (
+	ErrFull   = errors.New("jobs: the queue is full")
+	ErrClosed = errors.New("jobs: the queue is draining")
+)
+
+// Job is one piece of work, for the Worker to tell from its Kind what
+// to do and from Args what with.
+type Job struct {
+	Kind string            // such as "welcome"
+	Args map[string]string // such as the address to welcome
+}
+
+// Worker does a job. An error is logged, and the job is not tried
+// again.
+type Worker interface {
+	Work(ctx context.Context, job Job) error
+}
+
+// WorkerFunc is a function as a Worker.
+type WorkerFunc func(ctx context.Context, job Job) error
+
+func (f WorkerFunc) Work(ctx context.Context, job Job) error { return f(ctx, job) }
+
+// queued is a job and the logger of the request that enqueued it, so
+// the job's log lines carry the request's ID.
+type queued struct {
+	job    Job
+	logger *slog.Logger
+}
+
+// Queue holds the jobs enqueued until one of its workers is free. Jobs
+// live in memory only: those still queued when the process dies are
+// lost, so a job that must happen belongs in a database.
+type Queue struct {
+	worker  Worker
+	jobs    chan queued
+	ctx     context.Context // the workers', cancelled when a drain runs out of time
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	dropped atomic.Int64 // jobs left undone by that
+
+	mu     sync.RWMutex // held to send on jobs, and to close it
+	closed bool
+}
+
+// New starts n workers (at least 1) doing the jobs enqueued, of which
+// size more may wait their turn.
+func New(worker Worker, n, size int) *Queue {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{worker: worker, jobs: make(chan queued, size), ctx: ctx, cancel: cancel}
+	for range max(n, 1) {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Enqueue adds job to the queue without waiting for room: ErrFull is
+// for the caller to answer (a 503, say), and ErrClosed comes once the
+// queue is draining. The job logs through ctx's logger.
+func (q *Queue) Enqueue(ctx context.Context, job Job) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrClosed
+	}
+	select {
+	case q.jobs <- queued{job, logging.FromContext(ctx)}:
+		return nil
+	default:
+		return ErrFull
+	}
+}
+
+// Drain takes no more jobs and waits for the workers to finish those
+// queued. When ctx ends first, the jobs still at work have their
+// context cancelled, those still queued are dropped, and the error says
+// how many.
+func (q *Queue) Drain(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return fmt.Errorf("jobs: cancelled those at work and dropped %d queued: %w", q.dropped.Load(), ctx.Err())
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for item := range q.jobs {
+		if q.ctx.Err() != nil {
+			q.dropped.Add(1)
+			continue
+		}
+		q.do(item)
+	}
+}
+
+// do runs one job, logging how it went; a panic is logged as its error
+// rather than taking the process down.
+func (q *Queue) do(item queued) {
+	logger := item.logger.With("job", item.job.Kind)
+	ctx := logging.NewContext(q.ctx, logger)
+	err := func() (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("panic: %v", p)
+			}
+		}()
+		return q.worker.Work(ctx, item.job)
+	}()
+	if err != nil {
+		logger.Error("job failed", "err", err)
+		return
+	}
+	logger.Debug("job done")
+}
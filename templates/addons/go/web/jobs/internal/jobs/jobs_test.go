@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// fake is a Worker that says on started which job it takes, then holds
+// it until gate is closed or its context ends, so a test knows where the
+// workers are without sleeping.
+type fake struct {
+	started chan Job
+	gate    chan struct{}
+}
+
+func newFake() *fake {
+	return &fake{started: make(chan Job, 100), gate: make(chan struct{})}
+}
+
+func (f *fake) Work(ctx context.Context, job Job) error {
+	f.started <- job
+	if job.Kind == "panic" {
+		panic("boom")
+	}
+	select {
+	case <-f.gate:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// took is the kinds of the n jobs the worker takes next.
+func (f *fake) took(t *testing.T, n int) []string {
+	t.Helper()
+	var kinds []string
+	for range n {
+		select {
+		case job := <-f.started:
+			kinds = append(kinds, job.Kind)
+		case <-time.After(time.Second):
+			t.Fatalf("expected %d jobs taken, got %q", n, kinds)
+		}
+	}
+	return kinds
+}
+
+func TestQueueDoesEachJob(t *testing.T) {
+	f := newFake()
+	close(f.gate)
+	q := New(f, 2, 10)
+	for _, kind := range []string{"a", "b", "c"} {
+		if err := q.Enqueue(context.Background(), Job{Kind: kind}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.took(t, 3); len(got) != 3 {
+		t.Errorf("expected the three jobs done, got %q", got)
+	}
+	if err := q.Enqueue(context.Background(), Job{Kind: "late"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed once drained, got %v", err)
+	}
+}
+
+func TestEnqueueWhenFull(t *testing.T) {
+	f := newFake()
+	q := New(f, 1, 1)
+	q.Enqueue(context.Background(), Job{Kind: "at work"})
+	f.took(t, 1)
+	if err := q.Enqueue(context.Background(), Job{Kind: "waiting"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Enqueue(context.Background(), Job{Kind: "no room"}); !errors.Is(err, ErrFull) {
+		t.Errorf("expected ErrFull, got %v", err)
+	}
+	close(f.gate)
+	if err := q.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.took(t, 1); got[0] != "waiting" {
+		t.Errorf("expected the waiting job done on the drain, got %q", got)
+	}
+}
+
+func TestDrainRunsOutOfTime(t *testing.T) {
+	f := newFake()
+	q := New(f, 1, 10)
+	q.Enqueue(context.Background(), Job{Kind: "at work"})
+	f.took(t, 1)
+	q.Enqueue(context.Background(), Job{Kind: "queued"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := q.Drain(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "dropped 1 queued") {
+		t.Errorf("expected the drain to give up on the queued job, got %v", err)
+	}
+	select {
+	case job := <-f.started:
+		t.Errorf("expected the queued job dropped, got %q taken", job.Kind)
+	default:
+	}
+}
+
+func TestJobsLogThroughTheirRequest(t *testing.T) {
+	var logs bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(&logs, nil)).With("request_id", "abc"))
+	f := newFake()
+	close(f.gate)
+	q := New(f, 1, 10)
+	q.Enqueue(ctx, Job{Kind: "panic"})
+	q.Enqueue(ctx, Job{Kind: "after"})
+	if err := q.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := f.took(t, 2); got[1] != "after" {
+		t.Errorf("expected the worker to go on after a panic, got %q", got)
+	}
+	if line := logs.String(); !strings.Contains(line, "request_id=abc job=panic err=\"panic: boom\"") {
+		t.Errorf("expected the panic logged with the request's ID, got %q", line)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"{{MODULE_PATH}}/internal/jobs"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// work does the app's background jobs, telling them apart by kind; add
+// a case for each new kind.
+func work(ctx context.Context, job jobs.Job) error {
+	switch job.Kind {
+	case "welcome":
+		// Where the welcome email would be sent: it is slow, and may
+		// fail, so the request does not wait for it.
+		logging.FromContext(ctx).Info("welcome sent", "email", job.Args["email"])
+		return nil
+	}
+	return fmt.Errorf("no work for %q jobs", job.Kind)
+}
+
+// handleWelcome queues a welcome for the form's email and answers at
+// once, with a 202, or a 503 when the queue has no room for it.
+func handleWelcome(queue *jobs.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email := strings.TrimSpace(r.FormValue("email"))
+		if email == "" {
+			http.Error(w, "email is required", http.StatusBadRequest)
+			return
+		}
+		// ErrFull, or ErrClosed while the server shuts down.
+		if err := queue.Enqueue(r.Context(), jobs.Job{Kind: "welcome", Args: map[string]string{"email": email}}); err != nil {
+			w.Header().Set("Retry-After", "10")
+			http.Error(w, "Too busy to take that now; try again shortly.", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, "A welcome is on its way to %s.", email)
+	}
+}
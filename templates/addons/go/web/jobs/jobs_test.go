@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/jobs"
+)
+
+func TestWelcome(t *testing.T) {
+	done := make(chan jobs.Job, 1)
+	queue := jobs.New(jobs.WorkerFunc(func(_ context.Context, job jobs.Job) error {
+		done <- job
+		return nil
+	}), 1, 1)
+	post := func(email string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/welcome", strings.NewReader(url.Values{"email": {email}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handleWelcome(queue)(w, req)
+		return w
+	}
+
+	if w := post("ada@example.com"); w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body)
+	}
+	if job := <-done; job.Kind != "welcome" || job.Args["email"] != "ada@example.com" {
+		t.Errorf("expected a welcome for ada@example.com, got %+v", job)
+	}
+	if w := post(""); w.Code != http.StatusBadRequest {
+		t.Errorf("no email: expected status 400, got %d", w.Code)
+	}
+
+	if err := queue.Drain(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if w := post("ada@example.com"); w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("draining: expected status 503 and a Retry-After, got %d %v", w.Code, w.Header())
+	}
+}
+
+func TestWork(t *testing.T) {
+	if err := work(context.Background(), jobs.Job{Kind: "welcome", Args: map[string]string{"email": "ada@example.com"}}); err != nil {
+		t.Errorf("welcome: %v", err)
+	}
+	if err := work(context.Background(), jobs.Job{Kind: "unheard of"}); err == nil {
+		t.Error("expected an error for a kind of job there is no work for")
+	}
+}
@@ -0,0 +1,93 @@
+// Package mail sends the app's email: a Message, made from one of the
+// templates in templates/, goes through a Sender, which is an SMTP
+// server in production and a Mailbox, keeping it, in development.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.txt
+var templates embed.FS
+
+// Message is an email, in plain text.
+type Message struct {
+	From    string   // such as "Demo <noreply@example.com>"
+	To      []string // the same
+	ReplyTo string   // where replies go, when not to From
+	Subject string
+	Text    string
+}
+
+// Sender sends a Message on, or fails to.
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// New is the message templates/NAME.txt makes of data: the subject its
+// "subject" block gives, and the text the rest of it does. From and To
+// are for the caller to fill in.
+func New(name string, data any) (Message, error) {
+	t, err := template.New(name+".txt").Option("missingkey=error").ParseFS(templates, "templates/"+name+".txt")
+	if err != nil {
+		return Message{}, fmt.Errorf("mail: %w", err)
+	}
+	var subject, text strings.Builder
+	if err := t.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("mail: %s: %w", name, err)
+	}
+	if err := t.Execute(&text, data); err != nil {
+		return Message{}, fmt.Errorf("mail: %s: %w", name, err)
+	}
+	return Message{Subject: strings.TrimSpace(subject.String()), Text: text.String()}, nil
+}
+
+// format is m as it goes over the wire, sent at date: the headers, with
+// each address checked and the subject encoded, and then the text,
+// quoted-printable.
+func (m Message) format(date time.Time) ([]byte, error) {
+	from, err := netmail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("mail: From: %w", err)
+	}
+	if len(m.To) == 0 {
+		return nil, fmt.Errorf("mail: To: no one to send %q to", m.Subject)
+	}
+	to := make([]string, len(m.To))
+	for i, addr := range m.To {
+		a, err := netmail.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("mail: To: %w", err)
+		}
+		to[i] = a.String()
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	if m.ReplyTo != "" {
+		replyTo, err := netmail.ParseAddress(m.ReplyTo)
+		if err != nil {
+			return nil, fmt.Errorf("mail: Reply-To: %w", err)
+		}
+		fmt.Fprintf(&b, "Reply-To: %s\r\n", replyTo)
+	}
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	w := quotedprintable.NewWriter(&b)
+	w.Write([]byte(strings.ReplaceAll(m.Text, "\n", "\r\n")))
+	w.Close()
+	return b.Bytes(), nil
+}
@@ -0,0 +1,168 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var contact = struct{ Name, Email, Message string }{"Ada", "ada@example.com", "Hello there!"}
+
+func TestNew(t *testing.T) {
+	m, err := New("contact", contact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Subject != "A message from Ada" {
+		t.Errorf("expected the subject from its block, got %q", m.Subject)
+	}
+	if !strings.HasPrefix(m.Text, "Ada <ada@example.com> wrote, through the contact form:\n\nHello there!\n") {
+		t.Errorf("expected the text from the rest, got %q", m.Text)
+	}
+
+	if _, err := New("contact", map[string]string{"Name": "Ada"}); err == nil {
+		t.Error("expected an error for data missing what the template uses")
+	}
+	if _, err := New("no such mail", contact); err == nil {
+		t.Error("expected an error for a template that is not there")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	m := Message{
+		From:    "Demo <noreply@example.com>",
+		To:      []string{"Åse <ase@example.com>", "bob@example.com"},
+		ReplyTo: "ada@example.com",
+		Subject: "Hei, Åse\r\nBcc: eve@example.com",
+		Text:    "Line one\nLine two, with a non-ASCII ø",
+	}
+	b, err := m.format(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		"From: \"Demo\" <noreply@example.com>\r\n",
+		"To: =?utf-8?q?=C3=85se?= <ase@example.com>, <bob@example.com>\r\n",
+		"Reply-To: <ada@example.com>\r\n",
+		"Subject: =?utf-8?q?",
+		"Date: Wed, 01 May 2024 12:00:00 +0000\r\n",
+		"\r\n\r\nLine one\r\nLine two, with a non-ASCII =C3=B8",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in the message, got %q", want, got)
+		}
+	}
+	if strings.Contains(got, "\r\nBcc:") {
+		t.Errorf("expected the subject kept to its header, got %q", got)
+	}
+
+	for _, bad := range []Message{
+		{From: "not an address", To: []string{"bob@example.com"}},
+		{From: "noreply@example.com"},
+		{From: "noreply@example.com", To: []string{"bob"}},
+	} {
+		if _, err := bad.format(time.Now()); err == nil {
+			t.Errorf("expected %+v refused", bad)
+		}
+	}
+}
+
+func TestMailbox(t *testing.T) {
+	b := NewMailbox(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for _, subject := range []string{"first", "second"} {
+		if err := b.Send(context.Background(), Message{From: "noreply@example.com", To: []string{"ada@example.com"}, Subject: subject, Text: "Hi"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Send(context.Background(), Message{From: "noreply@example.com", Subject: "to no one"}); err == nil {
+		t.Error("expected a message SMTP would refuse refused")
+	}
+	if got := b.Messages(); len(got) != 2 || got[1].Subject != "second" {
+		t.Errorf("expected the two messages, oldest first, got %+v", got)
+	}
+
+	w := httptest.NewRecorder()
+	b.ServeHTTP(w, httptest.NewRequest("GET", "/dev/mail", nil))
+	if page := w.Body.String(); strings.Index(page, "Subject: second") > strings.Index(page, "Subject: first") {
+		t.Errorf("expected the messages, newest first, got %q", page)
+	}
+}
+
+// fakeSMTP is an SMTP server that takes one message and hands on what
+// it was told: the envelope's lines, then the message.
+func fakeSMTP(t *testing.T) (addr string, got <-chan []string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	lines := make(chan []string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r, seen := bufio.NewReader(conn), []string{}
+		io.WriteString(conn, "220 fake ESMTP\r\n")
+		for data := false; ; {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch {
+			case data && line == ".":
+				data = false
+				io.WriteString(conn, "250 taken\r\n")
+			case data:
+				seen = append(seen, line)
+			case strings.HasPrefix(line, "EHLO"):
+				io.WriteString(conn, "250 fake\r\n")
+			case line == "DATA":
+				data = true
+				io.WriteString(conn, "354 go on\r\n")
+			case line == "QUIT":
+				io.WriteString(conn, "221 bye\r\n")
+				lines <- seen
+				return
+			default:
+				seen = append(seen, line)
+				io.WriteString(conn, "250 ok\r\n")
+			}
+		}
+	}()
+	return l.Addr().String(), lines
+}
+
+func TestSMTP(t *testing.T) {
+	addr, got := fakeSMTP(t)
+	m, err := New("contact", contact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.From, m.To = "Demo <noreply@example.com>", []string{"owner@example.com"}
+	if err := (&SMTP{Addr: addr}).Send(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+
+	conversation := strings.Join(<-got, "\n")
+	for _, want := range []string{
+		"MAIL FROM:<noreply@example.com>",
+		"RCPT TO:<owner@example.com>",
+		"Subject: A message from Ada",
+		"Hello there!",
+	} {
+		if !strings.Contains(conversation, want) {
+			t.Errorf("expected %q told to the server, got:\n%s", want, conversation)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keep is how many messages a Mailbox holds on to, the latest.
+const keep = 100
+
+// Mailbox is a Sender for development and tests: it keeps the messages
+// sent, and logs them, rather than sending them anywhere.
+type Mailbox struct {
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMailbox(logger *slog.Logger) *Mailbox {
+	return &Mailbox{logger: logger}
+}
+
+// Send checks m as SMTP would, then keeps it.
+func (b *Mailbox) Send(ctx context.Context, m Message) error {
+	if _, err := m.format(time.Now()); err != nil {
+		return err
+	}
+	b.logger.InfoContext(ctx, "mail kept, not sent", "to", m.To, "subject", m.Subject)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.messages = append(b.messages, m)
+	if len(b.messages) > keep {
+		b.messages = slices.Delete(b.messages, 0, len(b.messages)-keep)
+	}
+	return nil
+}
+
+// Messages is what was sent, oldest first.
+func (b *Mailbox) Messages() []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.messages)
+}
+
+// ServeHTTP shows the messages kept, newest first, in plain text.
+func (b *Mailbox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	messages := b.Messages()
+	if len(messages) == 0 {
+		fmt.Fprintln(w, "No mail yet.")
+		return
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		m := messages[i]
+		fmt.Fprintf(w, "From: %s\nTo: %s\n", m.From, strings.Join(m.To, ", "))
+		if m.ReplyTo != "" {
+			fmt.Fprintf(w, "Reply-To: %s\n", m.ReplyTo)
+		}
+		fmt.Fprintf(w, "Subject: %s\n\n%s\n\n", m.Subject, m.Text)
+	}
+}
@@ -0,0 +1,73 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTP sends mail through the server at Addr (host:port): over TLS
+// once the server offers STARTTLS, and logging in when Username is set.
+type SMTP struct {
+	Addr     string
+	Username string
+	Password string
+}
+
+func (s *SMTP) Send(ctx context.Context, m Message) error {
+	msg, err := m.format(time.Now())
+	if err != nil {
+		return err
+	}
+	from, _ := netmail.ParseAddress(m.From) // format has checked them
+	host, _, _ := net.SplitHostPort(s.Addr)
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	// The conversation ends with ctx, wherever it has got to.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mail: %w", err)
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("mail: starttls: %w", err)
+		}
+	}
+	if s.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, host)); err != nil {
+			return fmt.Errorf("mail: auth: %w", err)
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("mail: from %s: %w", from.Address, err)
+	}
+	for _, addr := range m.To {
+		to, _ := netmail.ParseAddress(addr)
+		if err := c.Rcpt(to.Address); err != nil {
+			return fmt.Errorf("mail: to %s: %w", to.Address, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+
+	"{{MODULE_PATH}}/internal/form"
+	"{{MODULE_PATH}}/internal/mail"
+)
+
+// mailContact sends each message from the contact form to to, from
+// from, with replies going to whoever wrote it.
+func mailContact(sender mail.Sender, from, to string) func(context.Context, *form.Form) error {
+	return func(ctx context.Context, f *form.Form) error {
+		m, err := mail.New("contact", struct{ Name, Email, Message string }{f.Get("name"), f.Get("email"), f.Get("message")})
+		if err != nil {
+			return err
+		}
+		m.From, m.To, m.ReplyTo = from, []string{to}, f.Get("email")
+		return sender.Send(ctx, m)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/mail"
+)
+
+func TestContactMailed(t *testing.T) {
+	mailbox := mail.NewMailbox(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	deliver := mailContact(mailbox, "Demo <noreply@example.com>", "owner@example.com")
+	w := postContactTo(deliver, url.Values{"name": {"Ada"}, "email": {"ada@example.com"}, "message": {"Hello!"}})
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("expected a redirect to the form, got %d: %s", w.Code, w.Body)
+	}
+
+	sent := mailbox.Messages()
+	if len(sent) != 1 {
+		t.Fatalf("expected one message, got %+v", sent)
+	}
+	m := sent[0]
+	if m.To[0] != "owner@example.com" || m.ReplyTo != "ada@example.com" || m.Subject != "A message from Ada" || !strings.Contains(m.Text, "Hello!") {
+		t.Errorf("expected the message from Ada to the owner, got %+v", m)
+	}
+}
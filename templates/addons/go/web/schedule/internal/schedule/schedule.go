@@ -0,0 +1,122 @@
+// Package schedule runs tasks in the background at fixed intervals,
+// off a Clock that tests can stand in for.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// Clock is the time the scheduler keeps: System's, or a test's.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// System is the clock on the wall.
+var System Clock = systemClock{}
+
+// task is one of the Scheduler's, and whether a run of it is at work.
+type task struct {
+	name    string
+	every   time.Duration
+	run     func(context.Context) error
+	running atomic.Bool
+}
+
+// Scheduler runs its tasks, each every so often, from Start to Stop.
+type Scheduler struct {
+	clock  Clock
+	logger *slog.Logger
+	tasks  []*task
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(clock Clock, logger *slog.Logger) *Scheduler {
+	return &Scheduler{clock: clock, logger: logger}
+}
+
+// Every has run called every d from Start, with a context that ends at
+// Stop; d of 0 leaves the task off. A run still at work when the next
+// is due has that one skipped, not run alongside it, and an error is
+// logged.
+func (s *Scheduler) Every(name string, d time.Duration, run func(context.Context) error) {
+	if d <= 0 {
+		s.logger.Info("task off", "task", name)
+		return
+	}
+	s.tasks = append(s.tasks, &task{name: name, every: d, run: run})
+}
+
+// Start sets the tasks going, each first due one interval from now.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, t := range s.tasks {
+		s.wg.Add(1)
+		go s.keep(ctx, t)
+	}
+}
+
+// Stop ends the tasks' context and waits for the runs at work to
+// return.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// keep runs t each time it is due, until ctx ends.
+func (s *Scheduler) keep(ctx context.Context, t *task) {
+	defer s.wg.Done()
+	logger := s.logger.With("task", t.name)
+	next := s.clock.Now().Add(t.every)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(next.Sub(s.clock.Now())):
+		}
+		// Due times stay on the interval, and those the clock jumped
+		// past are not made up for.
+		for now := s.clock.Now(); !next.After(now); {
+			next = next.Add(t.every)
+		}
+		if !t.running.CompareAndSwap(false, true) {
+			logger.Warn("task skipped: the last run is still at work")
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer t.running.Store(false)
+			if err := s.do(logging.NewContext(ctx, logger), t); err != nil {
+				logger.Error("task failed", "err", err)
+			}
+		}()
+	}
+}
+
+// do runs t once, a panic coming back as its error.
+func (s *Scheduler) do(ctx context.Context, t *task) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return t.run(ctx)
+}
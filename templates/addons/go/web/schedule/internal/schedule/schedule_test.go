@@ -0,0 +1,178 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock moves only when a test advances it, and says on waiting
+// each time the scheduler starts waiting on it, so the test knows the
+// scheduler is ready for the next tick before giving it one.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []fakeTimer
+	waiting chan struct{}
+}
+
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), waiting: make(chan struct{}, 100)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := fakeTimer{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	c.waiting <- struct{}{}
+	return t.c
+}
+
+// wait returns once the scheduler is waiting on the clock again.
+func (c *fakeClock) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.waiting:
+	case <-time.After(time.Second):
+		t.Fatal("the scheduler is not waiting on the clock")
+	}
+}
+
+// advance moves the clock on by d, firing the timers that come due.
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	timers := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			timers = append(timers, timer)
+		} else {
+			timer.c <- c.now
+		}
+	}
+	c.timers = timers
+}
+
+// recv is the next value from c, failing the test when none comes.
+func recv[T any](t *testing.T, c <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-c:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("expected the task to run")
+		var zero T
+		return zero
+	}
+}
+
+func TestEvery(t *testing.T) {
+	clock := newFakeClock()
+	ran := make(chan time.Time, 10)
+	s := New(clock, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.Every("tick", time.Minute, func(context.Context) error {
+		ran <- clock.Now()
+		return nil
+	})
+	s.Start()
+	defer s.Stop()
+
+	start := clock.Now()
+	clock.wait(t)
+	clock.advance(30 * time.Second)
+	clock.advance(30 * time.Second)
+	if at := recv(t, ran); !at.Equal(start.Add(time.Minute)) {
+		t.Errorf("expected a run a minute in, got one at %v", at.Sub(start))
+	}
+	// The clock jumping ahead runs the task once, not once for each
+	// minute it passed.
+	clock.wait(t)
+	clock.advance(5 * time.Minute)
+	recv(t, ran)
+	clock.wait(t)
+	clock.advance(time.Minute)
+	if at := recv(t, ran); !at.Equal(start.Add(7 * time.Minute)) {
+		t.Errorf("expected the next run on the minute, got one at %v", at.Sub(start))
+	}
+	select {
+	case at := <-ran:
+		t.Errorf("expected no more runs, got one at %v", at.Sub(start))
+	default:
+	}
+}
+
+func TestEverySkipsARunWhileTheLastIsAtWork(t *testing.T) {
+	var logs bytes.Buffer
+	clock := newFakeClock()
+	started, gate := make(chan struct{}, 10), make(chan struct{})
+	s := New(clock, slog.New(slog.NewTextHandler(&logs, nil)))
+	s.Every("slow", time.Minute, func(context.Context) error {
+		started <- struct{}{}
+		<-gate
+		return errors.New("gave up")
+	})
+	s.Start()
+
+	clock.wait(t)
+	clock.advance(time.Minute)
+	recv(t, started)
+	clock.wait(t)
+	clock.advance(time.Minute) // due again, while the first is at work
+	clock.wait(t)              // and skipped
+	select {
+	case <-started:
+		t.Error("expected the second run skipped")
+	default:
+	}
+	close(gate)
+	s.Stop()
+	if !strings.Contains(logs.String(), "task skipped") || !strings.Contains(logs.String(), `task=slow err="gave up"`) {
+		t.Errorf("expected the skip and the error logged, got %q", logs.String())
+	}
+}
+
+func TestStopWaitsForTheRunsAtWork(t *testing.T) {
+	clock := newFakeClock()
+	started, finished := make(chan struct{}, 1), make(chan struct{})
+	s := New(clock, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.Every("long", time.Minute, func(ctx context.Context) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		close(finished)
+		return ctx.Err()
+	})
+	s.Every("off", 0, func(context.Context) error {
+		t.Error("expected a task of interval 0 never to run")
+		return nil
+	})
+	s.Start()
+	clock.wait(t)
+	clock.advance(time.Minute)
+	recv(t, started)
+
+	s.Stop()
+	select {
+	case <-finished:
+	default:
+		t.Error("expected Stop to wait for the run to finish")
+	}
+}
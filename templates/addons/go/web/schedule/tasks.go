@@ -0,0 +1,16 @@
+package main
+
+import (
+	"context"
+	"runtime"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// logStats is the example task: how the process is doing, in the log.
+func logStats(ctx context.Context) error {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	logging.FromContext(ctx).Info("stats", "goroutines", runtime.NumGoroutine(), "heap_bytes", mem.HeapAlloc)
+	return nil
+}
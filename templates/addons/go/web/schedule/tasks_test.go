@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func TestLogStats(t *testing.T) {
+	var logs bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(&logs, nil)))
+	if err := logStats(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), "msg=stats goroutines=") {
+		t.Errorf("expected the stats logged, got %q", logs.String())
+	}
+}
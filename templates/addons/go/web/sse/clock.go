@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// clock streams the server's time to each client as Server-Sent
+// Events, one "tick" every interval, until the client goes away or the
+// server shuts down.
+type clock struct {
+	interval time.Duration
+	done     chan struct{}
+	stop     sync.Once
+}
+
+func newClock(interval time.Duration) *clock {
+	return &clock{interval: interval, done: make(chan struct{})}
+}
+
+// Close ends the streams. The server's shutdown waits for requests to
+// finish, and these never would, so main calls it as shutdown begins.
+func (c *clock) Close() {
+	c.stop.Do(func() { close(c.done) })
+}
+
+func (c *clock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx would hold the events back
+	// How long the browser waits before connecting again.
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		// The server's WriteTimeout would cut the stream off; each event
+		// pushes the deadline on instead.
+		rc.SetWriteDeadline(time.Now().Add(c.interval + 10*time.Second))
+		fmt.Fprintf(w, "event: tick\ndata: %s\n\n", time.Now().Format(time.TimeOnly))
+		if err := rc.Flush(); err != nil {
+			logging.FromContext(r.Context()).Error("events", "err", err)
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done(): // the client went away
+			return
+		case <-c.done:
+			return
+		}
+	}
+}
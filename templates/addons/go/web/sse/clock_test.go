@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClockStreamsEvents(t *testing.T) {
+	clock := newClock(10 * time.Millisecond)
+	srv := httptest.NewServer(clock)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %q", ct)
+	}
+	lines := bufio.NewScanner(resp.Body)
+	var events []string
+	for len(events) < 2 && lines.Scan() {
+		if data, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	for _, data := range events {
+		if _, err := time.Parse(time.TimeOnly, data); err != nil {
+			t.Errorf("expected the time in each event, got %q", data)
+		}
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected two events, got %v (%v)", events, lines.Err())
+	}
+
+	clock.Close()
+	for lines.Scan() {
+	}
+	if err := lines.Err(); err != nil {
+		t.Errorf("expected the stream to end on Close, got %v", err)
+	}
+}
+
+func TestClockStopsWhenTheClientGoes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan struct{})
+	go func() {
+		newClock(time.Hour).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/events", nil).WithContext(ctx))
+		close(served)
+	}()
+	cancel()
+	select {
+	case <-served:
+	case <-time.After(time.Second):
+		t.Error("expected the stream to end when the client went away")
+	}
+}
@@ -0,0 +1,9 @@
+// Shows the server's time from the tick events on /events. EventSource
+// connects again by itself when the stream drops.
+(() => {
+  const clock = document.getElementById("clock");
+  const events = new EventSource("/events");
+  events.addEventListener("tick", (event) => {
+    clock.textContent = event.data;
+  });
+})();
@@ -0,0 +1,64 @@
+// Package blob keeps uploaded files. The handlers need only a Store,
+// so the files can move from a directory (Dir) to object storage such
+// as S3 without them changing.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store keeps files by key.
+type Store interface {
+	// Put writes what r holds under key, and is how many bytes that
+	// was. When it fails, nothing is left under key.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+}
+
+// Dir is a Store in a directory on disk, made when the first file
+// comes.
+type Dir string
+
+// Put writes r to a temporary file that becomes key once it is whole,
+// so a file cut off halfway is never seen under its name.
+func (d Dir) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	if key == "" || key != filepath.Base(key) || strings.HasPrefix(key, ".") {
+		return 0, fmt.Errorf("blob: %q is not a file name", key)
+	}
+	if err := os.MkdirAll(string(d), 0o755); err != nil {
+		return 0, err
+	}
+	f, err := os.CreateTemp(string(d), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, contextReader{ctx, r})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(string(d), key))
+	}
+	if err != nil {
+		return 0, errors.Join(err, os.Remove(f.Name()))
+	}
+	return n, nil
+}
+
+// contextReader stops reading once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,51 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirPut(t *testing.T) {
+	dir := Dir(filepath.Join(t.TempDir(), "uploads"))
+	n, err := dir.Put(context.Background(), "a.txt", strings.NewReader("hello"))
+	if err != nil || n != 5 {
+		t.Fatalf("expected 5 bytes put, got %d, %v", n, err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(string(dir), "a.txt")); string(got) != "hello" {
+		t.Errorf("expected the file written, got %q", got)
+	}
+}
+
+func TestDirPutLeavesNothingWhenItFails(t *testing.T) {
+	dir := Dir(t.TempDir())
+	broken := io.MultiReader(strings.NewReader("half"), errReader{})
+	if _, err := dir.Put(context.Background(), "a.txt", broken); err == nil {
+		t.Fatal("expected the read error")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := dir.Put(ctx, "b.txt", strings.NewReader("hello")); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled put to fail, got %v", err)
+	}
+	if entries, _ := os.ReadDir(string(dir)); len(entries) != 0 {
+		t.Errorf("expected nothing left, got %v", entries)
+	}
+}
+
+func TestDirPutRefusesPaths(t *testing.T) {
+	dir := Dir(t.TempDir())
+	for _, key := range []string{"", "../a.txt", "sub/a.txt", ".hidden"} {
+		if _, err := dir.Put(context.Background(), key, strings.NewReader("x")); err == nil {
+			t.Errorf("expected %q refused", key)
+		}
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
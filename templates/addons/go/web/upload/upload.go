@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"{{MODULE_PATH}}/components"
+	"{{MODULE_PATH}}/internal/blob"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// uploadTypes are the files the upload takes, by the type their first
+// bytes show (not the type the browser claims), with the extension each
+// is kept under.
+var uploadTypes = map[string]string{
+	"image/png":                 ".png",
+	"image/jpeg":                ".jpg",
+	"image/gif":                 ".gif",
+	"image/webp":                ".webp",
+	"application/pdf":           ".pdf",
+	"text/plain; charset=utf-8": ".txt",
+}
+
+// uploadTime is how long an upload may take to arrive, in place of the
+// server's ReadTimeout, which is meant for requests of a few kilobytes.
+const uploadTime = 10 * time.Minute
+
+func handleUploadForm(limit int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		components.Page("Upload", components.UploadForm(limit)).Render(r.Context(), w)
+	}
+}
+
+// handleUpload streams each file in a multipart/form-data upload into
+// store as it arrives, without holding it in memory or a temporary
+// file first, and answers with a list item for each. A body over limit
+// bytes gets a 413, and a file of a type not in uploadTypes a 415; the
+// files before it are kept.
+func handleUpload(store blob.Store, limit int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > limit {
+			http.Error(w, "That is more than the upload takes.", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		http.NewResponseController(w).SetReadDeadline(time.Now().Add(uploadTime))
+		parts, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, "Expected a multipart/form-data upload.", http.StatusBadRequest)
+			return
+		}
+
+		var files []components.UploadedFile
+		for {
+			part, err := parts.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				uploadFailed(w, r, err)
+				return
+			}
+			if part.FormName() != "file" || part.FileName() == "" {
+				continue
+			}
+			// The type is told from the first bytes, which go back in
+			// front of the rest.
+			head := make([]byte, 512)
+			n, err := io.ReadFull(part, head)
+			if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+				uploadFailed(w, r, err)
+				return
+			}
+			kind := http.DetectContentType(head[:n])
+			ext, ok := uploadTypes[kind]
+			if !ok {
+				http.Error(w, filepath.Base(part.FileName())+": files of type "+kind+" are not taken.", http.StatusUnsupportedMediaType)
+				return
+			}
+			key := newKey() + ext
+			size, err := store.Put(r.Context(), key, io.MultiReader(bytes.NewReader(head[:n]), part))
+			if err != nil {
+				uploadFailed(w, r, err)
+				return
+			}
+			logging.FromContext(r.Context()).Info("upload", "name", part.FileName(), "key", key, "type", kind, "bytes", size)
+			files = append(files, components.UploadedFile{Name: filepath.Base(part.FileName()), Type: kind, Size: size})
+		}
+		if len(files) == 0 {
+			http.Error(w, "Choose a file to upload.", http.StatusBadRequest)
+			return
+		}
+		components.Uploaded(files).Render(r.Context(), w)
+	}
+}
+
+// uploadFailed answers an upload that could not be read or kept: a 413
+// when it ran past the limit, else a 500, logged.
+func uploadFailed(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "That is more than the upload takes.", http.StatusRequestEntityTooLarge)
+		return
+	}
+	logging.FromContext(r.Context()).Error("upload", "err", err)
+	http.Error(w, "The upload failed; try again.", http.StatusInternalServerError)
+}
+
+// newKey is a random name to keep a file under, so what users call
+// their files never decides where they land.
+func newKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/blob"
+)
+
+var png = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 100)...)
+
+// multipartBody is an upload of files, by name, as a browser sends it.
+func multipartBody(t *testing.T, files map[string][]byte) (io.Reader, string) {
+	t.Helper()
+	var body bytes.Buffer
+	m := multipart.NewWriter(&body)
+	m.WriteField("note", "not a file")
+	for name, data := range files {
+		w, err := m.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	m.Close()
+	return &body, m.FormDataContentType()
+}
+
+func TestUpload(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string][]byte
+		status int
+		want   string // in the answer
+		kept   int    // files in the store after
+	}{
+		{"png", map[string][]byte{"cat.png": png}, http.StatusOK, "cat.png", 1},
+		{"text", map[string][]byte{"notes.txt": []byte("hello")}, http.StatusOK, "notes.txt", 1},
+		{"executable", map[string][]byte{"cat.png": []byte("MZ\x90\x00\x03\x00\x00\x00\x04")}, http.StatusUnsupportedMediaType, "not taken", 0},
+		{"too large", map[string][]byte{"big.png": append(png, make([]byte, 2048)...)}, http.StatusRequestEntityTooLarge, "more than", 0},
+		{"no file", nil, http.StatusBadRequest, "Choose a file", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			body, contentType := multipartBody(t, tt.files)
+			req := httptest.NewRequest("POST", "/upload", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			handleUpload(blob.Dir(dir), 1024)(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("expected %q in the answer, got %q", tt.want, w.Body)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != tt.kept {
+				t.Errorf("expected %d files kept, got %v", tt.kept, entries)
+			}
+		})
+	}
+}
+
+// Without a Content-Length to turn it away at once, an upload is cut
+// off as it passes the limit.
+func TestUploadStreamedPastTheLimit(t *testing.T) {
+	dir := t.TempDir()
+	body, contentType := multipartBody(t, map[string][]byte{"big.png": append(png, make([]byte, 4096)...)})
+	req := httptest.NewRequest("POST", "/upload", io.NopCloser(body))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	handleUpload(blob.Dir(dir), 1024)(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d: %s", w.Code, w.Body)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected nothing kept, got %v", entries)
+	}
+}
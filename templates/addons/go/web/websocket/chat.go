@@ -0,0 +1,11 @@
+package main
+
+import "{{MODULE_PATH}}/internal/chat"
+
+// newChat starts the chat's hub; main serves it on /ws/chat and shuts
+// it down with the server.
+func newChat() *chat.Hub {
+	hub := chat.NewHub()
+	go hub.Run()
+	return hub
+}
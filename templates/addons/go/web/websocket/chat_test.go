@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"{{MODULE_PATH}}/internal/middleware"
+)
+
+// The chat works behind main's middleware, which must let the upgrade
+// take the connection over.
+func TestChatThroughTheMiddleware(t *testing.T) {
+	hub := newChat()
+	defer hub.Close()
+	logger := slog.New(slog.NewTextHandler(new(bytes.Buffer), nil))
+	srv := httptest.NewServer(middleware.Chain(hub, middleware.RequestID, middleware.Logger(logger), middleware.Recover))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.CloseNow()
+	conn.Write(ctx, websocket.MessageText, []byte("ping"))
+	if _, msg, err := conn.Read(ctx); err != nil || string(msg) != "ping" {
+		t.Errorf("expected the message back, got %q, %v", msg, err)
+	}
+}
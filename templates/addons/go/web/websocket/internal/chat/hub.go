@@ -0,0 +1,187 @@
+// Package chat is a WebSocket chat: a hub that passes each message a
+// client sends to every client connected.
+package chat
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// MaxMessage is the most a client may send at once, in bytes; a longer
+// message closes its connection.
+const MaxMessage = 1024
+
+// Hub keeps the connected clients in its Run goroutine, which alone
+// touches them.
+type Hub struct {
+	join, leave chan *client
+	broadcast   chan []byte
+	done        chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+	conns  sync.WaitGroup // the connections ServeHTTP is serving
+}
+
+// client is a connection's queue of messages to write; Run closes it
+// when the client is to go.
+type client struct {
+	send chan []byte
+}
+
+// NewHub makes a hub for Run to serve.
+func NewHub() *Hub {
+	return &Hub{
+		join:      make(chan *client),
+		leave:     make(chan *client),
+		broadcast: make(chan []byte),
+		done:      make(chan struct{}),
+	}
+}
+
+// Run passes messages on until Close. A client too slow to take them
+// off its queue is dropped rather than holding the others up.
+func (h *Hub) Run() {
+	clients := map[*client]bool{}
+	drop := func(c *client) {
+		if clients[c] {
+			delete(clients, c)
+			close(c.send)
+		}
+	}
+	for {
+		select {
+		case c := <-h.join:
+			clients[c] = true
+		case c := <-h.leave:
+			drop(c)
+		case msg := <-h.broadcast:
+			for c := range clients {
+				select {
+				case c.send <- msg:
+				default:
+					drop(c)
+				}
+			}
+		case <-h.done:
+			for c := range clients {
+				drop(c)
+			}
+			return
+		}
+	}
+}
+
+// Close tells every client the server is going away and stops Run.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.closed {
+		h.closed = true
+		close(h.done)
+	}
+}
+
+// Shutdown closes the hub and waits for its clients to be told, or for
+// ctx to end. The http.Server does not wait for WebSockets, so main
+// calls it as the server shuts down.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.Close()
+	idle := make(chan struct{})
+	go func() {
+		h.conns.Wait()
+		close(idle)
+	}()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// track counts a connection for Shutdown to wait on, unless the hub is
+// closed.
+func (h *Hub) track() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.conns.Add(1)
+	return true
+}
+
+// ServeHTTP upgrades the request to a WebSocket and joins it to the
+// chat. Pages from other origins are turned away, as websocket.Accept
+// does unless told otherwise.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.track() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer h.conns.Done()
+	c := &client{send: make(chan []byte, 16)}
+	// Joined before the upgrade, so the client hears every message sent
+	// once it is connected.
+	select {
+	case h.join <- c:
+	case <-h.done:
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		h.leaving(c) // Accept has answered the request
+		return
+	}
+	conn.SetReadLimit(MaxMessage)
+	go h.read(r.Context(), conn, c)
+
+	for msg := range c.send {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		err := conn.Write(ctx, websocket.MessageText, msg)
+		cancel()
+		if err != nil {
+			h.leaving(c)
+			break
+		}
+	}
+	select {
+	case <-h.done:
+		conn.Close(websocket.StatusGoingAway, "server shutting down")
+	default:
+		conn.Close(websocket.StatusNormalClosure, "")
+	}
+}
+
+// read broadcasts what the client sends until it stops.
+func (h *Hub) read(ctx context.Context, conn *websocket.Conn, c *client) {
+	defer h.leaving(c)
+	for {
+		typ, msg, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		if typ != websocket.MessageText {
+			continue
+		}
+		select {
+		case h.broadcast <- msg:
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// leaving tells Run c is gone, unless Run has stopped.
+func (h *Hub) leaving(c *client) {
+	select {
+	case h.leave <- c:
+	case <-h.done:
+	}
+}
@@ -0,0 +1,75 @@
+package chat
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func dial(t *testing.T, ctx context.Context, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(url, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+	return conn
+}
+
+func TestHubBroadcastsAndClosesOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	hub := NewHub()
+	go hub.Run()
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	alice, bob := dial(t, ctx, srv.URL), dial(t, ctx, srv.URL)
+	if err := alice.Write(ctx, websocket.MessageText, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	for name, conn := range map[string]*websocket.Conn{"alice": alice, "bob": bob} {
+		_, msg, err := conn.Read(ctx)
+		if err != nil || string(msg) != "hello" {
+			t.Errorf("%s: expected hello, got %q, %v", name, msg, err)
+		}
+	}
+
+	// Shutdown waits on the close handshake, which needs the clients
+	// reading.
+	closed := make(chan error, 2)
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		go func(conn *websocket.Conn) {
+			_, _, err := conn.Read(ctx)
+			closed <- err
+		}(conn)
+	}
+	if err := hub.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-closed; websocket.CloseStatus(err) != websocket.StatusGoingAway {
+			t.Errorf("expected going away on shutdown, got %v", err)
+		}
+	}
+}
+
+func TestHubClosesOnLongMessages(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Close()
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	conn := dial(t, ctx, srv.URL)
+	conn.Write(ctx, websocket.MessageText, []byte(strings.Repeat("x", MaxMessage+1)))
+	if _, _, err := conn.Read(ctx); websocket.CloseStatus(err) != websocket.StatusMessageTooBig {
+		t.Errorf("expected message too big, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+// The chat on the home page: the form's messages go to /ws/chat, which
+// passes them to everyone connected, and what comes back is listed. A
+// dropped connection is tried again a few seconds later.
+(() => {
+  const list = document.getElementById("chat-messages");
+  const form = document.getElementById("chat-form");
+  let socket;
+
+  function connect() {
+    const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+    socket = new WebSocket(`${scheme}//${location.host}/ws/chat`);
+    socket.addEventListener("message", (event) => {
+      const item = document.createElement("li");
+      item.textContent = event.data;
+      list.append(item);
+    });
+    socket.addEventListener("close", () => setTimeout(connect, 3000));
+  }
+
+  form.addEventListener("submit", (event) => {
+    event.preventDefault();
+    if (socket.readyState === WebSocket.OPEN) {
+      socket.send(form.elements.message.value);
+      form.reset();
+    }
+  });
+  connect();
+})();
@@ -0,0 +1,28 @@
+import { afterAll, describe, expect, it } from "bun:test";
+import { serve } from "./index";
+
+const server = serve(0);
+afterAll(() => server.stop(true));
+
+function get(path: string): Promise<Response> {
+	return fetch(`http://localhost:${server.port}${path}`);
+}
+
+describe("the server", () => {
+	it("answers /health", async () => {
+		const res = await get("/health");
+		expect(res.status).toBe(200);
+		expect(await res.json()).toEqual({ status: "ok" });
+	});
+
+	it("greets the name it is given", async () => {
+		const res = await get("/api/greet?name=Ada");
+		expect(res.status).toBe(200);
+		expect(await res.json()).toEqual({ message: "Hello, Ada!" });
+	});
+
+	it("answers 404 for an unknown path", async () => {
+		const res = await get("/no/such/page");
+		expect(res.status).toBe(404);
+	});
+});
@@ -0,0 +1,27 @@
+import { greet } from "./greet";
+
+/** serve starts the server on port, or on a free one for 0. */
+export function serve(port: number) {
+	return Bun.serve({
+		port,
+		hostname: "0.0.0.0",
+		routes: {
+			"/health": Response.json({ status: "ok" }),
+			"/api/greet": {
+				GET: (req) => {
+					const name = new URL(req.url).searchParams.get("name");
+					return Response.json({ message: greet(name) });
+				},
+			},
+		},
+		// What no route matches.
+		fetch() {
+			return Response.json({ error: "not found" }, { status: 404 });
+		},
+	});
+}
+
+if (import.meta.main) {
+	const server = serve(Number(process.env.PORT ?? 3000));
+	console.log(`Listening on ${server.url}`);
+}
@@ -0,0 +1,22 @@
+#include "greet.h"
+
+#include <ctype.h>
+#include <stdio.h>
+#include <string.h>
+
+int greet(char *buf, size_t size, const char *name)
+{
+    const char *end;
+
+    if (name == NULL)
+        name = "";
+    while (isspace((unsigned char)*name))
+        name++;
+    end = name + strlen(name);
+    while (end > name && isspace((unsigned char)end[-1]))
+        end--;
+
+    if (end == name)
+        return snprintf(buf, size, "Hello, World!");
+    return snprintf(buf, size, "Hello, %.*s!", (int)(end - name), name);
+}
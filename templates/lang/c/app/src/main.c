@@ -0,0 +1,20 @@
+#include <stdio.h>
+#include <stdlib.h>
+
+#include "greet.h"
+
+int main(int argc, char **argv)
+{
+    const char *name = argc > 1 ? argv[1] : NULL;
+    int n = greet(NULL, 0, name);
+    char *buf;
+
+    if (n < 0 || (buf = malloc((size_t)n + 1)) == NULL) {
+        perror(argv[0]);
+        return EXIT_FAILURE;
+    }
+    greet(buf, (size_t)n + 1, name);
+    puts(buf);
+    free(buf);
+    return EXIT_SUCCESS;
+}
@@ -0,0 +1,22 @@
+(ns build
+  "clojure -T:build uber: an uberjar in target/ that runs with java -jar."
+  (:require [clojure.tools.build.api :as b]))
+
+(def lib '{{PROJECT_NAME | kebab}}/{{PROJECT_NAME | kebab}})
+(def version "0.1.0")
+(def main '{{PROJECT_NAME | kebab}}.core)
+(def class-dir "target/classes")
+(def uber-file (format "target/%s-%s-standalone.jar" (name lib) version))
+
+;; Delayed, so that clean does not resolve the dependencies.
+(def basis (delay (b/create-basis {:project "deps.edn"})))
+
+(defn clean [_]
+  (b/delete {:path "target"}))
+
+(defn uber [_]
+  (clean nil)
+  (b/copy-dir {:src-dirs ["src"] :target-dir class-dir})
+  (b/compile-clj {:basis @basis :ns-compile [main] :class-dir class-dir})
+  (b/uber {:class-dir class-dir :uber-file uber-file :basis @basis :main main})
+  (println "Wrote" uber-file))
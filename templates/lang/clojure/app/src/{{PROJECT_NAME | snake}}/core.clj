@@ -0,0 +1,14 @@
+(ns {{PROJECT_NAME | kebab}}.core
+  "The program: greets whoever its first argument names."
+  (:require [clojure.string :as str])
+  (:gen-class))
+
+(defn greet
+  "\"Hello, NAME!\", or \"Hello, World!\" for a name that is nil or blank."
+  [name]
+  (if (str/blank? name)
+    "Hello, World!"
+    (str "Hello, " (str/trim name) "!")))
+
+(defn -main [& args]
+  (println (greet (first args))))
@@ -0,0 +1,11 @@
+(ns {{PROJECT_NAME | kebab}}.core-test
+  (:require [clojure.test :refer [deftest is testing]]
+            [{{PROJECT_NAME | kebab}}.core :refer [greet]]))
+
+(deftest greet-test
+  (testing "greets by name"
+    (is (= "Hello, Ada!" (greet "Ada")))
+    (is (= "Hello, Ada Lovelace!" (greet "  Ada Lovelace\n"))))
+  (testing "greets the world without a name"
+    (is (= "Hello, World!" (greet nil)))
+    (is (= "Hello, World!" (greet " \t ")))))
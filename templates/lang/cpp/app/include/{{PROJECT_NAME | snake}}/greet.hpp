@@ -0,0 +1,12 @@
+#pragma once
+
+#include <string>
+#include <string_view>
+
+namespace {{PROJECT_NAME | snake}} {
+
+// "Hello, NAME!", or "Hello, World!" for a name that is empty or blank,
+// with the whitespace around it trimmed.
+[[nodiscard]] std::string greet(std::string_view name);
+
+}  // namespace {{PROJECT_NAME | snake}}
@@ -0,0 +1,21 @@
+#include "{{PROJECT_NAME | snake}}/greet.hpp"
+
+#include <string>
+#include <string_view>
+
+namespace {{PROJECT_NAME | snake}} {
+
+namespace {
+constexpr std::string_view whitespace = " \t\n\v\f\r";
+}  // namespace
+
+std::string greet(std::string_view name) {
+    const auto first = name.find_first_not_of(whitespace);
+    if (first == std::string_view::npos) {
+        return "Hello, World!";
+    }
+    const auto last = name.find_last_not_of(whitespace);
+    return "Hello, " + std::string{name.substr(first, last - first + 1)} + "!";
+}
+
+}  // namespace {{PROJECT_NAME | snake}}
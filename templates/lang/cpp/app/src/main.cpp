@@ -0,0 +1,10 @@
+#include <cstddef>
+#include <iostream>
+#include <span>
+
+#include "{{PROJECT_NAME | snake}}/greet.hpp"
+
+int main(int argc, char* argv[]) {
+    const std::span args(argv, static_cast<std::size_t>(argc));
+    std::cout << {{PROJECT_NAME | snake}}::greet(args.size() > 1 ? args[1] : "") << '\n';
+}
@@ -0,0 +1,15 @@
+#include "{{PROJECT_NAME | snake}}/greet.hpp"
+
+#include <gtest/gtest.h>
+
+using {{PROJECT_NAME | snake}}::greet;
+
+TEST(Greet, GreetsByName) {
+    EXPECT_EQ(greet("Ada"), "Hello, Ada!");
+    EXPECT_EQ(greet("  Ada Lovelace\n"), "Hello, Ada Lovelace!");
+}
+
+TEST(Greet, GreetsTheWorldWithoutAName) {
+    EXPECT_EQ(greet(""), "Hello, World!");
+    EXPECT_EQ(greet(" \t "), "Hello, World!");
+}
@@ -0,0 +1,11 @@
+import { assertEquals } from "@std/assert";
+import { greet } from "./greet.ts";
+
+Deno.test("greet greets by name", () => {
+  assertEquals(greet("Ada"), "Hello, Ada!");
+});
+
+Deno.test("greet greets the world when no name is given", () => {
+  assertEquals(greet(), "Hello, World!");
+  assertEquals(greet("  "), "Hello, World!");
+});
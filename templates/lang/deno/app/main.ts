@@ -0,0 +1,28 @@
+import { serveDir } from "@std/http/file-server";
+import { STATUS_CODE } from "@std/http/status";
+import { greet } from "./greet.ts";
+
+const STATIC = `${import.meta.dirname}/static`;
+
+/** handler answers one request: the API routes, then the files in static/. */
+export function handler(req: Request): Response | Promise<Response> {
+  const url = new URL(req.url);
+  if (url.pathname === "/health") {
+    return Response.json({ status: "ok" });
+  }
+  if (url.pathname === "/api/greet") {
+    if (req.method !== "GET") {
+      return new Response(null, {
+        status: STATUS_CODE.MethodNotAllowed,
+        headers: { Allow: "GET" },
+      });
+    }
+    return Response.json({ message: greet(url.searchParams.get("name")) });
+  }
+  return serveDir(req, { fsRoot: STATIC, quiet: true });
+}
+
+if (import.meta.main) {
+  const port = Number(Deno.env.get("PORT") ?? 8000);
+  Deno.serve({ port, hostname: "0.0.0.0" }, handler);
+}
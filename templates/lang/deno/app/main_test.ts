@@ -0,0 +1,39 @@
+import { assertEquals, assertStringIncludes } from "@std/assert";
+import { STATUS_CODE } from "@std/http/status";
+import { handler } from "./main.ts";
+
+function get(path: string, init?: RequestInit): Promise<Response> {
+  return Promise.resolve(handler(new Request(`http://localhost${path}`, init)));
+}
+
+Deno.test("/health answers ok", async () => {
+  const res = await get("/health");
+  assertEquals(res.status, STATUS_CODE.OK);
+  assertEquals(await res.json(), { status: "ok" });
+});
+
+Deno.test("/api/greet greets the name it is given", async () => {
+  const res = await get("/api/greet?name=Ada");
+  assertEquals(res.status, STATUS_CODE.OK);
+  assertEquals(await res.json(), { message: "Hello, Ada!" });
+});
+
+Deno.test("/api/greet only answers GET", async () => {
+  const res = await get("/api/greet", { method: "POST" });
+  assertEquals(res.status, STATUS_CODE.MethodNotAllowed);
+  assertEquals(res.headers.get("allow"), "GET");
+  await res.body?.cancel();
+});
+
+Deno.test("/ serves static/index.html", async () => {
+  const res = await get("/");
+  assertEquals(res.status, STATUS_CODE.OK);
+  assertStringIncludes(res.headers.get("content-type") ?? "", "text/html");
+  assertStringIncludes(await res.text(), "<title>{{PROJECT_NAME}}</title>");
+});
+
+Deno.test("an unknown path is not found", async () => {
+  const res = await get("/no/such/page");
+  assertEquals(res.status, STATUS_CODE.NotFound);
+  await res.body?.cancel();
+});
@@ -0,0 +1,27 @@
+;;; {{PROJECT_NAME | kebab}}-test.el --- Tests for {{PROJECT_NAME | kebab}}  -*- lexical-binding: t; -*-
+
+;;; Commentary:
+
+;; Run with eask test ert test/*.el.
+
+;;; Code:
+
+(require 'ert)
+(require '{{PROJECT_NAME | kebab}})
+
+(ert-deftest {{PROJECT_NAME | kebab}}-test-greeting ()
+  (should (equal ({{PROJECT_NAME | kebab}}-greeting "Ada") "Hello, Ada!"))
+  (should (equal ({{PROJECT_NAME | kebab}}-greeting "  Ada Lovelace\n")
+                 "Hello, Ada Lovelace!")))
+
+(ert-deftest {{PROJECT_NAME | kebab}}-test-greeting-default-name ()
+  (should (equal ({{PROJECT_NAME | kebab}}-greeting nil) "Hello, World!"))
+  (should (equal ({{PROJECT_NAME | kebab}}-greeting " \t ") "Hello, World!"))
+  (let (({{PROJECT_NAME | kebab}}-default-name "you"))
+    (should (equal ({{PROJECT_NAME | kebab}}-greeting "") "Hello, you!"))))
+
+(ert-deftest {{PROJECT_NAME | kebab}}-test-greet ()
+  (should (equal ({{PROJECT_NAME | kebab}}-greet "Ada") "Hello, Ada!")))
+
+(provide '{{PROJECT_NAME | kebab}}-test)
+;;; {{PROJECT_NAME | kebab}}-test.el ends here
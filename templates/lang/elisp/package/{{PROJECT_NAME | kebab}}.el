@@ -0,0 +1,48 @@
+;;; {{PROJECT_NAME | kebab}}.el --- {{SYNOPSIS}}  -*- lexical-binding: t; -*-
+
+;; Copyright (C) {{ date "%Y" }}  {{AUTHOR}}
+
+;; Author: {{AUTHOR_LINE}}
+;; Version: 0.1.0
+;; Package-Requires: ((emacs "27.1"))
+;; Keywords: {{keywords}}
+;; SPDX-License-Identifier: {{LICENSE}}
+
+;; This file is not part of GNU Emacs.
+
+;;; Commentary:
+
+;; {{SYNOPSIS}}.
+;;
+;; M-x {{PROJECT_NAME | kebab}}-greet shows a greeting in the echo area, for the
+;; name it asks for or, given none, for `{{PROJECT_NAME | kebab}}-default-name'.
+
+;;; Code:
+
+(require 'subr-x)
+
+(defgroup {{PROJECT_NAME | kebab}} nil
+  "{{SYNOPSIS}}."
+  :prefix "{{PROJECT_NAME | kebab}}-"
+  :group 'applications)
+
+(defcustom {{PROJECT_NAME | kebab}}-default-name "World"
+  "Who `{{PROJECT_NAME | kebab}}-greet' greets when given no name."
+  :type 'string)
+
+(defun {{PROJECT_NAME | kebab}}-greeting (name)
+  "Return \"Hello, NAME!\".
+A NAME that is nil or blank greets `{{PROJECT_NAME | kebab}}-default-name'."
+  (let ((name (string-trim (or name ""))))
+    (format "Hello, %s!"
+            (if (string-empty-p name) {{PROJECT_NAME | kebab}}-default-name name))))
+
+;;;###autoload
+(defun {{PROJECT_NAME | kebab}}-greet (name)
+  "Show a greeting for NAME in the echo area.
+Interactively, ask for NAME."
+  (interactive "sName: ")
+  (message "%s" ({{PROJECT_NAME | kebab}}-greeting name)))
+
+(provide '{{PROJECT_NAME | kebab}})
+;;; {{PROJECT_NAME | kebab}}.el ends here
@@ -0,0 +1,83 @@
+defmodule {{PROJECT_NAME | pascal}} do
+  @moduledoc """
+  Slugs for URLs and file names: lowercase ASCII words joined by a
+  separator.
+
+      iex> {{PROJECT_NAME | pascal}}.slugify("Crème brûlée: a how-to")
+      "creme-brulee-a-how-to"
+
+  """
+
+  @typedoc "An option for `slugify/2`."
+  @type option :: {:separator, String.t()} | {:max_length, non_neg_integer()}
+
+  # Letters NFKD does not take apart into a base letter and accents.
+  @folds %{"ß" => "ss", "æ" => "ae", "ø" => "o", "œ" => "oe", "đ" => "d", "ł" => "l"}
+
+  @doc """
+  Makes `text` a slug: letters folded to lowercase ASCII (é to e, ß to
+  ss), apostrophes dropped, and any run of other characters made one
+  separator, none at either end. It is `""` when `text` has no letters
+  or digits.
+
+  ## Options
+
+    * `:separator` - what goes between the words, `"-"` by default
+    * `:max_length` - cut the slug to at most this many characters,
+      after the last word that fits, or within the first word when even
+      that is too long; `0`, the default, is no limit
+
+  ## Examples
+
+      iex> {{PROJECT_NAME | pascal}}.slugify("Don't panic!")
+      "dont-panic"
+
+      iex> {{PROJECT_NAME | pascal}}.slugify("The quick brown fox", separator: "_", max_length: 12)
+      "the_quick"
+
+  """
+  @spec slugify(String.t(), [option()]) :: String.t()
+  def slugify(text, opts \\ []) do
+    sep = Keyword.get(opts, :separator, "-")
+    max = Keyword.get(opts, :max_length, 0)
+
+    text
+    |> String.downcase()
+    |> String.replace(Map.keys(@folds), &Map.fetch!(@folds, &1))
+    |> :unicode.characters_to_nfkd_binary()
+    # Dropping what is not ASCII drops the accents NFKD split off, and
+    # curly apostrophes; the straight ones go next, so don't is dont.
+    |> String.replace(~r/[^\x00-\x7F]/u, "")
+    |> String.replace("'", "")
+    |> String.split(~r/[^a-z0-9]+/, trim: true)
+    |> Enum.join(sep)
+    |> cut(sep, max)
+  end
+
+  defp cut(slug, _sep, max) when max <= 0 or byte_size(slug) <= max, do: slug
+  defp cut(slug, "", max), do: binary_part(slug, 0, max)
+
+  defp cut(slug, sep, max) do
+    <<short::binary-size(max), rest::binary>> = slug
+
+    if String.starts_with?(rest, sep) or not String.contains?(short, sep) do
+      String.trim_trailing(short, sep)
+    else
+      # The cut is within a word: drop what fits of it.
+      short |> String.split(sep) |> Enum.drop(-1) |> Enum.join(sep)
+    end
+  end
+
+  @doc """
+  Whether `text` is a slug as `slugify/2` makes them with its default
+  separator.
+
+      iex> {{PROJECT_NAME | pascal}}.slug?("hello-world")
+      true
+      iex> {{PROJECT_NAME | pascal}}.slug?("Hello World")
+      false
+
+  """
+  @spec slug?(String.t()) :: boolean()
+  def slug?(text), do: Regex.match?(~r/\A[a-z0-9]+(?:-[a-z0-9]+)*\z/, text)
+end
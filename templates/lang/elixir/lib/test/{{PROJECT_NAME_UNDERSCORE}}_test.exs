@@ -0,0 +1,58 @@
+defmodule {{PROJECT_NAME | pascal}}Test do
+  use ExUnit.Case, async: true
+
+  # The examples in the docs run as tests too.
+  doctest {{PROJECT_NAME | pascal}}
+
+  describe "slugify/2" do
+    for {text, want} <- [
+          {"Hello, World!", "hello-world"},
+          {"  many   spaces  ", "many-spaces"},
+          {"Crème brûlée", "creme-brulee"},
+          {"Straße", "strasse"},
+          {"Don't stop", "dont-stop"},
+          {"dots.and_underscores", "dots-and-underscores"},
+          {"100% sure", "100-sure"},
+          {"!!!", ""},
+          {"", ""}
+        ] do
+      test "makes #{inspect(text)} #{inspect(want)}" do
+        assert {{PROJECT_NAME | pascal}}.slugify(unquote(text)) == unquote(want)
+      end
+    end
+
+    for {max, want} <- [
+          {0, "the-quick-brown-fox"},
+          {50, "the-quick-brown-fox"},
+          {9, "the-quick"},
+          {10, "the-quick"},
+          {12, "the-quick"},
+          {2, "th"}
+        ] do
+      test "cuts at a word with max_length: #{max}" do
+        slug = {{PROJECT_NAME | pascal}}.slugify("The quick brown fox", max_length: unquote(max))
+        assert slug == unquote(want)
+      end
+    end
+
+    test "takes another separator" do
+      assert {{PROJECT_NAME | pascal}}.slugify("Hello, World!", separator: "_") == "hello_world"
+      assert {{PROJECT_NAME | pascal}}.slugify("a b c", separator: "--", max_length: 4) == "a--b"
+      assert {{PROJECT_NAME | pascal}}.slugify("a b c", separator: "", max_length: 2) == "ab"
+    end
+  end
+
+  describe "slug?/1" do
+    test "accepts what slugify/2 makes" do
+      for text <- ["Hello, World!", "Crème brûlée", "xxx"] do
+        assert {{PROJECT_NAME | pascal}}.slug?({{PROJECT_NAME | pascal}}.slugify(text))
+      end
+    end
+
+    test "refuses anything else" do
+      for text <- ["", "Hello", "a--b", "-a", "a-", "a b"] do
+        refute {{PROJECT_NAME | pascal}}.slug?(text), inspect(text)
+      end
+    end
+  end
+end
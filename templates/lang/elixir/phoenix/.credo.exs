@@ -0,0 +1,20 @@
+# mix credo --strict; `mix credo explain <file>:<line>` says why a check
+# fails and how to fix it.
+%{
+  configs: [
+    %{
+      name: "default",
+      files: %{
+        included: ["lib/", "test/", "config/"],
+        excluded: [~r"/_build/", ~r"/deps/"]
+      },
+      strict: true,
+      checks: %{
+        disabled: [
+          # Most of what phx.new writes has no @moduledoc.
+          {Credo.Check.Readability.ModuleDoc, []}
+        ]
+      }
+    }
+  ]
+}
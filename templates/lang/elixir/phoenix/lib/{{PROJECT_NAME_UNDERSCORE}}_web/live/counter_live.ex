@@ -0,0 +1,33 @@
+defmodule {{PROJECT_NAME | pascal}}Web.CounterLive do
+  @moduledoc """
+  A sample live page at /counter: a count the server keeps for each
+  visitor, changed over the LiveView socket without reloading the page.
+  """
+  use {{PROJECT_NAME | pascal}}Web, :live_view
+
+  @impl true
+  def mount(_params, _session, socket) do
+    {:ok, assign(socket, count: 0, page_title: "Counter")}
+  end
+
+  @impl true
+  def render(assigns) do
+    ~H"""
+    <.header>
+      Counter
+      <:subtitle>Kept on the server and sent to the page as it changes.</:subtitle>
+    </.header>
+    <p id="count" class="my-8 text-center text-6xl font-semibold">{@count}</p>
+    <div class="flex justify-center gap-4">
+      <.button phx-click="dec" aria-label="Down one">-</.button>
+      <.button phx-click="inc" aria-label="Up one">+</.button>
+      <.button phx-click="reset" disabled={@count == 0}>Reset</.button>
+    </div>
+    """
+  end
+
+  @impl true
+  def handle_event("inc", _params, socket), do: {:noreply, update(socket, :count, &(&1 + 1))}
+  def handle_event("dec", _params, socket), do: {:noreply, update(socket, :count, &(&1 - 1))}
+  def handle_event("reset", _params, socket), do: {:noreply, assign(socket, count: 0)}
+end
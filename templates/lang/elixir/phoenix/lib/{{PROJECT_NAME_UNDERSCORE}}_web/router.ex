@@ -0,0 +1,28 @@
+defmodule {{PROJECT_NAME | pascal}}Web.Router do
+  use {{PROJECT_NAME | pascal}}Web, :router
+
+  pipeline :browser do
+    plug :accepts, ["html"]
+    plug :fetch_session
+    plug :fetch_live_flash
+    plug :put_root_layout, html: {{{PROJECT_NAME | pascal}}Web.Layouts, :root}
+    plug :protect_from_forgery
+    plug :put_secure_browser_headers
+  end
+
+  pipeline :api do
+    plug :accepts, ["json"]
+  end
+
+  scope "/", {{PROJECT_NAME | pascal}}Web do
+    pipe_through :browser
+
+    get "/", PageController, :home
+    live "/counter", CounterLive
+  end
+
+  # Other scopes may use custom stacks.
+  # scope "/api", {{PROJECT_NAME | pascal}}Web do
+  #   pipe_through :api
+  # end
+end
@@ -0,0 +1,39 @@
+defmodule {{PROJECT_NAME | pascal}}Web.CounterLiveTest do
+  use {{PROJECT_NAME | pascal}}Web.ConnCase, async: true
+
+  import Phoenix.LiveViewTest
+
+  test "starts at zero", %{conn: conn} do
+    {:ok, view, html} = live(conn, ~p"/counter")
+
+    assert html =~ "Counter"
+    assert count(view) == "0"
+    assert has_element?(view, ~s{button[phx-click="reset"][disabled]})
+  end
+
+  test "counts up and down, and resets", %{conn: conn} do
+    {:ok, view, _html} = live(conn, ~p"/counter")
+
+    click(view, "inc")
+    click(view, "inc")
+    click(view, "dec")
+    assert count(view) == "1"
+
+    click(view, "reset")
+    assert count(view) == "0"
+  end
+
+  test "is served to a plain GET too", %{conn: conn} do
+    conn = get(conn, ~p"/counter")
+
+    assert html_response(conn, 200) =~ ~s{id="count"}
+  end
+
+  defp click(view, event) do
+    view |> element(~s{button[phx-click="#{event}"]}) |> render_click()
+  end
+
+  defp count(view) do
+    view |> element("#count") |> render() |> Floki.parse_fragment!() |> Floki.text()
+  end
+end
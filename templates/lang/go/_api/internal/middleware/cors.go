@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"{{MODULE_PATH}}/internal/api"
+)
+
+// CORS lets pages from origins ("*" for any) call the app from the
+// browser with methods and headers. It answers their preflight OPTIONS
+// requests itself, with a 403 for an origin, method or header it does
+// not allow, and marks their other requests as allowed for the
+// browser. Requests from origins it does not know get no CORS headers,
+// so the browser keeps the answer from the page. No origins turns it
+// off. Credentials are not allowed: pages elsewhere send no cookies,
+// so an API they may call takes its credentials in a header, such as
+// Authorization.
+func CORS(origins, methods, headers []string) func(http.Handler) http.Handler {
+	if len(origins) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			w.Header().Add("Vary", "Origin")
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			allowed := slices.Contains(origins, "*") || slices.Contains(origins, origin)
+			if preflight {
+				if !allowed || !slices.Contains(methods, r.Header.Get("Access-Control-Request-Method")) ||
+					!allHeaders(headers, r.Header.Get("Access-Control-Request-Headers")) {
+					api.WriteError(w, r, api.Errorf(http.StatusForbidden, "forbidden", "cross-origin request not allowed"))
+					return
+				}
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allHeaders is whether each of the comma-separated requested headers
+// is one of allowed, which header names are regardless of case.
+func allHeaders(allowed []string, requested string) bool {
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.TrimSpace(h)
+		if h != "" && !slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(a, h) }) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"{{MODULE_PATH}}/internal/api"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// RateLimit lets each client IP make rate requests a second, with
+// bursts of up to burst, and answers the rest with a 429 Too Many
+// Requests and a Retry-After header. A rate of 0 is no limit. Buckets
+// live in memory, one per IP, and are dropped once full again, so a
+// client that stops costs nothing.
+func RateLimit(rate float64, burst int) func(http.Handler) http.Handler {
+	if rate <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	l := &limiter{rate: rate, burst: float64(burst), buckets: map[string]*bucket{}, now: time.Now}
+	return l.middleware
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type limiter struct {
+	rate, burst float64
+	now         func() time.Time // time.Now, or a stand-in in tests
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+func (l *limiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait := l.take(clientIP(r)); wait > 0 {
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			logging.FromContext(r.Context()).Warn("rate limited", "ip", clientIP(r), "path", r.URL.Path)
+			api.WriteError(w, r, api.Errorf(http.StatusTooManyRequests, "rate_limited", "too many requests; retry in %ds", seconds))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// take spends one of ip's tokens, or says how long until there is one.
+func (l *limiter) take(ip string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.sweep(now)
+	b, ok := l.buckets[ip]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[ip] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
+
+// sweep drops, once a minute, the buckets that have filled up again:
+// a new one would be the same.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < time.Minute {
+		return
+	}
+	l.swept = now
+	for ip, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, ip)
+		}
+	}
+}
+
+// clientIP is the address the request came from. Behind a proxy that is
+// the proxy's, so every client shares one bucket; have the proxy limit
+// instead.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"{{MODULE_PATH}}/internal/api"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// Recover turns a panic in a handler into a 500, logging it with the
+// stack, so one bad request does not take the server down.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			if err == http.ErrAbortHandler {
+				panic(err) // net/http's way to abort, not a crash
+			}
+			logging.FromContext(r.Context()).Error("panic", "err", err, "stack", string(debug.Stack()))
+			api.WriteError(w, r, api.Errorf(http.StatusInternalServerError, "internal", "internal server error"))
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,3 @@
+module {{MODULE_PATH}}
+
+go {{GO_VERSION}}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func TestLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	h := Logger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/brew", nil))
+
+	if !strings.Contains(out.String(), "method=POST path=/brew status=418 ") {
+		t.Errorf("expected method, path and status, got %q", out.String())
+	}
+}
+
+func TestLoggerDefaultsTo200(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	h := Logger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if !strings.Contains(out.String(), "status=200 ") {
+		t.Errorf("expected status 200, got %q", out.String())
+	}
+}
+
+func TestLoggerScopesTheRequest(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("handled")
+	}), RequestID, Logger(logger))
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(Header, "abc123")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(out.String(), "msg=handled request_id=abc123") {
+		t.Errorf("expected the handler's line to carry the ID, got %q", out.String())
+	}
+}
+
+func TestLoggerLetsHandlersHijack(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(new(bytes.Buffer), nil))
+	hijacked := make(chan error, 1)
+	h := Logger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		hijacked <- err
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	http.Get(srv.URL) // fails: the handler closes the connection
+	if err := <-hijacked; err != nil {
+		t.Errorf("expected the connection hijacked, got %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"net/http"
+
+	"{{MODULE_PATH}}/internal/api"
+)
+
+type {{NAME | camel}}Response struct {
+	Message string `json:"message"`
+}
+
+func handle{{NAME | pascal}}(w http.ResponseWriter, r *http.Request) {
+	api.JSON(w, http.StatusOK, {{NAME | camel}}Response{"{{NAME | title}}"})
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealthz(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleHealthz(w, httptest.NewRequest("GET", "/healthz", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+	tests := []struct {
+		name   string
+		checks map[string]func(context.Context) error
+		status int
+		body   string
+	}{
+		{"no checks", nil, http.StatusOK, `{"status":"ok"}`},
+		{"all pass", map[string]func(context.Context) error{"db": ok}, http.StatusOK, `{"status":"ok"}`},
+		{"one fails", map[string]func(context.Context) error{"db": down, "cache": ok}, http.StatusServiceUnavailable,
+			`{"error":{"code":"not_ready","message":"not ready","fields":{"db":"connection refused"}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handleReadyz(tt.checks)(w, httptest.NewRequest("GET", "/readyz", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != tt.body {
+				t.Errorf("expected %q, got %q", tt.body, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package config loads the app's settings from environment variables,
+// with defaults, and checks them.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is the app's settings, each from the variable named beside it.
+type Config struct {
+	Port     string     // PORT; 4000
+	Env      string     // APP_ENV: development (the default) or production
+	LogLevel slog.Level // LOG_LEVEL: debug, info (the default), warn or error
+	BaseURL  string     // BASE_URL, where users reach the app; http://localhost:PORT
+	Profile  bool       // APP_PROFILE: serve /debug/pprof/ unless it is 0
+
+	RateLimit float64 // RATE_LIMIT: requests a second each IP may make to /api/; 0 (the default) is no limit
+	RateBurst int     // RATE_BURST: how many of those may come at once; 20
+
+	CORSOrigins []string // CORS_ORIGINS: origins (comma-separated, * or none) whose pages may call /api/; *
+	CORSMethods []string // CORS_METHODS: the methods they may use; GET, POST, PUT, PATCH and DELETE
+	CORSHeaders []string // CORS_HEADERS: the headers they may send; Content-Type and Authorization
+
+	ReadHeaderTimeout time.Duration // READ_HEADER_TIMEOUT: how long a client may take to send a request's headers; 5s
+	ReadTimeout       time.Duration // READ_TIMEOUT: and the whole request; 10s
+	WriteTimeout      time.Duration // WRITE_TIMEOUT: how long writing the answer may take; 60s, room for a 30s /debug/pprof/profile
+	IdleTimeout       time.Duration // IDLE_TIMEOUT: how long a kept-alive connection waits for its next request; 120s
+	RequestTimeout    time.Duration // REQUEST_TIMEOUT: how long a handler has before the client gets a 503; 30s
+	MaxHeaderBytes    int           // MAX_HEADER_BYTES: the most a request's headers may take; 64 KiB
+	MaxBodyBytes      int64         // MAX_BODY_BYTES: the most a request's body may take, or it gets a 413; 0 (the default) is no limit
+}
+
+// Load reads the settings through getenv (os.Getenv, or a stand-in in
+// tests). The error lists every setting that is wrong.
+func Load(getenv func(string) string) (Config, error) {
+	var errs []error
+	c := Config{
+		Port:    first(getenv("PORT"), "4000"),
+		Env:     first(getenv("APP_ENV"), "development"),
+		Profile: getenv("APP_PROFILE") != "0",
+	}
+	if n, err := strconv.Atoi(c.Port); err != nil || n < 1 || n > 65535 {
+		errs = append(errs, fmt.Errorf("PORT: %q is not a port number", c.Port))
+	}
+	if c.Env != "development" && c.Env != "production" {
+		errs = append(errs, fmt.Errorf("APP_ENV: %q is neither development nor production", c.Env))
+	}
+	if err := c.LogLevel.UnmarshalText([]byte(first(getenv("LOG_LEVEL"), "info"))); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+	}
+	if v := getenv("RATE_LIMIT"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(n) || n < 0 {
+			errs = append(errs, fmt.Errorf("RATE_LIMIT: %q is not a number of requests a second", v))
+		}
+		c.RateLimit = n
+	}
+	c.RateBurst = 20
+	if v := getenv("RATE_BURST"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			errs = append(errs, fmt.Errorf("RATE_BURST: %q is not a positive number", v))
+		}
+		c.RateBurst = n
+	}
+	// Without cookies, a page elsewhere can only do what anyone with the
+	// API's URL can, so any may call it unless CORS_ORIGINS says otherwise.
+	if v := first(getenv("CORS_ORIGINS"), "*"); v != "none" {
+		c.CORSOrigins = list(v)
+	}
+	for _, origin := range c.CORSOrigins {
+		if u, err := url.Parse(origin); origin != "*" && (err != nil || u.Host == "" || u.Scheme+"://"+u.Host != origin) {
+			errs = append(errs, fmt.Errorf("CORS_ORIGINS: %q is neither * nor none nor an origin like https://example.com", origin))
+		}
+	}
+	c.CORSMethods = list(first(getenv("CORS_METHODS"), "GET,POST,PUT,PATCH,DELETE"))
+	c.CORSHeaders = list(first(getenv("CORS_HEADERS"), "Content-Type,Authorization"))
+	c.BaseURL = first(getenv("BASE_URL"), "http://localhost:"+c.Port)
+	c.BaseURL = strings.TrimSuffix(c.BaseURL, "/")
+	if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("BASE_URL: %q is not an http or https URL", c.BaseURL))
+	}
+	c.ReadHeaderTimeout = duration(getenv, "READ_HEADER_TIMEOUT", 5*time.Second, &errs)
+	c.ReadTimeout = duration(getenv, "READ_TIMEOUT", 10*time.Second, &errs)
+	c.WriteTimeout = duration(getenv, "WRITE_TIMEOUT", 60*time.Second, &errs)
+	c.IdleTimeout = duration(getenv, "IDLE_TIMEOUT", 120*time.Second, &errs)
+	c.RequestTimeout = duration(getenv, "REQUEST_TIMEOUT", 30*time.Second, &errs)
+	if c.RequestTimeout > 0 && c.RequestTimeout >= c.WriteTimeout {
+		errs = append(errs, fmt.Errorf("REQUEST_TIMEOUT: %s is not shorter than WRITE_TIMEOUT, so its 503 could not be sent", c.RequestTimeout))
+	}
+	c.MaxHeaderBytes = int(size(getenv, "MAX_HEADER_BYTES", 64<<10, &errs))
+	c.MaxBodyBytes = size(getenv, "MAX_BODY_BYTES", 0, &errs)
+	return c, errors.Join(errs...)
+}
+
+// list is the comma-separated values of v, without blanks.
+func list(v string) []string {
+	var values []string
+	for _, value := range strings.Split(v, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
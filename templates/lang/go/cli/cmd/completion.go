@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func completionCommand() *command {
+	c := newCommand("completion", "bash|zsh|fish", "Print a shell completion script")
+	c.run = func(ctx context.Context, a *app, args []string) error {
+		if len(args) != 1 {
+			return usagef(a, "completion takes a shell: bash, zsh or fish")
+		}
+		write, ok := map[string]func(io.Writer, []*command){
+			"bash": bashCompletion,
+			"zsh":  zshCompletion,
+			"fish": fishCompletion,
+		}[args[0]]
+		if !ok {
+			return usagef(a, "no completion for %q; there is bash, zsh and fish", args[0])
+		}
+		write(a.stdout, visible(commands()))
+		return nil
+	}
+	return c
+}
+
+// completeFunc is the name of the completion function, which shells
+// want without the dashes a program's name may have.
+var completeFunc = "_" + strings.ReplaceAll(name, "-", "_")
+
+// flagNames are -name for each of fs's flags.
+func flagNames(fs *flag.FlagSet) []string {
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, "-"+f.Name) })
+	return names
+}
+
+func bashCompletion(w io.Writer, cmds []*command) {
+	names := []string{"help"}
+	for _, c := range cmds {
+		names = append(names, c.name)
+	}
+	fmt.Fprintf(w, "# bash completion for %s: source <(%s completion bash)\n", name, name)
+	fmt.Fprintf(w, "%s() {\n", completeFunc)
+	fmt.Fprintf(w, "    local cur=${COMP_WORDS[COMP_CWORD]}\n")
+	fmt.Fprintf(w, "    if [[ $COMP_CWORD -eq 1 ]]; then\n")
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(names, " "))
+	fmt.Fprintf(w, "        return\n    fi\n")
+	fmt.Fprintf(w, "    case ${COMP_WORDS[1]} in\n")
+	for _, c := range cmds {
+		words := flagNames(c.flags)
+		if c.name == "completion" {
+			words = []string{"bash", "zsh", "fish"}
+		}
+		if len(words) > 0 {
+			fmt.Fprintf(w, "        %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", c.name, strings.Join(words, " "))
+		}
+	}
+	fmt.Fprintf(w, "        help) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", strings.Join(names[1:], " "))
+	fmt.Fprintf(w, "    esac\n}\ncomplete -F %s %s\n", completeFunc, name)
+}
+
+// zshQuote makes s safe inside one of _arguments' specs, in single
+// quotes.
+var zshQuote = strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`).Replace
+
+func zshCompletion(w io.Writer, cmds []*command) {
+	fmt.Fprintf(w, "#compdef %s\n# zsh completion for %s: source <(%s completion zsh)\n", name, name, name)
+	fmt.Fprintf(w, "%s() {\n    local -a commands\n    commands=(\n", completeFunc)
+	for _, c := range cmds {
+		fmt.Fprintf(w, "        '%s:%s'\n", c.name, zshQuote(c.summary))
+	}
+	fmt.Fprintf(w, "        'help:Show help for a command'\n    )\n")
+	fmt.Fprintf(w, "    if (( CURRENT == 2 )); then\n        _describe command commands\n        return\n    fi\n")
+	fmt.Fprintf(w, "    case $words[2] in\n")
+	for _, c := range cmds {
+		var specs []string
+		c.flags.VisitAll(func(f *flag.Flag) {
+			value, usage := flag.UnquoteUsage(f)
+			spec := fmt.Sprintf("'-%s[%s]", f.Name, zshQuote(usage))
+			if !isBool(f) {
+				spec += ":" + zshQuote(value) + ":"
+			}
+			specs = append(specs, spec+"'")
+		})
+		if c.name == "completion" {
+			specs = []string{"'1:shell:(bash zsh fish)'"}
+		}
+		if len(specs) > 0 {
+			fmt.Fprintf(w, "        %s) _arguments %s ;;\n", c.name, strings.Join(specs, " "))
+		}
+	}
+	fmt.Fprintf(w, "        help) _describe command commands ;;\n")
+	fmt.Fprintf(w, "    esac\n}\ncompdef %s %s\n", completeFunc, name)
+}
+
+// fishQuote makes s safe in single quotes.
+var fishQuote = strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace
+
+func fishCompletion(w io.Writer, cmds []*command) {
+	fmt.Fprintf(w, "# fish completion for %s: %s completion fish | source\n", name, name)
+	fmt.Fprintf(w, "complete -c %s -f\n", name)
+	for _, c := range cmds {
+		fmt.Fprintf(w, "complete -c %s -n __fish_use_subcommand -a %s -d '%s'\n", name, c.name, fishQuote(c.summary))
+	}
+	fmt.Fprintf(w, "complete -c %s -n __fish_use_subcommand -a help -d 'Show help for a command'\n", name)
+	for _, c := range cmds {
+		seen := fmt.Sprintf("'__fish_seen_subcommand_from %s'", c.name)
+		c.flags.VisitAll(func(f *flag.Flag) {
+			_, usage := flag.UnquoteUsage(f)
+			line := fmt.Sprintf("complete -c %s -n %s -o %s -d '%s'", name, seen, f.Name, fishQuote(usage))
+			if !isBool(f) {
+				line += " -r"
+			}
+			fmt.Fprintln(w, line)
+		})
+		if c.name == "completion" {
+			fmt.Fprintf(w, "complete -c %s -n %s -a 'bash zsh fish'\n", name, seen)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/config"
+)
+
+func TestGreet(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(file, []byte(`{"greeting": "Hi"}`), 0o644)
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{"the world", nil, []string{"greet"}, "Hello, world!\n"},
+		{"names", nil, []string{"greet", "Ada", "Grace"}, "Hello, Ada!\nHello, Grace!\n"},
+		{"flags", nil, []string{"greet", "-greeting", "Hey", "-shout", "Ada"}, "HEY, ADA!\n"},
+		{"config file", nil, []string{"-config", file, "greet", "Ada"}, "Hi, Ada!\n"},
+		{"environment", map[string]string{config.Prefix + "GREETING": "Howdy"}, []string{"greet", "Ada"}, "Howdy, Ada!\n"},
+		{"flag over environment", map[string]string{config.Prefix + "GREETING": "Howdy"}, []string{"greet", "-greeting", "Hey", "Ada"}, "Hey, Ada!\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, code := run(t, tt.env, tt.args...)
+			if code != 0 || stdout != tt.want {
+				t.Errorf("expected %q, got %d %q (stderr %q)", tt.want, code, stdout, stderr)
+			}
+		})
+	}
+}
+
+func TestGreetWithoutConfig(t *testing.T) {
+	_, stderr, code := run(t, nil, "-config", filepath.Join(t.TempDir(), "none.json"), "greet")
+	if code != 1 || !strings.Contains(stderr, "no such file") {
+		t.Errorf("expected exit 1 saying the file is missing, got %d %q", code, stderr)
+	}
+}
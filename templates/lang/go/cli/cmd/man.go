@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/version"
+)
+
+// manCommand prints the manual page, for packagers and just man; it is
+// not listed, as users read the page rather than make it.
+func manCommand() *command {
+	c := newCommand("man", "", "Print the manual page, in roff")
+	c.hidden = true
+	c.run = func(ctx context.Context, a *app, args []string) error {
+		writeMan(a.stdout, rootFlags(&app{}), visible(commands()))
+		return nil
+	}
+	return c
+}
+
+// roff escapes s for a roff text line.
+var roff = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace
+
+// manFlags writes a tagged paragraph for each of fs's flags.
+func manFlags(w io.Writer, fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) {
+		value, usage := flag.UnquoteUsage(f)
+		fmt.Fprintf(w, ".TP\n\\fB\\-%s\\fR", roff(f.Name))
+		if value != "" {
+			fmt.Fprintf(w, " \\fI%s\\fR", roff(value))
+		}
+		fmt.Fprintf(w, "\n%s\n", roff(usage))
+	})
+}
+
+func writeMan(w io.Writer, root *flag.FlagSet, cmds []*command) {
+	fmt.Fprintf(w, ".TH %s 1 \"\" \"%s %s\" \"User Commands\"\n", roff(strings.ToUpper(name)), roff(name), roff(version.String()))
+	fmt.Fprintf(w, ".SH NAME\n%s \\- %s\n", roff(name), roff(strings.TrimSuffix(about, ".")))
+	fmt.Fprintf(w, ".SH SYNOPSIS\n.B %s\n[\\fIflags\\fR] \\fICOMMAND\\fR [\\fIflags\\fR] [\\fIargs\\fR]\n", roff(name))
+	fmt.Fprintf(w, ".SH DESCRIPTION\n%s\n", roff(about))
+	fmt.Fprintf(w, ".SH OPTIONS\n")
+	manFlags(w, root)
+	fmt.Fprintf(w, ".TP\n\\fB\\-version\\fR\nprint the version and exit\n")
+	fmt.Fprintf(w, ".SH COMMANDS\n")
+	for _, c := range cmds {
+		fmt.Fprintf(w, ".SS %s\n%s.\n", roff(strings.TrimSpace(c.name+" "+c.args)), roff(c.summary))
+		manFlags(w, c.flags)
+	}
+	fmt.Fprintf(w, ".SH ENVIRONMENT\n")
+	fmt.Fprintf(w, ".TP\n.B %sCONFIG\nthe config file, when \\fB\\-config\\fR does not name one\n", roff(config.Prefix))
+	fmt.Fprintf(w, ".TP\n.B %sGREETING\nwhat greet greets with, over the config file's greeting\n", roff(config.Prefix))
+	fmt.Fprintf(w, ".SH FILES\n.TP\n.I $XDG_CONFIG_HOME/%s/config.json\n", roff(name))
+	fmt.Fprintf(w, "the settings, as JSON, such as {\"greeting\": \"Hi\"}; ~/.config stands in for $XDG_CONFIG_HOME when it is not set\n")
+}
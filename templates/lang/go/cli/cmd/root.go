@@ -0,0 +1,195 @@
+// Package cmd is the command line: Execute picks a subcommand from the
+// arguments and runs it, and each subcommand has a file of its own.
+// Commands write to the writers they are given rather than os.Stdout,
+// so tests can run them and read what they said.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"{{MODULE_PATH}}/internal/config"
+)
+
+// name is what the program is called, in help, completions and the
+// manual.
+const name = "{{PROJECT_NAME}}"
+
+// about says what the program does, for help and the manual.
+const about = "Greet people by name."
+
+// A command is a subcommand, with its own flags.
+type command struct {
+	name    string
+	args    string // what follows its flags, for help, as in [NAME...]
+	summary string // what it does, in a line
+	hidden  bool   // left out of help and completions
+	flags   *flag.FlagSet
+	run     func(ctx context.Context, a *app, args []string) error
+}
+
+func newCommand(name, args, summary string) *command {
+	return &command{name: name, args: args, summary: summary, flags: flag.NewFlagSet(name, flag.ContinueOnError)}
+}
+
+// commands is every subcommand, in the order help lists them. They are
+// made anew for each Execute, so no flag's value outlives a run.
+func commands() []*command {
+	return []*command{greetCommand(), versionCommand(), completionCommand(), manCommand()}
+}
+
+// visible is the commands help and completions list.
+func visible(cmds []*command) []*command {
+	var shown []*command
+	for _, c := range cmds {
+		if !c.hidden {
+			shown = append(shown, c)
+		}
+	}
+	return shown
+}
+
+// app is what commands run with.
+type app struct {
+	stdout, stderr io.Writer
+	getenv         func(string) string
+	configPath     string // -config; config.Load finds one when empty
+}
+
+func (a *app) config() (config.Config, error) {
+	return config.Load(a.configPath, a.getenv)
+}
+
+// errUsage is a command line that is wrong, already reported with how
+// to put it right; Execute exits 2 on it.
+var errUsage = errors.New("usage")
+
+func usagef(a *app, format string, args ...any) error {
+	fmt.Fprintf(a.stderr, "%s: %s\nRun '%s help' for usage.\n", name, fmt.Sprintf(format, args...), name)
+	return errUsage
+}
+
+// Execute runs the command line args (os.Args[1:]) and is the exit
+// code: 0 when it succeeds, 2 when the command line is wrong and 1 when
+// the command fails, which it says on stderr.
+func Execute(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	a := &app{stdout: stdout, stderr: stderr, getenv: getenv}
+	err := execute(ctx, a, args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	}
+}
+
+func execute(ctx context.Context, a *app, args []string) error {
+	cmds := commands()
+	root := rootFlags(a)
+	root.Usage = func() { printUsage(a.stderr, root, cmds) }
+	showVersion := root.Bool("version", false, "print the version and exit")
+	if err := root.Parse(args); err != nil {
+		return parsed(err)
+	}
+	if *showVersion {
+		return printVersion(a)
+	}
+	if root.NArg() == 0 {
+		root.Usage()
+		return errUsage
+	}
+	sub, args := root.Arg(0), root.Args()[1:]
+	if sub == "help" {
+		return help(a, root, cmds, args)
+	}
+	for _, c := range cmds {
+		if c.name == sub {
+			c.flags.SetOutput(a.stderr)
+			c.flags.Usage = func() { printCommandUsage(a.stderr, c) }
+			if err := c.flags.Parse(args); err != nil {
+				return parsed(err)
+			}
+			return c.run(ctx, a, c.flags.Args())
+		}
+	}
+	return usagef(a, "unknown command %q", sub)
+}
+
+// rootFlags are the flags that come before the subcommand.
+func rootFlags(a *app) *flag.FlagSet {
+	root := flag.NewFlagSet(name, flag.ContinueOnError)
+	root.SetOutput(a.stderr)
+	root.StringVar(&a.configPath, "config", "", "read the settings from `FILE` rather than $XDG_CONFIG_HOME/"+name+"/config.json")
+	return root
+}
+
+// parsed is what an error from parsing flags means to Execute: the flag
+// package has printed it and the usage already.
+func parsed(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return errUsage
+}
+
+// help prints the usage, or a command's, on stdout.
+func help(a *app, root *flag.FlagSet, cmds []*command, args []string) error {
+	if len(args) == 0 {
+		printUsage(a.stdout, root, cmds)
+		return nil
+	}
+	for _, c := range cmds {
+		if c.name == args[0] {
+			printCommandUsage(a.stdout, c)
+			return nil
+		}
+	}
+	return usagef(a, "unknown command %q", args[0])
+}
+
+func printUsage(w io.Writer, root *flag.FlagSet, cmds []*command) {
+	fmt.Fprintf(w, "Usage: %s [flags] COMMAND [flags] [args]\n\n%s\n\nCommands:\n", name, about)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range visible(cmds) {
+		fmt.Fprintf(tw, "  %s\t%s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(tw, "  help\tShow help for a command\n")
+	tw.Flush()
+	fmt.Fprintf(w, "\nFlags:\n")
+	printFlags(w, root)
+	fmt.Fprintf(w, "\nRun '%s help COMMAND' for a command's flags.\n", name)
+}
+
+func printCommandUsage(w io.Writer, c *command) {
+	fmt.Fprintf(w, "Usage: %s %s [flags] %s\n\n%s.\n", name, c.name, c.args, c.summary)
+	if hasFlags(c.flags) {
+		fmt.Fprintf(w, "\nFlags:\n")
+		printFlags(w, c.flags)
+	}
+}
+
+func printFlags(w io.Writer, fs *flag.FlagSet) {
+	out := fs.Output()
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	fs.SetOutput(out)
+}
+
+func hasFlags(fs *flag.FlagSet) bool {
+	has := false
+	fs.VisitAll(func(*flag.Flag) { has = true })
+	return has
+}
+
+// isBool reports whether f is a flag that takes no value, as -shout.
+func isBool(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
@@ -0,0 +1,21 @@
+package version
+
+import "testing"
+
+func TestString(t *testing.T) {
+	defer func(v, c, d string) { Version, Commit, Date = v, c, d }(Version, Commit, Date)
+	tests := []struct {
+		version, commit, date string
+		want                  string
+	}{
+		{"v1.2.3", "1a2b3c4", "2026-01-02", "v1.2.3 (1a2b3c4, 2026-01-02)"},
+		{"v1.2.3", "", "", "v1.2.3"},
+		{"", "1a2b3c4", "", "dev (1a2b3c4)"},
+	}
+	for _, tt := range tests {
+		Version, Commit, Date = tt.version, tt.commit, tt.date
+		if got := String(); got != tt.want {
+			t.Errorf("String() with %q, %q, %q: expected %q, got %q", tt.version, tt.commit, tt.date, tt.want, got)
+		}
+	}
+}
@@ -1,3 +0,0 @@
-module {{PROJECT_NAME}}
-
-go 1.21
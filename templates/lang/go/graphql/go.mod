@@ -0,0 +1,9 @@
+module {{MODULE_PATH}}
+
+go {{GO_VERSION}}
+
+require (
+	github.com/99designs/gqlgen v0.17.55
+	github.com/vektah/gqlparser/v2 v2.5.17
+	github.com/vikstrous/dataloadgen v0.0.6
+)
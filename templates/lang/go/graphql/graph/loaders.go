@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vikstrous/dataloadgen"
+
+	"{{MODULE_PATH}}/graph/model"
+)
+
+// loaders batch and cache the lookups of one request, so a query for
+// fifty todos and their users looks the users up in one call to the
+// store rather than fifty. Each request gets its own, so nothing
+// cached outlives it.
+type loaders struct {
+	users *dataloadgen.Loader[string, *model.User]
+}
+
+type loadersKey struct{}
+
+// withLoaders gives each request new loaders over s.
+func withLoaders(s Store, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := &loaders{
+			// Lookups within a millisecond of the first go out together.
+			users: dataloadgen.NewLoader(s.UsersByID, dataloadgen.WithWait(time.Millisecond)),
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), loadersKey{}, l)))
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
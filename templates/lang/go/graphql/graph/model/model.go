@@ -0,0 +1,18 @@
+// Package model has the Go types of the schema's types. Those here are
+// written by hand and used as they are; gqlgen writes the rest, such as
+// the inputs, to models_gen.go.
+package model
+
+// Todo holds its user's ID rather than the user, whom the todoResolver
+// looks up only when a query asks for them.
+type Todo struct {
+	ID     string `json:"id"`
+	Text   string `json:"text"`
+	Done   bool   `json:"done"`
+	UserID string `json:"userId"`
+}
+
+type User struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,23 @@
+// Package graph serves the schema in schema.graphqls. gqlgen writes
+// generated.go and a stub in schema.resolvers.go for each new field
+// (just generate); the resolvers' bodies are written by hand and kept.
+package graph
+
+import (
+	"context"
+
+	"{{MODULE_PATH}}/graph/model"
+)
+
+// Resolver is the root of every resolver, holding what they share.
+type Resolver struct {
+	Store Store
+}
+
+// Store is what the resolvers need of where the todos are kept;
+// *store.Memory is one.
+type Store interface {
+	Todos(ctx context.Context) ([]*model.Todo, error)
+	CreateTodo(ctx context.Context, text, userID string) (*model.Todo, error)
+	UsersByID(ctx context.Context, ids []string) ([]*model.User, []error)
+}
@@ -0,0 +1,68 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.55
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"{{MODULE_PATH}}/graph/model"
+	"{{MODULE_PATH}}/internal/logging"
+	"{{MODULE_PATH}}/internal/store"
+)
+
+// CreateTodo is the resolver for the createTodo field.
+func (r *mutationResolver) CreateTodo(ctx context.Context, input model.NewTodo) (*model.Todo, error) {
+	switch {
+	case strings.TrimSpace(input.Text) == "":
+		return nil, inputError(ctx, "text is required")
+	case utf8.RuneCountInString(input.Text) > 200:
+		return nil, inputError(ctx, "text must be at most 200 characters")
+	}
+	todo, err := r.Store.CreateTodo(ctx, input.Text, input.UserID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, inputError(ctx, "there is no user %s", input.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The request's logger adds its request_id to every line.
+	logging.FromContext(ctx).Info("created todo", "id", todo.ID)
+	return todo, nil
+}
+
+// Todos is the resolver for the todos field.
+func (r *queryResolver) Todos(ctx context.Context) ([]*model.Todo, error) {
+	return r.Store.Todos(ctx)
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	user, err := loadersFrom(ctx).users.Load(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+// User is the resolver for the user field.
+func (r *todoResolver) User(ctx context.Context, obj *model.Todo) (*model.User, error) {
+	return loadersFrom(ctx).users.Load(ctx, obj.UserID)
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Todo returns TodoResolver implementation.
+func (r *Resolver) Todo() TodoResolver { return &todoResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type todoResolver struct{ *Resolver }
@@ -0,0 +1,64 @@
+// Package api writes the app's JSON responses outside GraphQL itself.
+// Every error goes out in the envelope GraphQL answers with,
+//
+//	{"errors": [{"message": "...", "extensions": {"code": "rate_limited"}}]}
+//
+// so clients handle failures one way whether a resolver or the server
+// around it failed.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// Error is a failed request as the client sees it: the status it is
+// answered with, a code for programs to switch on, a message for
+// people and, for a request that is invalid, what is wrong with each
+// bad field, which go out as the error's extensions.
+type Error struct {
+	Status  int               `json:"-"`
+	Code    string            `json:"code"`
+	Message string            `json:"-"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errorf is an Error with status and code, and a message formatted as
+// fmt.Sprintf does.
+func Errorf(status int, code, format string, args ...any) *Error {
+	return &Error{Status: status, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// JSON answers with status and v as JSON.
+func JSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// WriteError answers with err in the envelope. An *Error (anywhere in
+// err's chain) goes out as it is; anything else is a bug or an outage
+// the client cannot act on, so it is logged and answered with a plain
+// 500 that gives nothing of it away.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		logging.FromContext(r.Context()).Error("internal error", "method", r.Method, "path", r.URL.Path, "err", err)
+		e = Errorf(http.StatusInternalServerError, "internal", "internal server error")
+	}
+	type gqlError struct {
+		Message    string `json:"message"`
+		Extensions *Error `json:"extensions"`
+	}
+	JSON(w, e.Status, struct {
+		Errors []gqlError `json:"errors"`
+	}{[]gqlError{{e.Message, e}}})
+}
@@ -0,0 +1,76 @@
+// Package store keeps the todos and their users in memory. It stands in
+// for a database, with the calls one would answer, so the resolvers do
+// not change when one takes its place.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"{{MODULE_PATH}}/graph/model"
+)
+
+// ErrNotFound is what a lookup of something that is not there fails
+// with, wrapped.
+var ErrNotFound = errors.New("not found")
+
+// Memory is a store that forgets everything when the process exits.
+type Memory struct {
+	mu    sync.Mutex
+	users map[string]model.User
+	todos []model.Todo
+}
+
+// New is a Memory with two users and no todos.
+func New() *Memory {
+	return &Memory{users: map[string]model.User{
+		"1": {ID: "1", Name: "Ada Lovelace"},
+		"2": {ID: "2", Name: "Grace Hopper"},
+	}}
+}
+
+// Todos is every todo, oldest first.
+func (m *Memory) Todos(ctx context.Context) ([]*model.Todo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	todos := make([]*model.Todo, len(m.todos))
+	for i := range m.todos {
+		todo := m.todos[i]
+		todos[i] = &todo
+	}
+	return todos, nil
+}
+
+// CreateTodo adds a todo for the user with userID.
+func (m *Memory) CreateTodo(ctx context.Context, text, userID string) (*model.Todo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[userID]; !ok {
+		return nil, fmt.Errorf("user %s: %w", userID, ErrNotFound)
+	}
+	todo := model.Todo{ID: strconv.Itoa(len(m.todos) + 1), Text: text, UserID: userID}
+	m.todos = append(m.todos, todo)
+	return &todo, nil
+}
+
+// UsersByID looks up the users with ids, in one go: the result and the
+// errors are in the order of ids, each either the user or ErrNotFound.
+// It is the shape a dataloader batches lookups into.
+func (m *Memory) UsersByID(ctx context.Context, ids []string) ([]*model.User, []error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	users := make([]*model.User, len(ids))
+	errs := make([]error, len(ids))
+	for i, id := range ids {
+		user, ok := m.users[id]
+		if !ok {
+			errs[i] = fmt.Errorf("user %s: %w", id, ErrNotFound)
+			continue
+		}
+		users[i] = &user
+	}
+	return users, errs
+}
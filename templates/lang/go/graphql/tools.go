@@ -0,0 +1,7 @@
+//go:build tools
+
+// Package tools pins the gqlgen that just generate runs to go.mod's
+// version; nothing builds it.
+package tools
+
+import _ "github.com/99designs/gqlgen"
@@ -0,0 +1,8 @@
+module {{MODULE_PATH}}
+
+go {{GO_VERSION}}
+
+require (
+	google.golang.org/grpc v1.67.1
+	google.golang.org/protobuf v1.35.1
+)
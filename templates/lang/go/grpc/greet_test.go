@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	greetv1 "{{MODULE_PATH}}/gen/greet/v1"
+	"{{MODULE_PATH}}/internal/config"
+)
+
+// dial serves newServer on an in-memory listener and is a client
+// connection to it, both closed when the test ends.
+func dial(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv, _ := newServer(slog.New(slog.NewTextHandler(io.Discard, nil)), config.Config{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGreet(t *testing.T) {
+	client := greetv1.NewGreetServiceClient(dial(t))
+	tests := []struct {
+		name string
+		want string
+		code codes.Code
+	}{
+		{"Ada", "Hello, Ada!", codes.OK},
+		{"", "Hello, world!", codes.OK},
+		{strings.Repeat("a", 101), "", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		resp, err := client.Greet(context.Background(), &greetv1.GreetRequest{Name: tt.name})
+		if status.Code(err) != tt.code {
+			t.Errorf("Greet(%.10q): expected %s, got %v", tt.name, tt.code, err)
+			continue
+		}
+		if resp.GetMessage() != tt.want {
+			t.Errorf("Greet(%.10q): expected %q, got %q", tt.name, tt.want, resp.GetMessage())
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	client := healthpb.NewHealthClient(dial(t))
+	for _, service := range []string{"", greetv1.GreetService_ServiceDesc.ServiceName} {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("Check(%q): expected SERVING, got %v %v", service, resp.GetStatus(), err)
+		}
+	}
+}
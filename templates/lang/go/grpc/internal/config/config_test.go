@@ -0,0 +1,47 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// required holds the settings that have no default, set for every case.
+var required = map[string]string{}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want func(Config) bool
+		err  string // what the error says, "" for none
+	}{
+		{"defaults", nil, func(c Config) bool {
+			return c.Port == "4000" && c.Env == "development" && c.LogLevel == slog.LevelInfo && c.Reflection
+		}, ""},
+		{"production", map[string]string{"PORT": "50051", "APP_ENV": "production", "LOG_LEVEL": "debug", "GRPC_REFLECTION": "0"}, func(c Config) bool {
+			return c.Port == "50051" && c.Env == "production" && c.LogLevel == slog.LevelDebug && !c.Reflection
+		}, ""},
+		{"bad port", map[string]string{"PORT": "grpc"}, nil, "PORT"},
+		{"bad env", map[string]string{"APP_ENV": "prod"}, nil, "APP_ENV"},
+		{"bad log level", map[string]string{"LOG_LEVEL": "loud"}, nil, "LOG_LEVEL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(func(key string) string {
+				if v, ok := tt.env[key]; ok {
+					return v
+				}
+				return required[key]
+			})
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("expected an error about %s, got %v", tt.err, err)
+			case tt.want != nil && !tt.want(c):
+				t.Errorf("unexpected config: %+v", c)
+			}
+		})
+	}
+}
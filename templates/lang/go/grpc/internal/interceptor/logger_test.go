@@ -0,0 +1,39 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func TestLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(Header, "abc-123"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/greet.v1.GreetService/Greet"}
+	_, err := Logger(logger)(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		logging.FromContext(ctx).Info("inside")
+		return nil, status.Error(codes.NotFound, "no such greeting")
+	})
+
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected the handler's error, got %v", err)
+	}
+	for _, want := range []string{
+		"msg=inside request_id=abc-123",
+		"msg=call request_id=abc-123 method=/greet.v1.GreetService/Greet code=NotFound",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q logged, got %q", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func TestRecover(t *testing.T) {
+	var out bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(&out, nil)))
+	_, err := Recover(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+
+	if status.Code(err) != codes.Internal || strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected an Internal error that gives nothing away, got %v", err)
+	}
+	if !strings.Contains(out.String(), "msg=panic err=boom") {
+		t.Errorf("expected the panic logged, got %q", out.String())
+	}
+}
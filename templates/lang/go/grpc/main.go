@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func main() {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		slog.Error("config", "err", err)
+		os.Exit(1)
+	}
+	logger := logging.New(cfg.Env, cfg.LogLevel, os.Stderr)
+	slog.SetDefault(logger)
+
+	srv, healthSrv := newServer(logger, cfg)
+	lis, err := net.Listen("tcp", ":"+cfg.Port)
+	if err != nil {
+		logger.Error("listen", "err", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		logger.Info("listening", "port", cfg.Port, "reflection", cfg.Reflection)
+		if err := srv.Serve(lis); err != nil {
+			logger.Error("serve", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // a second signal stops the process at once
+	logger.Info("shutting down")
+	// Health checks fail from here, so load balancers stop sending calls,
+	// and calls in flight get this long to finish.
+	healthSrv.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		logger.Error("shutdown", "err", "calls still running after 10s")
+		srv.Stop()
+		os.Exit(1)
+	}
+}
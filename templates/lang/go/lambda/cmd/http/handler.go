@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+
+	"{{MODULE_PATH}}/internal/config"
+)
+
+// maxName is how long a name to greet may be.
+const maxName = 100
+
+// newHandler is the function Lambda calls for each request API Gateway
+// sends it: an HTTP API's, in payload format 2.0. It routes on the
+// method and path itself, so one function serves every route.
+func newHandler(cfg config.Config, logger *slog.Logger) func(context.Context, events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	return func(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+		start := time.Now()
+		resp := route(cfg, req)
+		log := logger
+		if lc, ok := lambdacontext.FromContext(ctx); ok {
+			log = log.With("request_id", lc.AwsRequestID)
+		}
+		log.Info("request",
+			"method", req.RequestContext.HTTP.Method,
+			"path", req.RawPath,
+			"status", resp.StatusCode,
+			"duration", time.Since(start),
+		)
+		// Errors are answered rather than returned: a returned error is
+		// a 500 from API Gateway with nothing of ours in it.
+		return resp, nil
+	}
+}
+
+// route answers req by its method and path.
+func route(cfg config.Config, req events.APIGatewayV2HTTPRequest) events.APIGatewayV2HTTPResponse {
+	method := req.RequestContext.HTTP.Method
+	switch {
+	case req.RawPath == "/hello" && method == http.MethodGet:
+		return greet(cfg, req.QueryStringParameters["name"])
+	case req.RawPath == "/hello" && method == http.MethodPost:
+		body, err := requestBody(req)
+		var in struct {
+			Name string `json:"name"`
+		}
+		if err != nil || json.Unmarshal(body, &in) != nil {
+			return errorResponse(http.StatusBadRequest, `the body must be JSON such as {"name": "Ada"}`)
+		}
+		return greet(cfg, in.Name)
+	case req.RawPath == "/hello":
+		resp := errorResponse(http.StatusMethodNotAllowed, "method not allowed")
+		resp.Headers["Allow"] = "GET, POST"
+		return resp
+	default:
+		return errorResponse(http.StatusNotFound, "not found")
+	}
+}
+
+func greet(cfg config.Config, name string) events.APIGatewayV2HTTPResponse {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "world"
+	}
+	if len(name) > maxName {
+		return errorResponse(http.StatusBadRequest, "the name is too long")
+	}
+	return jsonResponse(http.StatusOK, map[string]string{"message": cfg.Greeting + ", " + name + "!"})
+}
+
+// requestBody is req's body, decoded if API Gateway sent it in base64,
+// as it does for bodies that are not text.
+func requestBody(req events.APIGatewayV2HTTPRequest) ([]byte, error) {
+	if req.IsBase64Encoded {
+		return base64.StdEncoding.DecodeString(req.Body)
+	}
+	return []byte(req.Body), nil
+}
+
+func jsonResponse(status int, v any) events.APIGatewayV2HTTPResponse {
+	body, _ := json.Marshal(v)
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(body),
+	}
+}
+
+func errorResponse(status int, message string) events.APIGatewayV2HTTPResponse {
+	return jsonResponse(status, map[string]string{"error": message})
+}
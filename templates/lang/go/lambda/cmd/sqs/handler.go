@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+
+	"{{MODULE_PATH}}/internal/config"
+)
+
+// newHandler is the function Lambda calls with each batch of messages
+// from the SQS queue. A message that fails is reported back rather than
+// failing the batch, so only it is retried (the event source mapping
+// must list ReportBatchItemFailures, as the deployment does).
+func newHandler(cfg config.Config, logger *slog.Logger) func(context.Context, events.SQSEvent) (events.SQSEventResponse, error) {
+	return func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+		log := logger
+		if lc, ok := lambdacontext.FromContext(ctx); ok {
+			log = log.With("request_id", lc.AwsRequestID)
+		}
+		resp := events.SQSEventResponse{BatchItemFailures: []events.SQSBatchItemFailure{}}
+		for _, msg := range event.Records {
+			if err := process(ctx, cfg, log, msg); err != nil {
+				log.Error("message failed", "message_id", msg.MessageId, "err", err)
+				resp.BatchItemFailures = append(resp.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: msg.MessageId})
+			}
+		}
+		log.Info("batch", "messages", len(event.Records), "failed", len(resp.BatchItemFailures))
+		return resp, nil
+	}
+}
+
+// greeting is the body of the messages the function takes.
+type greeting struct {
+	Name string `json:"name"`
+}
+
+// process handles one message. Put the real work here; an error has the
+// message retried until the queue's redrive policy gives up on it.
+func process(ctx context.Context, cfg config.Config, log *slog.Logger, msg events.SQSMessage) error {
+	var g greeting
+	if err := json.Unmarshal([]byte(msg.Body), &g); err != nil {
+		return fmt.Errorf("the body is not JSON: %w", err)
+	}
+	if g.Name == "" {
+		return errors.New(`the body has no "name"`)
+	}
+	log.Info(cfg.Greeting+", "+g.Name+"!", "message_id", msg.MessageId)
+	return ctx.Err()
+}
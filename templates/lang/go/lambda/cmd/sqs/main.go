@@ -0,0 +1,24 @@
+// Command sqs is the function the SQS queue's messages are sent to, in
+// batches.
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/invoke"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func main() {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		slog.Error("config", "err", err)
+		os.Exit(1)
+	}
+	// Lambda sends what the function writes to CloudWatch Logs.
+	logger := logging.New(cfg.Env, cfg.LogLevel, os.Stderr)
+	slog.SetDefault(logger)
+	invoke.Start(newHandler(cfg, logger))
+}
@@ -0,0 +1,5 @@
+module {{MODULE_PATH}}
+
+go {{GO_VERSION}}
+
+require github.com/aws/aws-lambda-go v1.47.0
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want func(Config) bool
+		err  string // what the error says, "" for none
+	}{
+		{"defaults", nil, func(c Config) bool {
+			return c.Env == "development" && c.LogLevel == slog.LevelInfo && c.Greeting == "Hello"
+		}, ""},
+		{"production", map[string]string{"APP_ENV": "production", "LOG_LEVEL": "debug", "GREETING": "Hi"}, func(c Config) bool {
+			return c.Env == "production" && c.LogLevel == slog.LevelDebug && c.Greeting == "Hi"
+		}, ""},
+		{"bad env", map[string]string{"APP_ENV": "prod"}, nil, "APP_ENV"},
+		{"bad log level", map[string]string{"LOG_LEVEL": "loud"}, nil, "LOG_LEVEL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(func(key string) string { return tt.env[key] })
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("expected an error about %s, got %v", tt.err, err)
+			case tt.want != nil && !tt.want(c):
+				t.Errorf("unexpected config: %+v", c)
+			}
+		})
+	}
+}
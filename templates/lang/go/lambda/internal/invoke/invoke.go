@@ -0,0 +1,62 @@
+// Package invoke starts a function's handler: in Lambda, with the
+// runtime's loop, and anywhere else once, on an event from a file, so a
+// function can be tried without AWS.
+package invoke
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// Start runs handler, a function of the kinds lambda.Start takes. In
+// Lambda, which sets AWS_LAMBDA_RUNTIME_API, it is lambda.Start. Anywhere
+// else it calls handler once with the event in the file named on the
+// command line, or on stdin, prints the response and exits: just invoke.
+func Start(handler any) {
+	if os.Getenv("AWS_LAMBDA_RUNTIME_API") != "" {
+		lambda.Start(handler)
+		return
+	}
+	if err := Local(context.Background(), lambda.NewHandler(handler), os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		slog.Error("invoke", "err", err)
+		os.Exit(1)
+	}
+}
+
+// Local calls h as Lambda would, with the JSON event in the file args
+// names, or on stdin when it names none, and writes the response to
+// stdout, indented.
+func Local(ctx context.Context, h lambda.Handler, args []string, stdin io.Reader, stdout io.Writer) error {
+	var event []byte
+	var err error
+	switch len(args) {
+	case 0:
+		event, err = io.ReadAll(stdin)
+	case 1:
+		event, err = os.ReadFile(args[0])
+	default:
+		return errors.New("usage: go run ./cmd/FUNCTION [EVENT.json]")
+	}
+	if err != nil {
+		return err
+	}
+	response, err := h.Invoke(ctx, event)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if json.Indent(&out, response, "", "  ") != nil {
+		out.Reset()
+		out.Write(response)
+	}
+	out.WriteByte('\n')
+	_, err = out.WriteTo(stdout)
+	return err
+}
@@ -0,0 +1,58 @@
+package invoke
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+func echo(ctx context.Context, event map[string]string) (map[string]string, error) {
+	return event, nil
+}
+
+func TestLocal(t *testing.T) {
+	h := lambda.NewHandler(echo)
+	path := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(path, []byte(`{"from":"file"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  string
+	}{
+		{"stdin", nil, `{"a":"b"}`, "{\n  \"a\": \"b\"\n}\n"},
+		{"file", []string{path}, "", "{\n  \"from\": \"file\"\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := Local(context.Background(), h, tt.args, strings.NewReader(tt.stdin), &out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestLocalErrors(t *testing.T) {
+	h := lambda.NewHandler(echo)
+	var out bytes.Buffer
+	if err := Local(context.Background(), h, []string{"a.json", "b.json"}, nil, &out); err == nil {
+		t.Error("expected an error for two events")
+	}
+	if err := Local(context.Background(), h, []string{filepath.Join(t.TempDir(), "none.json")}, nil, &out); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if err := Local(context.Background(), h, nil, strings.NewReader("not json"), &out); err == nil {
+		t.Error("expected an error for an event that is not JSON")
+	}
+}
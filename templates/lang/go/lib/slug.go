@@ -0,0 +1,98 @@
+package {{PACKAGE}}
+
+import (
+	"strings"
+
+	"{{MODULE_PATH}}/internal/fold"
+)
+
+// Make is s as a slug, by the zero Slugger: words joined by hyphens, of
+// any length. It is "" when s has no letters or digits.
+func Make(s string) string {
+	return Slugger{}.Make(s)
+}
+
+// A Slugger makes slugs its own way. The zero value is ready to use and
+// makes the same slugs as Make.
+type Slugger struct {
+	// Separator goes between words; a hyphen when empty. One with
+	// letters or digits in it makes slugs Valid turns down.
+	Separator string
+
+	// MaxLen, when more than zero, is the most bytes a slug has. A
+	// longer one is cut after the last word that fits, or within its
+	// first word when even that is too long.
+	MaxLen int
+}
+
+// Make is s as a slug. Letters are folded to lower-case ASCII (é to e,
+// ß to ss), apostrophes are dropped and any run of other characters
+// becomes one separator, none at either end.
+func (sl Slugger) Make(s string) string {
+	sep := sl.Separator
+	if sep == "" {
+		sep = "-"
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	owed := false // a separator, before the next word
+	for _, r := range s {
+		if r == '\'' || r == '’' {
+			// don't is dont, not don-t.
+			continue
+		}
+		folded, ok := fold.ASCII(r)
+		if !ok {
+			owed = b.Len() > 0
+			continue
+		}
+		if owed {
+			b.WriteString(sep)
+			owed = false
+		}
+		b.WriteString(folded)
+	}
+	return sl.cut(b.String(), sep)
+}
+
+// cut shortens slug to MaxLen, at a word boundary where it can.
+func (sl Slugger) cut(slug, sep string) string {
+	if sl.MaxLen <= 0 || len(slug) <= sl.MaxLen {
+		return slug
+	}
+	short := slug[:sl.MaxLen]
+	if !strings.HasPrefix(slug[sl.MaxLen:], sep) {
+		// The cut is within a word: drop what fits of it.
+		if i := strings.LastIndex(short, sep); i > 0 {
+			short = short[:i]
+		}
+	}
+	// Drop a separator left at the end, or the part of one the cut
+	// went through.
+	for i := len(sep); i > 0; i-- {
+		if strings.HasSuffix(short, sep[:i]) {
+			return short[:len(short)-i]
+		}
+	}
+	return short
+}
+
+// Valid reports whether s is a slug as Make makes them: one or more
+// words of lower-case ASCII letters and digits, joined by single
+// hyphens.
+func Valid(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, word := range strings.Split(s, "-") {
+		if word == "" {
+			return false
+		}
+		for _, r := range word {
+			if !('a' <= r && r <= 'z' || '0' <= r && r <= '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
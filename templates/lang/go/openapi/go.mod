@@ -0,0 +1,8 @@
+module {{MODULE_PATH}}
+
+go {{GO_VERSION}}
+
+require (
+	github.com/getkin/kin-openapi v0.128.0
+	github.com/oapi-codegen/runtime v1.1.1
+)
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"{{MODULE_PATH}}/internal/api"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// readyTimeout bounds each /readyz probe, so a hung dependency fails
+// the check instead of the prober timing out.
+const readyTimeout = 2 * time.Second
+
+type status struct {
+	Status string `json:"status"`
+}
+
+// handleHealthz answers while the process is up: the liveness probe.
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	api.JSON(w, http.StatusOK, status{"ok"})
+}
+
+// handleReadyz answers 200 when every check passes and 503, with what
+// each that failed said in the error's fields, when one does: the
+// readiness probe, so traffic waits for the app's dependencies.
+func handleReadyz(checks map[string]func(context.Context) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+		failed := map[string]string{}
+		for name, check := range checks {
+			if err := check(ctx); err != nil {
+				failed[name] = err.Error()
+			}
+		}
+		if len(failed) > 0 {
+			logging.FromContext(r.Context()).Warn("not ready", "failed", failed)
+			e := api.Errorf(http.StatusServiceUnavailable, "not_ready", "not ready")
+			e.Fields = failed
+			api.WriteError(w, r, e)
+			return
+		}
+		api.JSON(w, http.StatusOK, status{"ok"})
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func TestWriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	e := Errorf(http.StatusNotFound, "not_found", "no note %d", 7)
+	WriteError(w, httptest.NewRequest("GET", "/api/v1/notes/7", nil), fmt.Errorf("finding note: %w", e))
+
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("expected a JSON 404, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != `{"error":{"code":"not_found","message":"no note 7"}}` {
+		t.Errorf("unexpected envelope %s", body)
+	}
+}
+
+func TestWriteErrorHidesInternalErrors(t *testing.T) {
+	var out bytes.Buffer
+	req := httptest.NewRequest("GET", "/api/v1/notes", nil)
+	req = req.WithContext(logging.NewContext(req.Context(), slog.New(slog.NewTextHandler(&out, nil))))
+	w := httptest.NewRecorder()
+	WriteError(w, req, errors.New("dial tcp: connection refused"))
+
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "refused") {
+		t.Errorf("expected a 500 that gives nothing away, got %d %s", w.Code, w.Body)
+	}
+	if !strings.Contains(out.String(), "connection refused") {
+		t.Errorf("expected the error logged, got %q", out.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	JSON(w, http.StatusCreated, map[string]int{"id": 1})
+
+	if w.Code != http.StatusCreated || strings.TrimSpace(w.Body.String()) != `{"id":1}` {
+		t.Errorf("expected a 201 with the value, got %d %s", w.Code, w.Body)
+	}
+}
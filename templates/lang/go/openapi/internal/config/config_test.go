@@ -0,0 +1,78 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// required holds the settings that have no default, set for every case.
+var required = map[string]string{}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want func(Config) bool
+		err  string // what the error says, "" for none
+	}{
+		{"defaults", nil, func(c Config) bool {
+			return c.Port == "4000" && c.Env == "development" && c.LogLevel == slog.LevelInfo &&
+				c.BaseURL == "http://localhost:4000" && c.Profile &&
+				c.RateLimit == 0 && c.RateBurst == 20 && strings.Join(c.CORSOrigins, " ") == "*" && len(c.CORSMethods) == 5
+		}, ""},
+		{"port", map[string]string{"PORT": "3000"}, func(c Config) bool {
+			return c.Port == "3000" && c.BaseURL == "http://localhost:3000"
+		}, ""},
+		{"production", map[string]string{"APP_ENV": "production", "LOG_LEVEL": "debug", "APP_PROFILE": "0"}, func(c Config) bool {
+			return c.Env == "production" && c.LogLevel == slog.LevelDebug && !c.Profile
+		}, ""},
+		{"base URL", map[string]string{"BASE_URL": "https://example.com/"}, func(c Config) bool {
+			return c.BaseURL == "https://example.com"
+		}, ""},
+		{"rate limit", map[string]string{"RATE_LIMIT": "2.5", "RATE_BURST": "5"}, func(c Config) bool {
+			return c.RateLimit == 2.5 && c.RateBurst == 5
+		}, ""},
+		{"CORS", map[string]string{"CORS_ORIGINS": "https://a.example, http://localhost:5173", "CORS_HEADERS": "X-Api-Key"}, func(c Config) bool {
+			return strings.Join(c.CORSOrigins, " ") == "https://a.example http://localhost:5173" &&
+				strings.Join(c.CORSHeaders, " ") == "X-Api-Key"
+		}, ""},
+		{"no CORS", map[string]string{"CORS_ORIGINS": "none"}, func(c Config) bool {
+			return c.CORSOrigins == nil
+		}, ""},
+		{"limits", map[string]string{"REQUEST_TIMEOUT": "5s", "WRITE_TIMEOUT": "10s", "MAX_BODY_BYTES": "1048576"}, func(c Config) bool {
+			return c.RequestTimeout == 5*time.Second && c.WriteTimeout == 10*time.Second && c.MaxBodyBytes == 1<<20 &&
+				c.ReadHeaderTimeout == 5*time.Second && c.IdleTimeout == 2*time.Minute && c.MaxHeaderBytes == 64<<10
+		}, ""},
+		{"bad port", map[string]string{"PORT": "http"}, nil, "PORT"},
+		{"port out of range", map[string]string{"PORT": "70000"}, nil, "PORT"},
+		{"bad env", map[string]string{"APP_ENV": "prod"}, nil, "APP_ENV"},
+		{"bad log level", map[string]string{"LOG_LEVEL": "loud"}, nil, "LOG_LEVEL"},
+		{"bad base URL", map[string]string{"BASE_URL": "example.com"}, nil, "BASE_URL"},
+		{"bad rate limit", map[string]string{"RATE_LIMIT": "-1"}, nil, "RATE_LIMIT"},
+		{"bad rate burst", map[string]string{"RATE_BURST": "0"}, nil, "RATE_BURST"},
+		{"bad CORS origin", map[string]string{"CORS_ORIGINS": "https://a.example/app"}, nil, "CORS_ORIGINS"},
+		{"bad timeout", map[string]string{"READ_TIMEOUT": "10"}, nil, "READ_TIMEOUT"},
+		{"request timeout past the write one", map[string]string{"REQUEST_TIMEOUT": "2m"}, nil, "REQUEST_TIMEOUT"},
+		{"bad body limit", map[string]string{"MAX_BODY_BYTES": "1MB"}, nil, "MAX_BODY_BYTES"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(func(key string) string {
+				if v, ok := tt.env[key]; ok {
+					return v
+				}
+				return required[key]
+			})
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("expected an error about %s, got %v", tt.err, err)
+			case tt.want != nil && !tt.want(c):
+				t.Errorf("unexpected config: %+v", c)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+// Package oapi is the Go side of openapi.yaml, the spec beside it: its
+// schemas as types, StrictServerInterface with a method for each
+// operation, and the handler that routes requests to them. oapi-codegen
+// writes it to oapi.gen.go (just generate), so change the spec rather
+// than the file.
+package oapi
+
+import _ "embed"
+
+// Spec is openapi.yaml, the contract the API is generated from and
+// checked against.
+//
+//go:embed openapi.yaml
+var Spec []byte
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"{{MODULE_PATH}}/internal/api"
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/logging"
+	"{{MODULE_PATH}}/internal/middleware"
+)
+
+func main() {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		slog.Error("config", "err", err)
+		os.Exit(1)
+	}
+	logger := logging.New(cfg.Env, cfg.LogLevel, os.Stderr)
+	slog.SetDefault(logger)
+
+	// What /readyz waits on: a check for each dependency, by name.
+	ready := map[string]func(context.Context) error{}
+
+	spec, err := loadSpec()
+	if err != nil {
+		logger.Error("openapi.yaml", "err", err)
+		os.Exit(1)
+	}
+	apiHandler, err := newAPI(spec)
+	if err != nil {
+		logger.Error("routes", "err", err)
+		os.Exit(1)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", apiHandler)
+	mux.HandleFunc("GET /openapi.yaml", handleSpec)
+	mux.HandleFunc("GET /healthz", handleHealthz)
+	mux.HandleFunc("GET /readyz", handleReadyz(ready))
+	mux.HandleFunc("/", handleNotFound)
+	if cfg.Profile {
+		mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+		mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	}
+
+	// Request IDs first so the log lines carry them; recovery inside the
+	// logger so a panic is logged as the 500 it becomes; the checks inside
+	// both, so what they turn away is logged too, and CORS ahead of the
+	// rate limit so a page can read the 429 it gets.
+	handler := middleware.Chain(mux,
+		middleware.RequestID,
+		middleware.Logger(logger),
+		middleware.Recover,
+		middleware.Timeout(cfg.RequestTimeout, "/debug/pprof/"),
+		middleware.MaxBytes(cfg.MaxBodyBytes),
+		middleware.Under("/api/", middleware.CORS(cfg.CORSOrigins, cfg.CORSMethods, cfg.CORSHeaders)),
+		middleware.Under("/api/", middleware.RateLimit(cfg.RateLimit, cfg.RateBurst)),
+	)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		logger.Info("listening", "port", cfg.Port, "url", cfg.BaseURL)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("serve", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // a second signal stops the process at once
+	logger.Info("shutting down")
+	// In-flight requests get this long to finish.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("shutdown", "err", err)
+		os.Exit(1)
+	}
+}
+
+// handleNotFound answers what no route matches, in the envelope like
+// every other error.
+func handleNotFound(w http.ResponseWriter, r *http.Request) {
+	api.WriteError(w, r, api.Errorf(http.StatusNotFound, "not_found", "no route for %s %s", r.Method, r.URL.Path))
+}
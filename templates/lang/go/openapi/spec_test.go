@@ -0,0 +1,89 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// TestResponsesMatchSpec sends requests through the API and checks
+// that each answer is one openapi.yaml allows, errors included.
+func TestResponsesMatchSpec(t *testing.T) {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatalf("openapi.yaml: %v", err)
+	}
+	h, err := newAPI(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		status       int
+		want         string // in the response
+	}{
+		{"greet the world", "GET", "/api/v1/greet", "", http.StatusOK, `"message":"Hello, world!"`},
+		{"greet by name", "GET", "/api/v1/greet?name=Ada", "", http.StatusOK, `"message":"Hello, Ada!"`},
+		{"blank name", "GET", "/api/v1/greet?name=", "", http.StatusUnprocessableEntity, `"name":`},
+		{"greet from the body", "POST", "/api/v1/greet", `{"name": "Ada"}`, http.StatusOK, `"message":"Hello, Ada!"`},
+		{"missing name", "POST", "/api/v1/greet", `{}`, http.StatusUnprocessableEntity, `"code":"invalid"`},
+		{"unknown field", "POST", "/api/v1/greet", `{"name": "Ada", "age": 36}`, http.StatusUnprocessableEntity, `"code":"invalid"`},
+		{"broken JSON", "POST", "/api/v1/greet", `{"name": `, http.StatusBadRequest, `"code":"bad_request"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.want) {
+				t.Fatalf("expected %d with %s, got %d %s", tt.status, tt.want, w.Code, w.Body)
+			}
+			route, params, err := router.FindRoute(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = openapi3filter.ValidateResponse(req.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{Request: req, PathParams: params, Route: route},
+				Status:                 w.Code,
+				Header:                 w.Header(),
+				Body:                   io.NopCloser(w.Body),
+			})
+			if err != nil {
+				t.Errorf("response does not match openapi.yaml: %v", err)
+			}
+		})
+	}
+}
+
+func TestUndescribedRequests(t *testing.T) {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatalf("openapi.yaml: %v", err)
+	}
+	h, err := newAPI(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, status := range map[string]int{"/api/v1/greet": http.StatusMethodNotAllowed, "/api/v2/greet": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("DELETE", path, nil))
+		if w.Code != status || !strings.Contains(w.Body.String(), `"error":`) {
+			t.Errorf("DELETE %s: expected %d in the envelope, got %d %s", path, status, w.Code, w.Body)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The markers (+kubebuilder:...) are read by controller-gen: just
+// generate writes the CRD in config/crd/bases and the deep copies in
+// zz_generated.deepcopy.go from them.
+
+// ConditionReady is the condition saying whether a Greeting's ConfigMap
+// is up to date with its spec.
+const ConditionReady = "Ready"
+
+// GreetingSpec is what the user asks for.
+type GreetingSpec struct {
+	// Message is what the Greeting's ConfigMap holds, under "message".
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=1024
+	Message string `json:"message"`
+}
+
+// GreetingStatus is what the operator last did about it.
+type GreetingStatus struct {
+	// ConfigMap is the name of the ConfigMap holding the message, in the
+	// Greeting's namespace.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// ObservedGeneration is the generation of the spec last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions are the latest observations of the Greeting's state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Greeting is a message the operator keeps in a ConfigMap of its own.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.spec.message`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type Greeting struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GreetingSpec   `json:"spec"`
+	Status GreetingStatus `json:"status,omitempty"`
+}
+
+// GreetingList is a list of Greetings.
+// +kubebuilder:object:root=true
+type GreetingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Greeting `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Greeting{}, &GreetingList{})
+}
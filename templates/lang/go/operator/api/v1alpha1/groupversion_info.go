@@ -0,0 +1,21 @@
+// Package v1alpha1 is the v1alpha1 version of the {{GROUP}} API: the
+// custom resources the operator reconciles.
+// +kubebuilder:object:generate=true
+// +groupName={{GROUP}}
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is the group and version the types here are served as.
+	GroupVersion = schema.GroupVersion{Group: "{{GROUP}}", Version: "v1alpha1"}
+
+	// SchemeBuilder registers the types here with a runtime.Scheme.
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the types here to a scheme, for a client or manager.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
@@ -0,0 +1,91 @@
+// Command manager runs the operator: the reconcilers in
+// internal/controller, under a controller-runtime manager that serves
+// metrics and health probes and, with LEADER_ELECT, makes sure only one
+// replica reconciles at a time.
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"{{MODULE_PATH}}/api/v1alpha1"
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/controller"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// scheme knows the built-in kinds and this operator's own.
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+}
+
+func main() {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		slog.Error("config", "err", err)
+		os.Exit(1)
+	}
+	logger := logging.New(cfg.Env, cfg.LogLevel, os.Stderr)
+	slog.SetDefault(logger)
+	// controller-runtime logs through logr; this sends it to the same
+	// handler, so its lines look like the operator's own.
+	ctrl.SetLogger(logr.FromSlogHandler(logger.Handler()))
+
+	// The kubeconfig (KUBECONFIG or ~/.kube/config) when run by hand,
+	// the pod's service account in the cluster.
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		logger.Error("kubeconfig", "err", err)
+		os.Exit(1)
+	}
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: cfg.MetricsAddr},
+		HealthProbeBindAddress: cfg.ProbeAddr,
+		LeaderElection:         cfg.LeaderElect,
+		LeaderElectionID:       "{{GROUP}}",
+		// The lease is handed over at once on shutdown rather than when
+		// it expires; safe because the process exits right after.
+		LeaderElectionReleaseOnCancel: true,
+	})
+	if err != nil {
+		logger.Error("manager", "err", err)
+		os.Exit(1)
+	}
+
+	if err := (&controller.GreetingReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		logger.Error("controller", "controller", "greeting", "err", err)
+		os.Exit(1)
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		logger.Error("health check", "err", err)
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		logger.Error("ready check", "err", err)
+		os.Exit(1)
+	}
+
+	logger.Info("starting manager", "metrics", cfg.MetricsAddr, "probes", cfg.ProbeAddr, "leader_elect", cfg.LeaderElect)
+	// SIGTERM or SIGINT stops the reconcilers and releases the lease; a
+	// second one exits at once.
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		logger.Error("manager", "err", err)
+		os.Exit(1)
+	}
+}
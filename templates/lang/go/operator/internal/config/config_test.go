@@ -0,0 +1,45 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want func(Config) bool
+		err  string // what the error says, "" for none
+	}{
+		{"defaults", nil, func(c Config) bool {
+			return c.Env == "development" && c.LogLevel == slog.LevelInfo &&
+				c.MetricsAddr == ":8080" && c.ProbeAddr == ":8081" && !c.LeaderElect
+		}, ""},
+		{"production", map[string]string{"APP_ENV": "production", "LOG_LEVEL": "debug", "LEADER_ELECT": "true"}, func(c Config) bool {
+			return c.Env == "production" && c.LogLevel == slog.LevelDebug && c.LeaderElect
+		}, ""},
+		{"no metrics", map[string]string{"METRICS_ADDR": "0", "PROBE_ADDR": "127.0.0.1:9000"}, func(c Config) bool {
+			return c.MetricsAddr == "0" && c.ProbeAddr == "127.0.0.1:9000"
+		}, ""},
+		{"bad env", map[string]string{"APP_ENV": "prod"}, nil, "APP_ENV"},
+		{"bad log level", map[string]string{"LOG_LEVEL": "loud"}, nil, "LOG_LEVEL"},
+		{"bad metrics address", map[string]string{"METRICS_ADDR": "8080"}, nil, "METRICS_ADDR"},
+		{"bad probe address", map[string]string{"PROBE_ADDR": "0"}, nil, "PROBE_ADDR"},
+		{"bad leader election", map[string]string{"LEADER_ELECT": "maybe"}, nil, "LEADER_ELECT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(func(key string) string { return tt.env[key] })
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("expected an error about %s, got %v", tt.err, err)
+			case tt.want != nil && !tt.want(c):
+				t.Errorf("unexpected config: %+v", c)
+			}
+		})
+	}
+}
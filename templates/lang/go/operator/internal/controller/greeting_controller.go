@@ -0,0 +1,104 @@
+// Package controller holds the reconcilers: each watches one kind of
+// resource and makes the cluster match what its objects ask for.
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"{{MODULE_PATH}}/api/v1alpha1"
+)
+
+// GreetingReconciler keeps a ConfigMap for each Greeting, holding its
+// message. The ConfigMap is owned by the Greeting, so it is deleted with
+// it, and a change to it by hand is put back.
+type GreetingReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// The rbac markers are the permissions the manager's ClusterRole grants:
+// just generate writes them to config/rbac/role.yaml.
+
+// +kubebuilder:rbac:groups={{GROUP}},resources=greetings,verbs=get;list;watch
+// +kubebuilder:rbac:groups={{GROUP}},resources=greetings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups={{GROUP}},resources=greetings/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile brings the ConfigMap of the Greeting named in req up to date
+// and records that in the Greeting's status. It is called for every
+// change to a Greeting or to a ConfigMap one owns, and again, with
+// backoff, when it returns an error.
+func (r *GreetingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var greeting v1alpha1.Greeting
+	if err := r.Get(ctx, req.NamespacedName, &greeting); err != nil {
+		// Gone: its ConfigMap goes too, by the owner reference.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      greeting.Name + "-greeting",
+		Namespace: greeting.Namespace,
+	}}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.Data = map[string]string{"message": greeting.Spec.Message}
+		return controllerutil.SetControllerReference(&greeting, cm, r.Scheme)
+	})
+	if err != nil {
+		meta.SetStatusCondition(&greeting.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ConfigMapFailed",
+			Message:            err.Error(),
+			ObservedGeneration: greeting.Generation,
+		})
+		// The error is what gets the request retried; a failure to
+		// record it is only logged.
+		if serr := r.Status().Update(ctx, &greeting); serr != nil {
+			logger.Error(serr, "recording the failure")
+		}
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		logger.Info("configmap", "name", cm.Name, "operation", op)
+	}
+
+	greeting.Status.ConfigMap = cm.Name
+	greeting.Status.ObservedGeneration = greeting.Generation
+	meta.SetStatusCondition(&greeting.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ConfigMapUpToDate",
+		Message:            "ConfigMap " + cm.Name + " holds the message",
+		ObservedGeneration: greeting.Generation,
+	})
+	if err := r.Status().Update(ctx, &greeting); err != nil {
+		if apierrors.IsConflict(err) {
+			// Changed since it was read; again, with the new version.
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager starts the reconciler with mgr, watching Greetings
+// and the ConfigMaps they own.
+func (r *GreetingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Greeting{}).
+		Owns(&corev1.ConfigMap{}).
+		Named("greeting").
+		Complete(r)
+}
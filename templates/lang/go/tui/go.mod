@@ -0,0 +1,9 @@
+module {{MODULE_PATH}}
+
+go {{GO_VERSION}}
+
+require (
+	github.com/charmbracelet/bubbles v0.20.0
+	github.com/charmbracelet/bubbletea v1.1.1
+	github.com/charmbracelet/lipgloss v0.13.0
+)
@@ -0,0 +1,29 @@
+package tui
+
+import "github.com/charmbracelet/bubbles/key"
+
+// keyMap is the keys the program answers to. It is a help.KeyMap, so
+// the help at the bottom of the view lists them from here and cannot
+// fall out of step.
+type keyMap struct {
+	Up, Down, Toggle, Help, Quit key.Binding
+}
+
+var keys = keyMap{
+	Up:     key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "move up")),
+	Down:   key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "move down")),
+	Toggle: key.NewBinding(key.WithKeys(" ", "x"), key.WithHelp("space", "check off")),
+	Help:   key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "more keys")),
+	Quit:   key.NewBinding(key.WithKeys("q", "esc", "ctrl+c"), key.WithHelp("q", "quit")),
+}
+
+// ShortHelp is the keys help shows on one line.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Toggle, k.Help, k.Quit}
+}
+
+// FullHelp is the keys help shows once ? asks for them all, a column
+// to each group.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.Up, k.Down}, {k.Toggle}, {k.Help, k.Quit}}
+}
@@ -0,0 +1,19 @@
+package tui
+
+import "github.com/charmbracelet/lipgloss"
+
+// accent is the colour of the title and cursor, darker on a light
+// terminal so it reads on both.
+var accent = lipgloss.AdaptiveColor{Light: "#5A3FC0", Dark: "#7D56F4"}
+
+// styles are how the view looks. Colours fall back to what the terminal
+// can show, and to none when the output is not a terminal.
+var styles = struct {
+	app, title, cursor, done, faint lipgloss.Style
+}{
+	app:    lipgloss.NewStyle().Padding(1, 2),
+	title:  lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(accent).Padding(0, 1),
+	cursor: lipgloss.NewStyle().Foreground(accent).Bold(true),
+	done:   lipgloss.NewStyle().Strikethrough(true).Foreground(lipgloss.AdaptiveColor{Light: "250", Dark: "241"}),
+	faint:  lipgloss.NewStyle().Faint(true),
+}
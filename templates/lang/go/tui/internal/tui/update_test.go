@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// press is keys as the messages bubbletea sends for them: up and down
+// by name, anything else typed.
+func press(keys ...string) []tea.Msg {
+	msgs := make([]tea.Msg, len(keys))
+	for i, k := range keys {
+		switch k {
+		case "up":
+			msgs[i] = tea.KeyMsg{Type: tea.KeyUp}
+		case "down":
+			msgs[i] = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msgs[i] = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+	}
+	return msgs
+}
+
+// update runs msgs through m's Update in turn.
+func update(m Model, msgs ...tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, msg := range msgs {
+		var next tea.Model
+		next, cmd = m.Update(msg)
+		m = next.(Model)
+	}
+	return m, cmd
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []string
+		cursor int
+		done   []string
+	}{
+		{"nothing", nil, 0, nil},
+		{"down", []string{"down", "j"}, 2, nil},
+		{"past the end", []string{"down", "down", "down", "down"}, 2, nil},
+		{"past the start", []string{"up", "k"}, 0, nil},
+		{"check off", []string{" ", "j", "x"}, 1, []string{"Milk", "Eggs"}},
+		{"and back", []string{" ", " "}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := update(New([]string{"Milk", "Eggs", "Bread"}), press(tt.keys...)...)
+			if m.cursor != tt.cursor || !slices.Equal(m.Done(), tt.done) {
+				t.Errorf("expected the cursor on %d and %v done, got %d and %v", tt.cursor, tt.done, m.cursor, m.Done())
+			}
+		})
+	}
+}
+
+func TestUpdateLeavesTheModelAlone(t *testing.T) {
+	before := New([]string{"Milk"})
+	update(before, press(" ")...)
+	if len(before.Done()) != 0 {
+		t.Errorf("expected the first model unchanged, got %v done", before.Done())
+	}
+}
+
+func TestUpdateWithoutItems(t *testing.T) {
+	m, _ := update(New(nil), press("down", " ", "up")...)
+	if m.cursor != 0 || !strings.Contains(m.View(), "Nothing to do") {
+		t.Errorf("expected an empty list to stay put, got the cursor on %d", m.cursor)
+	}
+}
+
+func TestQuit(t *testing.T) {
+	for _, k := range []string{"q", "ctrl+c"} {
+		msg := press(k)[0]
+		if k == "ctrl+c" {
+			msg = tea.KeyMsg{Type: tea.KeyCtrlC}
+		}
+		m, cmd := update(New([]string{"Milk"}), msg)
+		if cmd == nil || cmd() != tea.Quit() || m.View() != "" {
+			t.Errorf("%s: expected a quit and nothing left on screen", k)
+		}
+	}
+}
+
+func TestView(t *testing.T) {
+	m, _ := update(New([]string{"Milk", "Eggs"}), press("j", " ")...)
+	view := m.View()
+	for _, want := range []string{"{{PROJECT_NAME}}", "[ ] Milk", "> ", "[x] ", "Eggs", "check off"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the view, got\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "move down") {
+		t.Errorf("expected the short help, got\n%s", view)
+	}
+	if m, _ = update(m, press("?")...); !strings.Contains(m.View(), "move down") {
+		t.Errorf("expected ? to show every key, got\n%s", m.View())
+	}
+}
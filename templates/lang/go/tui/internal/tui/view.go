@@ -0,0 +1,29 @@
+package tui
+
+import "strings"
+
+// View draws the model. It is called after every Update, so it only
+// reads the model; working anything out belongs in Update.
+func (m Model) View() string {
+	if m.quitting {
+		// Leave nothing behind on the screen.
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(styles.title.Render(title) + "\n\n")
+	if len(m.items) == 0 {
+		b.WriteString(styles.faint.Render("Nothing to do.") + "\n")
+	}
+	for i, it := range m.items {
+		cursor, check, line := "  ", "[ ] ", it.title
+		if i == m.cursor {
+			cursor = styles.cursor.Render("> ")
+		}
+		if it.done {
+			check, line = "[x] ", styles.done.Render(line)
+		}
+		b.WriteString(cursor + check + line + "\n")
+	}
+	b.WriteString("\n" + m.help.View(m.keys))
+	return styles.app.Render(b.String())
+}
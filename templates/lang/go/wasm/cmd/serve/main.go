@@ -0,0 +1,47 @@
+// Command serve serves the built site (dist/) for development, uncached,
+// so a reload picks up each build. Any static file server does in
+// production; it only needs to send .wasm files as application/wasm.
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	dir := flag.String("dir", "dist", "the directory to serve")
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	addr := flag.String("addr", "localhost:"+port, "where to listen")
+	flag.Parse()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler(*dir),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	slog.Info("serving", "dir", *dir, "url", "http://"+*addr)
+	if err := srv.ListenAndServe(); err != nil {
+		slog.Error("serve", "err", err)
+		os.Exit(1)
+	}
+}
+
+// handler serves the files in dir, each with Cache-Control: no-store.
+func handler(dir string) http.Handler {
+	files := http.FileServer(http.Dir(dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		// WebAssembly.instantiateStreaming refuses any other type.
+		if strings.HasSuffix(r.URL.Path, ".wasm") {
+			w.Header().Set("Content-Type", "application/wasm")
+		}
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"index.html": "<!doctype html><title>app</title>",
+		"main.wasm":  "\x00asm",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := handler(dir)
+
+	tests := []struct {
+		path, contentType string
+		status            int
+	}{
+		{"/", "text/html", http.StatusOK},
+		{"/main.wasm", "application/wasm", http.StatusOK},
+		{"/missing.js", "text/plain", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("expected Content-Type %s, got %s", tt.contentType, got)
+			}
+			if got := w.Header().Get("Cache-Control"); tt.status == http.StatusOK && got != "no-store" {
+				t.Errorf("expected Cache-Control: no-store, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Package greet is what the page says. It is plain Go, without
+// syscall/js, so it is tested with go test like any other package; keep
+// the app's logic here and its DOM code in cmd/app.
+package greet
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Message greets name, or the world when it is blank.
+func Message(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "world"
+	}
+	return "Hello, " + name + "!"
+}
+
+// Waves says how many times the button has been pressed.
+func Waves(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return "You waved once."
+	default:
+		return "You waved " + strconv.Itoa(n) + " times."
+	}
+}
@@ -0,0 +1,33 @@
+package greet
+
+import "testing"
+
+func TestMessage(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"Ada", "Hello, Ada!"},
+		{"  Grace ", "Hello, Grace!"},
+		{"", "Hello, world!"},
+		{"   ", "Hello, world!"},
+	}
+	for _, tt := range tests {
+		if got := Message(tt.name); got != tt.want {
+			t.Errorf("Message(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWaves(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, ""},
+		{1, "You waved once."},
+		{3, "You waved 3 times."},
+	}
+	for _, tt := range tests {
+		if got := Waves(tt.n); got != tt.want {
+			t.Errorf("Waves(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package main
+
+import "net/http"
+
+func handle{{NAME | pascal}}(w http.ResponseWriter, r *http.Request) {
+	renderPage(w, r, "{{NAME | snake}}.html", pageData{Title: "{{NAME | title}}"})
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test{{NAME | pascal}}Handler(t *testing.T) {
+	req := httptest.NewRequest("GET", "{{route}}", nil)
+	w := httptest.NewRecorder()
+	handle{{NAME | pascal}}(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+}
@@ -0,0 +1,20 @@
+// Package web is the files built into the binary: static/, which
+// package static serves, and the html/template files in templates/.
+// They live here rather than beside main.go so that a main package
+// anywhere in the module, under cmd/ too, can use them.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:static templates
+var files embed.FS
+
+// Static is static/ as it was when the binary was built.
+var Static, _ = fs.Sub(files, "static") // fails only for a bad path
+
+// Templates is templates/: layouts/, partials/ and a file per page in
+// pages/.
+var Templates, _ = fs.Sub(files, "templates")
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"{{MODULE_PATH}}/internal/config"
+	"{{MODULE_PATH}}/internal/logging"
+	"{{MODULE_PATH}}/internal/middleware"
+	"{{MODULE_PATH}}/internal/static"
+	"{{MODULE_PATH}}/internal/web"
+)
+
+func main() {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		slog.Error("config", "err", err)
+		os.Exit(1)
+	}
+	logger := logging.New(cfg.Env, cfg.LogLevel, os.Stderr)
+	slog.SetDefault(logger)
+	static.Use(web.Static, cfg.Env == "development")
+
+	// What /readyz waits on; the database addons add theirs.
+	ready := map[string]func(context.Context) error{}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /static/", static.Handler())
+	mux.HandleFunc("GET /", handleHome)
+	mux.HandleFunc("GET /api/greet", handleGreet)
+	mux.HandleFunc("GET /healthz", handleHealthz)
+	mux.HandleFunc("GET /readyz", handleReadyz(ready))
+	if cfg.Profile {
+		mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+		mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	}
+
+	// Request IDs first so the log lines carry them; recovery inside the
+	// logger so a panic is logged as the 500 it becomes, and the limits
+	// inside both, so what they turn away is logged too.
+	handler := middleware.Chain(mux,
+		middleware.RequestID,
+		middleware.Logger(logger),
+		middleware.Recover,
+		middleware.Timeout(cfg.RequestTimeout, "/debug/pprof/"),
+		middleware.MaxBytes(cfg.MaxBodyBytes),
+	)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		logger.Info("listening", "port", cfg.Port, "url", cfg.BaseURL)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("serve", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // a second signal stops the process at once
+	logger.Info("shutting down")
+	// In-flight requests get this long to finish.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("shutdown", "err", err)
+		os.Exit(1)
+	}
+}
+
+func handleHome(w http.ResponseWriter, r *http.Request) {
+	renderPage(w, r, "home.html", pageData{Title: "Home"})
+}
+
+func handleGreet(w http.ResponseWriter, r *http.Request) {
+	message := "Hello from the server!"
+	// The request's logger adds its request_id to every line.
+	logging.FromContext(r.Context()).Info("greeting", "message", message)
+	renderPartial(w, r, "greeting", message)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+
+	"{{MODULE_PATH}}/internal/logging"
+	"{{MODULE_PATH}}/internal/static"
+	"{{MODULE_PATH}}/internal/web"
+)
+
+// partials holds the layouts and partials; pages holds each file in
+// internal/web/templates/pages/ parsed over a copy of them, so every
+// page can fill the layout's blocks without clashing with the others.
+var partials, pages = parseTemplates(web.Templates)
+
+// pageData is what the layout reads; pages embed it in their own data.
+type pageData struct {
+	Title string
+}
+
+// funcs are the functions templates may call: {{static "app.css"}} is
+// the file's URL with the hash of its content.
+var funcs = template.FuncMap{"static": static.URL}
+
+func parseTemplates(files fs.FS) (*template.Template, map[string]*template.Template) {
+	shared := template.New("").Funcs(funcs)
+	shared = template.Must(shared.ParseFS(files, "layouts/*.html", "partials/*.html"))
+	names, err := fs.Glob(files, "pages/*.html")
+	if err != nil {
+		panic(err)
+	}
+	pages := map[string]*template.Template{}
+	for _, name := range names {
+		page := template.Must(shared.Clone())
+		pages[path.Base(name)] = template.Must(page.ParseFS(files, name))
+	}
+	return shared, pages
+}
+
+// renderPage writes pages/NAME inside the base layout.
+func renderPage(w http.ResponseWriter, r *http.Request, name string, data any) {
+	page, ok := pages[name]
+	if !ok {
+		serverError(w, r, fmt.Errorf("no page %q", name))
+		return
+	}
+	render(w, r, page, "base", data)
+}
+
+// renderPartial writes the partial NAME (a {{define}} in partials/)
+// alone, the fragment htmx swaps in.
+func renderPartial(w http.ResponseWriter, r *http.Request, name string, data any) {
+	render(w, r, partials, name, data)
+}
+
+// render executes into a buffer first, so a template error is a 500
+// rather than half a page.
+func render(w http.ResponseWriter, r *http.Request, t *template.Template, name string, data any) {
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+		serverError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
+
+func serverError(w http.ResponseWriter, r *http.Request, err error) {
+	logging.FromContext(r.Context()).Error("render", "err", err)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"{{MODULE_PATH}}/components"
+	"{{MODULE_PATH}}/internal/flash"
+	"{{MODULE_PATH}}/internal/form"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func handleContactForm(w http.ResponseWriter, r *http.Request) {
+	sent := flash.Pop(w, r)
+	components.Page("Contact", components.Contact(form.New(nil), sent)).Render(r.Context(), w)
+}
+
+// handleContact takes a message from the contact form and hands it to
+// deliver. A form with a problem is shown again, filled in, with each
+// problem beside its field, as is a good one deliver fails with; once
+// delivered, the browser is sent back to the form, where a flash
+// message says it went, so reloading the page does not post it twice.
+func handleContact(deliver func(context.Context, *form.Form) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Bad Request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		f := form.New(r.PostForm)
+		f.Required("name", "email", "message")
+		f.MaxLength("name", 100)
+		f.Email("email")
+		f.MaxLength("message", 2000)
+		if !f.Valid() {
+			showContact(w, r, f, http.StatusUnprocessableEntity)
+			return
+		}
+		if err := deliver(r.Context(), f); err != nil {
+			logging.FromContext(r.Context()).Error("contact", "err", err)
+			f.Check(false, "message", "This could not be sent just now; try again in a little while.")
+			showContact(w, r, f, http.StatusServiceUnavailable)
+			return
+		}
+		flash.Set(w, "Thanks, "+f.Get("name")+". Your message is on its way.")
+		http.Redirect(w, r, "/contact", http.StatusSeeOther)
+	}
+}
+
+func showContact(w http.ResponseWriter, r *http.Request, f *form.Form, status int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	components.Page("Contact", components.Contact(f, "")).Render(r.Context(), w)
+}
+
+// logContact is where the contact form's messages go for now: the log,
+// which says who sent them. Where they should go is the app's to decide.
+func logContact(ctx context.Context, f *form.Form) error {
+	logging.FromContext(ctx).Info("contact", "name", f.Get("name"), "email", f.Get("email"))
+	return nil
+}
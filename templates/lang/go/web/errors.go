@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"{{MODULE_PATH}}/components"
+)
+
+// handleNotFound answers every GET no other route matches.
+func handleNotFound(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	components.Page("Page not found", components.NotFound(r.URL.Path)).Render(r.Context(), w)
+}
+
+// crashPage is the page middleware.RecoverWith answers a panic with.
+// In development it shows the panic and its stack; otherwise those
+// stay in the log.
+func crashPage(dev bool) func(http.ResponseWriter, *http.Request, any) {
+	return func(w http.ResponseWriter, r *http.Request, err any) {
+		detail := ""
+		if dev {
+			detail = fmt.Sprintf("%v\n\n%s", err, debug.Stack())
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		components.Page("Server error", components.ServerError(detail)).Render(r.Context(), w)
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"net/http"
+
+	"{{MODULE_PATH}}/components"
+)
+
+func handle{{NAME | pascal}}(w http.ResponseWriter, r *http.Request) {
+	components.Page("{{NAME | title}}", components.{{NAME | pascal}}()).Render(r.Context(), w)
+}
@@ -0,0 +1,5 @@
+module {{MODULE_PATH}}
+
+go {{GO_VERSION}}
+
+require golang.org/x/crypto v0.31.0
@@ -0,0 +1,40 @@
+// Package flash carries a one-time message, such as "Saved." after a
+// form is posted, across the redirect that follows, in a short-lived
+// cookie that the next page clears.
+package flash
+
+import (
+	"encoding/base64"
+	"net/http"
+)
+
+// Cookie is the cookie the message waits in.
+const Cookie = "flash"
+
+// Set leaves message for the next page to Pop. It is not secret, and
+// the browser may hold it for a minute at most.
+func Set(w http.ResponseWriter, message string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     Cookie,
+		Value:    base64.RawURLEncoding.EncodeToString([]byte(message)),
+		Path:     "/",
+		MaxAge:   60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Pop is the message Set left, or "" for none, and clears it so it is
+// shown once. Call it before writing the response.
+func Pop(w http.ResponseWriter, r *http.Request) string {
+	c, err := r.Cookie(Cookie)
+	if err != nil {
+		return ""
+	}
+	http.SetCookie(w, &http.Cookie{Name: Cookie, Path: "/", MaxAge: -1})
+	message, err := base64.RawURLEncoding.DecodeString(c.Value)
+	if err != nil {
+		return ""
+	}
+	return string(message)
+}
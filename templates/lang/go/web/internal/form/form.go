@@ -0,0 +1,67 @@
+// Package form checks submitted forms. A Form holds what was sent and
+// a message for each field that is wrong, so the page can show the
+// form again, filled in, with each message beside its field.
+package form
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Form is a submitted form and what is wrong with it.
+type Form struct {
+	url.Values
+	Errors map[string]string // by field name; the first problem found
+}
+
+// New is a Form of values, with no errors yet; nil is an empty form.
+func New(values url.Values) *Form {
+	if values == nil {
+		values = url.Values{}
+	}
+	return &Form{Values: values, Errors: map[string]string{}}
+}
+
+// Check records message for field unless ok, or the field already has a
+// message: the first check to fail is the one shown.
+func (f *Form) Check(ok bool, field, message string) {
+	if _, seen := f.Errors[field]; !ok && !seen {
+		f.Errors[field] = message
+	}
+}
+
+// Valid is whether every check passed.
+func (f *Form) Valid() bool {
+	return len(f.Errors) == 0
+}
+
+// Get is the field's value with the surrounding blanks trimmed off.
+func (f *Form) Get(field string) string {
+	return strings.TrimSpace(f.Values.Get(field))
+}
+
+// Required checks that each field is filled in.
+func (f *Form) Required(fields ...string) {
+	for _, field := range fields {
+		f.Check(f.Get(field) != "", field, "This field is required.")
+	}
+}
+
+// MaxLength checks that the field is at most n characters long.
+func (f *Form) MaxLength(field string, n int) {
+	f.Check(utf8.RuneCountInString(f.Get(field)) <= n, field, fmt.Sprintf("Keep this to %d characters or fewer.", n))
+}
+
+// Email checks that the field, when filled in, is an email address alone,
+// such as ada@example.com.
+func (f *Form) Email(field string) {
+	v := f.Get(field)
+	if v == "" {
+		return
+	}
+	addr, err := mail.ParseAddress(v)
+	f.Check(err == nil && addr.Address == v && strings.Contains(v, "."), field, "Enter an email address, such as ada@example.com.")
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+// The CSRF token lives in the CSRFCookie cookie, and comes back in the
+// CSRFField form field or the CSRFHeader header.
+const (
+	CSRFCookie = "csrf_token"
+	CSRFField  = "csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+)
+
+type csrfKey struct{}
+
+// CSRF guards against cross-site request forgery with a double-submit
+// token: a random token in a cookie, which every request but a GET,
+// HEAD, OPTIONS or TRACE must send back in a form field or header.
+// Another site can make a browser send the cookie but cannot read it,
+// so it cannot send the copy. Requests with neither an Origin nor a
+// Sec-Fetch-Site header come from outside a browser (curl, another
+// server), where there is no one to forge for, and pass, as do those
+// CORS allows from another origin without cookies, which cannot act as
+// anyone. secure sends the cookie over https only.
+func CSRF(secure bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := ""
+			if c, err := r.Cookie(CSRFCookie); err == nil && validToken(c.Value) {
+				token = c.Value
+			} else {
+				token = newToken()
+				http.SetCookie(w, &http.Cookie{
+					Name:     CSRFCookie,
+					Value:    token,
+					Path:     "/",
+					HttpOnly: true,
+					Secure:   secure,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+			if !safeMethod(r.Method) && fromBrowser(r) && !corsWithoutCookies(r) && !sentBack(r, token) {
+				logging.FromContext(r.Context()).Warn("csrf: missing or wrong token", "method", r.Method, "path", r.URL.Path)
+				http.Error(w, "Forbidden: missing or wrong CSRF token", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfKey{}, token)))
+		})
+	}
+}
+
+// CSRFToken is the token CSRF put in ctx, for forms to send back, or ""
+// without one.
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfKey{}).(string)
+	return token
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+func fromBrowser(r *http.Request) bool {
+	return r.Header.Get("Origin") != "" || r.Header.Get("Sec-Fetch-Site") != ""
+}
+
+func corsWithoutCookies(r *http.Request) bool {
+	return crossOrigin(r.Context()) && r.Header.Get("Cookie") == ""
+}
+
+// sentBack is whether r carries token in the header or, for a form, the
+// field.
+func sentBack(r *http.Request, token string) bool {
+	sent := r.Header.Get(CSRFHeader)
+	if sent == "" {
+		sent = r.PostFormValue(CSRFField)
+	}
+	return sent != "" && subtle.ConstantTimeCompare([]byte(sent), []byte(token)) == 1
+}
+
+func validToken(token string) bool {
+	b, err := hex.DecodeString(token)
+	return err == nil && len(b) == 32
+}
+
+func newToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
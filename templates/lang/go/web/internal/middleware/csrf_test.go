@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCSRF(t *testing.T) {
+	var seen string
+	h := CSRF(false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = CSRFToken(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != CSRFCookie || cookies[0].Value != seen || len(seen) != 64 {
+		t.Fatalf("expected a new token in the cookie and context, got %v and %q", cookies, seen)
+	}
+	cookie := cookies[0]
+
+	post := func(form url.Values, header string, withCookie bool) int {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Origin", "http://app.test")
+		if header != "" {
+			req.Header.Set(CSRFHeader, header)
+		}
+		if withCookie {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+	tests := []struct {
+		name       string
+		form       url.Values
+		header     string
+		withCookie bool
+		want       int
+	}{
+		{"form field", url.Values{CSRFField: {cookie.Value}}, "", true, http.StatusOK},
+		{"header", nil, cookie.Value, true, http.StatusOK},
+		{"no token", nil, "", true, http.StatusForbidden},
+		{"wrong token", url.Values{CSRFField: {strings.Repeat("0", 64)}}, "", true, http.StatusForbidden},
+		{"no cookie", url.Values{CSRFField: {cookie.Value}}, "", false, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if got := post(tt.form, tt.header, tt.withCookie); got != tt.want {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCSRFPassesRequestsFromOutsideABrowser(t *testing.T) {
+	h := CSRF(false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/api/notes", strings.NewReader(`{}`)))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected a POST without Origin or Sec-Fetch-Site through, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest("POST", "/api/notes", strings.NewReader(`{}`))
+	req.Header.Set("Sec-Fetch-Site", "cross-site")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected a browser's POST without a token rejected, got %d", w.Code)
+	}
+}
+
+func TestCSRFPassesCORSRequestsWithoutCookies(t *testing.T) {
+	h := CORS([]string{"https://app.example"}, []string{"POST"}, nil)(
+		CSRF(false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	post := func(cookie *http.Cookie) int {
+		req := httptest.NewRequest("POST", "/api/notes", strings.NewReader(`{}`))
+		req.Header.Set("Origin", "https://app.example")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+	if got := post(nil); got != http.StatusOK {
+		t.Errorf("expected an allowed origin's POST without cookies through, got %d", got)
+	}
+	if got := post(&http.Cookie{Name: "session", Value: "x"}); got != http.StatusForbidden {
+		t.Errorf("expected one with cookies to need the token, got %d", got)
+	}
+}
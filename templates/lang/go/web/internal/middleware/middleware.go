@@ -0,0 +1,32 @@
+// Package middleware has the handlers main wraps around the mux: panic
+// recovery, request logging, request IDs, CORS, rate limits and CSRF
+// tokens.
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Chain wraps h in each middleware, the first one outermost.
+func Chain(h http.Handler, middlewares ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// Under applies m to the requests whose path starts with prefix, such
+// as "/api/", and sends the rest straight to the handler.
+func Under(prefix string, m func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := m(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				wrapped.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
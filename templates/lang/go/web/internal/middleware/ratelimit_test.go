@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := &limiter{rate: 1, burst: 2, buckets: map[string]*bucket{}, now: func() time.Time { return now }}
+	h := l.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	get := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/greet", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := get("192.0.2.1"); w.Code != http.StatusOK {
+			t.Fatalf("request %d of the burst: expected status 200, got %d", i+1, w.Code)
+		}
+	}
+	w := get("192.0.2.1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" ||
+		!strings.Contains(w.Body.String(), "Too Many Requests") {
+		t.Errorf("expected a 429 with Retry-After: 1, got %d %q: %s", w.Code, w.Header().Get("Retry-After"), w.Body)
+	}
+	if w := get("192.0.2.2"); w.Code != http.StatusOK {
+		t.Errorf("expected another IP its own bucket, got %d", w.Code)
+	}
+
+	now = now.Add(time.Second)
+	if w := get("192.0.2.1"); w.Code != http.StatusOK {
+		t.Errorf("expected a token back after a second, got %d", w.Code)
+	}
+	if w := get("192.0.2.1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected it spent again, got %d", w.Code)
+	}
+}
+
+func TestRateLimitSweepsFullBuckets(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := &limiter{rate: 1, burst: 2, buckets: map[string]*bucket{}, now: func() time.Time { return now }}
+	l.take("192.0.2.1")
+	now = now.Add(time.Minute)
+	l.take("192.0.2.2")
+	if _, ok := l.buckets["192.0.2.1"]; ok || len(l.buckets) != 1 {
+		t.Errorf("expected the idle bucket dropped, got %v", l.buckets)
+	}
+}
+
+func TestRateLimitOff(t *testing.T) {
+	h := RateLimit(0, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for i := 0; i < 100; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/api/greet", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected no limit at rate 0, got %d", w.Code)
+		}
+	}
+}
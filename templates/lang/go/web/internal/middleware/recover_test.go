@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+func TestRecover(t *testing.T) {
+	var out bytes.Buffer
+	h := Recover(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+	req := httptest.NewRequest("GET", "/", nil)
+	logger := slog.New(slog.NewTextHandler(&out, nil))
+	req = req.WithContext(logging.NewContext(req.Context(), logger))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+	if !strings.Contains(out.String(), "msg=panic err=boom") {
+		t.Errorf("expected the panic logged, got %q", out.String())
+	}
+}
+
+func TestRecoverWith(t *testing.T) {
+	page := func(w http.ResponseWriter, r *http.Request, err any) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "<h1>oops: %v</h1>", err)
+	}
+	h := RecoverWith(page)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+	if got := w.Body.String(); got != "<h1>oops: boom</h1>" {
+		t.Errorf("expected the page, got %q", got)
+	}
+}
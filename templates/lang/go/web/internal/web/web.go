@@ -0,0 +1,16 @@
+// Package web is the files built into the binary: static/, which
+// package static serves. They live here rather than beside main.go so
+// that a main package anywhere in the module, under cmd/ too, can use
+// them.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:static
+var files embed.FS
+
+// Static is static/ as it was when the binary was built.
+var Static, _ = fs.Sub(files, "static") // fails only for a bad path
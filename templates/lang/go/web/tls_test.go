@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/config"
+)
+
+func TestRedirectToHTTPS(t *testing.T) {
+	tests := []struct{ port, host, want string }{
+		{"443", "example.com", "https://example.com/notes?page=2"},
+		{"443", "example.com:80", "https://example.com/notes?page=2"},
+		{"8443", "localhost:8080", "https://localhost:8443/notes?page=2"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "http://"+tt.host+"/notes?page=2", nil)
+		w := httptest.NewRecorder()
+		redirectToHTTPS(tt.port).ServeHTTP(w, req)
+		if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != tt.want {
+			t.Errorf("%s on %s: expected a redirect to %s, got %d %s", tt.host, tt.port, tt.want, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
+
+func TestUseTLS(t *testing.T) {
+	srv := &http.Server{}
+	if redirect := useTLS(srv, config.Config{Port: "4000"}); redirect != nil || srv.TLSConfig != nil {
+		t.Error("expected plain http without TLS settings")
+	}
+
+	cfg := config.Config{Port: "443", HTTPPort: "80", TLSDomains: []string{"example.com"}, TLSCache: t.TempDir()}
+	redirect := useTLS(srv, cfg)
+	if redirect == nil || redirect.Addr != ":80" || srv.TLSConfig == nil || srv.TLSConfig.GetCertificate == nil {
+		t.Fatal("expected autocert's certificates and a redirect on :80")
+	}
+	// autocert answers its challenges, and sends everything else to https.
+	w := httptest.NewRecorder()
+	redirect.Handler.ServeHTTP(w, httptest.NewRequest("GET", "http://example.com/", nil))
+	if w.Header().Get("Location") != "https://example.com/" {
+		t.Errorf("expected a redirect to https, got %d %s", w.Code, w.Header().Get("Location"))
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/queue"
+)
+
+func TestEnqueue(t *testing.T) {
+	q := queue.New(queue.Options{})
+	q.Register("greet", func(ctx context.Context, job *queue.Job) error { return nil })
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs/{kind}", handleEnqueue(q))
+
+	tests := []struct {
+		name, path, body string
+		status           int
+		want             string // in the body
+	}{
+		{"accepted", "/jobs/greet", `{"name": "Ada"}`, http.StatusAccepted, `{"id":1}`},
+		{"unknown kind", "/jobs/wave", `{}`, http.StatusNotFound, "unknown kind"},
+		{"not JSON", "/jobs/greet", `name=Ada`, http.StatusBadRequest, "as JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("expected %d with %q, got %d %s", tt.status, tt.want, w.Code, w.Body)
+			}
+		})
+	}
+
+	q.Shutdown(context.Background())
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/jobs/greet", strings.NewReader(`{}`)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected a 503 once the queue is shut down, got %d", w.Code)
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleHealthz(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+}
@@ -0,0 +1,88 @@
+// Package clock is time as the queue sees it: the real one in the
+// program, and a Fake in tests, which moves only when told to, so
+// retries and periodic jobs can be tested without waiting for them.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// A Clock tells the time and waits.
+type Clock interface {
+	Now() time.Time
+	// After sends the time on the channel once d has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real is the system clock.
+type Real struct{}
+
+func (Real) Now() time.Time                         { return time.Now() }
+func (Real) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Fake is a Clock that stands still until Advance moves it.
+type Fake struct {
+	mu      sync.Mutex
+	changed *sync.Cond // on a new waiter
+	now     time.Time
+	waiters []waiter
+}
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFake is a Fake clock at now.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.changed = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+	f.waiters = append(f.waiters, waiter{f.now.Add(d), ch})
+	f.changed.Broadcast()
+	return ch
+}
+
+// Advance moves the clock on by d, waking what was waiting for a time
+// up to then.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	waiting := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.at.After(f.now) {
+			waiting = append(waiting, w)
+			continue
+		}
+		w.ch <- f.now
+	}
+	f.waiters = waiting
+}
+
+// BlockUntil returns once n callers are waiting on After: a test calls
+// it before Advance, so that what it advances past has started waiting.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.waiters) < n {
+		f.changed.Wait()
+	}
+}
@@ -0,0 +1,48 @@
+// Package jobs is the work the worker does: a handler for each kind of
+// job, and the jobs that run on a schedule. Register adds them all to
+// the queue; a new kind needs a handler here and a line there.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"{{MODULE_PATH}}/internal/logging"
+	"{{MODULE_PATH}}/internal/queue"
+)
+
+// The kinds of job, as Enqueue and POST /jobs/{kind} name them.
+const (
+	KindGreet   = "greet"
+	KindCleanup = "cleanup"
+)
+
+// Register adds every kind of job to q, and schedules the periodic
+// ones.
+func Register(q *queue.Queue) {
+	q.Register(KindGreet, queue.JSON(greet))
+	// The next run does what a failed one left, so it is not retried.
+	q.Register(KindCleanup, cleanup, queue.MaxAttempts(1), queue.Timeout(10*time.Minute))
+	q.Every(KindCleanup, time.Hour, nil)
+}
+
+// Greet is a greet job's payload.
+type Greet struct {
+	Name string `json:"name"`
+}
+
+func greet(ctx context.Context, g Greet) error {
+	if strings.TrimSpace(g.Name) == "" {
+		return queue.Permanent(errors.New("greet: the name is empty"))
+	}
+	// The job's logger adds its job_id, kind and attempt to every line.
+	logging.FromContext(ctx).Info("greeting", "message", "Hello, "+g.Name+"!")
+	return nil
+}
+
+func cleanup(ctx context.Context, job *queue.Job) error {
+	logging.FromContext(ctx).Info("cleaning up")
+	return nil
+}
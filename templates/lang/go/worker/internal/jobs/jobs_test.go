@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"{{MODULE_PATH}}/internal/logging"
+	"{{MODULE_PATH}}/internal/queue"
+)
+
+func TestRegister(t *testing.T) {
+	q := queue.New(queue.Options{})
+	Register(q)
+	for _, kind := range []string{KindGreet, KindCleanup} {
+		if _, err := q.Enqueue(context.Background(), kind, Greet{"Ada"}); err != nil {
+			t.Errorf("%s: %v", kind, err)
+		}
+	}
+}
+
+func TestGreet(t *testing.T) {
+	var out bytes.Buffer
+	ctx := logging.NewContext(context.Background(), slog.New(slog.NewTextHandler(&out, nil)))
+	if err := greet(ctx, Greet{"Ada"}); err != nil || !strings.Contains(out.String(), "Hello, Ada!") {
+		t.Errorf("expected the greeting logged, got %v %q", err, out.String())
+	}
+	// A job that can never succeed is not retried.
+	job := &queue.Job{Kind: KindGreet, Payload: []byte(`{"name": " "}`)}
+	if err := queue.JSON(greet)(ctx, job); !queue.IsPermanent(err) {
+		t.Errorf("expected a permanent error, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package queue
+
+import (
+	"errors"
+	"time"
+)
+
+type periodic struct {
+	name    string
+	every   time.Duration
+	payload any
+}
+
+// Every enqueues a job of the kind named, with payload, each time every
+// passes, from Start until Shutdown. The kind must be registered
+// first. A job that takes longer than every overlaps the next one, so
+// a handler that must not run twice at once has to check.
+func (q *Queue) Every(name string, every time.Duration, payload any) {
+	if _, ok := q.kinds[name]; !ok {
+		panic("queue: Every " + name + ", which is not registered")
+	}
+	q.periodic = append(q.periodic, periodic{name, every, payload})
+}
+
+func (q *Queue) schedule(p periodic) {
+	defer q.running.Done()
+	for {
+		select {
+		case <-q.opts.Clock.After(p.every):
+			_, err := q.Enqueue(q.base, p.name, p.payload)
+			if errors.Is(err, ErrClosed) {
+				return
+			}
+			if err != nil {
+				q.opts.Logger.Warn("periodic job not enqueued", "kind", p.name, "err", err)
+			}
+		case <-q.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// Package queue runs background jobs in this process: a handler for
+// each kind of job, a pool of workers, retries with backoff, periodic
+// jobs and a drain on shutdown.
+//
+// The jobs live in memory, so those not yet run when the process stops
+// are lost. Work that must survive a restart wants a durable queue
+// (river on Postgres, asynq on Redis) behind the same Register,
+// Enqueue and Every.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"{{MODULE_PATH}}/internal/clock"
+	"{{MODULE_PATH}}/internal/logging"
+)
+
+var (
+	// ErrClosed is what Enqueue says once Shutdown has begun.
+	ErrClosed = errors.New("queue: shut down")
+	// ErrUnknownKind is what Enqueue says for a kind no handler is
+	// registered for.
+	ErrUnknownKind = errors.New("queue: unknown kind")
+)
+
+// A Job is one piece of work, as its handler sees it.
+type Job struct {
+	ID      int64
+	Kind    string
+	Payload json.RawMessage // the payload Enqueue was given, as JSON
+	Attempt int             // 1 on the first run, 2 on the first retry, ...
+}
+
+// A Handler does a job. An error has it retried, after a backoff, until
+// its kind's attempts run out, unless it is Permanent.
+type Handler func(ctx context.Context, job *Job) error
+
+// JSON is a Handler for jobs whose payload is a T: it decodes the
+// payload for fn. One that is not a T fails the job for good, as no
+// retry would fix it.
+func JSON[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return Permanent(fmt.Errorf("decoding the %s payload: %w", job.Kind, err))
+		}
+		return fn(ctx, payload)
+	}
+}
+
+// Options are how a Queue runs; the zero value of each is a default.
+type Options struct {
+	Workers     int                     // jobs run at once; 4
+	Size        int                     // jobs waiting to run before Enqueue waits; 1000
+	MaxAttempts int                     // runs of a job before it fails for good, unless its kind says; 5
+	Timeout     time.Duration           // of each run, unless its kind says; a minute
+	Backoff     func(int) time.Duration // the wait after a job's nth failed attempt; ExponentialBackoff
+	Clock       clock.Clock             // clock.Real{}
+	Logger      *slog.Logger            // slog.Default()
+	Failed      func(*Job, error)       // called on a job that failed for good, to record or alert on it
+}
+
+// Queue is a pool of workers running the jobs enqueued on it. Register
+// handlers and Every schedules before Start.
+type Queue struct {
+	opts     Options
+	kinds    map[string]kind
+	periodic []periodic
+	jobs     chan *Job
+	lastID   atomic.Int64
+
+	// mu guards closed, so no Enqueue sends once Shutdown has begun.
+	mu     sync.RWMutex
+	closed bool
+
+	stop    chan struct{}      // closed by Shutdown: no more retries or periodic jobs
+	base    context.Context    // the jobs' contexts come from it
+	cancel  context.CancelFunc // ends them, when Shutdown runs out of time
+	running sync.WaitGroup     // the workers and schedules
+}
+
+type kind struct {
+	handle      Handler
+	maxAttempts int
+	timeout     time.Duration
+}
+
+// New is a queue with opts, which must then be started.
+func New(opts Options) *Queue {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.Size <= 0 {
+		opts.Size = 1000
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = time.Minute
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = ExponentialBackoff
+	}
+	if opts.Clock == nil {
+		opts.Clock = clock.Real{}
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	base, cancel := context.WithCancel(context.Background())
+	return &Queue{
+		opts:   opts,
+		kinds:  map[string]kind{},
+		jobs:   make(chan *Job, opts.Size),
+		stop:   make(chan struct{}),
+		base:   base,
+		cancel: cancel,
+	}
+}
+
+// A KindOption changes how the jobs of one kind run.
+type KindOption func(*kind)
+
+// MaxAttempts is how many times the kind's jobs run before they fail
+// for good; 1 is never retried.
+func MaxAttempts(n int) KindOption {
+	return func(k *kind) { k.maxAttempts = n }
+}
+
+// Timeout bounds each run of the kind's jobs.
+func Timeout(d time.Duration) KindOption {
+	return func(k *kind) { k.timeout = d }
+}
+
+// Register has h do the jobs of the kind named, which it panics on
+// having already.
+func (q *Queue) Register(name string, h Handler, opts ...KindOption) {
+	if _, ok := q.kinds[name]; ok {
+		panic("queue: kind " + name + " registered twice")
+	}
+	k := kind{handle: h, maxAttempts: q.opts.MaxAttempts, timeout: q.opts.Timeout}
+	for _, opt := range opts {
+		opt(&k)
+	}
+	q.kinds[name] = k
+}
+
+// Enqueue adds a job of the kind named, with payload as its JSON, and
+// is its ID. It waits while the queue is full, until ctx is done.
+func (q *Queue) Enqueue(ctx context.Context, name string, payload any) (int64, error) {
+	if _, ok := q.kinds[name]; !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnknownKind, name)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("queue: %s payload: %w", name, err)
+	}
+	job := &Job{ID: q.lastID.Add(1), Kind: name, Payload: data, Attempt: 1}
+	return job.ID, q.push(ctx, job)
+}
+
+func (q *Queue) push(ctx context.Context, job *Job) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrClosed
+	}
+	select {
+	case q.jobs <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start starts the workers and the periodic schedules.
+func (q *Queue) Start() {
+	for range q.opts.Workers {
+		q.running.Add(1)
+		go q.work()
+	}
+	for _, p := range q.periodic {
+		q.running.Add(1)
+		go q.schedule(p)
+	}
+}
+
+// work runs jobs until Shutdown, and then those still waiting, unless
+// Shutdown runs out of time first.
+func (q *Queue) work() {
+	defer q.running.Done()
+	for q.base.Err() == nil {
+		select {
+		case job := <-q.jobs:
+			q.run(job)
+		case <-q.stop:
+			for q.base.Err() == nil {
+				select {
+				case job := <-q.jobs:
+					q.run(job)
+				default:
+					return
+				}
+			}
+			return
+		}
+	}
+}
+
+// run does one attempt at job and retries it, or gives up on it, when
+// that fails.
+func (q *Queue) run(job *Job) {
+	k := q.kinds[job.Kind]
+	logger := q.opts.Logger.With("job_id", job.ID, "kind", job.Kind, "attempt", job.Attempt)
+	ctx, cancel := context.WithTimeout(logging.NewContext(q.base, logger), k.timeout)
+	start := q.opts.Clock.Now()
+	err := safely(ctx, k.handle, job)
+	cancel()
+	took := q.opts.Clock.Now().Sub(start)
+	switch {
+	case err == nil:
+		logger.Info("job done", "took", took)
+	case IsPermanent(err) || job.Attempt >= k.maxAttempts:
+		logger.Error("job failed", "err", err, "took", took)
+		if q.opts.Failed != nil {
+			q.opts.Failed(job, err)
+		}
+	default:
+		wait := q.opts.Backoff(job.Attempt)
+		logger.Warn("job failed, will retry", "err", err, "took", took, "retry_in", wait)
+		q.retry(&Job{ID: job.ID, Kind: job.Kind, Payload: job.Payload, Attempt: job.Attempt + 1}, wait)
+	}
+}
+
+// safely is h(ctx, job) with a panic as its error, so one bad job does
+// not take the workers down.
+func safely(ctx context.Context, h Handler, job *Job) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+		}
+	}()
+	return h(ctx, job)
+}
+
+// retry puts job back on the queue after wait, unless Shutdown comes
+// first; it is then lost, as everything in memory is.
+func (q *Queue) retry(job *Job, wait time.Duration) {
+	after := q.opts.Clock.After(wait)
+	go func() {
+		select {
+		case <-after:
+			if err := q.push(q.base, job); err != nil {
+				q.opts.Logger.Warn("retry dropped", "job_id", job.ID, "kind", job.Kind, "err", err)
+			}
+		case <-q.stop:
+			q.opts.Logger.Warn("retry dropped", "job_id", job.ID, "kind", job.Kind, "err", ErrClosed)
+		}
+	}()
+}
+
+// Shutdown stops taking jobs and waits for those running and waiting
+// to finish: the drain. If ctx is done first, it cancels the jobs'
+// contexts, waits for their handlers to return and says how many jobs
+// were left undone. Jobs waiting to be retried are dropped.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrClosed
+	}
+	q.closed = true
+	q.mu.Unlock()
+	close(q.stop)
+
+	drained := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-drained
+		return fmt.Errorf("queue: %d jobs left undone: %w", len(q.jobs), ctx.Err())
+	}
+}
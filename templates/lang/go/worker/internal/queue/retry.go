@@ -0,0 +1,33 @@
+package queue
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// Permanent is err, marked so that the job it fails is not retried: for
+// a payload no handler could do anything with, or an answer that will
+// not change.
+func Permanent(err error) error {
+	return permanent{err}
+}
+
+type permanent struct{ err error }
+
+func (p permanent) Error() string { return p.err.Error() }
+func (p permanent) Unwrap() error { return p.err }
+
+// IsPermanent reports whether err is, or wraps, one from Permanent.
+func IsPermanent(err error) bool {
+	return errors.As(err, new(permanent))
+}
+
+// ExponentialBackoff is the wait after a job's nth failed attempt: a
+// second after the first, doubling each time to at most ten minutes,
+// and jittered to between half and all of that, so jobs that failed
+// together do not all retry together.
+func ExponentialBackoff(attempt int) time.Duration {
+	d := min(time.Second<<min(max(attempt-1, 0), 10), 10*time.Minute)
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,14 @@
+module Main (main) where
+
+import Data.Version (showVersion)
+import Greet (greet)
+import Paths_{{PROJECT_NAME | snake}} (version)
+import System.Environment (getArgs)
+
+main :: IO ()
+main = do
+  args <- getArgs
+  case args of
+    ["--version"] -> putStrLn (showVersion version)
+    name : _ -> putStrLn (greet name)
+    [] -> putStrLn (greet "")
@@ -0,0 +1,18 @@
+-- | Greetings, in the library so that the tests can use them as well as
+-- the executable.
+module Greet (greet) where
+
+import Data.Char (isSpace)
+import Data.List (dropWhileEnd)
+
+-- | @greet name@ is @"Hello, name!"@, or @"Hello, World!"@ for a name
+-- that is empty or blank.
+--
+-- >>> greet " Ada "
+-- "Hello, Ada!"
+greet :: String -> String
+greet name = case trim name of
+  "" -> "Hello, World!"
+  trimmed -> "Hello, " <> trimmed <> "!"
+  where
+    trim = dropWhileEnd isSpace . dropWhile isSpace
@@ -0,0 +1,14 @@
+module GreetSpec (spec) where
+
+import Greet (greet)
+import Test.Hspec
+
+spec :: Spec
+spec = describe "greet" $ do
+  it "greets by name" $ do
+    greet "Ada" `shouldBe` "Hello, Ada!"
+    greet "  Ada Lovelace\n" `shouldBe` "Hello, Ada Lovelace!"
+
+  it "greets the world without a name" $ do
+    greet "" `shouldBe` "Hello, World!"
+    greet " \t " `shouldBe` "Hello, World!"
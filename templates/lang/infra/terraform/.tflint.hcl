@@ -0,0 +1,25 @@
+# tflint --init installs the plugins; tflint --recursive lints the
+# module and its examples.
+
+plugin "terraform" {
+  enabled = true
+  preset  = "recommended"
+}
+
+plugin "{{provider}}" {
+  enabled = true
+  version = "{{TFLINT_VERSION}}"
+  source  = "github.com/terraform-linters/tflint-ruleset-{{provider}}"
+}
+
+rule "terraform_naming_convention" {
+  enabled = true
+}
+
+rule "terraform_documented_variables" {
+  enabled = true
+}
+
+rule "terraform_documented_outputs" {
+  enabled = true
+}
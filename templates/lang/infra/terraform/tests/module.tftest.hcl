@@ -0,0 +1,27 @@
+# terraform test runs these against a mocked {{provider}} provider:
+# each run plans the module, so no credentials or resources are needed.
+
+mock_provider "{{provider}}" {}
+
+variables {
+  name = "{{EXAMPLE_NAME}}"
+}
+
+run "names_the_resources" {
+  command = plan
+
+  assert {
+    condition     = output.name == "{{EXAMPLE_NAME}}"
+    error_message = "The name output should be the name it was given."
+  }
+}
+
+run "rejects_an_invalid_name" {
+  command = plan
+
+  variables {
+    name = "Not A Valid Name"
+  }
+
+  expect_failures = [var.name]
+}
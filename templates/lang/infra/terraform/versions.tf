@@ -0,0 +1,10 @@
+terraform {
+  required_version = ">= 1.7"
+
+  required_providers {
+    {{provider}} = {
+      source  = "hashicorp/{{provider}}"
+      version = "{{PROVIDER_VERSION}}"
+    }
+  }
+}
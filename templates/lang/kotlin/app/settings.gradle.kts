@@ -0,0 +1,7 @@
+plugins {
+    // Downloads the JDK that kotlin { jvmToolchain(...) } asks for, when
+    // this machine does not have it.
+    id("org.gradle.toolchains.foojay-resolver-convention") version "0.8.0"
+}
+
+rootProject.name = "{{PROJECT_NAME | kebab}}"
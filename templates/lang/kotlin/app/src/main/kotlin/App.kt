@@ -0,0 +1,5 @@
+package {{PACKAGE}}
+
+fun main(args: Array<String>) {
+    println(greet(args.firstOrNull().orEmpty()))
+}
@@ -0,0 +1,7 @@
+package {{PACKAGE}}
+
+/** "Hello, [name]!", or "Hello, World!" for a name that is empty or blank. */
+fun greet(name: String): String {
+    val trimmed = name.trim()
+    return if (trimmed.isEmpty()) "Hello, World!" else "Hello, $trimmed!"
+}
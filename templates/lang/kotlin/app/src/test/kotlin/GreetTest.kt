@@ -0,0 +1,12 @@
+package {{PACKAGE}}
+
+import io.kotest.matchers.shouldBe
+import org.junit.jupiter.api.Test
+
+class GreetTest {
+    @Test
+    fun `greets by name`() {
+        greet("Ada") shouldBe "Hello, Ada!"
+        greet("  Ada Lovelace\n") shouldBe "Hello, Ada Lovelace!"
+    }
+}
@@ -0,0 +1,7 @@
+;;;; sbcl --non-interactive --load scripts/build.lisp, from the project's
+;;;; directory: builds the executable into bin/.
+
+(require :asdf)
+(asdf:load-asd (truename "{{PROJECT_NAME | kebab}}.asd"))
+(ql:quickload "{{PROJECT_NAME | kebab}}" :silent t)
+(asdf:make "{{PROJECT_NAME | kebab}}")
@@ -0,0 +1,9 @@
+;;;; sbcl --non-interactive --load scripts/lint.lisp, from the project's
+;;;; directory: compiles both systems afresh, failing on a warning.
+
+(require :asdf)
+(asdf:load-asd (truename "{{PROJECT_NAME | kebab}}.asd"))
+(ql:quickload "{{PROJECT_NAME | kebab}}/tests" :silent t)
+(setf asdf:*compile-file-warnings-behaviour* :error)
+(asdf:compile-system "{{PROJECT_NAME | kebab}}/tests"
+                     :force '("{{PROJECT_NAME | kebab}}" "{{PROJECT_NAME | kebab}}/tests"))
@@ -0,0 +1,7 @@
+;;;; sbcl --non-interactive --load scripts/test.lisp, from the project's
+;;;; directory: runs the FiveAM suite, exiting non-zero when a test fails.
+
+(require :asdf)
+(asdf:load-asd (truename "{{PROJECT_NAME | kebab}}.asd"))
+(ql:quickload "{{PROJECT_NAME | kebab}}/tests" :silent t)
+(asdf:test-system "{{PROJECT_NAME | kebab}}")
@@ -0,0 +1,9 @@
+(in-package #:{{PROJECT_NAME | kebab}})
+
+(defun greet (name)
+  "\"Hello, NAME!\", or \"Hello, World!\" for a NAME that is NIL or blank."
+  (let ((trimmed (and name (string-trim '(#\Space #\Tab #\Newline #\Return)
+                                        name))))
+    (if (or (null trimmed) (string= trimmed ""))
+        "Hello, World!"
+        (format nil "Hello, ~A!" trimmed))))
@@ -0,0 +1,5 @@
+(in-package #:{{PROJECT_NAME | kebab}})
+
+(defun main ()
+  "The executable's entry point: greets whoever the first argument names."
+  (write-line (greet (first (uiop:command-line-arguments)))))
@@ -0,0 +1,4 @@
+(defpackage #:{{PROJECT_NAME | kebab}}
+  (:use #:cl)
+  (:export #:greet
+           #:main))
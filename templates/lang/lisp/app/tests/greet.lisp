@@ -0,0 +1,18 @@
+(defpackage #:{{PROJECT_NAME | kebab}}/tests
+  (:use #:cl #:fiveam)
+  (:import-from #:{{PROJECT_NAME | kebab}} #:greet)
+  (:export #:all))
+
+(in-package #:{{PROJECT_NAME | kebab}}/tests)
+
+(def-suite all :description "Every test of {{PROJECT_NAME | kebab}}.")
+(in-suite all)
+
+(test greets-by-name
+  (is (string= "Hello, Ada!" (greet "Ada")))
+  (is (string= "Hello, Ada Lovelace!"
+               (greet (format nil "  Ada Lovelace~%")))))
+
+(test greets-the-world-without-a-name
+  (is (string= "Hello, World!" (greet nil)))
+  (is (string= "Hello, World!" (greet (format nil " ~C " #\Tab)))))
@@ -0,0 +1,9 @@
+-- Read by LÖVE before main.lua: the window, and the version of LÖVE
+-- the game is written for.
+function love.conf(t)
+  t.identity = "{{PROJECT_NAME | kebab}}" -- the save directory's name
+  t.version = "11.5"
+  t.window.title = "{{PROJECT_NAME}}"
+  t.window.width = 800
+  t.window.height = 600
+end
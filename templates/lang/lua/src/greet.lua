@@ -0,0 +1,15 @@
+--- Greetings, in a module of their own so that the specs can require
+--- them.
+local M = {}
+
+--- "Hello, NAME!", or "Hello, World!" for a name that is nil, empty or
+--- blank.
+function M.greet(name)
+  local trimmed = (name or ""):match("^%s*(.-)%s*$")
+  if trimmed == "" then
+    return "Hello, World!"
+  end
+  return ("Hello, %s!"):format(trimmed)
+end
+
+return M
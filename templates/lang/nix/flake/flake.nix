@@ -0,0 +1,67 @@
+{
+  description = "{{PROJECT_NAME}}: {{SYNOPSIS}}";
+
+  inputs.nixpkgs.url = "github:NixOS/nixpkgs/nixos-24.11";
+
+  outputs =
+    { self, nixpkgs }:
+    let
+      # The systems the packages, shells and checks are built for.
+      systems = [
+        {{SYSTEMS}}
+      ];
+
+      # forAllSystems f is { <system> = f pkgs; }, with pkgs the
+      # system's nixpkgs with this flake's overlay applied.
+      forAllSystems =
+        f:
+        nixpkgs.lib.genAttrs systems (
+          system:
+          f (
+            import nixpkgs {
+              inherit system;
+              overlays = [ self.overlays.default ];
+            }
+          )
+        );
+    in
+    {
+      overlays.default = final: _prev: {
+        {{PROJECT_NAME | kebab}} = final.callPackage ./nix/package.nix { };
+      };
+
+      packages = forAllSystems (pkgs: {
+        default = pkgs.{{PROJECT_NAME | kebab}};
+        inherit (pkgs) {{PROJECT_NAME | kebab}};
+      });
+
+      devShells = forAllSystems (pkgs: {
+        default = pkgs.mkShell {
+          inputsFrom = [ pkgs.{{PROJECT_NAME | kebab}} ];
+          packages = with pkgs; [
+            deadnix
+            just
+            nixfmt-rfc-style
+            statix
+          ];
+        };
+      });
+
+      checks = forAllSystems (pkgs: {
+        package = pkgs.{{PROJECT_NAME | kebab}};
+
+        greets = pkgs.runCommand "{{PROJECT_NAME | kebab}}-greets" { } ''
+          [ "$(${pkgs.lib.getExe pkgs.{{PROJECT_NAME | kebab}}} Ada)" = "Hello, Ada!" ]
+          [ "$(${pkgs.lib.getExe pkgs.{{PROJECT_NAME | kebab}}})" = "Hello, World!" ]
+          touch $out
+        '';
+
+        formatting = pkgs.runCommand "{{PROJECT_NAME | kebab}}-formatting" { } ''
+          ${pkgs.lib.getExe pkgs.nixfmt-rfc-style} --check ${./flake.nix} ${./nix}/*.nix
+          touch $out
+        '';
+      });
+
+      formatter = forAllSystems (pkgs: pkgs.nixfmt-rfc-style);
+    };
+}
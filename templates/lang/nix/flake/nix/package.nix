@@ -0,0 +1,13 @@
+# The program in src/, as a shell application: writeShellApplication
+# runs it under bash with set -euo pipefail and checks it with
+# shellcheck as it builds.
+{ writeShellApplication }:
+
+writeShellApplication {
+  name = "{{PROJECT_NAME | kebab}}";
+  text = builtins.readFile ../src/{{PROJECT_NAME | kebab}}.sh;
+  meta = {
+    description = "{{SYNOPSIS}}";
+    mainProgram = "{{PROJECT_NAME | kebab}}";
+  };
+}
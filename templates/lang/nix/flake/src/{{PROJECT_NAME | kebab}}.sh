@@ -0,0 +1,4 @@
+# {{PROJECT_NAME | kebab}} [NAME] greets NAME, or the world. nix/package.nix
+# adds the shebang and strict mode.
+
+echo "Hello, ${1:-World}!"
@@ -0,0 +1,3 @@
+let () =
+  let name = if Array.length Sys.argv > 1 then Sys.argv.(1) else "" in
+  print_endline ({{MODULE}}.Greet.greet name)
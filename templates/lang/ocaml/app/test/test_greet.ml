@@ -0,0 +1,21 @@
+let greet = {{MODULE}}.Greet.greet
+
+let test_name () =
+  Alcotest.(check string) "by name" "Hello, Ada!" (greet "Ada");
+  Alcotest.(check string)
+    "trimmed" "Hello, Ada Lovelace!" (greet "  Ada Lovelace\n")
+
+let test_blank () =
+  Alcotest.(check string) "empty" "Hello, World!" (greet "");
+  Alcotest.(check string) "blank" "Hello, World!" (greet " \t ")
+
+let () =
+  Alcotest.run "{{PROJECT_NAME | kebab}}"
+    [
+      ( "greet",
+        [
+          Alcotest.test_case "greets by name" `Quick test_name;
+          Alcotest.test_case "greets the world without a name" `Quick
+            test_blank;
+        ] );
+    ]
@@ -0,0 +1,13 @@
+"""{{PROJECT_NAME}}: slugs for URLs and file names.
+
+>>> from {{PROJECT_NAME_UNDERSCORE}} import slugify
+>>> slugify("Crème brûlée: a how-to")
+'creme-brulee-a-how-to'
+"""
+
+from importlib.metadata import version
+
+from {{PROJECT_NAME_UNDERSCORE}}.slug import is_slug, slugify
+
+__all__ = ["is_slug", "slugify"]
+__version__ = version("{{PROJECT_NAME}}")
@@ -9,11 +9,13 @@ import pyroscope
 from fastapi import FastAPI, Request
 from fastapi.responses import HTMLResponse, Response
 from fastapi.staticfiles import StaticFiles
-from fastapi.templating import Jinja2Templates
 from pyinstrument import Profiler
 from pyinstrument.renderers.speedscope import SpeedscopeRenderer
 from starlette.routing import Match
 
+from {{PROJECT_NAME_UNDERSCORE}}.routers import api, health, pages
+from {{PROJECT_NAME_UNDERSCORE}}.settings import get_settings
+
 
 # Reject if any of these are set — request.client.host would reflect the
 # proxy's loopback, not the real caller.
@@ -25,7 +27,7 @@ _DRAIN_BYTE_CAP = 50 * 1024 * 1024
 
 
 def profiling_enabled() -> bool:
-    return os.environ.get("APP_PROFILE", "1") != "0"
+    return get_settings().app_profile
 
 
 # Per-request so commits during a session don't leave the tag stale —
@@ -70,7 +72,9 @@ async def lifespan(_app: FastAPI) -> AsyncIterator[None]:
 app = FastAPI(lifespan=lifespan)
 
 app.mount("/static", StaticFiles(directory="static"), name="static")
-templates = Jinja2Templates(directory="templates")
+app.include_router(pages.router)
+app.include_router(api.router)
+app.include_router(health.router)
 
 
 @app.middleware("http")
@@ -145,10 +149,3 @@ async def profile_request(request: Request, call_next):
             media_type="application/json",
         )
     return HTMLResponse(profiler.output_html())
-
-
-@app.get("/", response_class=HTMLResponse)
-async def home(request: Request) -> HTMLResponse:
-    return templates.TemplateResponse(
-        request, "home.html", {"message": "Hello, World!"}
-    )
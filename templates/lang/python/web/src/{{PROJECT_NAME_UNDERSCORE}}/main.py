@@ -1,14 +1,15 @@
-import os
-
 import uvicorn
 
+from {{PROJECT_NAME_UNDERSCORE}}.settings import get_settings
+
 
 def main() -> None:
+    settings = get_settings()
     uvicorn.run(
         "{{PROJECT_NAME_UNDERSCORE}}.app:app",
         host="0.0.0.0",
-        port=int(os.environ.get("PORT", "4000")),
-        reload=True,
+        port=settings.port,
+        reload=settings.app_env == "development",
     )
 
 
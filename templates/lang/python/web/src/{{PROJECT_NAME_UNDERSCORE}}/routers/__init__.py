@@ -0,0 +1 @@
+"""The app's routes, an APIRouter per area, which app.py includes."""
@@ -0,0 +1,25 @@
+from typing import Annotated
+
+from fastapi import APIRouter, Depends
+from pydantic import BaseModel, Field
+
+from {{PROJECT_NAME_UNDERSCORE}}.settings import Settings, get_settings
+
+router = APIRouter(prefix="/api", tags=["api"])
+
+
+class HelloRequest(BaseModel):
+    name: str = Field(min_length=1, max_length=100)
+
+
+class Hello(BaseModel):
+    message: str
+
+
+@router.post("/hello")
+async def hello(
+    body: HelloRequest, settings: Annotated[Settings, Depends(get_settings)]
+) -> Hello:
+    """Greet NAME. A body that is not a HelloRequest gets a 422 naming
+    what is wrong with it, from FastAPI."""
+    return Hello(message=f"{settings.greeting}, {body.name}!")
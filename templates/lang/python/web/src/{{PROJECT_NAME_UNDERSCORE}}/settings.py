@@ -0,0 +1,27 @@
+from functools import lru_cache
+from typing import Literal
+
+from pydantic_settings import BaseSettings, SettingsConfigDict
+
+
+class Settings(BaseSettings):
+    """The app's settings, from environment variables of the same name
+    in any case (PORT, APP_ENV), then .env, then these defaults. A value
+    of the wrong type stops the app at start with the variable named."""
+
+    model_config = SettingsConfigDict(env_file=".env", extra="ignore")
+
+    app_env: Literal["development", "production"] = "development"
+    port: int = 4000
+    # 0 turns the ?profile=1 profiler and pyroscope off.
+    app_profile: bool = True
+    # What /api/hello greets with.
+    greeting: str = "Hello"
+
+
+@lru_cache
+def get_settings() -> Settings:
+    """The settings, read once. Handlers take them as a dependency,
+    ``Annotated[Settings, Depends(get_settings)]``, which tests replace
+    through ``app.dependency_overrides``."""
+    return Settings()
@@ -0,0 +1,23 @@
+from collections.abc import Iterator
+
+import pytest
+from fastapi.testclient import TestClient
+
+from {{PROJECT_NAME_UNDERSCORE}}.app import app
+from {{PROJECT_NAME_UNDERSCORE}}.settings import Settings, get_settings
+
+
+@pytest.fixture
+def settings() -> Settings:
+    """The settings the app sees in a test, without the environment's
+    or .env's; change them in the test to try others."""
+    return Settings(_env_file=None, app_profile=False)
+
+
+@pytest.fixture
+def client(settings: Settings) -> Iterator[TestClient]:
+    """An httpx client calling the app in-process. Outside a ``with``
+    block it does not run the lifespan, so pyroscope is left alone."""
+    app.dependency_overrides[get_settings] = lambda: settings
+    yield TestClient(app)
+    app.dependency_overrides.clear()
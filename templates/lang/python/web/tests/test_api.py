@@ -0,0 +1,28 @@
+import pytest
+from fastapi.testclient import TestClient
+
+from {{PROJECT_NAME_UNDERSCORE}}.settings import Settings
+
+
+def test_hello(client: TestClient) -> None:
+    response = client.post("/api/hello", json={"name": "Ada"})
+    assert response.status_code == 200
+    assert response.json() == {"message": "Hello, Ada!"}
+
+
+def test_hello_greets_as_the_settings_say(
+    client: TestClient, settings: Settings
+) -> None:
+    settings.greeting = "Howdy"
+    response = client.post("/api/hello", json={"name": "Ada"})
+    assert response.json() == {"message": "Howdy, Ada!"}
+
+
+@pytest.mark.parametrize(
+    "body",
+    [{}, {"name": ""}, {"name": "x" * 101}, {"name": 42}],
+)
+def test_hello_refuses_bad_bodies(client: TestClient, body: object) -> None:
+    response = client.post("/api/hello", json=body)
+    assert response.status_code == 422
+    assert response.json()["detail"][0]["loc"][:2] == ["body", "name"]
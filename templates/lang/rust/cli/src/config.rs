@@ -0,0 +1,173 @@
+//! Settings: a TOML file, then `{{PROJECT_NAME | snake | upper}}_*` environment variables.
+
+use std::{
+    fs, io,
+    path::{Path, PathBuf},
+};
+
+use serde::{Deserialize, Serialize};
+use thiserror::Error;
+
+/// What the environment variables that override settings start with.
+pub const PREFIX: &str = "{{PROJECT_NAME | snake | upper}}_";
+
+#[derive(Debug, Clone, PartialEq, Eq, Deserialize, Serialize)]
+#[serde(default, deny_unknown_fields)]
+pub struct Config {
+    /// What comes before the name (`{{PROJECT_NAME | snake | upper}}_GREETING`); "Hello".
+    pub greeting: String,
+}
+
+impl Default for Config {
+    fn default() -> Self {
+        Config {
+            greeting: "Hello".into(),
+        }
+    }
+}
+
+#[derive(Debug, Error)]
+pub enum ConfigError {
+    #[error("could not read {}", .path.display())]
+    Read {
+        path: PathBuf,
+        #[source]
+        source: io::Error,
+    },
+    #[error("{} is not a valid config file", .path.display())]
+    Parse {
+        path: PathBuf,
+        #[source]
+        source: toml::de::Error,
+    },
+}
+
+impl Config {
+    /// Loads the settings from `path` or, without one, from the default
+    /// file if there is one, and then lets the environment, as `getenv`
+    /// reads it, override them. A file that was asked for must be there.
+    pub fn load(
+        path: Option<&Path>,
+        getenv: impl Fn(&str) -> Option<String>,
+    ) -> Result<Config, ConfigError> {
+        let mut config = match path {
+            Some(path) => Config::read(path)?,
+            None => match default_path(&getenv) {
+                Some(path) if path.is_file() => Config::read(&path)?,
+                _ => Config::default(),
+            },
+        };
+        if let Some(greeting) = getenv(&format!("{PREFIX}GREETING")).filter(|v| !v.is_empty()) {
+            config.greeting = greeting;
+        }
+        Ok(config)
+    }
+
+    fn read(path: &Path) -> Result<Config, ConfigError> {
+        let text = fs::read_to_string(path).map_err(|source| ConfigError::Read {
+            path: path.into(),
+            source,
+        })?;
+        toml::from_str(&text).map_err(|source| ConfigError::Parse {
+            path: path.into(),
+            source,
+        })
+    }
+
+    /// The settings as a config file would hold them.
+    pub fn to_toml(&self) -> String {
+        toml::to_string(self).expect("a Config is always valid TOML")
+    }
+}
+
+/// `$XDG_CONFIG_HOME/{{PROJECT_NAME}}/config.toml`, or under `~/.config`
+/// when that is not set.
+pub fn default_path(getenv: impl Fn(&str) -> Option<String>) -> Option<PathBuf> {
+    let base = match getenv("XDG_CONFIG_HOME").filter(|v| !v.is_empty()) {
+        Some(dir) => PathBuf::from(dir),
+        None => PathBuf::from(getenv("HOME")?).join(".config"),
+    };
+    Some(base.join(env!("CARGO_PKG_NAME")).join("config.toml"))
+}
+
+#[cfg(test)]
+mod tests {
+    use std::collections::HashMap;
+
+    use super::*;
+
+    fn env(pairs: &[(&str, &str)]) -> impl Fn(&str) -> Option<String> {
+        let vars: HashMap<String, String> = pairs
+            .iter()
+            .map(|(k, v)| (k.to_string(), v.to_string()))
+            .collect();
+        move |key: &str| vars.get(key).cloned()
+    }
+
+    #[test]
+    fn defaults_without_a_file() {
+        let dir = tempfile::tempdir().unwrap();
+        let config = Config::load(
+            None,
+            env(&[("XDG_CONFIG_HOME", dir.path().to_str().unwrap())]),
+        )
+        .unwrap();
+        assert_eq!(config, Config::default());
+    }
+
+    #[test]
+    fn reads_the_default_file_then_the_environment() {
+        let dir = tempfile::tempdir().unwrap();
+        let path = default_path(env(&[("XDG_CONFIG_HOME", dir.path().to_str().unwrap())])).unwrap();
+        fs::create_dir_all(path.parent().unwrap()).unwrap();
+        fs::write(&path, "greeting = \"Hi\"\n").unwrap();
+
+        let xdg = dir.path().to_str().unwrap();
+        let config = Config::load(None, env(&[("XDG_CONFIG_HOME", xdg)])).unwrap();
+        assert_eq!(config.greeting, "Hi");
+
+        let greeting = format!("{PREFIX}GREETING");
+        let config = Config::load(
+            None,
+            env(&[("XDG_CONFIG_HOME", xdg), (greeting.as_str(), "Howdy")]),
+        )
+        .unwrap();
+        assert_eq!(config.greeting, "Howdy");
+    }
+
+    #[test]
+    fn a_file_asked_for_must_be_there() {
+        let dir = tempfile::tempdir().unwrap();
+        let err = Config::load(Some(&dir.path().join("missing.toml")), env(&[])).unwrap_err();
+        assert!(matches!(err, ConfigError::Read { .. }), "got {err:?}");
+    }
+
+    #[test]
+    fn refuses_a_setting_it_does_not_know() {
+        let dir = tempfile::tempdir().unwrap();
+        let path = dir.path().join("config.toml");
+        fs::write(&path, "greting = \"Hi\"\n").unwrap();
+        let err = Config::load(Some(&path), env(&[])).unwrap_err();
+        assert!(matches!(err, ConfigError::Parse { .. }), "got {err:?}");
+    }
+
+    #[test]
+    fn the_default_file_is_under_home_without_xdg() {
+        let path = default_path(env(&[("HOME", "/home/ada")])).unwrap();
+        assert_eq!(
+            path,
+            Path::new("/home/ada/.config")
+                .join(env!("CARGO_PKG_NAME"))
+                .join("config.toml")
+        );
+        assert_eq!(default_path(env(&[])), None);
+    }
+
+    #[test]
+    fn round_trips_as_toml() {
+        let config = Config {
+            greeting: "Hi".into(),
+        };
+        assert_eq!(toml::from_str::<Config>(&config.to_toml()).unwrap(), config);
+    }
+}
@@ -0,0 +1,43 @@
+//! The JSON API: serde types in and out, and errors as `{"error": ...}`
+//! with a status to match.
+
+use axum::{http::StatusCode, Json};
+use serde::{Deserialize, Serialize};
+
+#[derive(Debug, Deserialize)]
+pub struct HelloRequest {
+    pub name: String,
+}
+
+#[derive(Debug, Serialize, Deserialize, PartialEq, Eq)]
+pub struct Hello {
+    pub message: String,
+}
+
+#[derive(Debug, Serialize, Deserialize)]
+pub struct ApiError {
+    pub error: String,
+}
+
+pub type ApiResult<T> = Result<Json<T>, (StatusCode, Json<ApiError>)>;
+
+fn error(status: StatusCode, message: &str) -> (StatusCode, Json<ApiError>) {
+    (
+        status,
+        Json(ApiError {
+            error: message.into(),
+        }),
+    )
+}
+
+/// POST /api/hello with `{"name": "Ada"}` answers `{"message": "Hello, Ada!"}`.
+/// A body that is not that JSON is refused by axum's `Json` before this runs.
+pub async fn handle_hello(Json(req): Json<HelloRequest>) -> ApiResult<Hello> {
+    let name = req.name.trim();
+    if name.is_empty() {
+        return Err(error(StatusCode::UNPROCESSABLE_ENTITY, "name is required"));
+    }
+    Ok(Json(Hello {
+        message: format!("Hello, {name}!"),
+    }))
+}
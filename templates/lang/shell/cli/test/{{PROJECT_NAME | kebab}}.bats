@@ -0,0 +1,53 @@
+#!/usr/bin/env bats
+
+bats_require_minimum_version 1.5.0
+
+setup() {
+  SCRIPT="$BATS_TEST_DIRNAME/../bin/{{PROJECT_NAME | kebab}}"
+}
+
+@test "greets by name" {
+  run --separate-stderr "$SCRIPT" Ada
+  [ "$status" -eq 0 ]
+  [ "$output" = "Hello, Ada!" ]
+  [ "$stderr" = "{{PROJECT_NAME | kebab}}: greeting Ada" ]
+}
+
+@test "greets the world without a name" {
+  run --separate-stderr "$SCRIPT"
+  [ "$status" -eq 0 ]
+  [ "$output" = "Hello, World!" ]
+}
+
+@test "takes the greeting as an option" {
+  run -0 --separate-stderr "$SCRIPT" --quiet -g Hi Ada
+  [ "$output" = "Hi, Ada!" ]
+  [ -z "$stderr" ]
+
+  run -0 --separate-stderr "$SCRIPT" --greeting=Hei -- -ada
+  [ "$output" = "Hei, -ada!" ]
+}
+
+@test "refuses an unknown option with the usage" {
+  run -2 --separate-stderr "$SCRIPT" --nope
+  [[ $stderr == Usage:* ]]
+  [[ $stderr == *"unknown option: --nope" ]]
+}
+
+@test "fails when an option lacks its value" {
+  run -1 --separate-stderr "$SCRIPT" --greeting
+  [ "$stderr" = "{{PROJECT_NAME | kebab}}: error: --greeting needs a value" ]
+}
+
+# In a shell of its own, so that the script's strict mode and ERR trap
+# stay out of bats'.
+greet() {
+  bash -c 'source "$1" && greet "${@:2}"' _ "$SCRIPT" "$@"
+}
+
+@test "greet trims the name" {
+  run -0 greet Hello "  Ada Lovelace  "
+  [ "$output" = "Hello, Ada Lovelace!" ]
+  run -0 greet Hello " "
+  [ "$output" = "Hello, World!" ]
+}
@@ -0,0 +1,13 @@
+// @ts-check
+import eslint from "@eslint/js";
+import prettier from "eslint-config-prettier";
+import tseslint from "typescript-eslint";
+
+// Prettier owns the formatting, so its config comes last and turns off
+// the rules that would argue with it.
+export default tseslint.config(
+	{ ignores: ["dist/", "coverage/"] },
+	eslint.configs.recommended,
+	tseslint.configs.strict,
+	prettier,
+);
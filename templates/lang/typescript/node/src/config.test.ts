@@ -0,0 +1,18 @@
+import { describe, expect, it } from "vitest";
+
+import { loadConfig } from "./config.js";
+
+describe("loadConfig", () => {
+	it("falls back to the defaults", () => {
+		expect(loadConfig({})).toEqual({ port: 4000, greeting: "Hello", drainTimeout: 10_000 });
+	});
+
+	it("reads the environment", () => {
+		const config = loadConfig({ PORT: "8080", GREETING: "Hei", DRAIN_TIMEOUT: "2" });
+		expect(config).toEqual({ port: 8080, greeting: "Hei", drainTimeout: 2000 });
+	});
+
+	it.each(["http", "-1", "1.5"])("refuses PORT=%s", (port) => {
+		expect(() => loadConfig({ PORT: port })).toThrow(/^PORT: expected a whole number/);
+	});
+});
@@ -0,0 +1,28 @@
+export type Config = {
+	port: number;
+	greeting: string;
+	// How long open connections get to finish on shutdown, in ms.
+	drainTimeout: number;
+};
+
+// loadConfig reads the settings from env, the process's environment
+// unless a test hands it its own, and throws on one it cannot use.
+export function loadConfig(env: NodeJS.ProcessEnv = process.env): Config {
+	return {
+		port: integer(env, "PORT", 4000),
+		greeting: env.GREETING || "Hello",
+		drainTimeout: integer(env, "DRAIN_TIMEOUT", 10) * 1000,
+	};
+}
+
+function integer(env: NodeJS.ProcessEnv, key: string, fallback: number): number {
+	const value = env[key];
+	if (value === undefined || value === "") {
+		return fallback;
+	}
+	const n = Number(value);
+	if (!Number.isInteger(n) || n < 0) {
+		throw new Error(`${key}: expected a whole number, got ${JSON.stringify(value)}`);
+	}
+	return n;
+}
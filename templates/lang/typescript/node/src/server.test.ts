@@ -0,0 +1,67 @@
+import type { AddressInfo } from "node:net";
+
+import { afterEach, beforeEach, describe, expect, it } from "vitest";
+
+import { loadConfig } from "./config.js";
+import { app, shutdown } from "./server.js";
+
+describe("server", () => {
+	let server: ReturnType<typeof app>;
+	let url: string;
+
+	beforeEach(async () => {
+		server = app(loadConfig({ GREETING: "Hei" }));
+		await new Promise<void>((resolve) => server.listen(0, "127.0.0.1", resolve));
+		url = `http://127.0.0.1:${(server.address() as AddressInfo).port}`;
+	});
+
+	afterEach(async () => {
+		if (server.listening) {
+			await shutdown(server, 100);
+		}
+	});
+
+	it("greets on /", async () => {
+		const res = await fetch(url);
+		expect(res.status).toBe(200);
+		expect(await res.text()).toContain("Hei from");
+	});
+
+	it("answers /healthz", async () => {
+		const res = await fetch(`${url}/healthz`);
+		expect(res.status).toBe(200);
+		expect(await res.json()).toEqual({ status: "ok" });
+	});
+
+	it("says hello through the API", async () => {
+		const res = await fetch(`${url}/api/hello`, {
+			method: "POST",
+			headers: { "Content-Type": "application/json" },
+			body: JSON.stringify({ name: " Ada " }),
+		});
+		expect(res.status).toBe(200);
+		expect(await res.json()).toEqual({ message: "Hei, Ada!" });
+	});
+
+	it.each([
+		["{}", 422],
+		['{"name": ""}', 422],
+		["not json", 400],
+		["x".repeat(65 * 1024), 413],
+	])("refuses the body %#", async (body, status) => {
+		const res = await fetch(`${url}/api/hello`, { method: "POST", body });
+		expect(res.status).toBe(status);
+		expect(await res.json()).toHaveProperty("error");
+	});
+
+	it("answers 404 for an unknown route", async () => {
+		const res = await fetch(`${url}/no/such/page`);
+		expect(res.status).toBe(404);
+	});
+
+	it("shuts down with nothing open", async () => {
+		await fetch(`${url}/healthz`);
+		await shutdown(server, 1000);
+		expect(server.listening).toBe(false);
+	});
+});
@@ -0,0 +1,29 @@
+import { render, screen } from "@testing-library/svelte";
+import userEvent from "@testing-library/user-event";
+import { beforeEach, describe, expect, it } from "vitest";
+
+import App from "./App.svelte";
+import { navigate } from "./lib/router";
+
+describe("App", () => {
+	beforeEach(() => navigate("/"));
+
+	it("renders the home page", () => {
+		render(App);
+		expect(screen.getByRole("heading", { level: 1 })).toHaveTextContent("Hello, World!");
+	});
+
+	it("follows the navigation", async () => {
+		render(App);
+		await userEvent.click(screen.getByRole("link", { name: "About" }));
+		expect(screen.getByRole("heading", { level: 1 })).toHaveTextContent("About");
+		expect(window.location.pathname).toBe("/about");
+	});
+
+	it("renders the not-found page for an unknown path", () => {
+		navigate("/no/such/page");
+		render(App);
+		expect(screen.getByRole("heading", { level: 1 })).toHaveTextContent("Not found");
+		expect(screen.getByText("/no/such/page")).toBeInTheDocument();
+	});
+});
@@ -0,0 +1,20 @@
+import { render, screen } from "@testing-library/svelte";
+import userEvent from "@testing-library/user-event";
+import { describe, expect, it } from "vitest";
+
+import Greeting from "./Greeting.svelte";
+
+describe("Greeting", () => {
+	it("greets the world until a name is typed", async () => {
+		render(Greeting);
+		expect(screen.getByRole("status")).toHaveTextContent("Hello, World!");
+
+		await userEvent.type(screen.getByLabelText("Name"), "Ada");
+		expect(screen.getByRole("status")).toHaveTextContent("Hello, Ada!");
+	});
+
+	it("takes its greeting", () => {
+		render(Greeting, { greeting: "Hei" });
+		expect(screen.getByRole("status")).toHaveTextContent("Hei, World!");
+	});
+});
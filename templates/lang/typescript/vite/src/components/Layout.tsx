@@ -0,0 +1,22 @@
+import { NavLink, Outlet } from "react-router";
+
+import { config } from "../config";
+
+export function Layout() {
+	return (
+		<>
+			<header>
+				<strong>{config.title}</strong>
+				<nav>
+					<NavLink to="/" end>
+						Home
+					</NavLink>
+					<NavLink to="/about">About</NavLink>
+				</nav>
+			</header>
+			<main>
+				<Outlet />
+			</main>
+		</>
+	);
+}
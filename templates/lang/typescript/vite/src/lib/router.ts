@@ -0,0 +1,29 @@
+import { writable } from "svelte/store";
+
+// The path the app shows, kept in step with the browser's history.
+export const path = writable(window.location.pathname);
+
+window.addEventListener("popstate", () => path.set(window.location.pathname));
+
+export function navigate(to: string): void {
+	if (to !== window.location.pathname) {
+		window.history.pushState(null, "", to);
+	}
+	path.set(to);
+}
+
+// link makes <a href="/about" use:link> change the page without a
+// reload; a click with a modifier (a new tab) is left to the browser.
+export function link(node: HTMLAnchorElement) {
+	const onclick = (e: MouseEvent) => {
+		if (e.button !== 0 || e.metaKey || e.ctrlKey || e.shiftKey || e.altKey) {
+			return;
+		}
+		e.preventDefault();
+		navigate(node.pathname);
+	};
+	node.addEventListener("click", onclick);
+	return {
+		destroy: () => node.removeEventListener("click", onclick),
+	};
+}
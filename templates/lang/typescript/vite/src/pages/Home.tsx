@@ -0,0 +1,10 @@
+import { Greeting } from "../components/Greeting";
+
+export function Home() {
+	return (
+		<>
+			<h1>Hello, World!</h1>
+			<Greeting />
+		</>
+	);
+}
@@ -0,0 +1,13 @@
+import { useLocation } from "react-router";
+
+export function NotFound() {
+	const { pathname } = useLocation();
+	return (
+		<>
+			<h1>Not found</h1>
+			<p>
+				There is no page at <code>{pathname}</code>.
+			</p>
+		</>
+	);
+}
@@ -0,0 +1,19 @@
+import type { RouteObject } from "react-router";
+
+import { Layout } from "./components/Layout";
+import { About } from "./pages/About";
+import { Home } from "./pages/Home";
+import { NotFound } from "./pages/NotFound";
+
+// The app's pages, under the layout; main.tsx serves them from the
+// browser's history, and the tests from memory.
+export const routes: RouteObject[] = [
+	{
+		element: <Layout />,
+		children: [
+			{ index: true, element: <Home /> },
+			{ path: "about", element: <About /> },
+			{ path: "*", element: <NotFound /> },
+		],
+	},
+];
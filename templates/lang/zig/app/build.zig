@@ -0,0 +1,48 @@
+const std = @import("std");
+
+pub fn build(b: *std.Build) void {
+    const target = b.standardTargetOptions(.{});
+    const optimize = b.standardOptimizeOption(.{});
+
+    // src/greet.zig as a module of its own, which main imports as
+    // "greet"; another package could depend on it the same way.
+    const greet = b.addModule("greet", .{
+        .root_source_file = b.path("src/greet.zig"),
+        .target = target,
+        .optimize = optimize,
+    });
+
+    const exe = b.addExecutable(.{
+        .name = "{{PROJECT_NAME}}",
+        .root_source_file = b.path("src/main.zig"),
+        .target = target,
+        .optimize = optimize,
+    });
+    exe.root_module.addImport("greet", greet);
+    b.installArtifact(exe);
+
+    const run_cmd = b.addRunArtifact(exe);
+    run_cmd.step.dependOn(b.getInstallStep());
+    if (b.args) |args| {
+        run_cmd.addArgs(args);
+    }
+    const run_step = b.step("run", "Run the app");
+    run_step.dependOn(&run_cmd.step);
+
+    // zig build test runs the tests in both files.
+    const greet_tests = b.addTest(.{
+        .root_source_file = b.path("src/greet.zig"),
+        .target = target,
+        .optimize = optimize,
+    });
+    const exe_tests = b.addTest(.{
+        .root_source_file = b.path("src/main.zig"),
+        .target = target,
+        .optimize = optimize,
+    });
+    exe_tests.root_module.addImport("greet", greet);
+
+    const test_step = b.step("test", "Run the tests");
+    test_step.dependOn(&b.addRunArtifact(greet_tests).step);
+    test_step.dependOn(&b.addRunArtifact(exe_tests).step);
+}
@@ -0,0 +1,34 @@
+//! Greetings, apart from main so that they can be tested on their own
+//! and imported by other code.
+
+const std = @import("std");
+
+/// Writes "Hello, NAME!" and a newline to writer, with "World" for a
+/// name that is empty or blank.
+pub fn greet(writer: anytype, name: []const u8) !void {
+    const trimmed = std.mem.trim(u8, name, " \t\r\n");
+    try writer.print("Hello, {s}!\n", .{if (trimmed.len == 0) "World" else trimmed});
+}
+
+fn expectGreeting(expected: []const u8, name: []const u8) !void {
+    var out = std.ArrayList(u8).init(std.testing.allocator);
+    defer out.deinit();
+    try greet(out.writer(), name);
+    try std.testing.expectEqualStrings(expected, out.items);
+}
+
+test "greets by name" {
+    try expectGreeting("Hello, Ada!\n", "Ada");
+    try expectGreeting("Hello, Ada Lovelace!\n", "  Ada Lovelace\n");
+}
+
+test "greets the world without a name" {
+    try expectGreeting("Hello, World!\n", "");
+    try expectGreeting("Hello, World!\n", " \t ");
+}
+
+test "passes on the writer's errors" {
+    var buf: [4]u8 = undefined;
+    var stream = std.io.fixedBufferStream(&buf);
+    try std.testing.expectError(error.NoSpaceLeft, greet(stream.writer(), "Ada"));
+}
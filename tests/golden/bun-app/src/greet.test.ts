@@ -0,0 +1,13 @@
+import { describe, expect, it } from "bun:test";
+import { greet } from "./greet";
+
+describe("greet", () => {
+	it("greets by name", () => {
+		expect(greet("Ada")).toBe("Hello, Ada!");
+	});
+
+	it("greets the world when no name is given", () => {
+		expect(greet()).toBe("Hello, World!");
+		expect(greet("  ")).toBe("Hello, World!");
+	});
+});
@@ -0,0 +1,15 @@
+#ifndef GREET_H
+#define GREET_H
+
+#include <stddef.h>
+
+/*
+ * Writes "Hello, NAME!" to buf, or "Hello, World!" for a name that is
+ * NULL, empty or blank, with the whitespace around it trimmed. Like
+ * snprintf it returns the length of the whole greeting, so a result of
+ * size or more means buf held only the start of it; greet(NULL, 0, name)
+ * measures it.
+ */
+int greet(char *buf, size_t size, const char *name);
+
+#endif
@@ -0,0 +1,52 @@
+#include <string.h>
+
+#include "greatest.h"
+#include "greet.h"
+
+TEST greets_by_name(void)
+{
+    char buf[64];
+
+    ASSERT_EQ(11, greet(buf, sizeof buf, "Ada"));
+    ASSERT_STR_EQ("Hello, Ada!", buf);
+    greet(buf, sizeof buf, "  Ada Lovelace\n");
+    ASSERT_STR_EQ("Hello, Ada Lovelace!", buf);
+    PASS();
+}
+
+TEST greets_the_world_without_a_name(void)
+{
+    char buf[64];
+
+    greet(buf, sizeof buf, NULL);
+    ASSERT_STR_EQ("Hello, World!", buf);
+    greet(buf, sizeof buf, " \t ");
+    ASSERT_STR_EQ("Hello, World!", buf);
+    PASS();
+}
+
+TEST truncates_to_the_buffer(void)
+{
+    char buf[6];
+
+    ASSERT_EQ(11, greet(buf, sizeof buf, "Ada"));
+    ASSERT_STR_EQ("Hello", buf);
+    ASSERT_EQ(11, greet(NULL, 0, "Ada"));
+    PASS();
+}
+
+SUITE(greet_suite)
+{
+    RUN_TEST(greets_by_name);
+    RUN_TEST(greets_the_world_without_a_name);
+    RUN_TEST(truncates_to_the_buffer);
+}
+
+GREATEST_MAIN_DEFS();
+
+int main(int argc, char **argv)
+{
+    GREATEST_MAIN_BEGIN();
+    RUN_SUITE(greet_suite);
+    GREATEST_MAIN_END();
+}
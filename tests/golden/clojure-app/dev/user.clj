@@ -0,0 +1,17 @@
+(ns user
+  "Where the REPL starts under the :dev alias: (reset) reloads what
+  changed on disk, and (run-tests) does that and then runs the tests."
+  (:require [clojure.test :as test]
+            [clojure.tools.namespace.repl :as repl]))
+
+;; Not dev/, so reloading leaves this namespace alone.
+(repl/set-refresh-dirs "src" "test")
+
+(defn reset []
+  (repl/refresh))
+
+(defn run-tests []
+  (let [result (reset)]
+    (if (= :ok result)
+      (test/run-all-tests #"golden-app\..*-test")
+      result)))
@@ -0,0 +1,12 @@
+#pragma once
+
+#include <string>
+#include <string_view>
+
+namespace golden_app {
+
+// "Hello, NAME!", or "Hello, World!" for a name that is empty or blank,
+// with the whitespace around it trimmed.
+[[nodiscard]] std::string greet(std::string_view name);
+
+}  // namespace golden_app
@@ -0,0 +1,10 @@
+#include <cstddef>
+#include <iostream>
+#include <span>
+
+#include "golden_app/greet.hpp"
+
+int main(int argc, char* argv[]) {
+    const std::span args(argv, static_cast<std::size_t>(argc));
+    std::cout << golden_app::greet(args.size() > 1 ? args[1] : "") << '\n';
+}
@@ -0,0 +1,15 @@
+#include "golden_app/greet.hpp"
+
+#include <gtest/gtest.h>
+
+using golden_app::greet;
+
+TEST(Greet, GreetsByName) {
+    EXPECT_EQ(greet("Ada"), "Hello, Ada!");
+    EXPECT_EQ(greet("  Ada Lovelace\n"), "Hello, Ada Lovelace!");
+}
+
+TEST(Greet, GreetsTheWorldWithoutAName) {
+    EXPECT_EQ(greet(""), "Hello, World!");
+    EXPECT_EQ(greet(" \t "), "Hello, World!");
+}
@@ -0,0 +1,4 @@
+/** greet says hello to name, or to the world when it is blank. */
+export function greet(name?: string | null): string {
+  return `Hello, ${name?.trim() || "World"}!`;
+}
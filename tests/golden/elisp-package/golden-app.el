@@ -0,0 +1,48 @@
+;;; golden-app.el --- A project rendered for the golden snapshots  -*- lexical-binding: t; -*-
+
+;; Copyright (C) 2000  Golden Author
+
+;; Author: Golden Author <golden@example.com>
+;; Version: 0.1.0
+;; Package-Requires: ((emacs "27.1"))
+;; Keywords: convenience
+;; SPDX-License-Identifier: MIT
+
+;; This file is not part of GNU Emacs.
+
+;;; Commentary:
+
+;; A project rendered for the golden snapshots.
+;;
+;; M-x golden-app-greet shows a greeting in the echo area, for the
+;; name it asks for or, given none, for `golden-app-default-name'.
+
+;;; Code:
+
+(require 'subr-x)
+
+(defgroup golden-app nil
+  "A project rendered for the golden snapshots."
+  :prefix "golden-app-"
+  :group 'applications)
+
+(defcustom golden-app-default-name "World"
+  "Who `golden-app-greet' greets when given no name."
+  :type 'string)
+
+(defun golden-app-greeting (name)
+  "Return \"Hello, NAME!\".
+A NAME that is nil or blank greets `golden-app-default-name'."
+  (let ((name (string-trim (or name ""))))
+    (format "Hello, %s!"
+            (if (string-empty-p name) golden-app-default-name name))))
+
+;;;###autoload
+(defun golden-app-greet (name)
+  "Show a greeting for NAME in the echo area.
+Interactively, ask for NAME."
+  (interactive "sName: ")
+  (message "%s" (golden-app-greeting name)))
+
+(provide 'golden-app)
+;;; golden-app.el ends here
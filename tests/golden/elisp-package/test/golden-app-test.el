@@ -0,0 +1,27 @@
+;;; golden-app-test.el --- Tests for golden-app  -*- lexical-binding: t; -*-
+
+;;; Commentary:
+
+;; Run with eask test ert test/*.el.
+
+;;; Code:
+
+(require 'ert)
+(require 'golden-app)
+
+(ert-deftest golden-app-test-greeting ()
+  (should (equal (golden-app-greeting "Ada") "Hello, Ada!"))
+  (should (equal (golden-app-greeting "  Ada Lovelace\n")
+                 "Hello, Ada Lovelace!")))
+
+(ert-deftest golden-app-test-greeting-default-name ()
+  (should (equal (golden-app-greeting nil) "Hello, World!"))
+  (should (equal (golden-app-greeting " \t ") "Hello, World!"))
+  (let ((golden-app-default-name "you"))
+    (should (equal (golden-app-greeting "") "Hello, you!"))))
+
+(ert-deftest golden-app-test-greet ()
+  (should (equal (golden-app-greet "Ada") "Hello, Ada!")))
+
+(provide 'golden-app-test)
+;;; golden-app-test.el ends here
@@ -0,0 +1,60 @@
+defmodule GoldenApp.MixProject do
+  use Mix.Project
+
+  @version "0.1.0"
+
+  def project do
+    [
+      app: :golden_app,
+      version: @version,
+      elixir: "~> 1.15",
+      start_permanent: Mix.env() == :prod,
+      deps: deps(),
+      description: ~S"A project rendered for the golden snapshots.",
+      package: package(),
+      docs: docs(),
+      dialyzer: dialyzer()
+    ]
+  end
+
+  def application do
+    [extra_applications: [:logger]]
+  end
+
+  defp deps do
+    [
+      {:dialyxir, "~> 1.4", only: [:dev, :test], runtime: false},
+      {:ex_doc, "~> 0.35", only: :dev, runtime: false}
+    ]
+  end
+
+  # What hex.pm shows for the package; `mix hex.build` checks it, and
+  # `mix hex.publish` sends it with the docs.
+  defp package do
+    [
+      licenses: ["MIT"],
+      # links: %{"GitHub" => "https://github.com/you/golden-app"},
+      links: %{},
+      files: ~w(lib mix.exs .formatter.exs README.md LICENSE)
+    ]
+  end
+
+  defp docs do
+    [
+      main: "GoldenApp",
+      authors: [~S"Golden Author"],
+      source_ref: "v#{@version}",
+      extras: ["README.md"]
+    ]
+  end
+
+  # The PLTs are kept in priv/plts/, out of git, so CI can cache them.
+  defp dialyzer do
+    [
+      plt_core_path: "priv/plts",
+      plt_local_path: "priv/plts",
+      plt_add_apps: [:ex_unit],
+      flags: [:error_handling, :extra_return, :missing_return, :underspecs]
+    ]
+  end
+end
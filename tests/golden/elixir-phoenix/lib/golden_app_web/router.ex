@@ -0,0 +1,28 @@
+defmodule GoldenAppWeb.Router do
+  use GoldenAppWeb, :router
+
+  pipeline :browser do
+    plug :accepts, ["html"]
+    plug :fetch_session
+    plug :fetch_live_flash
+    plug :put_root_layout, html: {GoldenAppWeb.Layouts, :root}
+    plug :protect_from_forgery
+    plug :put_secure_browser_headers
+  end
+
+  pipeline :api do
+    plug :accepts, ["json"]
+  end
+
+  scope "/", GoldenAppWeb do
+    pipe_through :browser
+
+    get "/", PageController, :home
+    live "/counter", CounterLive
+  end
+
+  # Other scopes may use custom stacks.
+  # scope "/api", GoldenAppWeb do
+  #   pipe_through :api
+  # end
+end
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGreetHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		status int
+		body   string
+	}{
+		{"default", "/api/v1/greet", http.StatusOK, `{"message":"Hello, world!"}`},
+		{"name", "/api/v1/greet?name=Ada", http.StatusOK, `{"message":"Hello, Ada!"}`},
+		{"blank name", "/api/v1/greet?name=", http.StatusUnprocessableEntity,
+			`{"error":{"code":"invalid","message":"the request is invalid","fields":{"name":"is required"}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handleGreet(w, httptest.NewRequest("GET", tt.url, nil))
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != tt.body {
+				t.Errorf("expected %s, got %s", tt.body, body)
+			}
+		})
+	}
+}
+
+func TestCreateGreetingHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string // in the response
+	}{
+		{"valid", `{"name": "Ada"}`, http.StatusOK, `"message":"Hello, Ada!"`},
+		{"missing name", `{}`, http.StatusUnprocessableEntity, `"name":"is required"`},
+		{"too long", `{"name": "` + strings.Repeat("a", 101) + `"}`, http.StatusUnprocessableEntity, `"name":"must be at most 100 characters"`},
+		{"unknown field", `{"name": "Ada", "age": 36}`, http.StatusBadRequest, `"code":"bad_request"`},
+		{"not JSON", `name=Ada`, http.StatusBadRequest, `"code":"bad_request"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/greet", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handleCreateGreeting(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("expected %s in %s", tt.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleNotFound(w, httptest.NewRequest("GET", "/api/v0/greet", nil))
+
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), `"code":"not_found"`) {
+		t.Errorf("expected a 404 in the envelope, got %d %s", w.Code, w.Body)
+	}
+}
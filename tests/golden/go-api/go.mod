@@ -0,0 +1,4 @@
+// SPDX-License-Identifier: MIT
+module golden-app
+
+go 1.22
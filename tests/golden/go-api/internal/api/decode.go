@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// MaxBody is how large a request body Decode reads.
+const MaxBody = 1 << 20
+
+// A Validator checks a request after decoding, saying what is wrong
+// with each bad field; none is valid.
+type Validator interface {
+	Validate() map[string]string
+}
+
+// Decode reads r's JSON body into v, which must be the whole body, and
+// validates v when it is a Validator. The error is an *Error for
+// WriteError: a 415 for a body that is not JSON, a 413 for one over
+// MaxBody, a 400 for one that does not fit v (unknown fields
+// included) and a 422 for one that is not valid.
+func Decode(w http.ResponseWriter, r *http.Request, v any) error {
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		return Errorf(http.StatusUnsupportedMediaType, "unsupported_media_type", "the body must be application/json")
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	if dec.Decode(&struct{}{}) != io.EOF {
+		return Errorf(http.StatusBadRequest, "bad_request", "the body must be a single JSON value")
+	}
+	return Validate(v)
+}
+
+// Validate is an *Error with v's problems, for a 422, when v is a
+// Validator that finds some, and nil otherwise; for requests that come
+// in the query or path rather than the body.
+func Validate(v any) error {
+	validator, ok := v.(Validator)
+	if !ok {
+		return nil
+	}
+	if problems := validator.Validate(); len(problems) > 0 {
+		e := Errorf(http.StatusUnprocessableEntity, "invalid", "the request is invalid")
+		e.Fields = problems
+		return e
+	}
+	return nil
+}
+
+// decodeError says what was wrong with a body json could not decode,
+// in terms the client can fix.
+func decodeError(err error) *Error {
+	var syntax *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	var tooLarge *http.MaxBytesError
+	msg := ""
+	switch {
+	case errors.As(err, &tooLarge):
+		return Errorf(http.StatusRequestEntityTooLarge, "too_large", "the body is larger than %d bytes", tooLarge.Limit)
+	case errors.Is(err, io.EOF):
+		msg = "the body is empty"
+	case errors.As(err, &syntax), errors.Is(err, io.ErrUnexpectedEOF):
+		msg = "the body is not valid JSON"
+	case errors.As(err, &typ) && typ.Field != "":
+		msg = fmt.Sprintf("%s cannot be a JSON %s", typ.Field, typ.Value)
+	case errors.As(err, &typ):
+		msg = fmt.Sprintf("the body cannot be a JSON %s", typ.Value)
+	default:
+		// Unknown fields come as a plain error: json: unknown field "x".
+		msg = strings.TrimPrefix(err.Error(), "json: ")
+	}
+	return Errorf(http.StatusBadRequest, "bad_request", "%s", msg)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type note struct {
+	Title string `json:"title"`
+	Stars int    `json:"stars"`
+}
+
+func (n note) Validate() map[string]string {
+	problems := map[string]string{}
+	if n.Title == "" {
+		problems["title"] = "is required"
+	}
+	return problems
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int    // of the error, 0 for none
+		message     string // what it says
+	}{
+		{"valid", "application/json", `{"title": "Milk", "stars": 2}`, 0, ""},
+		{"charset", "application/json; charset=utf-8", `{"title": "Milk"}`, 0, ""},
+		{"not JSON", "text/plain", `{"title": "Milk"}`, http.StatusUnsupportedMediaType, "application/json"},
+		{"empty", "application/json", ``, http.StatusBadRequest, "empty"},
+		{"broken", "application/json", `{"title": "Milk"`, http.StatusBadRequest, "not valid JSON"},
+		{"wrong type", "application/json", `{"title": "Milk", "stars": "two"}`, http.StatusBadRequest, "stars cannot be a JSON string"},
+		{"not an object", "application/json", `["Milk"]`, http.StatusBadRequest, "cannot be a JSON array"},
+		{"unknown field", "application/json", `{"title": "Milk", "owner": "me"}`, http.StatusBadRequest, `unknown field "owner"`},
+		{"two values", "application/json", `{"title": "Milk"} {}`, http.StatusBadRequest, "single JSON value"},
+		{"too large", "application/json", `{"title": "` + strings.Repeat("a", MaxBody) + `"}`, http.StatusRequestEntityTooLarge, "larger than"},
+		{"invalid", "application/json", `{"stars": 2}`, http.StatusUnprocessableEntity, "invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/notes", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			var n note
+			err := Decode(httptest.NewRecorder(), req, &n)
+
+			var e *Error
+			switch {
+			case tt.status == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.status == 0 && n.Title != "Milk":
+				t.Errorf("expected the note decoded, got %+v", n)
+			case tt.status != 0 && !errors.As(err, &e):
+				t.Fatalf("expected an *Error, got %v", err)
+			case tt.status != 0 && (e.Status != tt.status || !strings.Contains(e.Message, tt.message)):
+				t.Errorf("expected %d saying %q, got %d %q", tt.status, tt.message, e.Status, e.Message)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var e *Error
+	if err := Validate(note{}); !errors.As(err, &e) || e.Fields["title"] != "is required" {
+		t.Errorf("expected the title's problem, got %v", err)
+	}
+	if err := Validate(note{Title: "Milk"}); err != nil {
+		t.Errorf("expected a valid note to pass, got %v", err)
+	}
+	if err := Validate(struct{}{}); err != nil {
+		t.Errorf("expected a value without Validate to pass, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "slow")
+		select {
+		case <-r.Context().Done():
+		case <-time.After(100 * time.Millisecond):
+			w.Write([]byte("finished"))
+		}
+	})
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "fast")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("done"))
+	})
+	h := Timeout(20*time.Millisecond, "/debug/")
+
+	w := httptest.NewRecorder()
+	h(fast).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusCreated || w.Body.String() != "done" || w.Header().Get("X-Handler") != "fast" {
+		t.Errorf("a fast handler should answer as it wrote, got %d %q %v", w.Code, w.Body, w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	h(slow).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+	if w.Header().Get("X-Handler") != "" || strings.Contains(w.Body.String(), "finished") {
+		t.Errorf("expected what the handler wrote dropped, got %v %q", w.Header(), w.Body)
+	}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/debug/pprof/profile", nil),
+		httptest.NewRequest("GET", "/events", nil),
+		httptest.NewRequest("GET", "/ws", nil),
+	} {
+		if req.URL.Path == "/events" {
+			req.Header.Set("Accept", "text/event-stream")
+		}
+		if req.URL.Path == "/ws" {
+			req.Header.Set("Upgrade", "websocket")
+		}
+		w = httptest.NewRecorder()
+		h(slow).ServeHTTP(w, req)
+		if w.Body.String() != "finished" {
+			t.Errorf("%s should not be timed, got %d %q", req.URL.Path, w.Code, w.Body)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	Timeout(0)(fast).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusCreated {
+		t.Errorf("0 should be no limit, got %d", w.Code)
+	}
+}
+
+func TestTimeoutPassesPanicsOn(t *testing.T) {
+	h := Timeout(time.Second)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+	defer func() {
+		if err := recover(); err != "boom" {
+			t.Errorf("expected the panic passed on, got %v", err)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func TestMaxBytes(t *testing.T) {
+	var readErr error
+	h := MaxBytes(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader("small")))
+	if w.Code != http.StatusOK || readErr != nil {
+		t.Errorf("a small body should pass, got %d %v", w.Code, readErr)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/", strings.NewReader("far too large")))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 from Content-Length, got %d", w.Code)
+	}
+
+	// Without a Content-Length the handler finds out as it reads.
+	req := httptest.NewRequest("POST", "/", io.NopCloser(strings.NewReader("far too large")))
+	req.ContentLength = -1
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if readErr == nil {
+		t.Error("expected reading past the limit to fail")
+	}
+}
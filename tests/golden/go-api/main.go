@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"golden-app/internal/api"
+	"golden-app/internal/config"
+	"golden-app/internal/logging"
+	"golden-app/internal/middleware"
+)
+
+func main() {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		slog.Error("config", "err", err)
+		os.Exit(1)
+	}
+	logger := logging.New(cfg.Env, cfg.LogLevel, os.Stderr)
+	slog.SetDefault(logger)
+
+	// What /readyz waits on: a check for each dependency, by name.
+	ready := map[string]func(context.Context) error{}
+
+	// A change that would break v1's clients goes under /api/v2/, beside
+	// v1, rather than into it.
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/greet", handleGreet)
+	mux.HandleFunc("POST /api/v1/greet", handleCreateGreeting)
+	mux.HandleFunc("GET /healthz", handleHealthz)
+	mux.HandleFunc("GET /readyz", handleReadyz(ready))
+	mux.HandleFunc("/", handleNotFound)
+	if cfg.Profile {
+		mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+		mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	}
+
+	// Request IDs first so the log lines carry them; recovery inside the
+	// logger so a panic is logged as the 500 it becomes; the checks inside
+	// both, so what they turn away is logged too, and CORS ahead of the
+	// rate limit so a page can read the 429 it gets.
+	handler := middleware.Chain(mux,
+		middleware.RequestID,
+		middleware.Logger(logger),
+		middleware.Recover,
+		middleware.Timeout(cfg.RequestTimeout, "/debug/pprof/"),
+		middleware.MaxBytes(cfg.MaxBodyBytes),
+		middleware.Under("/api/", middleware.CORS(cfg.CORSOrigins, cfg.CORSMethods, cfg.CORSHeaders)),
+		middleware.Under("/api/", middleware.RateLimit(cfg.RateLimit, cfg.RateBurst)),
+	)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		logger.Info("listening", "port", cfg.Port, "url", cfg.BaseURL)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("serve", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // a second signal stops the process at once
+	logger.Info("shutting down")
+	// In-flight requests get this long to finish.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("shutdown", "err", err)
+		os.Exit(1)
+	}
+}
+
+// handleNotFound answers what no route matches, in the envelope like
+// every other error.
+func handleNotFound(w http.ResponseWriter, r *http.Request) {
+	api.WriteError(w, r, api.Errorf(http.StatusNotFound, "not_found", "no route for %s %s", r.Method, r.URL.Path))
+}
+
+// greetRequest is the body POST /api/v1/greet takes; GET takes the name
+// from ?name= instead.
+type greetRequest struct {
+	Name string `json:"name"`
+}
+
+// Validate says what is wrong with each bad field, for api.Decode and
+// api.Validate.
+func (g greetRequest) Validate() map[string]string {
+	problems := map[string]string{}
+	switch {
+	case strings.TrimSpace(g.Name) == "":
+		problems["name"] = "is required"
+	case utf8.RuneCountInString(g.Name) > 100:
+		problems["name"] = "must be at most 100 characters"
+	}
+	return problems
+}
+
+type greeting struct {
+	Message string `json:"message"`
+}
+
+func handleGreet(w http.ResponseWriter, r *http.Request) {
+	req := greetRequest{Name: r.URL.Query().Get("name")}
+	if !r.URL.Query().Has("name") {
+		req.Name = "world"
+	}
+	if err := api.Validate(req); err != nil {
+		api.WriteError(w, r, err)
+		return
+	}
+	greet(w, r, req)
+}
+
+func handleCreateGreeting(w http.ResponseWriter, r *http.Request) {
+	var req greetRequest
+	if err := api.Decode(w, r, &req); err != nil {
+		api.WriteError(w, r, err)
+		return
+	}
+	greet(w, r, req)
+}
+
+func greet(w http.ResponseWriter, r *http.Request, req greetRequest) {
+	message := "Hello, " + req.Name + "!"
+	// The request's logger adds its request_id to every line.
+	logging.FromContext(r.Context()).Info("greeting", "message", message)
+	api.JSON(w, http.StatusOK, greeting{message})
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	for shell, want := range map[string]string{
+		"bash": "complete -F _",
+		"zsh":  "#compdef golden-app",
+		"fish": "complete -c golden-app -n '__fish_seen_subcommand_from greet' -o shout",
+	} {
+		stdout, stderr, code := run(t, nil, "completion", shell)
+		if code != 0 || !strings.Contains(stdout, want) || !strings.Contains(stdout, "greet") {
+			t.Errorf("%s: expected a script with %q, got %d %q (stderr %q)", shell, want, code, stdout, stderr)
+		}
+		if hasWord(stdout, "man") {
+			t.Errorf("%s: expected hidden commands left out, got %q", shell, stdout)
+		}
+	}
+	if _, _, code := run(t, nil, "completion", "tcsh"); code != 2 {
+		t.Errorf("expected exit 2 for a shell without completion, got %d", code)
+	}
+}
+
+func TestMan(t *testing.T) {
+	stdout, _, code := run(t, nil, "man")
+	for _, want := range []string{".TH ", ".SH COMMANDS", ".SS greet", `\fB\-shout\fR`, "GREETING"} {
+		if code != 0 || !strings.Contains(stdout, want) {
+			t.Errorf("expected a manual page with %q, got %d %q", want, code, stdout)
+		}
+	}
+}
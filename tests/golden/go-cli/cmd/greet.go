@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+func greetCommand() *command {
+	c := newCommand("greet", "[NAME...]", "Greet each NAME, or the world")
+	greeting := c.flags.String("greeting", "", "greet with `WORD` rather than the configured greeting")
+	shout := c.flags.Bool("shout", false, "greet in capitals")
+	c.run = func(ctx context.Context, a *app, names []string) error {
+		cfg, err := a.config()
+		if err != nil {
+			return err
+		}
+		if *greeting == "" {
+			*greeting = cfg.Greeting
+		}
+		if len(names) == 0 {
+			names = []string{"world"}
+		}
+		for _, n := range names {
+			// A long-running command checks ctx, so Ctrl-C stops it.
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			line := fmt.Sprintf("%s, %s!", *greeting, n)
+			if *shout {
+				line = strings.ToUpper(line)
+			}
+			fmt.Fprintln(a.stdout, line)
+		}
+		return nil
+	}
+	return c
+}
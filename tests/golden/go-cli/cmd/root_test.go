@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// run executes args with env as the whole environment and is what the
+// command wrote to stdout and stderr, and its exit code.
+func run(t *testing.T, env map[string]string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	// A config directory of the test's own, so no real config is read.
+	home := t.TempDir()
+	code = Execute(context.Background(), args, &out, &errOut, func(key string) string {
+		if key == "XDG_CONFIG_HOME" {
+			return home
+		}
+		return env[key]
+	})
+	return out.String(), errOut.String(), code
+}
+
+func TestUsage(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string // in stdout; "" for nothing there
+		stderr string // in stderr; "" for nothing there
+	}{
+		{"no command", nil, 2, "", "Usage: golden-app"},
+		{"help", []string{"help"}, 0, "greet", ""},
+		{"command help", []string{"help", "greet"}, 0, "-shout", ""},
+		{"-h", []string{"greet", "-h"}, 0, "", "-shout"},
+		{"unknown command", []string{"great"}, 2, "", `unknown command "great"`},
+		{"unknown flag", []string{"greet", "-loud"}, 2, "", "-loud"},
+		{"extra arguments", []string{"version", "now"}, 2, "", "no arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, code := run(t, nil, tt.args...)
+			if code != tt.code {
+				t.Errorf("expected exit %d, got %d (stderr %q)", tt.code, code, stderr)
+			}
+			if !strings.Contains(stdout, tt.stdout) || (tt.stdout == "") != (stdout == "") {
+				t.Errorf("expected stdout with %q, got %q", tt.stdout, stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) || (tt.stderr == "") != (stderr == "") {
+				t.Errorf("expected stderr with %q, got %q", tt.stderr, stderr)
+			}
+		})
+	}
+}
+
+// hasWord reports whether s has word in it, on its own.
+func hasWord(s, word string) bool {
+	for _, w := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHelpLeavesOutHiddenCommands(t *testing.T) {
+	stdout, _, _ := run(t, nil, "help")
+	if hasWord(stdout, "man") {
+		t.Errorf("expected man left out of help, got %q", stdout)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	for _, args := range [][]string{{"version"}, {"-version"}} {
+		stdout, _, code := run(t, nil, args...)
+		if code != 0 || !strings.HasPrefix(stdout, "golden-app ") {
+			t.Errorf("%v: expected the version, got %d %q", args, code, stdout)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"golden-app/internal/version"
+)
+
+func versionCommand() *command {
+	c := newCommand("version", "", "Print the version")
+	c.run = func(ctx context.Context, a *app, args []string) error {
+		if len(args) > 0 {
+			return usagef(a, "version takes no arguments")
+		}
+		return printVersion(a)
+	}
+	return c
+}
+
+func printVersion(a *app) error {
+	_, err := fmt.Fprintf(a.stdout, "%s %s\n", name, version.String())
+	return err
+}
@@ -0,0 +1,76 @@
+// Package config loads the settings: from a JSON config file, then from
+// environment variables, which win over it. Flags, where a command has
+// them, win over both.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Prefix starts the names of the environment variables the settings
+// come from.
+const Prefix = "GOLDEN_APP_"
+
+// Config is the settings, each under its key in the config file and
+// from the environment variable beside it.
+type Config struct {
+	Greeting string `json:"greeting"` // Prefix+GREETING: what greet greets with; Hello
+}
+
+// DefaultPath is the config file read when none is named:
+// golden-app/config.json in $XDG_CONFIG_HOME, or in the user's
+// config directory (~/.config on Linux) without it.
+func DefaultPath(getenv func(string) string) string {
+	dir := getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir, _ = os.UserConfigDir()
+	}
+	return filepath.Join(dir, "golden-app", "config.json")
+}
+
+// Load reads the config file at path, or at Prefix+CONFIG, or, when
+// both are empty, at DefaultPath, which need not exist (the others must),
+// and then the environment through getenv (os.Getenv, or a stand-in in
+// tests).
+func Load(path string, getenv func(string) string) (Config, error) {
+	c := Config{Greeting: "Hello"}
+	required := true
+	if path == "" {
+		path = getenv(Prefix + "CONFIG")
+	}
+	if path == "" {
+		path, required = DefaultPath(getenv), false
+	}
+	if err := read(path, &c); err != nil && (required || !errors.Is(err, fs.ErrNotExist)) {
+		return c, err
+	}
+	if v := getenv(Prefix + "GREETING"); v != "" {
+		c.Greeting = v
+	}
+	if strings.TrimSpace(c.Greeting) == "" {
+		return c, fmt.Errorf("%s: the greeting is empty", path)
+	}
+	return c, nil
+}
+
+// read decodes the config file at path into c, refusing keys c does
+// not have so a misspelt one is not silently ignored.
+func read(path string, c *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	hi := write("hi.json", `{"greeting": "Hi"}`)
+	typo := write("typo.json", `{"greting": "Hi"}`)
+	empty := write("empty.json", `{"greeting": " "}`)
+	// Where DefaultPath looks, with XDG_CONFIG_HOME set to home.
+	home := t.TempDir()
+	os.Mkdir(filepath.Join(home, "golden-app"), 0o755)
+	os.WriteFile(filepath.Join(home, "golden-app", "config.json"), []byte(`{"greeting": "Howdy"}`), 0o644)
+
+	tests := []struct {
+		name, path string
+		env        map[string]string
+		want       string // the greeting
+		err        string // what the error says, "" for none
+	}{
+		{"defaults", "", nil, "Hello", ""},
+		{"file", hi, nil, "Hi", ""},
+		{"file from the environment", "", map[string]string{Prefix + "CONFIG": hi}, "Hi", ""},
+		{"environment wins", hi, map[string]string{Prefix + "GREETING": "Hey"}, "Hey", ""},
+		{"default file", "", map[string]string{"XDG_CONFIG_HOME": home}, "Howdy", ""},
+		{"missing file", filepath.Join(dir, "none.json"), nil, "", "no such file"},
+		{"unknown key", typo, nil, "", `unknown field "greting"`},
+		{"empty greeting", empty, nil, "", "greeting is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{"XDG_CONFIG_HOME": dir}
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			c, err := Load(tt.path, func(key string) string { return env[key] })
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("expected an error about %s, got %v", tt.err, err)
+			case tt.err == "" && c.Greeting != tt.want:
+				t.Errorf("expected the greeting %q, got %q", tt.want, c.Greeting)
+			}
+		})
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	path := DefaultPath(func(key string) string {
+		return map[string]string{"XDG_CONFIG_HOME": "/etc/xdg"}[key]
+	})
+	if path != filepath.Join("/etc/xdg", "golden-app", "config.json") {
+		t.Errorf("unexpected path %s", path)
+	}
+}
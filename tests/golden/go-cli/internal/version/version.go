@@ -0,0 +1,53 @@
+// Package version says which build of the program is running.
+package version
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Version, Commit and Date describe the build. A release sets them with
+// the linker, as just build does:
+//
+//	go build -ldflags "-X golden-app/internal/version.Version=v1.2.3"
+//
+// Those left unset come from what go build recorded instead: the module
+// version under go install, and the commit in a git checkout.
+var (
+	Version = ""
+	Commit  = ""
+	Date    = ""
+)
+
+// String is the version with the commit and date that are known, as in
+// "v1.2.3 (1a2b3c4, 2026-01-02T15:04:05Z)", or "dev" for a build that
+// knows none.
+func String() string {
+	version, commit, date := Version, Commit, Date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if version == "" && info.Main.Version != "(devel)" {
+			version = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && commit == "":
+				commit = s.Value[:min(7, len(s.Value))]
+			case s.Key == "vcs.time" && date == "":
+				date = s.Value
+			}
+		}
+	}
+	if version == "" {
+		version = "dev"
+	}
+	var details []string
+	for _, d := range []string{commit, date} {
+		if d != "" {
+			details = append(details, d)
+		}
+	}
+	if len(details) == 0 {
+		return version
+	}
+	return version + " (" + strings.Join(details, ", ") + ")"
+}
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: MIT
+module golden-app
+
+go 1.22
+
+require (
+	github.com/99designs/gqlgen v0.17.55
+	github.com/vektah/gqlparser/v2 v2.5.17
+	github.com/vikstrous/dataloadgen v0.0.6
+)
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"golden-app/internal/logging"
+)
+
+// maxComplexity bounds how much a single query may ask for, counting
+// each field it selects as one, so one deeply nested query cannot keep
+// the server busy.
+const maxComplexity = 200
+
+// NewHandler serves the schema over HTTP, queries by GET or POST and
+// mutations by POST, with the resolvers reading and writing s.
+// Introspection, which the playground and code generators learn the
+// schema by, is on only when introspect is.
+func NewHandler(s Store, introspect bool) http.Handler {
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{Store: s}}))
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	if introspect {
+		srv.Use(extension.Introspection{})
+	}
+	srv.Use(extension.FixedComplexityLimit(maxComplexity))
+	srv.SetErrorPresenter(presentError)
+	srv.SetRecoverFunc(recoverPanic)
+	return withLoaders(s, srv)
+}
+
+// inputError is an error the client can fix, such as a field that is
+// too long, shown to it as it is with the code BAD_USER_INPUT.
+func inputError(ctx context.Context, format string, args ...any) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    fmt.Sprintf(format, args...),
+		Path:       graphql.GetPath(ctx),
+		Extensions: map[string]any{"code": "BAD_USER_INPUT"},
+	}
+}
+
+// presentError shows the client the errors made for it: those gqlgen
+// makes of a query it cannot run, and those a resolver returns as a
+// *gqlerror.Error, such as inputError's. Any other error a resolver
+// returns is a bug or an outage the client cannot act on, so it is
+// logged and shown as a plain internal error that gives nothing of it
+// away.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	e := graphql.DefaultErrorPresenter(ctx, err)
+	// gqlgen wraps a resolver's plain errors on their way here.
+	if errors.Unwrap(e) == nil {
+		return e
+	}
+	logging.FromContext(ctx).Error("internal error", "path", e.Path.String(), "err", err)
+	return &gqlerror.Error{
+		Message:    "internal error",
+		Path:       e.Path,
+		Extensions: map[string]any{"code": "INTERNAL"},
+	}
+}
+
+// recoverPanic turns a panic in a resolver into an internal error,
+// logging it with the stack, so the rest of the response still gets
+// out.
+func recoverPanic(ctx context.Context, p any) error {
+	logging.FromContext(ctx).Error("panic", "err", p, "stack", string(debug.Stack()))
+	return &gqlerror.Error{Message: "internal error", Extensions: map[string]any{"code": "INTERNAL"}}
+}
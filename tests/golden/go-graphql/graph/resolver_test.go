@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/99designs/gqlgen/client"
+
+	"golden-app/graph/model"
+	"golden-app/internal/store"
+)
+
+// countingStore counts the user lookups that reach the store.
+type countingStore struct {
+	*store.Memory
+	lookups atomic.Int32
+}
+
+func (s *countingStore) UsersByID(ctx context.Context, ids []string) ([]*model.User, []error) {
+	s.lookups.Add(1)
+	return s.Memory.UsersByID(ctx, ids)
+}
+
+// brokenStore fails to list the todos as a database that is down would.
+type brokenStore struct {
+	*store.Memory
+}
+
+func (brokenStore) Todos(context.Context) ([]*model.Todo, error) {
+	return nil, errors.New("dial tcp: connection refused")
+}
+
+const createTodo = `mutation($text: String!, $user: ID!) {
+	createTodo(input: {text: $text, userId: $user}) { id text done user { name } }
+}`
+
+func TestCreateTodo(t *testing.T) {
+	c := client.New(NewHandler(store.New(), false))
+	var resp struct {
+		CreateTodo struct {
+			ID, Text string
+			Done     bool
+			User     struct{ Name string }
+		}
+	}
+	c.MustPost(createTodo, &resp, client.Var("text", "Write tests"), client.Var("user", "1"))
+
+	if got := resp.CreateTodo; got.ID != "1" || got.Text != "Write tests" || got.Done || got.User.Name != "Ada Lovelace" {
+		t.Errorf("unexpected todo %+v", got)
+	}
+}
+
+func TestCreateTodoInvalid(t *testing.T) {
+	c := client.New(NewHandler(store.New(), false))
+	tests := []struct {
+		name, text, user string
+		message          string
+	}{
+		{"no text", " ", "1", "text is required"},
+		{"long text", strings.Repeat("a", 201), "1", "at most 200"},
+		{"no such user", "Write tests", "7", "there is no user 7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp any
+			err := c.Post(createTodo, &resp, client.Var("text", tt.text), client.Var("user", tt.user))
+			if err == nil || !strings.Contains(err.Error(), tt.message) || !strings.Contains(err.Error(), "BAD_USER_INPUT") {
+				t.Errorf("expected BAD_USER_INPUT saying %q, got %v", tt.message, err)
+			}
+		})
+	}
+}
+
+func TestTodosLoadUsersInOneBatch(t *testing.T) {
+	s := &countingStore{Memory: store.New()}
+	c := client.New(NewHandler(s, false))
+	for _, user := range []string{"1", "2", "1"} {
+		var created any
+		c.MustPost(createTodo, &created, client.Var("text", "Write tests"), client.Var("user", user))
+	}
+	s.lookups.Store(0)
+
+	var resp struct {
+		Todos []struct {
+			User struct{ Name string }
+		}
+	}
+	c.MustPost(`{ todos { user { name } } }`, &resp)
+
+	if len(resp.Todos) != 3 || resp.Todos[1].User.Name != "Grace Hopper" {
+		t.Fatalf("expected three todos with their users, got %+v", resp.Todos)
+	}
+	if n := s.lookups.Load(); n != 1 {
+		t.Errorf("expected the users looked up in one batch, got %d lookups", n)
+	}
+}
+
+func TestUser(t *testing.T) {
+	c := client.New(NewHandler(store.New(), false))
+	for id, want := range map[string]string{"2": "Grace Hopper", "7": ""} {
+		var resp struct {
+			User *struct{ Name string }
+		}
+		c.MustPost(`query($id: ID!) { user(id: $id) { name } }`, &resp, client.Var("id", id))
+		switch {
+		case want == "" && resp.User != nil:
+			t.Errorf("user %s: expected null for a user that is not there, got %+v", id, resp.User)
+		case want != "" && (resp.User == nil || resp.User.Name != want):
+			t.Errorf("user %s: expected %s, got %+v", id, want, resp.User)
+		}
+	}
+}
+
+func TestInternalErrorsAreHidden(t *testing.T) {
+	c := client.New(NewHandler(brokenStore{store.New()}, false))
+	var resp any
+	err := c.Post(`{ todos { id } }`, &resp)
+	if err == nil || !strings.Contains(err.Error(), `"INTERNAL"`) || strings.Contains(err.Error(), "refused") {
+		t.Errorf("expected an internal error that gives nothing away, got %v", err)
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	query := `{ __schema { queryType { name } } }`
+	var resp any
+	if err := client.New(NewHandler(store.New(), true)).Post(query, &resp); err != nil {
+		t.Errorf("expected introspection when it is on, got %v", err)
+	}
+	if err := client.New(NewHandler(store.New(), false)).Post(query, &resp); err == nil {
+		t.Error("expected introspection refused when it is off")
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = ID(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if len(seen) != 16 || w.Header().Get(Header) != seen {
+		t.Errorf("expected a new ID in context and header, got %q and %q", seen, w.Header().Get(Header))
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(Header, "from-proxy")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if seen != "from-proxy" {
+		t.Errorf("expected the incoming ID kept, got %q", seen)
+	}
+
+	req.Header.Set(Header, "bad id\n")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if seen == "bad id\n" || len(seen) != 16 {
+		t.Errorf("expected a bad incoming ID replaced, got %q", seen)
+	}
+}
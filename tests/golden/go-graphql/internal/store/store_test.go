@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCreateTodo(t *testing.T) {
+	ctx := context.Background()
+	m := New()
+	todo, err := m.CreateTodo(ctx, "Write tests", "1")
+	if err != nil || todo.ID != "1" || todo.UserID != "1" {
+		t.Fatalf("expected todo 1 for user 1, got %+v %v", todo, err)
+	}
+	if _, err := m.CreateTodo(ctx, "Write tests", "7"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a user that is not there, got %v", err)
+	}
+	todos, _ := m.Todos(ctx)
+	if len(todos) != 1 || todos[0].Text != "Write tests" {
+		t.Errorf("expected the one todo, got %+v", todos)
+	}
+}
+
+func TestUsersByID(t *testing.T) {
+	users, errs := New().UsersByID(context.Background(), []string{"2", "7", "1"})
+	if users[0].Name != "Grace Hopper" || users[2].Name != "Ada Lovelace" || errs[0] != nil || errs[2] != nil {
+		t.Errorf("expected users 2 and 1 in order, got %+v %v", users, errs)
+	}
+	if users[1] != nil || !errors.Is(errs[1], ErrNotFound) {
+		t.Errorf("expected ErrNotFound for user 7, got %+v %v", users[1], errs[1])
+	}
+}
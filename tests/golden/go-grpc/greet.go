@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	greetv1 "golden-app/gen/greet/v1"
+	"golden-app/internal/logging"
+)
+
+// greetServer implements greet.v1.GreetService. Embedding the
+// Unimplemented server answers methods added to the .proto with
+// Unimplemented until they are written here.
+type greetServer struct {
+	greetv1.UnimplementedGreetServiceServer
+}
+
+func (greetServer) Greet(ctx context.Context, req *greetv1.GreetRequest) (*greetv1.GreetResponse, error) {
+	name := req.GetName()
+	switch {
+	case name == "":
+		name = "world"
+	case utf8.RuneCountInString(name) > 100:
+		return nil, status.Error(codes.InvalidArgument, "name must be at most 100 characters")
+	}
+	message := "Hello, " + name + "!"
+	// The call's logger adds its request_id to every line.
+	logging.FromContext(ctx).Info("greeting", "message", message)
+	return &greetv1.GreetResponse{Message: message}, nil
+}
@@ -0,0 +1,39 @@
+// Package config loads the app's settings from environment variables,
+// with defaults, and checks them.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// Config is the app's settings, each from the variable named beside it.
+type Config struct {
+	Port       string     // PORT, where gRPC is served; 4000
+	Env        string     // APP_ENV: development (the default) or production
+	LogLevel   slog.Level // LOG_LEVEL: debug, info (the default), warn or error
+	Reflection bool       // GRPC_REFLECTION: serve reflection, for grpcurl and the like, unless it is 0
+}
+
+// Load reads the settings through getenv (os.Getenv, or a stand-in in
+// tests). The error lists every setting that is wrong.
+func Load(getenv func(string) string) (Config, error) {
+	var errs []error
+	c := Config{
+		Port:       first(getenv("PORT"), "4000"),
+		Env:        first(getenv("APP_ENV"), "development"),
+		Reflection: getenv("GRPC_REFLECTION") != "0",
+	}
+	if n, err := strconv.Atoi(c.Port); err != nil || n < 1 || n > 65535 {
+		errs = append(errs, fmt.Errorf("PORT: %q is not a port number", c.Port))
+	}
+	if c.Env != "development" && c.Env != "production" {
+		errs = append(errs, fmt.Errorf("APP_ENV: %q is neither development nor production", c.Env))
+	}
+	if err := c.LogLevel.UnmarshalText([]byte(first(getenv("LOG_LEVEL"), "info"))); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+	}
+	return c, errors.Join(errs...)
+}
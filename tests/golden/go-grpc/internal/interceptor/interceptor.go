@@ -0,0 +1,61 @@
+// Package interceptor has what main wraps around every gRPC call: call
+// logging with request IDs, and panic recovery. Each comes for unary
+// calls and, with Stream in its name, for streams.
+package interceptor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Header is the metadata key carrying the request ID, in from a proxy
+// or client and back out in the response headers.
+const Header = "x-request-id"
+
+type idKey struct{}
+
+// A caller's ID is kept only if it is short and plain, since it ends up
+// in logs and response headers.
+var validID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ID is the request ID Logger put in ctx, or "" without one.
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// withID is ctx carrying the call's request ID: the one it came with,
+// or a new random one. It is sent back in the response headers.
+func withID(ctx context.Context) context.Context {
+	id := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(Header)) > 0 {
+		id = md.Get(Header)[0]
+	}
+	if !validID.MatchString(id) {
+		id = newID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(Header, id))
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// stream is a grpc.ServerStream with its own context, which is how a
+// stream interceptor hands a handler new values.
+type stream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *stream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,36 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithID(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string // the x-request-id the call came with
+		kept bool
+	}{
+		{"none", "", false},
+		{"plain", "abc-123", true},
+		{"spaces", "abc 123", false},
+		{"too long", string(make([]byte, 65)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.in != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(Header, tt.in))
+			}
+			id := ID(withID(ctx))
+			switch {
+			case tt.kept && id != tt.in:
+				t.Errorf("expected %q kept, got %q", tt.in, id)
+			case !tt.kept && (id == tt.in || !validID.MatchString(id)):
+				t.Errorf("expected a new ID, got %q", id)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"golden-app/internal/logging"
+)
+
+// Logger gives each unary call a request ID and a logger carrying it,
+// which handlers get with logging.FromContext, and logs the call's
+// method, status code and duration when it is done.
+func Logger(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = withID(ctx)
+		l := logger.With("request_id", ID(ctx))
+		resp, err := handler(logging.NewContext(ctx, l), req)
+		logCall(l, info.FullMethod, err, start)
+		return resp, err
+	}
+}
+
+// StreamLogger is Logger for streams, logged when they end.
+func StreamLogger(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := withID(ss.Context())
+		l := logger.With("request_id", ID(ctx))
+		err := handler(srv, &stream{ss, logging.NewContext(ctx, l)})
+		logCall(l, info.FullMethod, err, start)
+		return err
+	}
+}
+
+func logCall(l *slog.Logger, method string, err error, start time.Time) {
+	l.Info("call",
+		"method", method,
+		"code", status.Code(err).String(),
+		"duration", time.Since(start),
+	)
+}
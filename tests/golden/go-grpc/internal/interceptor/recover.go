@@ -0,0 +1,39 @@
+package interceptor
+
+import (
+	"context"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"golden-app/internal/logging"
+)
+
+// Recover turns a panic in a unary handler into an Internal error,
+// logging it with the stack, so one bad call does not take the server
+// down.
+func Recover(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(ctx, p)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// StreamRecover is Recover for streams.
+func StreamRecover(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(ss.Context(), p)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func recovered(ctx context.Context, p any) error {
+	logging.FromContext(ctx).Error("panic", "err", p, "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
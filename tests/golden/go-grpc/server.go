@@ -0,0 +1,37 @@
+package main
+
+import (
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	greetv1 "golden-app/gen/greet/v1"
+	"golden-app/internal/config"
+	"golden-app/internal/interceptor"
+)
+
+// newServer is the gRPC server with every service registered, and its
+// health service, which main marks NOT_SERVING on the way down.
+func newServer(logger *slog.Logger, cfg config.Config) (*grpc.Server, *health.Server) {
+	// The logger first so its request ID and logger reach the handler,
+	// and so a panic is logged as the Internal error it becomes.
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptor.Logger(logger), interceptor.Recover),
+		grpc.ChainStreamInterceptor(interceptor.StreamLogger(logger), interceptor.StreamRecover),
+	)
+	greetv1.RegisterGreetServiceServer(srv, greetServer{})
+
+	// "" is the server as a whole; each service also answers by name.
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthSrv.SetServingStatus(greetv1.GreetService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+
+	if cfg.Reflection {
+		reflection.Register(srv)
+	}
+	return srv, healthSrv
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"golden-app/internal/config"
+	"golden-app/internal/invoke"
+)
+
+// request is a fake API Gateway event for method and path.
+func request(method, path string) events.APIGatewayV2HTTPRequest {
+	req := events.APIGatewayV2HTTPRequest{Version: "2.0", RawPath: path}
+	req.RequestContext.HTTP.Method = method
+	req.RequestContext.HTTP.Path = path
+	return req
+}
+
+func TestHandler(t *testing.T) {
+	handler := newHandler(config.Config{Greeting: "Hello"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	withName := request(http.MethodGet, "/hello")
+	withName.QueryStringParameters = map[string]string{"name": "Ada"}
+	posted := request(http.MethodPost, "/hello")
+	posted.Body = `{"name": "Grace"}`
+	encoded := request(http.MethodPost, "/hello")
+	encoded.Body, encoded.IsBase64Encoded = base64.StdEncoding.EncodeToString([]byte(`{"name": "Alan"}`)), true
+	badBody := request(http.MethodPost, "/hello")
+	badBody.Body = "name=Ada"
+	longName := request(http.MethodGet, "/hello")
+	longName.QueryStringParameters = map[string]string{"name": strings.Repeat("a", maxName+1)}
+
+	tests := []struct {
+		name   string
+		req    events.APIGatewayV2HTTPRequest
+		status int
+		want   string // in the body
+	}{
+		{"default name", request(http.MethodGet, "/hello"), http.StatusOK, `"Hello, world!"`},
+		{"query", withName, http.StatusOK, `"Hello, Ada!"`},
+		{"body", posted, http.StatusOK, `"Hello, Grace!"`},
+		{"base64 body", encoded, http.StatusOK, `"Hello, Alan!"`},
+		{"bad body", badBody, http.StatusBadRequest, "must be JSON"},
+		{"long name", longName, http.StatusBadRequest, "too long"},
+		{"method", request(http.MethodDelete, "/hello"), http.StatusMethodNotAllowed, "not allowed"},
+		{"not found", request(http.MethodGet, "/nope"), http.StatusNotFound, "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := handler(context.Background(), tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status || !strings.Contains(resp.Body, tt.want) {
+				t.Errorf("expected %d with %q, got %d %s", tt.status, tt.want, resp.StatusCode, resp.Body)
+			}
+			if resp.Headers["Content-Type"] != "application/json" {
+				t.Errorf("expected a JSON response, got headers %v", resp.Headers)
+			}
+		})
+	}
+}
+
+// TestSampleEvent runs the handler on events/http.json, as just invoke
+// http does, so the sample stays in step with it.
+func TestSampleEvent(t *testing.T) {
+	handler := newHandler(config.Config{Greeting: "Hello"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var out bytes.Buffer
+	path := filepath.Join("..", "..", "events", "http.json")
+	if err := invoke.Local(context.Background(), lambda.NewHandler(handler), []string{path}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("Hello, Ada!")) {
+		t.Errorf("unexpected response: %s", out.String())
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"golden-app/internal/config"
+	"golden-app/internal/invoke"
+)
+
+func TestHandlerReportsTheMessagesThatFail(t *testing.T) {
+	handler := newHandler(config.Config{Greeting: "Hello"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	event := events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "1", Body: `{"name": "Ada"}`},
+		{MessageId: "2", Body: `not json`},
+		{MessageId: "3", Body: `{"name": "Grace"}`},
+		{MessageId: "4", Body: `{}`},
+	}}
+	resp, err := handler(context.Background(), event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failed []string
+	for _, f := range resp.BatchItemFailures {
+		failed = append(failed, f.ItemIdentifier)
+	}
+	if !slices.Equal(failed, []string{"2", "4"}) {
+		t.Errorf("expected messages 2 and 4 to fail, got %v", failed)
+	}
+}
+
+func TestHandlerEmptyBatch(t *testing.T) {
+	handler := newHandler(config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	resp, err := handler(context.Background(), events.SQSEvent{})
+	if err != nil || len(resp.BatchItemFailures) != 0 {
+		t.Errorf("expected no failures, got %v, %v", resp, err)
+	}
+}
+
+// TestSampleEvent runs the handler on events/sqs.json, as just invoke
+// sqs does, so the sample stays in step with it.
+func TestSampleEvent(t *testing.T) {
+	handler := newHandler(config.Config{Greeting: "Hello"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var out bytes.Buffer
+	path := filepath.Join("..", "..", "events", "sqs.json")
+	if err := invoke.Local(context.Background(), lambda.NewHandler(handler), []string{path}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte(`"batchItemFailures": []`)) {
+		t.Errorf("unexpected response: %s", out.String())
+	}
+}
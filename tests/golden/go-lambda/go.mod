@@ -0,0 +1,6 @@
+// SPDX-License-Identifier: MIT
+module golden-app
+
+go 1.21
+
+require github.com/aws/aws-lambda-go v1.47.0
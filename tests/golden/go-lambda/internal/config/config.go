@@ -0,0 +1,35 @@
+// Package config loads the function's settings from environment
+// variables, with defaults, and checks them. In Lambda they are the
+// function's environment, set in the deployment.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Config is the function's settings, each from the variable named
+// beside it.
+type Config struct {
+	Env      string     // APP_ENV: development (the default) or production
+	LogLevel slog.Level // LOG_LEVEL: debug, info (the default), warn or error
+	Greeting string     // GREETING, what the function greets with; Hello
+}
+
+// Load reads the settings through getenv (os.Getenv, or a stand-in in
+// tests). The error lists every setting that is wrong.
+func Load(getenv func(string) string) (Config, error) {
+	var errs []error
+	c := Config{
+		Env:      first(getenv("APP_ENV"), "development"),
+		Greeting: first(getenv("GREETING"), "Hello"),
+	}
+	if c.Env != "development" && c.Env != "production" {
+		errs = append(errs, fmt.Errorf("APP_ENV: %q is neither development nor production", c.Env))
+	}
+	if err := c.LogLevel.UnmarshalText([]byte(first(getenv("LOG_LEVEL"), "info"))); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+	}
+	return c, errors.Join(errs...)
+}
@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	for env, want := range map[string]string{
+		"production":  `"msg":"hi"`,
+		"development": "msg=hi",
+		"":            "msg=hi",
+	} {
+		var out bytes.Buffer
+		New(env, slog.LevelInfo, &out).Info("hi")
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("%q: expected %s, got %q", env, want, out.String())
+		}
+	}
+}
+
+func TestNewLevel(t *testing.T) {
+	var out bytes.Buffer
+	logger := New("", slog.LevelWarn, &out)
+	logger.Info("quiet")
+	logger.Warn("loud")
+	if strings.Contains(out.String(), "quiet") || !strings.Contains(out.String(), "loud") {
+		t.Errorf("expected only the warning, got %q", out.String())
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Error("expected the default logger without one in the context")
+	}
+	var out bytes.Buffer
+	logger := New("", slog.LevelInfo, &out).With("request_id", "abc")
+	FromContext(NewContext(context.Background(), logger)).Info("hi")
+	if !strings.Contains(out.String(), "request_id=abc") {
+		t.Errorf("expected the context's logger, got %q", out.String())
+	}
+}
@@ -0,0 +1,8 @@
+// Package goldenapp turns text into URL slugs: lower-case ASCII
+// letters and digits with a hyphen between words, as in
+// "creme-brulee-a-how-to" from "Crème brûlée: a how-to".
+//
+// Make covers the usual case. A Slugger picks another separator or
+// keeps slugs short, and Valid checks a slug that came from elsewhere,
+// such as a URL.
+package goldenapp
@@ -0,0 +1,33 @@
+package goldenapp_test
+
+import (
+	"fmt"
+
+	"golden-app"
+)
+
+// The examples are tests as well as documentation: go test checks what
+// each prints against its Output comment, and pkg.go.dev shows them
+// beside what they use.
+
+func ExampleMake() {
+	fmt.Println(goldenapp.Make("Hello, World!"))
+	fmt.Println(goldenapp.Make("Crème brûlée: a how-to"))
+	// Output:
+	// hello-world
+	// creme-brulee-a-how-to
+}
+
+func ExampleSlugger() {
+	sl := goldenapp.Slugger{Separator: "_", MaxLen: 20}
+	fmt.Println(sl.Make("The quick brown fox jumps over the lazy dog"))
+	// Output: the_quick_brown_fox
+}
+
+func ExampleValid() {
+	fmt.Println(goldenapp.Valid("hello-world"))
+	fmt.Println(goldenapp.Valid("Hello World"))
+	// Output:
+	// true
+	// false
+}
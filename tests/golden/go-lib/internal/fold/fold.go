@@ -0,0 +1,64 @@
+// Package fold maps letters to the ASCII they are written as without
+// their accents. It is internal: another module cannot import it, so
+// its API can change with the package that uses it.
+package fold
+
+import "unicode"
+
+// ASCII is r in lower-case ASCII, which may be more than one letter (ß
+// is ss), and whether it is a letter or digit to keep at all. Letters
+// without an ASCII form here, such as those of other scripts, are not.
+func ASCII(r rune) (string, bool) {
+	r = unicode.ToLower(r)
+	// Slices of alnum rather than string(r), which would allocate.
+	switch {
+	case '0' <= r && r <= '9':
+		return alnum[r-'0' : r-'0'+1], true
+	case 'a' <= r && r <= 'z':
+		return alnum[10+r-'a' : 11+r-'a'], true
+	}
+	s, ok := table[r]
+	return s, ok
+}
+
+const alnum = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// table is what each letter folds to, built from groups.
+var table = func() map[rune]string {
+	t := map[rune]string{}
+	for _, g := range groups {
+		for _, r := range g.from {
+			t[r] = g.to
+		}
+	}
+	return t
+}()
+
+// groups are the lower-case Latin letters with accents or ligatures,
+// by what they fold to.
+var groups = []struct{ from, to string }{
+	{"àáâãäåāăą", "a"},
+	{"æ", "ae"},
+	{"çćĉċč", "c"},
+	{"ďđð", "d"},
+	{"èéêëēĕėęě", "e"},
+	{"ĝğġģ", "g"},
+	{"ĥħ", "h"},
+	{"ìíîïĩīĭįı", "i"},
+	{"ĳ", "ij"},
+	{"ĵ", "j"},
+	{"ķ", "k"},
+	{"ĺļľŀł", "l"},
+	{"ñńņňŉ", "n"},
+	{"òóôõöøōŏő", "o"},
+	{"œ", "oe"},
+	{"ŕŗř", "r"},
+	{"śŝşš", "s"},
+	{"ß", "ss"},
+	{"ţťŧ", "t"},
+	{"þ", "th"},
+	{"ùúûüũūŭůűų", "u"},
+	{"ŵ", "w"},
+	{"ýÿŷ", "y"},
+	{"źżž", "z"},
+}
@@ -0,0 +1,44 @@
+package fold
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestASCII(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want string
+		ok   bool
+	}{
+		{'a', "a", true},
+		{'Z', "z", true},
+		{'7', "7", true},
+		{'é', "e", true},
+		{'Ø', "o", true},
+		{'ß', "ss", true},
+		{'Æ', "ae", true},
+		{' ', "", false},
+		{'-', "", false},
+		{'я', "", false},
+		{'日', "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := ASCII(tt.r); got != tt.want || ok != tt.ok {
+			t.Errorf("ASCII(%q) = %q, %v; expected %q, %v", tt.r, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTableIsLowerCaseASCII(t *testing.T) {
+	for r, s := range table {
+		if !unicode.IsLower(r) {
+			t.Errorf("%q: expected only lower-case letters, as ASCII lowers r first", r)
+		}
+		for _, c := range s {
+			if c < 'a' || c > 'z' {
+				t.Errorf("%q folds to %q, which is not lower-case ASCII", r, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package goldenapp
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"words", "Hello World", "hello-world"},
+		{"punctuation", "Hello, World!", "hello-world"},
+		{"runs", "  a -- b\t\nc  ", "a-b-c"},
+		{"accents", "Crème brûlée", "creme-brulee"},
+		{"ligatures", "Æsir straße", "aesir-strasse"},
+		{"apostrophes", "Don't stop, it’s fine", "dont-stop-its-fine"},
+		{"digits", "Top 10 of 2024", "top-10-of-2024"},
+		{"other scripts", "日本 Tokyo", "tokyo"},
+		{"already a slug", "hello-world", "hello-world"},
+		{"nothing to keep", "!?", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Make(tt.in); got != tt.want {
+				t.Errorf("Make(%q) = %q; expected %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugger(t *testing.T) {
+	tests := []struct {
+		name string
+		sl   Slugger
+		in   string
+		want string
+	}{
+		{"separator", Slugger{Separator: "_"}, "Hello World", "hello_world"},
+		{"long separator", Slugger{Separator: "--"}, "a b", "a--b"},
+		{"fits", Slugger{MaxLen: 11}, "Hello World", "hello-world"},
+		{"at a boundary", Slugger{MaxLen: 5}, "Hello World", "hello"},
+		{"within a word", Slugger{MaxLen: 8}, "Hello World", "hello"},
+		{"one long word", Slugger{MaxLen: 4}, "Hello World", "hell"},
+		{"through a separator", Slugger{Separator: "--", MaxLen: 2}, "a b", "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sl.Make(tt.in); got != tt.want {
+				t.Errorf("%+v.Make(%q) = %q; expected %q", tt.sl, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"hello-world", true},
+		{"a", true},
+		{"top-10", true},
+		{"", false},
+		{"Hello", false},
+		{"-hello", false},
+		{"hello-", false},
+		{"hello--world", false},
+		{"hello_world", false},
+		{"crème", false},
+	}
+	for _, tt := range tests {
+		if got := Valid(tt.in); got != tt.want {
+			t.Errorf("Valid(%q) = %v; expected %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// FuzzMake checks what holds for any input: the slug is Valid (or
+// empty), making it again changes nothing, and MaxLen is kept to. go
+// test runs the seeds; just fuzz looks for inputs that break it.
+func FuzzMake(f *testing.F) {
+	for _, seed := range []string{"Hello, World!", "Crème brûlée", "don't", "", "---", "日本"} {
+		f.Add(seed, 0)
+	}
+	f.Fuzz(func(t *testing.T, s string, maxLen int) {
+		slug := Slugger{MaxLen: maxLen}.Make(s)
+		if slug != "" && !Valid(slug) {
+			t.Errorf("Make(%q) = %q, which is not Valid", s, slug)
+		}
+		if again := Make(slug); again != slug {
+			t.Errorf("Make(%q) = %q; expected a slug to stay as it is", slug, again)
+		}
+		if maxLen > 0 && len(slug) > maxLen {
+			t.Errorf("Make(%q) = %q, longer than %d", s, slug, maxLen)
+		}
+	})
+}
+
+func BenchmarkMake(b *testing.B) {
+	title := "Crème brûlée: a how-to, in 10 easy steps (with pictures)"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Make(title)
+	}
+}
+
+func BenchmarkSluggerMaxLen(b *testing.B) {
+	sl := Slugger{MaxLen: 24}
+	title := "Crème brûlée: a how-to, in 10 easy steps (with pictures)"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sl.Make(title)
+	}
+}
@@ -0,0 +1,9 @@
+// SPDX-License-Identifier: MIT
+module golden-app
+
+go 1.22
+
+require (
+	github.com/getkin/kin-openapi v0.128.0
+	github.com/oapi-codegen/runtime v1.1.1
+)
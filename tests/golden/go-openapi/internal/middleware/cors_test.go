@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	h := CORS([]string{"https://app.example"}, []string{"GET", "POST"}, []string{"Content-Type"})(http.NotFoundHandler())
+	preflight := func(origin, method, headers string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("OPTIONS", "/api/v1/notes", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", headers)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := preflight("https://app.example", "POST", "content-type")
+	if w.Code != http.StatusNoContent ||
+		w.Header().Get("Access-Control-Allow-Origin") != "https://app.example" ||
+		w.Header().Get("Access-Control-Allow-Methods") != "GET, POST" ||
+		w.Header().Get("Access-Control-Allow-Headers") != "Content-Type" {
+		t.Errorf("expected the preflight allowed, got %d %v", w.Code, w.Header())
+	}
+	if vary := strings.Join(w.Header().Values("Vary"), ", "); !strings.Contains(vary, "Origin") {
+		t.Errorf("expected Vary: Origin, got %q", vary)
+	}
+
+	tests := []struct{ name, origin, method, headers string }{
+		{"other origin", "https://evil.example", "POST", ""},
+		{"other method", "https://app.example", "DELETE", ""},
+		{"other header", "https://app.example", "POST", "Content-Type, X-Secret"},
+	}
+	for _, tt := range tests {
+		w := preflight(tt.origin, tt.method, tt.headers)
+		if w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" ||
+			!strings.Contains(w.Body.String(), `"code":"forbidden"`) {
+			t.Errorf("%s: expected a 403 without CORS headers, got %d %v", tt.name, w.Code, w.Header())
+		}
+	}
+}
+
+func TestCORSRequests(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := CORS([]string{"https://app.example"}, []string{"GET"}, nil)(next)
+	get := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/notes", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("https://app.example"); w.Header().Get("Access-Control-Allow-Origin") != "https://app.example" {
+		t.Errorf("expected an allowed origin's request allowed, got %v", w.Header())
+	}
+	if w := get("https://evil.example"); w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected a disallowed origin's request served without CORS headers, got %d %v", w.Code, w.Header())
+	}
+	if w := get(""); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected a same-origin request left alone, got %v", w.Header())
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/notes", nil)
+	req.Header.Set("Origin", "https://app.example")
+	CORS(nil, nil, nil)(next).ServeHTTP(w, req)
+	if len(w.Header()) != 0 {
+		t.Errorf("expected no CORS without origins, got %v", w.Header())
+	}
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	h := CORS([]string{"*"}, []string{"GET"}, nil)(http.NotFoundHandler())
+	req := httptest.NewRequest("GET", "/api/v1/notes", nil)
+	req.Header.Set("Origin", "https://anywhere.example")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://anywhere.example" {
+		t.Errorf("expected any origin allowed, got %v", w.Header())
+	}
+}
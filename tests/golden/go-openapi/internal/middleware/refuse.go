@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	"golden-app/internal/api"
+)
+
+// timedOut answers a request Timeout gave up on.
+func timedOut(w http.ResponseWriter, r *http.Request) {
+	api.WriteError(w, r, api.Errorf(http.StatusServiceUnavailable, "timeout", "the server took too long to answer"))
+}
+
+// tooLarge answers a request MaxBytes refuses.
+func tooLarge(w http.ResponseWriter, r *http.Request) {
+	api.WriteError(w, r, api.Errorf(http.StatusRequestEntityTooLarge, "too_large", "the request body is too large"))
+}
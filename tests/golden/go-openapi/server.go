@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+
+	"golden-app/internal/logging"
+	"golden-app/internal/oapi"
+)
+
+// server implements the operations in openapi.yaml, a method each. By
+// the time one runs, its request has passed the spec's checks and been
+// decoded; it returns one of the responses the spec lists, and an
+// error only for a 500.
+type server struct{}
+
+var _ oapi.StrictServerInterface = server{}
+
+func (server) GetGreeting(ctx context.Context, req oapi.GetGreetingRequestObject) (oapi.GetGreetingResponseObject, error) {
+	name := "world"
+	if req.Params.Name != nil {
+		name = *req.Params.Name
+	}
+	return oapi.GetGreeting200JSONResponse(greet(ctx, name)), nil
+}
+
+func (server) CreateGreeting(ctx context.Context, req oapi.CreateGreetingRequestObject) (oapi.CreateGreetingResponseObject, error) {
+	return oapi.CreateGreeting200JSONResponse(greet(ctx, req.Body.Name)), nil
+}
+
+func greet(ctx context.Context, name string) oapi.Greeting {
+	message := "Hello, " + name + "!"
+	// The request's logger adds its request_id to every line.
+	logging.FromContext(ctx).Info("greeting", "message", message)
+	return oapi.Greeting{Message: message}
+}
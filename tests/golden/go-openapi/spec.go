@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	"golden-app/internal/api"
+	"golden-app/internal/oapi"
+)
+
+// loadSpec parses openapi.yaml and checks that it is a valid spec.
+func loadSpec() (*openapi3.T, error) {
+	spec, err := openapi3.NewLoader().LoadFromData(oapi.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return spec, spec.Validate(context.Background())
+}
+
+// newAPI serves spec's operations with server's methods. Each request
+// is checked against spec first, so the methods see only what it
+// allows, and what it does not describe gets a 404 or 405.
+func newAPI(spec *openapi3.T) (http.Handler, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+	strict := oapi.NewStrictHandlerWithOptions(server{}, nil, oapi.StrictHTTPServerOptions{
+		RequestErrorHandlerFunc:  badRequest,
+		ResponseErrorHandlerFunc: api.WriteError,
+	})
+	h := oapi.HandlerWithOptions(strict, oapi.StdHTTPServerOptions{ErrorHandlerFunc: badRequest})
+	return validateRequests(router, h), nil
+}
+
+// validateRequests answers the requests that do not match the route
+// router finds for them, and passes the rest to next.
+func validateRequests(router routers.Router, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, params, err := router.FindRoute(r)
+		switch {
+		case errors.Is(err, routers.ErrMethodNotAllowed):
+			api.WriteError(w, r, api.Errorf(http.StatusMethodNotAllowed, "method_not_allowed", "%s is not allowed on %s", r.Method, r.URL.Path))
+			return
+		case err != nil:
+			api.WriteError(w, r, api.Errorf(http.StatusNotFound, "not_found", "no route for %s %s", r.Method, r.URL.Path))
+			return
+		}
+		input := &openapi3filter.RequestValidationInput{Request: r, PathParams: params, Route: route}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			api.WriteError(w, r, invalid(err))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// invalid is err, from checking a request against the spec, for the
+// client: a 422 naming the field for a parameter or a value in the
+// body the spec does not allow, and a 400 for a body it cannot read.
+func invalid(err error) *api.Error {
+	var req *openapi3filter.RequestError
+	var schema *openapi3.SchemaError
+	isSchema := errors.As(err, &schema)
+	field, problem := "", ""
+	switch {
+	case errors.As(err, &req) && req.Parameter != nil:
+		field, problem = req.Parameter.Name, req.Reason
+		if isSchema {
+			problem = schema.Reason
+		} else if req.Err != nil {
+			problem = req.Err.Error()
+		}
+	case isSchema:
+		field, problem = strings.Join(schema.JSONPointer(), "."), schema.Reason
+	default:
+		return api.Errorf(http.StatusBadRequest, "bad_request", "%s", err)
+	}
+	e := api.Errorf(http.StatusUnprocessableEntity, "invalid", "the request is invalid")
+	if field == "" {
+		e.Message = problem
+		return e
+	}
+	e.Fields = map[string]string{field: problem}
+	return e
+}
+
+// badRequest answers a request the generated code could not read, which
+// the spec check should have turned away already.
+func badRequest(w http.ResponseWriter, r *http.Request, err error) {
+	api.WriteError(w, r, api.Errorf(http.StatusBadRequest, "bad_request", "%s", err))
+}
+
+// handleSpec serves openapi.yaml, for clients and their generators.
+func handleSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(oapi.Spec)
+}
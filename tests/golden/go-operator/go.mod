@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+module golden-app
+
+go 1.22
+
+require (
+	github.com/go-logr/logr v1.4.2
+	k8s.io/api v0.31.2
+	k8s.io/apimachinery v0.31.2
+	k8s.io/client-go v0.31.2
+	sigs.k8s.io/controller-runtime v0.19.1
+	sigs.k8s.io/controller-tools v0.16.5
+)
@@ -0,0 +1,58 @@
+// Package config loads the operator's settings from environment
+// variables, with defaults, and checks them.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+)
+
+// Config is the operator's settings, each from the variable named
+// beside it.
+type Config struct {
+	Env      string     // APP_ENV: development (the default) or production
+	LogLevel slog.Level // LOG_LEVEL: debug, info (the default), warn or error
+
+	MetricsAddr string // METRICS_ADDR, where Prometheus metrics are served; :8080, or 0 for none
+	ProbeAddr   string // PROBE_ADDR, for /healthz and /readyz; :8081
+	LeaderElect bool   // LEADER_ELECT: only one replica reconciles at a time when true; false
+}
+
+// Load reads the settings through getenv (os.Getenv, or a stand-in in
+// tests). The error lists every setting that is wrong.
+func Load(getenv func(string) string) (Config, error) {
+	var errs []error
+	c := Config{
+		Env:         first(getenv("APP_ENV"), "development"),
+		MetricsAddr: first(getenv("METRICS_ADDR"), ":8080"),
+		ProbeAddr:   first(getenv("PROBE_ADDR"), ":8081"),
+	}
+	if c.Env != "development" && c.Env != "production" {
+		errs = append(errs, fmt.Errorf("APP_ENV: %q is neither development nor production", c.Env))
+	}
+	if err := c.LogLevel.UnmarshalText([]byte(first(getenv("LOG_LEVEL"), "info"))); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+	}
+	if c.MetricsAddr != "0" && !isAddr(c.MetricsAddr) {
+		errs = append(errs, fmt.Errorf("METRICS_ADDR: %q is neither an address such as :8080 nor 0", c.MetricsAddr))
+	}
+	if !isAddr(c.ProbeAddr) {
+		errs = append(errs, fmt.Errorf("PROBE_ADDR: %q is not an address such as :8081", c.ProbeAddr))
+	}
+	v := first(getenv("LEADER_ELECT"), "false")
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("LEADER_ELECT: %q is neither true nor false", v))
+	}
+	c.LeaderElect = b
+	return c, errors.Join(errs...)
+}
+
+// isAddr is whether s is host:port, the host perhaps empty.
+func isAddr(s string) bool {
+	_, port, err := net.SplitHostPort(s)
+	return err == nil && port != ""
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"golden-app/api/v1alpha1"
+)
+
+// newGreeting creates a Greeting saying message in a namespace of its
+// own.
+func newGreeting(t *testing.T, message string) *v1alpha1.Greeting {
+	t.Helper()
+	g := &v1alpha1.Greeting{
+		ObjectMeta: metav1.ObjectMeta{Name: "hello", Namespace: newNamespace(t)},
+		Spec:       v1alpha1.GreetingSpec{Message: message},
+	}
+	if err := k8sClient.Create(context.Background(), g); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// configMapSays is a check that g's ConfigMap holds message.
+func configMapSays(g *v1alpha1.Greeting, message string) func() error {
+	return func() error {
+		var cm corev1.ConfigMap
+		key := types.NamespacedName{Namespace: g.Namespace, Name: g.Name + "-greeting"}
+		if err := k8sClient.Get(context.Background(), key, &cm); err != nil {
+			return err
+		}
+		if got := cm.Data["message"]; got != message {
+			return fmt.Errorf("the ConfigMap says %q, want %q", got, message)
+		}
+		return nil
+	}
+}
+
+func TestReconcileCreatesTheConfigMap(t *testing.T) {
+	ctx := context.Background()
+	g := newGreeting(t, "Hello, Ada")
+	eventually(t, configMapSays(g, "Hello, Ada"))
+
+	var cm corev1.ConfigMap
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: g.Namespace, Name: g.Name + "-greeting"}, &cm); err != nil {
+		t.Fatal(err)
+	}
+	owner := metav1.GetControllerOf(&cm)
+	if owner == nil || owner.Kind != "Greeting" || owner.UID != g.UID {
+		t.Errorf("the ConfigMap is not owned by the Greeting: %+v", cm.OwnerReferences)
+	}
+
+	eventually(t, func() error {
+		var got v1alpha1.Greeting
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: g.Namespace, Name: g.Name}, &got); err != nil {
+			return err
+		}
+		if !meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionReady) {
+			return errors.New("the Greeting is not Ready")
+		}
+		if got.Status.ConfigMap != cm.Name || got.Status.ObservedGeneration != got.Generation {
+			return fmt.Errorf("unexpected status: %+v", got.Status)
+		}
+		return nil
+	})
+}
+
+func TestReconcileFollowsTheSpec(t *testing.T) {
+	ctx := context.Background()
+	g := newGreeting(t, "Hello")
+	eventually(t, configMapSays(g, "Hello"))
+
+	eventually(t, func() error {
+		var latest v1alpha1.Greeting
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: g.Namespace, Name: g.Name}, &latest); err != nil {
+			return err
+		}
+		latest.Spec.Message = "Goodbye"
+		return k8sClient.Update(ctx, &latest)
+	})
+	eventually(t, configMapSays(g, "Goodbye"))
+}
+
+func TestReconcileRestoresAnEditedConfigMap(t *testing.T) {
+	ctx := context.Background()
+	g := newGreeting(t, "Hello")
+	eventually(t, configMapSays(g, "Hello"))
+
+	eventually(t, func() error {
+		var cm corev1.ConfigMap
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: g.Namespace, Name: g.Name + "-greeting"}, &cm); err != nil {
+			return err
+		}
+		cm.Data["message"] = "edited by hand"
+		return k8sClient.Update(ctx, &cm)
+	})
+	eventually(t, configMapSays(g, "Hello"))
+}
+
+func TestTheCRDRejectsAnEmptyMessage(t *testing.T) {
+	g := &v1alpha1.Greeting{
+		ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: newNamespace(t)},
+	}
+	err := k8sClient.Create(context.Background(), g)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected the API server to refuse it as invalid, got %v", err)
+	}
+}
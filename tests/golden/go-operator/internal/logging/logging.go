@@ -0,0 +1,35 @@
+// Package logging sets up the app's slog logger and carries the logger
+// for each request or job, with its attributes, in the context it runs
+// in.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// New is a logger of lines at level and above: JSON when env is
+// "production", for log collectors, and text for people otherwise.
+func New(env string, level slog.Level, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if env == "production" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+type key struct{}
+
+// NewContext is ctx carrying logger, for FromContext.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, key{}, logger)
+}
+
+// FromContext is the logger NewContext put in ctx, or slog.Default().
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(key{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,7 @@
+//go:build tools
+
+// Package tools pins the controller-gen that just generate runs to
+// go.mod's version; nothing builds it.
+package tools
+
+import _ "sigs.k8s.io/controller-tools/cmd/controller-gen"
@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: MIT
+module golden-app
+
+go 1.21
+
+require (
+	github.com/charmbracelet/bubbles v0.20.0
+	github.com/charmbracelet/bubbletea v1.1.1
+	github.com/charmbracelet/lipgloss v0.13.0
+)
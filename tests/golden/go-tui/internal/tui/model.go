@@ -0,0 +1,55 @@
+// Package tui is the terminal interface, in bubbletea's shape: the
+// state is a Model (this file), Update (update.go) answers each message
+// with a new Model, and View (view.go) draws one. Keys are in keys.go
+// and colours in styles.go.
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// title heads the view.
+const title = "golden-app"
+
+type item struct {
+	title string
+	done  bool
+}
+
+// Model is a checklist, and which item the cursor is on. It is a value,
+// as bubbletea expects: Update returns a changed copy and leaves the
+// one it was called on alone.
+type Model struct {
+	items    []item
+	cursor   int
+	keys     keyMap
+	help     help.Model
+	quitting bool
+}
+
+// New is a checklist of titles, none checked off.
+func New(titles []string) Model {
+	items := make([]item, len(titles))
+	for i, t := range titles {
+		items[i] = item{title: t}
+	}
+	return Model{items: items, keys: keys, help: help.New()}
+}
+
+// Init is the command to run on start: none, as the items are there
+// already. One that fetches them would return it here.
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Done is the titles checked off, in order.
+func (m Model) Done() []string {
+	var done []string
+	for _, it := range m.items {
+		if it.done {
+			done = append(done, it.title)
+		}
+	}
+	return done
+}
@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+// TestProgram runs the model in a real bubbletea program, on a terminal
+// teatest stands in for, and types at it.
+func TestProgram(t *testing.T) {
+	tm := teatest.NewTestModel(t, New([]string{"Milk", "Eggs"}), teatest.WithInitialTermSize(80, 24))
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte("Eggs"))
+	}, teatest.WithDuration(3*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Type(" q")
+
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(3*time.Second)).(Model)
+	if !slices.Equal(final.Done(), []string{"Eggs"}) {
+		t.Errorf("expected Eggs checked off, got %v", final.Done())
+	}
+}
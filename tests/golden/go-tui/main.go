@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"golden-app/internal/tui"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "golden-app: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run shows a checklist of the items in args, or of examples without
+// them, and prints those checked off once it quits, one to a line, for
+// scripts to read.
+func run(args []string) error {
+	// The screen is the program's, so anything logged goes to a file:
+	// DEBUG=1 and just log to follow it.
+	if os.Getenv("DEBUG") != "" {
+		f, err := tea.LogToFile("debug.log", "debug")
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+	}
+	if len(args) == 0 {
+		args = []string{"Write the code", "Test it", "Ship it"}
+	}
+	final, err := tea.NewProgram(tui.New(args), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	for _, title := range final.(tui.Model).Done() {
+		fmt.Println(title)
+	}
+	return nil
+}
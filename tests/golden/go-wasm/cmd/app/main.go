@@ -0,0 +1,41 @@
+//go:build js && wasm
+
+// Command app is the program the page runs, compiled to WebAssembly. It
+// finds its elements in index.html by ID and wires them up through
+// syscall/js.
+package main
+
+import (
+	"syscall/js"
+
+	"golden-app/internal/greet"
+)
+
+func main() {
+	doc := js.Global().Get("document")
+	byID := func(id string) js.Value { return doc.Call("getElementById", id) }
+	name, greeting := byID("name"), byID("greeting")
+	wave, waved := byID("wave"), byID("waved")
+
+	update := func() {
+		greeting.Set("textContent", greet.Message(name.Get("value").String()))
+	}
+	// js.FuncOf makes a Go function callable from JavaScript. The page
+	// keeps these for as long as it is open, so they are never Released.
+	name.Call("addEventListener", "input", js.FuncOf(func(js.Value, []js.Value) any {
+		update()
+		return nil
+	}))
+	waves := 0
+	wave.Call("addEventListener", "click", js.FuncOf(func(js.Value, []js.Value) any {
+		waves++
+		waved.Set("textContent", greet.Waves(waves))
+		return nil
+	}))
+
+	update()
+	wave.Set("disabled", false)
+	doc.Get("body").Get("classList").Call("remove", "loading")
+	// The callbacks run on this program, so it must not return.
+	select {}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHomeHandler(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handleHome(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "<title>Home</title>") {
+		t.Errorf("expected the page in the layout, got %q", w.Body)
+	}
+}
+
+func TestGreetHandler(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/greet", nil)
+	w := httptest.NewRecorder()
+	handleGreet(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if got := w.Body.String(); got != "<p>Hello from the server!</p>" {
+		t.Errorf("expected the greeting fragment, got %q", got)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	renderPage(w, req, "missing.html", pageData{})
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("missing page: expected status 500, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	renderPage(w, req, "home.html", struct{}{}) // the layout reads .Title
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("bad data: expected status 500, got %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "<html") {
+		t.Errorf("expected no partial page, got %q", w.Body)
+	}
+}
@@ -0,0 +1,13 @@
+// Package middleware has the handlers main wraps around the mux: panic
+// recovery, request logging and request IDs.
+package middleware
+
+import "net/http"
+
+// Chain wraps h in each middleware, the first one outermost.
+func Chain(h http.Handler, middlewares ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import "net/http"
+
+// timedOut answers a request Timeout gave up on.
+func timedOut(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "the server took too long to answer", http.StatusServiceUnavailable)
+}
+
+// tooLarge answers a request MaxBytes refuses.
+func tooLarge(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golden-app/internal/flash"
+	"golden-app/internal/form"
+)
+
+func postContact(values url.Values) *httptest.ResponseRecorder {
+	return postContactTo(logContact, values)
+}
+
+func postContactTo(deliver func(context.Context, *form.Form) error, values url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/contact", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	handleContact(deliver)(w, req)
+	return w
+}
+
+func TestContactSent(t *testing.T) {
+	w := postContact(url.Values{"name": {"Ada"}, "email": {"ada@example.com"}, "message": {"Hello!"}})
+
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/contact" {
+		t.Fatalf("expected a redirect to the form, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	// The form it is sent back to says the message went, once.
+	req := httptest.NewRequest("GET", "/contact", nil)
+	for _, c := range w.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	if got := flash.Pop(httptest.NewRecorder(), req); !strings.Contains(got, "Thanks, Ada.") {
+		t.Errorf("expected a flash message thanking Ada, got %q", got)
+	}
+}
+
+func TestContactNotDelivered(t *testing.T) {
+	w := postContactTo(func(context.Context, *form.Form) error {
+		return errors.New("no way through")
+	}, url.Values{"name": {"Ada"}, "email": {"ada@example.com"}, "message": {"Hello!"}})
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "could not be sent") || len(w.Result().Cookies()) != 0 {
+		t.Errorf("expected the form shown again with the problem, and no flash message, got %q", w.Body)
+	}
+}
+
+func TestContactProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+		want   []string // on the page shown again
+	}{
+		{"empty", url.Values{}, []string{"This field is required."}},
+		{"bad email", url.Values{"name": {"Ada"}, "email": {"ada"}, "message": {"Hello!"}}, []string{"Enter an email address", `value="Ada"`}},
+		{"long message", url.Values{"name": {"Ada"}, "email": {"ada@example.com"}, "message": {strings.Repeat("a", 2001)}}, []string{"2000 characters or fewer"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postContact(tt.values)
+
+			if w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("expected status 422, got %d", w.Code)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("expected %q on the page, got %q", want, w.Body)
+				}
+			}
+			if len(w.Result().Cookies()) != 0 {
+				t.Error("expected no flash message")
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golden-app/internal/middleware"
+)
+
+func TestNotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleNotFound(w, httptest.NewRequest("GET", "/no/such/page", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "/no/such/page") {
+		t.Errorf("expected the path on the page, got %q", body)
+	}
+}
+
+func TestServerError(t *testing.T) {
+	panics := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("database on fire")
+	})
+	for _, dev := range []bool{false, true} {
+		w := httptest.NewRecorder()
+		middleware.RecoverWith(crashPage(dev))(panics).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("dev=%v: expected status 500, got %d", dev, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Errorf("dev=%v: expected an HTML page, got %q", dev, ct)
+		}
+		if shown := strings.Contains(w.Body.String(), "database on fire"); shown != dev {
+			t.Errorf("dev=%v: detail shown = %v", dev, shown)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+// SPDX-License-Identifier: MIT
+module golden-app
+
+go 1.22
+
+require golang.org/x/crypto v0.31.0
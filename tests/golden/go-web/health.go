@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golden-app/internal/logging"
+)
+
+// readyTimeout bounds each /readyz probe, so a hung dependency fails
+// the check instead of the prober timing out.
+const readyTimeout = 2 * time.Second
+
+// handleHealthz answers while the process is up: the liveness probe.
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+}
+
+// handleReadyz answers 200 when every check passes and 503, naming
+// those that failed, when one does: the readiness probe, so traffic
+// waits for the app's dependencies.
+func handleReadyz(checks map[string]func(context.Context) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+		var failed []string
+		for name, check := range checks {
+			if err := check(ctx); err != nil {
+				failed = append(failed, name+": "+err.Error())
+			}
+		}
+		if len(failed) > 0 {
+			sort.Strings(failed)
+			logging.FromContext(r.Context()).Warn("not ready", "failed", failed)
+			http.Error(w, strings.Join(failed, "\n"), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}
+}
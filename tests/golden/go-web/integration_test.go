@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golden-app/internal/config"
+	"golden-app/internal/middleware"
+)
+
+// The integration tests run the whole app, as main does, and compare
+// what each route answers with its golden file in testdata/. After
+// changing a page on purpose, rewrite them with
+//
+//	go test -run TestRoutes -update
+//
+// and check the diff before committing it.
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/ with what the app answers")
+
+// startApp runs the app with the settings in env, on top of the tests'
+// own and then the environment's, behind an httptest.Server, until the
+// test ends.
+func startApp(t *testing.T, env map[string]string) *httptest.Server {
+	t.Helper()
+	settings := map[string]string{
+		"APP_ENV":   "production", // the embedded static files, and no panic detail
+		"LOG_LEVEL": "error",
+	}
+	for k, v := range env {
+		settings[k] = v
+	}
+	getenv := func(key string) string {
+		if v, ok := settings[key]; ok {
+			return v
+		}
+		return os.Getenv(key)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan *httptest.Server, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, getenv, func(srv *http.Server, _ config.Config) error {
+			started <- httptest.NewServer(srv.Handler)
+			return nil
+		})
+	}()
+	select {
+	case ts := <-started:
+		t.Cleanup(func() {
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("shutdown: %v", err)
+			}
+			ts.Close()
+		})
+		return ts
+	case err := <-done:
+		cancel()
+		t.Fatalf("the app did not start: %v", err)
+		return nil
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	ts := startApp(t, nil)
+	tests := []struct {
+		path   string
+		status int
+		golden string // its file in testdata/
+	}{
+		{"/", http.StatusOK, "home.html"},
+		{"/api/greet", http.StatusOK, "greet.html"},
+		{"/contact", http.StatusOK, "contact.html"},
+		{"/no/such/page", http.StatusNotFound, "not-found.html"},
+		{"/healthz", http.StatusOK, "healthz.txt"},
+		{"/readyz", http.StatusOK, "readyz.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			res, err := ts.Client().Get(ts.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if res.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, res.StatusCode)
+			}
+			if id := res.Header.Get("X-Request-ID"); id == "" {
+				t.Error("expected the response to carry its request ID")
+			}
+			golden(t, tt.golden, scrub(res, body))
+		})
+	}
+}
+
+// scrub puts a placeholder in place of what differs from one request
+// to the next, the CSRF token, so the golden files can stay put.
+func scrub(res *http.Response, body []byte) []byte {
+	for _, c := range res.Cookies() {
+		if c.Name == middleware.CSRFCookie {
+			body = bytes.ReplaceAll(body, []byte(c.Value), []byte("CSRF_TOKEN"))
+		}
+	}
+	return body
+}
+
+// golden compares got with testdata/NAME or, with -update, writes it
+// there. A file that is not there yet is written too, so a new route's
+// first run records what it answers, to be checked and committed.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	want, err := os.ReadFile(path)
+	if *update || os.IsNotExist(err) {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("wrote %s", path)
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("the answer differs from %s (go test -update rewrites it):\n got: %s\nwant: %s", path, got, want)
+	}
+}
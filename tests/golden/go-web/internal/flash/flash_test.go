@@ -0,0 +1,41 @@
+package flash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetThenPop(t *testing.T) {
+	w := httptest.NewRecorder()
+	Set(w, "Thanks, Åse; we got it.")
+
+	r := httptest.NewRequest("GET", "/", nil)
+	for _, c := range w.Result().Cookies() {
+		r.AddCookie(c)
+	}
+	w = httptest.NewRecorder()
+	if got := Pop(w, r); got != "Thanks, Åse; we got it." {
+		t.Errorf("expected the message back, got %q", got)
+	}
+	cleared := w.Result().Cookies()
+	if len(cleared) != 1 || cleared[0].Name != Cookie || cleared[0].MaxAge >= 0 {
+		t.Errorf("expected the cookie cleared, got %v", cleared)
+	}
+}
+
+func TestPopWithoutAMessage(t *testing.T) {
+	w := httptest.NewRecorder()
+	if got := Pop(w, httptest.NewRequest("GET", "/", nil)); got != "" {
+		t.Errorf("expected no message, got %q", got)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("expected no cookie set")
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: Cookie, Value: "%%%"})
+	if got := Pop(httptest.NewRecorder(), r); got != "" {
+		t.Errorf("expected a broken cookie to be no message, got %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+package form
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestForm(t *testing.T) {
+	tests := []struct {
+		name   string
+		values url.Values
+		errors []string // the fields with a message
+	}{
+		{"valid", url.Values{"name": {" Ada "}, "email": {"ada@example.com"}}, nil},
+		{"missing", url.Values{"name": {"  "}}, []string{"email", "name"}},
+		{"too long", url.Values{"name": {strings.Repeat("å", 11)}, "email": {"ada@example.com"}}, []string{"name"}},
+		{"bad email", url.Values{"name": {"Ada"}, "email": {"Ada <ada@example.com>"}}, []string{"email"}},
+		{"email without a domain", url.Values{"name": {"Ada"}, "email": {"ada@localhost"}}, []string{"email"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(tt.values)
+			f.Required("name", "email")
+			f.MaxLength("name", 10)
+			f.Email("email")
+
+			var fields []string
+			for field := range f.Errors {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			if got, want := strings.Join(fields, " "), strings.Join(tt.errors, " "); got != want {
+				t.Errorf("expected errors for %q, got %v", want, f.Errors)
+			}
+			if f.Valid() != (len(tt.errors) == 0) {
+				t.Errorf("Valid() = %v with %v", f.Valid(), f.Errors)
+			}
+		})
+	}
+}
+
+func TestFirstProblemIsKept(t *testing.T) {
+	f := New(nil)
+	f.Required("name")
+	f.MaxLength("name", 0)
+	f.Check(false, "name", "later")
+	if got := f.Errors["name"]; got != "This field is required." {
+		t.Errorf("expected the first message, got %q", got)
+	}
+	if f.Get("name") != "" {
+		t.Error("expected an empty form to have no values")
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type corsKey struct{}
+
+// CORS lets pages from origins ("*" for any) call the app from the
+// browser with methods and headers. It answers their preflight OPTIONS
+// requests itself, with a 403 for an origin, method or header it does
+// not allow, and marks their other requests as allowed for the
+// browser. Requests from origins it does not know get no CORS headers,
+// so the browser keeps the answer from the page. No origins turns it
+// off. Credentials are not allowed, so pages elsewhere call the app
+// without its cookies, and CSRF lets their requests through.
+func CORS(origins, methods, headers []string) func(http.Handler) http.Handler {
+	if len(origins) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			w.Header().Add("Vary", "Origin")
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			allowed := slices.Contains(origins, "*") || slices.Contains(origins, origin)
+			if preflight {
+				if !allowed || !slices.Contains(methods, r.Header.Get("Access-Control-Request-Method")) ||
+					!allHeaders(headers, r.Header.Get("Access-Control-Request-Headers")) {
+					http.Error(w, "Forbidden: cross-origin request not allowed", http.StatusForbidden)
+					return
+				}
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			if !allowed {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), corsKey{}, true)))
+		})
+	}
+}
+
+// crossOrigin is whether CORS allowed the request in ctx from another
+// origin.
+func crossOrigin(ctx context.Context) bool {
+	allowed, _ := ctx.Value(corsKey{}).(bool)
+	return allowed
+}
+
+// allHeaders is whether each of the comma-separated requested headers
+// is one of allowed, which header names are regardless of case.
+func allHeaders(allowed []string, requested string) bool {
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.TrimSpace(h)
+		if h != "" && !slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(a, h) }) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"golden-app/internal/logging"
+)
+
+// Recover turns a panic in a handler into a plain-text 500, logging it
+// with the stack, so one bad request does not take the server down.
+func Recover(next http.Handler) http.Handler {
+	return RecoverWith(nil)(next)
+}
+
+// RecoverWith is Recover answering with page, which gets the panic's
+// value, in place of the plain-text 500 (which a nil page keeps).
+func RecoverWith(page func(w http.ResponseWriter, r *http.Request, err any)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err) // net/http's way to abort, not a crash
+				}
+				logging.FromContext(r.Context()).Error("panic", "err", err, "stack", string(debug.Stack()))
+				if page == nil {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+				page(w, r, err)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package static serves the files in internal/web/static/ and links
+// them from pages: URL adds a hash of a file's content, so browsers may
+// cache it for good and fetch it again once it changes.
+package static
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	mu     sync.Mutex
+	files  fs.FS = os.DirFS("internal/web/static")
+	cached bool
+	hashes = map[string]string{}
+)
+
+// Use serves embedded, the static/ built into the binary, hashing each
+// file once. In dev it reads internal/web/static/ from disk instead, so
+// edits show without a rebuild.
+func Use(embedded fs.FS, dev bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	files, cached, hashes = embedded, true, map[string]string{}
+	if dev {
+		files, cached = os.DirFS("internal/web/static"), false
+	}
+}
+
+// FS is the static files being served.
+func FS() fs.FS {
+	mu.Lock()
+	defer mu.Unlock()
+	return files
+}
+
+// URL is the address of static/NAME with the hash of its content; a
+// file that cannot be read is linked without one.
+func URL(name string) string {
+	hash, ok := hashOf(name)
+	if !ok {
+		return "/static/" + name
+	}
+	return "/static/" + name + "?v=" + hash
+}
+
+// hashOf is the start of the SHA-256 of static/NAME's content, kept
+// unless in dev, and false when it cannot be read.
+func hashOf(name string) (string, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if hash, ok := hashes[name]; ok {
+		return hash, true
+	}
+	data, err := fs.ReadFile(files, name)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:6])
+	if cached {
+		hashes[name] = hash
+	}
+	return hash, true
+}
+
+// Handler serves the files under /static/. A URL with a hash may be
+// cached for a year; anything else is checked with the server each
+// time, and answered with a 304 when the ETag, the same hash, shows the
+// browser has it already.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("v") {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		if hash, ok := hashOf(strings.TrimPrefix(r.URL.Path, "/static/")); ok {
+			w.Header().Set("ETag", `"`+hash+`"`)
+		}
+		http.StripPrefix("/static/", http.FileServer(http.FS(FS()))).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,78 @@
+package static
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestURL(t *testing.T) {
+	files := fstest.MapFS{"app.css": {Data: []byte("body{}")}}
+	Use(files, false)
+
+	url := URL("app.css")
+	if !strings.HasPrefix(url, "/static/app.css?v=") || len(url) != len("/static/app.css?v=")+12 {
+		t.Errorf("expected a hashed URL, got %q", url)
+	}
+	files["app.css"].Data = []byte("body{color:red}")
+	if URL("app.css") != url {
+		t.Error("expected an embedded file's hash to be kept")
+	}
+	if got := URL("missing.js"); got != "/static/missing.js" {
+		t.Errorf("expected no hash for a missing file, got %q", got)
+	}
+}
+
+func TestURLInDevReadsTheDisk(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	css := filepath.Join(dir, "internal", "web", "static", "app.css")
+	os.MkdirAll(filepath.Dir(css), 0o755)
+	os.WriteFile(css, []byte("body{}"), 0o644)
+	Use(nil, true)
+
+	before := URL("app.css")
+	os.WriteFile(css, []byte("body{color:red}"), 0o644)
+	if after := URL("app.css"); after == before {
+		t.Errorf("expected a new hash after an edit, got %q twice", after)
+	}
+}
+
+func TestHandlerCaching(t *testing.T) {
+	Use(fstest.MapFS{"app.css": {Data: []byte("body{}")}}, false)
+	for url, want := range map[string]string{
+		URL("app.css"):    "public, max-age=31536000, immutable",
+		"/static/app.css": "no-cache",
+	} {
+		w := httptest.NewRecorder()
+		Handler().ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+		if w.Code != 200 || w.Header().Get("Cache-Control") != want {
+			t.Errorf("%s: expected 200 with %q, got %d with %q", url, want, w.Code, w.Header().Get("Cache-Control"))
+		}
+	}
+}
+
+func TestHandlerETag(t *testing.T) {
+	Use(fstest.MapFS{"app.css": {Data: []byte("body{}")}}, false)
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/static/app.css", nil))
+	etag := w.Header().Get("ETag")
+	if w.Code != 200 || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d with %q", w.Code, etag)
+	}
+
+	req := httptest.NewRequest("GET", "/static/app.css", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	Handler().ServeHTTP(w, req)
+	if w.Code != 304 || w.Body.Len() != 0 {
+		t.Errorf("expected a 304 with no body for the ETag the browser has, got %d %q", w.Code, w.Body)
+	}
+}
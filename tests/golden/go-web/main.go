@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golden-app/components"
+	"golden-app/internal/config"
+	"golden-app/internal/logging"
+	"golden-app/internal/middleware"
+	"golden-app/internal/static"
+	"golden-app/internal/web"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop) // a second signal stops the process at once
+	if err := run(ctx, os.Getenv, listen); err != nil {
+		slog.Error("server", "err", err)
+		os.Exit(1)
+	}
+}
+
+// run is the app: it reads its settings through getenv, and serves
+// until ctx is done, then shuts down. serve listens for srv until it is
+// shut down, as listen does; the integration tests hand in one that
+// serves through httptest instead.
+func run(ctx context.Context, getenv func(string) string, serve func(*http.Server, config.Config) error) error {
+	cfg, err := config.Load(getenv)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	logger := logging.New(cfg.Env, cfg.LogLevel, os.Stderr)
+	slog.SetDefault(logger)
+	static.Use(web.Static, cfg.Env == "development")
+
+	// What /readyz waits on; the database addons add theirs.
+	ready := map[string]func(context.Context) error{}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /static/", static.Handler())
+	mux.HandleFunc("GET /{$}", handleHome)
+	mux.HandleFunc("GET /", handleNotFound)
+	mux.HandleFunc("GET /contact", handleContactForm)
+	mux.HandleFunc("POST /contact", handleContact(logContact))
+	mux.HandleFunc("GET /api/greet", handleGreet)
+	mux.HandleFunc("GET /healthz", handleHealthz)
+	mux.HandleFunc("GET /readyz", handleReadyz(ready))
+	if cfg.Profile {
+		mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+		mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	}
+
+	// Request IDs first so the log lines carry them; recovery inside the
+	// logger so a panic is logged as the 500 it becomes; the checks inside
+	// both, so what they turn away is logged too, and CORS ahead of the
+	// rate limit so a page can read the 429 it gets.
+	handler := middleware.Chain(mux,
+		middleware.RequestID,
+		middleware.Logger(logger),
+		middleware.RecoverWith(crashPage(cfg.Env == "development")),
+		middleware.Timeout(cfg.RequestTimeout, "/debug/pprof/"),
+		middleware.MaxBytes(cfg.MaxBodyBytes),
+		middleware.Under("/api/", middleware.CORS(cfg.CORSOrigins, cfg.CORSMethods, cfg.CORSHeaders)),
+		middleware.Under("/api/", middleware.RateLimit(cfg.RateLimit, cfg.RateBurst)),
+		middleware.CSRF(cfg.HTTPS()),
+	)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+	redirect := useTLS(srv, cfg)
+
+	failed := make(chan error, 2)
+	go func() {
+		logger.Info("listening", "port", cfg.Port, "url", cfg.BaseURL)
+		if err := serve(srv, cfg); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			failed <- fmt.Errorf("serve: %w", err)
+		}
+	}()
+	if redirect != nil {
+		go func() {
+			logger.Info("redirecting to https", "port", cfg.HTTPPort)
+			if err := redirect.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				failed <- fmt.Errorf("serve redirect: %w", err)
+			}
+		}()
+	}
+
+	select {
+	case err := <-failed:
+		return err
+	case <-ctx.Done():
+	}
+	logger.Info("shutting down")
+	// In-flight requests get this long to finish.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if redirect != nil {
+		redirect.Shutdown(shutdownCtx)
+	}
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	return nil
+}
+
+func handleHome(w http.ResponseWriter, r *http.Request) {
+	components.Page("Home", components.Home()).Render(r.Context(), w)
+}
+
+func handleGreet(w http.ResponseWriter, r *http.Request) {
+	message := "Hello from the server!"
+	// The request's logger adds its request_id to every line.
+	logging.FromContext(r.Context()).Info("greeting", "message", message)
+	components.Greeting(message).Render(r.Context(), w)
+}
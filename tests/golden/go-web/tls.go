@@ -0,0 +1,65 @@
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"golden-app/internal/config"
+)
+
+// useTLS sets srv up to serve https when cfg asks for it, and returns
+// the plain http server on HTTP_PORT that sends browsers there; nil
+// when the app serves plain http, as it does in development. With
+// TLS_DOMAINS, autocert gets and renews the certificates from Let's
+// Encrypt, answering its challenges on both servers, so the world must
+// reach PORT on 443 or HTTP_PORT on 80.
+func useTLS(srv *http.Server, cfg config.Config) *http.Server {
+	if !cfg.TLS() {
+		return nil
+	}
+	redirect := redirectToHTTPS(cfg.Port)
+	srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(cfg.TLSDomains) > 0 {
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLSDomains...),
+			Cache:      autocert.DirCache(cfg.TLSCache),
+		}
+		srv.TLSConfig = m.TLSConfig()
+		redirect = m.HTTPHandler(redirect)
+	}
+	return &http.Server{
+		Addr:              ":" + cfg.HTTPPort,
+		Handler:           redirect,
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+}
+
+// listen serves srv over https when useTLS set it up, http otherwise.
+func listen(srv *http.Server, cfg config.Config) error {
+	if srv.TLSConfig == nil {
+		return srv.ListenAndServe()
+	}
+	// Empty paths take the certificates from autocert's TLSConfig.
+	return srv.ListenAndServeTLS(cfg.TLSCert, cfg.TLSKey)
+}
+
+// redirectToHTTPS sends each request to the same URL over https on
+// port, which is left out when it is 443.
+func redirectToHTTPS(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"golden-app/internal/queue"
+)
+
+// maxPayload is how large a job's payload may be.
+const maxPayload = 1 << 20
+
+// handleEnqueue adds a job of the kind in the path, with the JSON body
+// as its payload, and answers 202 with its ID: how other programs hand
+// the worker work.
+func handleEnqueue(q *queue.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload json.RawMessage
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPayload)).Decode(&payload); err != nil {
+			writeError(w, http.StatusBadRequest, "the body must be the job's payload, as JSON")
+			return
+		}
+		id, err := q.Enqueue(r.Context(), r.PathValue("kind"), payload)
+		switch {
+		case errors.Is(err, queue.ErrUnknownKind):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, queue.ErrClosed):
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+		case err != nil:
+			// The queue stayed full until the client gave up.
+			writeError(w, http.StatusServiceUnavailable, "the queue is full")
+		default:
+			writeJSON(w, http.StatusAccepted, map[string]int64{"id": id})
+		}
+	}
+}
+
+// handleHealthz answers while the process is up: the liveness probe.
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,34 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	soon, later := f.After(time.Second), f.After(time.Minute)
+	f.BlockUntil(2)
+
+	f.Advance(time.Second)
+	select {
+	case at := <-soon:
+		if !at.Equal(start.Add(time.Second)) {
+			t.Errorf("expected %v, got %v", start.Add(time.Second), at)
+		}
+	default:
+		t.Error("expected a second to have passed")
+	}
+	select {
+	case <-later:
+		t.Error("expected a minute not to have passed")
+	default:
+	}
+	if now := f.Now(); !now.Equal(start.Add(time.Second)) {
+		t.Errorf("expected the clock at %v, got %v", start.Add(time.Second), now)
+	}
+	if at := <-f.After(0); !at.Equal(f.Now()) {
+		t.Errorf("expected After(0) at once, got %v", at)
+	}
+}
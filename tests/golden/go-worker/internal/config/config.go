@@ -0,0 +1,64 @@
+// Package config loads the worker's settings from environment
+// variables, with defaults, and checks them.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// Config is the worker's settings, each from the variable named beside
+// it.
+type Config struct {
+	Port     string     // PORT, for POST /jobs/{kind} and the health checks; 4000
+	Env      string     // APP_ENV: development (the default) or production
+	LogLevel slog.Level // LOG_LEVEL: debug, info (the default), warn or error
+
+	Workers         int           // WORKERS: jobs run at once; 4
+	QueueSize       int           // QUEUE_SIZE: jobs waiting to run before enqueueing waits; 1000
+	ShutdownTimeout time.Duration // SHUTDOWN_TIMEOUT: how long the drain on shutdown may take; 30s
+}
+
+// Load reads the settings through getenv (os.Getenv, or a stand-in in
+// tests). The error lists every setting that is wrong.
+func Load(getenv func(string) string) (Config, error) {
+	var errs []error
+	c := Config{
+		Port: first(getenv("PORT"), "4000"),
+		Env:  first(getenv("APP_ENV"), "development"),
+	}
+	if n, err := strconv.Atoi(c.Port); err != nil || n < 1 || n > 65535 {
+		errs = append(errs, fmt.Errorf("PORT: %q is not a port number", c.Port))
+	}
+	if c.Env != "development" && c.Env != "production" {
+		errs = append(errs, fmt.Errorf("APP_ENV: %q is neither development nor production", c.Env))
+	}
+	if err := c.LogLevel.UnmarshalText([]byte(first(getenv("LOG_LEVEL"), "info"))); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: %w", err))
+	}
+	for _, s := range []struct {
+		name string
+		to   *int
+		def  int
+	}{
+		{"WORKERS", &c.Workers, 4},
+		{"QUEUE_SIZE", &c.QueueSize, 1000},
+	} {
+		v := first(getenv(s.name), strconv.Itoa(s.def))
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			errs = append(errs, fmt.Errorf("%s: %q is not a positive number", s.name, v))
+		}
+		*s.to = n
+	}
+	v := first(getenv("SHUTDOWN_TIMEOUT"), "30s")
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		errs = append(errs, fmt.Errorf("SHUTDOWN_TIMEOUT: %q is not a duration such as 30s", v))
+	}
+	c.ShutdownTimeout = d
+	return c, errors.Join(errs...)
+}
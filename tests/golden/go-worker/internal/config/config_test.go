@@ -0,0 +1,47 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want func(Config) bool
+		err  string // what the error says, "" for none
+	}{
+		{"defaults", nil, func(c Config) bool {
+			return c.Port == "4000" && c.Env == "development" && c.LogLevel == slog.LevelInfo &&
+				c.Workers == 4 && c.QueueSize == 1000 && c.ShutdownTimeout == 30*time.Second
+		}, ""},
+		{"production", map[string]string{"APP_ENV": "production", "LOG_LEVEL": "debug"}, func(c Config) bool {
+			return c.Env == "production" && c.LogLevel == slog.LevelDebug
+		}, ""},
+		{"queue", map[string]string{"WORKERS": "16", "QUEUE_SIZE": "10", "SHUTDOWN_TIMEOUT": "2m"}, func(c Config) bool {
+			return c.Workers == 16 && c.QueueSize == 10 && c.ShutdownTimeout == 2*time.Minute
+		}, ""},
+		{"bad port", map[string]string{"PORT": "http"}, nil, "PORT"},
+		{"bad env", map[string]string{"APP_ENV": "prod"}, nil, "APP_ENV"},
+		{"bad log level", map[string]string{"LOG_LEVEL": "loud"}, nil, "LOG_LEVEL"},
+		{"no workers", map[string]string{"WORKERS": "0"}, nil, "WORKERS"},
+		{"bad queue size", map[string]string{"QUEUE_SIZE": "many"}, nil, "QUEUE_SIZE"},
+		{"bad shutdown timeout", map[string]string{"SHUTDOWN_TIMEOUT": "30"}, nil, "SHUTDOWN_TIMEOUT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(func(key string) string { return tt.env[key] })
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("expected an error about %s, got %v", tt.err, err)
+			case tt.want != nil && !tt.want(c):
+				t.Errorf("unexpected config: %+v", c)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// first is the first of values that is not empty.
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// duration is the setting name, a positive duration such as 10s or 2m,
+// or def when it is unset; a bad value is added to errs.
+func duration(getenv func(string) string, name string, def time.Duration, errs *[]error) time.Duration {
+	v := getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		*errs = append(*errs, fmt.Errorf("%s: %q is not a duration such as 30s or 2m", name, v))
+	}
+	return d
+}
+
+// size is the setting name, a number of bytes, or def when it is
+// unset; a bad value is added to errs.
+func size(getenv func(string) string, name string, def int64, errs *[]error) int64 {
+	v := getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		*errs = append(*errs, fmt.Errorf("%s: %q is not a number of bytes", name, v))
+	}
+	return n
+}
@@ -0,0 +1,219 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golden-app/internal/clock"
+)
+
+// start is a started queue on a fake clock, with a backoff of a second
+// per attempt so far, and the clock. The queue is shut down when the
+// test ends, if the test has not.
+func start(t *testing.T, opts Options, register func(*Queue)) (*Queue, *clock.Fake) {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	opts.Clock = clk
+	opts.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	if opts.Backoff == nil {
+		opts.Backoff = func(attempt int) time.Duration { return time.Duration(attempt) * time.Second }
+	}
+	q := New(opts)
+	register(q)
+	q.Start()
+	t.Cleanup(func() { q.Shutdown(context.Background()) })
+	return q, clk
+}
+
+// receive is the next value from ch, failing the test if none comes.
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+		panic("unreachable")
+	}
+}
+
+type greet struct {
+	Name string `json:"name"`
+}
+
+func TestEnqueue(t *testing.T) {
+	names := make(chan string, 3)
+	q, _ := start(t, Options{}, func(q *Queue) {
+		q.Register("greet", JSON(func(ctx context.Context, g greet) error {
+			names <- g.Name
+			return nil
+		}))
+	})
+	for _, name := range []string{"Ada", "Grace"} {
+		if _, err := q.Enqueue(context.Background(), "greet", greet{name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := map[string]bool{receive(t, names): true, receive(t, names): true}
+	if !got["Ada"] || !got["Grace"] {
+		t.Errorf("expected both greeted, got %v", got)
+	}
+	if _, err := q.Enqueue(context.Background(), "wave", nil); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("expected ErrUnknownKind, got %v", err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	attempts := make(chan int, 3)
+	q, clk := start(t, Options{}, func(q *Queue) {
+		q.Register("flaky", func(ctx context.Context, job *Job) error {
+			attempts <- job.Attempt
+			if job.Attempt < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		})
+	})
+	q.Enqueue(context.Background(), "flaky", nil)
+
+	// Each retry waits for its backoff, and not a moment less.
+	for attempt := 1; attempt <= 3; attempt++ {
+		if got := receive(t, attempts); got != attempt {
+			t.Fatalf("expected attempt %d, got %d", attempt, got)
+		}
+		if attempt < 3 {
+			clk.BlockUntil(1)
+			clk.Advance(time.Duration(attempt)*time.Second - time.Millisecond)
+			select {
+			case got := <-attempts:
+				t.Fatalf("expected attempt %d to wait, but it ran", got)
+			case <-time.After(10 * time.Millisecond):
+			}
+			clk.Advance(time.Millisecond)
+		}
+	}
+}
+
+func TestFailed(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		attempts int // how many runs, at most 2
+	}{
+		{"out of attempts", errors.New("down"), 2},
+		{"permanent", Permanent(errors.New("bad payload")), 1},
+		{"panic", nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var runs atomic.Int32
+			failed := make(chan error, 1)
+			q, clk := start(t, Options{Failed: func(job *Job, err error) { failed <- err }}, func(q *Queue) {
+				q.Register("doomed", func(ctx context.Context, job *Job) error {
+					runs.Add(1)
+					if tt.err == nil {
+						panic("oops")
+					}
+					return tt.err
+				}, MaxAttempts(2))
+			})
+			q.Enqueue(context.Background(), "doomed", nil)
+			if tt.attempts > 1 {
+				clk.BlockUntil(1)
+				clk.Advance(time.Second)
+			}
+			if err := receive(t, failed); err == nil {
+				t.Error("expected the error")
+			}
+			if got := int(runs.Load()); got != tt.attempts {
+				t.Errorf("expected %d runs, got %d", tt.attempts, got)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	failed := make(chan error, 1)
+	q, _ := start(t, Options{Failed: func(job *Job, err error) { failed <- err }}, func(q *Queue) {
+		q.Register("slow", func(ctx context.Context, job *Job) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, MaxAttempts(1), Timeout(time.Millisecond))
+	})
+	q.Enqueue(context.Background(), "slow", nil)
+	if err := receive(t, failed); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the run to time out, got %v", err)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	ticks := make(chan struct{}, 2)
+	_, clk := start(t, Options{}, func(q *Queue) {
+		q.Register("tick", func(ctx context.Context, job *Job) error {
+			ticks <- struct{}{}
+			return nil
+		})
+		q.Every("tick", time.Minute, nil)
+	})
+	for range 2 {
+		clk.BlockUntil(1)
+		clk.Advance(time.Minute)
+		receive(t, ticks)
+	}
+}
+
+func TestShutdownDrains(t *testing.T) {
+	release := make(chan struct{})
+	var done atomic.Int32
+	q, _ := start(t, Options{Workers: 1}, func(q *Queue) {
+		q.Register("wait", func(ctx context.Context, job *Job) error {
+			<-release
+			done.Add(1)
+			return nil
+		})
+	})
+	for range 3 {
+		q.Enqueue(context.Background(), "wait", nil)
+	}
+	shutdown := make(chan error)
+	go func() { shutdown <- q.Shutdown(context.Background()) }()
+	// Shutdown stops new jobs at once, and waits for those it has.
+	for {
+		if _, err := q.Enqueue(context.Background(), "wait", nil); errors.Is(err, ErrClosed) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	if err := receive(t, shutdown); err != nil {
+		t.Fatal(err)
+	}
+	if done.Load() < 3 {
+		t.Errorf("expected the 3 jobs before Shutdown done, got %d", done.Load())
+	}
+}
+
+func TestShutdownRunsOutOfTime(t *testing.T) {
+	running := make(chan struct{}, 2)
+	q, _ := start(t, Options{Workers: 1}, func(q *Queue) {
+		q.Register("stuck", func(ctx context.Context, job *Job) error {
+			running <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		}, MaxAttempts(1))
+	})
+	q.Enqueue(context.Background(), "stuck", nil)
+	q.Enqueue(context.Background(), "stuck", nil)
+	receive(t, running)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline, got %v", err)
+	}
+}
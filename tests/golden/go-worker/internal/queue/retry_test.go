@@ -0,0 +1,25 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{20, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		for range 100 {
+			if d := ExponentialBackoff(tt.attempt); d < tt.max/2 || d > tt.max {
+				t.Fatalf("attempt %d: expected between %v and %v, got %v", tt.attempt, tt.max/2, tt.max, d)
+			}
+		}
+	}
+}
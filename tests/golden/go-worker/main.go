@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"golden-app/internal/config"
+	"golden-app/internal/jobs"
+	"golden-app/internal/logging"
+	"golden-app/internal/queue"
+)
+
+func main() {
+	cfg, err := config.Load(os.Getenv)
+	if err != nil {
+		slog.Error("config", "err", err)
+		os.Exit(1)
+	}
+	logger := logging.New(cfg.Env, cfg.LogLevel, os.Stderr)
+	slog.SetDefault(logger)
+
+	// Options.Failed is the place to record jobs that failed for good,
+	// beyond the log line each gets.
+	q := queue.New(queue.Options{Workers: cfg.Workers, Size: cfg.QueueSize, Logger: logger})
+	jobs.Register(q)
+	q.Start()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs/{kind}", handleEnqueue(q))
+	mux.HandleFunc("GET /healthz", handleHealthz)
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		logger.Info("working", "workers", cfg.Workers, "port", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("serve", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // a second signal stops the process at once
+	logger.Info("shutting down", "timeout", cfg.ShutdownTimeout)
+	// The server first, so no job is enqueued after the drain begins;
+	// then the jobs running and waiting get what is left of the time.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := errors.Join(srv.Shutdown(shutdownCtx), q.Shutdown(shutdownCtx)); err != nil {
+		logger.Error("shutdown", "err", err)
+		os.Exit(1)
+	}
+	logger.Info("drained")
+}
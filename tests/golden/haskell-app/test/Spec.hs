@@ -0,0 +1,2 @@
+-- hspec-discover runs every *Spec.hs module in test/.
+{-# OPTIONS_GHC -F -pgmF hspec-discover #-}
@@ -0,0 +1,18 @@
+# The module with its defaults: terraform init && terraform plan, with
+# AWS credentials in the environment.
+
+provider "aws" {
+  region = "eu-north-1"
+}
+
+module "bucket" {
+  source = "../.."
+
+  name = "golden-app-example"
+  tags = { Example = "basic" }
+}
+
+output "name" {
+  description = "The bucket's name"
+  value       = module.bucket.name
+}
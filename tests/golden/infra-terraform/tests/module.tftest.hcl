@@ -0,0 +1,27 @@
+# terraform test runs these against a mocked aws provider:
+# each run plans the module, so no credentials or resources are needed.
+
+mock_provider "aws" {}
+
+variables {
+  name = "golden-app-example"
+}
+
+run "names_the_resources" {
+  command = plan
+
+  assert {
+    condition     = output.name == "golden-app-example"
+    error_message = "The name output should be the name it was given."
+  }
+}
+
+run "rejects_an_invalid_name" {
+  command = plan
+
+  variables {
+    name = "Not A Valid Name"
+  }
+
+  expect_failures = [var.name]
+}
@@ -0,0 +1,27 @@
+variable "name" {
+  description = "The bucket's name, unique across all of S3"
+  type        = string
+
+  validation {
+    condition     = can(regex("^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$", var.name))
+    error_message = "Bucket names are 3 to 63 lowercase letters, digits, dots and hyphens."
+  }
+}
+
+variable "versioning" {
+  description = "Whether to keep every version of each object"
+  type        = bool
+  default     = true
+}
+
+variable "force_destroy" {
+  description = "Whether destroying the bucket deletes the objects in it too"
+  type        = bool
+  default     = false
+}
+
+variable "tags" {
+  description = "Tags for the bucket"
+  type        = map(string)
+  default     = {}
+}
@@ -0,0 +1,33 @@
+plugins {
+    alias(libs.plugins.kotlin.jvm)
+    alias(libs.plugins.ktlint)
+    application
+}
+
+group = "com.example"
+version = "0.1.0"
+
+repositories {
+    mavenCentral()
+}
+
+dependencies {
+    testImplementation(platform(libs.junit.bom))
+    testImplementation(libs.junit.jupiter)
+    testImplementation(libs.kotest.runner)
+    testImplementation(libs.kotest.assertions)
+    testRuntimeOnly(libs.junit.launcher)
+}
+
+kotlin {
+    jvmToolchain(21)
+}
+
+application {
+    mainClass = "com.example.goldenapp.AppKt"
+}
+
+tasks.test {
+    // JUnit 5 runs both the JUnit tests and the kotest specs.
+    useJUnitPlatform()
+}
@@ -0,0 +1,5 @@
+package com.example.goldenapp
+
+fun main(args: Array<String>) {
+    println(greet(args.firstOrNull().orEmpty()))
+}
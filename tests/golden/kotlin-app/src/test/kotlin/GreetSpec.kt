@@ -0,0 +1,12 @@
+package com.example.goldenapp
+
+import io.kotest.core.spec.style.StringSpec
+import io.kotest.matchers.shouldBe
+
+class GreetSpec :
+    StringSpec({
+        "greets the world without a name" {
+            greet("") shouldBe "Hello, World!"
+            greet(" \t ") shouldBe "Hello, World!"
+        }
+    })
@@ -0,0 +1,7 @@
+;;;; sbcl --non-interactive --load scripts/build.lisp, from the project's
+;;;; directory: builds the executable into bin/.
+
+(require :asdf)
+(asdf:load-asd (truename "golden-app.asd"))
+(ql:quickload "golden-app" :silent t)
+(asdf:make "golden-app")
@@ -0,0 +1,9 @@
+;;;; sbcl --non-interactive --load scripts/lint.lisp, from the project's
+;;;; directory: compiles both systems afresh, failing on a warning.
+
+(require :asdf)
+(asdf:load-asd (truename "golden-app.asd"))
+(ql:quickload "golden-app/tests" :silent t)
+(setf asdf:*compile-file-warnings-behaviour* :error)
+(asdf:compile-system "golden-app/tests"
+                     :force '("golden-app" "golden-app/tests"))
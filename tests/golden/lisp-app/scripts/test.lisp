@@ -0,0 +1,7 @@
+;;;; sbcl --non-interactive --load scripts/test.lisp, from the project's
+;;;; directory: runs the FiveAM suite, exiting non-zero when a test fails.
+
+(require :asdf)
+(asdf:load-asd (truename "golden-app.asd"))
+(ql:quickload "golden-app/tests" :silent t)
+(asdf:test-system "golden-app")
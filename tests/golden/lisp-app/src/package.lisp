@@ -0,0 +1,4 @@
+(defpackage #:golden-app
+  (:use #:cl)
+  (:export #:greet
+           #:main))
@@ -0,0 +1,18 @@
+(defpackage #:golden-app/tests
+  (:use #:cl #:fiveam)
+  (:import-from #:golden-app #:greet)
+  (:export #:all))
+
+(in-package #:golden-app/tests)
+
+(def-suite all :description "Every test of golden-app.")
+(in-suite all)
+
+(test greets-by-name
+  (is (string= "Hello, Ada!" (greet "Ada")))
+  (is (string= "Hello, Ada Lovelace!"
+               (greet (format nil "  Ada Lovelace~%")))))
+
+(test greets-the-world-without-a-name
+  (is (string= "Hello, World!" (greet nil)))
+  (is (string= "Hello, World!" (greet (format nil " ~C " #\Tab)))))
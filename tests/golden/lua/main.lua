@@ -0,0 +1,4 @@
+-- lua main.lua [NAME]: prints a greeting.
+local greet = require("src.greet")
+
+print(greet.greet(arg[1]))
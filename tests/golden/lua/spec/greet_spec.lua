@@ -0,0 +1,13 @@
+local greet = require("src.greet").greet
+
+describe("greet", function()
+  it("greets by name", function()
+    assert.are.equal("Hello, Ada!", greet("Ada"))
+    assert.are.equal("Hello, Ada Lovelace!", greet("  Ada Lovelace\n"))
+  end)
+
+  it("greets the world without a name", function()
+    assert.are.equal("Hello, World!", greet(nil))
+    assert.are.equal("Hello, World!", greet(" \t "))
+  end)
+end)
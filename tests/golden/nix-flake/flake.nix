@@ -0,0 +1,70 @@
+{
+  description = "golden-app: A project rendered for the golden snapshots";
+
+  inputs.nixpkgs.url = "github:NixOS/nixpkgs/nixos-24.11";
+
+  outputs =
+    { self, nixpkgs }:
+    let
+      # The systems the packages, shells and checks are built for.
+      systems = [
+        "x86_64-linux"
+        "aarch64-linux"
+        "x86_64-darwin"
+        "aarch64-darwin"
+      ];
+
+      # forAllSystems f is { <system> = f pkgs; }, with pkgs the
+      # system's nixpkgs with this flake's overlay applied.
+      forAllSystems =
+        f:
+        nixpkgs.lib.genAttrs systems (
+          system:
+          f (
+            import nixpkgs {
+              inherit system;
+              overlays = [ self.overlays.default ];
+            }
+          )
+        );
+    in
+    {
+      overlays.default = final: _prev: {
+        golden-app = final.callPackage ./nix/package.nix { };
+      };
+
+      packages = forAllSystems (pkgs: {
+        default = pkgs.golden-app;
+        inherit (pkgs) golden-app;
+      });
+
+      devShells = forAllSystems (pkgs: {
+        default = pkgs.mkShell {
+          inputsFrom = [ pkgs.golden-app ];
+          packages = with pkgs; [
+            deadnix
+            just
+            nixfmt-rfc-style
+            statix
+          ];
+        };
+      });
+
+      checks = forAllSystems (pkgs: {
+        package = pkgs.golden-app;
+
+        greets = pkgs.runCommand "golden-app-greets" { } ''
+          [ "$(${pkgs.lib.getExe pkgs.golden-app} Ada)" = "Hello, Ada!" ]
+          [ "$(${pkgs.lib.getExe pkgs.golden-app})" = "Hello, World!" ]
+          touch $out
+        '';
+
+        formatting = pkgs.runCommand "golden-app-formatting" { } ''
+          ${pkgs.lib.getExe pkgs.nixfmt-rfc-style} --check ${./flake.nix} ${./nix}/*.nix
+          touch $out
+        '';
+      });
+
+      formatter = forAllSystems (pkgs: pkgs.nixfmt-rfc-style);
+    };
+}
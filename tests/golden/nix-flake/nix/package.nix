@@ -0,0 +1,13 @@
+# The program in src/, as a shell application: writeShellApplication
+# runs it under bash with set -euo pipefail and checks it with
+# shellcheck as it builds.
+{ writeShellApplication }:
+
+writeShellApplication {
+  name = "golden-app";
+  text = builtins.readFile ../src/golden-app.sh;
+  meta = {
+    description = "A project rendered for the golden snapshots";
+    mainProgram = "golden-app";
+  };
+}
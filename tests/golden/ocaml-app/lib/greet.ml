@@ -0,0 +1,4 @@
+let greet name =
+  match String.trim name with
+  | "" -> "Hello, World!"
+  | name -> Printf.sprintf "Hello, %s!" name
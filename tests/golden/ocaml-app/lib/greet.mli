@@ -0,0 +1,6 @@
+(** Greetings, in the library so that the tests and other programs can use
+    them as well as bin/main.ml. *)
+
+val greet : string -> string
+(** [greet name] is ["Hello, name!"], or ["Hello, World!"] for a name that is
+    empty or blank. *)
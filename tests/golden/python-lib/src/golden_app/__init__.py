@@ -0,0 +1,13 @@
+"""golden-app: slugs for URLs and file names.
+
+>>> from golden_app import slugify
+>>> slugify("Crème brûlée: a how-to")
+'creme-brulee-a-how-to'
+"""
+
+from importlib.metadata import version
+
+from golden_app.slug import is_slug, slugify
+
+__all__ = ["is_slug", "slugify"]
+__version__ = version("golden-app")
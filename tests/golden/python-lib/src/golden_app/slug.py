@@ -0,0 +1,53 @@
+"""Making slugs: lower-case ASCII words joined by a separator."""
+
+import re
+import unicodedata
+
+_NOT_WORD = re.compile(r"[^a-z0-9]+")
+_SLUG = re.compile(r"[a-z0-9]+(?:-[a-z0-9]+)*")
+
+# Letters NFKD does not take apart into a base letter and accents.
+_FOLDS = str.maketrans({"ß": "ss", "æ": "ae", "ø": "o", "œ": "oe", "đ": "d", "ł": "l"})
+
+
+def slugify(text: str, *, sep: str = "-", max_len: int = 0) -> str:
+    """TEXT as a slug: letters folded to lower-case ASCII (é to e, ß to
+    ss), apostrophes dropped, and any run of other characters made one
+    SEP, none at either end. It is "" when TEXT has no letters or digits.
+
+    With MAX_LEN over zero the slug is cut to at most that many
+    characters, after the last word that fits, or within the first word
+    when even that is too long.
+
+    >>> slugify("Don't panic!")
+    'dont-panic'
+    >>> slugify("The quick brown fox", sep="_", max_len=12)
+    'the_quick'
+    """
+    folded = unicodedata.normalize("NFKD", text.lower().translate(_FOLDS))
+    # Dropping what is not ASCII drops the accents NFKD split off, and
+    # curly apostrophes; the straight ones go next, so don't is dont.
+    ascii_only = folded.encode("ascii", "ignore").decode().replace("'", "")
+    words = _NOT_WORD.split(ascii_only)
+    slug = sep.join(word for word in words if word)
+    return _cut(slug, sep, max_len)
+
+
+def _cut(slug: str, sep: str, max_len: int) -> str:
+    if max_len <= 0 or len(slug) <= max_len:
+        return slug
+    short = slug[:max_len]
+    if not slug[max_len:].startswith(sep) and sep in short:
+        # The cut is within a word: drop what fits of it.
+        short = short[: short.rindex(sep)]
+    return short.rstrip(sep) if sep else short
+
+
+def is_slug(text: str) -> bool:
+    """Whether TEXT is a slug as slugify makes them with its default
+    separator.
+
+    >>> is_slug("hello-world"), is_slug("Hello World")
+    (True, False)
+    """
+    return _SLUG.fullmatch(text) is not None
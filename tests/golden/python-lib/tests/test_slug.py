@@ -0,0 +1,56 @@
+import pytest
+
+import golden_app
+from golden_app import is_slug, slugify
+
+
+@pytest.mark.parametrize(
+    ("text", "want"),
+    [
+        ("Hello, World!", "hello-world"),
+        ("  many   spaces  ", "many-spaces"),
+        ("Crème brûlée", "creme-brulee"),
+        ("Straße", "strasse"),
+        ("Don't stop", "dont-stop"),
+        ("dots.and_underscores", "dots-and-underscores"),
+        ("100% sure", "100-sure"),
+        ("!!!", ""),
+        ("", ""),
+    ],
+)
+def test_slugify(text: str, want: str) -> None:
+    assert slugify(text) == want
+
+
+@pytest.mark.parametrize(
+    ("max_len", "want"),
+    [
+        (0, "the-quick-brown-fox"),
+        (50, "the-quick-brown-fox"),
+        (9, "the-quick"),
+        (10, "the-quick"),
+        (12, "the-quick"),
+        (2, "th"),
+    ],
+)
+def test_slugify_cuts_at_a_word(max_len: int, want: str) -> None:
+    assert slugify("The quick brown fox", max_len=max_len) == want
+
+
+def test_slugify_with_another_separator() -> None:
+    assert slugify("Hello, World!", sep="_") == "hello_world"
+    assert slugify("a b c", sep="--", max_len=4) == "a--b"
+
+
+@pytest.mark.parametrize("text", ["Hello, World!", "Crème brûlée", "x" * 3])
+def test_slugs_are_valid(text: str) -> None:
+    assert is_slug(slugify(text))
+
+
+@pytest.mark.parametrize("text", ["", "Hello", "a--b", "-a", "a-", "a b"])
+def test_is_slug_refuses(text: str) -> None:
+    assert not is_slug(text)
+
+
+def test_version() -> None:
+    assert golden_app.__version__ == "0.1.0"
@@ -0,0 +1,17 @@
+import uvicorn
+
+from golden_app.settings import get_settings
+
+
+def main() -> None:
+    settings = get_settings()
+    uvicorn.run(
+        "golden_app.app:app",
+        host="0.0.0.0",
+        port=settings.port,
+        reload=settings.app_env == "development",
+    )
+
+
+if __name__ == "__main__":
+    main()
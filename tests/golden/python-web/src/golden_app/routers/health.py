@@ -0,0 +1,10 @@
+from fastapi import APIRouter
+
+router = APIRouter(tags=["health"])
+
+
+@router.get("/healthz")
+async def healthz() -> dict[str, str]:
+    """Liveness, for orchestrators and load balancers: the process is up
+    and answering."""
+    return {"status": "ok"}
@@ -0,0 +1,13 @@
+from fastapi.testclient import TestClient
+
+
+def test_home(client: TestClient) -> None:
+    response = client.get("/")
+    assert response.status_code == 200
+    assert "Hello, World!" in response.text
+
+
+def test_healthz(client: TestClient) -> None:
+    response = client.get("/healthz")
+    assert response.status_code == 200
+    assert response.json() == {"status": "ok"}
@@ -0,0 +1,32 @@
+import pytest
+from pydantic import ValidationError
+
+from golden_app.settings import Settings
+
+
+def test_defaults(monkeypatch: pytest.MonkeyPatch) -> None:
+    for name in ("APP_ENV", "PORT", "APP_PROFILE", "GREETING"):
+        monkeypatch.delenv(name, raising=False)
+    settings = Settings(_env_file=None)
+    assert settings.app_env == "development"
+    assert settings.port == 4000
+    assert settings.app_profile is True
+
+
+def test_from_the_environment(monkeypatch: pytest.MonkeyPatch) -> None:
+    monkeypatch.setenv("PORT", "8080")
+    monkeypatch.setenv("APP_ENV", "production")
+    monkeypatch.setenv("APP_PROFILE", "0")
+    settings = Settings(_env_file=None)
+    assert settings.port == 8080
+    assert settings.app_env == "production"
+    assert settings.app_profile is False
+
+
+@pytest.mark.parametrize(("name", "value"), [("PORT", "http"), ("APP_ENV", "prod")])
+def test_refuses_bad_values(
+    monkeypatch: pytest.MonkeyPatch, name: str, value: str
+) -> None:
+    monkeypatch.setenv(name, value)
+    with pytest.raises(ValidationError):
+        Settings(_env_file=None)
@@ -0,0 +1,10 @@
+def hello() -> str:
+    return "Hello, World!"
+
+
+def main() -> None:
+    print(hello())
+
+
+if __name__ == "__main__":
+    main()
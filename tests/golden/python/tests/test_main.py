@@ -0,0 +1,5 @@
+from golden_app.main import hello
+
+
+def test_hello():
+    assert hello() == "Hello, World!"
@@ -0,0 +1,101 @@
+//! The command line: the flags every command takes, and a variant of
+//! `Command` for each subcommand.
+
+use std::{env, io::Write, path::PathBuf};
+
+use anyhow::{Context, Result};
+use clap::{Args, Parser, Subcommand};
+
+use crate::config::Config;
+
+/// A command-line program.
+#[derive(Debug, Parser)]
+#[command(version, propagate_version = true)]
+pub struct Cli {
+    /// Read the settings from FILE instead of the default one
+    #[arg(long, global = true, value_name = "FILE", env = "GOLDEN_APP_CONFIG")]
+    pub config: Option<PathBuf>,
+
+    #[command(subcommand)]
+    pub command: Command,
+}
+
+#[derive(Debug, Subcommand)]
+pub enum Command {
+    /// Greet someone
+    Greet(GreetArgs),
+    /// Print the settings in effect, as TOML
+    Config,
+}
+
+#[derive(Debug, Args)]
+pub struct GreetArgs {
+    /// Who to greet
+    #[arg(default_value = "World")]
+    pub name: String,
+
+    /// Greet them in capitals
+    #[arg(short, long)]
+    pub shout: bool,
+}
+
+impl Cli {
+    /// Runs the command, writing what it prints to `out`.
+    pub fn run(self, out: &mut impl Write) -> Result<()> {
+        let config = Config::load(self.config.as_deref(), |key| env::var(key).ok())?;
+        match self.command {
+            Command::Greet(args) => greet(&config, &args, out),
+            Command::Config => write!(out, "{}", config.to_toml()).context("writing the settings"),
+        }
+    }
+}
+
+fn greet(config: &Config, args: &GreetArgs, out: &mut impl Write) -> Result<()> {
+    let mut greeting = format!("{}, {}!", config.greeting, args.name);
+    if args.shout {
+        greeting = greeting.to_uppercase();
+    }
+    writeln!(out, "{greeting}").context("writing the greeting")
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn run(args: &[&str]) -> String {
+        let cli =
+            Cli::try_parse_from(std::iter::once("golden-app").chain(args.iter().copied())).unwrap();
+        let mut out = Vec::new();
+        greet(&Config::default(), &greet_args(cli), &mut out).unwrap();
+        String::from_utf8(out).unwrap()
+    }
+
+    fn greet_args(cli: Cli) -> GreetArgs {
+        match cli.command {
+            Command::Greet(args) => args,
+            command => panic!("expected greet, got {command:?}"),
+        }
+    }
+
+    #[test]
+    fn greets_the_world_by_default() {
+        assert_eq!(run(&["greet"]), "Hello, World!\n");
+    }
+
+    #[test]
+    fn shouts() {
+        assert_eq!(run(&["greet", "--shout", "Ada"]), "HELLO, ADA!\n");
+    }
+
+    #[test]
+    fn refuses_an_unknown_command() {
+        let err = Cli::try_parse_from(["golden-app", "wave"]).unwrap_err();
+        assert_eq!(err.kind(), clap::error::ErrorKind::InvalidSubcommand);
+    }
+
+    #[test]
+    fn the_command_line_is_consistent() {
+        use clap::CommandFactory;
+        Cli::command().debug_assert();
+    }
+}
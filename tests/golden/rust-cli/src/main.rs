@@ -0,0 +1,22 @@
+mod cli;
+mod config;
+
+use std::{io, process::ExitCode};
+
+use clap::Parser;
+
+use crate::cli::Cli;
+
+// clap exits with 2 on a wrong command line, before run; a failure
+// after that is reported with its causes, one after the other, and
+// exits with 1.
+fn main() -> ExitCode {
+    let cli = Cli::parse();
+    match cli.run(&mut io::stdout().lock()) {
+        Ok(()) => ExitCode::SUCCESS,
+        Err(err) => {
+            eprintln!("{}: {err:#}", env!("CARGO_PKG_NAME"));
+            ExitCode::FAILURE
+        }
+    }
+}
@@ -0,0 +1,87 @@
+//! The whole program, run as a user would: each test gets a config
+//! directory of its own, so the settings on the machine running them
+//! do not count.
+
+use std::fs;
+
+use assert_cmd::Command;
+use predicates::prelude::*;
+use tempfile::TempDir;
+
+const ENV_PREFIX: &str = "GOLDEN_APP_";
+
+fn cli() -> (Command, TempDir) {
+    let home = tempfile::tempdir().unwrap();
+    let mut cmd = Command::new(env!("CARGO_BIN_EXE_golden-app"));
+    cmd.env("XDG_CONFIG_HOME", home.path())
+        .env_remove(format!("{ENV_PREFIX}CONFIG"))
+        .env_remove(format!("{ENV_PREFIX}GREETING"));
+    (cmd, home)
+}
+
+#[test]
+fn greets() {
+    let (mut cmd, _home) = cli();
+    cmd.args(["greet", "Ada"])
+        .assert()
+        .success()
+        .stdout("Hello, Ada!\n");
+}
+
+#[test]
+fn reads_the_config_file_then_the_environment() {
+    let (mut cmd, home) = cli();
+    let path = home.path().join("settings.toml");
+    fs::write(&path, "greeting = \"Hi\"\n").unwrap();
+    cmd.arg("--config")
+        .arg(&path)
+        .arg("greet")
+        .assert()
+        .success()
+        .stdout("Hi, World!\n");
+
+    let (mut cmd, _home) = cli();
+    cmd.env(format!("{ENV_PREFIX}GREETING"), "Howdy")
+        .args(["greet", "--shout"])
+        .assert()
+        .success()
+        .stdout("HOWDY, WORLD!\n");
+}
+
+#[test]
+fn prints_the_settings() {
+    let (mut cmd, _home) = cli();
+    cmd.arg("config")
+        .assert()
+        .success()
+        .stdout("greeting = \"Hello\"\n");
+}
+
+#[test]
+fn fails_with_1_and_the_cause() {
+    let (mut cmd, home) = cli();
+    cmd.arg("--config")
+        .arg(home.path().join("missing.toml"))
+        .arg("greet")
+        .assert()
+        .code(1)
+        .stderr(
+            predicate::str::contains("could not read")
+                .and(predicate::str::contains("missing.toml")),
+        );
+}
+
+#[test]
+fn fails_with_2_on_a_wrong_command_line() {
+    let (mut cmd, _home) = cli();
+    cmd.arg("wave").assert().code(2);
+}
+
+#[test]
+fn prints_its_version() {
+    let (mut cmd, _home) = cli();
+    cmd.arg("--version")
+        .assert()
+        .success()
+        .stdout(predicate::str::contains(env!("CARGO_PKG_VERSION")));
+}
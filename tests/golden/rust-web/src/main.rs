@@ -0,0 +1,281 @@
+mod api;
+
+use std::{env, future::IntoFuture, sync::Arc, time::Duration};
+
+use axum::{
+    error_handling::HandleErrorLayer,
+    extract::{Query, State},
+    http::{header, StatusCode},
+    response::{Html, IntoResponse, Response},
+    routing::{get, post},
+    BoxError, Json, Router,
+};
+use axum_htmx::HxRequest;
+use minijinja::{context, Environment};
+use pprof::ProfilerGuard;
+use serde::Deserialize;
+use serde_json::{json, Value};
+use tokio::sync::Notify;
+use tower::ServiceBuilder;
+use tower_http::{services::ServeDir, trace::TraceLayer};
+use tower_livereload::LiveReloadLayer;
+use tracing_subscriber::EnvFilter;
+
+// How long the open connections get to finish once shutdown begins;
+// the live-reload one never does of itself.
+const DRAIN_TIMEOUT: Duration = Duration::from_secs(10);
+
+struct AppState {
+    env: Environment<'static>,
+}
+
+#[derive(Deserialize)]
+struct ProfileQuery {
+    seconds: Option<u64>,
+}
+
+#[tokio::main]
+async fn main() {
+    tracing_subscriber::fmt()
+        .with_env_filter(
+            EnvFilter::try_from_default_env().unwrap_or_else(|_| "info,tower_http=debug".into()),
+        )
+        .init();
+
+    let port = env::var("PORT").unwrap_or_else(|_| "4000".into());
+    let timeout = env::var("REQUEST_TIMEOUT")
+        .ok()
+        .and_then(|secs| secs.parse().ok())
+        .map(Duration::from_secs)
+        .unwrap_or(Duration::from_secs(30));
+
+    let mut app = app(state(), timeout);
+
+    // Past the timeout, which a profile runs longer than.
+    if env::var("APP_PROFILE")
+        .map(|value| value != "0")
+        .unwrap_or(true)
+    {
+        app = app.route("/debug/pprof/profile", get(handle_profile));
+    }
+
+    let app = app
+        .layer(TraceLayer::new_for_http())
+        .layer(LiveReloadLayer::new());
+
+    let addr = format!("0.0.0.0:{port}");
+    let listener = tokio::net::TcpListener::bind(&addr).await.unwrap();
+    tracing::info!("listening on {addr}");
+
+    let stop = Arc::new(Notify::new());
+    let server = axum::serve(listener, app).with_graceful_shutdown({
+        let stop = stop.clone();
+        async move { stop.notified().await }
+    });
+    tokio::select! {
+        result = server.into_future() => result.unwrap(),
+        _ = async {
+            shutdown_signal().await;
+            stop.notify_one();
+            tokio::time::sleep(DRAIN_TIMEOUT).await;
+        } => tracing::warn!("connections still open after {DRAIN_TIMEOUT:?}; stopping anyway"),
+    }
+}
+
+fn state() -> Arc<AppState> {
+    let mut env = Environment::new();
+    env.set_loader(minijinja::path_loader("templates"));
+    Arc::new(AppState { env })
+}
+
+/// The routes, each answered within `timeout`.
+fn app(state: Arc<AppState>, timeout: Duration) -> Router {
+    let app = Router::new()
+        .route("/", get(handle_home))
+        .route("/api/greet", get(handle_greet))
+        .route("/api/hello", post(api::handle_hello))
+        .route("/healthz", get(handle_healthz))
+        .nest_service("/static", ServeDir::new("static"))
+        .with_state(state);
+    with_timeout(app, timeout)
+}
+
+/// Answers a request still at work after `timeout` with a 503, dropping
+/// the handler's future.
+fn with_timeout(router: Router, timeout: Duration) -> Router {
+    router.layer(
+        ServiceBuilder::new()
+            .layer(HandleErrorLayer::new(|_: BoxError| async {
+                StatusCode::SERVICE_UNAVAILABLE
+            }))
+            .timeout(timeout),
+    )
+}
+
+/// Resolves on Ctrl-C or, on Unix, SIGTERM, as sent by `docker stop`
+/// and Kubernetes.
+async fn shutdown_signal() {
+    let ctrl_c = async {
+        tokio::signal::ctrl_c()
+            .await
+            .expect("installing the Ctrl-C handler");
+    };
+    #[cfg(unix)]
+    let terminate = async {
+        tokio::signal::unix::signal(tokio::signal::unix::SignalKind::terminate())
+            .expect("installing the SIGTERM handler")
+            .recv()
+            .await;
+    };
+    #[cfg(not(unix))]
+    let terminate = std::future::pending::<()>();
+
+    tokio::select! {
+        _ = ctrl_c => {},
+        _ = terminate => {},
+    }
+    tracing::info!("shutting down");
+}
+
+async fn handle_home(State(state): State<Arc<AppState>>) -> Html<String> {
+    let tmpl = state.env.get_template("index.html").unwrap();
+    Html(tmpl.render(context! { title => "Home" }).unwrap())
+}
+
+async fn handle_greet(
+    HxRequest(is_htmx): HxRequest,
+    State(state): State<Arc<AppState>>,
+) -> Html<String> {
+    if is_htmx {
+        Html("<p>Hello from the server!</p>".into())
+    } else {
+        let tmpl = state.env.get_template("index.html").unwrap();
+        Html(tmpl.render(context! { title => "Greeting" }).unwrap())
+    }
+}
+
+async fn handle_healthz() -> Json<Value> {
+    Json(json!({ "status": "ok" }))
+}
+
+async fn handle_profile(
+    Query(query): Query<ProfileQuery>,
+) -> Result<Response, (StatusCode, String)> {
+    let seconds = query.seconds.unwrap_or(5).clamp(1, 30);
+    let svg = tokio::task::spawn_blocking(move || -> Result<Vec<u8>, String> {
+        let guard = ProfilerGuard::new(100).map_err(|err| err.to_string())?;
+        std::thread::sleep(Duration::from_secs(seconds));
+        let report = guard.report().build().map_err(|err| err.to_string())?;
+        let mut svg = Vec::new();
+        report
+            .flamegraph(&mut svg)
+            .map_err(|err| err.to_string())?;
+        Ok(svg)
+    })
+    .await
+    .map_err(|err| (StatusCode::INTERNAL_SERVER_ERROR, err.to_string()))?
+    .map_err(|err| (StatusCode::INTERNAL_SERVER_ERROR, err))?;
+
+    Ok(([(header::CONTENT_TYPE, "image/svg+xml")], svg).into_response())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use axum::body::Body;
+    use axum::http::Request;
+    use http_body_util::BodyExt;
+    use tower::ServiceExt;
+
+    fn test_app() -> Router {
+        app(state(), Duration::from_secs(30))
+    }
+
+    async fn send(app: Router, req: Request<Body>) -> (StatusCode, String) {
+        let resp = app.oneshot(req).await.unwrap();
+        let status = resp.status();
+        let body = resp.into_body().collect().await.unwrap().to_bytes();
+        (status, String::from_utf8(body.to_vec()).unwrap())
+    }
+
+    fn post_json(uri: &str, body: &str) -> Request<Body> {
+        Request::post(uri)
+            .header(header::CONTENT_TYPE, "application/json")
+            .body(Body::from(body.to_owned()))
+            .unwrap()
+    }
+
+    #[tokio::test]
+    async fn home_returns_html() {
+        let (status, body) = send(test_app(), Request::get("/").body(Body::empty()).unwrap()).await;
+        assert_eq!(status, StatusCode::OK);
+        assert!(body.contains("Hello, World!"), "got {body}");
+    }
+
+    #[tokio::test]
+    async fn greet_htmx_returns_fragment() {
+        let req = Request::get("/api/greet")
+            .header("HX-Request", "true")
+            .body(Body::empty())
+            .unwrap();
+        let (status, body) = send(test_app(), req).await;
+        assert_eq!(status, StatusCode::OK);
+        assert_eq!(body, "<p>Hello from the server!</p>");
+    }
+
+    #[tokio::test]
+    async fn greet_without_htmx_returns_the_page() {
+        let req = Request::get("/api/greet").body(Body::empty()).unwrap();
+        let (status, body) = send(test_app(), req).await;
+        assert_eq!(status, StatusCode::OK);
+        assert!(body.contains("<html"), "got {body}");
+    }
+
+    #[tokio::test]
+    async fn healthz_is_ok() {
+        let req = Request::get("/healthz").body(Body::empty()).unwrap();
+        let (status, body) = send(test_app(), req).await;
+        assert_eq!(status, StatusCode::OK);
+        assert_eq!(body, r#"{"status":"ok"}"#);
+    }
+
+    #[tokio::test]
+    async fn hello_answers_json() {
+        let (status, body) = send(test_app(), post_json("/api/hello", r#"{"name":"Ada"}"#)).await;
+        assert_eq!(status, StatusCode::OK);
+        let hello: api::Hello = serde_json::from_str(&body).unwrap();
+        assert_eq!(hello.message, "Hello, Ada!");
+    }
+
+    #[tokio::test]
+    async fn hello_refuses_bad_requests() {
+        let tests = [
+            (r#"{"name":" "}"#, StatusCode::UNPROCESSABLE_ENTITY),
+            (r#"{"nom":"Ada"}"#, StatusCode::UNPROCESSABLE_ENTITY),
+            (r#"{"name":"#, StatusCode::BAD_REQUEST),
+        ];
+        for (body, want) in tests {
+            let (status, _) = send(test_app(), post_json("/api/hello", body)).await;
+            assert_eq!(status, want, "for {body}");
+        }
+
+        let (_, body) = send(test_app(), post_json("/api/hello", r#"{"name":""}"#)).await;
+        let err: api::ApiError = serde_json::from_str(&body).unwrap();
+        assert_eq!(err.error, "name is required");
+    }
+
+    #[tokio::test]
+    async fn slow_handlers_time_out() {
+        let slow = Router::new().route(
+            "/slow",
+            get(|| async {
+                tokio::time::sleep(Duration::from_secs(5)).await;
+                "finished"
+            }),
+        );
+        let req = Request::get("/slow").body(Body::empty()).unwrap();
+        let (status, body) = send(with_timeout(slow, Duration::from_millis(10)), req).await;
+        assert_eq!(status, StatusCode::SERVICE_UNAVAILABLE);
+        assert!(body.is_empty(), "got {body}");
+    }
+}
@@ -0,0 +1,25 @@
+import { loadConfig } from "./config.js";
+import { app, shutdown } from "./server.js";
+
+const config = loadConfig();
+const server = app(config);
+
+server.listen(config.port, () => {
+	console.log(`listening on http://localhost:${config.port}`);
+});
+
+// On Ctrl-C or SIGTERM (docker stop, a deploy), finish the requests
+// under way, then exit; a second signal exits at once.
+for (const signal of ["SIGINT", "SIGTERM"] as const) {
+	process.once(signal, () => {
+		console.log(`${signal}: shutting down`);
+		process.once(signal, () => process.exit(1));
+		shutdown(server, config.drainTimeout).then(
+			() => process.exit(0),
+			(err: unknown) => {
+				console.error(err);
+				process.exit(1);
+			},
+		);
+	});
+}
@@ -0,0 +1,94 @@
+import { createServer, type IncomingMessage, type Server, type ServerResponse } from "node:http";
+
+import type { Config } from "./config.js";
+
+// The largest request body the API reads, in bytes.
+const MAX_BODY = 64 * 1024;
+
+class HttpError extends Error {
+	constructor(
+		readonly status: number,
+		message: string,
+	) {
+		super(message);
+	}
+}
+
+// app builds the server without starting it, so the tests can listen
+// on a port of their own.
+export function app(config: Config): Server {
+	return createServer((req, res) => {
+		route(config, req, res).catch((err: unknown) => {
+			if (err instanceof HttpError) {
+				json(res, err.status, { error: err.message });
+				return;
+			}
+			console.error(err);
+			json(res, 500, { error: "internal server error" });
+		});
+	});
+}
+
+async function route(config: Config, req: IncomingMessage, res: ServerResponse): Promise<void> {
+	const { pathname } = new URL(req.url ?? "/", "http://localhost");
+	switch (`${req.method} ${pathname}`) {
+		case "GET /":
+			res.writeHead(200, { "Content-Type": "text/plain; charset=utf-8" });
+			res.end(`${config.greeting} from golden-app!\n`);
+			return;
+		case "GET /healthz":
+			json(res, 200, { status: "ok" });
+			return;
+		case "POST /api/hello": {
+			const body = await readJSON(req);
+			const name =
+				typeof body === "object" && body !== null && "name" in body ? body.name : undefined;
+			if (typeof name !== "string" || name.trim() === "") {
+				throw new HttpError(422, "name is required");
+			}
+			json(res, 200, { message: `${config.greeting}, ${name.trim()}!` });
+			return;
+		}
+		default:
+			throw new HttpError(404, `no route for ${req.method} ${pathname}`);
+	}
+}
+
+async function readJSON(req: IncomingMessage): Promise<unknown> {
+	let size = 0;
+	const chunks: Buffer[] = [];
+	for await (const chunk of req) {
+		size += (chunk as Buffer).length;
+		if (size > MAX_BODY) {
+			throw new HttpError(413, "request body too large");
+		}
+		chunks.push(chunk as Buffer);
+	}
+	try {
+		return JSON.parse(Buffer.concat(chunks).toString("utf8"));
+	} catch {
+		throw new HttpError(400, "request body is not JSON");
+	}
+}
+
+function json(res: ServerResponse, status: number, body: unknown): void {
+	res.writeHead(status, { "Content-Type": "application/json" });
+	res.end(JSON.stringify(body));
+}
+
+// shutdown stops taking connections and waits for the open ones to
+// finish, closing whatever is still open after timeout ms.
+export function shutdown(server: Server, timeout: number): Promise<void> {
+	return new Promise((resolve, reject) => {
+		const timer = setTimeout(() => server.closeAllConnections(), timeout);
+		server.close((err) => {
+			clearTimeout(timer);
+			if (err) {
+				reject(err);
+			} else {
+				resolve();
+			}
+		});
+		server.closeIdleConnections();
+	});
+}
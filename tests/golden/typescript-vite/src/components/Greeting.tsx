@@ -0,0 +1,20 @@
+import { useState } from "react";
+
+type Props = {
+	greeting?: string;
+};
+
+// Greeting greets whoever is typed in, or the world.
+export function Greeting({ greeting = "Hello" }: Props) {
+	const [name, setName] = useState("");
+	return (
+		<section>
+			<label>
+				Name <input value={name} onChange={(e) => setName(e.target.value)} />
+			</label>
+			<p role="status">
+				{greeting}, {name.trim() || "World"}!
+			</p>
+		</section>
+	);
+}
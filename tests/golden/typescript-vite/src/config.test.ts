@@ -0,0 +1,17 @@
+import { describe, expect, it } from "vitest";
+
+import { readConfig } from "./config";
+
+describe("readConfig", () => {
+	it("falls back to the defaults", () => {
+		expect(readConfig({} as ImportMetaEnv)).toEqual({ title: "golden-app", apiBase: "/api" });
+	});
+
+	it("reads the VITE_ variables", () => {
+		const env = { VITE_APP_TITLE: "Demo", VITE_API_BASE: "https://api.example.com/" };
+		expect(readConfig(env as ImportMetaEnv)).toEqual({
+			title: "Demo",
+			apiBase: "https://api.example.com",
+		});
+	});
+});
@@ -0,0 +1,16 @@
+export type Config = {
+	title: string;
+	// Where the API is; /api is proxied to the backend in development.
+	apiBase: string;
+};
+
+// readConfig reads the VITE_* variables, which Vite fills in from the
+// environment and .env files when it builds the bundle.
+export function readConfig(env: ImportMetaEnv): Config {
+	return {
+		title: env.VITE_APP_TITLE || "golden-app",
+		apiBase: (env.VITE_API_BASE || "/api").replace(/\/+$/, ""),
+	};
+}
+
+export const config = readConfig(import.meta.env);
@@ -0,0 +1,14 @@
+import { StrictMode } from "react";
+import { createRoot } from "react-dom/client";
+import { RouterProvider, createBrowserRouter } from "react-router";
+
+import { routes } from "./routes";
+import "./styles.css";
+
+const router = createBrowserRouter(routes);
+
+createRoot(document.getElementById("app")!).render(
+	<StrictMode>
+		<RouterProvider router={router} />
+	</StrictMode>,
+);
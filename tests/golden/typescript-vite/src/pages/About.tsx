@@ -0,0 +1,12 @@
+import { config } from "../config";
+
+export function About() {
+	return (
+		<>
+			<h1>About</h1>
+			<p>
+				{config.title} is built with Vite and React; its API is at <code>{config.apiBase}</code>.
+			</p>
+		</>
+	);
+}
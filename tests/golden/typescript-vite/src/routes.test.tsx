@@ -0,0 +1,29 @@
+import { render, screen } from "@testing-library/react";
+import userEvent from "@testing-library/user-event";
+import { RouterProvider, createMemoryRouter } from "react-router";
+import { describe, expect, it } from "vitest";
+
+import { routes } from "./routes";
+
+function renderAt(path: string) {
+	render(<RouterProvider router={createMemoryRouter(routes, { initialEntries: [path] })} />);
+}
+
+describe("routes", () => {
+	it("renders the home page", () => {
+		renderAt("/");
+		expect(screen.getByRole("heading", { level: 1 })).toHaveTextContent("Hello, World!");
+	});
+
+	it("follows the navigation", async () => {
+		renderAt("/");
+		await userEvent.click(screen.getByRole("link", { name: "About" }));
+		expect(screen.getByRole("heading", { level: 1 })).toHaveTextContent("About");
+	});
+
+	it("renders the not-found page for an unknown path", () => {
+		renderAt("/no/such/page");
+		expect(screen.getByRole("heading", { level: 1 })).toHaveTextContent("Not found");
+		expect(screen.getByText("/no/such/page")).toBeInTheDocument();
+	});
+});
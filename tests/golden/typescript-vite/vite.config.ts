@@ -0,0 +1,23 @@
+import react from "@vitejs/plugin-react";
+import { loadEnv } from "vite";
+import { defineConfig } from "vitest/config";
+
+export default defineConfig(({ mode }) => {
+	// Where the dev server sends /api: API_PROXY from the environment or
+	// .env, else the backend given to jolo new (none when empty).
+	const env = loadEnv(mode, process.cwd(), "");
+	const target = env.API_PROXY || "";
+	return {
+		plugins: [react()],
+		server: {
+			port: Number(env.PORT) || 5173,
+			proxy: target ? { "/api": { target, changeOrigin: true } } : undefined,
+		},
+		test: {
+			environment: "jsdom",
+			// So Testing Library can unmount after each test.
+			globals: true,
+			setupFiles: ["src/setup-tests.ts"],
+		},
+	};
+});
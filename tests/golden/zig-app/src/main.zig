@@ -0,0 +1,19 @@
+const std = @import("std");
+const greet = @import("greet");
+
+pub fn main() !void {
+    var gpa = std.heap.GeneralPurposeAllocator(.{}){};
+    defer _ = gpa.deinit();
+    const allocator = gpa.allocator();
+
+    const args = try std.process.argsAlloc(allocator);
+    defer std.process.argsFree(allocator, args);
+
+    const name = if (args.len > 1) args[1] else "";
+    try greet.greet(std.io.getStdOut().writer(), name);
+}
+
+test {
+    // Compiles main and brings in the tests of what it refers to.
+    std.testing.refAllDecls(@This());
+}
@@ -0,0 +1,175 @@
+#!/usr/bin/env python3
+"""Tests for jolo add generators."""
+
+import io
+import os
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import add, scaffold, verify
+
+
+class TestAdd(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        root = Path(self.tmpdir.name)
+        self.templates = root / "templates"
+        path = self.templates / "svc"
+        (path / "generators" / "route").mkdir(parents=True)
+        (path / scaffold.MANIFEST_FILE).write_text("")
+        (path / "main.txt").write_text("routes:\n")
+        gen = path / "generators" / "route"
+        (gen / scaffold.GENERATOR_FILE).write_text(
+            'description = "A route"\n'
+            '[variables.path]\ntype = "string"\n'
+            'default = "/{{NAME | kebab}}"\n'
+            '[[patches]]\nfile = "main.txt"\nafter = "routes:"\n'
+            'insert = "  {{path}} -> {{NAME | snake}}"\n'
+        )
+        (gen / "{{NAME | snake}}.txt").write_text("{{PROJECT_NAME}}\n")
+
+        self.project = root / "shop"
+        self.project.mkdir()
+        template = scaffold.load_template(path, "svc")
+        files = scaffold.render_project(
+            template, [], scaffold.project_variables("shop")
+        )
+        scaffold.write_files(files, self.project)
+        scaffold.write_lock(
+            self.project,
+            scaffold.lock_data(template, [], "shop", {}, self.templates),
+        )
+        cwd = os.getcwd()
+        os.chdir(self.project)
+        self.addCleanup(os.chdir, cwd)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _add(self, *argv):
+        args = jolo.parse_args(["add", *argv, "--no-input"])
+        out = io.StringIO()
+        with mock.patch("sys.stdout", out):
+            add.run_add_mode(args)
+        return out.getvalue()
+
+    def test_generators_are_not_rendered_with_the_template(self):
+        self.assertEqual(
+            sorted(p.name for p in self.project.iterdir()),
+            ["README.md", "jolo.lock", "main.txt"],
+        )
+
+    def test_lists_generators(self):
+        self.assertEqual(self._add(), "route  A route\n")
+
+    def test_creates_files_and_patches(self):
+        out = self._add("route", "userList")
+        self.assertIn("Patched: main.txt", out)
+        self.assertEqual(
+            (self.project / "user_list.txt").read_text(), "shop\n"
+        )
+        self.assertEqual(
+            (self.project / "main.txt").read_text(),
+            "routes:\n  /user-list -> user_list\n",
+        )
+
+    def test_var_overrides_rendered_default(self):
+        self._add("route", "users", "--var", "path=/api/u")
+        main = (self.project / "main.txt").read_text()
+        self.assertIn("/api/u -> users", main)
+
+    def test_existing_files_need_force_and_patches_apply_once(self):
+        self._add("route", "users")
+        with self.assertRaises(SystemExit) as cm:
+            self._add("route", "users")
+        self.assertIn("Already exists: users.txt", str(cm.exception))
+        self._add("route", "users", "--force")
+        main = (self.project / "main.txt").read_text()
+        self.assertEqual(main.count("-> users"), 1)
+
+    def test_force_keeps_protected_regions(self):
+        gen = self.templates / "svc" / "generators" / "route"
+        (gen / "{{NAME | snake}}.txt").write_text(
+            "{{PROJECT_NAME}}\n// jolo:keep-start notes\n"
+            "// jolo:keep-end\n"
+        )
+        self._add("route", "users")
+        users = self.project / "users.txt"
+        users.write_text(
+            "edited\n// jolo:keep-start notes\nmine\n// jolo:keep-end\n"
+        )
+        self._add("route", "users", "--force")
+        self.assertEqual(
+            users.read_text(),
+            "shop\n// jolo:keep-start notes\nmine\n// jolo:keep-end\n",
+        )
+
+    def test_dry_run_writes_nothing(self):
+        out = self._add("route", "users", "--dry-run")
+        self.assertEqual(out, "create  users.txt\npatch  main.txt\n")
+        self.assertFalse((self.project / "users.txt").exists())
+
+    def test_unknown_generator(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._add("nope", "x")
+        self.assertIn("have: route", str(cm.exception))
+
+
+class TestBuiltinGenerators(unittest.TestCase):
+    def test_go_web_generators_render_cleanly(self):
+        template = scaffold.find_template("go/web")
+        variables = scaffold.project_variables("shop")
+        variables.update(scaffold.config_variables({}, "shop"))
+        files = dict(scaffold.render_project(template, [], variables))
+        found = add.generators([template])
+        self.assertEqual(sorted(found), ["component", "handler"])
+
+        with tempfile.TemporaryDirectory() as tmp:
+            project = Path(tmp)
+            scaffold.write_files(list(files.items()), project)
+            for name, generator in found.items():
+                gen_vars = {**variables, "NAME": "userList"}
+                generator = add._with_rendered_defaults(generator, gen_vars)
+                gen_vars.update(
+                    scaffold.resolve_variables(generator, {}, no_input=True)
+                )
+                new, patched = add.render_generator(
+                    generator, project, gen_vars
+                )
+                self.assertEqual(
+                    verify.find_leaks([*new, *patched.items()]), [], name
+                )
+        self.assertIn(
+            'mux.HandleFunc("GET /user-list", handleUserList)',
+            patched.get("main.go", ""),
+        )
+        self.assertIn(
+            '{"/user-list", http.StatusOK, "user-list.html"},',
+            patched.get("integration_test.go", ""),
+        )
+
+    def test_go_api_handler_follows_the_cmd_layout(self):
+        template = scaffold.find_template("go/api")
+        variables = scaffold.project_variables("shop")
+        variables.update(scaffold.config_variables({}, "shop"))
+        variables["GO_CMDS"] = "shop"
+        files = scaffold.render_project(template, [], variables)
+        generator = add.generators([template])["handler"]
+        with tempfile.TemporaryDirectory() as tmp:
+            project = Path(tmp)
+            scaffold.write_files(files, project)
+            gen_vars = {**variables, "NAME": "users"}
+            generator = add._with_rendered_defaults(generator, gen_vars)
+            gen_vars.update(
+                scaffold.resolve_variables(generator, {}, no_input=True)
+            )
+            new, patched = add.render_generator(generator, project, gen_vars)
+        self.assertIn("cmd/shop/handler_users.go", dict(new))
+        self.assertEqual(list(patched), ["cmd/shop/main.go"])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,152 @@
+#!/usr/bin/env python3
+"""Tests for template aliases and favorites."""
+
+import io
+import os
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import aliases, catalog, completion, scaffold
+
+CONFIG = {
+    "aliases": {
+        "web": "go/web --with tailwind,docker --license mit --var a=1",
+        "api": "go shop --no-install",
+    },
+    "favorites": ["web", "python"],
+    "templates": ["go", "python"],
+}
+
+
+class TestExpand(unittest.TestCase):
+    def _expand(self, *argv, cfg=CONFIG):
+        args = jolo.parse_args(["new", *argv])
+        aliases.expand(args, cfg)
+        return args
+
+    def test_fills_in_template_and_flags(self):
+        args = self._expand("web", "shop")
+        self.assertEqual(
+            (args.template, args.name, args.with_, args.license, args.var),
+            ("go/web", "shop", ["tailwind,docker"], "mit", ["a=1"]),
+        )
+
+    def test_command_line_wins_and_lists_add_up(self):
+        args = self._expand(
+            "web",
+            "--license",
+            "apache",
+            "--with",
+            "sqlite",
+            "--var",
+            "a=2",
+        )
+        self.assertEqual(args.license, "apache")
+        self.assertEqual(args.with_, ["tailwind,docker", "sqlite"])
+        self.assertEqual(scaffold.parse_var_args(args.var), {"a": "2"})
+
+    def test_alias_may_name_the_project(self):
+        self.assertEqual(self._expand("api").name, "shop")
+        self.assertEqual(self._expand("api", "other").name, "other")
+        self.assertTrue(self._expand("api").no_install)
+
+    def test_templates_are_left_alone(self):
+        args = self._expand("go/web", "shop")
+        self.assertEqual((args.template, args.with_), ("go/web", []))
+
+    def test_show_takes_the_flags_it_has(self):
+        args = jolo.parse_args(["show", "web"])
+        aliases.expand(args, CONFIG)
+        self.assertEqual(args.template, "go/web")
+        self.assertEqual(args.with_, ["tailwind,docker"])
+        self.assertFalse(hasattr(args, "license"))
+
+    def test_bad_aliases_exit(self):
+        for line, message in (
+            ("--with docker", "'--with docker' starts with no template"),
+            ("go --bogus", "unknown --bogus"),
+            ('go "open', "No closing quotation"),
+        ):
+            with self.assertRaises(SystemExit, msg=line) as cm:
+                self._expand("x", cfg={"aliases": {"x": line}})
+            self.assertEqual(
+                cm.exception.code, f"Error: alias x: {message}", msg=line
+            )
+        with self.assertRaises(SystemExit):
+            aliases.aliases({"aliases": {"x": ["go"]}})
+
+    def test_preferred_puts_favorites_first(self):
+        self.assertEqual(aliases.preferred(CONFIG), ["web", "python", "go"])
+
+
+class TestPickerAndListing(unittest.TestCase):
+    def test_picker_offers_aliases(self):
+        choices = scaffold._template_choices(
+            aliases.preferred(CONFIG),
+            [scaffold.TEMPLATES_ROOT],
+            CONFIG["aliases"],
+        )
+        names = [n for n, _ in choices]
+        self.assertEqual(names[:3], ["web", "python", "go"])
+        self.assertIn(("api", "= go shop --no-install"), choices)
+
+    def test_picked_alias_is_expanded(self):
+        args = jolo.parse_args(["new", "--templates-dir", "."])
+        with (
+            mock.patch.object(scaffold, "prompt_choice", return_value="web"),
+            mock.patch("sys.stdin.isatty", return_value=False),
+        ):
+            template, _ = scaffold._resolve_template(args, None, CONFIG)
+        self.assertEqual(template.name, "go/web")
+        self.assertEqual(args.with_, ["tailwind,docker"])
+
+    def test_listing_has_aliases_and_favorites_first(self):
+        entries = catalog.catalog([scaffold.TEMPLATES_ROOT], [])
+        entries = catalog.alias_entries(entries, CONFIG["aliases"])
+        entries = catalog.favorites_first(entries, CONFIG["favorites"])
+        self.assertEqual([e.name for e in entries[:2]], ["web", "python"])
+        web = entries[0]
+        self.assertEqual((web.language, web.source), ("go", "alias"))
+        self.assertEqual(entries[-1].name, "api")
+
+    def test_completion_offers_aliases(self):
+        with mock.patch.object(
+            completion, "load_config", return_value={"new": CONFIG}
+        ):
+            self.assertIn("web", completion.complete(["new", "w"]))
+            self.assertIn(
+                "sqlite", completion.complete(["new", "web", "--with", ""])
+            )
+
+
+class TestNew(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def test_new_with_an_alias(self):
+        args = jolo.parse_args(["new", "web", "shop", "--git=false"])
+        cfg = {"new": {**CONFIG, "install": False}}
+        with (
+            mock.patch.object(scaffold, "load_config", return_value=cfg),
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+        lock = scaffold.read_lock(Path("shop"))
+        self.assertEqual(lock["template"], "go/web")
+        self.assertEqual(lock["addons"], ["tailwind", "docker"])
+        self.assertTrue(Path("shop/LICENSE").read_text().startswith("MIT"))
+
+
+if __name__ == "__main__":
+    unittest.main()
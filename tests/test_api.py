@@ -0,0 +1,116 @@
+#!/usr/bin/env python3
+"""Tests for the programmatic scaffolding API."""
+
+import tempfile
+import unittest
+from pathlib import Path
+
+from _jolo import api, scaffold
+
+
+class TestApi(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+        self.templates = self.root / "templates"
+        path = self.templates / "svc"
+        path.mkdir(parents=True)
+        (path / scaffold.MANIFEST_FILE).write_text(
+            'description = "A service"\ntags = ["web"]\n'
+            '[variables.port]\ntype = "int"\ndefault = 8000\n'
+        )
+        (path / "app.txt").write_text("{{PROJECT_NAME}}:{{port}}\n")
+        (path / "run.sh").write_text("#!/bin/sh\n")
+        (path / "run.sh").chmod(0o755)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _open(self, name="svc"):
+        return api.open_template(name, [str(self.templates)])
+
+    def test_list_templates_includes_dirs_and_builtins(self):
+        names = [t.name for t in api.list_templates([str(self.templates)])]
+        self.assertIn("svc", names)
+        self.assertIn("go/web", names)
+
+    def test_describe(self):
+        with self._open() as template:
+            info = api.describe(template)
+        self.assertEqual(info["description"], "A service")
+        self.assertEqual(info["tags"], ["web"])
+        self.assertEqual(info["variables"]["port"]["default"], 8000)
+
+    def test_render_applies_answers_and_defaults(self):
+        with self._open() as template:
+            self.assertEqual(
+                api.render(template, "shop")["app.txt"], "shop:8000\n"
+            )
+            files = api.render(template, "shop", {"port": 9000})
+        self.assertEqual(files["app.txt"], "shop:9000\n")
+
+    def test_generate_writes_files_modes_and_lock(self):
+        dest = self.root / "out" / "shop"
+        with self._open() as template:
+            api.generate(template, dest, {"port": "9000"})
+        self.assertEqual((dest / "app.txt").read_text(), "shop:9000\n")
+        self.assertTrue((dest / "run.sh").stat().st_mode & 0o100)
+        lock = scaffold.read_lock(dest)
+        self.assertEqual(lock["template"], "svc")
+        self.assertEqual(lock["variables"]["port"], "9000")
+        self.assertEqual(lock["templates_dir"], str(self.templates))
+
+    def test_generate_locks_the_dir_a_template_came_from(self):
+        nested = self.templates / "team" / "go" / "svc"
+        nested.mkdir(parents=True)
+        (nested / scaffold.MANIFEST_FILE).write_text("")
+        with self._open("team/go/svc") as template:
+            api.generate(template, self.root / "a")
+        lock = scaffold.read_lock(self.root / "a")
+        self.assertEqual(lock["templates_dir"], str(self.templates))
+        with api.open_template(nested) as template:
+            api.generate(template, self.root / "b")
+        lock = scaffold.read_lock(self.root / "b")
+        self.assertEqual(lock["template"], "svc")
+        self.assertEqual(lock["templates_dir"], str(nested.parent))
+
+    def test_generate_refuses_non_empty_dest(self):
+        dest = self.root / "shop"
+        dest.mkdir()
+        (dest / "keep").write_text("")
+        with self._open() as template:
+            with self.assertRaises(api.ScaffoldError):
+                api.generate(template, dest)
+            api.generate(template, dest, overwrite=True)
+        self.assertTrue((dest / "keep").exists())
+        self.assertTrue((dest / "app.txt").exists())
+
+    def test_open_template_from_path(self):
+        with api.open_template(self.templates / "svc") as template:
+            self.assertEqual(template.name, "svc")
+
+    def test_errors_raise_instead_of_exiting(self):
+        with self.assertRaisesRegex(api.ScaffoldError, "^Unknown template"):
+            with self._open("nope"):
+                pass
+        with self._open() as template:
+            with self.assertRaisesRegex(api.ScaffoldError, "port"):
+                api.render(template, "shop", {"port": "many"})
+            with self.assertRaises(api.ScaffoldError):
+                api.render(template, "shop", addons=["nope"])
+
+    def test_failing_hook_removes_new_dest(self):
+        (self.templates / "svc" / scaffold.MANIFEST_FILE).write_text(
+            '[hooks]\npost = ["false"]\n'
+        )
+        dest = self.root / "shop"
+        with self._open() as template:
+            api.generate(template, dest)
+            with self.assertRaisesRegex(api.ScaffoldError, "hook failed"):
+                api.generate(template, self.root / "other", hooks=True)
+        self.assertTrue(dest.is_dir())
+        self.assertFalse((self.root / "other").exists())
+
+
+if __name__ == "__main__":
+    unittest.main()
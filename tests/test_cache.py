@@ -0,0 +1,81 @@
+#!/usr/bin/env python3
+"""Tests for jolo cache."""
+
+import io
+import json
+import os
+import tempfile
+import unittest
+from unittest import mock
+
+import jolo
+from _jolo import cache, remote
+
+
+class TestCacheMode(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        env = mock.patch.dict(
+            os.environ, {"XDG_CACHE_HOME": self.tmpdir.name}
+        )
+        env.start()
+        self.addCleanup(env.stop)
+        for url, ref in (
+            ("https://example.com/a.git", ""),
+            ("https://example.com/b.git", "v1.0.0"),
+        ):
+            entry = remote._entry_path(url, ref or None)
+            (entry / "repo").mkdir(parents=True)
+            (entry / "repo" / "f").write_text("x" * 10)
+            (entry / "entry.json").write_text(
+                json.dumps(
+                    {
+                        "url": url,
+                        "ref": ref,
+                        "commit": "c0ffee" * 7,
+                        "fetched": "2026-01-02T03:04:05",
+                    }
+                )
+            )
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _run(self, *argv):
+        args = jolo.parse_args(["cache", *argv])
+        out = io.StringIO()
+        with mock.patch("sys.stdout", out):
+            cache.run_cache_mode(args)
+        return out.getvalue().splitlines()
+
+    def test_list(self):
+        lines = self._run()
+        self.assertEqual(len(lines), 2)
+        self.assertTrue(
+            lines[0].startswith("https://example.com/a.git  HEAD")
+        )
+        self.assertIn("v1.0.0", lines[1])
+        self.assertIn("2026-01-02 03:04:05", lines[1])
+
+    def test_clean_matching(self):
+        [line] = self._run("clean", "b.git")
+        self.assertTrue(line.startswith("Removed 1 entries ("), line)
+        self.assertEqual(
+            [e.url for e in remote.cache_entries()],
+            ["https://example.com/a.git"],
+        )
+        self._run("clean")
+        self.assertEqual(remote.cache_entries(), [])
+        self.assertEqual(len(self._run()), 1)
+
+    def test_offline_pin_uses_cached_tags(self):
+        with mock.patch.object(remote.constants, "OFFLINE", True):
+            self.assertEqual(
+                remote.pin("example.com/b@^1"), "example.com/b@v1.0.0"
+            )
+            with self.assertRaises(SystemExit):
+                remote.pin("example.com/b@2")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,139 @@
+#!/usr/bin/env python3
+"""Tests for jolo capture."""
+
+import io
+import tempfile
+import tomllib
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import capture, scaffold
+
+
+class TestReplacements(unittest.TestCase):
+    def test_spellings_longest_first(self):
+        self.assertEqual(
+            capture.replacements("billing-api", "github.com/ada/billing-api"),
+            [
+                ("github.com/ada/billing-api", "{{MODULE_PATH}}"),
+                ("billing-api", "{{PROJECT_NAME}}"),
+                ("billing_api", "{{PROJECT_NAME_UNDERSCORE}}"),
+                ("BILLING_API", "{{PROJECT_NAME | snake | upper}}"),
+                ("Billing Api", "{{PROJECT_NAME | title}}"),
+                ("BillingApi", "{{PROJECT_NAME | pascal}}"),
+                ("billingApi", "{{PROJECT_NAME | camel}}"),
+            ],
+        )
+
+    def test_coinciding_spellings_listed_once(self):
+        literals = [lit for lit, _ in capture.replacements("shop")]
+        self.assertEqual(literals, ["shop", "Shop", "SHOP"])
+
+    def test_substitute_whole_words(self):
+        pairs = capture.replacements("shop")
+        self.assertEqual(
+            capture.substitute(
+                "shop shopping ShopServer workshop SHOP_URL", pairs
+            ),
+            "{{PROJECT_NAME}} shopping {{PROJECT_NAME | pascal}}Server "
+            "workshop {{PROJECT_NAME | snake | upper}}_URL",
+        )
+
+    def test_substitute_does_not_recurse(self):
+        pairs = [("a.io/app", "{{MODULE_PATH}}"), ("app", "{{PROJECT_NAME}}")]
+        self.assertEqual(
+            capture.substitute("import a.io/app/x // app", pairs),
+            "import {{MODULE_PATH}}/x // {{PROJECT_NAME}}",
+        )
+
+
+class TestCaptureMode(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+        self.src = self.root / "billing-api"
+        (self.src / "cmd" / "billing-api").mkdir(parents=True)
+        (self.src / "go.mod").write_text(
+            "module github.com/ada/billing-api\n"
+        )
+        (self.src / "cmd" / "billing-api" / "main.go").write_text(
+            'import "github.com/ada/billing-api/internal"\n'
+            "type BillingApiServer struct{}\n"
+        )
+        (self.src / "run.sh").write_text("#!/bin/sh\necho billing-api\n")
+        (self.src / "run.sh").chmod(0o755)
+        (self.src / "logo.png").write_bytes(b"\x89PNG\0billing-api")
+        (self.src / scaffold.LOCK_FILE).write_text("")
+        self.templates = self.root / "templates"
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _capture(self, *extra, stdin=""):
+        args = jolo.parse_args(
+            [
+                "capture",
+                str(self.src),
+                "team/svc",
+                "--templates-dir",
+                str(self.templates),
+                *extra,
+            ]
+        )
+        with (
+            mock.patch("sys.stdout", io.StringIO()),
+            mock.patch("sys.stdin", io.StringIO(stdin)),
+        ):
+            capture.run_capture_mode(args)
+        return self.templates / "team" / "svc"
+
+    def test_captures_with_placeholders(self):
+        dest = self._capture("--no-input", "--description", "Billing")
+        main = dest / "cmd" / "{{PROJECT_NAME}}" / "main.go"
+        self.assertEqual(
+            main.read_text(),
+            'import "{{MODULE_PATH}}/internal"\n'
+            "type {{PROJECT_NAME | pascal}}Server struct{}\n",
+        )
+        self.assertEqual(
+            (dest / "logo.png").read_bytes(), b"\x89PNG\0billing-api"
+        )
+        self.assertTrue((dest / "run.sh").stat().st_mode & 0o100)
+        self.assertFalse((dest / scaffold.LOCK_FILE).exists())
+        with open(dest / scaffold.MANIFEST_FILE, "rb") as f:
+            manifest = tomllib.load(f)
+        self.assertEqual(manifest["description"], "Billing")
+        self.assertEqual(manifest["verify"]["run"][-1], "go test ./...")
+
+    def test_renders_back_for_a_new_name(self):
+        dest = self._capture("--no-input")
+        template = scaffold.load_template(dest, "team/svc")
+        variables = {
+            **scaffold.project_variables("orders"),
+            "MODULE_PATH": "github.com/ada/orders",
+        }
+        files = dict(scaffold.render_project(template, [], variables))
+        self.assertIn("cmd/orders/main.go", files)
+        self.assertIn("OrdersServer", files["cmd/orders/main.go"])
+        self.assertEqual(files["go.mod"], "module github.com/ada/orders\n")
+
+    def test_declined_replacements_are_kept(self):
+        # Module path: yes; project name: no; then the description.
+        dest = self._capture(stdin="y\nn\n\n\n\n\n")
+        self.assertEqual(
+            (dest / "run.sh").read_text(), "#!/bin/sh\necho billing-api\n"
+        )
+        self.assertEqual(
+            (dest / "go.mod").read_text(), "module {{MODULE_PATH}}\n"
+        )
+
+    def test_refuses_existing_template(self):
+        self._capture("--no-input")
+        with self.assertRaises(SystemExit):
+            self._capture("--no-input")
+
+
+if __name__ == "__main__":
+    unittest.main()
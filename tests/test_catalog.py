@@ -0,0 +1,108 @@
+#!/usr/bin/env python3
+"""Tests for jolo templates / jolo search."""
+
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import catalog, scaffold
+
+
+def _template(root, name, manifest):
+    path = root / name
+    path.mkdir(parents=True)
+    (path / scaffold.MANIFEST_FILE).write_text(manifest)
+
+
+class TestCatalog(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_builtins_carry_tags(self):
+        entries = catalog.catalog([scaffold.TEMPLATES_ROOT], [])
+        web = next(e for e in entries if e.name == "go/web")
+        self.assertEqual(web.language, "go")
+        self.assertEqual(web.source, "builtin")
+        self.assertIn("web", web.tags)
+
+    def test_layers_shadow_and_git_sources_follow(self):
+        _template(
+            self.root,
+            "go/web",
+            'description = "team"\nlanguage = "golang"\ntags = ["x"]\n',
+        )
+        entries = catalog.catalog(
+            [self.root, scaffold.TEMPLATES_ROOT],
+            ["go", "github.com/me/tpl"],
+        )
+        web = next(e for e in entries if e.name == "go/web")
+        self.assertEqual(
+            (web.description, web.language, web.tags, web.source),
+            ("team", "golang", ["x"], str(self.root)),
+        )
+        self.assertEqual(entries[-1].name, "github.com/me/tpl")
+        self.assertEqual(entries[-1].source, "git")
+
+
+class TestSearch(unittest.TestCase):
+    def setUp(self):
+        self.entries = [
+            catalog.Entry("go", "go", "builtin", "Go module", ["cli"]),
+            catalog.Entry("go/web", "go", "builtin", "templ + HTMX", ["web"]),
+            catalog.Entry("python/web", "python", "builtin", "FastAPI"),
+        ]
+
+    def names(self, query):
+        return [e.name for e in catalog.search(self.entries, query)]
+
+    def test_every_word_must_match(self):
+        self.assertEqual(self.names("go web"), ["go/web"])
+        self.assertEqual(self.names("web"), ["go/web", "python/web"])
+        self.assertEqual(self.names("rails"), [])
+
+    def test_best_match_first(self):
+        self.assertEqual(self.names("htmx"), ["go/web"])
+        self.assertEqual(self.names("go")[0], "go")
+
+    def test_fuzzy_subsequence_on_name(self):
+        self.assertEqual(self.names("pyw"), ["python/web"])
+
+    def test_format_skips_empty_columns(self):
+        lines = catalog.format_entries(
+            [catalog.Entry("a", "x", "builtin"), catalog.Entry("bb", "y", "g")]
+        )
+        self.assertEqual(lines, ["a   x  builtin", "bb  y  g"])
+
+
+class TestTemplatesMode(unittest.TestCase):
+    def test_search_alias_parses(self):
+        args = jolo.parse_args(["search", "go", "web", "--tag", "web"])
+        self.assertEqual(args.command, "search")
+        self.assertEqual(args.query, ["go", "web"])
+        self.assertEqual(args.tag, "web")
+
+    def test_lists_builtins(self):
+        args = jolo.parse_args(["templates"])
+        with (
+            mock.patch.object(catalog, "load_config", return_value={}),
+            mock.patch("builtins.print") as fake_print,
+        ):
+            catalog.run_templates_mode(args)
+        printed = [c.args[0] for c in fake_print.call_args_list]
+        self.assertTrue(any(line.startswith("go/web ") for line in printed))
+
+    def test_no_match_exits(self):
+        args = jolo.parse_args(["search", "zzzz"])
+        with mock.patch.object(catalog, "load_config", return_value={}):
+            with self.assertRaises(SystemExit):
+                catalog.run_templates_mode(args)
+
+
+if __name__ == "__main__":
+    unittest.main()
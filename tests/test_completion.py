@@ -0,0 +1,105 @@
+#!/usr/bin/env python3
+"""Tests for jolo completion and the __complete helper."""
+
+import io
+import shutil
+import subprocess
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import completion, scaffold
+
+
+class TestComplete(unittest.TestCase):
+    def setUp(self):
+        patcher = mock.patch.object(completion, "load_config", return_value={})
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def test_subcommands(self):
+        self.assertEqual(completion.complete(["ne"]), ["new"])
+        self.assertIn("completion", completion.complete([""]))
+
+    def test_flags(self):
+        self.assertIn("--dry-run", completion.complete(["new", "--d"]))
+
+    def test_template_names(self):
+        names = completion.complete(["new", "go"])
+        self.assertIn("go/web", names)
+        self.assertNotIn("python", names)
+        self.assertEqual(completion.complete(["new", "go/web", ""]), [])
+
+    def test_option_choices(self):
+        self.assertEqual(
+            completion.complete(["new", "go", "--on-conflict", "b"]),
+            ["backup"],
+        )
+
+    def test_addons_for_the_selected_template(self):
+        names = scaffold.addon_names(scaffold.find_template("go/web"))
+        self.assertEqual(
+            completion.complete(["new", "go/web", "--with", ""]), names
+        )
+        self.assertTrue({"docker", "sqlite", "tailwind"} <= set(names))
+        self.assertEqual(
+            completion.complete(
+                ["new", "go/web", "--with", "docker,sqlite", "--with", "sq"]
+            ),
+            [],
+        )
+        self.assertEqual(
+            completion.complete(["new", "go/web", "--with", "docker,"]),
+            [f"docker,{n}" for n in names if n != "docker"],
+        )
+
+    def test_var_keys_from_the_manifest(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            path = Path(tmp) / "svc"
+            path.mkdir()
+            (path / scaffold.MANIFEST_FILE).write_text(
+                "[variables.port]\ndefault = 1\n"
+            )
+            words = ["new", "svc", "--templates-dir", tmp, "--var", ""]
+            self.assertIn("port=", completion.complete(words))
+
+    def test_unknown_template_offers_nothing(self):
+        with mock.patch("sys.stderr", io.StringIO()):
+            words = ["new", "nope", "--var", ""]
+            self.assertEqual(completion.complete(words), [])
+
+
+class TestCompletionScripts(unittest.TestCase):
+    def test_parses(self):
+        args = jolo.parse_args(["completion", "fish"])
+        self.assertEqual(args.shell, "fish")
+
+    def test_prints_script(self):
+        args = jolo.parse_args(["completion", "zsh"])
+        with mock.patch("sys.stdout", new_callable=io.StringIO) as out:
+            completion.run_completion_mode(args)
+        self.assertIn("compdef _jolo jolo", out.getvalue())
+
+    @unittest.skipUnless(shutil.which("bash"), "bash not installed")
+    def test_bash_script_is_valid(self):
+        result = subprocess.run(
+            ["bash", "-n"],
+            input=completion.BASH,
+            capture_output=True,
+            text=True,
+        )
+        self.assertEqual(result.returncode, 0, result.stderr)
+
+    def test_main_dispatches_complete(self):
+        with (
+            mock.patch.object(completion, "load_config", return_value={}),
+            mock.patch("builtins.print") as fake_print,
+        ):
+            jolo.main(["__complete", "completion", "b"])
+        fake_print.assert_called_once_with("bash")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,142 @@
+#!/usr/bin/env python3
+"""Tests for rendering cookiecutter templates."""
+
+import json
+import tempfile
+import unittest
+from pathlib import Path
+
+from _jolo import api, cookiecutter, scaffold
+
+
+class TestRender(unittest.TestCase):
+    def render(self, text, **variables):
+        return cookiecutter.render(text, variables)
+
+    def test_variables_methods_and_filters(self):
+        self.assertEqual(
+            self.render(
+                "{{ cookiecutter.name.lower().replace(' ', '_') }} "
+                "{{cookiecutter.name | slugify}} "
+                "{{ cookiecutter.name | replace('Big', 'Small') | upper }}",
+                name="Big App",
+            ),
+            "big_app big-app SMALL APP",
+        )
+
+    def test_conditionals(self):
+        text = (
+            "{% if cookiecutter.db == 'postgres' %}pg"
+            "{% elif cookiecutter.db in ['sqlite', 'duckdb'] %}file"
+            "{% else %}none{% endif %}"
+        )
+        for db, want in (
+            ("postgres", "pg"),
+            ("duckdb", "file"),
+            ("x", "none"),
+        ):
+            self.assertEqual(self.render(text, db=db), want)
+
+    def test_nested_if_and_boolean_logic(self):
+        text = (
+            "{% if cookiecutter.a == 'y' and not cookiecutter.b %}"
+            "{% if cookiecutter.c %}1{% else %}2{% endif %}"
+            "{% endif %}"
+        )
+        self.assertEqual(self.render(text, a="y", b="false", c="true"), "1")
+        self.assertEqual(self.render(text, a="y", b="", c="false"), "2")
+        self.assertEqual(self.render(text, a="n", b="", c="true"), "")
+
+    def test_whitespace_control(self):
+        text = "a\n{%- if cookiecutter.x %}\nb\n{%- endif %}\nc\n"
+        self.assertEqual(self.render(text, x="true"), "a\nb\nc\n")
+        self.assertEqual(self.render(text, x="false"), "a\nc\n")
+
+    def test_raw_and_comments(self):
+        self.assertEqual(
+            self.render("{# note #}{% raw %}{{ x }}{% endraw %}"), "{{ x }}"
+        )
+
+    def test_unsupported_constructs_raise(self):
+        for text in (
+            "{% for x in cookiecutter.items %}{% endfor %}",
+            "{{ cookiecutter.missing }}",
+            "{{ cookiecutter.x | wordwrap }}",
+            "{{ cookiecutter.x.__class__ }}",
+            "{% if cookiecutter.x %}",
+        ):
+            with self.assertRaises(ValueError, msg=text):
+                self.render(text, x="1")
+
+
+class TestTemplate(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name) / "tpl"
+        project = self.root / "{{cookiecutter.project_slug}}"
+        (project / "{{cookiecutter.pkg}}").mkdir(parents=True)
+        (self.root / "cookiecutter.json").write_text(
+            json.dumps(
+                {
+                    "project_name": "My Thing",
+                    "project_slug": "{{ cookiecutter.project_name|slugify }}",
+                    "pkg": "{{ cookiecutter.project_slug.replace('-', '_') }}",
+                    "license": ["MIT", "Apache-2.0"],
+                    "docker": True,
+                    "_copy_without_render": ["*.html"],
+                    "__prompts__": {"project_name": "Name of the project"},
+                }
+            )
+        )
+        (project / "README.md").write_text(
+            "# {{ cookiecutter.project_name }} ({{ cookiecutter.license }})\n"
+            "{% if cookiecutter.docker %}docker{% endif %}\n"
+        )
+        (project / "{{cookiecutter.pkg}}" / "__init__.py").write_text("")
+        (project / "page.html").write_text("{{ untouched }}\n")
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_manifest_from_cookiecutter_json(self):
+        template = scaffold.load_template(self.root, "cc")
+        self.assertEqual(template.path.name, "{{cookiecutter.project_slug}}")
+        variables = template.variables
+        self.assertEqual(
+            variables["project_name"]["prompt"], "Name of the project"
+        )
+        self.assertEqual(variables["license"]["type"], "choice")
+        self.assertEqual(
+            variables["docker"], {"type": "bool", "default": True}
+        )
+        self.assertNotIn("_copy_without_render", variables)
+        self.assertEqual(template.manifest["binary"], ["*.html"])
+
+    def test_renders_through_the_api(self):
+        template = scaffold.load_template(self.root, "cc")
+        files = api.render(template, "shop-api", {"docker": False})
+        self.assertEqual(
+            sorted(files),
+            ["LICENSE", "README.md", "page.html", "shop_api/__init__.py"],
+        )
+        self.assertEqual(files["README.md"], "# My Thing (MIT)\n\n")
+        self.assertEqual(files["page.html"], b"{{ untouched }}\n")
+
+    def test_defaults_follow_earlier_answers(self):
+        template = scaffold.load_template(self.root, "cc")
+        answers = scaffold.resolve_variables(
+            template,
+            {"project_slug": "Custom-Slug"},
+            no_input=True,
+            context=scaffold.project_variables("x"),
+        )
+        self.assertEqual(answers["pkg"], "Custom_Slug")
+
+    def test_needs_one_project_dir(self):
+        (self.root / "{{cookiecutter.other}}").mkdir()
+        with self.assertRaises(SystemExit):
+            scaffold.load_template(self.root, "cc")
+
+
+if __name__ == "__main__":
+    unittest.main()
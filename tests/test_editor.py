@@ -0,0 +1,96 @@
+#!/usr/bin/env python3
+"""Tests for jolo new --open (_jolo.editor)."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import editor, scaffold
+
+
+class TestCommand(unittest.TestCase):
+    def test_editor_command_lines(self):
+        path = Path("/src/demo")
+        with mock.patch("shutil.which", return_value="/usr/bin/emacsclient"):
+            self.assertEqual(
+                editor.command("emacs", path),
+                ["emacsclient", "-n", "/src/demo"],
+            )
+            self.assertEqual(
+                editor.command("emacsclient -c", path),
+                ["emacsclient", "-c", "-n", "/src/demo"],
+            )
+        self.assertEqual(
+            editor.command("code --new-window", path),
+            ["code", "--new-window", "/src/demo"],
+        )
+        self.assertEqual(editor.command("vim", path), ["vim", "/src/demo"])
+
+    def test_default_editor(self):
+        with mock.patch.dict(os.environ, {"VISUAL": "", "EDITOR": "hx"}):
+            self.assertEqual(editor._default(), "hx")
+        with (
+            mock.patch.dict(os.environ, {"VISUAL": "", "EDITOR": ""}),
+            mock.patch("shutil.which", side_effect=lambda p: p == "code"),
+        ):
+            self.assertEqual(editor._default(), "code")
+
+    def test_gui_editor_is_detached(self):
+        with mock.patch("subprocess.Popen") as popen:
+            editor.open_project(Path("/src/demo"), "code")
+        self.assertEqual(popen.call_args.args[0], ["code", "/src/demo"])
+        self.assertTrue(popen.call_args.kwargs["start_new_session"])
+
+    def test_failure_is_a_warning(self):
+        with (
+            mock.patch("subprocess.run", side_effect=FileNotFoundError("nvi")),
+            mock.patch("sys.stderr", io.StringIO()) as err,
+        ):
+            editor.open_project(Path("/src/demo"), "nvi")
+        self.assertIn("Warning: --open: nvi", err.getvalue())
+
+
+class TestNewOpen(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv, config=None):
+        args = jolo.parse_args(
+            ["new", "go", "demo", "--no-input", "--git=false", "--no-install"]
+            + list(argv)
+        )
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": config or {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()),
+            mock.patch.object(editor, "open_project") as open_project,
+        ):
+            scaffold.run_new_mode(args)
+        return open_project
+
+    def test_open_after_writing(self):
+        open_project = self._new("--open=code")
+        open_project.assert_called_once_with(Path("demo").resolve(), "code")
+
+    def test_config_editor(self):
+        open_project = self._new("--open", config={"editor": "emacs"})
+        open_project.assert_called_once_with(Path("demo").resolve(), "emacs")
+
+    def test_not_without_the_flag(self):
+        self.assertFalse(self._new(config={"editor": "emacs"}).called)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,76 @@
+#!/usr/bin/env python3
+"""Tests for the .editorconfig sections templates contribute."""
+
+import tempfile
+import unittest
+from pathlib import Path
+
+from _jolo import editorconfig, scaffold
+
+SHARED = "root = true\n\n[*]\nindent_size = 4\n"
+
+
+def _template(name, sections=None):
+    manifest = {"editorconfig": sections} if sections is not None else {}
+    return scaffold.Template(name, Path(tempfile.gettempdir()), manifest)
+
+
+class TestSections(unittest.TestCase):
+    def test_merged_by_glob_later_wins(self):
+        templates = [
+            _template("go", {"*.go": {"indent_style": "tab"}}),
+            _template("addon", {"*.go": {"indent_size": 8, "x": True}}),
+            _template("last", {"*.go": {"indent_size": 4}}),
+        ]
+        self.assertEqual(
+            editorconfig.sections(templates),
+            {"*.go": {"indent_style": "tab", "indent_size": "4", "x": "true"}},
+        )
+
+    def test_bad_tables_exit(self):
+        for table in ({"*.go": "tab"}, {"*.go": {"a": [1]}}):
+            with self.assertRaises(SystemExit, msg=table):
+                editorconfig.sections([_template("t", table)])
+
+
+class TestApply(unittest.TestCase):
+    def test_sections_follow_the_shared_rules(self):
+        files = [(".editorconfig", SHARED), ("main.go", "")]
+        template = _template("go", {"*.go": {"indent_style": "tab"}})
+        out = dict(editorconfig.apply(files, [template]))
+        self.assertEqual(
+            out[".editorconfig"], SHARED + "\n[*.go]\nindent_style = tab\n"
+        )
+
+    def test_left_alone_without_the_file_or_sections(self):
+        files = [("main.go", "")]
+        template = _template("go", {"*.go": {"indent_style": "tab"}})
+        self.assertIs(editorconfig.apply(files, [template]), files)
+        files = [(".editorconfig", SHARED)]
+        self.assertIs(editorconfig.apply(files, [_template("t")]), files)
+
+    def test_nested_drops_root(self):
+        files = editorconfig.nested([(".editorconfig", "# x\n" + SHARED)])
+        self.assertEqual(
+            dict(files)[".editorconfig"], "# x\n\n[*]\nindent_size = 4\n"
+        )
+
+    def test_builtin_templates(self):
+        def rendered(name):
+            base = scaffold.find_template(name)
+            addon = scaffold.find_addon("editorconfig", base)
+            variables = scaffold.project_variables("demo")
+            files = scaffold.render_project(base, [addon], variables)
+            return dict(files)[".editorconfig"]
+
+        go_web = rendered("go/web")
+        self.assertIn("[*.go]\nindent_style = tab\n", go_web)
+        self.assertIn("[*.templ]\nindent_style = tab\n", go_web)
+        self.assertIn("root = true", go_web)
+        self.assertNotIn("[*.templ]", rendered("go"))
+        self.assertIn("[*.rs]\nmax_line_length = 100\n", rendered("rust"))
+        self.assertIn("[*.{ts,tsx,js,jsx}]", rendered("typescript/web"))
+
+
+if __name__ == "__main__":
+    unittest.main()
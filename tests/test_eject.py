@@ -0,0 +1,178 @@
+#!/usr/bin/env python3
+"""Tests for jolo eject."""
+
+import os
+import shutil
+import subprocess
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import add, eject, scaffold, upgrade
+
+
+def _git(cwd, *args):
+    subprocess.run(
+        ["git", "-c", "user.name=t", "-c", "user.email=t@t", *args],
+        cwd=cwd,
+        check=True,
+        capture_output=True,
+    )
+
+
+class TestEject(unittest.TestCase):
+    """A project from a team templates dir, ejected and then changed."""
+
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        self.team = Path(self.tmpdir) / "team"
+        files = {
+            "_base/template.toml": "",
+            "_base/base.txt": "base {{PROJECT_NAME}}\n",
+            "svc/template.toml": 'extends = "_base"\n',
+            "svc/a.txt": "v1 {{PROJECT_NAME}}\n",
+            "svc/generators/route/generator.toml": 'description = "A route"\n',
+            "svc/generators/route/{{NAME}}.txt": "route\n",
+            "svc/nested/template.toml": "",
+        }
+        for rel, content in files.items():
+            path = self.team / rel
+            path.parent.mkdir(parents=True, exist_ok=True)
+            path.write_text(content)
+        _git(self.team, "init", "-q")
+        _git(self.team, "add", "-A")
+        _git(self.team, "commit", "-q", "-m", "v1")
+
+        os.chdir(self.tmpdir)
+        args = jolo.parse_args(
+            ["new", "svc", "demo", "--templates-dir", str(self.team)]
+        )
+        with mock.patch("builtins.print"):
+            scaffold.run_new_mode(args)
+        os.chdir("demo")
+        self.project = Path.cwd()
+        self.copy = self.project / scaffold.EJECT_DIR / "lang"
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _run(self, mode, *argv):
+        args = jolo.parse_args(list(argv))
+        with mock.patch("builtins.print") as fake_print:
+            mode(args)
+        return [c.args[0] for c in fake_print.call_args_list if c.args]
+
+    def _eject(self):
+        return self._run(eject.run_eject_mode, "eject")
+
+    def _commit(self):
+        _git(self.project, "add", "-A")
+        _git(self.project, "commit", "-q", "-m", "change")
+
+    def test_copies_the_template_and_its_parent(self):
+        self._eject()
+        self.assertEqual(
+            (self.copy / "svc" / "a.txt").read_text(), "v1 {{PROJECT_NAME}}\n"
+        )
+        self.assertTrue((self.copy / "svc/generators/route").is_dir())
+        self.assertFalse((self.copy / "svc" / "nested").exists())
+        self.assertTrue((self.copy / "_base" / "base.txt").is_file())
+        self.assertEqual(scaffold.read_lock(self.project)["ejected"], "svc")
+
+    def test_copies_the_locked_version(self):
+        (self.team / "svc" / "a.txt").write_text("v2 {{PROJECT_NAME}}\n")
+        _git(self.team, "commit", "-q", "-am", "v2")
+        self._eject()
+        self.assertEqual(
+            (self.copy / "svc" / "a.txt").read_text(), "v1 {{PROJECT_NAME}}\n"
+        )
+
+    def test_upgrade_prefers_the_ejected_copy(self):
+        self._eject()
+        (self.team / "svc" / "a.txt").write_text("v2 {{PROJECT_NAME}}\n")
+        _git(self.team, "commit", "-q", "-am", "v2")
+        (self.copy / "svc" / "a.txt").write_text("ours {{PROJECT_NAME}}\n")
+        self._commit()
+        self._run(upgrade.run_upgrade_mode, "upgrade")
+        self.assertEqual(Path("a.txt").read_text(), "ours demo\n")
+        lock = scaffold.read_lock(self.project)
+        self.assertEqual(lock["ejected_commit"], self._head())
+
+        # The next upgrade starts from the copy as it was then.
+        self._commit()
+        (self.copy / "svc" / "a.txt").write_text("ours again\n")
+        (self.copy / "svc" / "b.txt").write_text("new\n")
+        self._commit()
+        self._run(upgrade.run_upgrade_mode, "upgrade")
+        self.assertEqual(Path("a.txt").read_text(), "ours again\n")
+        self.assertEqual(Path("b.txt").read_text(), "new\n")
+
+    def test_diff_right_after_ejecting(self):
+        self._eject()
+        out = self._run(upgrade.run_diff_mode, "diff")
+        self.assertEqual(out, ["demo matches svc"])
+
+    def test_add_uses_the_ejected_generators(self):
+        self._eject()
+        route = self.copy / "svc" / "generators" / "route"
+        (route / "{{NAME}}.txt").write_text("our route\n")
+        self._run(add.run_add_mode, "add", "route", "users")
+        self.assertEqual(Path("users.txt").read_text(), "our route\n")
+
+    def test_ejects_once(self):
+        self._eject()
+        with self.assertRaises(SystemExit) as cm:
+            self._eject()
+        self.assertIn("already ejected", str(cm.exception))
+
+    def _head(self):
+        return subprocess.run(
+            ["git", "rev-parse", "HEAD"],
+            cwd=self.project,
+            capture_output=True,
+            text=True,
+        ).stdout.strip()
+
+
+class TestEjectBuiltin(unittest.TestCase):
+    def test_go_worker_with_an_addon(self):
+        template = scaffold.find_template("go/worker")
+        addon = scaffold.find_addon("lefthook", template)
+        with tempfile.TemporaryDirectory() as tmp:
+            dest = Path(tmp)
+            written = eject.eject(
+                template, "go/worker", [addon], scaffold.ADDONS_ROOT, dest
+            )
+            lang = dest / "lang"
+            self.assertEqual(
+                written,
+                [
+                    "lang/go/worker",
+                    "lang/go/_service",
+                    "lang/go/_base",
+                    "addons/go/lefthook",
+                ],
+            )
+            ejected = scaffold.find_template("go/worker", lang)
+            self.assertEqual(ejected.parent.path, lang / "go" / "_service")
+            variables = scaffold.project_variables("demo")
+            variables.update(scaffold.config_variables({}, "demo"))
+            self.assertEqual(
+                dict(scaffold.render_template(ejected, variables)),
+                dict(scaffold.render_template(template, variables)),
+            )
+
+    def test_git_templates_are_named_after_their_repo(self):
+        self.assertEqual(eject.ejected_name("go/web"), "go/web")
+        self.assertEqual(eject.ejected_name("github.com/me/tpl@v1"), "tpl")
+        self.assertEqual(
+            eject.ejected_name("github.com/me/tpls//go/svc"), "svc"
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,67 @@
+#!/usr/bin/env python3
+"""Tests for the [env] exports written to .envrc."""
+
+import tempfile
+import unittest
+from pathlib import Path
+
+from _jolo import envrc, scaffold
+
+VARIABLES = {"PROJECT_NAME_UNDERSCORE": "shop"}
+ENVRC = """\
+dotenv_if_exists
+export PORT=4000
+source_env_if_exists .envrc.local
+"""
+
+
+def _template(name, env=None):
+    manifest = {"env": env} if env is not None else {}
+    return scaffold.Template(name, Path(tempfile.gettempdir()), manifest)
+
+
+class TestExports(unittest.TestCase):
+    def test_rendered_and_later_templates_win(self):
+        templates = [
+            _template("base", {"LOG": "{{PROJECT_NAME_UNDERSCORE}}=debug"}),
+            _template("addon", {"MODE": "a b", "PORT": "5000"}),
+            _template("last", {"MODE": "dev"}),
+        ]
+        self.assertEqual(
+            envrc.exports(templates, VARIABLES),
+            {"LOG": "shop=debug", "MODE": "dev", "PORT": "5000"},
+        )
+
+    def test_bad_tables_exit(self):
+        for env in ({"A": 1}, {"bad-name": "x"}, ["A"]):
+            with self.assertRaises(SystemExit, msg=env):
+                envrc.exports([_template("t", env)], VARIABLES)
+
+
+class TestApply(unittest.TestCase):
+    def test_exports_go_above_the_local_file(self):
+        template = _template("t", {"PORT": "5000", "MODE": "a b"})
+        files = envrc.apply([(".envrc", ENVRC)], [template], VARIABLES)
+        self.assertEqual(
+            dict(files)[".envrc"],
+            "dotenv_if_exists\n"
+            "export PORT=4000\n"
+            "export MODE='a b'\n"
+            "source_env_if_exists .envrc.local\n",
+        )
+
+    def test_appends_without_a_local_file(self):
+        files = envrc.apply(
+            [(".envrc", "use flake")], [_template("t", {"A": "1"})], {}
+        )
+        self.assertEqual(dict(files)[".envrc"], "use flake\nexport A=1\n")
+
+    def test_no_envrc_no_exports(self):
+        files = [("main.go", "package main\n")]
+        self.assertIs(
+            envrc.apply(files, [_template("t", {"A": "1"})], {}), files
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,125 @@
+#!/usr/bin/env python3
+"""Tests for jolo new --json and exit codes (_jolo.events)."""
+
+import io
+import json
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from _jolo import commands, events, scaffold, toolchain
+
+
+class TestEvents(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        tpl = Path(self.tmpdir, "templates", "t")
+        tpl.mkdir(parents=True)
+        (tpl / "template.toml").write_text(
+            'description = "t"\n\n[hooks]\npost = ["true"]\n'
+        )
+        (tpl / "README.md").write_text("# {{PROJECT_NAME}}\n")
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _main(self, *argv):
+        """Exit status, events and stderr of ``jolo new t demo ARGV``."""
+        argv = [
+            "new",
+            "t",
+            "demo",
+            "--templates-dir",
+            "templates",
+            "--git=false",
+            "--license=none",
+            *argv,
+        ]
+        status = 0
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()) as out,
+            mock.patch("sys.stderr", io.StringIO()) as err,
+        ):
+            try:
+                commands.main(argv)
+            except SystemExit as e:
+                status = e.code
+        lines = out.getvalue().splitlines()
+        return status, [json.loads(line) for line in lines], err.getvalue()
+
+    def test_stream(self):
+        status, stream, err = self._main("--json")
+        self.assertEqual(status, 0)
+        self.assertEqual(
+            [e["event"] for e in stream],
+            ["start", "file", "hook", "hook", "summary"],
+        )
+        self.assertEqual(stream[0]["template"], "t")
+        self.assertEqual(
+            stream[1], {"event": "file", "path": "README.md", "bytes": 7,
+                        "action": "create"}
+        )  # fmt: skip
+        self.assertEqual(stream[3]["status"], "ok")
+        self.assertEqual(stream[-1]["files"], {"create": 1})
+        self.assertIn("Created t project", err)
+        self.assertFalse(events.enabled())
+
+    def test_dry_run_plans(self):
+        _, stream, _ = self._main("--json", "--dry-run")
+        self.assertEqual(
+            [e["event"] for e in stream], ["start", "plan", "summary"]
+        )
+        self.assertFalse(Path("demo").exists())
+
+    def test_conflict_status(self):
+        Path("demo").mkdir()
+        for argv in ((), ("--json",)):
+            status, stream, err = self._main(*argv)
+            self.assertEqual(status, events.EXIT["conflict"])
+            self.assertIn("Directory already exists", err)
+        self.assertEqual(stream[-1]["event"], "error")
+        self.assertEqual(stream[-1]["exit"], 3)
+
+    def test_conflict_decisions(self):
+        Path("demo").mkdir()
+        Path("demo/README.md").write_text("mine\n")
+        _, stream, _ = self._main("--json", "--on-conflict=skip")
+        self.assertIn(
+            {"event": "conflict", "path": "README.md", "decision": "skip"},
+            stream,
+        )
+        self.assertEqual(stream[-1]["files"], {"skip": 1})
+
+    def test_hook_status(self):
+        Path("templates/t/template.toml").write_text(
+            'description = "t"\n\n[hooks]\npost = ["exit 7"]\n'
+        )
+        status, stream, _ = self._main("--json")
+        self.assertEqual(status, events.EXIT["hook"])
+        self.assertEqual(stream[-2]["exit"], 7)
+        self.assertIn("removed", stream[-1]["message"])
+        self.assertFalse(Path("demo").exists())
+
+    def test_tools_status(self):
+        Path("templates/t/template.toml").write_text(
+            'description = "t"\n\n[requires]\ngo = "1.21"\n'
+        )
+        with mock.patch.object(
+            toolchain, "installed_version", return_value=None
+        ):
+            status, stream, _ = self._main("--json", "--check")
+        self.assertEqual(status, events.EXIT["tools"])
+        self.assertEqual([e["event"] for e in stream], ["error"])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,119 @@
+#!/usr/bin/env python3
+"""Tests for did-you-mean suggestions and the fuzzy picker."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import fuzzy, scaffold
+
+NAMES = ["go", "go/web", "python", "python/web", "rust", "typescript"]
+
+
+class TestSuggest(unittest.TestCase):
+    def test_misspelled(self):
+        self.assertEqual(fuzzy.suggest("pyton", NAMES)[0], "python")
+        self.assertEqual(fuzzy.suggest("gp/web", NAMES)[0], "go/web")
+        self.assertEqual(fuzzy.suggest("cobol", NAMES), [])
+
+    def test_bare_name(self):
+        self.assertEqual(fuzzy.suggest("web", NAMES), ["go/web", "python/web"])
+
+    def test_did_you_mean(self):
+        self.assertEqual(
+            fuzzy.did_you_mean("web", NAMES),
+            " (did you mean go/web or python/web?)",
+        )
+        self.assertEqual(
+            fuzzy.did_you_mean("rusty", NAMES), " (did you mean rust?)"
+        )
+        self.assertEqual(fuzzy.did_you_mean("cobol", NAMES), "")
+
+    def test_matches(self):
+        self.assertEqual(fuzzy.matches("", NAMES), NAMES)
+        self.assertEqual(fuzzy.matches("PW", NAMES), ["python/web"])
+        self.assertEqual(
+            fuzzy.matches("t", ["xxt", "t", "txx"]), ["t", "txx", "xxt"]
+        )
+        self.assertEqual(fuzzy.matches("zz", NAMES), [])
+
+
+class TestTemplateTypos(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _args(self, template):
+        return jolo.parse_args(
+            [
+                "new",
+                template,
+                "demo",
+                "--git=false",
+                "--license=none",
+                "--var",
+                "DESCRIPTION=d",
+                "--var",
+                "MODULE_PATH=demo",
+            ]
+        )
+
+    def test_error_suggests(self):
+        with self.assertRaises(SystemExit) as cm:
+            scaffold.find_layered("gp/web", [scaffold.TEMPLATES_ROOT])
+        self.assertIn("(did you mean go/web", cm.exception.code)
+        base = scaffold.find_template("go/web")
+        with self.assertRaises(SystemExit) as cm:
+            scaffold.find_addon("sqlit", base)
+        self.assertIn("did you mean sqlite", cm.exception.code)
+
+    def test_typo_opens_the_picker(self):
+        with (
+            mock.patch("sys.stdin.isatty", return_value=True),
+            mock.patch.object(scaffold.shutil, "which", return_value="fzf"),
+            mock.patch.object(
+                scaffold, "_fzf_pick", return_value="go  Go module"
+            ) as pick,
+            mock.patch("sys.stderr", io.StringIO()) as err,
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(self._args("og"))
+        self.assertEqual(pick.call_args.args[2], "og")
+        self.assertIn("Unknown template: og", err.getvalue())
+        self.assertTrue(Path(self.tmpdir, "demo", "go.mod").exists())
+
+    def test_plain_picker_narrows(self):
+        answers = iter(["web", "pyweb"])
+        with (
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("sys.stderr", io.StringIO()) as err,
+        ):
+            picked = scaffold.prompt_choice(
+                "Template", [(n, "") for n in NAMES], "g"
+            )
+        self.assertEqual(picked, "python/web")
+        listed = err.getvalue().splitlines()
+        self.assertEqual(listed[:2], ["  1) go", "  2) go/web"])
+        self.assertIn("  2) python/web", listed)
+
+    def test_no_input_still_fails(self):
+        args = self._args("goo")
+        args.no_input = True
+        with mock.patch("sys.stdin.isatty", return_value=True):
+            with self.assertRaises(SystemExit) as cm:
+                scaffold.run_new_mode(args)
+        self.assertIn("did you mean go", cm.exception.code)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,74 @@
+#!/usr/bin/env python3
+"""Tests for generated .gitignore files."""
+
+import tempfile
+import unittest
+from pathlib import Path
+
+from _jolo import gitignore, scaffold
+
+VARIABLES = {"PROJECT_NAME": "shop"}
+
+
+def _template(name, manifest=None):
+    return scaffold.Template(name, Path(tempfile.gettempdir()), manifest or {})
+
+
+class TestRuleSets(unittest.TestCase):
+    def test_language_from_name_or_manifest(self):
+        self.assertEqual(gitignore.rule_sets(_template("go/web")), ["go"])
+        self.assertEqual(
+            gitignore.rule_sets(_template("typescript")), ["node"]
+        )
+        self.assertEqual(
+            gitignore.rule_sets(_template("x", {"language": "python"})),
+            ["python"],
+        )
+        self.assertEqual(gitignore.rule_sets(_template("github.com/a/b")), [])
+
+    def test_manifest_adds_rule_sets(self):
+        template = _template("go", {"gitignore": {"rules": ["node", "go"]}})
+        self.assertEqual(gitignore.rule_sets(template), ["go", "node"])
+
+    def test_unknown_rule_set_exits(self):
+        template = _template("go", {"gitignore": {"rules": ["cobol"]}})
+        with self.assertRaises(SystemExit) as cm:
+            gitignore.rule_sets(template)
+        self.assertIn("unknown gitignore rules cobol", cm.exception.code)
+
+    def test_every_builtin_language_has_rules(self):
+        for template in scaffold.list_templates():
+            self.assertTrue(gitignore.rule_sets(template), template.name)
+
+
+class TestGenerate(unittest.TestCase):
+    def test_sections_rendered_and_deduplicated(self):
+        base = _template("go", {"gitignore": {"entries": ["tmp/"]}})
+        addon = _template("sqlite", {"gitignore": {"entries": ["*.db"]}})
+        text = gitignore.generate(
+            [base, addon], VARIABLES, own="# mine\n*.log\nlocal/\n"
+        )
+        lines = text.splitlines()
+        self.assertIn("/shop", lines)
+        self.assertEqual(lines.count("*.log"), 1)
+        self.assertLess(lines.index("# --- go ---"), lines.index("tmp/"))
+        self.assertLess(lines.index("*.db"), lines.index("local/"))
+        self.assertIn("# mine", lines)
+
+    def test_apply_replaces_the_shipped_file_in_place(self):
+        files = [(".gitignore", "local/\n"), ("main.go", "package main\n")]
+        out = gitignore.apply(files, [_template("go")], VARIABLES)
+        self.assertEqual([rel for rel, _ in out], [".gitignore", "main.go"])
+        self.assertTrue(out[0][1].startswith("# Generated by jolo new"))
+        self.assertTrue(out[0][1].endswith("local/\n"))
+
+    def test_unknown_language_is_left_alone(self):
+        files = [(".gitignore", "mine\n")]
+        self.assertEqual(
+            gitignore.apply(files, [_template("github.com/a/b")], VARIABLES),
+            files,
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,256 @@
+#!/usr/bin/env python3
+"""Tests for Go module paths and versions in jolo new."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import api, golang, scaffold
+
+
+class TestModulePath(unittest.TestCase):
+    def test_valid_paths(self):
+        for path in (
+            "app",
+            "github.com/ada/my-app",
+            "github.com/Ada/App",
+            "example.com/x/v2",
+            "gopkg.in/yaml.v3",
+            "my_app~1x",
+        ):
+            golang.check_module_path(path)
+
+    def test_invalid_paths(self):
+        for path, why in (
+            ("", "empty"),
+            ("my app", "invalid character ' '"),
+            ("GitHub.com/ada/app", "must be lower case"),
+            ("github.com//app", "empty path element"),
+            ("github.com/ada/", "trailing slash"),
+            ("github.com/.hidden", "starts or ends with ."),
+            ("-app", "leading dash"),
+            ("example.com/con", "reserved"),
+            ("example.com/ünï", "invalid character"),
+        ):
+            with self.assertRaises(ValueError, msg=path) as cm:
+                golang.check_module_path(path)
+            self.assertIn(why, str(cm.exception))
+
+    def test_default_follows_the_name(self):
+        self.assertEqual(
+            golang.default_module_path("", "shop-api"), "shop-api"
+        )
+        self.assertEqual(
+            golang.default_module_path("github.com/ada", "My App"),
+            "github.com/ada/my-app",
+        )
+
+
+class TestGoVersion(unittest.TestCase):
+    def _template(self, requires):
+        manifest = {"requires": requires}
+        return scaffold.Template("t", Path(tempfile.gettempdir()), manifest)
+
+    def test_newest_required(self):
+        templates = [
+            self._template({"go": "1.21"}),
+            self._template({"go": "1.9"}),
+            self._template({"go": "1.23.2"}),
+            self._template({"docker": ""}),
+        ]
+        self.assertEqual(golang.go_version(templates), "1.23.2")
+        self.assertEqual(
+            golang.go_version([self._template({})]),
+            golang.DEFAULT_GO_VERSION,
+        )
+
+    def test_in_go_mod_and_the_dockerfile(self):
+        base = scaffold.find_template("go/web")
+        docker = scaffold.find_addon("docker", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [docker], variables))
+        self.assertIn("\ngo 1.22\n", files["go.mod"])
+        self.assertIn("ARG GO_VERSION=1.22\n", files["Dockerfile"])
+        variables["GO_VERSION"] = "1.23"
+        files = dict(scaffold.render_project(base, [docker], variables))
+        self.assertIn("\ngo 1.23\n", files["go.mod"])
+        self.assertIn('GO_VERSION: "1.23"', files["compose.yaml"])
+
+
+class TestCmdLayout(unittest.TestCase):
+    def _render(self, name, cmds, runner=""):
+        variables = {
+            **scaffold.project_variables("demo"),
+            **scaffold.config_variables({}, "demo"),
+            "GO_CMDS": cmds,
+        }
+        if runner:
+            variables["TASK_RUNNER"] = runner
+        base = scaffold.find_template(name)
+        return dict(scaffold.render_project(base, [], variables))
+
+    def test_main_package_moves_to_the_first_command(self):
+        files = self._render("go/worker", "server,worker")
+        for rel in ("main.go", "http.go", "http_test.go"):
+            self.assertNotIn(rel, files)
+            self.assertIn(f"cmd/server/{rel}", files)
+        self.assertIn("internal/config/config.go", files)
+        self.assertIn("    go run ./cmd/server\n", files["justfile"])
+        self.assertIn(
+            'cmd = "go build -o ./tmp/main ./cmd/server"', files[".air.toml"]
+        )
+        self.assertIn("bin/\n", files[".gitignore"])
+
+    def test_other_commands_get_a_starter(self):
+        files = self._render("go/worker", "server,worker")
+        starter = files["cmd/worker/main.go"]
+        self.assertTrue(starter.startswith("// Command worker runs"))
+        self.assertIn('slog.Info("worker started")', starter)
+
+    def test_a_target_per_command(self):
+        makefile = self._render("go/api", "api,worker", "make")["Makefile"]
+        for line in (
+            "build-api:\n\tgo build -o bin/api ./cmd/api\n",
+            "run-worker:\n\tgo run ./cmd/worker\n",
+        ):
+            self.assertIn(line, makefile)
+
+    def test_flat_without_commands(self):
+        files = self._render("go/worker", "")
+        self.assertIn("main.go", files)
+        self.assertNotIn("cmd/", " ".join(files))
+        self.assertIn("    go run .\n", files["justfile"])
+
+    def test_embedding_main_package_raises(self):
+        files = {"main.go": "package main\n\n//go:embed x\nvar x string\n"}
+        with self.assertRaises(ValueError) as cm:
+            golang.split_cmds(files, {"GO_CMDS": "web"}, False)
+        self.assertIn("main.go embeds files", str(cm.exception))
+
+    def test_web_templates_embed_from_internal(self):
+        for name in ("go/web", "go/web-stdlib", "go/openapi"):
+            with self.subTest(name=name):
+                files = self._render(name, "server,worker")
+                self.assertIn("cmd/server/main.go", files)
+                self.assertIn("cmd/worker/main.go", files)
+                for rel, content in files.items():
+                    if rel.startswith("cmd/"):
+                        self.assertNotIn("//go:embed", content, rel)
+
+    def test_bad_names(self):
+        for name in ("Server", "1st", "a/b", ""):
+            with self.assertRaises(ValueError, msg=name):
+                golang.check_cmd(name)
+
+
+class TestNewModulePath(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, template, *argv, config=None):
+        args = jolo.parse_args(
+            ["new", template, "demo", "--git=false", "--license=none", *argv]
+        )
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": config or {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+        return Path(self.tmpdir) / "demo"
+
+    def test_module_path_in_go_mod_and_imports(self):
+        demo = self._new(
+            "go/web", "--var", "MODULE_PATH=github.com/ada/shop", "--no-input"
+        )
+        go_mod = (demo / "go.mod").read_text()
+        self.assertTrue(go_mod.startswith("module github.com/ada/shop\n"))
+        self.assertIn(
+            '"github.com/ada/shop/components"', (demo / "main.go").read_text()
+        )
+
+    def test_default_from_prefix(self):
+        demo = self._new(
+            "go", "--no-input", config={"module_prefix": "git.example.com/t/"}
+        )
+        self.assertIn(
+            "module git.example.com/t/demo", (demo / "go.mod").read_text()
+        )
+
+    def test_invalid_var_exits(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._new("go", "--var", "MODULE_PATH=My App", "--no-input")
+        self.assertIn("MODULE_PATH: 'My App'", cm.exception.code)
+        self.assertFalse((Path(self.tmpdir) / "demo").exists())
+
+    def test_asked_again_on_a_terminal(self):
+        answers = iter(["Bad Path", "", "ok"])
+        with (
+            mock.patch("sys.stdin.isatty", return_value=True),
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("sys.stderr", io.StringIO()) as err,
+        ):
+            demo = self._new("go")
+        self.assertIn("invalid character ' '", err.getvalue())
+        self.assertIn("module demo\n", (demo / "go.mod").read_text())
+
+    def test_not_asked_for_other_languages(self):
+        with (
+            mock.patch("sys.stdin.isatty", return_value=True),
+            mock.patch("builtins.input", return_value="") as ask,
+        ):
+            self._new("python", "--var", "MODULE_PATH=x y")
+        prompts = [c.args[0] for c in ask.call_args_list]
+        self.assertNotIn("Go module path", " ".join(prompts))
+
+    def test_cmd_is_kept_in_the_lock(self):
+        demo = self._new("go/api", "--cmd", "api", "--cmd", "jobs")
+        lock = scaffold.read_lock(demo)
+        self.assertEqual(lock["variables"]["GO_CMDS"], "api,jobs")
+        self.assertTrue((demo / "cmd/api/main.go").is_file())
+        self.assertTrue((demo / "cmd/jobs/main.go").is_file())
+
+    def test_web_with_commands(self):
+        demo = self._new(
+            "go/web",
+            "--cmd",
+            "server",
+            "--cmd",
+            "worker",
+            "--no-input",
+            "--no-install",
+        )
+        main = (demo / "cmd/server/main.go").read_text()
+        self.assertIn('"demo/internal/web"', main)
+        self.assertIn("web.Static", main)
+        self.assertFalse((demo / "main.go").exists())
+        self.assertTrue((demo / "cmd/worker/main.go").is_file())
+        self.assertIn(
+            "//go:embed all:static", (demo / "internal/web/web.go").read_text()
+        )
+
+    def test_cmd_needs_go(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._new("python", "--cmd", "x", "--no-input")
+        self.assertIn("not a Go template", cm.exception.code)
+
+    def test_api_checks_it(self):
+        template = scaffold.find_template("go")
+        with self.assertRaises(api.ScaffoldError):
+            api.render(template, "demo", {"MODULE_PATH": "a b"})
+
+
+if __name__ == "__main__":
+    unittest.main()
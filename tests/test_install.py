@@ -0,0 +1,116 @@
+#!/usr/bin/env python3
+"""Tests for the dependency install step of jolo new."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import install, scaffold
+
+
+def _template(run):
+    return scaffold.Template(
+        "t", Path(tempfile.gettempdir()), {"install": {"run": run}}
+    )
+
+
+class TestRun(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = Path(tempfile.mkdtemp())
+
+    def tearDown(self):
+        shutil.rmtree(self.tmpdir)
+
+    def _run(self, *groups):
+        with mock.patch("sys.stderr", io.StringIO()) as err:
+            steps = install.run(list(groups), self.tmpdir)
+        return steps, err.getvalue()
+
+    def test_commands_rendered_per_template(self):
+        templates = [_template(["echo {{PROJECT_NAME}}"]), _template([])]
+        self.assertEqual(
+            install.commands(templates, {"PROJECT_NAME": "demo"}),
+            [["echo demo"]],
+        )
+
+    def test_runs_in_the_project(self):
+        steps, err = self._run(["touch installed"])
+        self.assertTrue((self.tmpdir / "installed").exists())
+        self.assertEqual(steps[0].status, "ok")
+        self.assertIn("==> touch installed", err)
+        self.assertIn("Install: 1 ok", err)
+
+    def test_failure_stops_the_rest(self):
+        steps, err = self._run(["echo broken >&2; exit 3", "touch later"])
+        self.assertEqual([s.status for s in steps], ["fail", "pending"])
+        self.assertFalse((self.tmpdir / "later").exists())
+        self.assertIn("exit 3\n        broken", err)
+        self.assertIn("Install: 1 fail, 1 not run", err)
+        self.assertIn("Warning: install failed", err)
+
+    def test_missing_program_skips_its_template(self):
+        steps, err = self._run(["no-such-jolo-tool x", "true"], ["true"])
+        self.assertEqual([s.status for s in steps], ["skip", "skip", "ok"])
+        self.assertIn("(no-such-jolo-tool not installed)", err)
+
+    def test_program_past_assignments_and_keywords(self):
+        for cmd, program in (
+            ("GOOS=js GOARCH=wasm go vet ./...", "go"),
+            ('ASSETS="$(setup-envtest use -p path)" go test', "go"),
+            ("if command -v gradle; then gradle wrapper; fi", "command"),
+            ("! grep -q x y", "grep"),
+            ("A=1", None),
+        ):
+            with self.subTest(cmd=cmd):
+                self.assertEqual(install.program(cmd), program)
+        self.assertIsNone(install.missing_program(["GOOS=js true"]))
+        self.assertEqual(
+            install.missing_program(["GOOS=js no-such-jolo-tool"]),
+            "no-such-jolo-tool",
+        )
+
+
+class TestNewInstalls(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv, config=None):
+        args = jolo.parse_args(
+            ["new", "go", "demo", "--git=false", "--no-input", *argv]
+        )
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": config or {}}
+            ),
+            mock.patch.object(install, "run") as run,
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+        return run
+
+    def test_manifest_commands_run(self):
+        run = self._new()
+        run.assert_called_once_with(
+            [["go mod tidy"]], Path(self.tmpdir).resolve() / "demo"
+        )
+
+    def test_no_install_flag(self):
+        self._new("--no-install").assert_not_called()
+
+    def test_config_turns_it_off(self):
+        self._new(config={"install": False}).assert_not_called()
+
+
+if __name__ == "__main__":
+    unittest.main()
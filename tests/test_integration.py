@@ -393,8 +393,10 @@ class TestCreateModeFlavorIntegration(unittest.TestCase):
         self.assertTrue(app_py.exists())
         content = app_py.read_text()
         self.assertIn("FastAPI", content)
-        self.assertIn("Jinja2Templates", content)
+        self.assertIn("include_router(pages.router)", content)
         self.assertIn("pyinstrument", content)
+        pages = (project_path / "src" / "testproj" / "routers" / "pages.py")
+        self.assertIn("Jinja2Templates", pages.read_text())
         self.assertIn('request.query_params.get("profile")', content)
 
         # main.py with uvicorn
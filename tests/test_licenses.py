@@ -0,0 +1,140 @@
+#!/usr/bin/env python3
+"""Tests for LICENSE files and SPDX identifiers."""
+
+import io
+import json
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import licenses, scaffold
+
+
+class TestNames(unittest.TestCase):
+    def test_spdx_ids_and_aliases(self):
+        self.assertEqual(licenses.spdx("mit"), "MIT")
+        self.assertEqual(licenses.spdx("apache-2.0"), "Apache-2.0")
+        self.assertEqual(licenses.spdx("gpl-3.0"), "GPL-3.0-or-later")
+        self.assertEqual(licenses.spdx("NONE"), "none")
+        self.assertIsNone(licenses.spdx("Proprietary"))
+
+    def test_every_license_has_a_text(self):
+        for name in licenses.LICENSES:
+            if name != licenses.NONE:
+                path = licenses.LICENSES_DIR / f"{name}.txt"
+                self.assertTrue(path.is_file(), name)
+
+    def test_unknown_flag_value_exits(self):
+        with self.assertRaises(SystemExit) as cm:
+            licenses.parse_license_arg("wtfpl")
+        self.assertIn("Unknown license: wtfpl", cm.exception.code)
+
+
+class TestTagging(unittest.TestCase):
+    def test_go_mod_gets_a_comment_once(self):
+        tagged = licenses.tag_go_mod("module x\n", "MIT")
+        self.assertEqual(
+            tagged, "// SPDX-License-Identifier: MIT\nmodule x\n"
+        )
+        self.assertEqual(licenses.tag_go_mod(tagged, "ISC"), tagged)
+
+    def test_package_json_after_version(self):
+        text = json.dumps({"name": "x", "version": "1.0.0", "main": "i.js"})
+        data = json.loads(licenses.tag_package_json(text, "ISC"))
+        self.assertEqual(list(data), ["name", "version", "license", "main"])
+        self.assertEqual(data["license"], "ISC")
+        kept = json.dumps({"name": "x", "license": "MIT"})
+        self.assertEqual(licenses.tag_package_json(kept, "ISC"), kept)
+
+    def test_toml_table(self):
+        text = '[package]\nname = "x"\nversion = "0.1.0"\n\n[deps]\n'
+        self.assertEqual(
+            licenses.tag_toml(text, "package", "MIT"),
+            '[package]\nname = "x"\nversion = "0.1.0"\nlicense = "MIT"\n'
+            "\n[deps]\n",
+        )
+        kept = '[project]\nname = "x"\nlicense = "ISC"\n'
+        self.assertEqual(licenses.tag_toml(kept, "project", "MIT"), kept)
+        self.assertEqual(
+            licenses.tag_toml("a = 1\n", "project", "MIT"), "a = 1\n"
+        )
+
+    def test_apply_keeps_a_template_license(self):
+        files = [("COPYING", "mine\n"), ("go.mod", "module x\n")]
+        out = dict(licenses.apply(files, {"LICENSE": "MIT"}))
+        self.assertNotIn("LICENSE", out)
+        self.assertTrue(out["go.mod"].startswith("// SPDX"))
+
+    def test_apply_none_and_unknown(self):
+        files = [("go.mod", "module x\n")]
+        for name in ("none", "Proprietary"):
+            self.assertEqual(licenses.apply(files, {"LICENSE": name}), files)
+
+
+class TestNewLicense(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv, config=None):
+        args = jolo.parse_args(["new", "go", "demo", "--git=false", *argv])
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": config or {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+        return Path(self.tmpdir) / "demo"
+
+    def test_flag_writes_text_with_year_and_author(self):
+        demo = self._new(
+            "--license", "bsd-3-clause", "--no-input", config={"author": "Ada"}
+        )
+        text = (demo / "LICENSE").read_text()
+        self.assertTrue(text.startswith("BSD 3-Clause License"))
+        self.assertRegex(text, r"Copyright \(c\) \d{4}, Ada\n")
+        self.assertIn(
+            "SPDX-License-Identifier: BSD-3-Clause",
+            (demo / "go.mod").read_text(),
+        )
+        lock = scaffold.read_lock(demo)
+        self.assertEqual(lock["variables"]["LICENSE"], "BSD-3-Clause")
+
+    def test_flag_beats_config(self):
+        demo = self._new("--license", "none", config={"license": "MIT"})
+        self.assertFalse((demo / "LICENSE").exists())
+        self.assertNotIn("SPDX", (demo / "go.mod").read_text())
+
+    def test_var_and_config_are_normalised(self):
+        demo = self._new("--var", "LICENSE=apache")
+        self.assertIn("Apache License", (demo / "LICENSE").read_text())
+        shutil.rmtree(demo)
+        demo = self._new(config={"license": "isc"})
+        self.assertTrue((demo / "LICENSE").read_text().startswith("ISC"))
+
+    def test_prompts_on_a_terminal(self):
+        with (
+            mock.patch("sys.stdin.isatty", return_value=True),
+            mock.patch.object(
+                scaffold, "prompt_choice", return_value="Unlicense"
+            ) as prompt,
+        ):
+            demo = self._new(
+                "--var", "DESCRIPTION=demo", "--var", "MODULE_PATH=demo"
+            )
+        self.assertEqual(prompt.call_args.args[0], "License")
+        self.assertIn("public domain", (demo / "LICENSE").read_text())
+
+
+if __name__ == "__main__":
+    unittest.main()
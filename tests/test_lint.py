@@ -0,0 +1,238 @@
+#!/usr/bin/env python3
+"""Tests for jolo lint."""
+
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import lint, scaffold
+
+
+class TestLintTemplate(unittest.TestCase):
+    """Each check on a template written to a temp templates dir."""
+
+    def setUp(self):
+        self.root = Path(tempfile.mkdtemp())
+
+    def tearDown(self):
+        shutil.rmtree(self.root)
+
+    def _lint(self, files, name="t"):
+        files = {"tests/test_x.py": "", **files}
+        for rel, content in files.items():
+            path = self.root / name / rel
+            path.parent.mkdir(parents=True, exist_ok=True)
+            if isinstance(content, bytes):
+                path.write_bytes(content)
+            else:
+                path.write_text(content)
+        template = scaffold.find_template(name, self.root)
+        return [
+            (f.check, f.message, f.line)
+            for f in lint.lint_template(template)
+        ]
+
+    def test_clean(self):
+        findings = self._lint(
+            {
+                "template.toml": '[variables.db]\nchoices = ["pg", "none"]\ndefault = "pg"\n'
+                '[conditions]\n"db.sql" = "db == \'pg\'"\n',
+                "README.md": "# {{PROJECT_NAME}}\n{{ upper .PROJECT_NAME }}\n",
+                "db.sql": "-- {{db}}\n",
+            }
+        )
+        self.assertEqual(findings, [])
+
+    def test_manifest(self):
+        findings = self._lint(
+            {
+                "template.toml": 'descripton = "x"\ntags = "go"\n'
+                "[variables.db]\nchoises = []\ndefault = \"pg\"\n"
+                '[install]\nrun = "make"\n'
+                '[lint]\nignore = ["test"]\n',
+            }
+        )
+        self.assertEqual(
+            [m for c, m, _ in findings if c == "manifest"],
+            [
+                "unknown key descripton",
+                "tags must be a list",
+                "install.run must be a list of commands",
+                "variables.db: unknown key choises",
+                "lint.ignore: unknown check 'test'",
+            ],
+        )
+
+    def test_undeclared(self):
+        findings = self._lint(
+            {
+                "template.toml": "",
+                "a.txt": "{{PROJECT_NAME}}\n{{DB_URL}} {{args}}\n",
+                "b.just": '{{ partial "nope.just" }}\n',
+            }
+        )
+        self.assertEqual(
+            sorted(f for f in findings if f[0] == "undeclared"),
+            [
+                ("undeclared", "no partial nope.just in _partials/", 1),
+                ("undeclared", "{{DB_URL}} is not a declared variable", 2),
+            ],
+        )
+
+    def test_unused(self):
+        findings = self._lint(
+            {
+                "template.toml": "[variables.port]\ndefault = \"8080\"\n"
+                "[variables.name]\ndefault = \"x\"\n"
+                "[computed]\nLABEL = 'name + \"!\"'\n",
+                "a.txt": "{{LABEL}}\n",
+            }
+        )
+        self.assertEqual(
+            findings, [("unused", "variable port is never used", 0)]
+        )
+
+    def test_unreachable(self):
+        findings = self._lint(
+            {
+                "template.toml": '[variables.db]\nchoices = ["pg", "none"]\ndefault = "pg"\n'
+                "[conditions]\n"
+                '"gone.sql" = "db == \'pg\'"\n'
+                '"db.sql" = "db == \'mysql\'"\n'
+                '"cache/" = "cache"\n',
+                "db.sql": "{{db}}\n",
+                "cache/a.txt": "",
+            }
+        )
+        self.assertEqual(
+            [m for c, m, _ in findings if c == "unreachable"],
+            [
+                "conditions.'gone.sql' matches no file",
+                "conditions.'db.sql' can never hold: "
+                "mysql is not one of db's pg, none",
+                "conditions.'cache/' can never hold: unknown variable cache",
+            ],
+        )
+
+    def test_no_tests(self):
+        path = self.root / "t"
+        path.mkdir()
+        (path / "template.toml").write_text("")
+        (path / "main.go").write_text("package main\n")
+        template = scaffold.find_template("t", self.root)
+        self.assertEqual(
+            [f.check for f in lint.lint_template(template)], ["tests"]
+        )
+        (path / "lib.rs").write_text("#[cfg(test)]\nmod tests {}\n")
+        self.assertEqual(lint.lint_template(template), [])
+
+    def test_binary(self):
+        png = b"\x89PNG\r\n\x1a\n\x00\x00"
+        findings = self._lint({"template.toml": "", "logo.png": png})
+        self.assertEqual([c for c, _, _ in findings], ["binary"])
+        findings = self._lint(
+            {"template.toml": 'binary = ["*.png"]\n', "logo.png": png},
+            name="u",
+        )
+        self.assertEqual(findings, [])
+
+    def test_ignore(self):
+        findings = self._lint(
+            {
+                "template.toml": '[lint]\nignore = ["undeclared"]\n',
+                "a.txt": "{{DB_URL}}\n",
+            }
+        )
+        self.assertEqual(findings, [])
+
+    def test_test_files(self):
+        for rel in (
+            "main_test.go",
+            "tests/test_app.py",
+            "src/app.test.ts",
+            "spec/app_spec.rb",
+            "src/AppTest.java",
+            "t/basic.t",
+            "tests/main.tftest.hcl",
+        ):
+            with self.subTest(rel=rel):
+                self.assertTrue(lint.is_test(rel))
+        self.assertFalse(lint.is_test("src/contest.go"))
+
+
+class TestRun(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _run(self, *argv):
+        args = jolo.parse_args(["lint", *argv])
+        with (
+            mock.patch.object(lint, "load_config", return_value={}),
+            mock.patch("builtins.print") as fake_print,
+        ):
+            lint.run_lint_mode(args)
+        return [str(c.args[0]) for c in fake_print.call_args_list if c.args]
+
+    def _write(self, rel, content):
+        path = Path(self.tmpdir) / rel
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content)
+
+    def test_builtin_templates_are_clean(self):
+        out = self._run("--all")
+        self.assertRegex(out[-1], r"^\d+ templates, no findings$")
+
+    def test_findings_exit_1(self):
+        self._write("team/svc/template.toml", "")
+        self._write("team/svc/a.txt", "{{DB_URL}}\n")
+        with self.assertRaises(SystemExit) as cm:
+            self._run("svc", "--templates-dir", "team")
+        # The undeclared placeholder, and no tests.
+        self.assertEqual(str(cm.exception), "2 findings in 1 template")
+
+    def test_printed_relative_with_lines(self):
+        self._write("team/svc/template.toml", "")
+        self._write("team/svc/tests/test_a.py", "\n{{DB_URL}}\n")
+        args = jolo.parse_args(["lint", "team/svc"])
+        with (
+            mock.patch.object(lint, "load_config", return_value={}),
+            mock.patch("builtins.print") as fake_print,
+            self.assertRaises(SystemExit),
+        ):
+            lint.run_lint_mode(args)
+        self.assertEqual(
+            str(fake_print.call_args.args[0]),
+            "team/svc/tests/test_a.py:2: {{DB_URL}} is not a declared "
+            "variable [undeclared]",
+        )
+
+    def test_the_current_directory(self):
+        self._write("svc/template.toml", "")
+        self._write("svc/tests/test_a.py", "")
+        os.chdir("svc")
+        self.assertEqual(self._run(), ["1 template, no findings"])
+
+    def test_a_bad_manifest_is_a_finding(self):
+        self._write("team/svc/template.toml", 'extends = "nope"\n')
+        with self.assertRaises(SystemExit) as cm:
+            self._run("team/svc")
+        self.assertEqual(str(cm.exception), "1 findings in 1 template")
+
+    def test_no_manifest_here(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._run()
+        self.assertIn("no template.toml here", str(cm.exception))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,82 @@
+#!/usr/bin/env python3
+"""Tests for logging, verbosity and colour (_jolo.log)."""
+
+import io
+import os
+import unittest
+from unittest import mock
+
+import jolo
+from _jolo import log
+from _jolo.cli import verbose_print
+
+
+class _Tty(io.StringIO):
+    def isatty(self):
+        return True
+
+
+class TestLog(unittest.TestCase):
+    def tearDown(self):
+        log.setup()
+
+    def _err(self, fn, stream=None):
+        stream = stream or io.StringIO()
+        with mock.patch("sys.stderr", stream):
+            fn()
+        return stream.getvalue()
+
+    def test_levels(self):
+        def say():
+            log.debug("d")
+            log.info("i")
+            log.warning("w")
+            log.error("e")
+
+        self.assertEqual(self._err(say), "i\nWarning: w\nError: e\n")
+        log.setup(1)
+        self.assertEqual(
+            self._err(say), "[verbose] d\ni\nWarning: w\nError: e\n"
+        )
+        log.setup(-1)
+        self.assertEqual(self._err(say), "Warning: w\nError: e\n")
+
+    def test_fields(self):
+        err = self._err(lambda: log.info("wrote", path="a b", n=3, x=""))
+        self.assertEqual(err, 'wrote path="a b" n=3 x=""\n')
+
+    def test_color(self):
+        with mock.patch.dict(os.environ, {"TERM": "xterm"}, clear=True):
+            err = self._err(lambda: log.warning("w"), _Tty())
+            self.assertEqual(err, "\033[33mWarning: w\033[0m\n")
+            self.assertEqual(self._err(lambda: log.info("i"), _Tty()), "i\n")
+            self.assertFalse(log.color(io.StringIO()))
+            log.setup(plain=True)
+            self.assertFalse(log.color(_Tty()))
+        for env in ({"NO_COLOR": "1"}, {"TERM": "dumb"}):
+            with mock.patch.dict(os.environ, env, clear=True):
+                log.setup()
+                self.assertFalse(log.color(_Tty()))
+
+    def test_summary(self):
+        with mock.patch("sys.stdout", io.StringIO()) as out:
+            log.summary("Created x")
+            log.setup(-1)
+            log.summary("Created y")
+        self.assertEqual(out.getvalue(), "Created x\n")
+
+    def test_flags(self):
+        args = jolo.parse_args(["new", "go", "x", "-q", "--plain"])
+        self.assertEqual((args.quiet, args.plain, args.verbose), (1, 1, 0))
+        self.assertFalse(jolo.parse_args(["completion", "bash"]).quiet)
+
+    def test_verbose_print(self):
+        self.assertEqual(self._err(lambda: verbose_print("x")), "")
+        log.setup(1)
+        self.assertEqual(
+            self._err(lambda: verbose_print("x")), "[verbose] x\n"
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,140 @@
+#!/usr/bin/env python3
+"""Tests for the jolo mcp server."""
+
+import io
+import json
+import tempfile
+import unittest
+from pathlib import Path
+
+from _jolo import mcp, scaffold
+
+
+class TestMcpServer(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        root = Path(self.tmpdir.name)
+        self.templates = root / "templates"
+        path = self.templates / "svc"
+        path.mkdir(parents=True)
+        (path / scaffold.MANIFEST_FILE).write_text(
+            'description = "A service"\ntags = ["api"]\n'
+            '[variables.port]\ntype = "int"\ndefault = 8000\n'
+        )
+        (path / "app.txt").write_text("{{PROJECT_NAME}}:{{port}}\n")
+        self.workspace = root / "work"
+        self.workspace.mkdir()
+        self.server = mcp.Server(self.workspace, [str(self.templates)])
+        self.ids = 0
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _request(self, method, params=None):
+        self.ids += 1
+        message = {"jsonrpc": "2.0", "id": self.ids, "method": method}
+        if params is not None:
+            message["params"] = params
+        response = self.server.handle(message)
+        self.assertEqual(response["id"], self.ids)
+        return response
+
+    def _call(self, tool, **arguments):
+        response = self._request(
+            "tools/call", {"name": tool, "arguments": arguments}
+        )
+        return response["result"]
+
+    def test_initialize_and_tools_list(self):
+        result = self._request(
+            "initialize", {"protocolVersion": "2025-03-26"}
+        )["result"]
+        self.assertEqual(result["protocolVersion"], "2025-03-26")
+        self.assertIn("tools", result["capabilities"])
+        self.assertIsNone(
+            self.server.handle(
+                {"jsonrpc": "2.0", "method": "notifications/initialized"}
+            )
+        )
+        tools = self._request("tools/list")["result"]["tools"]
+        self.assertEqual(
+            [t["name"] for t in tools],
+            ["list_templates", "describe_template", "generate_project"],
+        )
+        for tool in tools:
+            self.assertEqual(tool["inputSchema"]["type"], "object")
+
+    def test_list_templates(self):
+        result = self._call("list_templates", query="service")
+        names = [t["name"] for t in result["structuredContent"]["templates"]]
+        self.assertEqual(names, ["svc"])
+        text = json.loads(result["content"][0]["text"])
+        self.assertEqual(text, result["structuredContent"])
+
+        result = self._call("list_templates", tag="htmx")
+        names = [t["name"] for t in result["structuredContent"]["templates"]]
+        self.assertIn("go/web", names)
+
+    def test_describe_template(self):
+        result = self._call("describe_template", template="go/web")
+        self.assertFalse(result["isError"])
+        self.assertIn("docker", result["structuredContent"]["addons"])
+
+        result = self._call("describe_template", template="svc")
+        port = result["structuredContent"]["variables"]["port"]
+        self.assertEqual((port["type"], port["default"]), ("int", 8000))
+
+    def test_generate_project(self):
+        result = self._call(
+            "generate_project",
+            template="svc",
+            name="shop",
+            variables={"port": 9000},
+        )
+        self.assertFalse(result["isError"], result)
+        data = result["structuredContent"]
+        self.assertEqual(data["path"], str(self.workspace / "shop"))
+        self.assertEqual(
+            data["files"],
+            ["LICENSE", "README.md", "app.txt", scaffold.LOCK_FILE],
+        )
+        self.assertEqual(
+            (self.workspace / "shop" / "app.txt").read_text(), "shop:9000\n"
+        )
+
+        again = self._call("generate_project", template="svc", name="shop")
+        self.assertTrue(again["isError"])
+        self.assertIn("not empty", again["content"][0]["text"])
+
+    def test_tool_errors_are_results(self):
+        for arguments, text in [
+            ({"template": "svc", "name": "../out"}, "outside the workspace"),
+            ({"template": "nope", "name": "x"}, "Unknown template"),
+            ({"template": "svc"}, "bad arguments"),
+        ]:
+            with self.subTest(arguments=arguments):
+                result = self._call("generate_project", **arguments)
+                self.assertTrue(result["isError"])
+                self.assertIn(text, result["content"][0]["text"])
+        self.assertFalse((self.workspace.parent / "out").exists())
+
+    def test_protocol_errors(self):
+        unknown = self._request("tools/call", {"name": "rm_rf"})
+        self.assertEqual(unknown["error"]["code"], -32602)
+        self.assertEqual(self._request("nope")["error"]["code"], -32601)
+
+    def test_serve_reads_lines(self):
+        stdin = io.StringIO(
+            '{"jsonrpc": "2.0", "id": 1, "method": "ping"}\n'
+            "\n"
+            "not json\n"
+        )
+        stdout = io.StringIO()
+        self.server.serve(stdin, stdout)
+        lines = [json.loads(line) for line in stdout.getvalue().splitlines()]
+        self.assertEqual(lines[0], {"jsonrpc": "2.0", "id": 1, "result": {}})
+        self.assertEqual(lines[1]["error"]["code"], -32700)
+
+
+if __name__ == "__main__":
+    unittest.main()
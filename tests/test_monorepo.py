@@ -0,0 +1,153 @@
+#!/usr/bin/env python3
+"""Tests for jolo new into an existing repository."""
+
+import io
+import os
+import shutil
+import subprocess
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import monorepo, scaffold
+
+TASKFILE = """version: '3'
+
+tasks:
+  lint:
+    cmds:
+      - golangci-lint run
+
+vars:
+  X: y
+"""
+
+
+class TestGoWork(unittest.TestCase):
+    def setUp(self):
+        self.root = Path(tempfile.mkdtemp())
+
+    def tearDown(self):
+        shutil.rmtree(self.root)
+
+    def _module(self, rel):
+        (self.root / rel).mkdir(parents=True)
+        (self.root / rel / "go.mod").write_text(f"module {rel}\n\ngo 1.22\n")
+        return self.root / rel
+
+    def test_created_with_every_module(self):
+        self._module("libs/auth")
+        self._module("web/node_modules/x")
+        api = self._module("services/api")
+        monorepo.add_to_go_work(self.root, api)
+        self.assertEqual(
+            (self.root / "go.work").read_text(),
+            "go 1.22\n\nuse (\n\t./libs/auth\n\t./services/api\n)\n",
+        )
+
+    def test_added_to_a_use_block(self):
+        (self.root / "go.work").write_text("go 1.22\n\nuse (\n\t./a\n)\n")
+        monorepo.add_to_go_work(self.root, self._module("b"))
+        self.assertEqual(
+            monorepo.used_modules((self.root / "go.work").read_text()),
+            ["./a", "./b"],
+        )
+
+    def test_added_after_use_lines(self):
+        (self.root / "go.work").write_text("go 1.22\n\nuse ./a\n")
+        monorepo.add_to_go_work(self.root, self._module("b"))
+        text = (self.root / "go.work").read_text()
+        self.assertTrue(text.endswith("use ./a\n\nuse ./b\n"))
+
+    def test_already_used(self):
+        (self.root / "go.work").write_text("go 1.22\n\nuse b\n")
+        self.assertIsNone(
+            monorepo.add_to_go_work(self.root, self._module("b"))
+        )
+
+
+class TestRegister(unittest.TestCase):
+    def setUp(self):
+        self.root = Path(tempfile.mkdtemp())
+        self.api = self.root / "services" / "api"
+
+    def tearDown(self):
+        shutil.rmtree(self.root)
+
+    def test_taskfile(self):
+        (self.root / "Taskfile.yml").write_text(TASKFILE)
+        path, text = monorepo.registration(self.root, self.api)
+        self.assertEqual(path.name, "Taskfile.yml")
+        self.assertIn(
+            "      - golangci-lint run\n  api:\n"
+            "    desc: just recipes of services/api (task api -- RECIPE)\n"
+            "    dir: services/api\n"
+            "    cmds:\n      - just {{.CLI_ARGS}}\n\nvars:\n",
+            text,
+        )
+
+    def test_makefile(self):
+        (self.root / "Makefile").write_text("all:\n\tgo build ./...\n")
+        _, text = monorepo.registration(self.root, self.api)
+        self.assertIn("api/%:\n\tcd services/api && just $*\n", text)
+
+    def test_taken_name_exits(self):
+        (self.root / "Makefile").write_text("api:\n\ttrue\n")
+        with self.assertRaises(SystemExit) as cm:
+            monorepo.registration(self.root, self.api)
+        self.assertIn("already has a target api", cm.exception.code)
+
+    def test_nothing_to_register_in_exits(self):
+        with self.assertRaises(SystemExit) as cm:
+            monorepo.registration(self.root, self.api)
+        self.assertIn("no Taskfile.yml", cm.exception.code)
+
+
+class TestNewInRepo(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        subprocess.run(["git", "init", "-q"], check=True)
+        Path("Makefile").write_text("all:\n")
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv):
+        args = jolo.parse_args(
+            ["new", *argv, "--no-input", "--license=none", "--no-verify"]
+        )
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()) as out,
+        ):
+            scaffold.run_new_mode(args)
+        return out.getvalue()
+
+    def test_go_module_joins_the_workspace(self):
+        out = self._new("go", "services/api", "--register")
+        root = Path(self.tmpdir)
+        self.assertFalse((root / "services/api/.git").exists())
+        self.assertIn("\t./services/api\n", (root / "go.work").read_text())
+        self.assertIn("api/%:", (root / "Makefile").read_text())
+        self.assertIn("registered api in", out)
+
+    def test_register_checked_before_writing(self):
+        os.remove("Makefile")
+        with self.assertRaises(SystemExit):
+            self._new("python", "tools/gen", "--register")
+        self.assertFalse(Path("tools").exists())
+
+    def test_no_go_work_for_other_languages(self):
+        self._new("python", "tools/gen")
+        self.assertFalse(Path("go.work").exists())
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,89 @@
+#!/usr/bin/env python3
+"""Tests for the file worker pool (_jolo.parallel)."""
+
+import os
+import tempfile
+import threading
+import unittest
+from pathlib import Path
+from unittest import mock
+
+from _jolo import constants, parallel, scaffold
+
+
+def _jobs(n):
+    return mock.patch.object(constants, "JOBS", n)
+
+
+class TestPmap(unittest.TestCase):
+    def test_jobs(self):
+        with _jobs(0), mock.patch.dict(os.environ, {"JOLO_JOBS": "3"}):
+            self.assertEqual(parallel.jobs(), 3)
+            with _jobs(7):
+                self.assertEqual(parallel.jobs(), 7)
+        with _jobs(0), mock.patch.dict(os.environ, {"JOLO_JOBS": "x"}):
+            self.assertGreaterEqual(parallel.jobs(), 5)
+
+    def test_order_kept_and_threads_used(self):
+        seen = set()
+        barrier = threading.Barrier(4, timeout=5)
+
+        def work(n):
+            seen.add(threading.get_ident())
+            if n < 4:
+                barrier.wait()  # only passes if 4 run at once
+            return n * n
+
+        with _jobs(4):
+            squares = parallel.pmap(work, range(40))
+        self.assertEqual(squares, [n * n for n in range(40)])
+        self.assertGreaterEqual(len(seen), 4)
+
+    def test_one_job_stays_on_this_thread(self):
+        with _jobs(1):
+            idents = parallel.pmap(lambda _: threading.get_ident(), range(40))
+        self.assertEqual(set(idents), {threading.get_ident()})
+
+    def test_first_failure_in_order_raises(self):
+        def work(n):
+            if n in (30, 20):
+                raise SystemExit(f"Error: {n}")
+            return n
+
+        with _jobs(8), self.assertRaises(SystemExit) as cm:
+            parallel.pmap(work, range(40))
+        self.assertEqual(cm.exception.code, "Error: 20")
+
+
+class TestParallelRender(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        path = Path(self.tmpdir.name, "big")
+        path.mkdir()
+        (path / scaffold.MANIFEST_FILE).write_text('description = "big"\n')
+        for i in range(60):
+            sub = path / f"d{i % 5}"
+            sub.mkdir(exist_ok=True)
+            (sub / f"f{i}.txt").write_text(f"{i} {{{{PROJECT_NAME}}}}\n")
+        (path / "logo.bin").write_bytes(b"\x00\x01")
+        self.template = scaffold.load_template(path, "big")
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_same_files_as_in_order(self):
+        variables = scaffold.project_variables("app")
+        with _jobs(1):
+            expected = scaffold.render_template(self.template, variables)
+        with _jobs(8):
+            files = scaffold.render_template(self.template, variables)
+            out = Path(self.tmpdir.name, "out")
+            scaffold.write_files(files, out)
+        self.assertEqual(files, expected)
+        self.assertEqual(Path(out, "d3", "f8.txt").read_text(), "8 app\n")
+        self.assertEqual(Path(out, "logo.bin").read_bytes(), b"\x00\x01")
+        self.assertEqual(len([p for p in out.rglob("*") if p.is_file()]), 61)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,188 @@
+#!/usr/bin/env python3
+"""Tests for line endings, Windows paths and symlinks (_jolo.portable)."""
+
+import io
+import os
+import shutil
+import tarfile
+import tempfile
+import tomllib
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import portable, scaffold, streaming, upgrade
+from _jolo.writers import DirWriter, TarWriter
+
+
+class TestLineEndings(unittest.TestCase):
+    def test_convert(self):
+        self.assertEqual(portable.convert("a\r\nb\nc", "crlf"), "a\r\nb\r\nc")
+        self.assertEqual(portable.convert("a\r\nb\n", "lf"), "a\nb\n")
+
+    def test_scripts_stay_lf(self):
+        files = portable.apply_eol(
+            [
+                ("a.md", "x\n"),
+                ("justfile", "run:\n"),
+                ("bin/run", "#!/bin/sh\n"),
+                ("logo.png", b"\n"),
+            ],
+            "crlf",
+        )
+        self.assertEqual(
+            files,
+            [
+                ("a.md", "x\r\n"),
+                ("justfile", "run:\n"),
+                ("bin/run", "#!/bin/sh\n"),
+                ("logo.png", b"\n"),
+            ],
+        )
+
+    def test_cr_at_a_chunk_boundary(self):
+        chunks = ["a\r", "\nb\r", "", "\n", "c\n"]
+        self.assertEqual(
+            "".join(portable.convert_chunks(chunks, "lf")), "a\nb\nc\n"
+        )
+        self.assertEqual(
+            "".join(portable.convert_chunks(["a\r", "\n"], "crlf")), "a\r\n"
+        )
+
+    def test_streamed(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            src = Path(tmp, "big.txt")
+            src.write_bytes(b"{{PROJECT_NAME}}\r\nb\n" * 100)
+            with mock.patch.object(streaming, "CHUNK", 3):
+                [(_, streamed)] = portable.apply_eol(
+                    [("big.txt", streaming.Streamed(src, {}))], "crlf"
+                )
+                self.assertEqual(
+                    streamed.read(), "{{PROJECT_NAME}}\r\nb\r\n" * 100
+                )
+
+    def test_resolve(self):
+        self.assertEqual(portable.resolve_eol("crlf"), "crlf")
+        with mock.patch.object(os, "linesep", "\r\n"):
+            self.assertEqual(portable.resolve_eol("native"), "crlf")
+        with self.assertRaises(SystemExit) as cm:
+            portable.resolve_eol("cr")
+        self.assertIn("Unknown eol 'cr'", cm.exception.code)
+
+
+class TestPaths(unittest.TestCase):
+    def test_windows_problems(self):
+        for path, problem in (
+            ("src/aux.go", "reserved name"),
+            ("CON", "reserved name"),
+            ("a/b:c", "':'"),
+            ("notes.", "ends in '.'"),
+        ):
+            self.assertIn(problem, portable.windows_problem(path), path)
+        self.assertIsNone(portable.windows_problem("src/auxiliary.go"))
+
+    def test_render_path(self):
+        with self.assertRaises(ValueError):
+            scaffold.render_path("{{X}}.txt", {"X": "a\\b"})
+        with mock.patch.object(scaffold.os, "name", "nt"):
+            with self.assertRaises(ValueError) as cm:
+                scaffold.render_path("{{X}}.go", {"X": "nul"})
+        self.assertIn("reserved name", str(cm.exception))
+        self.assertEqual(scaffold.render_path("nul.go", {}), "nul.go")
+
+    def test_text_kept_byte_for_byte(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            path = Path(tmp, "a.txt")
+            portable.write_text(path, "é\r\n")
+            self.assertEqual(path.read_bytes(), "é\r\n".encode())
+            self.assertEqual(portable.read_text(path), "é\r\n")
+
+
+class TestNew(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        tpl = Path(self.tmpdir, "templates", "t")
+        (tpl / "docs").mkdir(parents=True)
+        (tpl / "template.toml").write_text('description = "t"\n')
+        (tpl / "README.md").write_text("# {{PROJECT_NAME}}\n\nhi\n")
+        (tpl / "justfile").write_text("run:\n    echo\n")
+        (tpl / "docs" / "README.md").symlink_to("../README.md")
+        (tpl / "outside").symlink_to(Path(self.tmpdir, "templates"))
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv):
+        args = jolo.parse_args(
+            [
+                "new",
+                "t",
+                "demo",
+                "--templates-dir",
+                "templates",
+                "--no-input",
+                "--git=false",
+                "--license=none",
+                *argv,
+            ]
+        )
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+        return Path(self.tmpdir, "demo")
+
+    def test_eol(self):
+        demo = self._new("--eol", "crlf")
+        self.assertEqual(
+            (demo / "README.md").read_bytes(), b"# demo\r\n\r\nhi\r\n"
+        )
+        self.assertEqual((demo / "justfile").read_bytes(), b"run:\n    echo\n")
+        lock = tomllib.loads((demo / scaffold.LOCK_FILE).read_text())
+        self.assertEqual(lock["eol"], "crlf")
+        template = scaffold.load_template(
+            Path(self.tmpdir, "templates", "t"), "t"
+        )
+        files, _, _ = upgrade._render(template, Path(), lock, True)
+        self.assertEqual(files["README.md"], "# demo\r\n\r\nhi\r\n")
+
+    def test_symlinks_kept(self):
+        demo = self._new()
+        link = demo / "docs" / "README.md"
+        self.assertTrue(link.is_symlink())
+        self.assertEqual(os.readlink(link), "../README.md")
+        self.assertEqual(link.read_text(), "# demo\n\nhi\n")
+        self.assertFalse((demo / "outside").exists())
+
+    def test_copied_where_links_fail(self):
+        with mock.patch("os.symlink", side_effect=OSError("privilege")):
+            demo = self._new()
+        link = demo / "docs" / "README.md"
+        self.assertFalse(link.is_symlink())
+        self.assertEqual(link.read_text(), "# demo\n\nhi\n")
+
+    def test_archives(self):
+        link = portable.Link("../README.md", "text\n")
+        buf = io.BytesIO()
+        with TarWriter(buf, "p") as w:
+            w.write("docs/README.md", link)
+        buf.seek(0)
+        member = tarfile.open(fileobj=buf).getmember("p/docs/README.md")
+        self.assertEqual(
+            (member.issym(), member.linkname), (True, "../README.md")
+        )
+        out = Path(self.tmpdir, "out")
+        DirWriter(out).write("a.md", "old\n")
+        DirWriter(out).write("a.md", portable.Link("b.md", "x"))
+        self.assertEqual(os.readlink(out / "a.md"), "b.md")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,130 @@
+#!/usr/bin/env python3
+"""Tests for generated README files."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import readme, scaffold
+
+JUSTFILE = """set shell := ["bash", "-c"]
+
+# Run tests
+test:
+    go test ./...
+
+# Run with auto-reload
+dev:
+    air
+"""
+
+
+def _template(manifest=None):
+    return scaffold.Template("t", Path(tempfile.gettempdir()), manifest or {})
+
+
+class TestGenerate(unittest.TestCase):
+    def variables(self, **extra):
+        return {
+            **scaffold.project_variables("shop"),
+            "LICENSE": "MIT",
+            **extra,
+        }
+
+    def test_quickstart_from_the_justfile(self):
+        files = {"justfile": JUSTFILE}
+        self.assertEqual(
+            readme.quickstart([_template()], files),
+            [("just dev", "Run with auto-reload"), ("just test", "Run tests")],
+        )
+
+    def test_manifest_quickstart_wins(self):
+        template = _template({"readme": {"quickstart": ["make"]}})
+        files = {"justfile": JUSTFILE}
+        self.assertEqual(readme.quickstart([template], files), [("make", "")])
+
+    def test_layout_dirs_first_with_descriptions(self):
+        template = _template({"readme": {"layout": {"cmd/": "binaries"}}})
+        files = {"main.go": "", "cmd/x/main.go": "", "src/a": "", ".env": ""}
+        self.assertEqual(
+            readme.layout([template], files),
+            [("cmd/", "binaries"), ("src/", "source code"), ("main.go", "")],
+        )
+
+    def test_full_readme(self):
+        addon = _template(
+            {
+                "readme": {
+                    "sections": [
+                        {"title": "Database", "body": "{{MODULE_NAME}}.db\n"}
+                    ]
+                }
+            }
+        )
+        files = {"justfile": JUSTFILE, "LICENSE": "..."}
+        text = readme.generate(
+            [_template(), addon],
+            files,
+            self.variables(DESCRIPTION="Sells things."),
+        )
+        self.assertTrue(text.startswith("# shop\n\nSells things.\n"))
+        self.assertIn("```sh\njust dev   # Run with auto-reload\n", text)
+        self.assertIn("## Database\n\nshop.db\n", text)
+        self.assertIn("- `justfile`: tasks", text)
+        self.assertTrue(text.endswith("## License\n\nMIT, see LICENSE.\n"))
+
+    def test_template_readme_is_kept(self):
+        files = [("README.org", "* mine\n")]
+        self.assertEqual(
+            readme.apply(files, [_template()], self.variables()), files
+        )
+
+
+class TestDescription(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv):
+        args = jolo.parse_args(
+            ["new", "go", "demo", "--git=false", "--license=mit", *argv]
+        )
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+        return (Path(self.tmpdir) / "demo" / readme.FILE).read_text()
+
+    def test_prompted_on_a_terminal(self):
+        with (
+            mock.patch("sys.stdin.isatty", return_value=True),
+            mock.patch("builtins.input", return_value="A greeter") as ask,
+        ):
+            text = self._new("--var", "MODULE_PATH=demo")
+        self.assertEqual(ask.call_args.args[0], "Project description: ")
+        self.assertTrue(text.startswith("# demo\n\nA greeter\n"))
+
+    def test_var_answers_it(self):
+        text = self._new("--var", "DESCRIPTION=Says hi", "--no-input")
+        self.assertTrue(text.startswith("# demo\n\nSays hi\n"))
+
+    def test_no_input_leaves_it_out(self):
+        text = self._new("--no-input")
+        self.assertTrue(text.startswith("# demo\n\n## Quickstart\n"))
+
+
+if __name__ == "__main__":
+    unittest.main()
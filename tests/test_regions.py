@@ -0,0 +1,74 @@
+#!/usr/bin/env python3
+"""Tests for protected regions (jolo:keep-start / jolo:keep-end)."""
+
+import unittest
+
+from _jolo import regions
+
+GENERATED = """package main
+
+// jolo:keep-start imports
+import "fmt"
+// jolo:keep-end
+
+func main() {
+	// jolo:keep-start
+	fmt.Println("hi")
+	// jolo:keep-end
+}
+"""
+
+
+class TestPreserve(unittest.TestCase):
+    def test_regions_by_name_and_position(self):
+        self.assertEqual(
+            regions.regions(GENERATED),
+            {"imports": 'import "fmt"\n', "#1": '\tfmt.Println("hi")\n'},
+        )
+
+    def test_current_lines_win(self):
+        current = GENERATED.replace('"fmt"', '"fmt"\nimport "os"').replace(
+            '"hi"', '"mine"'
+        )
+        fresh = GENERATED.replace("package main", "package main // v2")
+        merged = regions.preserve(fresh, current)
+        self.assertTrue(merged.startswith("package main // v2\n"))
+        self.assertIn('import "fmt"\nimport "os"\n', merged)
+        self.assertIn('fmt.Println("mine")', merged)
+
+    def test_new_regions_keep_the_generated_lines(self):
+        current = "x\n# jolo:keep-start a\nmine\n# jolo:keep-end\n"
+        generated = current.replace("mine", "gen") + (
+            "# jolo:keep-start b\nnew\n# jolo:keep-end\n"
+        )
+        self.assertEqual(
+            regions.preserve(generated, current),
+            "x\n# jolo:keep-start a\nmine\n# jolo:keep-end\n"
+            "# jolo:keep-start b\nnew\n# jolo:keep-end\n",
+        )
+        self.assertEqual(regions.dropped("y\n", current), ["a"])
+
+    def test_no_regions_is_a_plain_overwrite(self):
+        self.assertEqual(regions.preserve("new\n", "old\n"), "new\n")
+
+    def test_bad_markers(self):
+        for text, why in (
+            ("// jolo:keep-start\n", "never ends"),
+            ("// jolo:keep-end\n", "without start"),
+            (
+                "# jolo:keep-start\n# jolo:keep-start\n# jolo:keep-end\n",
+                "inside the region from line 1",
+            ),
+            (
+                "# jolo:keep-start a\n# jolo:keep-end\n"
+                "# jolo:keep-start a\n# jolo:keep-end\n",
+                "line 3: second region a",
+            ),
+        ):
+            with self.assertRaises(ValueError, msg=text) as cm:
+                regions.regions(text)
+            self.assertIn(why, str(cm.exception))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,3004 @@
+#!/usr/bin/env python3
+"""Tests for jolo new (template discovery, rendering, prompts)."""
+
+import base64
+import datetime
+import io
+import json
+import os
+import subprocess
+import sys
+import tarfile
+import tempfile
+import tomllib
+import unittest
+import zipfile
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import golang, placeholders, remote, scaffold, semver
+
+
+def _write_template(
+    root: Path, name: str, files: dict[str, str], manifest: str = ""
+) -> Path:
+    path = root / name
+    path.mkdir(parents=True)
+    (path / scaffold.MANIFEST_FILE).write_text(
+        'description = "test"\n' + manifest
+    )
+    for rel, content in files.items():
+        (path / rel).parent.mkdir(parents=True, exist_ok=True)
+        (path / rel).write_text(content)
+    return path
+
+
+class TestNewArgParsing(unittest.TestCase):
+    def test_new_with_template_and_name(self):
+        args = jolo.parse_args(["new", "go/web", "myapp"])
+        self.assertEqual(args.command, "new")
+        self.assertEqual(args.template, "go/web")
+        self.assertEqual(args.name, "myapp")
+        self.assertFalse(args.no_input)
+
+    def test_new_without_args(self):
+        args = jolo.parse_args(["new"])
+        self.assertIsNone(args.template)
+        self.assertIsNone(args.name)
+
+
+# Answers beyond the project name for the rows below.
+_ADA = {
+    "AUTHOR": "Ada Lovelace",
+    "EMAIL": "ada@example.com",
+    "LICENSE": "ISC",
+    "DESCRIPTION": "Greet people",
+}
+_MODULE = {"MODULE_PATH": "github.com/ada/my-app"}
+_ELISP = {**_ADA, "keywords": "convenience tools"}
+_DOMAIN = {"domain": "example.com"}
+
+# (template, answers, path, text the rendered file holds), for a project
+# named my-app; None for a path that is not rendered.
+_RENDERED = [
+    ("go", {}, "go.mod", "module my-app\n"),
+    ("go", {}, "web/main.go", None),
+    ("go", {}, "template.toml", None),
+    ("go/web", {}, "main.go", '"my-app/components"'),
+    ("go/web", {}, "main.go", 'mux.HandleFunc("GET /", handleNotFound)'),
+    ("go/web", {}, "main.go", "middleware.RecoverWith(crashPage("),
+    ("go/web", {}, "main.go", "if err := run(ctx, os.Getenv, listen);"),
+    ("go/web", {}, "main.go", "handleContact(logContact))"),
+    ("go/web", {}, "components/errors.templ", "templ NotFound(path string)"),
+    ("go/web", {}, "internal/middleware/recover.go", "func RecoverWith("),
+    ("go/web", {}, "integration_test.go", "httptest.NewServer(srv.Handler)"),
+    ("go/web", {}, "example_test.go", None),
+    ("go/web", {}, "justfile", "golden:"),
+    ("go/web", {}, "contact.go", "flash.Set(w, "),
+    ("go/web", {}, "internal/form/form.go", "func (f *Form) Check("),
+    ("go/web", {}, "components/contact.templ", '@field(f, "email", "Email")'),
+    (
+        "go/web-stdlib",
+        {},
+        "internal/web/templates/layouts/base.html",
+        "{{.Title}}",
+    ),
+    ("go/web-stdlib", {}, "components/page.templ", None),
+    ("go/api", {}, "main.go", '"GET /api/v1/greet"'),
+    ("go/api", {}, "internal/api/decode.go", "package api"),
+    ("go/api", {}, "internal/middleware/csrf.go", None),
+    ("go/openapi", {}, "internal/oapi/openapi.yaml", "title: my-app"),
+    ("go/openapi", {}, "internal/oapi/doc.go", "//go:embed openapi.yaml"),
+    ("go/openapi", {}, "oapi-codegen.yaml", "internal/oapi/oapi.gen.go"),
+    ("go/openapi", {}, "justfile", "\ngenerate:\n    oapi-codegen"),
+    ("go/grpc", {}, "proto/greet/v1/greet.proto", "my-app/gen/greet/v1"),
+    ("go/grpc", {}, "buf.gen.yaml", "out: gen"),
+    ("go/grpc", {}, "justfile", "\ngenerate:\n    buf generate"),
+    ("go/grpc", {}, "greet_test.go", "test/bufconn"),
+    ("go/graphql", {}, "graph/schema.graphqls", "type Mutation {"),
+    ("go/graphql", {}, "gqlgen.yml", '  - "my-app/graph/model"'),
+    ("go/graphql", {}, "graph/loaders.go", "dataloadgen.NewLoader"),
+    ("go/graphql", {}, "tools.go", "//go:build tools"),
+    ("go/cli", {}, "cmd/root.go", '"flag"'),
+    ("go/cli", {}, "internal/config/config.go", 'const Prefix = "MY_APP_"'),
+    ("go/cli", {}, "justfile", "version.Version="),
+    ("go/cli", {}, "justfile", "\ndist: build\n"),
+    ("go/tui", {}, "internal/tui/keys.go", "func (k keyMap) FullHelp()"),
+    ("go/tui", {}, "internal/tui/tui_test.go", "teatest.NewTestModel"),
+    ("go/tui", {}, "go.mod", "charmbracelet/bubbletea v1"),
+    ("go/lib", _MODULE, "doc.go", "package myapp\n"),
+    ("go/lib", _MODULE, "example_test.go", '"github.com/ada/my-app"'),
+    ("go/lib", _MODULE, "slug_test.go", "func FuzzMake(f *testing.F)"),
+    ("go/lib", _MODULE, "main.go", None),
+    ("go/worker", {}, "main.go", '"POST /jobs/{kind}"'),
+    ("go/worker", {}, "internal/clock/clock.go", "func (f *Fake) Advance("),
+    ("go/worker", {}, "internal/queue/queue_test.go", "clock.NewFake("),
+    ("go/worker", {}, "internal/jobs/jobs.go", "q.Every(KindCleanup"),
+    ("go/operator", _DOMAIN, "cmd/main.go", "LeaderElection:"),
+    (
+        "go/operator",
+        _DOMAIN,
+        "config/crd/bases/my-app.example.com_greetings.yaml",
+        "name: greetings.my-app.example.com",
+    ),
+    (
+        "go/operator",
+        _DOMAIN,
+        "api/v1alpha1/groupversion_info.go",
+        "// +groupName=my-app.example.com",
+    ),
+    (
+        "go/operator",
+        _DOMAIN,
+        "config/default/kustomization.yaml",
+        "namespace: my-app-system",
+    ),
+    ("go/lambda", {}, "cmd/http/main.go", "invoke.Start(newHandler("),
+    ("go/lambda", {}, "internal/invoke/invoke.go", "AWS_LAMBDA_RUNTIME_API"),
+    ("go/lambda", {}, "cmd/sqs/handler.go", "BatchItemFailures"),
+    ("go/lambda", {}, "justfile", "-tags lambda.norpc"),
+    ("go/lambda", {}, "template.yaml", None),
+    ("go/wasm", {}, "cmd/app/main.go", "//go:build js && wasm\n"),
+    ("go/wasm", {}, "web/index.html", 'src="wasm_exec.js"'),
+    ("go/wasm", {}, "cmd/serve/main.go", '"application/wasm"'),
+    ("rust/cli", {}, "Cargo.toml", "[profile.release]\nlto = true"),
+    ("rust/cli", {}, "src/cli.rs", 'env = "MY_APP_CONFIG"'),
+    ("rust/cli", {}, "src/config.rs", "#[derive(Debug, Error)]"),
+    ("rust/cli", {}, "tests/cli.rs", "CARGO_BIN_EXE_my-app"),
+    ("rust/web", {}, "src/main.rs", ".layer(TraceLayer::new_for_http())"),
+    ("rust/web", {}, "src/main.rs", ".with_graceful_shutdown("),
+    ("rust/web", {}, "src/main.rs", "fn with_timeout("),
+    ("rust/web", {}, "src/api.rs", "pub async fn handle_hello("),
+    ("python", {}, "src/my_app/__init__.py", ""),
+    ("python", {}, "tests/test_main.py", "from my_app.main import hello"),
+    ("python/lib", {}, "src/my_app/py.typed", ""),
+    ("python/lib", {}, "src/my_app/__init__.py", "from my_app.slug import"),
+    ("python/lib", {}, "pyproject.toml", "[tool.mypy]\nstrict = true"),
+    ("python/lib", {}, "pyproject.toml", "hatchling.build"),
+    ("python/web", {}, "src/my_app/app.py", "include_router(health.router)"),
+    ("python/web", {}, "src/my_app/routers/health.py", '"/healthz"'),
+    ("python/web", {}, "src/my_app/settings.py", "(BaseSettings):"),
+    ("python/web", {}, "tests/conftest.py", "dependency_overrides"),
+    ("typescript/node", {}, "package.json", '"dev": "tsx watch src/index.ts"'),
+    ("typescript/node", {}, "package.json", '"packageManager": "pnpm@'),
+    ("typescript/node", {}, "tsconfig.json", '"strict": true'),
+    ("typescript/node", {}, "src/server.ts", "export function shutdown("),
+    ("typescript/node", {}, "src/server.test.ts", "vitest"),
+    ("elixir/phoenix", {}, "lib/my_app_web/router.ex", 'live "/counter"'),
+    (
+        "elixir/phoenix",
+        {},
+        "lib/my_app_web/live/counter_live.ex",
+        "use MyAppWeb, :live_view",
+    ),
+    (
+        "elixir/phoenix",
+        {},
+        "test/my_app_web/live/counter_live_test.exs",
+        "import Phoenix.LiveViewTest",
+    ),
+    ("elixir/phoenix", {}, ".credo.exs", "Credo.Check"),
+    ("elixir/lib", _ADA, "mix.exs", "defmodule MyApp.MixProject do"),
+    ("elixir/lib", _ADA, "mix.exs", 'description: ~S"Greet people",'),
+    ("elixir/lib", _ADA, "mix.exs", 'licenses: ["ISC"],'),
+    ("elixir/lib", _ADA, "mix.exs", 'authors: [~S"Ada Lovelace"],'),
+    ("elixir/lib", _ADA, "test/my_app_test.exs", "doctest MyApp"),
+    ("elixir/lib", _ADA, "lib/my_app.ex", "@spec slugify("),
+    ("zig/app", {}, "build.zig.zon", '.name = "my_app",'),
+    ("zig/app", {}, "build.zig.zon", '.minimum_zig_version = "0.13.0",'),
+    ("zig/app", {}, "build.zig", 'b.step("test", "Run the tests")'),
+    ("zig/app", {}, "src/main.zig", '@import("greet")'),
+    ("zig/app", {}, ".gitignore", "zig-out/"),
+    ("ocaml/app", _ADA, "dune-project", '(authors "Ada Lovelace")'),
+    ("ocaml/app", _ADA, "dune-project", "(license ISC)"),
+    ("ocaml/app", _ADA, "my-app.opam", 'license: "ISC"'),
+    ("ocaml/app", _ADA, "bin/dune", "(public_name my-app)"),
+    ("ocaml/app", _ADA, "lib/dune", "(name my_app)"),
+    ("ocaml/app", _ADA, "bin/main.ml", "My_app.Greet.greet"),
+    ("ocaml/app", _ADA, "test/test_greet.ml", "Alcotest.run"),
+    ("clojure/app", {}, "src/my_app/core.clj", "(ns my-app.core"),
+    ("clojure/app", {}, "test/my_app/core_test.clj", "(ns my-app.core-test"),
+    ("clojure/app", {}, "deps.edn", ":build {"),
+    ("clojure/app", {}, "build.clj", "(def main 'my-app.core)"),
+    ("clojure/app", {}, "dev/user.clj", "(ns user"),
+    ("lisp/app", {}, "my-app.asd", ':entry-point "my-app:main"'),
+    ("lisp/app", {}, "my-app.asd", '(defsystem "my-app/tests"'),
+    ("lisp/app", {}, "src/package.lisp", "(defpackage #:my-app"),
+    ("lisp/app", {}, "tests/greet.lisp", "(def-suite all"),
+    ("lisp/app", {}, "scripts/test.lisp", '(asdf:test-system "my-app")'),
+    ("elisp/package", _ELISP, "my-app.el", ";;; my-app.el --- Greet people"),
+    (
+        "elisp/package",
+        _ELISP,
+        "my-app.el",
+        ";; Author: Ada Lovelace <ada@example.com>",
+    ),
+    ("elisp/package", _ELISP, "my-app.el", ";;; my-app.el ends here\n"),
+    ("elisp/package", _ELISP, "Eask", '(keywords "convenience" "tools")'),
+    ("elisp/package", _ELISP, "test/my-app-test.el", "(require 'my-app)"),
+    ("gleam/app", {"target": "erlang"}, "gleam.toml", 'target = "erlang"'),
+    (
+        "gleam/app",
+        {"target": "javascript"},
+        "gleam.toml",
+        'target = "javascript"',
+    ),
+    ("gleam/app", {"target": "erlang"}, "src/my_app.gleam", "import my_app/"),
+    (
+        "gleam/app",
+        {"target": "erlang"},
+        "test/my_app_test.gleam",
+        "gleeunit.main()",
+    ),
+    (
+        "kotlin/app",
+        {**_MODULE, "group": ""},
+        "build.gradle.kts",
+        'mainClass = "com.github.ada.myapp.AppKt"',
+    ),
+    (
+        "kotlin/app",
+        {"group": "org.example"},
+        "src/main/kotlin/App.kt",
+        "package org.example.myapp\n",
+    ),
+    ("kotlin/app", {"group": ""}, "build.gradle.kts", '"com.example"'),
+    ("kotlin/app", {"group": ""}, "settings.gradle.kts", '"my-app"'),
+    ("kotlin/app", {"group": ""}, "gradle/libs.versions.toml", "[plugins]"),
+    (
+        "kotlin/app",
+        {"group": ""},
+        "gradle/wrapper/gradle-wrapper.properties",
+        "gradle-8.10.2-bin.zip",
+    ),
+    ("kotlin/app", {"group": ""}, "gradlew", "GradleWrapperMain"),
+    ("kotlin/app", {"group": ""}, "gradlew.bat", "\r\n"),
+    ("cpp/app", {}, "CMakeLists.txt", "add_library(my_app_core src/greet.cpp"),
+    ("cpp/app", {}, "CMakeLists.txt", "add_executable(my-app src/main.cpp)"),
+    ("cpp/app", {}, "CMakePresets.json", '"name": "release"'),
+    ("cpp/app", {}, "include/my_app/greet.hpp", "namespace my_app {"),
+    ("cpp/app", {}, "src/main.cpp", '#include "my_app/greet.hpp"'),
+    ("cpp/app", {}, "tests/greet_test.cpp", "TEST(Greet,"),
+    ("shell/cli", {}, "bin/my-app", "set -Eeuo pipefail"),
+    ("shell/cli", {}, "test/my-app.bats", "/../bin/my-app"),
+    (
+        "infra/terraform",
+        {"provider": "aws"},
+        "main.tf",
+        'resource "aws_s3_bucket" "this"',
+    ),
+    (
+        "infra/terraform",
+        {"provider": "google"},
+        "versions.tf",
+        'source  = "hashicorp/google"',
+    ),
+    (
+        "infra/terraform",
+        {"provider": "azurerm"},
+        "tests/module.tftest.hcl",
+        'mock_provider "azurerm" {}',
+    ),
+    ("infra/terraform", {"provider": "aws"}, ".tflint.hcl", "ruleset-aws"),
+    ("infra/terraform", {"provider": "aws"}, "README.md", "BEGIN_TF_DOCS"),
+    (
+        "nix/flake",
+        {"systems": "x86_64-linux,aarch64-darwin"},
+        "flake.nix",
+        '      systems = [\n        "x86_64-linux"\n        "aarch64-darwin"',
+    ),
+    (
+        "nix/flake",
+        {"systems": "x86_64-linux"},
+        "flake.nix",
+        "my-app = final.callPackage ./nix/package.nix { };",
+    ),
+    (
+        "nix/flake",
+        {"systems": "x86_64-linux"},
+        "nix/package.nix",
+        "builtins.readFile ../src/my-app.sh",
+    ),
+    ("deno/app", {}, "deno.json", '"nodeModulesDir": "none"'),
+    ("deno/app", {}, "deno.json", "--output bin/my-app"),
+    ("deno/app", {}, "main.ts", 'from "@std/http/file-server"'),
+    ("deno/app", {}, "main_test.ts", "Deno.test("),
+    ("deno/app", {}, "package.json", None),
+    ("bun/app", {}, "package.json", '"dev": "bun --hot src/index.ts"'),
+    ("bun/app", {}, "package.json", "--outfile bin/my-app"),
+    ("bun/app", {}, "src/index.ts", "Bun.serve({"),
+    ("bun/app", {}, "src/index.test.ts", 'from "bun:test"'),
+    ("bun/app", {}, "bunfig.toml", "[test]"),
+]
+_RENDERED += [
+    (name, {}, path, needle)
+    for name in ("go/web", "go/web-stdlib", "go/api")
+    for path, needle in (
+        ("main.go", "WriteTimeout:      cfg.WriteTimeout,"),
+        ("main.go", "middleware.MaxBytes(cfg.MaxBodyBytes)"),
+        ("internal/config/config.go", '"REQUEST_TIMEOUT", 30*time.Second'),
+        ("internal/middleware/limits.go", "func Timeout("),
+    )
+]
+
+
+class TestBuiltinTemplates(unittest.TestCase):
+    def test_lists_language_templates(self):
+        names = [t.name for t in scaffold.list_templates()]
+        for name in ("go", "go/web", "python", "python/web", "rust"):
+            self.assertIn(name, names)
+        self.assertEqual(names, sorted(names))
+
+    def test_every_builtin_has_description(self):
+        for template in scaffold.list_templates():
+            self.assertTrue(template.description, template.name)
+
+    def test_unknown_template_exits(self):
+        with self.assertRaises(SystemExit):
+            scaffold.find_template("cobol/web")
+
+    def test_rendered_files(self):
+        rendered = {}
+        for name, answers, path, needle in _RENDERED:
+            key = (name, tuple(sorted(answers.items())))
+            with self.subTest(template=name, path=path, needle=needle):
+                if key not in rendered:
+                    template = scaffold.find_template(name)
+                    variables = {
+                        **scaffold.project_variables("my-app"),
+                        **answers,
+                    }
+                    rendered[key] = dict(
+                        scaffold.render_project(template, [], variables)
+                    )
+                files = rendered[key]
+                if needle is None:
+                    self.assertNotIn(path, files)
+                else:
+                    self.assertIn(path, files)
+                    self.assertIn(needle, files[path])
+
+    def test_addons_for_other_templates_are_refused(self):
+        for name, addon in (("go/api", "tailwind"), ("go/grpc", "sse")):
+            with self.subTest(template=name, addon=addon):
+                template = scaffold.find_template(name)
+                with self.assertRaises(SystemExit):
+                    scaffold.find_addon(addon, template)
+
+    def test_python_web_takes_the_docker_addon(self):
+        template = scaffold.find_template("python/web")
+        docker = scaffold.find_addon("docker", template)
+        variables = scaffold.project_variables("my-app")
+        files = dict(scaffold.render_project(template, [docker], variables))
+        self.assertIn("uv sync --no-dev", files["Dockerfile"])
+        self.assertIn("my_app.app:app", files["Dockerfile"])
+        self.assertNotIn("distroless", files["Dockerfile"])
+        self.assertIn("compose.yaml", files)
+
+    def test_elixir_phoenix_runs_phx_new_with_the_project_names(self):
+        template = scaffold.find_template("elixir/phoenix")
+        variables = scaffold.project_variables("my-app")
+        hooks = scaffold.template_hooks([template], "pre", variables)
+        self.assertIn("--app my_app --module MyApp --no-ecto", hooks[-1])
+
+    def test_shell_cli_script_is_executable(self):
+        template = scaffold.find_template("shell/cli")
+        variables = scaffold.project_variables("my-app")
+        modes = scaffold.render_modes([template], variables)
+        self.assertEqual(modes["bin/my-app"], 0o755)
+
+    def test_typescript_vite_renders_react_or_svelte(self):
+        template = scaffold.find_template("typescript/vite")
+        for framework, main, other in (
+            ("react", "src/main.tsx", "src/App.svelte"),
+            ("svelte", "src/App.svelte", "src/main.tsx"),
+        ):
+            with self.subTest(framework=framework):
+                variables = {
+                    **scaffold.project_variables("my-site"),
+                    "framework": framework,
+                    "api_proxy": "",
+                }
+                files = dict(scaffold.render_project(template, [], variables))
+                self.assertIn(main, files)
+                self.assertNotIn(other, files)
+                config = files["vite.config.ts"]
+                self.assertEqual(framework == "svelte", "svelte()" in config)
+
+    def test_haskell_app_builds_with_cabal_or_stack(self):
+        template = scaffold.find_template("haskell/app")
+        for tool, project, other in [
+            ("cabal", "cabal.project", "stack.yaml"),
+            ("stack", "stack.yaml", "cabal.project"),
+        ]:
+            with self.subTest(tool=tool):
+                variables = {
+                    **scaffold.project_variables("my-app"),
+                    "build_tool": tool,
+                }
+                files = dict(scaffold.render_project(template, [], variables))
+                self.assertIn(project, files)
+                self.assertNotIn(other, files)
+                self.assertIn(f"{tool} test", files["justfile"])
+                cabal = files["my-app.cabal"]
+                self.assertIn("autogen-modules: Paths_my_app", cabal)
+
+    def test_c_app_builds_with_make_or_meson(self):
+        template = scaffold.find_template("c/app")
+        for system, build, other, line in [
+            ("make", "Makefile", "meson.build", "    make test\n"),
+            ("meson", "meson.build", "Makefile", "-Db_sanitize=address"),
+        ]:
+            with self.subTest(system=system):
+                variables = {
+                    **scaffold.project_variables("my-app"),
+                    "build_system": system,
+                }
+                files = dict(scaffold.render_project(template, [], variables))
+                self.assertIn(build, files)
+                self.assertNotIn(other, files)
+                self.assertIn(line, files["justfile"])
+
+    def test_lua_is_a_program_or_a_love_game(self):
+        template = scaffold.find_template("lua")
+        for kind, line in (
+            ("cli", "print(greet.greet(arg[1]))"),
+            ("love", "function love.draw()"),
+        ):
+            with self.subTest(kind=kind):
+                variables = {
+                    **scaffold.project_variables("my-game"),
+                    "kind": kind,
+                }
+                files = dict(scaffold.render_project(template, [], variables))
+                self.assertIn(line, files["main.lua"])
+                self.assertEqual(kind == "love", "conf.lua" in files)
+
+
+class TestRenderTemplate(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_unknown_placeholders_pass_through(self):
+        """just's own {{args}} must survive substitution."""
+        _write_template(
+            self.root, "t", {"justfile": "run *args:\n    {{args}}\n"}
+        )
+        template = scaffold.find_template("t", root=self.root)
+        files = dict(
+            scaffold.render_template(
+                template, scaffold.project_variables("x")
+            )
+        )
+        self.assertEqual(files["justfile"], "run *args:\n    {{args}}\n")
+
+    def test_filters_apply_to_paths(self):
+        _write_template(
+            self.root,
+            "t",
+            {"cmd/{{PROJECT_NAME | snake}}/main.go": "// {{PROJECT_NAME}}"},
+        )
+        template = scaffold.find_template("t", root=self.root)
+        files = dict(
+            scaffold.render_template(
+                template, scaffold.project_variables("my-app")
+            )
+        )
+        self.assertEqual(files["cmd/my_app/main.go"], "// my-app")
+
+    def test_path_values_may_add_directories(self):
+        self.assertEqual(
+            scaffold.render_path("internal/{{PKG}}/x.go", {"PKG": "a/b"}),
+            "internal/a/b/x.go",
+        )
+
+    def test_paths_cannot_escape_project(self):
+        for value in ("..", "", "/etc"):
+            with self.assertRaises(ValueError, msg=value):
+                scaffold.render_path("{{PKG}}/x", {"PKG": value})
+
+    def test_colliding_paths_exit(self):
+        _write_template(self.root, "t", {"{{A}}": "1", "{{B}}": "2"})
+        template = scaffold.find_template("t", root=self.root)
+        with self.assertRaises(SystemExit):
+            scaffold.render_template(template, {"A": "same", "B": "same"})
+
+
+class TestPlaceholders(unittest.TestCase):
+    def render(self, text, **variables):
+        return placeholders.render(text, variables)
+
+    def test_case_filters(self):
+        name = "my-cool_app"
+        for f, want in (
+            ("upper", "MY-COOL_APP"),
+            ("snake", "my_cool_app"),
+            ("kebab", "my-cool-app"),
+            ("pascal", "MyCoolApp"),
+            ("camel", "myCoolApp"),
+            ("title", "My Cool App"),
+        ):
+            got = self.render(f"{{{{N | {f}}}}}", N=name)
+            self.assertEqual(got, want, f)
+
+    def test_words_split_camel_case(self):
+        self.assertEqual(
+            placeholders.words("myHTTPServer-v2"),
+            ["my", "HTTP", "Server", "v2"],
+        )
+
+    def test_filters_chain(self):
+        got = self.render("{{ N | snake | upper }}", N="a-b")
+        self.assertEqual(got, "A_B")
+
+    def test_unresolved_left_alone(self):
+        text = "{{args}} {{ title }} {{ .Name }} {{N | shout}} ${{ env.X }}"
+        self.assertEqual(self.render(text, N="x"), text)
+
+    def test_date(self):
+        day = datetime.date(2026, 3, 4)
+        with mock.patch.object(placeholders.datetime, "date") as date:
+            date.today.return_value = day
+            self.assertEqual(self.render("{{date}}"), "2026-03-04")
+            self.assertEqual(self.render('{{ date "%Y" }}'), "2026")
+
+    def test_env(self):
+        with mock.patch.dict(os.environ, {"JOLO_T": "v"}):
+            self.assertEqual(self.render('{{ env "JOLO_T" }}'), "v")
+        with mock.patch.dict(os.environ, clear=True):
+            self.assertEqual(self.render('{{ env "JOLO_T" "d" }}'), "d")
+
+    def test_uuid_is_stable_per_project(self):
+        project = "12345678-1234-5678-1234-567812345678"
+        self.assertEqual(self.render("{{uuid}}", UUID=project), project)
+        db = self.render('{{ uuid "db" }}', UUID=project)
+        self.assertNotEqual(db, project)
+        self.assertEqual(db, self.render('{{ uuid "db" }}', UUID=project))
+
+    def test_variables_shadow_helpers(self):
+        self.assertEqual(self.render("{{date}}", date="today"), "today")
+
+
+class TestBinaryFiles(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_sniffing(self):
+        self.assertTrue(scaffold.is_binary(b"\x89PNG\r\n\x1a\n\0\0"))
+        self.assertTrue(scaffold.is_binary(b"\xff\xfe{{PROJECT_NAME}}"))
+        self.assertFalse(scaffold.is_binary("héllo {{X}}".encode()))
+        # A character split at the sniffing boundary is not binary.
+        self.assertFalse(scaffold.is_binary(b"a" * 8191 + "é".encode()))
+        # Anywhere else, and in a file that ends there, it is.
+        self.assertTrue(scaffold.is_binary(b"hello world \xff"))
+        self.assertTrue(scaffold.is_binary(b"a" * 8191 + b"\xc3"))
+        self.assertTrue(scaffold.is_binary(b"a" * 8190 + b"\xffab"))
+
+    def test_binary_copied_verbatim(self):
+        png = b"\x89PNG\0{{PROJECT_NAME}}"
+        path = _write_template(
+            self.root,
+            "t",
+            {"logo.txt": "{{PROJECT_NAME}}", "icon.svg": "{{PROJECT_NAME}}"},
+            'binary = ["*.svg"]\n',
+        )
+        (path / "{{PROJECT_NAME}}.png").write_bytes(png)
+        template = scaffold.find_template("t", root=self.root)
+        files = dict(
+            scaffold.render_template(
+                template, scaffold.project_variables("app")
+            )
+        )
+        self.assertEqual(files["app.png"], png)
+        self.assertEqual(files["icon.svg"], b"{{PROJECT_NAME}}")
+        self.assertEqual(files["logo.txt"], "app")
+
+        target = self.root / "out"
+        scaffold.write_files(list(files.items()), target)
+        self.assertEqual((target / "app.png").read_bytes(), png)
+
+
+class TestFileModes(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_executable_sources_keep_their_mode(self):
+        path = _write_template(
+            self.root, "t", {"scripts/dev.sh": "", "README": ""}
+        )
+        (path / "scripts/dev.sh").chmod(0o755)
+        template = scaffold.find_template("t", root=self.root)
+        self.assertEqual(
+            scaffold.render_modes([template], {}), {"scripts/dev.sh": 0o755}
+        )
+
+    def test_manifest_modes_override(self):
+        _write_template(
+            self.root,
+            "t",
+            {"bin/{{PROJECT_NAME}}": "", "secret": "", "a.sh": ""},
+            '[modes]\n"bin/" = "755"\n"secret" = 0o600\n"*.sh" = 0o750\n',
+        )
+        template = scaffold.find_template("t", root=self.root)
+        modes = scaffold.render_modes([template], {"PROJECT_NAME": "app"})
+        self.assertEqual(
+            modes, {"bin/app": 0o755, "secret": 0o600, "a.sh": 0o750}
+        )
+
+    def test_bad_mode_exits(self):
+        _write_template(self.root, "t", {"a": ""}, '[modes]\n"a" = "rwx"\n')
+        template = scaffold.find_template("t", root=self.root)
+        with self.assertRaises(SystemExit):
+            scaffold.render_modes([template], {})
+
+    def test_write_files_applies_modes(self):
+        target = self.root / "out"
+        scaffold.write_files(
+            [("run", "#!/bin/sh\n"), ("x", "")], target, {"run": 0o755}
+        )
+        self.assertEqual((target / "run").stat().st_mode & 0o777, 0o755)
+        self.assertFalse((target / "x").stat().st_mode & 0o111)
+
+
+class TestConditionalFiles(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_condition_forms(self):
+        variables = {"docker": "true", "db": "sqlite", "off": "false"}
+        for expr, want in (
+            ("docker", True),
+            ("!docker", False),
+            ("off", False),
+            ("!off", True),
+            ("db == sqlite", True),
+            ("db == 'pg'", False),
+            ("db != none", True),
+            ("docker and db == sqlite", True),
+            ("docker and off", False),
+        ):
+            got = scaffold.condition_holds(expr, variables)
+            self.assertEqual(got, want, expr)
+
+    def test_unknown_variable_is_an_error(self):
+        with self.assertRaises(ValueError):
+            scaffold.condition_holds("dokcer", {"docker": "true"})
+
+    def test_files_and_directories_follow_conditions(self):
+        _write_template(
+            self.root,
+            "t",
+            {
+                "main.go": "",
+                "Dockerfile": "",
+                "deploy/fly.toml": "",
+            },
+            '[conditions]\n"Dockerfile" = "docker"\n'
+            '"deploy/" = "cloud == fly"\n',
+        )
+        template = scaffold.find_template("t", root=self.root)
+
+        def paths(**variables):
+            files = scaffold.render_template(template, variables)
+            return sorted(rel for rel, _ in files)
+
+        self.assertEqual(paths(docker="false", cloud="none"), ["main.go"])
+        self.assertEqual(
+            paths(docker="true", cloud="fly"),
+            ["Dockerfile", "deploy/fly.toml", "main.go"],
+        )
+
+    def test_patch_when(self):
+        base = _write_template(self.root, "go/t", {"main.go": "a\n"})
+        addons = self.root / "addons"
+        _write_template(
+            addons,
+            "x",
+            {},
+            '[[patches]]\nfile = "main.go"\nappend = "b\\n"\n'
+            'when = "extra"\n',
+        )
+        template = scaffold.load_template(base, "go/t")
+        addon = scaffold.find_addon("x", template, root=addons)
+        for extra, want in (("true", "a\nb\n"), ("false", "a\n")):
+            files = dict(
+                scaffold.render_project(template, [addon], {"extra": extra})
+            )
+            self.assertEqual(files["main.go"], want)
+
+
+VARIABLES_MANIFEST = """
+[variables.license]
+type = "choice"
+choices = ["MIT", "Apache-2.0"]
+default = "MIT"
+
+[variables.port]
+type = "int"
+prompt = "Dev port"
+default = 4000
+
+[variables.docker]
+type = "bool"
+default = false
+
+[variables.author]
+description = "Shown in the README"
+"""
+
+
+class TestTemplateVariables(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        root = Path(self.tmpdir.name)
+        _write_template(
+            root,
+            "t",
+            {"README": "{{license}} {{port}} {{docker}} {{author}}"},
+            VARIABLES_MANIFEST,
+        )
+        self.template = scaffold.find_template("t", root=root)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _resolve(self, argv):
+        args = jolo.parse_args(["new", "t", "x", *argv])
+        return scaffold.resolve_variables(
+            self.template, scaffold.parse_var_args(args.var), args.no_input
+        )
+
+    def test_manifest_order_is_kept(self):
+        self.assertEqual(
+            list(self.template.variables),
+            ["license", "port", "docker", "author"],
+        )
+
+    def test_var_flags_with_no_input(self):
+        values = self._resolve(
+            [
+                "--no-input",
+                "--var",
+                "author=Ada",
+                "--var",
+                "docker=yes",
+                "--var",
+                "extra=1",
+            ]
+        )
+        self.assertEqual(
+            values,
+            {
+                "license": "MIT",
+                "port": "4000",
+                "docker": "true",
+                "author": "Ada",
+                "extra": "1",
+            },
+        )
+
+    def test_no_input_without_default_exits(self):
+        with self.assertRaises(SystemExit):
+            self._resolve(["--no-input"])
+
+    def test_invalid_var_exits(self):
+        for var in ("port=lots", "license=GPL", "docker=maybe", "nokey"):
+            with self.assertRaises(SystemExit, msg=var):
+                self._resolve(
+                    ["--no-input", "--var", var, "--var", "author=a"]
+                )
+
+    def test_prompts_reask_on_invalid_and_use_defaults(self):
+        answers = iter(["2", "eighty", "8080", "", "Ada"])
+        with (
+            mock.patch("sys.stdin.isatty", return_value=False),
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("sys.stderr"),
+        ):
+            values = self._resolve([])
+        self.assertEqual(
+            values,
+            {
+                "license": "Apache-2.0",
+                "port": "8080",
+                "docker": "false",
+                "author": "Ada",
+            },
+        )
+
+    def test_unknown_type_exits(self):
+        self.template.manifest["variables"]["port"]["type"] = "float"
+        with self.assertRaises(SystemExit):
+            self.template.variables
+
+    def test_values_reach_rendered_files(self):
+        variables = scaffold.project_variables("x")
+        variables.update(
+            scaffold.resolve_variables(self.template, {"author": "Ada"}, True)
+        )
+        files = dict(scaffold.render_template(self.template, variables))
+        self.assertEqual(files["README"], "MIT 4000 false Ada")
+
+    def _conditional(self):
+        variables = self.template.manifest["variables"]
+        variables["port"]["when"] = "license == MIT"
+        variables["author"]["when"] = "docker"
+
+    def test_when_skips_the_prompt(self):
+        self._conditional()
+        answers = iter(["2", ""])
+        with (
+            mock.patch("sys.stdin.isatty", return_value=False),
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("sys.stderr"),
+        ):
+            values = self._resolve([])
+        self.assertEqual(
+            values,
+            {
+                "license": "Apache-2.0",
+                "port": "4000",
+                "docker": "false",
+                "author": "",
+            },
+        )
+
+    def test_when_follows_earlier_answers(self):
+        self._conditional()
+        answers = iter(["1", "8080", "yes", "Ada"])
+        with (
+            mock.patch("sys.stdin.isatty", return_value=False),
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("sys.stderr"),
+        ):
+            values = self._resolve([])
+        self.assertEqual(
+            values,
+            {
+                "license": "MIT",
+                "port": "8080",
+                "docker": "true",
+                "author": "Ada",
+            },
+        )
+
+    def test_given_var_beats_when(self):
+        self._conditional()
+        values = self._resolve(
+            ["--no-input", "--var", "license=Apache-2.0", "--var", "port=80"]
+        )
+        self.assertEqual((values["port"], values["author"]), ("80", ""))
+
+    def test_when_with_an_unknown_name(self):
+        self.template.manifest["variables"]["port"]["when"] = "nope"
+        with self.assertRaises(SystemExit) as cm:
+            self._resolve(["--no-input"])
+        self.assertEqual(
+            cm.exception.code,
+            "Error: t: port: when: unknown variable in condition: nope",
+        )
+
+
+FEATURES_MANIFEST = """
+[variables.features]
+type = "multi"
+choices = ["docker", "auth", "metrics"]
+default = ["metrics"]
+
+[variables.features.addons]
+docker = "docker"
+
+[conditions]
+"auth.go" = "features has auth"
+"""
+
+
+class TestMultiSelect(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        self.team = Path(self.tmpdir) / "team"
+        _write_template(
+            self.team,
+            "go/web",
+            {"auth.go": "package main", "README": "{{features}}"},
+            FEATURES_MANIFEST,
+        )
+        self.env = mock.patch.dict(
+            os.environ, {"JOLO_TEMPLATES": str(self.team)}
+        )
+        self.env.start()
+
+    def tearDown(self):
+        self.env.stop()
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv):
+        args = jolo.parse_args(
+            ["new", "go/web", "app", "--git=false", "--no-install", *argv]
+        )
+        with mock.patch("builtins.print"):
+            scaffold.run_new_mode(args)
+        return Path("app")
+
+    def test_coerce(self):
+        spec = {"type": "multi", "choices": ["a", "b", "c"]}
+        self.assertEqual(scaffold.coerce_variable(spec, ["c", "a"]), "a,c")
+        self.assertEqual(scaffold.coerce_variable(spec, " b, a,b "), "a,b")
+        self.assertEqual(scaffold.coerce_variable(spec, ""), "")
+        with self.assertRaises(ValueError) as cm:
+            scaffold.coerce_variable(spec, "a,d")
+        self.assertEqual(
+            str(cm.exception), "expected some of a, b, c, got d"
+        )
+
+    def test_has_condition(self):
+        variables = {"features": "docker,auth"}
+        self.assertTrue(
+            scaffold.condition_holds("features has auth", variables)
+        )
+        self.assertFalse(
+            scaffold.condition_holds("features has 'metrics'", variables)
+        )
+        with self.assertRaises(ValueError):
+            scaffold.condition_holds("nope has auth", variables)
+
+    def test_features_flag_picks_addons_and_files(self):
+        app = self._new("--no-input", "--features", "docker,auth")
+        self.assertEqual((app / "README").read_text(), "docker,auth")
+        self.assertTrue((app / "auth.go").exists())
+        self.assertTrue((app / "Dockerfile").exists())
+        lock = scaffold.read_lock(app)
+        self.assertEqual(lock["addons"], ["docker"])
+
+    def test_default_without_input(self):
+        app = self._new("--no-input")
+        self.assertEqual((app / "README").read_text(), "metrics")
+        self.assertFalse((app / "auth.go").exists())
+        self.assertFalse((app / "Dockerfile").exists())
+
+    def test_prompt_takes_numbers_and_names(self):
+        answers = iter(["1 sqlite", "1, auth"])
+        with (
+            mock.patch("sys.stdin.isatty", return_value=False),
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("sys.stderr", io.StringIO()),
+        ):
+            app = self._new()
+        self.assertIsNone(next(answers, None))  # sqlite was asked again
+        self.assertEqual((app / "README").read_text(), "docker,auth")
+        self.assertTrue((app / "Dockerfile").exists())
+
+    def test_empty_answer_takes_the_default(self):
+        with (
+            mock.patch("sys.stdin.isatty", return_value=False),
+            mock.patch("builtins.input", return_value=""),
+            mock.patch("sys.stderr", io.StringIO()),
+        ):
+            app = self._new()
+        self.assertEqual((app / "README").read_text(), "metrics")
+
+    def test_features_without_a_features_variable_exits(self):
+        args = jolo.parse_args(
+            ["new", "go", "app", "--no-input", "--features", "docker"]
+        )
+        with self.assertRaises(SystemExit) as cm:
+            scaffold.run_new_mode(args)
+        self.assertEqual(
+            cm.exception.code, "Error: go has no features (--features)"
+        )
+
+    def test_addons_need_a_multi_variable(self):
+        template = scaffold.Template(
+            name="t",
+            path=Path(self.tmpdir),
+            manifest={
+                "variables": {"x": {"type": "bool", "addons": {"y": "z"}}}
+            },
+        )
+        with self.assertRaises(SystemExit):
+            template.variables
+
+
+class TestAddons(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+        _write_template(
+            self.root / "lang",
+            "go",
+            {"main.go": "func main() {\n\tmux := 1\n}\n", "go.mod": "go 1"},
+        )
+        self.base = scaffold.find_template("go", root=self.root / "lang")
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _addon(self, name, files, manifest=""):
+        _write_template(self.root / "addons", name, files, manifest)
+        return scaffold.find_addon(
+            name.split("/")[-1], self.base, root=self.root / "addons"
+        )
+
+    def _render(self, *addons):
+        return dict(
+            scaffold.render_project(
+                self.base, list(addons), scaffold.project_variables("x")
+            )
+        )
+
+    def _render_web(self, *picked):
+        base = scaffold.find_template("go/web")
+        addons = [scaffold.find_addon(a, base) for a in picked]
+        variables = scaffold.project_variables("demo")
+        return dict(scaffold.render_project(base, addons, variables))
+
+    def test_parse_with_args(self):
+        self.assertEqual(
+            scaffold.parse_with_args(["docker,sqlite", "docker", " a "]),
+            ["docker", "sqlite", "a"],
+        )
+
+    def test_language_addon_preferred_over_generic(self):
+        _write_template(self.root / "addons", "docker", {"generic": ""})
+        addon = self._addon(
+            "go/docker", {"Dockerfile": "FROM {{MODULE_NAME}}"}
+        )
+        files = self._render(addon)
+        self.assertEqual(files["Dockerfile"], "FROM x")
+        self.assertNotIn("generic", files)
+
+    def test_generic_addon_fallback(self):
+        addon = self._addon("ci", {".ci.yml": "on: push"})
+        self.assertIn(".ci.yml", self._render(addon))
+
+    def test_addon_files_replace_base(self):
+        addon = self._addon("m", {"main.go": "replaced"})
+        self.assertEqual(self._render(addon)["main.go"], "replaced")
+
+    def test_patches(self):
+        addon = self._addon(
+            "p",
+            {},
+            """
+[[patches]]
+file = "main.go"
+after = "mux := 1"
+insert = "\\tuse(mux)"
+
+[[patches]]
+file = "main.go"
+before = "mux := 1"
+insert = "\\t// {{PROJECT_NAME}}"
+
+[[patches]]
+file = "go.mod"
+append = "require a v1\\n"
+""",
+        )
+        files = self._render(addon)
+        self.assertEqual(
+            files["main.go"],
+            "func main() {\n\t// x\n\tmux := 1\n\tuse(mux)\n}\n",
+        )
+        self.assertEqual(files["go.mod"], "go 1\nrequire a v1\n")
+
+    def test_replace_patch(self):
+        addon = self._addon(
+            "p",
+            {},
+            '[[patches]]\nfile = "main.go"\nreplace = "mux := 1"\n'
+            'insert = "\\tmux := 2"\n',
+        )
+        self.assertEqual(
+            self._render(addon)["main.go"], "func main() {\n\tmux := 2\n}\n"
+        )
+
+    def test_builtin_watch_replaces_air(self):
+        base = scaffold.find_template("go/web")
+        addon = scaffold.find_addon("watch", base)
+        variables = {
+            **scaffold.project_variables("demo"),
+            "TASK_RUNNER": "make",
+        }
+        files = dict(scaffold.render_project(base, [addon], variables))
+        dev = files["Makefile"].split("\ndev:\n", 1)[1].splitlines()[0]
+        self.assertIn("templ generate --watch", dev)
+        self.assertIn('--cmd="go run ."', dev)
+        self.assertNotIn("air", dev)
+        variables["TASK_RUNNER"] = "just"
+        files = dict(scaffold.render_project(base, [addon], variables))
+        self.assertNotIn("air -proxy", files["justfile"])
+        self.assertIn("templ generate --watch", files["justfile"])
+
+    def test_missing_anchor_exits(self):
+        addon = self._addon(
+            "p",
+            {},
+            '[[patches]]\nfile = "main.go"\nafter = "nope"\ninsert = "x"\n',
+        )
+        with self.assertRaises(SystemExit):
+            self._render(addon)
+
+    def test_missing_patch_target_exits(self):
+        addon = self._addon(
+            "p", {}, '[[patches]]\nfile = "nope.go"\nappend = "x"\n'
+        )
+        with self.assertRaises(SystemExit):
+            self._render(addon)
+
+    def test_templates_restriction(self):
+        with self.assertRaises(SystemExit):
+            self._addon("web-only", {}, 'templates = ["go/web"]\n')
+
+    def test_needed_addons_apply_first(self):
+        sessions = self._addon(
+            "sessions",
+            {},
+            '[[patches]]\nfile = "main.go"\nbefore = "}"\n'
+            'insert = "\\ts := 1"\n',
+        )
+        auth = self._addon(
+            "auth",
+            {},
+            'needs = ["sessions"]\n\n[[patches]]\nfile = "main.go"\n'
+            'after = "s := 1"\ninsert = "\\tuse(s)"\n',
+        )
+        main = self._render(auth, sessions)["main.go"]
+        self.assertIn("\ts := 1\n\tuse(s)\n", main)
+        with self.assertRaises(SystemExit):
+            self._render(auth)
+
+    def test_unknown_addon_exits(self):
+        with self.assertRaises(SystemExit):
+            scaffold.find_addon("nope", self.base, root=self.root / "addons")
+
+    def test_builtin_go_web_addons_apply(self):
+        base = scaffold.find_template("go/web")
+        addons = [
+            scaffold.find_addon(n, base)
+            for n in ("docker", "sqlite", "tailwind")
+        ]
+        files = dict(
+            scaffold.render_project(
+                base, addons, scaffold.project_variables("demo")
+            )
+        )
+        self.assertIn("Dockerfile", files)
+        self.assertIn("internal/web/static/css/input.css", files)
+        self.assertIn('static.URL("app.css")', files["components/page.templ"])
+        self.assertIn('"demo.db"', files["internal/config/config.go"])
+
+    def test_builtin_devcontainer_follows_the_toolchain(self):
+        def dockerfile(name):
+            base = scaffold.find_template(name)
+            addon = scaffold.find_addon("devcontainer", base)
+            files = dict(
+                scaffold.render_project(
+                    base, [addon], scaffold.project_variables("demo")
+                )
+            )
+            config = json.loads(files[".devcontainer/devcontainer.json"])
+            self.assertEqual(config["name"], "demo")
+            return files[".devcontainer/Dockerfile"]
+
+        self.assertIn("a-h/templ", dockerfile("go/web"))
+        self.assertNotIn("a-h/templ", dockerfile("go"))
+        self.assertIn("devcontainers/go", dockerfile("go"))
+        self.assertIn("bun.sh", dockerfile("typescript/web"))
+        self.assertIn("astral-sh/uv", dockerfile("python"))
+        self.assertIn("bacon", dockerfile("rust/web"))
+        self.assertIn(
+            "devcontainer", scaffold.addon_names(scaffold.find_template("go"))
+        )
+
+    def test_builtin_nix_and_direnv(self):
+        def render(name, *picked):
+            base = scaffold.find_template(name)
+            addons = [scaffold.find_addon(a, base) for a in picked]
+            variables = scaffold.project_variables("demo")
+            return dict(scaffold.render_project(base, addons, variables))
+
+        files = render("go/web", "direnv", "nix")
+        self.assertIn("templ", files["flake.nix"])
+        self.assertIn('"demo development shell"', files["flake.nix"])
+        self.assertEqual(
+            files[".envrc"],
+            "use flake\n"
+            "dotenv_if_exists\n"
+            'export PORT="${PORT:-4000}"\n'
+            "export APP_PROFILE=1\n"
+            "source_env_if_exists .envrc.local\n",
+        )
+        self.assertIn(".direnv/", files[".gitignore"])
+        self.assertIn(".envrc.local", files[".gitignore"])
+        self.assertTrue(
+            render("go", "nix", "direnv")[".envrc"].startswith("use flake\n")
+        )
+        self.assertTrue(
+            render("go", "direnv")[".envrc"].startswith("dotenv_if_exists\n")
+        )
+        self.assertNotIn("templ", render("go", "nix")["flake.nix"])
+        self.assertIn("python312", render("python/web", "nix")["flake.nix"])
+
+    def test_builtin_git_hook_addons(self):
+        def render(name, addon):
+            base = scaffold.find_template(name)
+            found = scaffold.find_addon(addon, base)
+            variables = scaffold.project_variables("demo")
+            files = scaffold.render_project(base, [found], variables)
+            return found, dict(files)
+
+        addon, files = render("go/web", "lefthook")
+        self.assertEqual(addon.manifest["hooks"]["git"], ["lefthook install"])
+        self.assertIn("templ generate", files["lefthook.yml"])
+        self.assertIn("gofmt", files["lefthook.yml"])
+        _, files = render("go", "pre-commit")
+        self.assertIn("go vet ./...", files[".pre-commit-config.yaml"])
+        self.assertNotIn("templ", files[".pre-commit-config.yaml"])
+        addon, files = render("typescript/web", "pre-commit")
+        self.assertIn("eslint", files[".pre-commit-config.yaml"])
+        self.assertIn("eslint.config.js", files)
+        self.assertEqual(
+            addon.manifest["hooks"]["git"], ["pre-commit install"]
+        )
+        _, files = render("python/web", "lefthook")
+        self.assertIn("ruff", files["lefthook.yml"])
+
+    def test_builtin_docker_compose(self):
+        files = self._render_web("docker")
+        self.assertNotIn("db:", files["compose.yaml"])
+        self.assertNotIn(".env", files)
+        self.assertIn("distroless", files["Dockerfile"])
+        self.assertIn(".env", files[".dockerignore"])
+        files = self._render_web("postgres", "docker", "sqlite")
+        self.assertIn("POSTGRES_PASSWORD=", files[".env"])
+        text = files["compose.yaml"]
+        environment = text.split("environment:\n", 1)[1]
+        self.assertTrue(
+            environment.startswith(
+                "      PORT: ${PORT:-4000}\n"
+                "      DATABASE_PATH: /data/demo.db\n"
+                "      DATABASE_URL: postgres://app:${POSTGRES_PASSWORD}@db"
+            )
+        )
+        self.assertIn("    depends_on:\n      db:\n", text)
+        self.assertIn("    volumes:\n      - ./data:/data\n", text)
+        self.assertIn("image: postgres:17", text)
+        config = files["internal/config/config.go"]
+        self.assertIn("\n\n\tDatabasePath string // DATABASE_PATH", config)
+        self.assertIn('c.DatabaseURL = getenv("DATABASE_URL")', config)
+        self.assertIn(
+            '"DATABASE_URL": "postgres://',
+            files["internal/config/config_test.go"],
+        )
+        self.assertIn(
+            "store.Open(context.Background(), cfg.DatabasePath)",
+            files["main.go"],
+        )
+
+    def test_builtin_sqlite_store(self):
+        base = scaffold.find_template("go/web")
+        addon = scaffold.find_addon("sqlite", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        self.assertIn('"demo/internal/store"', files["main.go"])
+        self.assertIn(
+            '"POST /api/greetings", handleCreateGreeting(queries)',
+            files["main.go"],
+        )
+        self.assertIn(
+            "//go:embed migrations/*.sql", files["internal/store/store.go"]
+        )
+        self.assertIn("out: internal/store", files["sqlc.yaml"])
+        self.assertIn(
+            "-- name: ListGreetings :many",
+            files["internal/store/queries/greetings.sql"],
+        )
+        self.assertIn("demo.db-*", files[".gitignore"])
+
+    def test_builtin_databases_page_their_lists(self):
+        base = scaffold.find_template("go/web")
+        variables = scaffold.project_variables("demo")
+        for name, route, handler in (
+            ("sqlite", "/greetings", "handleGreetingsPage(queries)"),
+            ("postgres", "/notes", "handleNotesPage(pg)"),
+        ):
+            addon = scaffold.find_addon(name, base)
+            files = dict(scaffold.render_project(base, [addon], variables))
+            self.assertIn(
+                f'mux.HandleFunc("GET {route}", {handler})', files["main.go"]
+            )
+            self.assertIn(
+                "func FromQuery(q url.Values) (Page, error)",
+                files["internal/paging/paging.go"],
+            )
+            self.assertIn(
+                "templ Pager(p paging.Page, u *url.URL)",
+                files["components/pager.templ"],
+            )
+            self.assertIn(
+                f'{{"{route}", http.StatusOK,', files["integration_test.go"]
+            )
+
+    def test_builtin_postgres(self):
+        base = scaffold.find_template("go/web")
+        addon = scaffold.find_addon("postgres", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        self.assertIn('"demo/internal/postgres"', files["main.go"])
+        self.assertIn(
+            "postgres.Open(context.Background(), cfg.DatabaseURL, "
+            "cfg.DatabaseMaxConns)",
+            files["main.go"],
+        )
+        self.assertIn(
+            "-- +goose Up",
+            files["internal/postgres/migrations/00001_create_notes.sql"],
+        )
+        integration = files["internal/postgres/postgres_integration_test.go"]
+        self.assertTrue(integration.startswith("//go:build integration\n"))
+        self.assertIn("127.0.0.1:5432:5432", files["compose.db.yaml"])
+        self.assertIn("go test -tags integration", files["justfile"])
+        self.assertIn("writeJSON", files["json.go"])
+
+    def test_builtin_sessions(self):
+        files = self._render_web("sessions")
+        self.assertIn("\t\tsessions.LoadAndSave,\n", files["main.go"])
+        self.assertNotIn("sessions.Store", files["main.go"])
+        self.assertNotIn("internal/store/sessions.go", files)
+        files = self._render_web("sqlite", "sessions")
+        self.assertIn(
+            "sessions.Store = store.NewSessionStore(db)", files["main.go"]
+        )
+        self.assertIn(
+            "internal/store/migrations/0002_create_sessions.sql", files
+        )
+        self.assertNotIn("internal/postgres/sessions.go", files)
+        main = self._render_web("sessions", "sqlite", "postgres")["main.go"]
+        self.assertLess(
+            main.index("store.NewSessionStore"),
+            main.index("postgres.NewSessionStore"),
+        )
+
+    def test_builtin_auth(self):
+        with self.assertRaises(SystemExit):
+            self._render_web("auth")
+        files = self._render_web("auth", "sessions")
+        main = files["main.go"]
+        self.assertLess(
+            main.index("sessions := newSessions(cfg)"),
+            main.index("accounts := &auth.Accounts{"),
+        )
+        self.assertIn('"demo/internal/auth"\n\t"demo/internal/config"', main)
+        self.assertIn("{ email }", files["components/accounts.templ"])
+        forms = files["components/accounts.templ"]
+        self.assertEqual(forms.count("@CSRFField()"), forms.count("<form"))
+        self.assertIn("middleware.CSRF(cfg.HTTPS())", main)
+        self.assertNotIn("internal/auth/sqlite.go", files)
+        files = self._render_web("postgres", "sessions", "auth")
+        self.assertIn(
+            "accounts.Users = auth.NewPostgresUsers(pg)", files["main.go"]
+        )
+        self.assertIn(
+            "internal/postgres/migrations/00003_create_users.sql", files
+        )
+
+    def test_builtin_oauth(self):
+        files = self._render_web("sessions", "oauth")
+        self.assertNotIn("signin_accounts.go", files)
+        self.assertNotIn("signIn.Done", files["main.go"])
+        self.assertIn("/auth/{provider}/callback", files["main.go"])
+        self.assertIn("OAUTH_GITHUB", files["internal/config/config.go"])
+        files = self._render_web("sessions", "auth", "oauth")
+        self.assertIn("signin_accounts.go", files)
+        self.assertIn(
+            "signIn.Done = loginAccount(accounts, signIn)", files["main.go"]
+        )
+
+    def test_builtin_lambda_deploys(self):
+        base = scaffold.find_template("go/lambda")
+        addons = [scaffold.find_addon(a, base) for a in ("sam", "terraform")]
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, addons, variables))
+        self.assertIn("CodeUri: dist/sqs.zip", files["template.yaml"])
+        self.assertIn('default     = "demo"', files["deploy/main.tf"])
+        self.assertIn("ReportBatchItemFailures", files["deploy/sqs.tf"])
+        self.assertIn("\ndeploy-sam: zip\n", files["justfile"])
+        self.assertIn("\ndeploy-terraform: zip\n", files["justfile"])
+
+    def test_builtin_tinygo(self):
+        base = scaffold.find_template("go/wasm")
+        addon = scaffold.find_addon("tinygo", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        justfile = files["justfile"]
+        self.assertIn("    tinygo build -o dist/main.wasm", justfile)
+        self.assertIn("TINYGOROOT)/targets/wasm_exec.js", justfile)
+        self.assertNotIn("GOOS=js GOARCH=wasm go build", justfile)
+
+    def test_builtin_metrics(self):
+        base = scaffold.find_template("go/web")
+        addon = scaffold.find_addon("metrics", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        main = files["main.go"]
+        self.assertLess(
+            main.index("middleware.Logger(logger),"),
+            main.index("recorder.Middleware(mux),"),
+        )
+        self.assertLess(
+            main.index("recorder.Middleware(mux),"),
+            main.index("middleware.RecoverWith("),
+        )
+        self.assertIn("prometheus/client_golang", files["go.mod"])
+
+    def test_builtin_otel(self):
+        base = scaffold.find_template("go/web")
+        addon = scaffold.find_addon("otel", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        self.assertIn("\ngo 1.22\n", files["go.mod"])
+        main = files["main.go"]
+        self.assertIn("defer stopTracing()", main)
+        self.assertIn("middleware.RequestID,\n\t\ttraced(mux),", main)
+        self.assertIn('tracer().Start(r.Context(), "greet")', main)
+
+    def test_builtin_websocket(self):
+        base = scaffold.find_template("go/web")
+        addon = scaffold.find_addon("websocket", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        main = files["main.go"]
+        self.assertIn('mux.Handle("GET /ws/chat", hub)', main)
+        self.assertLess(
+            main.index("hub.Shutdown(shutdownCtx)"),
+            main.index("srv.Shutdown(shutdownCtx)"),
+        )
+        self.assertIn("@Chat()", files["components/home.templ"])
+        self.assertIn("internal/web/static/chat.js", files)
+
+    def test_builtin_sse(self):
+        base = scaffold.find_template("go/web")
+        addon = scaffold.find_addon("sse", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        main = files["main.go"]
+        self.assertIn('mux.Handle("GET /events", clock)', main)
+        self.assertIn("srv.RegisterOnShutdown(clock.Close)", main)
+        self.assertIn("@Clock()", files["components/home.templ"])
+
+    def test_builtin_i18n(self):
+        files = self._render_web("i18n")
+        main = files["main.go"]
+        imports = '\t"demo/internal/i18n"\n\t"demo/internal/logging"'
+        self.assertIn(imports, main)
+        self.assertLess(
+            main.index("translations.Negotiate,"),
+            main.index("middleware.CSRF("),
+        )
+        self.assertIn('i18n.T(r.Context(), "Hello from the server!")', main)
+        self.assertIn(
+            "<html lang={ i18n.Lang(ctx) }>", files["components/page.templ"]
+        )
+        home = files["components/home.templ"]
+        self.assertTrue(home.startswith('package components\n\nimport "'))
+        self.assertIn('{ i18n.T(ctx, "Get Greeting") }', home)
+        self.assertIn("golang.org/x/text", files["go.mod"])
+        self.assertIn("internal/i18n/locales/nb.json", files)
+
+    def test_builtin_gateway(self):
+        base = scaffold.find_template("go/grpc")
+        addon = scaffold.find_addon("gateway", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        proto = files["proto/greet/v1/greet.proto"]
+        self.assertIn('import "google/api/annotations.proto";', proto)
+        self.assertIn('post: "/v1/greet"', proto)
+        self.assertIn("buf.build/googleapis/googleapis", files["buf.yaml"])
+        self.assertIn("grpc-ecosystem/gateway", files["buf.gen.yaml"])
+        self.assertIn("newGateway(conn)", files["main.go"])
+        config = files["internal/config/config.go"]
+        self.assertIn('getenv("GATEWAY_PORT")', config)
+        with self.assertRaises(SystemExit):
+            scaffold.find_addon("gateway", scaffold.find_template("go/web"))
+
+    def test_builtin_cobra(self):
+        base = scaffold.find_template("go/cli")
+        addon = scaffold.find_addon("cobra", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        self.assertIn("github.com/spf13/cobra v1.8.1", files["go.mod"])
+        for path in ("cmd/root.go", "cmd/completion.go", "cmd/man.go"):
+            self.assertIn('"github.com/spf13/cobra"', files[path])
+            self.assertNotIn('"flag"', files[path])
+        self.assertIn("GenZshCompletion", files["cmd/completion.go"])
+        with self.assertRaises(SystemExit):
+            scaffold.find_addon("cobra", scaffold.find_template("go/api"))
+
+    def test_builtin_tailwind(self):
+        base = scaffold.find_template("go/web")
+        addons = [
+            scaffold.find_addon(a, base)
+            for a in ("tailwind", "docker", "devcontainer")
+        ]
+        variables = {
+            **scaffold.project_variables("demo"),
+            "TASK_RUNNER": "make",
+        }
+        files = dict(scaffold.render_project(base, addons, variables))
+        self.assertIn("internal/web/static/app.css", files[".gitignore"])
+        self.assertIn("tailwindcss -i", files[".air.toml"])
+        self.assertIn("\ncss-watch:\n\ttailwindcss", files["Makefile"])
+        self.assertIn(
+            "internal/web/static/app.css --minify", files["Dockerfile"]
+        )
+        self.assertIn("tailwindcss", files[".devcontainer/Dockerfile"])
+        self.assertIn("@source", files["internal/web/static/css/input.css"])
+        files = dict(scaffold.render_project(base, addons[1:], variables))
+        self.assertNotIn("tailwindcss", files["Dockerfile"])
+
+    def test_builtin_htmx(self):
+        base = scaffold.find_template("go/web")
+        addon = scaffold.find_addon("htmx", base)
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(base, [addon], variables))
+        page = files["components/page.templ"]
+        self.assertIn('<script src={ static.URL("htmx.min.js") }>', page)
+        self.assertNotIn("unpkg.com", page)
+        self.assertIn("if !isHTMX(r) {", files["main.go"])
+        self.assertIn("func TestGreetReturnsAFragment", files["htmx_test.go"])
+        self.assertIn("htmx.org@2.0.4/dist/htmx.min.js", files["justfile"])
+        self.assertEqual(
+            addon.manifest["install"]["run"][0].split()[-1],
+            "https://unpkg.com/htmx.org@2.0.4/dist/htmx.min.js",
+        )
+
+    def test_builtin_alpine(self):
+        base = scaffold.find_template("go/web")
+        variables = scaffold.project_variables("demo")
+        for names in (("alpine", "htmx", "tailwind"), ("tailwind", "alpine")):
+            addons = [scaffold.find_addon(a, base) for a in names]
+            files = dict(scaffold.render_project(base, addons, variables))
+            css = files["internal/web/static/css/input.css"]
+            self.assertIn('@source "../js";', css)
+        page = files["components/page.templ"]
+        self.assertLess(
+            page.index('static.URL("js/components.js")'),
+            page.index('<script defer src={ static.URL("alpine.min.js") }>'),
+        )
+        home = files["components/home.templ"]
+        self.assertIn("\t\t@Counter()\n\t</main>", home)
+        script = files["internal/web/static/js/components.js"]
+        self.assertIn('Alpine.data("counter"', script)
+        self.assertIn("@alpinejs/csp@", files["justfile"])
+
+    def test_builtin_esbuild(self):
+        base = scaffold.find_template("go/web")
+        addons = [
+            scaffold.find_addon(a, base)
+            for a in ("esbuild", "docker", "devcontainer")
+        ]
+        variables = {
+            **scaffold.project_variables("demo"),
+            "TASK_RUNNER": "make",
+        }
+        files = dict(scaffold.render_project(base, addons, variables))
+        self.assertIn("internal/web/static/dist/", files[".gitignore"])
+        page = files["components/page.templ"]
+        self.assertIn('<script type="module" src={ Asset("main.js") }>', page)
+        self.assertIn("func Asset(", files["components/assets.go"])
+        self.assertIn("\nassets-watch:\n\tesbuild", files["Makefile"])
+        self.assertIn("--entry-names=[name]-[hash]", files["Makefile"])
+        self.assertIn("esbuild@v0.24.2 assets/main.ts", files["Dockerfile"])
+        self.assertIn("esbuild", files[".devcontainer/Dockerfile"])
+        files = dict(scaffold.render_project(base, addons[1:], variables))
+        self.assertNotIn("esbuild", files["Dockerfile"])
+
+    def test_builtin_ci(self):
+        def render(name, provider="github"):
+            base = scaffold.find_template(name)
+            addon = scaffold.find_addon("ci", base)
+            variables = {
+                **scaffold.project_variables("demo"),
+                **scaffold.resolve_variables(
+                    addon, {"ci_provider": provider}, True
+                ),
+            }
+            return dict(scaffold.render_project(base, [addon], variables))
+
+        files = render("go/web")
+        workflow = files[".github/workflows/ci.yml"]
+        self.assertNotIn(".gitlab-ci.yml", files)
+        self.assertIn("templ generate", workflow)
+        self.assertIn("{{.Version}}", workflow)
+        self.assertIn("cache-dependency-path: go.sum", workflow)
+        files = render("rust/web", "gitlab")
+        self.assertNotIn(".github/workflows/ci.yml", files)
+        self.assertIn("cargo test", files[".gitlab-ci.yml"])
+        workflow = render("python")[".github/workflows/ci.yml"]
+        self.assertIn("uv run pytest", workflow)
+        self.assertIn(
+            "hashFiles('bun.lock'",
+            render("typescript/web")[".github/workflows/ci.yml"],
+        )
+
+    def test_builtin_e2e(self):
+        base = scaffold.find_template("go/web")
+        e2e = scaffold.find_addon("e2e", base)
+        files = self._render_web("e2e")
+        test = files["e2e_test.go"]
+        self.assertTrue(test.startswith("//go:build e2e\n"))
+        self.assertIn("ts := startApp(t, nil)", test)
+        self.assertIn("github.com/chromedp/chromedp", files["go.mod"])
+        self.assertIn("-tags e2e -run Browser -count=1 .", files["justfile"])
+        ci = scaffold.find_addon("ci", base)
+        for provider, path in (
+            ("github", ".github/workflows/ci.yml"),
+            ("gitlab", ".gitlab-ci.yml"),
+        ):
+            variables = {
+                **scaffold.project_variables("demo"),
+                **scaffold.resolve_variables(
+                    ci, {"ci_provider": provider}, True
+                ),
+            }
+            files = dict(scaffold.render_project(base, [ci, e2e], variables))
+            self.assertIn("go test -tags e2e -run Browser ./...", files[path])
+            files = dict(scaffold.render_project(base, [ci], variables))
+            self.assertNotIn("-tags e2e", files[path])
+
+    def test_builtin_upload(self):
+        files = self._render_web("upload")
+        main = files["main.go"]
+        self.assertIn("uploads := blob.Dir(cfg.UploadDir)", main)
+        self.assertIn(
+            'mux.HandleFunc("POST /upload", '
+            "handleUpload(uploads, cfg.UploadMaxBytes))",
+            main,
+        )
+        self.assertIn('"/debug/pprof/", "/upload"),', main)
+        config = files["internal/config/config.go"]
+        self.assertIn("UploadMaxBytes int64", config)
+        self.assertIn("func (d Dir) Put(", files["internal/blob/blob.go"])
+        form = files["components/upload.templ"]
+        self.assertIn("templ UploadForm(limit int64)", form)
+        self.assertIn('href="/upload"', files["components/home.templ"])
+        self.assertIn('"upload.html"}', files["integration_test.go"])
+        self.assertIn("uploads/", files[".gitignore"])
+
+    def test_builtin_jobs(self):
+        files = self._render_web("jobs")
+        main = files["main.go"]
+        self.assertIn(
+            "queue := jobs.New(jobs.WorkerFunc(work), cfg.JobWorkers, "
+            "cfg.JobQueue)",
+            main,
+        )
+        self.assertIn("if err := queue.Drain(ctx); err != nil {", main)
+        self.assertIn('"POST /api/welcome", handleWelcome(queue)', main)
+        config = files["internal/config/config.go"]
+        self.assertIn('getenv("JOB_WORKERS")', config)
+        self.assertIn("func (q *Queue) Drain(", files["internal/jobs/jobs.go"])
+        # Whatever the order picked, the drain is deferred after the
+        # database's Close, to run first.
+        main = self._render_web("jobs", "sqlite")["main.go"]
+        self.assertLess(
+            main.index("defer db.Close()"), main.index("queue.Drain(ctx)")
+        )
+
+    def test_builtin_schedule(self):
+        files = self._render_web("schedule")
+        main = files["main.go"]
+        self.assertIn("tasks := schedule.New(schedule.System, logger)", main)
+        self.assertIn(
+            'tasks.Every("stats", cfg.Schedules["stats"], logStats)', main
+        )
+        self.assertIn("\tdefer tasks.Stop()\n", main)
+        self.assertIn(
+            'getenv("SCHEDULES")', files["internal/config/config.go"]
+        )
+        self.assertIn(
+            "type fakeClock struct",
+            files["internal/schedule/schedule_test.go"],
+        )
+        main = self._render_web("schedule", "sqlite")["main.go"]
+        self.assertLess(
+            main.index("defer db.Close()"), main.index("defer tasks.Stop()")
+        )
+
+    def test_builtin_mail(self):
+        files = self._render_web("mail")
+        main = files["main.go"]
+        self.assertIn(
+            "handleContact(mailContact(mailer, cfg.MailFrom, cfg.MailTo))",
+            main,
+        )
+        self.assertIn('mux.Handle("GET /dev/mail", mailbox)', main)
+        config = files["internal/config/config.go"]
+        self.assertIn("demo <noreply@localhost>", config)
+        self.assertIn(
+            '{{define "subject"}}A message from {{.Name}}{{end -}}',
+            files["internal/mail/templates/contact.txt"],
+        )
+        self.assertIn("type Sender interface", files["internal/mail/mail.go"])
+
+    def test_builtin_cache(self):
+        files = self._render_web("cache")
+        main = files["main.go"]
+        self.assertIn(
+            'responses := cache.New(cfg.CacheTTL, 1000, "Accept-Language")',
+            main,
+        )
+        self.assertLess(
+            main.index("middleware.CSRF(cfg.HTTPS())"),
+            main.index('cache.ETag("/debug/pprof/")'),
+        )
+        self.assertIn(
+            'middleware.Under("/api/greet", responses.Handler)', main
+        )
+        self.assertIn(
+            'getenv, "CACHE_TTL"', files["internal/config/config.go"]
+        )
+        self.assertIn("func Fresh(", files["internal/cache/etag.go"])
+
+    def test_template_addon_preferred_over_language(self):
+        _write_template(self.root / "lang", "go/web", {})
+        base = scaffold.find_template("go/web", root=self.root / "lang")
+        _write_template(self.root / "addons", "go/ci", {"go": ""})
+        _write_template(self.root / "addons", "go/web/ci", {"web": ""})
+        root = self.root / "addons"
+        self.assertEqual(
+            scaffold.find_addon("ci", base, root).path.parent.name, "web"
+        )
+        self.assertEqual(
+            scaffold.find_addon("ci", self.base, root).path.parent.name, "go"
+        )
+        self.assertEqual(scaffold.addon_names(base, root), ["ci"])
+
+
+HOOKS_MANIFEST = """
+[hooks]
+pre = ["test -z \\"$(ls -A)\\" && echo pre > pre.txt"]
+post = ["test -f main.txt && echo {{PROJECT_NAME}} > post.txt"]
+"""
+
+
+class TestHooks(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        self.root = Path(self.tmpdir) / "templates"
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, manifest, *argv):
+        _write_template(self.root, "t", {"main.txt": "x"}, manifest)
+        args = jolo.parse_args(
+            ["new", "t", "demo", "--templates-dir", str(self.root), *argv]
+        )
+        with (
+            mock.patch("sys.stderr"),
+            mock.patch("builtins.print"),
+        ):
+            scaffold.run_new_mode(args)
+        return Path(self.tmpdir) / "demo"
+
+    def test_pre_runs_before_and_post_after_files(self):
+        demo = self._new(HOOKS_MANIFEST)
+        self.assertEqual((demo / "pre.txt").read_text(), "pre\n")
+        self.assertEqual((demo / "post.txt").read_text(), "demo\n")
+
+    def test_no_hooks(self):
+        demo = self._new(HOOKS_MANIFEST, "--no-hooks")
+        self.assertTrue((demo / "main.txt").exists())
+        self.assertFalse((demo / "pre.txt").exists())
+        self.assertFalse((demo / "post.txt").exists())
+
+    def test_output_archive_skips_hooks(self):
+        _write_template(self.root, "t", {"main.txt": "x"}, HOOKS_MANIFEST)
+        argv = ["new", "t", "demo", "--templates-dir", str(self.root)]
+        args = jolo.parse_args([*argv, "--output", "demo.zip"])
+        with mock.patch("sys.stderr", io.StringIO()) as err:
+            scaffold.run_new_mode(args)
+        self.assertIn("Skipping hooks", err.getvalue())
+        with zipfile.ZipFile("demo.zip") as zf:
+            self.assertEqual(
+                zf.namelist(),
+                [
+                    "demo/main.txt",
+                    "demo/LICENSE",
+                    "demo/README.md",
+                    "demo/jolo.lock",
+                ],
+            )
+        self.assertFalse((Path(self.tmpdir) / "demo").exists())
+
+    def test_failing_hook_removes_target(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._new('[hooks]\npost = ["exit 3"]\n')
+        self.assertIn("exit 3", str(cm.exception))
+        self.assertFalse((Path(self.tmpdir) / "demo").exists())
+
+
+class TestDryRun(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def _dry_run(self, *argv):
+        args = jolo.parse_args(["new", *argv, "--dry-run"])
+        with mock.patch("builtins.print") as fake_print:
+            scaffold.run_new_mode(args)
+        return [c.args[0] if c.args else "" for c in fake_print.call_args_list]
+
+    def test_format_tree(self):
+        lines = scaffold.format_tree(
+            "demo/",
+            [
+                ("b.txt", 3, "create"),
+                ("a/x.txt", 2048, "overwrite"),
+                ("a/y.txt", 0, "unchanged"),
+            ],
+        )
+        self.assertEqual(
+            lines,
+            [
+                "demo/",
+                "├── a/",
+                "│   ├── x.txt    2.0 KB  overwrite",
+                "│   └── y.txt       0 B  unchanged",
+                "└── b.txt           3 B  create",
+            ],
+        )
+
+    def test_plan_files_compares_with_target(self):
+        target = Path(self.tmpdir) / "t"
+        target.mkdir()
+        (target / "same").write_text("x")
+        (target / "diff").write_text("y")
+        plan = scaffold.plan_files(
+            [("same", "x"), ("diff", "x"), ("new", "x")], target
+        )
+        self.assertEqual(
+            [action for _, _, action in plan],
+            ["unchanged", "overwrite", "create"],
+        )
+
+    def test_writes_nothing(self):
+        lines = self._dry_run("go", "demo", "--no-install")
+        self.assertFalse((Path(self.tmpdir) / "demo").exists())
+        self.assertTrue(any("go.mod" in line for line in lines))
+        self.assertIn("create", lines[-1])
+
+    def test_install_commands_listed(self):
+        lines = self._dry_run("go", "demo")
+        self.assertEqual(
+            lines[-3:],
+            [
+                "install: go mod tidy",
+                "verify: go vet ./...",
+                "verify: go test ./...",
+            ],
+        )
+
+    def test_policy_shown_for_conflicts(self):
+        (Path(self.tmpdir) / "demo").mkdir()
+        (Path(self.tmpdir) / "demo" / "main.go").write_text("old")
+        lines = self._dry_run("go", "demo", "--on-conflict=backup")
+        main = next(line for line in lines if "main.go" in line)
+        self.assertTrue(main.endswith("backup"))
+
+    def test_existing_dir_is_reported_not_refused(self):
+        (Path(self.tmpdir) / "demo").mkdir()
+        (Path(self.tmpdir) / "demo" / "main.go").write_text("old")
+        lines = self._dry_run("go", "demo")
+        main = next(line for line in lines if "main.go" in line)
+        self.assertTrue(main.endswith("overwrite"))
+        self.assertEqual(
+            (Path(self.tmpdir) / "demo" / "main.go").read_text(), "old"
+        )
+
+
+CONFIG = """
+[new]
+author = "Ada"
+email = "ada@example.com"
+module_prefix = "github.com/ada/"
+templates = ["python", "go"]
+
+[new.addons]
+"go/web" = ["docker"]
+
+[new.vars]
+greeting = "hello"
+
+[new.for."go/web"]
+module_prefix = "github.com/web-team"
+
+[new.for."go/web".vars]
+greeting = "hi"
+
+[new.for.go]
+email = "go@example.com"
+
+[profiles.work.new]
+author = "Ada at Work"
+module_prefix = "git.corp.example/ada"
+"""
+
+
+class TestNewConfig(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        self.config_dir = Path(self.tmpdir) / "config"
+        self.config_dir.mkdir()
+        (self.config_dir / "config.toml").write_text(CONFIG)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def _load(self, profile=None):
+        return jolo.load_config(
+            global_config_dir=self.config_dir, profile=profile
+        )
+
+    def _new(self, *argv):
+        args = jolo.parse_args(["new", *argv])
+        with (
+            mock.patch.object(
+                scaffold,
+                "load_config",
+                lambda profile=None: self._load(profile),
+            ),
+            mock.patch("builtins.print"),
+        ):
+            scaffold.run_new_mode(args)
+        return scaffold.read_lock(Path(self.tmpdir) / args.name)
+
+    def test_profile_merges_into_tables(self):
+        new = self._load("work")["new"]
+        self.assertEqual(new["author"], "Ada at Work")
+        self.assertEqual(new["module_prefix"], "git.corp.example/ada")
+        self.assertEqual(new["templates"], ["python", "go"])
+
+    def test_unknown_profile_exits(self):
+        with self.assertRaises(SystemExit):
+            self._load("nope")
+
+    def test_config_variables(self):
+        self.assertEqual(
+            scaffold.config_variables(self._load()["new"], "app"),
+            {
+                "AUTHOR": "Ada",
+                "EMAIL": "ada@example.com",
+                "LICENSE": "MIT",
+                "MODULE_PATH": "github.com/ada/app",
+            },
+        )
+
+    def test_module_prefix_reaches_templates(self):
+        template = scaffold.Template("t", Path(self.tmpdir))
+        (Path(self.tmpdir) / "go.mod").write_text("module {{MODULE_PATH}}")
+        variables = scaffold.project_variables("app")
+        variables.update(scaffold.config_variables(self._load()["new"], "app"))
+        files = dict(scaffold.render_template(template, variables))
+        self.assertEqual(files["go.mod"], "module github.com/ada/app")
+
+    def test_profile_flag(self):
+        lock = self._new("go", "app", "--profile", "work")
+        self.assertEqual(lock["variables"]["AUTHOR"], "Ada at Work")
+
+    def test_flags_override_config(self):
+        lock = self._new("go", "app", "--var", "AUTHOR=Grace")
+        self.assertEqual(lock["variables"]["AUTHOR"], "Grace")
+
+    def test_author_and_email_flags(self):
+        lock = self._new(
+            "go",
+            "app",
+            "--var",
+            "AUTHOR=Grace",
+            "--author",
+            "Grace Hopper",
+            "--email",
+            "grace@example.com",
+        )
+        self.assertEqual(lock["variables"]["AUTHOR"], "Grace Hopper")
+        self.assertEqual(lock["variables"]["EMAIL"], "grace@example.com")
+
+    def test_author_and_email_fall_back_to_git(self):
+        git = {"user.name": "Git Name", "user.email": "git@example.com"}
+        with mock.patch.object(scaffold, "_git_config", git.get):
+            self.assertEqual(
+                scaffold.config_variables({}, "app"),
+                {
+                    "AUTHOR": "Git Name",
+                    "EMAIL": "git@example.com",
+                    "LICENSE": "MIT",
+                    "MODULE_PATH": "app",
+                },
+            )
+
+    def test_preferred_templates_listed_first(self):
+        choices = scaffold._template_choices(
+            ["python", "go"], [scaffold.TEMPLATES_ROOT]
+        )
+        names = [n for n, _ in choices]
+        self.assertEqual(names[:2], ["python", "go"])
+        self.assertEqual(len(names), len(set(names)))
+
+    def test_default_addons_per_template(self):
+        lock = self._new("go/web", "app")
+        self.assertEqual(lock["addons"], ["docker"])
+        self.assertTrue(Path("app/Dockerfile").exists())
+        lock = self._new("go/web", "other", "--with", "sqlite")
+        self.assertEqual(lock["addons"], ["sqlite"])
+
+    def test_vars_override_manifest_default(self):
+        template = scaffold.Template(
+            "t", Path(self.tmpdir), {"variables": {"greeting": {}}}
+        )
+        values = scaffold.resolve_variables(
+            template, {}, True, self._load()["new"]["vars"]
+        )
+        self.assertEqual(values, {"greeting": "hello"})
+
+    def test_template_config(self):
+        new = self._load()["new"]
+        web = scaffold.template_config(new, "go/web")
+        self.assertEqual(
+            (web["module_prefix"], web["email"], web["vars"]),
+            ("github.com/web-team", "go@example.com", {"greeting": "hi"}),
+        )
+        go = scaffold.template_config(new, "go")
+        self.assertEqual(
+            (go["module_prefix"], go["email"], go["vars"]),
+            ("github.com/ada/", "go@example.com", {"greeting": "hello"}),
+        )
+        python = scaffold.template_config(new, "python")
+        self.assertEqual(python["email"], "ada@example.com")
+        self.assertNotIn("for", python)
+        self.assertNotIn("for", scaffold.template_config(new, "gopher"))
+
+    def test_per_template_config_reaches_new(self):
+        lock = self._new("go/web", "app", "--no-input")
+        self.assertEqual(
+            lock["variables"]["MODULE_PATH"], "github.com/web-team/app"
+        )
+        self.assertEqual(lock["variables"]["EMAIL"], "go@example.com")
+
+    def test_answer_precedence(self):
+        team = Path(self.tmpdir) / "team"
+        _write_template(
+            team,
+            "go/web",
+            {"README": "{{greeting}}"},
+            '[variables.greeting]\ndefault = "manifest"\n',
+        )
+        answers = Path(self.tmpdir) / "answers.toml"
+        answers.write_text('[variables]\ngreeting = "file"\n')
+        common = ["--templates-dir", str(team), "--no-input"]
+        runs = (
+            ("a", [], "hi"),
+            ("b", ["--answers", str(answers)], "file"),
+            ("c", ["--answers", str(answers), "--var", "greeting=flag"], "flag"),
+        )
+        for name, argv, want in runs:
+            self._new("go/web", name, *common, *argv)
+            self.assertEqual(Path(name, "README").read_text(), want)
+
+
+class TestTemplateLayers(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        self.team = Path(self.tmpdir) / "team"
+        _write_template(
+            self.team, "go/web", {"main.go": "team {{MODULE_NAME}}"}
+        )
+        _write_template(self.team, "zig", {"build.zig": ""})
+        self.env = mock.patch.dict(os.environ, {"JOLO_TEMPLATES": ""})
+        self.env.start()
+
+    def tearDown(self):
+        self.env.stop()
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def test_roots_priority(self):
+        other = Path(self.tmpdir) / "other"
+        other.mkdir()
+        os.environ["JOLO_TEMPLATES"] = f"{other}{os.pathsep}"
+        roots = scaffold.template_roots(
+            [str(self.team)], {"templates_dirs": [self.tmpdir]}
+        )
+        self.assertEqual(
+            roots,
+            [
+                self.team,
+                other,
+                Path(self.tmpdir).resolve(),
+                scaffold.TEMPLATES_ROOT,
+            ],
+        )
+
+    def test_missing_dir_exits(self):
+        with self.assertRaises(SystemExit):
+            scaffold.template_roots(["/nonexistent/jolo"], {})
+
+    def test_layer_shadows_builtin_and_adds_new(self):
+        roots = [self.team, scaffold.TEMPLATES_ROOT]
+        templates = {t.name: t for t in scaffold.list_layered(roots)}
+        self.assertEqual(templates["go/web"].path, self.team / "go/web")
+        self.assertIn("zig", templates)
+        self.assertEqual(templates["go"].path, scaffold.TEMPLATES_ROOT / "go")
+
+    def test_names_stay_inside_their_root(self):
+        outside = Path(self.tmpdir) / "outside"
+        _write_template(outside, "evil", {"x": ""})
+        (self.team / "link").symlink_to(outside / "evil")
+        names = [
+            "../outside/evil",
+            str(outside / "evil"),
+            "go//web",
+            "./zig",
+            "link",
+        ]
+        for name in names:
+            with self.subTest(name=name):
+                with self.assertRaises(SystemExit) as cm:
+                    scaffold.find_layered(name, [self.team])
+                self.assertIn("Unknown template", cm.exception.code)
+
+    def test_new_uses_shadowing_template_and_locks_its_dir(self):
+        os.environ["JOLO_TEMPLATES"] = str(self.team)
+        args = jolo.parse_args(["new", "go/web", "app"])
+        with mock.patch("builtins.print"):
+            scaffold.run_new_mode(args)
+        self.assertEqual(Path("app/main.go").read_text(), "team app")
+        self.assertFalse(Path("app/go.mod").exists())
+        lock = scaffold.read_lock(Path("app"))
+        self.assertEqual(lock["templates_dir"], str(self.team))
+
+
+class TestInheritance(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+        _write_template(
+            self.root,
+            "go/_base",
+            {
+                "go.mod": "module {{PROJECT_NAME}}\n",
+                "README": "base",
+                "_partials/add.just": "add:\n    go get {{packages}}\n",
+            },
+            '[tasks]\ntest = { run = "go test ./..." }\n'
+            'lint = { run = "go vet ./..." }\n'
+            '[requires]\ngo = "1.21"\njust = ""\n'
+            '[verify]\nrun = ["go vet ./...", "go test ./..."]\n',
+        )
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _child(self, manifest, files=None, name="go/app"):
+        _write_template(self.root, name, files or {}, manifest)
+        return scaffold.find_layered(name, [self.root])[0]
+
+    def test_child_renders_over_its_parent(self):
+        template = self._child(
+            'extends = "go/_base"\n',
+            {"README": "own", "justfile": '{{ partial "add.just" }}\n'},
+        )
+        files = dict(
+            scaffold.render_template(
+                template, scaffold.project_variables("demo")
+            )
+        )
+        self.assertEqual(files["go.mod"], "module demo\n")
+        self.assertEqual(files["README"], "own")
+        self.assertEqual(files["justfile"], "add:\n    go get {{packages}}\n")
+        self.assertNotIn("_partials/add.just", files)
+        self.assertEqual(template.parent.name, "go/_base")
+
+    def test_manifest_tables_merge_and_the_rest_is_replaced(self):
+        template = self._child(
+            'extends = "go/_base"\n'
+            '[tasks]\ntest = { run = "go test -race ./..." }\n'
+            '[requires]\ngo = "1.22"\n'
+            '[verify]\nrun = ["go test -race ./..."]\n'
+        )
+        manifest = template.manifest
+        self.assertEqual(manifest["description"], "test")
+        tasks = manifest["tasks"]
+        self.assertEqual(tasks["test"]["run"], "go test -race ./...")
+        self.assertEqual(tasks["lint"]["run"], "go vet ./...")
+        self.assertEqual(manifest["requires"], {"go": "1.22", "just": ""})
+        self.assertEqual(manifest["verify"]["run"], ["go test -race ./..."])
+
+    def test_abstract_templates_are_not_listed(self):
+        self._child('extends = "go/_base"\n')
+        names = [t.name for t in scaffold.list_templates(self.root)]
+        self.assertEqual(names, ["go/app"])
+
+    def test_unknown_parent_and_loops(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._child('extends = "go/nope"\n')
+        self.assertIn("extends unknown template go/nope", cm.exception.code)
+        _write_template(self.root, "a", {}, 'extends = "b"\n')
+        _write_template(self.root, "b", {}, 'extends = "a"\n')
+        with self.assertRaises(SystemExit) as cm:
+            scaffold.find_layered("a", [self.root])
+        self.assertIn("extends loops: a -> b -> a", cm.exception.code)
+
+    def test_unknown_partial(self):
+        template = self._child(
+            'extends = "go/_base"\n',
+            {"justfile": '{{ partial "nope.just" }}\n'},
+        )
+        with self.assertRaises(SystemExit) as cm:
+            scaffold.render_template(
+                template, scaffold.project_variables("demo")
+            )
+        self.assertIn("no partial nope.just", cm.exception.code)
+
+    def test_builtin_go_templates_extend_the_base(self):
+        for template in scaffold.list_templates():
+            if template.name.split("/")[0] == "go":
+                with self.subTest(template=template.name):
+                    base = template
+                    while base.parent is not None:
+                        base = base.parent
+                    self.assertEqual(base.name, "go/_base")
+                    self.assertTrue(golang.is_go(template))
+
+    def test_go_apis_answer_panics_in_their_envelope(self):
+        api = scaffold.find_template("go/api")
+        self.assertEqual(
+            [api.parent.name, api.parent.parent.name], ["go/_api", "go/_http"]
+        )
+        sources = {rel: p for p, rel in scaffold._template_sources(api)}
+        recover = sources["internal/middleware/recover.go"]
+        self.assertTrue(recover.is_relative_to(api.parent.path))
+        self.assertIn("internal/middleware/requestid.go", sources)
+        self.assertIn("internal/logging/logging.go", sources)
+
+
+class TestVersionPins(unittest.TestCase):
+    def test_satisfies(self):
+        cases = [
+            ("1.2.3", "1.2.3", True),
+            ("1.2.3", "v1.2.3", True),
+            ("1.2.4", "1.2", True),
+            ("1.3.0", "1.2", False),
+            ("1.9.0", "1", True),
+            ("1.9.0", "^1.2", True),
+            ("1.1.0", "^1.2", False),
+            ("2.0.0", "^1.2", False),
+            ("0.2.5", "^0.2", True),
+            ("0.3.0", "^0.2", False),
+            ("1.2.9", "~1.2", True),
+            ("1.3.0", "~1.2", False),
+            ("1.2.0", "~1.2.1", False),
+            ("1.2.0-rc1", "1", False),
+            ("", "1", False),
+        ]
+        for version, pin, expected in cases:
+            with self.subTest(version=version, pin=pin):
+                self.assertIs(semver.satisfies(version, pin), expected)
+
+    def test_best_picks_highest_match(self):
+        tags = ["v1.0.0", "v1.10.0", "v1.9.3", "v2.0.0", "latest", "1.2"]
+        self.assertEqual(semver.best(tags, "^1"), "v1.10.0")
+        self.assertEqual(semver.best(tags, "1.9"), "v1.9.3")
+        self.assertIsNone(semver.best(tags, "3"))
+
+    def test_is_pin(self):
+        self.assertTrue(semver.is_pin("^1.2"))
+        self.assertTrue(semver.is_pin("v2"))
+        self.assertFalse(semver.is_pin("main"))
+        self.assertFalse(semver.is_pin("deadbeef"))
+
+    def test_local_template_pin(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            _write_template(Path(tmp), "svc", {}, 'version = "1.4.0"\n')
+            roots = [Path(tmp)]
+            template, _ = scaffold.find_pinned("svc@^1.2", roots)
+            self.assertEqual(template.version, "1.4.0")
+            self.assertEqual(template.name, "svc")
+            with self.assertRaises(SystemExit) as cm:
+                scaffold.find_pinned("svc@2", roots)
+            self.assertIn("svc is 1.4.0, which is not 2", str(cm.exception))
+
+    def test_invalid_manifest_version_exits(self):
+        template = scaffold.Template("t", Path("."), {"version": "1.x"})
+        with self.assertRaises(SystemExit):
+            template.version
+
+    def test_builtins_are_versioned(self):
+        for template in scaffold.list_templates():
+            self.assertTrue(semver.parse(template.version), template.name)
+
+    def test_lock_records_version(self):
+        template = scaffold.Template("t", Path("."), {"version": "1.4.0"})
+        lock = scaffold.lock_data(template, [], "app", {}, None)
+        self.assertEqual(lock["version"], "1.4.0")
+        remote_template = scaffold.Template("example.com/t@v2.1.0", Path("."))
+        lock = scaffold.lock_data(remote_template, [], "app", {}, None)
+        self.assertEqual(lock["version"], "2.1.0")
+
+
+class TestAnswersFile(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        root = Path(self.tmpdir) / "templates"
+        _write_template(
+            root,
+            "t",
+            {"out.txt": "{{PROJECT_NAME}} {{license}} {{port}}"},
+            VARIABLES_MANIFEST,
+        )
+        self.layer = ["--templates-dir", str(root)]
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, argv, answers=()):
+        args = jolo.parse_args(["new", *argv, *self.layer])
+        answers = iter(answers)
+        with (
+            mock.patch("sys.stdin.isatty", return_value=False),
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("sys.stderr"),
+            mock.patch("builtins.print"),
+        ):
+            scaffold.run_new_mode(args)
+
+    def test_record_then_replay(self):
+        self._new(
+            ["t", "one", "--record", "answers.toml"],
+            ["2", "8080", "y", "Ada"],
+        )
+        with open("answers.toml", "rb") as f:
+            recorded = tomllib.load(f)
+        self.assertEqual(recorded["template"], "t")
+        self.assertEqual(recorded["name"], "one")
+        self.assertEqual(recorded["variables"]["license"], "Apache-2.0")
+        self.assertNotIn("UUID", recorded["variables"])
+
+        recorded["name"] = "two"
+        scaffold.write_toml(Path("answers.toml"), recorded, "edited")
+        with mock.patch("builtins.input") as fake_input:
+            self._new(["--answers", "answers.toml"])
+        fake_input.assert_not_called()
+        self.assertEqual(
+            Path("two/out.txt").read_text(), "two Apache-2.0 8080"
+        )
+
+    def test_flags_beat_answers(self):
+        scaffold.write_toml(
+            Path("answers.toml"),
+            {
+                "template": "t",
+                "name": "one",
+                "variables": {"port": "1", "author": "a", "docker": "no"},
+            },
+            "test",
+        )
+        self._new(
+            ["t", "three", "--answers", "answers.toml", "--var", "port=2"],
+            ["1"],
+        )
+        self.assertEqual(Path("three/out.txt").read_text(), "three MIT 2")
+
+    def test_missing_answers_file_exits(self):
+        with self.assertRaises(SystemExit):
+            self._new(["--answers", "nope.toml"])
+
+
+class TestRunNewMode(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def test_writes_template_into_named_dir(self):
+        args = jolo.parse_args(["new", "go", "demo", "--git=false"])
+        scaffold.run_new_mode(args)
+        go_mod = Path(self.tmpdir) / "demo" / "go.mod"
+        self.assertTrue(go_mod.exists())
+        self.assertIn("module demo", go_mod.read_text())
+        self.assertFalse((Path(self.tmpdir) / "demo" / ".git").exists())
+
+    def test_output_tar_to_stdout(self):
+        stdout = mock.MagicMock()
+        stdout.buffer = io.BytesIO()
+        stdout.isatty.return_value = False
+        args = jolo.parse_args(["new", "go", "demo", "--output", "-"])
+        with (
+            mock.patch("sys.stdout", stdout),
+            mock.patch("sys.stderr", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+        stdout.buffer.seek(0)
+        with tarfile.open(fileobj=stdout.buffer) as tf:
+            names = tf.getnames()
+            go_mod = tf.extractfile("demo/go.mod").read().decode()
+        self.assertIn("demo/jolo.lock", names)
+        self.assertIn("module demo", go_mod)
+        self.assertFalse((Path(self.tmpdir) / "demo").exists())
+
+    def test_output_ignores_an_existing_directory(self):
+        (Path(self.tmpdir) / "demo").mkdir()
+        args = jolo.parse_args(["new", "go", "demo", "-o", "demo.tar.gz"])
+        with mock.patch("sys.stderr", io.StringIO()):
+            scaffold.run_new_mode(args)
+        self.assertTrue(tarfile.is_tarfile("demo.tar.gz"))
+        self.assertEqual(os.listdir(Path(self.tmpdir) / "demo"), [])
+
+    def test_with_overlays_addons(self):
+        args = jolo.parse_args(
+            ["new", "go/web", "demo", "--with", "docker,sqlite"]
+        )
+        with mock.patch("builtins.print"):
+            scaffold.run_new_mode(args)
+        demo = Path(self.tmpdir) / "demo"
+        self.assertTrue((demo / "Dockerfile").exists())
+        main = (demo / "main.go").read_text()
+        self.assertIn('ready["db"] = db.PingContext', main)
+        self.assertIn("modernc.org/sqlite", (demo / "go.mod").read_text())
+
+    def test_with_unsupported_base_exits(self):
+        args = jolo.parse_args(["new", "go", "demo", "--with", "sqlite"])
+        with self.assertRaises(SystemExit):
+            scaffold.run_new_mode(args)
+        self.assertFalse((Path(self.tmpdir) / "demo").exists())
+
+    def test_existing_dir_exits(self):
+        (Path(self.tmpdir) / "demo").mkdir()
+        args = jolo.parse_args(["new", "go", "demo"])
+        with self.assertRaises(SystemExit):
+            scaffold.run_new_mode(args)
+
+    def _existing(self):
+        demo = Path(self.tmpdir) / "demo"
+        demo.mkdir()
+        (demo / "main.go").write_text("old\n")
+        (demo / "notes.txt").write_text("mine\n")
+        return demo
+
+    def _new_into_existing(self, policy, answers=()):
+        args = jolo.parse_args(
+            ["new", "go", "demo", f"--on-conflict={policy}", "--no-install"]
+        )
+        answers = iter(answers)
+        with (
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("builtins.print") as fake_print,
+        ):
+            scaffold.run_new_mode(args)
+        return [c.args[0] for c in fake_print.call_args_list if c.args]
+
+    def test_on_conflict_skip(self):
+        demo = self._existing()
+        out = self._new_into_existing("skip")
+        self.assertEqual((demo / "main.go").read_text(), "old\n")
+        self.assertTrue((demo / "go.mod").exists())
+        self.assertEqual((demo / "notes.txt").read_text(), "mine\n")
+        self.assertIn("Skipped: main.go", out)
+
+    def test_on_conflict_overwrite(self):
+        demo = self._existing()
+        out = self._new_into_existing("overwrite")
+        self.assertIn("package main", (demo / "main.go").read_text())
+        self.assertIn("Overwrote: main.go", out)
+
+    def test_on_conflict_backup(self):
+        demo = self._existing()
+        (demo / "main.go.orig").write_text("older\n")
+        out = self._new_into_existing("backup")
+        self.assertIn("package main", (demo / "main.go").read_text())
+        self.assertEqual((demo / "main.go.orig").read_text(), "older\n")
+        self.assertEqual((demo / "main.go.orig.1").read_text(), "old\n")
+        self.assertIn("Backed up (.orig): main.go", out)
+
+    def test_on_conflict_prompt(self):
+        demo = self._existing()
+        (demo / "go.mod").write_text("module x\n")
+        self._new_into_existing("prompt", ["x", "s", "b"])
+        self.assertEqual((demo / "go.mod").read_text(), "module x\n")
+        self.assertEqual((demo / "main.go.orig").read_text(), "old\n")
+
+    def test_on_conflict_prompt_needs_input(self):
+        self._existing()
+        args = jolo.parse_args(
+            ["new", "go", "demo", "--on-conflict=prompt", "--no-input"]
+        )
+        with self.assertRaises(SystemExit):
+            scaffold.run_new_mode(args)
+
+    def test_unchanged_files_are_not_conflicts(self):
+        demo = Path(self.tmpdir) / "demo"
+        with mock.patch("builtins.print"):
+            scaffold.run_new_mode(
+                jolo.parse_args(["new", "go", "demo", "--no-install"])
+            )
+        out = self._new_into_existing("prompt")
+        self.assertEqual(out, [f"Created go project: {demo}"])
+
+    def test_no_input_requires_template(self):
+        args = jolo.parse_args(["new", "--no-input"])
+        with self.assertRaises(SystemExit):
+            scaffold.run_new_mode(args)
+
+    def test_no_input_requires_name(self):
+        args = jolo.parse_args(["new", "go", "--no-input"])
+        with mock.patch("builtins.input") as fake_input:
+            with self.assertRaises(SystemExit):
+                scaffold.run_new_mode(args)
+        fake_input.assert_not_called()
+
+    def test_plain_prompts_without_tty(self):
+        """Non-TTY stdin falls back to numbered line prompts."""
+        args = jolo.parse_args(["new"])
+        names = [t.name for t in scaffold.list_templates()]
+        answers = iter([str(names.index("go") + 1), "demo"])
+        with (
+            mock.patch("sys.stdin.isatty", return_value=False),
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("sys.stderr"),
+        ):
+            scaffold.run_new_mode(args)
+        self.assertTrue((Path(self.tmpdir) / "demo" / "go.mod").exists())
+
+    def test_prompt_eof_aborts(self):
+        args = jolo.parse_args(["new", "go"])
+        with (
+            mock.patch("builtins.input", side_effect=EOFError),
+            mock.patch("sys.stderr"),
+        ):
+            with self.assertRaises(SystemExit):
+                scaffold.run_new_mode(args)
+
+    def test_fzf_used_on_tty(self):
+        args = jolo.parse_args(["new", "--no-input"])
+        args.no_input = False
+        args.name = "demo"
+        args.license = "MIT"
+        args.var = ["DESCRIPTION=demo", "MODULE_PATH=demo"]
+        with (
+            mock.patch("sys.stdin.isatty", return_value=True),
+            mock.patch.object(scaffold.shutil, "which", return_value="fzf"),
+            mock.patch.object(
+                scaffold,
+                "_fzf_pick",
+                return_value="go      Go module",
+            ) as pick,
+        ):
+            scaffold.run_new_mode(args)
+        pick.assert_called_once()
+        self.assertTrue((Path(self.tmpdir) / "demo" / "go.mod").exists())
+
+
+class TestGitInit(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        identity = {
+            f"GIT_{who}_{what}": value
+            for who in ("AUTHOR", "COMMITTER")
+            for what, value in (("NAME", "t"), ("EMAIL", "t@t"))
+        }
+        env = mock.patch.dict(os.environ, identity)
+        env.start()
+        self.addCleanup(env.stop)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv, name="demo", config=None):
+        args = jolo.parse_args(["new", "go", name, "--no-verify", *argv])
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": config or {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+        return Path(self.tmpdir) / name
+
+    @staticmethod
+    def _git(cwd, *args):
+        return subprocess.run(
+            ["git", *args], cwd=cwd, capture_output=True, text=True
+        ).stdout.strip()
+
+    def test_commits_the_scaffold(self):
+        demo = self._new()
+        self.assertEqual(
+            self._git(demo, "log", "--format=%s"), "scaffold go with jolo"
+        )
+        self.assertEqual(self._git(demo, "status", "--porcelain"), "")
+        self.assertIn("jolo.lock", self._git(demo, "ls-files"))
+
+    def test_branch_from_config_and_remote(self):
+        demo = self._new(
+            "--remote",
+            "git@example.com:me/demo.git",
+            config={"default_branch": "trunk"},
+        )
+        self.assertEqual(self._git(demo, "branch", "--show-current"), "trunk")
+        self.assertEqual(
+            self._git(demo, "remote", "get-url", "origin"),
+            "git@example.com:me/demo.git",
+        )
+
+    def test_git_false_and_config_skip(self):
+        off = {"git": False}
+        self.assertFalse((self._new("--git=false") / ".git").exists())
+        self.assertFalse((self._new(name="b", config=off) / ".git").exists())
+        demo = self._new("--git", name="c", config=off)
+        self.assertTrue((demo / ".git").is_dir())
+
+    def test_git_hooks_run_after_the_commit(self):
+        real = scaffold.template_hooks
+
+        def hooks(templates, stage, variables):
+            if stage == "git":
+                return ["git log --format=%s > hooked", "exit 3"]
+            return real(templates, stage, variables)
+
+        with (
+            mock.patch.object(scaffold, "template_hooks", hooks),
+            mock.patch("sys.stderr", io.StringIO()) as err,
+        ):
+            demo = self._new()
+            skipped = self._new("--no-hooks", name="b")
+            bare = self._new("--git=false", name="c")
+        hooked = demo / "hooked"
+        self.assertEqual(hooked.read_text(), "scaffold go with jolo\n")
+        self.assertIn("hook failed (exit 3): exit 3", err.getvalue())
+        self.assertFalse((skipped / "hooked").exists())
+        self.assertFalse((bare / "hooked").exists())
+
+    def test_no_nested_repository(self):
+        subprocess.run(["git", "init", "-q"], check=True)
+        demo = self._new()
+        self.assertFalse((demo / ".git").exists())
+
+    def test_failed_commit_is_a_warning(self):
+        real = subprocess.run
+
+        def no_commit(cmd, *a, **kw):
+            if cmd[:2] == ["git", "commit"]:
+                return subprocess.CompletedProcess(cmd, 1, "", "no identity")
+            return real(cmd, *a, **kw)
+
+        with (
+            mock.patch("subprocess.run", no_commit),
+            mock.patch("sys.stderr", io.StringIO()) as err,
+        ):
+            demo = self._new()
+        self.assertIn("git commit -q -m", err.getvalue())
+        self.assertTrue((demo / ".git").is_dir())
+        self.assertTrue((demo / "go.mod").is_file())
+
+
+class TestRemoteSpec(unittest.TestCase):
+    def test_builtin_names_are_not_remote(self):
+        for spec in ("go", "go/web", "python/web"):
+            self.assertFalse(remote.is_remote(spec), spec)
+
+    def test_urls_and_shorthands_are_remote(self):
+        for spec in (
+            "github.com/me/tpl",
+            "https://example.org/tpl.git",
+            "git@github.com:me/tpl",
+            "file:///tmp/tpl",
+        ):
+            self.assertTrue(remote.is_remote(spec), spec)
+
+    def test_shorthand_expands_to_https(self):
+        spec = remote.parse_spec("github.com/me/tpl")
+        self.assertEqual(spec.url, "https://github.com/me/tpl.git")
+        self.assertIsNone(spec.ref)
+        self.assertEqual(spec.subdir, "")
+
+    def test_subdir_and_ref(self):
+        spec = remote.parse_spec("github.com/me/tpl//go/api@v1.2")
+        self.assertEqual(spec.url, "https://github.com/me/tpl.git")
+        self.assertEqual(spec.subdir, "go/api")
+        self.assertEqual(spec.ref, "v1.2")
+
+    def test_ssh_url_user_is_not_a_ref(self):
+        spec = remote.parse_spec("git@github.com:me/tpl.git@main")
+        self.assertEqual(spec.url, "git@github.com:me/tpl.git")
+        self.assertEqual(spec.ref, "main")
+
+    def test_file_url_with_subdir(self):
+        spec = remote.parse_spec("file:///srv/tpl//web")
+        self.assertEqual(spec.url, "file:///srv/tpl")
+        self.assertEqual(spec.subdir, "web")
+
+
+class TestRemoteAuth(unittest.TestCase):
+    def env(self, **values):
+        clean = {
+            k: v
+            for k, v in os.environ.items()
+            if not k.startswith(("JOLO_GIT_", "GIT_CONFIG_"))
+            and k not in remote.HOST_TOKENS.values()
+        }
+        return mock.patch.dict(os.environ, {**clean, **values}, clear=True)
+
+    def header(self, env):
+        n = int(env["GIT_CONFIG_COUNT"]) - 1
+        return env[f"GIT_CONFIG_KEY_{n}"], env[f"GIT_CONFIG_VALUE_{n}"]
+
+    def test_token_is_sent_as_a_host_scoped_header(self):
+        with self.env(JOLO_GIT_TOKEN="s3cret"):
+            env = remote.git_env("https://github.com/me/tpl.git")
+        key, value = self.header(env)
+        self.assertEqual(key, "http.https://github.com/.extraHeader")
+        basic = base64.b64encode(b"x-access-token:s3cret").decode()
+        self.assertEqual(value, f"Authorization: Basic {basic}")
+        self.assertEqual(env["GIT_TERMINAL_PROMPT"], "0")
+
+    def test_host_token_wins_and_existing_config_is_kept(self):
+        with self.env(
+            JOLO_GIT_TOKEN="general",
+            JOLO_GIT_TOKEN_GITLAB_EXAMPLE_ORG="specific",
+            GIT_CONFIG_COUNT="1",
+        ):
+            env = remote.git_env("https://gitlab.example.org/g/tpl.git")
+        self.assertEqual(env["GIT_CONFIG_COUNT"], "2")
+        key, value = self.header(env)
+        basic = base64.b64encode(b"oauth2:specific").decode()
+        self.assertEqual(value, f"Authorization: Basic {basic}")
+
+    def test_forge_tokens_apply_to_their_own_host(self):
+        with self.env(GITLAB_TOKEN="gl"):
+            self.assertIn(
+                "GIT_CONFIG_COUNT",
+                remote.git_env("https://gitlab.com/g/tpl.git"),
+            )
+            self.assertNotIn(
+                "GIT_CONFIG_COUNT",
+                remote.git_env("https://github.com/me/tpl.git"),
+            )
+
+    def test_no_token_for_ssh_or_file_urls(self):
+        with self.env(JOLO_GIT_TOKEN="s3cret"):
+            for url in ("git@github.com:me/tpl.git", "file:///srv/tpl"):
+                self.assertNotIn("GIT_CONFIG_COUNT", remote.git_env(url))
+
+    def test_token_stays_out_of_errors(self):
+        result = subprocess.CompletedProcess(
+            [], 128, "", "fatal: Authentication failed for 'https://…'"
+        )
+        with (
+            self.env(JOLO_GIT_TOKEN="s3cret"),
+            mock.patch.object(subprocess, "run", return_value=result) as run,
+            self.assertRaises(SystemExit) as cm,
+        ):
+            remote.remote_refs("https://github.com/me/private.git")
+        self.assertNotIn("s3cret", " ".join(run.call_args.args[0]))
+        self.assertNotIn("s3cret", cm.exception.code)
+        self.assertIn("JOLO_GIT_TOKEN", cm.exception.code)
+        self.assertIn("ssh-agent", cm.exception.code)
+
+    def test_ssh_protocol_expands_shorthands(self):
+        with self.env(JOLO_GIT_PROTOCOL="ssh"):
+            spec = remote.parse_spec("github.com/me/tpl@v1")
+        self.assertEqual(spec.url, "git@github.com:me/tpl.git")
+        self.assertEqual(spec.ref, "v1")
+
+
+class TestRemoteNew(unittest.TestCase):
+    """jolo new against a local repository reached over file://."""
+
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        repo = Path(self.tmpdir) / "repo"
+        (repo / "api").mkdir(parents=True)
+        (repo / "api" / "README.md").write_text("# {{PROJECT_NAME}} v1\n")
+        self._git(repo, "init", "-q")
+        self._git(repo, "add", "-A")
+        self._git(repo, "commit", "-q", "-m", "v1")
+        self._git(repo, "tag", "v1")
+        (repo / "api" / "README.md").write_text("# {{PROJECT_NAME}} v2\n")
+        self._git(repo, "commit", "-q", "-am", "v2")
+        self.url = repo.as_uri()
+        work = Path(self.tmpdir) / "work"
+        work.mkdir()
+        os.chdir(work)
+        self.cache = mock.patch.dict(
+            os.environ, {"XDG_CACHE_HOME": str(Path(self.tmpdir) / "cache")}
+        )
+        self.cache.start()
+
+    def tearDown(self):
+        self.cache.stop()
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    @staticmethod
+    def _git(cwd, *args):
+        subprocess.run(
+            ["git", "-c", "user.name=t", "-c", "user.email=t@t", *args],
+            cwd=cwd,
+            check=True,
+        )
+
+    def test_fetches_head_by_default(self):
+        scaffold.run_new_mode(
+            jolo.parse_args(["new", f"{self.url}//api", "demo", "--git=false"])
+        )
+        readme = Path("demo/README.md").read_text()
+        self.assertEqual(readme, "# demo v2\n")
+        self.assertFalse(Path("demo/.git").exists())
+
+    def test_fetches_tag(self):
+        scaffold.run_new_mode(
+            jolo.parse_args(["new", f"{self.url}//api@v1", "demo"])
+        )
+        self.assertEqual(Path("demo/README.md").read_text(), "# demo v1\n")
+
+    def test_version_pin_resolves_to_highest_tag(self):
+        repo = Path(self.url.removeprefix("file://"))
+        self._git(repo, "tag", "v1.0.0", "HEAD~1")
+        self._git(repo, "tag", "v1.1.0")
+        self._git(repo, "tag", "v2.0.0-rc1")
+        scaffold.run_new_mode(
+            jolo.parse_args(["new", f"{self.url}//api@^1", "demo"])
+        )
+        self.assertEqual(Path("demo/README.md").read_text(), "# demo v2\n")
+        lock = scaffold.read_lock(Path("demo"))
+        self.assertEqual(lock["template"], f"{self.url}//api@v1.1.0")
+        self.assertEqual(lock["version"], "1.1.0")
+
+        scaffold.run_new_mode(
+            jolo.parse_args(["new", f"{self.url}//api@~1.0", "old"])
+        )
+        self.assertEqual(Path("old/README.md").read_text(), "# old v1\n")
+
+        args = jolo.parse_args(["new", f"{self.url}//api@^2", "none"])
+        with self.assertRaises(SystemExit) as cm:
+            scaffold.run_new_mode(args)
+        self.assertIn("have: v1.0.0, v1.1.0", str(cm.exception))
+
+    def test_whole_repo_skips_git_dir(self):
+        args = jolo.parse_args(["new", self.url, "demo", "--git=false"])
+        scaffold.run_new_mode(args)
+        self.assertTrue(Path("demo/api/README.md").exists())
+        self.assertFalse(Path("demo/.git").exists())
+
+    def test_fetches_are_cached(self):
+        scaffold.run_new_mode(
+            jolo.parse_args(["new", f"{self.url}//api", "demo"])
+        )
+        entries = remote.cache_entries()
+        self.assertEqual(
+            [(e.url, e.ref) for e in entries], [(self.url, "")]
+        )
+        self.assertTrue(
+            str(entries[0].path).startswith(str(remote.cache_root()))
+        )
+
+    def test_offline_uses_cache_only(self):
+        with mock.patch.object(remote.constants, "OFFLINE", True):
+            args = jolo.parse_args(["new", f"{self.url}//api", "a"])
+            with self.assertRaises(SystemExit) as cm:
+                scaffold.run_new_mode(args)
+            self.assertIn("is not cached", str(cm.exception))
+        scaffold.run_new_mode(
+            jolo.parse_args(["new", f"{self.url}//api", "b"])
+        )
+        repo = Path(self.url.removeprefix("file://"))
+        (repo / "api" / "README.md").write_text("# moved on\n")
+        self._git(repo, "commit", "-q", "-am", "v3")
+        with mock.patch.object(remote.constants, "OFFLINE", True):
+            scaffold.run_new_mode(
+                jolo.parse_args(["new", f"{self.url}//api", "c"])
+            )
+        self.assertEqual(Path("c/README.md").read_text(), "# c v2\n")
+
+    def test_failed_fetch_falls_back_to_cache(self):
+        scaffold.run_new_mode(
+            jolo.parse_args(["new", f"{self.url}//api", "a"])
+        )
+        real = remote._git
+
+        def broken(args, cwd, url=None):
+            if args[0] == "fetch":
+                sys.exit("Error: git fetch failed:\nnetwork is down")
+            real(args, cwd, url)
+
+        with (
+            mock.patch.object(remote, "_git", broken),
+            mock.patch("sys.stderr", io.StringIO()) as err,
+        ):
+            scaffold.run_new_mode(
+                jolo.parse_args(["new", f"{self.url}//api", "b"])
+            )
+        self.assertIn("using the cached copy", err.getvalue())
+        self.assertEqual(Path("b/README.md").read_text(), "# b v2\n")
+
+    def test_release_tag_is_not_refetched(self):
+        repo = Path(self.url.removeprefix("file://"))
+        self._git(repo, "tag", "v1.0.0")
+        spec = f"{self.url}//api@v1.0.0"
+        scaffold.run_new_mode(jolo.parse_args(["new", spec, "a"]))
+        with mock.patch.object(remote, "refresh") as refresh:
+            scaffold.run_new_mode(jolo.parse_args(["new", spec, "b"]))
+        refresh.assert_not_called()
+
+    def test_missing_subdir_exits(self):
+        args = jolo.parse_args(["new", f"{self.url}//nope", "demo"])
+        with self.assertRaises(SystemExit):
+            scaffold.run_new_mode(args)
+
+
+if __name__ == "__main__":
+    unittest.main()
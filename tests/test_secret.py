@@ -0,0 +1,174 @@
+#!/usr/bin/env python3
+"""Tests for generated secrets and the .env they land in."""
+
+import base64
+import os
+import subprocess
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import placeholders, scaffold, secret, upgrade
+
+APP = "{version} {{{{SESSION_KEY}}}}\n\n-\n\none-off {{{{ secret 4 }}}}\n"
+MANIFEST = """\
+[secrets]
+SESSION_KEY = 16
+CSRF_SECRET = { bytes = 24, encoding = "base64url" }
+"""
+
+
+class TestToken(unittest.TestCase):
+    def test_encodings(self):
+        self.assertEqual(len(secret.token(16)), 32)
+        raw = base64.b64decode(secret.token(24, "base64"))
+        self.assertEqual(len(raw), 24)
+        self.assertNotIn("=", secret.token(16, "base64url"))
+        self.assertNotEqual(secret.token(16), secret.token(16))
+
+    def test_seeded_is_stable(self):
+        self.assertEqual(
+            secret.token(16, seed="s", key="a"),
+            secret.token(16, seed="s", key="a"),
+        )
+        self.assertNotEqual(
+            secret.token(16, seed="s", key="a"),
+            secret.token(16, seed="s", key="b"),
+        )
+
+    def test_helper(self):
+        text = placeholders.render('{{ secret 8 }} {{ secret 8 "x" }}', {})
+        value, left = text.split(" ", 1)
+        self.assertEqual(len(value), 16)
+        self.assertEqual(left, '{{ secret 8 "x" }}')
+        seeded = {"SECRET_SEED": "s"}
+        once = placeholders.render("{{ secret 8 | upper }}", seeded)
+        self.assertEqual(
+            once, placeholders.render("{{ secret 8 | upper }}", seeded)
+        )
+        self.assertEqual(once, once.upper())
+
+
+class TestManifest(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _template(self, manifest, files=None):
+        (self.root / "template.toml").write_text(manifest)
+        for rel, text in (files or {}).items():
+            (self.root / rel).write_text(text)
+        return scaffold.load_template(self.root, "svc")
+
+    def test_declared(self):
+        self.assertEqual(
+            secret.declared([self._template(MANIFEST)]),
+            {"SESSION_KEY": (16, "hex"), "CSRF_SECRET": (24, "base64url")},
+        )
+
+    def test_bad_declarations_exit(self):
+        for line, message in (
+            ("K = 0", "bytes must be a positive integer"),
+            ('K = { bytes = 8, encoding = "b32" }', "encoding must be one"),
+            ('K = "32"', "expected BYTES or { bytes, encoding }"),
+        ):
+            template = self._template(f"[secrets]\n{line}\n")
+            with self.assertRaises(SystemExit, msg=line) as cm:
+                secret.declared([template])
+            self.assertIn(message, cm.exception.code, msg=line)
+
+    def test_render_writes_env_and_ignores_it(self):
+        template = self._template(
+            MANIFEST,
+            {"config.txt": "key={{SESSION_KEY}}\n", ".env": "PORT=8080"},
+        )
+        variables = scaffold.project_variables("demo")
+        files = dict(scaffold.render_project(template, [], variables))
+        env = secret.parse_env(files[".env"])
+        self.assertEqual(set(env), {"PORT", "SESSION_KEY", "CSRF_SECRET"})
+        self.assertEqual(files["config.txt"], f"key={env['SESSION_KEY']}\n")
+        self.assertEqual(files[".gitignore"], ".env\n")
+
+    def test_kept_values_and_existing_ignores(self):
+        template = self._template(
+            MANIFEST, {".gitignore": "/.env*\n", ".env": "SESSION_KEY=old\n"}
+        )
+        variables = {**scaffold.project_variables("demo"), "CSRF_SECRET": "c"}
+        files = dict(scaffold.render_project(template, [], variables))
+        self.assertEqual(files[".env"], "SESSION_KEY=old\nCSRF_SECRET=c\n")
+        self.assertEqual(files[".gitignore"], "/.env*\n")
+
+    def test_negated_ignore(self):
+        self.assertTrue(secret._ignores_env(".env\n!.env.example\n"))
+        self.assertFalse(secret._ignores_env(".env*\n!.env\n"))
+
+
+class TestNewAndUpgrade(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        tmp = Path(self.tmpdir.name)
+        self.team = tmp / "team"
+        (self.team / "svc").mkdir(parents=True)
+        (self.team / "svc" / "template.toml").write_text(MANIFEST)
+        (self.team / "svc" / "app.txt").write_text(APP.format(version="v1"))
+        self._git("init", "-q")
+        self._git("add", "-A")
+        self._git("commit", "-q", "-m", "v1")
+        cwd = os.getcwd()
+        os.chdir(tmp)
+        self.addCleanup(os.chdir, cwd)
+        args = jolo.parse_args(
+            [
+                "new",
+                "svc",
+                "demo",
+                "--templates-dir",
+                str(self.team),
+                "--record",
+                "answers.toml",
+                "--git=false",
+            ]
+        )
+        with mock.patch("builtins.print"):
+            scaffold.run_new_mode(args)
+        os.chdir("demo")
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _git(self, *args):
+        subprocess.run(
+            ["git", "-c", "user.name=t", "-c", "user.email=t@t", *args],
+            cwd=self.team,
+            check=True,
+            capture_output=True,
+        )
+
+    def test_secrets_stay_out_of_the_lock(self):
+        key = secret.read_env(Path(".env"))["SESSION_KEY"]
+        self.assertNotIn(key, Path("jolo.lock").read_text())
+        self.assertNotIn(key, Path("../answers.toml").read_text())
+        self.assertTrue(Path("app.txt").read_text().startswith(f"v1 {key}\n"))
+
+    def test_upgrade_keeps_the_secrets(self):
+        env = Path(".env").read_text()
+        one_off = Path("app.txt").read_text().split()[-1]
+        (self.team / "svc" / "app.txt").write_text(APP.format(version="v2"))
+        self._git("commit", "-q", "-am", "v2")
+        with mock.patch("builtins.print"):
+            upgrade.run_upgrade_mode(jolo.parse_args(["upgrade"]))
+        key = secret.read_env(Path(".env"))["SESSION_KEY"]
+        self.assertEqual(Path(".env").read_text(), env)
+        self.assertEqual(
+            Path("app.txt").read_text(),
+            f"v2 {key}\n\n-\n\none-off {one_off}\n",
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,147 @@
+#!/usr/bin/env python3
+"""Tests for jolo serve."""
+
+import io
+import json
+import os
+import tarfile
+import tempfile
+import threading
+import unittest
+import urllib.error
+import urllib.request
+import zipfile
+from http.server import ThreadingHTTPServer
+from pathlib import Path
+
+import jolo
+from _jolo import scaffold, serve
+
+
+class TestServe(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.templates = Path(self.tmpdir.name)
+        path = self.templates / "svc"
+        path.mkdir()
+        (path / scaffold.MANIFEST_FILE).write_text(
+            'description = "A service"\n'
+            '[variables.port]\ntype = "int"\ndefault = 8000\n'
+            '[hooks]\npost = ["touch hooked"]\n'
+        )
+        (path / "app.txt").write_text("{{PROJECT_NAME}}:{{port}}\n")
+        (path / "run.sh").write_text("#!/bin/sh\n")
+        (path / "run.sh").chmod(0o755)
+
+        handler = serve.make_handler([str(self.templates)])
+        handler.log_message = lambda *args: None
+        self.server = ThreadingHTTPServer(("127.0.0.1", 0), handler)
+        self.url = "http://127.0.0.1:%d" % self.server.server_address[1]
+        thread = threading.Thread(target=self.server.serve_forever)
+        thread.start()
+        self.addCleanup(thread.join)
+        self.addCleanup(self.server.server_close)
+        self.addCleanup(self.server.shutdown)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _post(self, body):
+        data = body if isinstance(body, bytes) else json.dumps(body).encode()
+        request = urllib.request.Request(
+            self.url + "/generate", data=data, method="POST"
+        )
+        try:
+            with urllib.request.urlopen(request) as response:
+                return response.status, response.headers, response.read()
+        except urllib.error.HTTPError as e:
+            return e.code, e.headers, e.read()
+
+    def test_lists_templates(self):
+        with urllib.request.urlopen(self.url + "/templates") as response:
+            templates = json.load(response)
+        svc = next(t for t in templates if t["name"] == "svc")
+        self.assertEqual(svc["description"], "A service")
+        self.assertIn("go/web", [t["name"] for t in templates])
+
+    def test_generate_zip(self):
+        status, headers, body = self._post(
+            {"template": "svc", "name": "shop", "variables": {"port": 9000}}
+        )
+        self.assertEqual(status, 200, body)
+        self.assertEqual(headers["Content-Type"], "application/zip")
+        self.assertIn('filename="shop.zip"', headers["Content-Disposition"])
+        with zipfile.ZipFile(io.BytesIO(body)) as zf:
+            self.assertEqual(zf.read("shop/app.txt"), b"shop:9000\n")
+            self.assertIn("shop/jolo.lock", zf.namelist())
+            self.assertNotIn("shop/hooked", zf.namelist())
+            mode = zf.getinfo("shop/run.sh").external_attr >> 16
+            self.assertTrue(mode & 0o100)
+
+    def test_generate_tar_gz(self):
+        status, headers, body = self._post(
+            {"template": "svc", "name": "shop", "format": "tar.gz"}
+        )
+        self.assertEqual(status, 200, body)
+        with tarfile.open(fileobj=io.BytesIO(body)) as tf:
+            app = tf.extractfile("shop/app.txt").read()
+            self.assertTrue(tf.getmember("shop/run.sh").mode & 0o100)
+        self.assertEqual(app, b"shop:8000\n")
+
+    def test_bad_requests(self):
+        cases = [
+            (b"not json", 400, "invalid JSON"),
+            ({"name": "shop"}, 400, "template required"),
+            ({"template": "nope", "name": "shop"}, 404, "Unknown template"),
+            ({"template": "svc", "name": "../up"}, 400, "name"),
+            ({"template": "svc/../svc", "name": "shop"}, 400, "template"),
+            ({"template": "/etc", "name": "shop"}, 400, "template"),
+            (
+                {"template": "github.com/someone/templates", "name": "shop"},
+                400,
+                "git sources",
+            ),
+            (
+                {"template": "svc", "name": "shop", "format": "rar"},
+                400,
+                "format",
+            ),
+            (
+                {"template": "svc", "name": "s", "variables": {"port": "x"}},
+                400,
+                "port",
+            ),
+        ]
+        for body, status, error in cases:
+            with self.subTest(body=body):
+                got, _, data = self._post(body)
+                self.assertEqual(got, status)
+                self.assertIn(error, json.loads(data)["error"])
+
+    def test_addons_stay_in_the_addons_dir(self):
+        with tempfile.TemporaryDirectory() as outside:
+            (Path(outside) / scaffold.MANIFEST_FILE).write_text("")
+            (Path(outside) / "secret.txt").write_text("secret\n")
+            up = os.path.relpath(outside, scaffold.ADDONS_ROOT)
+            for addon in (outside, up):
+                with self.subTest(addon=addon):
+                    got, _, data = self._post(
+                        {"template": "svc", "name": "s", "addons": [addon]}
+                    )
+                    self.assertEqual(got, 400)
+                    self.assertIn("Unknown addon", json.loads(data)["error"])
+
+    def test_unknown_endpoint(self):
+        with self.assertRaises(urllib.error.HTTPError) as cm:
+            urllib.request.urlopen(self.url + "/nope")
+        self.assertEqual(cm.exception.code, 404)
+
+
+class TestServeArgs(unittest.TestCase):
+    def test_defaults(self):
+        args = jolo.parse_args(["serve"])
+        self.assertEqual((args.host, args.port), ("127.0.0.1", 8421))
+
+
+if __name__ == "__main__":
+    unittest.main()
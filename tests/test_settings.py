@@ -0,0 +1,183 @@
+#!/usr/bin/env python3
+"""Tests for jolo config (get, set, list, unset)."""
+
+import io
+import os
+import tempfile
+import tomllib
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import settings
+
+CONFIG = """\
+# my settings
+base_port = 4000
+
+[new]
+# who I am
+author = "Ada"  # shown in LICENSE
+templates = [
+    "go/web",
+]
+
+[profiles.work.new]
+author = "Ada at Work"
+"""
+
+
+class TestValues(unittest.TestCase):
+    def test_key_path(self):
+        self.assertEqual(settings.key_path("author"), ["new", "author"])
+        self.assertEqual(settings.key_path("base_port"), ["base_port"])
+        self.assertEqual(
+            settings.key_path("new.vars.x", "work"),
+            ["profiles", "work", "new", "vars", "x"],
+        )
+        with self.assertRaises(SystemExit):
+            settings.key_path("new..x")
+
+    def test_parse_value(self):
+        self.assertIs(settings.parse_value("true"), True)
+        self.assertEqual(settings.parse_value("4000"), 4000)
+        self.assertEqual(settings.parse_value('["a", "b"]'), ["a", "b"])
+        self.assertEqual(settings.parse_value("T. Bergheim"), "T. Bergheim")
+        self.assertEqual(settings.parse_value('"1.2"'), "1.2")
+
+    def test_format_value(self):
+        self.assertEqual(
+            settings.format_value({"a b": [1, True], "c": "d"}),
+            '{ "a b" = [1, true], c = "d" }',
+        )
+
+    def test_format_value_round_trips(self):
+        for value in ["Rocket 🚀", "a\x7fb", 'q " \\ ø', {"🚀 key": "x"}]:
+            with self.subTest(value=value):
+                text = settings.format_value(value)
+                self.assertEqual(settings.parse_value(text), value)
+
+
+class TestEdit(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.file = Path(self.tmpdir.name) / "config.toml"
+        self.file.write_text(CONFIG)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _write(self, key, value=None, profile=None, remove=False):
+        path = settings.key_path(key, profile)
+        return settings.write(self.file, path, value, remove)
+
+    def test_replace_keeps_comments(self):
+        self._write("author", "Grace")
+        text = self.file.read_text()
+        self.assertIn('author = "Grace"  # shown in LICENSE\n', text)
+        self.assertEqual(text.replace("Grace", "Ada"), CONFIG)
+
+    def test_add_to_a_table_and_the_top(self):
+        self._write("new.git", False)
+        self._write("notify_threshold", 30)
+        data = tomllib.loads(self.file.read_text())
+        self.assertIs(data["new"]["git"], False)
+        self.assertEqual(data["notify_threshold"], 30)
+        self.assertEqual(data["new"]["templates"], ["go/web"])
+        self.assertIn("# my settings\nbase_port", self.file.read_text())
+
+    def test_new_table_and_profile(self):
+        self._write("new.vars.go_version", "1.23")
+        self._write("license", "MIT", profile="work")
+        self._write("email", "a@b", profile="home")
+        data = tomllib.loads(self.file.read_text())
+        self.assertEqual(data["new"]["vars"], {"go_version": "1.23"})
+        self.assertEqual(
+            data["profiles"]["work"]["new"],
+            {"author": "Ada at Work", "license": "MIT"},
+        )
+        self.assertEqual(data["profiles"]["home"]["new"], {"email": "a@b"})
+
+    def test_unset(self):
+        self.assertTrue(self._write("author", profile="work", remove=True))
+        self.assertFalse(self._write("author", profile="work", remove=True))
+        self.assertFalse(self._write("new.nope", remove=True))
+        data = tomllib.loads(self.file.read_text())
+        self.assertEqual(data["profiles"]["work"]["new"], {})
+        self.assertEqual(data["new"]["author"], "Ada")
+
+    def test_multiline_value_is_left_for_hand_editing(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._write("new.templates", ["go"])
+        self.assertIn("edit it by hand", cm.exception.code)
+        self.assertEqual(self.file.read_text(), CONFIG)
+
+    def test_into_a_non_table(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._write("new.author.x", 1)
+        self.assertEqual(cm.exception.code, "Error: new.author is not a table")
+
+    def test_creates_the_file(self):
+        self.file.unlink()
+        self.file = Path(self.tmpdir.name) / "jolo" / "config.toml"
+        self._write("author", "Ada")
+        self.assertEqual(self.file.read_text(), '[new]\nauthor = "Ada"\n')
+
+
+class TestConfigCommand(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        self.home = mock.patch.dict(os.environ, {"HOME": self.tmpdir})
+        self.home.start()
+
+    def tearDown(self):
+        self.home.stop()
+        os.chdir(self.original_cwd)
+        import shutil
+
+        shutil.rmtree(self.tmpdir)
+
+    def _run(self, *argv):
+        args = jolo.parse_args(["config", *argv])
+        with mock.patch("sys.stdout", io.StringIO()) as stdout:
+            settings.run_config_mode(args)
+        return stdout.getvalue()
+
+    def test_set_then_get(self):
+        self._run("set", "--profile", "work", "author", "T. Bergheim")
+        self._run("set", "author", "Tor")
+        self.assertEqual(self._run("get", "author"), "Tor\n")
+        self.assertEqual(
+            self._run("get", "--profile", "work", "author"), "T. Bergheim\n"
+        )
+        self.assertEqual(self._run("get", "new"), 'new.author = "Tor"\n')
+        self.assertTrue(
+            (Path(self.tmpdir) / ".config/jolo/config.toml").is_file()
+        )
+
+    def test_project_file(self):
+        self._run("set", "--project", "new.eol", "crlf")
+        self.assertEqual(
+            Path(".jolo.toml").read_text(), '[new]\neol = "crlf"\n'
+        )
+        self.assertEqual(self._run("get", "new.eol"), "crlf\n")
+
+    def test_list_is_the_default(self):
+        self._run("set", "new.git", "false")
+        self.assertEqual(self._run(), self._run("list"))
+        self.assertIn("new.git = false\n", self._run())
+        self.assertIn("base_port = 4000\n", self._run())
+
+    def test_missing_keys(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._run("get", "new.nope")
+        self.assertEqual(cm.exception.code, "Error: new.nope is not set")
+        with self.assertRaises(SystemExit):
+            self._run("unset", "author")
+
+
+if __name__ == "__main__":
+    unittest.main()
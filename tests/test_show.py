@@ -0,0 +1,91 @@
+#!/usr/bin/env python3
+"""Tests for jolo show (_jolo.show)."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import completion, show
+
+
+class _Stdout(io.StringIO):
+    def __init__(self):
+        super().__init__()
+        self.buffer = io.BytesIO()
+
+
+class TestShow(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        tpl = Path(self.tmpdir, "templates", "t")
+        (tpl / "{{PROJECT_NAME}}").mkdir(parents=True)
+        (tpl / "template.toml").write_text(
+            'description = "A test"\nversion = "1.2.0"\n'
+            '[variables.PORT]\ntype = "int"\ndefault = 8080\n'
+            'description = "Port to serve on"\n'
+            "[variables.DB]\n"
+        )
+        (tpl / "{{PROJECT_NAME}}" / "main.txt").write_text(
+            "{{PROJECT_NAME}} on {{PORT}} with {{DB}}\n"
+        )
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _show(self, *argv):
+        args = jolo.parse_args(
+            ["show", *argv, "--templates-dir", "templates", "--var", "DB=pg"]
+        )
+        with (
+            mock.patch.object(show, "load_config", return_value={}),
+            mock.patch("sys.stdout", _Stdout()) as out,
+        ):
+            show.run_show_mode(args)
+        return out.getvalue() + out.buffer.getvalue().decode()
+
+    def test_tree_and_variables(self):
+        out = self._show("t")
+        lines = out.splitlines()
+        self.assertEqual(lines[0], "t 1.2.0: A test")
+        self.assertEqual(lines[2], "demo/")
+        self.assertIn("└── demo/", lines)
+        self.assertIn("main.txt", out)
+        self.assertIn("Variables:", lines)
+        self.assertIn("  PORT  int     8080  Port to serve on", lines)
+        self.assertIn("  DB    string", lines)
+        self.assertFalse(any(line != line.rstrip() for line in lines))
+
+    def test_one_file(self):
+        out = self._show("t", "shop/main.txt", "--var", "PROJECT_NAME=shop")
+        self.assertEqual(out, "shop on 8080 with pg\n")
+
+    def test_missing_file(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._show("t", "demo/mian.txt")
+        self.assertEqual(
+            cm.exception.code,
+            "Error: t renders no demo/mian.txt (did you mean demo/main.txt?)",
+        )
+
+    def test_builtin(self):
+        out = self._show("go/web")
+        self.assertIn("main.go", out)
+        self.assertIn("Addons: alpine, auth, cache, ci, devcontainer", out)
+
+    def test_completion(self):
+        self.assertIn("go/web", completion.complete(["show", ""]))
+        self.assertIn(
+            "docker", completion.complete(["show", "go/web", "--with", ""])
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,138 @@
+#!/usr/bin/env python3
+"""Tests for the checks jolo new runs on the project it made."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import events, install, scaffold, smoke
+
+
+def _template(run):
+    return scaffold.Template(
+        "t", Path(tempfile.gettempdir()), {"verify": {"run": run}}
+    )
+
+
+class TestRun(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = Path(tempfile.mkdtemp())
+
+    def tearDown(self):
+        shutil.rmtree(self.tmpdir)
+
+    def _run(self, *groups):
+        with mock.patch("sys.stderr", io.StringIO()) as err:
+            steps = smoke.run(list(groups), self.tmpdir)
+            ok = smoke.passed(steps)
+        return steps, ok, err.getvalue()
+
+    def test_commands_rendered_per_template(self):
+        templates = [_template(["test -d {{PROJECT_NAME}}"]), _template([])]
+        self.assertEqual(
+            smoke.commands(templates, {"PROJECT_NAME": "demo"}),
+            [["test -d demo"]],
+        )
+
+    def test_pass(self):
+        steps, ok, err = self._run(["true", "touch built"])
+        self.assertTrue(ok)
+        self.assertEqual([s.status for s in steps], ["ok", "ok"])
+        self.assertTrue((self.tmpdir / "built").exists())
+        self.assertIn("Verify: PASS", err)
+
+    def test_failure_is_logged_and_stops_the_rest(self):
+        steps, ok, err = self._run(
+            ["seq 30; echo broken >&2; exit 3", "touch later"]
+        )
+        self.assertFalse(ok)
+        self.assertEqual([s.status for s in steps], ["fail", "pending"])
+        self.assertFalse((self.tmpdir / "later").exists())
+        self.assertIn("exit 3", err)
+        self.assertIn("Verify: FAIL", err)
+        log = Path(err.split("full output: ")[1].split()[0])
+        self.addCleanup(log.unlink)
+        self.assertFalse(log.is_relative_to(self.tmpdir))
+        text = log.read_text()
+        self.assertTrue(text.startswith("$ seq 30;"))
+        self.assertIn("1\n2\n", text)
+        self.assertIn("broken", text)
+
+    def test_missing_program_skips(self):
+        steps, ok, err = self._run(["no-such-jolo-tool x"])
+        self.assertTrue(ok)
+        self.assertEqual(steps[0].status, "skip")
+        self.assertIn("Verify: skipped", err)
+
+
+class TestNewVerifies(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv, config=None, steps=(), installed="ok"):
+        args = jolo.parse_args(
+            ["new", "go", "demo", "--git=false", "--no-input", *argv]
+        )
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": config or {}}
+            ),
+            mock.patch.object(
+                install, "run", return_value=[install.Step("x", installed)]
+            ),
+            mock.patch.object(smoke, "run", return_value=list(steps)) as run,
+            mock.patch("sys.stdout", io.StringIO()),
+            mock.patch("sys.stderr", io.StringIO()) as err,
+        ):
+            scaffold.run_new_mode(args)
+        self.err = err.getvalue()
+        return run
+
+    def test_checks_run_in_the_project(self):
+        run = self._new(steps=[install.Step("go vet ./...", "ok")])
+        run.assert_called_once_with(
+            [["go vet ./...", "go test ./..."]],
+            Path(self.tmpdir).resolve() / "demo",
+        )
+
+    def test_failure_exits_6_and_keeps_the_project(self):
+        with self.assertRaises(events.Failure) as cm:
+            self._new(steps=[install.Step("go vet ./...", "fail")])
+        self.assertEqual(cm.exception.status, 6)
+        self.assertIn("--no-verify", cm.exception.code)
+        self.assertTrue(Path("demo/go.mod").is_file())
+
+    def test_skipped(self):
+        for argv, config in (
+            (["--no-verify"], None),
+            (["--no-install"], None),
+            ([], {"verify": False}),
+        ):
+            with self.subTest(argv=argv, config=config):
+                self._new(*argv, config=config).assert_not_called()
+                shutil.rmtree("demo")
+
+    def test_no_install_says_so(self):
+        self._new("--no-install")
+        self.assertIn("Skipping verify: the dependencies are not", self.err)
+        shutil.rmtree("demo")
+        self._new("--no-install", "--no-verify")
+        self.assertNotIn("Skipping verify", self.err)
+
+    def test_not_after_a_failed_install(self):
+        self._new(installed="fail").assert_not_called()
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,115 @@
+#!/usr/bin/env python3
+"""Golden-file tests for the builtin templates, and the snapshot helpers.
+
+When a template change is intended, regenerate the golden files with
+``JOLO_UPDATE_GOLDEN=1`` (or ``jolo snapshot tests/golden --update``)
+and commit the result.
+"""
+
+import io
+import os
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import scaffold, snapshot
+
+GOLDEN = Path(__file__).resolve().parent / "golden"
+
+
+class TestBuiltinGoldenFiles(unittest.TestCase):
+    def test_templates_match_golden_files(self):
+        update = os.environ.get("JOLO_UPDATE_GOLDEN") == "1"
+        for template in scaffold.list_templates():
+            with self.subTest(template=template.name):
+                path = snapshot.golden_dir(GOLDEN, template.name)
+                rendered = snapshot.render_snapshot(template, GOLDEN)
+                if update:
+                    snapshot.update_tree(path, rendered)
+                lines = snapshot.diff_tree(snapshot.read_tree(path), rendered)
+                self.assertEqual(
+                    "".join(lines),
+                    "",
+                    f"{template.name} output changed; rerun with "
+                    "JOLO_UPDATE_GOLDEN=1 if intended",
+                )
+
+
+class TestSnapshotHelpers(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+        self.templates = self.root / "templates"
+        path = self.templates / "svc"
+        path.mkdir(parents=True)
+        (path / scaffold.MANIFEST_FILE).write_text(
+            '[variables.port]\ntype = "int"\ndefault = 1\n'
+        )
+        (path / "a.txt").write_text(
+            '{{PROJECT_NAME}} {{port}} {{ date "%Y" }} {{uuid}}\n'
+        )
+        self.template = scaffold.load_template(path, "svc")
+        self.golden = self.root / "golden"
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_fixed_answers(self):
+        files = snapshot.render_snapshot(self.template, self.golden)
+        self.assertEqual(
+            files["a.txt"],
+            f"golden-app 1 2000 {snapshot.FIXED['UUID']}\n",
+        )
+
+    def test_answers_file_overrides_variables(self):
+        self.golden.mkdir()
+        (self.golden / "svc.answers.toml").write_text(
+            "[variables]\nport = 8080\n"
+        )
+        files = snapshot.render_snapshot(self.template, self.golden)
+        self.assertIn(" 8080 ", files["a.txt"])
+
+    def test_diff_tree(self):
+        lines = snapshot.diff_tree(
+            {"a": "1\n", "gone": "x\n"}, {"a": "2\n", "new": b"\0"}
+        )
+        text = "".join(lines)
+        self.assertIn("-1\n+2\n", text)
+        self.assertIn("+++ /dev/null", text)
+        self.assertIn("Binary files a/new and b/new differ", text)
+
+    def _run(self, *extra):
+        args = jolo.parse_args(
+            [
+                "snapshot",
+                str(self.golden),
+                "svc",
+                "--templates-dir",
+                str(self.templates),
+                *extra,
+            ]
+        )
+        out = io.StringIO()
+        with (
+            mock.patch.object(snapshot, "load_config", return_value={}),
+            mock.patch("sys.stdout", out),
+        ):
+            snapshot.run_snapshot_mode(args)
+        return out.getvalue()
+
+    def test_update_then_check(self):
+        with self.assertRaises(SystemExit):
+            self._run()
+        self.assertIn("Updated", self._run("--update"))
+        self.assertTrue((self.golden / "svc" / "a.txt").is_file())
+        self.assertIn("1 templates match", self._run())
+
+        (self.templates / "svc" / "a.txt").write_text("changed\n")
+        with self.assertRaises(SystemExit):
+            self._run()
+
+
+if __name__ == "__main__":
+    unittest.main()
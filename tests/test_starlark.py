@@ -0,0 +1,234 @@
+#!/usr/bin/env python3
+"""Tests for template scripts (_jolo.starlark)."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import scaffold, starlark
+
+SCRIPT = '''
+MAIN = """package main
+
+// {{SERVICE}} of {{SERVICE_COUNT}}
+"""
+
+def services(vars):
+    return [s.strip() for s in vars["SERVICES"].split(",") if s.strip()]
+
+def variables(vars):
+    n = len(services(vars))
+    return {"SERVICE_COUNT": n, "MANY": n > 1}
+
+def include(path, vars):
+    return not path.startswith("docs/") or vars["MANY"] == "true"
+
+def transform(path, content, vars):
+    return content.replace("TODO", "done") if path.endswith(".md") else content
+
+def files(vars):
+    out = {}
+    for name in services(vars):
+        main = render(MAIN, dict(vars, SERVICE=name))
+        out["services/%s/main.go" % name] = main
+    return out
+'''
+
+
+def _script(source):
+    return starlark.Script(source, "t.star")
+
+
+class TestDialect(unittest.TestCase):
+    def test_functions_and_builtins(self):
+        script = _script(
+            "def f(xs):\n"
+            "    return sorted([x.upper() for x in xs], reverse=True)\n"
+        )
+        self.assertEqual(script.call("f", ["a", "b"]), ["B", "A"])
+        self.assertFalse(script.has("g"))
+
+    def test_rejected_statements(self):
+        for source, why in (
+            ("import os", "Import is not allowed"),
+            ("while True:\n    pass", "While is not allowed"),
+            ("x = f'{1}'", "JoinedStr is not allowed"),
+            ("x = open", "name 'open' is not defined"),
+            ("x = ''.__class__", "outside a method call"),
+            ("x = '{0.__class__}'.format(1)", "method format"),
+            ("x = {}.fromkeys([1])", "dict has no method fromkeys"),
+        ):
+            with self.assertRaises(ValueError, msg=source) as cm:
+                _script(source)
+            self.assertIn(why, str(cm.exception))
+
+    def test_errors_say_where(self):
+        with self.assertRaises(ValueError) as cm:
+            _script("x = 1\ny = x + 'a'\n")
+        self.assertTrue(str(cm.exception).startswith("t.star:2: TypeError"))
+
+    def test_fail(self):
+        script = _script("def f():\n    fail('no', 'services')\n")
+        with self.assertRaises(ValueError) as cm:
+            script.call("f")
+        self.assertEqual(str(cm.exception), "t.star:2: no services")
+
+    def test_render_has_no_env(self):
+        script = _script(
+            "def f():\n    return render('{{ env \"HOME\" }}', {})\n"
+        )
+        with self.assertRaises(ValueError) as cm:
+            script.call("f")
+        self.assertIn("env is not available", str(cm.exception))
+
+    def test_recursion_is_refused(self):
+        with self.assertRaises(ValueError) as cm:
+            _script("def f(n):\n    return f(n - 1)\nf(3)\n")
+        self.assertIn("f: recursion is not allowed", str(cm.exception))
+
+    def test_bounded(self):
+        for source in (
+            "for i in range(10000000):\n    pass",
+            "x = 'a' * 10000000",
+            "x = 'a' * 1000\nfor i in range(20):\n    x += x",
+            "x = 9 ** 100000",
+            "x = '%99999999s' % 'a'",
+            "x = 'a' * 2000\ny = x.replace('a', x)",
+        ):
+            with self.assertRaises(ValueError, msg=source):
+                _script(source)
+
+
+class TestTemplateScript(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        tpl = Path(self.tmpdir, "templates", "svc")
+        (tpl / "docs").mkdir(parents=True)
+        (tpl / "template.toml").write_text(
+            'description = "services"\nscript = "template.star"\n\n'
+            '[variables.SERVICES]\ndefault = "users"\n'
+        )
+        (tpl / "template.star").write_text(SCRIPT)
+        (tpl / "NOTES.md").write_text("TODO: {{SERVICE_COUNT}}\n")
+        (tpl / "docs" / "many.md").write_text("several\n")
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv):
+        args = jolo.parse_args(
+            [
+                "new",
+                "svc",
+                "demo",
+                "--templates-dir",
+                "templates",
+                "--no-input",
+                "--git=false",
+                "--license=none",
+                *argv,
+            ]
+        )
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+        demo = Path(self.tmpdir, "demo")
+        return sorted(
+            p.relative_to(demo).as_posix()
+            for p in demo.rglob("*")
+            if p.is_file()
+        )
+
+    def test_structure_follows_the_answers(self):
+        files = self._new("--var", "SERVICES=users, orders")
+        self.assertIn("services/orders/main.go", files)
+        self.assertIn("services/users/main.go", files)
+        self.assertIn("docs/many.md", files)
+        self.assertNotIn("template.star", files)
+        main = Path("demo/services/orders/main.go").read_text()
+        self.assertEqual(main, "package main\n\n// orders of 2\n")
+        self.assertEqual(Path("demo/NOTES.md").read_text(), "done: 2\n")
+
+    def test_include_leaves_files_out(self):
+        files = self._new()
+        self.assertNotIn("docs/many.md", files)
+        self.assertIn("services/users/main.go", files)
+
+    def test_bad_return_exits(self):
+        star = Path("templates/svc/template.star")
+        star.write_text(
+            SCRIPT + "\ndef transform(path, content, vars):\n    return 1\n"
+        )
+        with self.assertRaises(SystemExit) as cm:
+            self._new()
+        self.assertIn(
+            "transform() returned int, expected str", cm.exception.code
+        )
+        self.assertFalse(Path("demo").exists())
+
+    def test_computed_variables(self):
+        manifest = Path("templates/svc/template.toml")
+        manifest.write_text(
+            manifest.read_text()
+            + '\n[computed]\nPACKAGE = "snake(PROJECT_NAME) + \'_pkg\'"\n'
+            'YEAR = "now().year"\nFIRST = "SERVICES.split(\',\')[0]"\n'
+            'LABEL = "upper(PACKAGE)"\n'
+        )
+        Path("templates/svc/NOTES.md").write_text(
+            "{{PACKAGE}} {{YEAR}} {{FIRST}} {{LABEL}}\n"
+        )
+        self._new(
+            "--var", "SERVICES=orders,users", "--var", "TODAY=2001-02-03"
+        )
+        self.assertEqual(
+            Path("demo/NOTES.md").read_text(),
+            "demo_pkg 2001 orders DEMO_PKG\n",
+        )
+
+    def test_computed_errors(self):
+        template = scaffold.Template("t", Path(self.tmpdir))
+        for source, why in (
+            ("MISSING", "t: computed.X:1: name 'MISSING' is not defined"),
+            ("[1]", "t: computed.X is list; expected a string"),
+            ("1 +", "t: computed.X: invalid syntax"),
+            ("now().hour", "t: computed.X: no field hour"),
+        ):
+            template.manifest = {"computed": {"X": source}}
+            with self.assertRaises(SystemExit, msg=source) as cm:
+                scaffold.computed_variables([template], {})
+            self.assertIn(why, cm.exception.code)
+
+
+class TestExpression(unittest.TestCase):
+    def _eval(self, source, **variables):
+        return starlark.Expression(source, "e").evaluate(variables)
+
+    def test_filters_and_fields(self):
+        self.assertEqual(self._eval("pascal(N)", N="my-app"), "MyApp")
+        self.assertEqual(self._eval("now().month", TODAY="2001-02-03"), 2)
+        self.assertEqual(self._eval("[x for x in range(3)][-1] * 2"), 4)
+
+    def test_variables_shadow_builtins(self):
+        self.assertEqual(self._eval("len", len="x"), "x")
+
+    def test_no_fields_beyond_dates(self):
+        with self.assertRaisesRegex(ValueError, "string has no field day"):
+            self._eval("N.day", N="x")
+        with self.assertRaisesRegex(ValueError, "outside a method call"):
+            starlark.Script("x = now().year", "t.star")
+
+
+if __name__ == "__main__":
+    unittest.main()
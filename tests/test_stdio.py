@@ -0,0 +1,94 @@
+#!/usr/bin/env python3
+"""Tests for the jolo --stdio editor protocol."""
+
+import io
+import json
+import tempfile
+import unittest
+from pathlib import Path
+
+from _jolo import scaffold, stdio
+
+
+class TestStdioServer(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        root = Path(self.tmpdir.name)
+        path = root / "templates" / "svc"
+        path.mkdir(parents=True)
+        (path / scaffold.MANIFEST_FILE).write_text(
+            'description = "A service"\n'
+            '[variables.port]\ntype = "int"\ndefault = 8000\n'
+        )
+        (path / "app.txt").write_text("{{PROJECT_NAME}}:{{port}}\n")
+        self.cwd = root / "src"
+        self.cwd.mkdir()
+        self.server = stdio.Server(self.cwd, [str(root / "templates")])
+        self.ids = 0
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _request(self, method, **params):
+        self.ids += 1
+        response = self.server.handle(
+            {"jsonrpc": "2.0", "id": self.ids, "method": method,
+             "params": params}
+        )  # fmt: skip
+        self.assertEqual(response["id"], self.ids)
+        return response
+
+    def test_initialize(self):
+        result = self._request("initialize")["result"]
+        self.assertEqual(result["methods"], list(stdio.Server.METHODS))
+
+    def test_list_and_describe(self):
+        templates = self._request("templates/list", query="service")
+        names = [t["name"] for t in templates["result"]["templates"]]
+        self.assertEqual(names, ["svc"])
+        result = self._request("templates/describe", template="svc")
+        port = result["result"]["variables"]["port"]
+        self.assertEqual((port["type"], port["default"]), ("int", 8000))
+
+    def test_preview_writes_nothing(self):
+        result = self._request("project/preview", template="svc", path="shop")
+        self.assertIn("app.txt", result["result"]["files"])
+        self.assertFalse((self.cwd / "shop").exists())
+
+    def test_generate(self):
+        result = self._request(
+            "project/generate",
+            template="svc",
+            path="shop",
+            variables={"port": 9000},
+        )["result"]
+        self.assertEqual(result["path"], str(self.cwd / "shop"))
+        self.assertIn(scaffold.LOCK_FILE, result["files"])
+        self.assertEqual(
+            (self.cwd / "shop" / "app.txt").read_text(), "shop:9000\n"
+        )
+        again = self._request("project/generate", template="svc", path="shop")
+        self.assertEqual(again["error"]["code"], stdio.SCAFFOLD_ERROR)
+        self.assertIn("not empty", again["error"]["message"])
+
+    def test_errors(self):
+        self.assertEqual(self._request("nope")["error"]["code"], -32601)
+        bad = self._request("project/generate", template="svc")
+        self.assertEqual(bad["error"]["code"], -32602)
+        missing = self._request("templates/describe", template="nope")
+        self.assertIn("Unknown template", missing["error"]["message"])
+
+    def test_serve_until_eof(self):
+        stdin = io.StringIO(
+            '{"jsonrpc": "2.0", "id": 1, "method": "initialize"}\n'
+            '{"jsonrpc": "2.0", "method": "initialized"}\n'
+        )
+        stdout = io.StringIO()
+        self.server.serve(stdin, stdout)
+        lines = stdout.getvalue().splitlines()
+        self.assertEqual(len(lines), 1)
+        self.assertEqual(json.loads(lines[0])["result"]["name"], "jolo")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,139 @@
+#!/usr/bin/env python3
+"""Tests for streamed rendering of large template files."""
+
+import io
+import tarfile
+import tempfile
+import unittest
+import zipfile
+from pathlib import Path
+from unittest import mock
+
+from _jolo import placeholders, scaffold, streaming
+from _jolo.writers import DirWriter, TarWriter, ZipWriter
+
+VARIABLES = {"PROJECT_NAME": "my-app"}
+TEXT = "é {{PROJECT_NAME}} {{ PROJECT_NAME | pascal }} {{just}} {\n" * 50
+
+
+def _chunks(text, n):
+    return [text[i : i + n] for i in range(0, len(text), n)]
+
+
+class TestRenderChunks(unittest.TestCase):
+    def test_same_as_whole_text(self):
+        expected = placeholders.render(TEXT, VARIABLES)
+        with mock.patch.object(placeholders, "MAX_PLACEHOLDER", 40):
+            for n in (1, 2, 5, 13, 64, 10_000):
+                with self.subTest(chunk=n):
+                    rendered = placeholders.render_chunks(
+                        _chunks(TEXT, n), VARIABLES
+                    )
+                    self.assertEqual("".join(rendered), expected)
+
+    def test_placeholder_split_at_the_boundary(self):
+        with mock.patch.object(placeholders, "MAX_PLACEHOLDER", 20):
+            out = placeholders.render_chunks(
+                ["x" * 30 + "{", "{PROJECT", "_NAME}", "}" + "y" * 30],
+                VARIABLES,
+            )
+            self.assertEqual("".join(out), "x" * 30 + "my-app" + "y" * 30)
+
+
+class TestStreamed(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+        self.src = self.root / "big.txt"
+        self.src.write_text(TEXT)
+        self.chunk = mock.patch.object(streaming, "CHUNK", 7)
+        self.chunk.start()
+
+    def tearDown(self):
+        self.chunk.stop()
+        self.tmpdir.cleanup()
+
+    def test_text_and_size(self):
+        streamed = streaming.Streamed(self.src, VARIABLES)
+        expected = placeholders.render(TEXT, VARIABLES)
+        self.assertEqual(streamed.read(), expected)
+        self.assertEqual(streamed.size, len(expected.encode()))
+        self.assertEqual(streamed.open().read(), expected.encode())
+
+    def test_copied_as_is(self):
+        streamed = streaming.Streamed(self.src, None)
+        self.assertEqual(streamed.read(), self.src.read_bytes())
+
+    def test_same_as(self):
+        streamed = streaming.Streamed(self.src, VARIABLES)
+        out = self.root / "out.txt"
+        out.write_text(placeholders.render(TEXT, VARIABLES))
+        self.assertTrue(streamed.same_as(out))
+        out.write_text(placeholders.render(TEXT, VARIABLES) + "x")
+        self.assertFalse(streamed.same_as(out))
+
+    def test_writers(self):
+        streamed = streaming.Streamed(self.src, VARIABLES)
+        expected = placeholders.render(TEXT, VARIABLES).encode()
+        DirWriter(self.root / "dir").write("a/big.txt", streamed)
+        self.assertEqual((self.root / "dir/a/big.txt").read_bytes(), expected)
+        for writer, read in (
+            (ZipWriter, lambda b: zipfile.ZipFile(b).read("p/big.txt")),
+            (
+                TarWriter,
+                lambda b: tarfile.open(fileobj=b)
+                .extractfile("p/big.txt")
+                .read(),
+            ),
+        ):
+            buf = io.BytesIO()
+            with writer(buf, "p") as w:
+                w.write("big.txt", streamed)
+            buf.seek(0)
+            self.assertEqual(read(buf), expected, writer.__name__)
+
+
+class TestRenderTemplate(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        path = Path(self.tmpdir.name, "t")
+        path.mkdir()
+        (path / scaffold.MANIFEST_FILE).write_text('description = "t"\n')
+        (path / "big.txt").write_text(TEXT)
+        (path / "big.bin").write_bytes(b"\0" * 5000)
+        (path / "small.txt").write_text("{{PROJECT_NAME}}\n")
+        self.template = scaffold.load_template(path, "t")
+        self.size = mock.patch.object(streaming, "STREAM_SIZE", 1000)
+        self.size.start()
+
+    def tearDown(self):
+        self.size.stop()
+        self.tmpdir.cleanup()
+
+    def test_large_files_streamed(self):
+        files = dict(scaffold.render_template(self.template, VARIABLES, True))
+        self.assertIsInstance(files["big.txt"], streaming.Streamed)
+        self.assertIsInstance(files["big.bin"], streaming.Streamed)
+        self.assertEqual(files["big.bin"].read(), b"\0" * 5000)
+        self.assertEqual(files["small.txt"], "my-app\n")
+        self.assertEqual(
+            files["big.txt"].read(), placeholders.render(TEXT, VARIABLES)
+        )
+
+    def test_in_memory_unless_asked(self):
+        files = dict(scaffold.render_template(self.template, VARIABLES))
+        expected = placeholders.render(TEXT, VARIABLES)
+        self.assertEqual(files["big.txt"], expected)
+
+    def test_plan(self):
+        files = scaffold.render_template(self.template, VARIABLES, True)
+        target = Path(self.tmpdir.name, "out")
+        scaffold.write_files(files, target)
+        plan = scaffold.plan_files(files, target)
+        self.assertEqual({a for _, _, a in plan}, {"unchanged"})
+        size = dict((rel, n) for rel, n, _ in plan)["big.txt"]
+        self.assertEqual(size, (target / "big.txt").stat().st_size)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,141 @@
+#!/usr/bin/env python3
+"""Tests for the [tasks] written to a justfile, Makefile or Taskfile."""
+
+import tempfile
+import unittest
+from pathlib import Path
+
+from _jolo import monorepo, readme, scaffold, tasks
+
+VARIABLES = {"PROJECT_NAME": "shop"}
+TASKS = {
+    "dev": {"run": "air", "about": "Run with auto-reload"},
+    "bench": "go test -bench .",
+    "build": ["templ generate", "go build -o {{PROJECT_NAME}} ."],
+}
+
+
+def _template(name, table=None):
+    manifest = {"tasks": table} if table is not None else {}
+    return scaffold.Template(name, Path(tempfile.gettempdir()), manifest)
+
+
+def _apply(files, runner, table=TASKS):
+    variables = {**VARIABLES, "TASK_RUNNER": runner}
+    return dict(tasks.apply(files, [_template("t", table)], variables))
+
+
+class TestDeclared(unittest.TestCase):
+    def test_canonical_first_rendered_later_wins(self):
+        found = tasks.declared(
+            [_template("base", TASKS), _template("addon", {"dev": "x"})],
+            VARIABLES,
+        )
+        self.assertEqual(list(found), ["build", "dev", "bench"])
+        self.assertEqual(found["build"].run[1], "go build -o shop .")
+        self.assertEqual(found["dev"], tasks.Task("", ["x"]))
+
+    def test_bad_tables_exit(self):
+        for table in ({"a": 1}, {"a": []}, {"a": {"about": "x"}}, ["a"]):
+            with self.assertRaises(SystemExit, msg=table):
+                tasks.declared([_template("t", table)], VARIABLES)
+
+    def test_unknown_runner_exits(self):
+        with self.assertRaises(SystemExit):
+            _apply([], "rake")
+
+
+class TestApply(unittest.TestCase):
+    def test_makefile_replaces_the_justfile(self):
+        out = _apply([("justfile", "run:\n    go run .\n")], "make")
+        self.assertNotIn("justfile", out)
+        self.assertEqual(
+            out["Makefile"],
+            "SHELL := bash\n"
+            ".SHELLFLAGS := -eo pipefail -c\n\n"
+            ".PHONY: build dev bench\n\n"
+            "build:\n\ttempl generate\n\tgo build -o shop .\n\n"
+            "# Run with auto-reload\ndev:\n\tair\n\n"
+            "bench:\n\tgo test -bench .\n",
+        )
+
+    def test_make_escapes_dollars(self):
+        out = _apply([], "make", {"dev": "PORT=$PORT air"})
+        self.assertIn("\tPORT=$$PORT air\n", out["Makefile"])
+
+    def test_taskfile(self):
+        out = _apply([], "task", {"dev": TASKS["dev"]})
+        self.assertEqual(
+            out["Taskfile.yml"],
+            'version: "3"\n\nset: [pipefail]\n\ntasks:\n'
+            '  dev:\n    desc: "Run with auto-reload"\n'
+            '    cmds:\n      - "air"\n',
+        )
+
+    def test_justfile_gains_only_missing_tasks(self):
+        justfile = "# Run it\ndev:\n    air 2>&1 | tee dev.log\n"
+        out = _apply([("justfile", justfile)], "just")
+        self.assertTrue(out["justfile"].startswith(justfile + "\n"))
+        self.assertIn("bench:\n    go test -bench .\n", out["justfile"])
+        self.assertEqual(tasks.names("just", out["justfile"]).count("dev"), 1)
+
+    def test_left_alone_without_a_runner(self):
+        files = [("justfile", "dev:\n    air\n")]
+        self.assertIs(
+            tasks.apply(files, [_template("t", TASKS)], VARIABLES), files
+        )
+
+    def test_extra_tasks_go_into_the_justfile_without_a_runner(self):
+        files = [("justfile", "dev:\n    air\n")]
+        extra = {"run-api": tasks.Task("Run cmd/api", ["go run ./cmd/api"])}
+        out = dict(
+            tasks.apply(files, [_template("t", TASKS)], VARIABLES, extra)
+        )
+        self.assertEqual(
+            out["justfile"],
+            "dev:\n    air\n\n# Run cmd/api\nrun-api:\n    go run ./cmd/api\n",
+        )
+
+
+class TestTemplates(unittest.TestCase):
+    def _render(self, name, runner):
+        variables = {
+            **scaffold.project_variables("demo"),
+            "TASK_RUNNER": runner,
+        }
+        base = scaffold.find_template(name)
+        return dict(scaffold.render_project(base, [], variables))
+
+    def test_builtin_templates_have_the_canonical_tasks(self):
+        for template in scaffold.list_templates():
+            found = tasks.declared([template], VARIABLES)
+            for task in ("build", "test", "dev", "lint"):
+                self.assertIn(task, found, template.name)
+
+    def test_quickstart_and_layout_follow_the_runner(self):
+        files = self._render("go/web", "task")
+        self.assertNotIn("justfile", files)
+        self.assertIn("trap", self._render("rust/web", "make")["Makefile"])
+        self.assertIn("task dev   # Run with", files["README.md"])
+        self.assertIn(
+            "`Taskfile.yml`: tasks: `task --list`", files["README.md"]
+        )
+
+    def test_registration_runs_the_runner(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            root = Path(tmp)
+            (root / "Makefile").write_text("all:\n")
+            _, text = monorepo.registration(root, root / "api", "task")
+        self.assertIn("\tcd api && task $*\n", text)
+        self.assertIn("# tasks of api: make api/RECIPE", text)
+
+    def test_readme_quickstart_from_a_makefile(self):
+        files = {"Makefile": "# Run tests\ntest:\n\tgo test ./...\n"}
+        self.assertEqual(
+            readme.quickstart([_template("t")], files),
+            [("make test", "Run tests")],
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
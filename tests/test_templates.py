@@ -209,7 +209,7 @@ class TestGetProjectInitCommands(unittest.TestCase):
         """Go bare scaffold must include go.mod so the project is a valid module."""
         files = dict(jolo.get_scaffold_files("go"))
         self.assertIn("go.mod", files)
-        self.assertIn("module {{PROJECT_NAME}}", files["go.mod"])
+        self.assertIn("module {{MODULE_PATH}}", files["go.mod"])
 
     def test_go_web_returns_templ_commands(self):
         """Go web should return templ generate (go.mod ships in scaffold)."""
@@ -222,7 +222,7 @@ class TestGetProjectInitCommands(unittest.TestCase):
         """Go web scaffold must include go.mod alongside templ files."""
         files = dict(jolo.get_scaffold_files("go-web"))
         self.assertIn("go.mod", files)
-        self.assertIn("module {{PROJECT_NAME}}", files["go.mod"])
+        self.assertIn("module {{MODULE_PATH}}", files["go.mod"])
 
     def test_python_web_returns_fastapi_deps(self):
         """Python web should install FastAPI deps."""
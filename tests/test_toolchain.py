@@ -0,0 +1,125 @@
+#!/usr/bin/env python3
+"""Tests for template tool requirements (jolo doctor, jolo new --check)."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import scaffold, toolchain
+
+
+def _template(requires):
+    return scaffold.Template(
+        "t", Path(tempfile.gettempdir()), {"requires": requires}
+    )
+
+
+def _versions(**found):
+    return mock.patch.object(
+        toolchain, "installed_version", side_effect=lambda t: found.get(t)
+    )
+
+
+class TestRequirements(unittest.TestCase):
+    def test_highest_minimum_wins(self):
+        templates = [
+            _template({"go": "1.21", "templ": ""}),
+            _template({"go": "1.22"}),
+        ]
+        self.assertEqual(
+            toolchain.requirements(templates), {"go": "1.22", "templ": ""}
+        )
+
+    def test_version_compared_numerically(self):
+        self.assertTrue(toolchain.Check("go", "1.9", "1.21.3", "").ok)
+        self.assertFalse(toolchain.Check("go", "1.22", "1.21.3", "").ok)
+        self.assertTrue(toolchain.Check("x", "2", "", "").ok)
+        self.assertFalse(toolchain.Check("x", "", None, "").ok)
+
+    def test_version_read_from_the_tool(self):
+        result = mock.Mock(stdout="go version go1.22.3 linux/amd64\n")
+        result.stderr = ""
+        with (
+            mock.patch("shutil.which", return_value="/usr/bin/go"),
+            mock.patch("subprocess.run", return_value=result),
+        ):
+            self.assertEqual(toolchain.installed_version("go"), "1.22.3")
+
+    def test_unknown_tool_only_looked_up(self):
+        with mock.patch("shutil.which", return_value=None):
+            self.assertIsNone(toolchain.installed_version("protoc"))
+        with mock.patch("shutil.which", return_value="/usr/bin/protoc"):
+            self.assertEqual(toolchain.installed_version("protoc"), "")
+
+    def test_failures_get_install_hints(self):
+        with (
+            _versions(go="1.20.1"),
+            mock.patch("sys.stdout", io.StringIO()) as out,
+        ):
+            checks = toolchain.check({"go": "1.21", "templ": ""})
+            toolchain.print_checks(checks)
+        self.assertEqual(
+            out.getvalue().splitlines(),
+            [
+                "  [FAIL] go  (1.20.1, needs 1.21)",
+                "         install: https://go.dev/dl/",
+                "  [FAIL] templ  (not installed)",
+                "         install: "
+                "go install github.com/a-h/templ/cmd/templ@latest",
+            ],
+        )
+
+    def test_builtin_requirements_are_known_tools(self):
+        for template in scaffold.list_templates():
+            for tool in template.manifest.get("requires", {}):
+                self.assertIn(tool, toolchain.TOOLS, template.name)
+
+
+class TestDoctorTemplates(unittest.TestCase):
+    def _doctor(self, *argv):
+        args = jolo.parse_args(["doctor", *argv])
+        with mock.patch("sys.stdout", io.StringIO()) as out:
+            jolo.run_doctor_mode(args)
+        return out.getvalue()
+
+    def test_all_present(self):
+        with _versions(go="1.22.0", just="1.36.0", air="1.61.0"):
+            out = self._doctor("go")
+        self.assertIn("  [  ok] go  (1.22.0, needs 1.21)", out)
+        self.assertTrue(out.endswith("3 passed, 0 failed\n"))
+
+    def test_addon_requirements_and_exit(self):
+        with _versions(go="1.22.0"), self.assertRaises(SystemExit) as cm:
+            self._doctor("go/web", "--with", "docker")
+        self.assertEqual(cm.exception.code, 1)
+
+
+class TestNewCheck(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def test_stops_before_writing(self):
+        args = jolo.parse_args(["new", "go", "demo", "--check", "--no-input"])
+        with (
+            _versions(go="1.22.0"),
+            mock.patch("sys.stdout", io.StringIO()),
+            self.assertRaises(SystemExit) as cm,
+        ):
+            scaffold.run_new_mode(args)
+        self.assertIn("missing or too old: just, air", cm.exception.code)
+        self.assertFalse(Path("demo").exists())
+
+
+if __name__ == "__main__":
+    unittest.main()
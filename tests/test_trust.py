@@ -0,0 +1,153 @@
+#!/usr/bin/env python3
+"""Tests for template checksums and signature verification."""
+
+import io
+import os
+import shutil
+import subprocess
+import tempfile
+import tomllib
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import remote, scaffold, trust, upgrade
+
+_GIT = ["git", "-c", "user.name=t", "-c", "user.email=t@t"]
+
+
+class TestContentHash(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+        (self.root / "sub").mkdir()
+        (self.root / "a.txt").write_text("a\n")
+        (self.root / "sub" / "run.sh").write_text("echo\n")
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_stable_and_ignores_git(self):
+        before = trust.content_hash(self.root)
+        self.assertTrue(before.startswith("sha256:"))
+        (self.root / ".git").mkdir()
+        (self.root / ".git" / "HEAD").write_text("ref: x\n")
+        self.assertEqual(trust.content_hash(self.root), before)
+
+    def test_contents_names_and_modes_count(self):
+        before = trust.content_hash(self.root)
+        (self.root / "sub" / "run.sh").chmod(0o755)
+        moded = trust.content_hash(self.root)
+        self.assertNotEqual(moded, before)
+        (self.root / "a.txt").rename(self.root / "b.txt")
+        self.assertNotEqual(trust.content_hash(self.root), moded)
+
+    def test_check_checksum(self):
+        good = trust.content_hash(self.root)
+        trust.check_checksum(self.root, good, "tpl")
+        (self.root / "a.txt").write_text("changed\n")
+        with self.assertRaises(SystemExit) as cm:
+            trust.check_checksum(self.root, good, "tpl")
+        self.assertIn("tpl does not match", cm.exception.code)
+
+
+class TestRemoteTrust(unittest.TestCase):
+    """Checksums and signatures of a repository reached over file://."""
+
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        self.repo = Path(self.tmpdir) / "repo"
+        self.repo.mkdir()
+        (self.repo / "README.md").write_text("# {{PROJECT_NAME}}\n")
+        self._git("init", "-q")
+        self._git("add", "-A")
+        self._git("commit", "-q", "-m", "v1")
+        self.url = self.repo.as_uri()
+        work = Path(self.tmpdir) / "work"
+        work.mkdir()
+        os.chdir(work)
+        env = mock.patch.dict(
+            os.environ, {"XDG_CACHE_HOME": str(Path(self.tmpdir) / "cache")}
+        )
+        env.start()
+        self.addCleanup(env.stop)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _git(self, *args):
+        subprocess.run(
+            [*_GIT, *args], cwd=self.repo, check=True, capture_output=True
+        )
+
+    def _new(self, spec, name, *flags):
+        with mock.patch("sys.stdout", io.StringIO()):
+            scaffold.run_new_mode(jolo.parse_args(["new", spec, name, *flags]))
+        return scaffold.read_lock(Path(name))
+
+    def test_lock_records_checksum_and_diff_checks_it(self):
+        lock = self._new(self.url, "demo")
+        self.assertTrue(lock["checksum"].startswith("sha256:"))
+        os.chdir("demo")
+        with mock.patch("sys.stdout", io.StringIO()):
+            upgrade.run_diff_mode(jolo.parse_args(["diff"]))
+        locked = remote._entry_path(self.url, lock["commit"])
+        (locked / "repo" / "README.md").write_text("# {{PROJECT_NAME}}!\n")
+        with self.assertRaises(SystemExit) as cm:
+            upgrade.run_diff_mode(jolo.parse_args(["diff"]))
+        self.assertIn("does not match the checksum", cm.exception.code)
+
+    def test_builtin_lock_has_no_checksum(self):
+        with mock.patch("sys.stdout", io.StringIO()):
+            args = jolo.parse_args(["new", "go", "demo", "--no-verify"])
+            scaffold.run_new_mode(args)
+        lock = tomllib.loads(Path("demo/jolo.lock").read_text())
+        self.assertNotIn("checksum", lock)
+
+    def test_unsigned_tag_is_refused(self):
+        self._git("tag", "-a", "v1.0.0", "-m", "release")
+        spec = f"{self.url}@v1.0.0"
+        with self.assertRaises(SystemExit) as cm:
+            self._new(spec, "demo", "--verify-signature")
+        self.assertIn("not signed by a trusted key", cm.exception.code)
+        self.assertFalse(Path("demo").exists())
+
+    def test_config_turns_verification_on(self):
+        config = {"new": {"verify_signatures": True}}
+        with (
+            mock.patch.object(scaffold, "load_config", return_value=config),
+            self.assertRaises(SystemExit) as cm,
+        ):
+            self._new(self.url, "demo")
+        self.assertIn("not signed", cm.exception.code)
+
+    @unittest.skipUnless(shutil.which("ssh-keygen"), "needs ssh-keygen")
+    def test_ssh_signed_tag_is_accepted(self):
+        key = Path(self.tmpdir) / "key"
+        subprocess.run(
+            ["ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", str(key)],
+            check=True,
+        )
+        signers = Path(self.tmpdir) / "allowed_signers"
+        signers.write_text(f"t@t {Path(f'{key}.pub').read_text()}")
+        config = {
+            "gpg.format": "ssh",
+            "user.signingkey": str(key),
+            "gpg.ssh.allowedSignersFile": str(signers),
+        }
+        env = {"GIT_CONFIG_COUNT": str(len(config))}
+        for n, (k, v) in enumerate(config.items()):
+            env[f"GIT_CONFIG_KEY_{n}"] = k
+            env[f"GIT_CONFIG_VALUE_{n}"] = v
+        with mock.patch.dict(os.environ, env):
+            self._git("tag", "-s", "v1.0.0", "-m", "release")
+            spec = f"{self.url}@v1.0.0"
+            lock = self._new(spec, "demo", "--verify-signature")
+        self.assertEqual(lock["template"], spec)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,452 @@
+#!/usr/bin/env python3
+"""Tests for jolo.lock and jolo upgrade."""
+
+import os
+import shutil
+import subprocess
+import tempfile
+import tomllib
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import scaffold, upgrade
+
+
+def _git(cwd, *args):
+    subprocess.run(
+        ["git", "-c", "user.name=t", "-c", "user.email=t@t", *args],
+        cwd=cwd,
+        check=True,
+        capture_output=True,
+    )
+
+
+class TestMergeInto(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.project = Path(self.tmpdir.name)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _write(self, files):
+        for rel, content in files.items():
+            (self.project / rel).write_text(content)
+
+    def test_untouched_file_takes_new_version(self):
+        self._write({"a": "1\n"})
+        report = upgrade.merge_into(self.project, {"a": "1\n"}, {"a": "2\n"})
+        self.assertEqual((self.project / "a").read_text(), "2\n")
+        self.assertEqual(report["updated"], ["a"])
+
+    def test_new_template_file_is_added(self):
+        report = upgrade.merge_into(self.project, {}, {"d/b": "x"})
+        self.assertEqual((self.project / "d/b").read_text(), "x")
+        self.assertEqual(report["added"], ["d/b"])
+
+    def test_removed_template_file(self):
+        self._write({"same": "x", "edited": "mine"})
+        report = upgrade.merge_into(
+            self.project, {"same": "x", "edited": "x"}, {}
+        )
+        self.assertFalse((self.project / "same").exists())
+        self.assertEqual((self.project / "edited").read_text(), "mine")
+        self.assertEqual(report["removed"], ["same"])
+        self.assertEqual(report["kept"], ["edited"])
+
+    def test_locally_deleted_file_stays_deleted(self):
+        report = upgrade.merge_into(self.project, {"a": "1"}, {"a": "2"})
+        self.assertFalse((self.project / "a").exists())
+        self.assertFalse(any(report.values()))
+
+    def test_binary_files(self):
+        (self.project / "logo.png").write_bytes(b"\x89PNG 1")
+        (self.project / "mine.png").write_bytes(b"\x89PNG edited")
+        report = upgrade.merge_into(
+            self.project,
+            {"logo.png": b"\x89PNG 1", "mine.png": b"\x89PNG 1"},
+            {"logo.png": b"\x89PNG 2", "mine.png": b"\x89PNG 2"},
+        )
+        self.assertEqual(
+            (self.project / "logo.png").read_bytes(), b"\x89PNG 2"
+        )
+        self.assertEqual(
+            (self.project / "mine.png").read_bytes(), b"\x89PNG edited"
+        )
+        self.assertEqual(report["updated"], ["logo.png"])
+        self.assertEqual(report["kept"], ["mine.png"])
+
+    def test_clean_merge(self):
+        base = "one\ntwo\nthree\nfour\nfive\n"
+        self._write({"a": "ONE\ntwo\nthree\nfour\nfive\n"})
+        report = upgrade.merge_into(
+            self.project,
+            {"a": base},
+            {"a": "one\ntwo\nthree\nfour\nFIVE\n"},
+        )
+        self.assertEqual(
+            (self.project / "a").read_text(),
+            "ONE\ntwo\nthree\nfour\nFIVE\n",
+        )
+        self.assertEqual(report["merged"], ["a"])
+
+    def test_conflict_gets_markers(self):
+        self._write({"a": "mine\n"})
+        report = upgrade.merge_into(
+            self.project, {"a": "base\n"}, {"a": "theirs\n"}
+        )
+        text = (self.project / "a").read_text()
+        self.assertIn("<<<<<<< project\nmine\n=======\n", text)
+        self.assertIn("theirs\n>>>>>>> template (new)\n", text)
+        self.assertEqual(report["conflicted"], ["a"])
+
+    def test_keep_regions_survive_template_changes(self):
+        base = "a\n# jolo:keep-start\ndefault\n# jolo:keep-end\nz\n"
+        theirs = "A\n# jolo:keep-start\nnew default\n# jolo:keep-end\nz\n"
+        self._write({"f": base.replace("default", "mine\nmore")})
+        report = upgrade.merge_into(self.project, {"f": base}, {"f": theirs})
+        self.assertEqual(
+            (self.project / "f").read_text(),
+            "A\n# jolo:keep-start\nmine\nmore\n# jolo:keep-end\nz\n",
+        )
+        self.assertEqual(report["updated"], ["f"])
+
+
+class TestLock(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def test_new_writes_lock(self):
+        args = jolo.parse_args(["new", "go/web", "demo", "--with", "sqlite"])
+        with mock.patch("builtins.print"):
+            scaffold.run_new_mode(args)
+        lock = scaffold.read_lock(Path("demo"))
+        self.assertEqual(lock["template"], "go/web")
+        self.assertEqual(lock["addons"], ["sqlite"])
+        self.assertEqual(lock["name"], "demo")
+        self.assertEqual(lock["commit"], scaffold.builtin_commit())
+
+    def test_lock_round_trips_odd_values(self):
+        lock = {
+            "template": "t",
+            "addons": [],
+            "variables": {
+                "a b": 'quote " and \\ and ø',
+                "n": "1",
+                "DESCRIPTION": "Rocket 🚀",
+                "del": "a\x7fb",
+            },
+        }
+        scaffold.write_lock(Path(self.tmpdir), lock)
+        with open(scaffold.LOCK_FILE, "rb") as f:
+            self.assertEqual(tomllib.load(f), lock)
+
+    def test_missing_lock_exits(self):
+        with self.assertRaises(SystemExit):
+            scaffold.read_lock(Path(self.tmpdir))
+
+
+class TestBuiltinAt(unittest.TestCase):
+    def test_extracts_templates_at_commit(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            repo = Path(tmp)
+            lang = repo / "templates" / "lang" / "t"
+            lang.mkdir(parents=True)
+            (lang / "template.toml").write_text("")
+            (lang / "f").write_text("v1")
+            _git(repo, "init", "-q")
+            _git(repo, "add", "-A")
+            _git(repo, "commit", "-q", "-m", "v1")
+            commit = subprocess.run(
+                ["git", "rev-parse", "HEAD"],
+                cwd=repo,
+                capture_output=True,
+                text=True,
+            ).stdout.strip()
+            (lang / "f").write_text("v2")
+
+            with mock.patch.object(
+                upgrade, "_TEMPLATES_DIR", repo / "templates"
+            ):
+                with upgrade._builtin_at(commit) as (lang_root, addons):
+                    self.assertEqual((lang_root / "t" / "f").read_text(), "v1")
+                    self.assertEqual(addons.name, "addons")
+
+
+    def test_skips_links_out_of_the_tree(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            repo = Path(tmp)
+            lang = repo / "templates" / "lang" / "t"
+            lang.mkdir(parents=True)
+            (lang / "template.toml").write_text("")
+            (lang / "elsewhere").symlink_to("/etc/hosts")
+            _git(repo, "init", "-q")
+            _git(repo, "add", "-A")
+            _git(repo, "commit", "-q", "-m", "v1")
+
+            with upgrade._archive_at(repo / "templates", "HEAD") as root:
+                self.assertTrue((root / "lang/t/template.toml").is_file())
+                self.assertFalse((root / "lang/t/elsewhere").exists())
+
+    def test_builtin_templates_at_this_checkout(self):
+        commit = scaffold.builtin_commit()
+        if not commit:
+            self.skipTest("jolo is not a git checkout")
+        with upgrade._builtin_at(commit) as (lang_root, addons):
+            self.assertTrue((lang_root / "go" / "template.toml").is_file())
+            self.assertTrue((addons / "direnv" / ".envrc").is_file())
+
+
+class TestUpgradeRemote(unittest.TestCase):
+    """jolo new from a file:// repo, then upgrade after it moves on."""
+
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        cache = mock.patch.dict(
+            os.environ, {"XDG_CACHE_HOME": str(Path(self.tmpdir) / "cache")}
+        )
+        cache.start()
+        self.addCleanup(cache.stop)
+        self.repo = Path(self.tmpdir) / "repo"
+        self.repo.mkdir()
+        self._commit(
+            {
+                "template.toml": '[variables.greeting]\ndefault = "hi"\n',
+                "README": "# {{PROJECT_NAME}}\n\nintro\n\nusage\n",
+                "main.txt": "{{greeting}}\n",
+                "old.txt": "drop me\n",
+            }
+        )
+        work = Path(self.tmpdir) / "work"
+        work.mkdir()
+        os.chdir(work)
+        args = jolo.parse_args(
+            ["new", self.repo.as_uri(), "demo", "--var", "greeting=hey"]
+        )
+        with mock.patch("builtins.print"):
+            scaffold.run_new_mode(args)
+        os.chdir("demo")
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _commit(self, files, remove=()):
+        for rel, content in files.items():
+            (self.repo / rel).write_text(content)
+        for rel in remove:
+            (self.repo / rel).unlink()
+        if not (self.repo / ".git").exists():
+            _git(self.repo, "init", "-q")
+        _git(self.repo, "add", "-A")
+        _git(self.repo, "commit", "-q", "-m", "change")
+
+    def _upgrade(self, *argv):
+        args = jolo.parse_args(["upgrade", *argv])
+        with mock.patch("builtins.print") as fake_print:
+            upgrade.run_upgrade_mode(args)
+        return [c.args[0] for c in fake_print.call_args_list if c.args]
+
+    def test_up_to_date(self):
+        out = self._upgrade()
+        self.assertEqual(
+            out, [f"demo is up to date with {self.repo.as_uri()}"]
+        )
+
+    def test_merges_template_changes(self):
+        Path("README").write_text("# demo\n\nintro\n\nusage\n\nmy notes\n")
+        self._commit(
+            {
+                "README": "# {{PROJECT_NAME}}\n\nbetter intro\n\nusage\n",
+                "main.txt": "{{greeting}} there\n",
+                "new.txt": "{{PROJECT_NAME}}\n",
+            },
+            remove=["old.txt"],
+        )
+        out = self._upgrade()
+        self.assertEqual(
+            Path("README").read_text(),
+            "# demo\n\nbetter intro\n\nusage\n\nmy notes\n",
+        )
+        self.assertEqual(Path("main.txt").read_text(), "hey there\n")
+        self.assertEqual(Path("new.txt").read_text(), "demo\n")
+        self.assertFalse(Path("old.txt").exists())
+        self.assertIn("Merged: README", out)
+
+        lock = scaffold.read_lock(Path.cwd())
+        head = subprocess.run(
+            ["git", "rev-parse", "HEAD"],
+            cwd=self.repo,
+            capture_output=True,
+            text=True,
+        ).stdout.strip()
+        self.assertEqual(lock["commit"], head)
+        self.assertEqual(lock["variables"]["greeting"], "hey")
+
+    def test_conflict_exits_with_markers(self):
+        Path("main.txt").write_text("yo\n")
+        self._commit({"main.txt": "{{greeting}} there\n"})
+        with self.assertRaises(SystemExit):
+            self._upgrade()
+        self.assertIn("<<<<<<< project", Path("main.txt").read_text())
+
+    def test_new_variable_takes_default_with_no_input(self):
+        self._commit(
+            {
+                "template.toml": '[variables.greeting]\ndefault = "hi"\n'
+                '[variables.who]\ndefault = "world"\n',
+                "main.txt": "{{greeting}} {{who}}\n",
+            }
+        )
+        self._upgrade("--no-input")
+        self.assertEqual(Path("main.txt").read_text(), "hey world\n")
+        variables = scaffold.read_lock(Path.cwd())["variables"]
+        self.assertEqual(variables["greeting"], "hey")
+        self.assertEqual(variables["who"], "world")
+
+    def test_diff_clean(self):
+        args = jolo.parse_args(["diff"])
+        with mock.patch("builtins.print") as fake_print:
+            upgrade.run_diff_mode(args)
+        fake_print.assert_called_once_with(
+            f"demo matches {self.repo.as_uri()}"
+        )
+
+    def test_diff_shows_drift_from_locked_version(self):
+        Path("main.txt").write_text("yo\n")
+        Path("old.txt").unlink()
+        # Template moving on must not show up: diff is against the lock.
+        self._commit({"README": "changed\n"})
+        args = jolo.parse_args(["diff"])
+        with mock.patch("sys.stdout") as out:
+            upgrade.run_diff_mode(args)
+        text = "".join(out.writelines.call_args.args[0])
+        self.assertIn("--- a/main.txt\n+++ b/main.txt\n", text)
+        self.assertIn("-hey\n+yo\n", text)
+        self.assertIn("--- a/old.txt\n+++ /dev/null\n", text)
+        self.assertNotIn("README", text)
+
+
+class TestProjectDiff(unittest.TestCase):
+    def test_missing_trailing_newline_is_marked(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            (Path(tmp) / "a").write_text("x")
+            lines = upgrade.project_diff(Path(tmp), {"a": "x\n"})
+        self.assertEqual(
+            "".join(lines),
+            "--- a/a\n+++ b/a\n@@ -1 +1 @@\n-x\n+x\n"
+            "\\ No newline at end of file\n",
+        )
+
+    def test_binary_files_are_not_diffed(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            (Path(tmp) / "a.png").write_bytes(b"\x89PNG new")
+            lines = upgrade.project_diff(Path(tmp), {"a.png": b"\x89PNG"})
+        self.assertEqual(lines, ["Binary files a/a.png and b/a.png differ\n"])
+
+
+class TestUpgradeTemplatesDir(unittest.TestCase):
+    def test_upgrades_from_the_locked_templates_dir(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            team = Path(tmp) / "team"
+            (team / "svc").mkdir(parents=True)
+            (team / "svc" / "template.toml").write_text("")
+            (team / "svc" / "a.txt").write_text("v1 {{PROJECT_NAME}}\n")
+            _git(team, "init", "-q")
+            _git(team, "add", "-A")
+            _git(team, "commit", "-q", "-m", "v1")
+
+            cwd = os.getcwd()
+            os.chdir(tmp)
+            try:
+                args = jolo.parse_args(
+                    ["new", "svc", "demo", "--templates-dir", str(team)]
+                )
+                with mock.patch("builtins.print"):
+                    scaffold.run_new_mode(args)
+                (team / "svc" / "a.txt").write_text("v2 {{PROJECT_NAME}}\n")
+                _git(team, "commit", "-q", "-am", "v2")
+                os.chdir("demo")
+                with mock.patch("builtins.print"):
+                    upgrade.run_upgrade_mode(jolo.parse_args(["upgrade"]))
+                self.assertEqual(Path("a.txt").read_text(), "v2 demo\n")
+            finally:
+                os.chdir(cwd)
+
+
+class TestUpgradeVersions(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        tmp = Path(self.tmpdir.name)
+        self.team = tmp / "team"
+        (self.team / "svc").mkdir(parents=True)
+        self._release("1.0.0", "v1")
+        cwd = os.getcwd()
+        os.chdir(tmp)
+        self.addCleanup(os.chdir, cwd)
+        args = jolo.parse_args(
+            ["new", "svc@1", "demo", "--templates-dir", str(self.team)]
+        )
+        with mock.patch("builtins.print"):
+            scaffold.run_new_mode(args)
+        os.chdir("demo")
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _release(self, version, text):
+        svc = self.team / "svc"
+        (svc / "template.toml").write_text(f'version = "{version}"\n')
+        (svc / "a.txt").write_text(f"{text}\n")
+        if not (self.team / ".git").exists():
+            _git(self.team, "init", "-q")
+        _git(self.team, "add", "-A")
+        _git(self.team, "commit", "-q", "-m", version)
+
+    def _upgrade(self, *argv):
+        args = jolo.parse_args(["upgrade", *argv])
+        with mock.patch("builtins.print") as fake_print:
+            upgrade.run_upgrade_mode(args)
+        return [c.args[0] for c in fake_print.call_args_list if c.args]
+
+    def test_new_records_version(self):
+        self.assertEqual(scaffold.read_lock(Path.cwd())["version"], "1.0.0")
+
+    def test_minor_upgrade_reports_version(self):
+        self._release("1.1.0", "v1.1")
+        out = self._upgrade()
+        self.assertIn("svc: 1.0.0 -> 1.1.0", out)
+        self.assertEqual(scaffold.read_lock(Path.cwd())["version"], "1.1.0")
+
+    def test_major_upgrade_needs_ref(self):
+        self._release("2.0.0", "v2")
+        with self.assertRaises(SystemExit) as cm:
+            self._upgrade()
+        self.assertIn("--ref 2", str(cm.exception))
+        self.assertEqual(Path("a.txt").read_text(), "v1\n")
+        with self.assertRaises(SystemExit):
+            self._upgrade("--ref", "^1")
+        self._upgrade("--ref", "2")
+        self.assertEqual(Path("a.txt").read_text(), "v2\n")
+        self.assertEqual(scaffold.read_lock(Path.cwd())["version"], "2.0.0")
+
+
+class TestUpgradeArgParsing(unittest.TestCase):
+    def test_upgrade_defaults(self):
+        args = jolo.parse_args(["upgrade"])
+        self.assertEqual(args.command, "upgrade")
+        self.assertIsNone(args.ref)
+        self.assertFalse(args.no_input)
+
+
+if __name__ == "__main__":
+    unittest.main()
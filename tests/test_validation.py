@@ -0,0 +1,189 @@
+#!/usr/bin/env python3
+"""Tests for answer validation rules (_jolo.validation)."""
+
+import io
+import os
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import api, scaffold, validation
+
+MANIFEST = """\
+description = "t"
+
+[variables.service]
+pattern = "[a-z][a-z0-9-]*"
+min = 3
+max = 12
+
+[variables.replicas]
+type = "int"
+default = 2
+min = 1
+max = 5
+
+[variables.region]
+choices = ["eu", "us"]
+default = "eu"
+
+[variables.owner]
+required = true
+message = "every service needs an owner"
+
+[validate.PROJECT_NAME]
+pattern = "[a-z]+"
+message = "lowercase letters only"
+"""
+
+
+class TestRules(unittest.TestCase):
+    def _fails(self, spec, value):
+        with self.assertRaises(ValueError) as cm:
+            validation.check(spec, value)
+        return str(cm.exception)
+
+    def test_pattern_is_anchored(self):
+        spec = {"pattern": "[a-z]+"}
+        validation.check(spec, "abc")
+        self.assertEqual(
+            self._fails(spec, "abc1"), "must match [a-z]+, got 'abc1'"
+        )
+
+    def test_bounds(self):
+        self.assertEqual(
+            self._fails({"type": "int", "min": 1}, "0"),
+            "must be at least 1, got '0'",
+        )
+        self.assertEqual(
+            self._fails({"max": 2}, "abc"),
+            "must be at most 2 characters long, got 'abc'",
+        )
+        validation.check({"type": "int", "max": 2}, "2")
+
+    def test_required_choices_and_message(self):
+        self.assertEqual(
+            self._fails({"required": True}, ""), "a value is required"
+        )
+        self.assertIn(
+            "expected one of a, b", self._fails({"choices": ["a", "b"]}, "c")
+        )
+        self.assertEqual(
+            self._fails({"pattern": "x", "message": "say x"}, "y"), "say x"
+        )
+
+    def test_spec_problems(self):
+        self.assertIn("bad pattern", validation.spec_problem({"pattern": "("}))
+        self.assertEqual(
+            validation.spec_problem({"type": "bool", "min": 1}),
+            "min needs a string or int, not bool",
+        )
+        self.assertEqual(
+            validation.spec_problem({"max": "9"}), "max must be an integer"
+        )
+        self.assertIsNone(validation.spec_problem({"min": 0, "pattern": "a"}))
+
+
+class TestNew(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.mkdtemp()
+        self.original_cwd = os.getcwd()
+        os.chdir(self.tmpdir)
+        tpl = Path(self.tmpdir, "templates", "t")
+        tpl.mkdir(parents=True)
+        (tpl / "template.toml").write_text(MANIFEST)
+        (tpl / "out.txt").write_text("{{service}} {{replicas}} {{owner}}\n")
+        self.template = scaffold.find_template(
+            "t", Path(self.tmpdir, "templates")
+        )
+
+    def tearDown(self):
+        os.chdir(self.original_cwd)
+        shutil.rmtree(self.tmpdir)
+
+    def _new(self, *argv, name="demo"):
+        args = jolo.parse_args(
+            [
+                "new",
+                "t",
+                *([name] if name else []),
+                "--templates-dir",
+                "templates",
+                "--git=false",
+                "--license=none",
+                "--var",
+                "DESCRIPTION=d",
+                *argv,
+            ]
+        )
+        with (
+            mock.patch.object(
+                scaffold, "load_config", return_value={"new": {}}
+            ),
+            mock.patch("sys.stdout", io.StringIO()),
+        ):
+            scaffold.run_new_mode(args)
+
+    def test_var_breaking_a_rule(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._new("--no-input", "--var", "service=Bad", "--var", "owner=a")
+        self.assertEqual(
+            cm.exception.code,
+            "Error: service: must match [a-z][a-z0-9-]*, got 'Bad'",
+        )
+        self.assertFalse(Path(self.tmpdir, "demo").exists())
+
+    def test_project_name(self):
+        with self.assertRaises(SystemExit) as cm:
+            self._new("--no-input", name="demo2")
+        self.assertEqual(
+            cm.exception.code, "Error: PROJECT_NAME: lowercase letters only"
+        )
+        with self.assertRaises(api.ScaffoldError):
+            api.render(self.template, "Demo", {"service": "abc", "owner": "a"})
+
+    def test_prompts_ask_again(self):
+        answers = iter(
+            ["Demo", "demo", "ab", "billing", "9", "3", "", "", "ops"]
+        )
+        with (
+            mock.patch("sys.stdin.isatty", return_value=False),
+            mock.patch("builtins.input", side_effect=lambda _: next(answers)),
+            mock.patch("sys.stderr", io.StringIO()) as err,
+        ):
+            self._new(name=None)
+        self.assertEqual(
+            Path(self.tmpdir, "demo", "out.txt").read_text(),
+            "billing 3 ops\n",
+        )
+        self.assertEqual(
+            [line for line in err.getvalue().splitlines() if "  " in line[:2]],
+            [
+                "  lowercase letters only",
+                "  must be at least 3 characters long, got 'ab'",
+                "  must be at most 5, got '9'",
+                "  every service needs an owner",
+            ],
+        )
+
+    def test_bad_rule_in_manifest(self):
+        self.template.manifest["variables"]["replicas"]["pattern"] = "("
+        with self.assertRaises(SystemExit) as cm:
+            self.template.variables
+        self.assertIn("t: replicas: bad pattern", cm.exception.code)
+
+
+class TestBuiltinTemplates(unittest.TestCase):
+    def test_names_that_break_the_build_are_refused(self):
+        for name, bad in (("go", "1app"), ("typescript", "MyApp")):
+            template = scaffold.find_template(name)
+            with self.assertRaises(api.ScaffoldError):
+                api.render(template, bad)
+            api.render(template, "my-app")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,144 @@
+#!/usr/bin/env python3
+"""Tests for jolo verify."""
+
+import io
+import tempfile
+import unittest
+from pathlib import Path
+from unittest import mock
+
+import jolo
+from _jolo import scaffold, verify
+
+
+def _template(root, name, files, manifest=""):
+    path = root / name
+    path.mkdir(parents=True)
+    (path / scaffold.MANIFEST_FILE).write_text(manifest)
+    for rel, content in files.items():
+        (path / rel).write_text(content)
+    return scaffold.load_template(path, name)
+
+
+class TestFindLeaks(unittest.TestCase):
+    def test_reports_jolo_placeholders_only(self):
+        files = [
+            ("go.mod", "module {{PROJECT_NAME}}\n"),
+            ("justfile", "run *args:\n    go run . {{args}}\n"),
+            ("a.html", "{{ title }} {{ NAME | pascal }}"),
+            ("cmd/{{X}}/main.go", ""),
+            ("logo.png", b"{{PROJECT_NAME}}"),
+        ]
+        self.assertEqual(
+            verify.find_leaks(files),
+            [
+                "go.mod:1: {{PROJECT_NAME}}",
+                "a.html:1: {{ NAME | pascal }}",
+                "cmd/{{X}}/main.go: path",
+            ],
+        )
+
+
+class TestVerifyTemplate(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.root = Path(self.tmpdir.name)
+        self.work = self.root / "work"
+        self.work.mkdir()
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def test_pass_runs_commands_in_rendered_project(self):
+        template = _template(
+            self.root,
+            "t",
+            {"name.txt": "{{PROJECT_NAME}}"},
+            '[verify]\nrun = ["grep -q verify-app name.txt"]\n',
+        )
+        result = verify.verify_template(template, self.work)
+        self.assertEqual(result.status, "pass", result.detail)
+
+    def test_failing_command_reports_output(self):
+        template = _template(
+            self.root,
+            "t",
+            {},
+            '[verify]\nrun = ["echo broken; exit 3"]\n',
+        )
+        result = verify.verify_template(template, self.work)
+        self.assertEqual(result.status, "fail")
+        self.assertIn("exit 3", result.detail)
+        self.assertIn("broken", result.detail)
+
+    def test_leak_fails_before_running(self):
+        template = _template(
+            self.root,
+            "t",
+            {"go.mod": "module {{MODULE}}"},
+            '[verify]\nrun = ["true"]\n',
+        )
+        result = verify.verify_template(template, self.work)
+        self.assertEqual(result.status, "fail")
+        self.assertIn("go.mod:2: {{MODULE}}", result.detail)
+
+    def test_missing_program_skips(self):
+        template = _template(
+            self.root, "t", {}, '[verify]\nrun = ["no-such-tool-xyz build"]\n'
+        )
+        result = verify.verify_template(template, self.work)
+        self.assertEqual(result.status, "skip")
+        self.assertIn("no-such-tool-xyz", result.detail)
+
+    def test_required_variable_fails(self):
+        template = _template(
+            self.root, "t", {}, "[variables.token]\ntype = 'string'\n"
+        )
+        result = verify.verify_template(template, self.work)
+        self.assertEqual(result.status, "fail")
+        self.assertIn("token required", result.detail)
+
+    def test_hooks_run_around_the_files(self):
+        template = _template(
+            self.root,
+            "t",
+            {"a": "x"},
+            '[hooks]\npre = ["test ! -e a"]\npost = ["test -e a"]\n',
+        )
+        result = verify.verify_template(template, self.work)
+        self.assertEqual(result.status, "pass", result.detail)
+
+
+class TestVerifyMode(unittest.TestCase):
+    def test_exits_nonzero_on_failure(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            _template(Path(tmp), "ok", {}, '[verify]\nrun = ["true"]\n')
+            _template(Path(tmp), "bad", {}, '[verify]\nrun = ["false"]\n')
+            args = jolo.parse_args(
+                ["verify", "ok", "bad", "--templates-dir", tmp]
+            )
+            out = io.StringIO()
+            with (
+                mock.patch.object(verify, "load_config", return_value={}),
+                mock.patch("sys.stdout", out),
+                self.assertRaises(SystemExit),
+            ):
+                verify.run_verify_mode(args)
+        lines = out.getvalue().splitlines()
+        self.assertTrue(lines[0].startswith("PASS  ok"))
+        self.assertTrue(lines[1].startswith("FAIL  bad"))
+        self.assertEqual(lines[-1], "1 pass, 1 fail, 0 skip")
+
+    def test_builtin_templates_render_without_leaks(self):
+        for template in scaffold.list_templates():
+            variables = scaffold.project_variables(verify.PROJECT_NAME)
+            variables.update(scaffold.config_variables({}, "x"))
+            variables.update(
+                scaffold.resolve_variables(template, {}, no_input=True)
+            )
+            files = scaffold.render_project(template, [], variables)
+            self.assertEqual(verify.find_leaks(files), [], template.name)
+
+
+if __name__ == "__main__":
+    unittest.main()
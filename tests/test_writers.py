@@ -0,0 +1,85 @@
+#!/usr/bin/env python3
+"""Tests for directory and archive writers."""
+
+import io
+import os
+import tarfile
+import tempfile
+import unittest
+import zipfile
+from pathlib import Path
+from unittest import mock
+
+from _jolo import writers
+
+
+class TestWriters(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.dir = Path(self.tmpdir.name)
+
+    def tearDown(self):
+        self.tmpdir.cleanup()
+
+    def _fill(self, writer):
+        with writer:
+            writer.write("README.md", "# demo\n")
+            writer.write("bin/run", "#!/bin/sh\n", 0o755)
+            writer.write("logo.png", b"\x89PNG\0")
+
+    def test_dir_writer(self):
+        self._fill(writers.DirWriter(self.dir / "demo"))
+        self.assertEqual((self.dir / "demo/README.md").read_text(), "# demo\n")
+        mode = (self.dir / "demo/bin/run").stat().st_mode & 0o777
+        self.assertEqual(mode, 0o755)
+        self.assertEqual(
+            (self.dir / "demo/logo.png").read_bytes(), b"\x89PNG\0"
+        )
+
+    def test_zip_keeps_prefix_and_modes(self):
+        buf = io.BytesIO()
+        self._fill(writers.archive_writer(buf, "demo", "zip"))
+        with zipfile.ZipFile(buf) as zf:
+            self.assertEqual(
+                zf.namelist(),
+                ["demo/README.md", "demo/bin/run", "demo/logo.png"],
+            )
+            mode = zf.getinfo("demo/bin/run").external_attr >> 16
+            self.assertEqual(mode & 0o777, 0o755)
+            self.assertEqual(zf.read("demo/logo.png"), b"\x89PNG\0")
+
+    def test_tar_stream(self):
+        buf = io.BytesIO()
+        self._fill(writers.archive_writer(buf, "demo", "tar.gz"))
+        buf.seek(0)
+        with tarfile.open(fileobj=buf, mode="r:gz") as tf:
+            self.assertEqual(tf.getmember("demo/bin/run").mode, 0o755)
+            self.assertEqual(tf.getmember("demo/README.md").mode, 0o644)
+            data = tf.extractfile("demo/README.md").read()
+        self.assertEqual(data, b"# demo\n")
+
+    def test_open_output_picks_format_by_suffix(self):
+        for name, check in (
+            ("a.zip", zipfile.is_zipfile),
+            ("a.tgz", tarfile.is_tarfile),
+            ("a.tar", tarfile.is_tarfile),
+        ):
+            path = self.dir / name
+            self._fill(writers.open_output(str(path), "demo"))
+            self.assertTrue(check(path), name)
+
+    def test_open_output_refuses(self):
+        (self.dir / "taken.zip").write_text("")
+        for output in ("a.rar", str(self.dir / "taken.zip")):
+            with self.assertRaises(SystemExit, msg=output):
+                writers.open_output(output, "demo")
+        with (
+            mock.patch("sys.stdout.isatty", return_value=True),
+            self.assertRaises(SystemExit),
+        ):
+            writers.open_output("-", "demo")
+        self.assertEqual(os.listdir(self.dir), ["taken.zip"])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,104 @@
+#!/usr/bin/env python3
+"""Tests for running jolo as a single-file zipapp with bundled templates."""
+
+import os
+import shutil
+import subprocess
+import sys
+import tempfile
+import unittest
+import zipapp
+import zipfile
+from pathlib import Path
+from unittest import mock
+
+from _jolo import templates
+
+REPO = Path(__file__).resolve().parent.parent
+
+
+class TestBundledTemplates(unittest.TestCase):
+    def setUp(self):
+        self.tmpdir = tempfile.TemporaryDirectory()
+        self.tmp = Path(self.tmpdir.name)
+        self.archive = self.tmp / "jolo.pyz"
+        with zipfile.ZipFile(self.archive, "w") as zf:
+            zf.writestr("__main__.py", "")
+            zf.writestr("templates/lang/go/go.mod", "module x")
+        env = {"XDG_CACHE_HOME": str(self.tmp / "cache")}
+        self.env = mock.patch.dict(os.environ, env)
+        self.env.start()
+
+    def tearDown(self):
+        self.env.stop()
+        self.tmpdir.cleanup()
+
+    def test_extracts_templates_into_cache(self):
+        path = templates._bundled_templates(self.archive)
+        self.assertTrue(path.is_relative_to(self.tmp / "cache" / "jolo"))
+        self.assertEqual((path / "lang/go/go.mod").read_text(), "module x")
+        self.assertFalse((path.parent / "__main__.py").exists())
+
+    def test_keeps_executable_bits(self):
+        info = zipfile.ZipInfo("templates/lang/go/dev.sh")
+        info.external_attr = 0o755 << 16
+        with zipfile.ZipFile(self.archive, "a") as zf:
+            zf.writestr(info, "#!/bin/sh\n")
+        path = templates._bundled_templates(self.archive)
+        mode = (path / "lang/go/dev.sh").stat().st_mode & 0o777
+        self.assertEqual(mode, 0o755)
+
+    def test_reuses_extraction(self):
+        first = templates._bundled_templates(self.archive)
+        (first / "marker").write_text("")
+        self.assertTrue(
+            (templates._bundled_templates(self.archive) / "marker").exists()
+        )
+
+    def test_checkout_uses_templates_next_to_code(self):
+        self.assertEqual(templates._TEMPLATES_DIR, REPO / "templates")
+
+
+class TestZipappNew(unittest.TestCase):
+    def test_new_runs_from_single_file(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            tmp = Path(tmp)
+            stage = tmp / "stage"
+            stage.mkdir()
+            shutil.copytree(
+                REPO / "_jolo",
+                stage / "_jolo",
+                ignore=shutil.ignore_patterns("__pycache__"),
+            )
+            shutil.copytree(
+                REPO / "templates",
+                stage / "templates",
+                ignore_dangling_symlinks=True,
+            )
+            shutil.copy(REPO / "jolo.py", stage / "__main__.py")
+            app = tmp / "jolo"
+            zipapp.create_archive(stage, app)
+            work = tmp / "work"
+            work.mkdir()
+
+            result = subprocess.run(
+                [
+                    sys.executable,
+                    str(app),
+                    "new",
+                    "go",
+                    "demo",
+                    "--no-input",
+                    "--no-verify",
+                ],
+                cwd=work,
+                env={**os.environ, "XDG_CACHE_HOME": str(tmp / "cache")},
+                capture_output=True,
+                text=True,
+            )
+            self.assertEqual(result.returncode, 0, result.stderr)
+            self.assertIn("module demo", (work / "demo/go.mod").read_text())
+
+
+if __name__ == "__main__":
+    unittest.main()